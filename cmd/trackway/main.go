@@ -2,10 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"reflect"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -15,12 +18,15 @@ import (
 	"trackway/internal/config"
 	"trackway/internal/dashboard"
 	"trackway/internal/logstore"
+	"trackway/internal/teams"
 	"trackway/internal/telegram"
+	"trackway/internal/telemetry"
 	"trackway/internal/tracker"
 )
 
 func main() {
-	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})))
+	logLevel := new(slog.LevelVar)
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel})))
 
 	cfgPath := envOrDefault("CONFIG_PATH", "config.json")
 
@@ -30,6 +36,21 @@ func main() {
 		os.Exit(1)
 	}
 
+	if cfg.Otel.Enabled {
+		shutdown, err := telemetry.Setup(context.Background(), cfg.Otel)
+		if err != nil {
+			fmt.Println("otel init error:", err)
+			os.Exit(1)
+		}
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdown(shutdownCtx); err != nil {
+				slog.Warn("otel shutdown failed", "error", err)
+			}
+		}()
+	}
+
 	store, err := initStore(cfg)
 	if err != nil {
 		fmt.Println("storage init error:", err)
@@ -39,21 +60,31 @@ func main() {
 		fmt.Println("targets init error:", err)
 		os.Exit(1)
 	}
+	if cfg.Monitoring.RequireTargets {
+		if err := requireTargets(store, cfg); err != nil {
+			fmt.Println("targets init error:", err)
+			os.Exit(1)
+		}
+	}
 
-	updates := make(chan *models.Update, 128)
+	var svc *tracker.Service
 	client, err := telegram.New(cfg.Bot.Token, cfg.Bot.ChatID, func(ctx context.Context, update *models.Update) {
-		select {
-		case updates <- update:
-		case <-ctx.Done():
-		default:
-			slog.Warn("dropping update due to full queue")
-		}
+		svc.EnqueueUpdate(ctx, update)
 	})
 	if err != nil {
 		fmt.Println("bot init error:", err)
 		os.Exit(1)
 	}
-	svc := tracker.New(cfg, store, client)
+	client.SetRestartBackoff(
+		time.Duration(cfg.Bot.RestartBackoffSeconds)*time.Second,
+		time.Duration(cfg.Bot.RestartMaxBackoffSeconds)*time.Second,
+	)
+	client.SetBroadcastChatIDs(cfg.Bot.BroadcastChatIDs)
+	svc = tracker.New(cfg, store, client)
+	svc.SetLogLevelVar(logLevel)
+	if cfg.Teams.WebhookURL != "" {
+		svc.AddAlertSink(teams.New(cfg.Teams.WebhookURL))
+	}
 	var dash *dashboard.Server
 	if cfg.Dashboard.Enabled {
 		allowedMiniAppUserID := int64(0)
@@ -66,6 +97,9 @@ func main() {
 			os.Exit(1)
 		}
 		svc.SetAuthLinkGenerator(dash.NewAuthLink)
+		if cfg.Dashboard.PublicURL != "" {
+			svc.SetDashboardLinkGenerator(dash.NewSignedLink)
+		}
 	}
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
@@ -80,14 +114,7 @@ func main() {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case update := <-updates:
-				svc.HandleUpdate(ctx, update)
-			}
-		}
+		svc.RunUpdates(ctx)
 	}()
 	if dash != nil {
 		wg.Add(1)
@@ -100,22 +127,97 @@ func main() {
 		}()
 	}
 
+	go reloadOnSIGHUP(ctx, cfgPath, cfg, svc)
+
 	sendStatus(client, "<b>INFO</b>\nport tracker started (Go)")
 	client.Start(ctx)
 	wg.Wait()
 	sendStatus(client, "<b>INFO</b>\nport tracker stopped")
 }
 
+// reloadOnSIGHUP re-reads the config file on each SIGHUP and applies it to the
+// running monitor. A config that does not load is rejected and the current
+// one stays in force.
+func reloadOnSIGHUP(ctx context.Context, cfgPath string, current config.Config, svc *tracker.Service) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		}
+		next, err := config.Load(cfgPath)
+		if err != nil {
+			slog.Error("config reload rejected, keeping the current config", "path", cfgPath, "error", err)
+			continue
+		}
+		if changed := restartOnlyChanges(current, next); len(changed) > 0 {
+			slog.Warn("config changes that need a restart were not applied", "sections", strings.Join(changed, ","))
+		}
+		if err := svc.Reload(next); err != nil {
+			slog.Error("config reload failed", "path", cfgPath, "error", err)
+			continue
+		}
+		// Keep comparing against what is in force, so a restart-only change
+		// is reported on every reload until the process restarts.
+		current = appliedConfig(current, next)
+	}
+}
+
+// appliedConfig returns the config in force after Reload applied next on top
+// of current: the targets, maintenance windows and the monitoring fields
+// Reload handles come from next, everything else stays as current.
+func appliedConfig(current, next config.Config) config.Config {
+	applied := current
+	applied.Monitoring.IntervalSeconds = next.Monitoring.IntervalSeconds
+	applied.Monitoring.ConnectTimeoutSeconds = next.Monitoring.ConnectTimeoutSeconds
+	applied.Monitoring.MaxParallelChecks = next.Monitoring.MaxParallelChecks
+	applied.Monitoring.EscalateAfterSeconds = next.Monitoring.EscalateAfterSeconds
+	applied.Monitoring.FailureThreshold = next.Monitoring.FailureThreshold
+	applied.Targets = next.Targets
+	applied.Maintenance = next.Maintenance
+	return applied
+}
+
+// restartOnlyChanges names the changed config sections that Reload does not
+// apply.
+func restartOnlyChanges(current, next config.Config) []string {
+	applied := appliedConfig(current, next)
+	var changed []string
+	for _, section := range []struct {
+		name          string
+		applied, next any
+	}{
+		{"bot", applied.Bot, next.Bot},
+		{"monitoring", applied.Monitoring, next.Monitoring},
+		{"storage", applied.Storage, next.Storage},
+		{"dashboard", applied.Dashboard, next.Dashboard},
+		{"teams", applied.Teams, next.Teams},
+		{"otel", applied.Otel, next.Otel},
+		{"graph", applied.Graph, next.Graph},
+		{"quiet_hours", applied.QuietHours, next.QuietHours},
+		{"target_source", applied.TargetSource, next.TargetSource},
+	} {
+		if !reflect.DeepEqual(section.applied, section.next) {
+			changed = append(changed, section.name)
+		}
+	}
+	return changed
+}
+
 func initStore(cfg config.Config) (*logstore.Store, error) {
 	if cfg.Storage.Driver != "sqlite" {
 		return nil, fmt.Errorf("unsupported storage driver: %s", cfg.Storage.Driver)
 	}
 	return logstore.NewSQLite(logstore.SQLiteOptions{
-		Path:          cfg.Storage.SQLite.Path,
-		RetentionDays: cfg.Storage.SQLite.RetentionDays,
-		BusyTimeoutMS: cfg.Storage.SQLite.BusyTimeoutMS,
-		MaxOpenConns:  cfg.Storage.SQLite.MaxOpenConns,
-		MaxIdleConns:  cfg.Storage.SQLite.MaxIdleConns,
+		Path:           cfg.Storage.SQLite.Path,
+		RetentionDays:  cfg.Storage.SQLite.RetentionDays,
+		BusyTimeoutMS:  cfg.Storage.SQLite.BusyTimeoutMS,
+		MaxOpenConns:   cfg.Storage.SQLite.MaxOpenConns,
+		MaxIdleConns:   cfg.Storage.SQLite.MaxIdleConns,
+		DedupePollRows: cfg.Storage.SQLite.DedupePollRows,
 	})
 }
 
@@ -135,6 +237,28 @@ func sendStatus(client *telegram.Client, message string) {
 	}
 }
 
+// requireTargets fails when neither the store nor the target source file,
+// which the engine only loads once it runs, holds a target.
+func requireTargets(store *logstore.Store, cfg config.Config) error {
+	existing, err := store.ListTargets()
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return nil
+	}
+	if cfg.TargetSource.Source == "file" {
+		targets, err := config.LoadTargetsFile(cfg.TargetSource.Path, cfg.Monitoring.RejectDuplicateEndpoints)
+		if err != nil {
+			return fmt.Errorf("monitoring.require_targets is set, no targets are stored and the target source file does not load: %w", err)
+		}
+		if len(targets) > 0 {
+			return nil
+		}
+	}
+	return errors.New("monitoring.require_targets is set but no targets are configured")
+}
+
 func seedTargets(store *logstore.Store, targets []config.Target) error {
 	if len(targets) == 0 {
 		return nil
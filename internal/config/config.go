@@ -2,12 +2,23 @@ package config
 
 import (
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
 	"os"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+	// Quiet hours timezones must resolve in minimal images without zoneinfo.
+	_ "time/tzdata"
+
+	"github.com/robfig/cron/v3"
 )
 
 const (
@@ -17,21 +28,150 @@ const (
 	defaultSQLiteBusyTimeout  = 5000
 	defaultSQLiteMaxOpenConns = 1
 	defaultSQLiteMaxIdleConns = 1
+	defaultAutoDisableHours   = 72
+	defaultRestartBackoffSec  = 1
+	defaultRestartMaxBackoff  = 300
+	maxUpdateQueueSize        = 10000
+	maxWindowChecks           = 100
+	maxFailureThreshold       = 20
+	maxIntervalSeconds        = 3600
+	maxCertWarnDays           = 365
+	maxFastRecoveryWindow     = 86400
+	maxAlertCooldown          = 86400
+	maxSlowThresholdMs        = 300000
+	maxGraphSide              = 2000
+	maxProbeRetries           = 5
+	maxProbes                 = 20
+	defaultTargetsReloadSec   = 60
+	defaultAuthRateLimit      = 20
+	defaultAuthRateWindowSec  = 60
 )
 
+// DefaultUpdateQueueSize buffers bot updates when bot.update_queue_size is
+// unset.
+const DefaultUpdateQueueSize = 128
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9_.-]{1,32}$`)
+
 type Config struct {
 	Bot struct {
-		Token  string `json:"token"`
-		ChatID int64  `json:"chat_id"`
+		Token            string `json:"token"`
+		ChatID           int64  `json:"chat_id"`
+		EscalationChatID int64  `json:"escalation_chat_id"`
+		// BroadcastChatIDs get a copy of every alert sent to ChatID.
+		BroadcastChatIDs []int64 `json:"broadcast_chat_ids"`
+		// PinnedStatus keeps a pinned live status message in the chat and sends
+		// alerts as replies to it.
+		PinnedStatus bool `json:"pinned_status"`
+		// Restart backoff for the Telegram polling loop.
+		RestartBackoffSeconds    int `json:"restart_backoff_seconds"`
+		RestartMaxBackoffSeconds int `json:"restart_max_backoff_seconds"`
+		// UpdateQueueSize buffers incoming updates for the command handler;
+		// updates beyond it are dropped and counted in /diag.
+		UpdateQueueSize     int  `json:"update_queue_size"`
+		AlertDroppedUpdates bool `json:"alert_dropped_updates"`
 	} `json:"bot"`
 	Monitoring struct {
-		IntervalSeconds       int `json:"interval_seconds"`
-		ConnectTimeoutSeconds int `json:"connect_timeout_seconds"`
-		MaxParallelChecks     int `json:"max_parallel_checks"`
+		IntervalSeconds          int    `json:"interval_seconds"`
+		ConnectTimeoutSeconds    int    `json:"connect_timeout_seconds"`
+		MaxParallelChecks        int    `json:"max_parallel_checks"`
+		RejectDuplicateEndpoints bool   `json:"reject_duplicate_endpoints"`
+		AutoDisable              bool   `json:"auto_disable"`
+		AutoDisableAfterHours    int    `json:"auto_disable_after_hours"`
+		StorageFailureThreshold  int    `json:"storage_failure_threshold"`
+		StorageAlertCooldownSec  int    `json:"storage_alert_cooldown_seconds"`
+		EscalateAfterSeconds     int    `json:"escalate_after_seconds"`
+		CycleLogLevel            string `json:"cycle_log_level"`
+		RequireTargets           bool   `json:"require_targets"`
+		// OrphanRecovery decides what happens to a RECOVERED alert for a target
+		// whose DOWN alert was never sent: "relabel" (default), "suppress" or "send".
+		OrphanRecovery string `json:"orphan_recovery"`
+		// RecoveryConfirmSeconds > 0 holds each RECOVERED alert until a recheck
+		// that many seconds later also succeeds.
+		RecoveryConfirmSeconds int `json:"recovery_confirm_seconds"`
+		// FastRecoveryWindowSeconds is how soon after a DOWN alert a recovery
+		// edits it into DOWN -> RECOVERED instead of sending a new message;
+		// 0 means 30 seconds.
+		FastRecoveryWindowSeconds int `json:"fast_recovery_window_seconds"`
+		// AlertCooldownSeconds > 0 suppresses another DOWN alert for a target
+		// within that many seconds of its last one, unless a recovery was
+		// reported in between.
+		AlertCooldownSeconds int `json:"alert_cooldown_seconds"`
+		// StartupResolve pre-resolves target hostnames before the first cycle;
+		// WarmupCheck also runs one check round that is not recorded or alerted.
+		StartupResolve bool `json:"startup_resolve"`
+		WarmupCheck    bool `json:"warmup_check"`
+		// InvestigateFirstFailure posts the first failure of an UP target as an
+		// INVESTIGATING note and only alerts DOWN once a second check fails.
+		InvestigateFirstFailure bool `json:"investigate_first_failure"`
+		// DownTimeoutSeconds > 0 replaces the connect timeout for targets whose
+		// last known state is DOWN, so a slow recovery is not missed.
+		DownTimeoutSeconds int `json:"down_timeout_seconds"`
+		// FailureThreshold is the default number of consecutive failed checks
+		// before a target is DOWN; 0 and 1 flip on the first failure.
+		FailureThreshold int `json:"failure_threshold"`
+		// SourceAddress is the local IP probes connect from on multi-homed
+		// hosts; empty leaves the choice to the OS.
+		SourceAddress string `json:"source_address"`
+		// ProbeRetries retries a failed tcp dial within the same check, and
+		// its connect timeout, before it counts as failed; 0 keeps a single
+		// attempt.
+		ProbeRetries int `json:"probe_retries"`
 	} `json:"monitoring"`
 	Storage   Storage   `json:"storage"`
 	Dashboard Dashboard `json:"dashboard"`
-	Targets   []Target  `json:"targets"`
+	Teams     struct {
+		// WebhookURL is a Teams incoming-webhook URL; empty disables Teams alerts.
+		WebhookURL string `json:"webhook_url"`
+	} `json:"teams"`
+	Otel    Otel     `json:"otel"`
+	Graph   Graph    `json:"graph"`
+	Targets []Target `json:"targets"`
+	// Maintenance lists planned windows in which alerts of the affected
+	// targets are suppressed while checks and logging go on.
+	Maintenance []MaintenanceWindow `json:"maintenance,omitempty"`
+	QuietHours  QuietHours          `json:"quiet_hours"`
+	// TargetSource reads further targets from an external file.
+	TargetSource TargetSource `json:"target_source"`
+}
+
+// TargetSource with Source "file" reloads targets from the JSON or YAML file
+// at Path every ReloadIntervalSeconds (default 60) and reconciles the store
+// against it. Targets dropped from the file are disabled, not deleted.
+type TargetSource struct {
+	Source                string `json:"source,omitempty"`
+	Path                  string `json:"path,omitempty"`
+	ReloadIntervalSeconds int    `json:"reload_interval_seconds,omitempty"`
+}
+
+// QuietHours defers alerts of targets that are not Critical from Start to
+// End ("HH:MM" wall clock in Timezone, default UTC; the window may wrap
+// midnight) into one digest sent when it ends. An empty Start disables it.
+type QuietHours struct {
+	Start    string `json:"start,omitempty"`
+	End      string `json:"end,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// ParseClock parses an "HH:MM" wall clock time into minutes after midnight.
+func ParseClock(value string) (int, error) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not HH:MM", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// MaintenanceWindow is either a fixed Start-End range (RFC3339) or a
+// recurring one starting at each Schedule tick (5-field cron, UTC) and
+// lasting DurationMinutes. No Targets means every target.
+type MaintenanceWindow struct {
+	Name            string   `json:"name,omitempty"`
+	Start           string   `json:"start,omitempty"`
+	End             string   `json:"end,omitempty"`
+	Schedule        string   `json:"schedule,omitempty"`
+	DurationMinutes int      `json:"duration_minutes,omitempty"`
+	Targets         []string `json:"targets,omitempty"`
 }
 
 type Storage struct {
@@ -40,27 +180,159 @@ type Storage struct {
 }
 
 type SQLite struct {
-	Path          string `json:"path"`
-	RetentionDays int    `json:"retention_days"`
-	BusyTimeoutMS int    `json:"busy_timeout_ms"`
-	MaxOpenConns  int    `json:"max_open_conns"`
-	MaxIdleConns  int    `json:"max_idle_conns"`
+	Path           string `json:"path"`
+	RetentionDays  int    `json:"retention_days"`
+	BusyTimeoutMS  int    `json:"busy_timeout_ms"`
+	MaxOpenConns   int    `json:"max_open_conns"`
+	MaxIdleConns   int    `json:"max_idle_conns"`
+	DedupePollRows bool   `json:"dedupe_poll_rows"`
 }
 
 type Target struct {
-	Name    string `json:"name"`
-	Address string `json:"address"`
-	Port    int    `json:"port"`
+	Name       string `json:"name"`
+	Address    string `json:"address"`
+	Port       int    `json:"port"`
+	Mention    string `json:"mention,omitempty"`
+	RunbookURL string `json:"runbook_url,omitempty"`
+	// Probes > 1 (at most 20) opens that many fresh connections per cycle,
+	// for endpoints behind a load balancer; MinHealthyProbes defaults to all
+	// of them.
+	Probes           int `json:"probes,omitempty"`
+	MinHealthyProbes int `json:"min_healthy_probes,omitempty"`
+	// Schedule is a standard 5-field cron expression; checks only run in
+	// minutes it matches and the target is suspended otherwise.
+	Schedule string `json:"schedule,omitempty"`
+	// WindowChecks/WindowFailures switch the target to a windowed policy: it is
+	// DOWN while at least WindowFailures of the last WindowChecks checks failed.
+	WindowChecks   int `json:"window_checks,omitempty"`
+	WindowFailures int `json:"window_failures,omitempty"`
+	// CertWarnDays > 0 verifies the TLS certificate on the target port after
+	// successful checks and alerts CERT_EXPIRING when fewer days are left, or
+	// CERT_INVALID when the chain does not verify.
+	CertWarnDays int `json:"cert_warn_days,omitempty"`
+	// IntervalSeconds overrides monitoring.interval_seconds for the target.
+	IntervalSeconds int `json:"interval_seconds,omitempty"`
+	// FailureThreshold overrides monitoring.failure_threshold for the target.
+	// A threshold set with /threshold takes precedence over both.
+	FailureThreshold int `json:"failure_threshold,omitempty"`
+	// FallbackCheck is tried when the primary TCP check fails; the target is
+	// UP if either succeeds.
+	FallbackCheck *FallbackCheck `json:"fallback_check,omitempty"`
+	// Critical targets make up the service-level uptime in /sla overall;
+	// Weight (default 1) is their share of the weighted uptime.
+	Critical bool    `json:"critical,omitempty"`
+	Weight   float64 `json:"weight,omitempty"`
+	// Impact orders grouped alerts: higher impact targets and the groups
+	// holding them are listed first. The default 0 keeps name order.
+	Impact int `json:"impact,omitempty"`
+	// WatchCert fingerprints the TLS certificate served on the target port
+	// and alerts CERT_CHANGED when it differs from the last one seen.
+	WatchCert bool `json:"watch_cert,omitempty"`
+	// AlertGate names another target, e.g. an internet gateway; while it is
+	// DOWN this target's alerts are held so an outage reports once.
+	AlertGate string `json:"alert_gate,omitempty"`
+	// Type selects the check: "tcp" (default) connects only; "http" and
+	// "https" GET Path on the target port and are DOWN unless the status is
+	// ExpectStatus (default: any status below 400); "ping" sends an ICMP echo
+	// and needs no port; "udp" sends UDPPayload (or UDPPayloadHex) to the port
+	// and is UP on a reply containing UDPExpect (any reply without one).
+	Type         string `json:"type,omitempty"`
+	Path         string `json:"path,omitempty"`
+	ExpectStatus int    `json:"expect_status,omitempty"`
+	// HealthyRegex/UnhealthyRegex decide an http or https check from the
+	// response body instead of the status when either is set, as on
+	// FallbackCheck.
+	HealthyRegex   string `json:"healthy_regex,omitempty"`
+	UnhealthyRegex string `json:"unhealthy_regex,omitempty"`
+	UDPPayload     string `json:"udp_payload,omitempty"`
+	UDPPayloadHex  string `json:"udp_payload_hex,omitempty"`
+	UDPExpect      string `json:"udp_expect,omitempty"`
+	// UDPNoResponse decides a udp check that gets no reply, which cannot tell
+	// a silent service from a dead one: "down" (default) or "up".
+	UDPNoResponse string `json:"udp_no_response,omitempty"`
+	// Tags group targets for /status <tag> and /api/status?tag=; they are
+	// lowercased and stored with the target.
+	Tags []string `json:"tags,omitempty"`
+	// SlowThresholdMs > 0 alerts SLOW while the target is UP but its check
+	// latency exceeds that many milliseconds.
+	SlowThresholdMs int `json:"slow_threshold_ms,omitempty"`
+}
+
+// FallbackCheck is a secondary check method. Type "tcp" connects to Address
+// (default: the target address) and Port; type "http" expects a non-error
+// status (< 400) from a GET to URL.
+type FallbackCheck struct {
+	Type    string `json:"type"`
+	Address string `json:"address,omitempty"`
+	Port    int    `json:"port,omitempty"`
+	URL     string `json:"url,omitempty"`
+	// HealthyRegex/UnhealthyRegex decide an http check from the response body
+	// instead of the status code when either is set; an unhealthy match wins.
+	HealthyRegex   string `json:"healthy_regex,omitempty"`
+	UnhealthyRegex string `json:"unhealthy_regex,omitempty"`
+}
+
+// Otel configures optional OpenTelemetry export over OTLP/HTTP. Endpoint is
+// the collector base URL, e.g. http://otel-collector:4318.
+type Otel struct {
+	Enabled     bool   `json:"enabled"`
+	Endpoint    string `json:"endpoint"`
+	ServiceName string `json:"service_name"`
+}
+
+// DefaultGraphWidth and DefaultGraphHeight size the /graph image when the
+// config leaves it unset.
+const (
+	DefaultGraphWidth  = 720
+	DefaultGraphHeight = 120
+)
+
+// Graph sets the size in pixels of the /graph image.
+type Graph struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
 }
 
 type Dashboard struct {
-	Enabled             bool   `json:"enabled"`
-	ListenAddress       string `json:"listen_address"`
-	PublicURL           string `json:"public_url"`
-	AuthTokenTTLSeconds int    `json:"auth_token_ttl_seconds"`
-	SecureCookie        bool   `json:"secure_cookie"`
-	MiniAppEnabled      bool   `json:"mini_app_enabled"`
-	MiniAppMaxAgeSec    int    `json:"mini_app_max_age_seconds"`
+	Enabled             bool     `json:"enabled"`
+	ListenAddress       string   `json:"listen_address"`
+	PublicURL           string   `json:"public_url"`
+	AuthTokenTTLSeconds int      `json:"auth_token_ttl_seconds"`
+	SecureCookie        bool     `json:"secure_cookie"`
+	MiniAppEnabled      bool     `json:"mini_app_enabled"`
+	MiniAppMaxAgeSec    int      `json:"mini_app_max_age_seconds"`
+	StaticDir           string   `json:"static_dir"`
+	MaxBodyBytes        int64    `json:"max_body_bytes"`
+	IPAllowlist         []string `json:"ip_allowlist"`
+	// TrustedProxies lists peers (CIDRs or addresses) whose X-Forwarded-For
+	// and X-Real-IP headers identify the client; empty trusts no proxy.
+	TrustedProxies []string `json:"trusted_proxies"`
+	// AllowedOrigins lists frontend origins (scheme://host[:port]) hosted
+	// apart from the API that may call /api/* with credentials.
+	AllowedOrigins []string `json:"allowed_origins,omitempty"`
+	// BasePath mounts the dashboard under a subpath (e.g. "/trackway") when
+	// reverse-proxied there; empty serves it at the root.
+	BasePath string `json:"base_path"`
+	// /metrics is open unless a bearer token or basic auth credentials are set;
+	// with both, either is accepted.
+	MetricsBearerToken string            `json:"metrics_bearer_token,omitempty"`
+	MetricsBasicAuth   *MetricsBasicAuth `json:"metrics_basic_auth,omitempty"`
+	// PublicBadge serves /api/badge.svg without auth for embedding; it only
+	// reveals the up and total target counts.
+	PublicBadge bool `json:"public_badge,omitempty"`
+	// BasicAuthUser and BasicAuthPassword let API clients such as CI
+	// authenticate with HTTP Basic auth instead of a session cookie.
+	BasicAuthUser     string `json:"basic_auth_user,omitempty"`
+	BasicAuthPassword string `json:"basic_auth_password,omitempty"`
+	// AuthRateLimit caps login attempts per client address within
+	// AuthRateWindowSeconds; further attempts get 429 until the window ends.
+	AuthRateLimit         int `json:"auth_rate_limit,omitempty"`
+	AuthRateWindowSeconds int `json:"auth_rate_window_seconds,omitempty"`
+}
+
+type MetricsBasicAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
 }
 
 func Load(path string) (Config, error) {
@@ -76,18 +348,85 @@ func Load(path string) (Config, error) {
 	if cfg.Bot.Token == "" || cfg.Bot.ChatID == 0 {
 		return cfg, errors.New("bot.token and bot.chat_id are required")
 	}
-	seenTargets := make(map[string]struct{}, len(cfg.Targets))
-	for i := range cfg.Targets {
-		cfg.Targets[i].Name = strings.TrimSpace(cfg.Targets[i].Name)
-		cfg.Targets[i].Address = strings.TrimSpace(cfg.Targets[i].Address)
-		if cfg.Targets[i].Name == "" || cfg.Targets[i].Address == "" || cfg.Targets[i].Port <= 0 {
-			return cfg, errors.New("each target requires non-empty name/address and port > 0")
+	for _, chatID := range cfg.Bot.BroadcastChatIDs {
+		if chatID == 0 {
+			return cfg, errors.New("bot.broadcast_chat_ids must not contain 0")
 		}
-		key := strings.ToLower(cfg.Targets[i].Name)
-		if _, exists := seenTargets[key]; exists {
-			return cfg, fmt.Errorf("duplicate target name: %s", cfg.Targets[i].Name)
+	}
+	if err := normalizeTargets(cfg.Targets, cfg.Monitoring.RejectDuplicateEndpoints); err != nil {
+		return cfg, err
+	}
+	if err := validateAlertGates(cfg.Targets); err != nil {
+		return cfg, err
+	}
+	if err := validateMaintenance(cfg.Maintenance, cfg.Targets); err != nil {
+		return cfg, err
+	}
+	if err := validateQuietHours(&cfg.QuietHours); err != nil {
+		return cfg, err
+	}
+	if err := validateTargetSource(&cfg.TargetSource); err != nil {
+		return cfg, err
+	}
+
+	if cfg.Monitoring.DownTimeoutSeconds < 0 || cfg.Monitoring.DownTimeoutSeconds > 60 {
+		return cfg, errors.New("monitoring.down_timeout_seconds must be between 0 and 60")
+	}
+	cfg.Monitoring.SourceAddress = strings.TrimSpace(cfg.Monitoring.SourceAddress)
+	if cfg.Monitoring.SourceAddress != "" {
+		if _, err := netip.ParseAddr(cfg.Monitoring.SourceAddress); err != nil {
+			return cfg, fmt.Errorf("monitoring.source_address must be an IP address: %w", err)
 		}
-		seenTargets[key] = struct{}{}
+	}
+	if cfg.Monitoring.ProbeRetries < 0 || cfg.Monitoring.ProbeRetries > maxProbeRetries {
+		return cfg, fmt.Errorf("monitoring.probe_retries must be between 0 and %d", maxProbeRetries)
+	}
+	if cfg.Monitoring.FastRecoveryWindowSeconds < 0 || cfg.Monitoring.FastRecoveryWindowSeconds > maxFastRecoveryWindow {
+		return cfg, fmt.Errorf("monitoring.fast_recovery_window_seconds must be between 0 and %d", maxFastRecoveryWindow)
+	}
+	if cfg.Monitoring.AlertCooldownSeconds < 0 || cfg.Monitoring.AlertCooldownSeconds > maxAlertCooldown {
+		return cfg, fmt.Errorf("monitoring.alert_cooldown_seconds must be between 0 and %d", maxAlertCooldown)
+	}
+	if cfg.Monitoring.FailureThreshold < 0 || cfg.Monitoring.FailureThreshold > maxFailureThreshold {
+		return cfg, fmt.Errorf("monitoring.failure_threshold must be between 0 and %d", maxFailureThreshold)
+	}
+
+	cfg.Monitoring.CycleLogLevel = strings.ToLower(strings.TrimSpace(cfg.Monitoring.CycleLogLevel))
+	switch cfg.Monitoring.CycleLogLevel {
+	case "":
+		cfg.Monitoring.CycleLogLevel = "info"
+	case "info", "debug":
+	default:
+		return cfg, fmt.Errorf("unsupported monitoring.cycle_log_level: %s", cfg.Monitoring.CycleLogLevel)
+	}
+
+	if cfg.Bot.RestartBackoffSeconds <= 0 {
+		cfg.Bot.RestartBackoffSeconds = defaultRestartBackoffSec
+	}
+	if cfg.Bot.RestartMaxBackoffSeconds <= 0 {
+		cfg.Bot.RestartMaxBackoffSeconds = defaultRestartMaxBackoff
+	}
+	if cfg.Bot.RestartMaxBackoffSeconds < cfg.Bot.RestartBackoffSeconds {
+		return cfg, errors.New("bot.restart_max_backoff_seconds must be >= bot.restart_backoff_seconds")
+	}
+	if cfg.Bot.UpdateQueueSize <= 0 {
+		cfg.Bot.UpdateQueueSize = DefaultUpdateQueueSize
+	}
+	if cfg.Bot.UpdateQueueSize > maxUpdateQueueSize {
+		return cfg, fmt.Errorf("bot.update_queue_size must be <= %d", maxUpdateQueueSize)
+	}
+
+	cfg.Monitoring.OrphanRecovery = strings.ToLower(strings.TrimSpace(cfg.Monitoring.OrphanRecovery))
+	switch cfg.Monitoring.OrphanRecovery {
+	case "":
+		cfg.Monitoring.OrphanRecovery = "relabel"
+	case "relabel", "suppress", "send":
+	default:
+		return cfg, fmt.Errorf("unsupported monitoring.orphan_recovery: %s", cfg.Monitoring.OrphanRecovery)
+	}
+
+	if cfg.Monitoring.AutoDisable && cfg.Monitoring.AutoDisableAfterHours <= 0 {
+		cfg.Monitoring.AutoDisableAfterHours = defaultAutoDisableHours
 	}
 
 	if err := normalizeStorageConfig(&cfg); err != nil {
@@ -96,6 +435,12 @@ func Load(path string) (Config, error) {
 
 	cfg.Dashboard.ListenAddress = strings.TrimSpace(cfg.Dashboard.ListenAddress)
 	cfg.Dashboard.PublicURL = strings.TrimSpace(cfg.Dashboard.PublicURL)
+	cfg.Dashboard.StaticDir = strings.TrimSpace(cfg.Dashboard.StaticDir)
+	basePath, err := normalizeBasePath(cfg.Dashboard.BasePath)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Dashboard.BasePath = basePath
 	if !cfg.Dashboard.Enabled && (cfg.Dashboard.ListenAddress != "" || cfg.Dashboard.PublicURL != "") {
 		cfg.Dashboard.Enabled = true
 	}
@@ -108,6 +453,48 @@ func Load(path string) (Config, error) {
 	if cfg.Dashboard.MiniAppMaxAgeSec <= 0 {
 		cfg.Dashboard.MiniAppMaxAgeSec = 86400
 	}
+	for i, origin := range cfg.Dashboard.AllowedOrigins {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		if parsed, err := url.Parse(origin); err != nil || !isHTTPURL(origin) || parsed.Path != "" {
+			return cfg, fmt.Errorf("dashboard.allowed_origins: %q must be an http(s) origin without a path", origin)
+		}
+		cfg.Dashboard.AllowedOrigins[i] = origin
+	}
+	if cfg.Dashboard.AuthRateLimit <= 0 {
+		cfg.Dashboard.AuthRateLimit = defaultAuthRateLimit
+	}
+	if cfg.Dashboard.AuthRateWindowSeconds <= 0 {
+		cfg.Dashboard.AuthRateWindowSeconds = defaultAuthRateWindowSec
+	}
+	cfg.Teams.WebhookURL = strings.TrimSpace(cfg.Teams.WebhookURL)
+	if cfg.Teams.WebhookURL != "" && !isHTTPURL(cfg.Teams.WebhookURL) {
+		return cfg, errors.New("teams.webhook_url must be an http(s) URL")
+	}
+	cfg.Otel.Endpoint = strings.TrimSpace(cfg.Otel.Endpoint)
+	if cfg.Otel.Enabled && !isHTTPURL(cfg.Otel.Endpoint) {
+		return cfg, errors.New("otel.endpoint must be an http(s) URL when otel.enabled is true")
+	}
+	if cfg.Otel.ServiceName == "" {
+		cfg.Otel.ServiceName = "trackway"
+	}
+	if cfg.Graph.Width <= 0 {
+		cfg.Graph.Width = DefaultGraphWidth
+	}
+	if cfg.Graph.Height <= 0 {
+		cfg.Graph.Height = DefaultGraphHeight
+	}
+	if cfg.Graph.Width > maxGraphSide || cfg.Graph.Height > maxGraphSide {
+		return cfg, fmt.Errorf("graph.width and graph.height must be <= %d", maxGraphSide)
+	}
+	if cfg.Graph.Width > 20*cfg.Graph.Height || cfg.Graph.Height > 20*cfg.Graph.Width {
+		return cfg, errors.New("graph.width and graph.height must not differ by more than 20 times")
+	}
+	if (cfg.Dashboard.BasicAuthUser == "") != (cfg.Dashboard.BasicAuthPassword == "") {
+		return cfg, errors.New("dashboard.basic_auth_user and dashboard.basic_auth_password must be set together")
+	}
+	if auth := cfg.Dashboard.MetricsBasicAuth; auth != nil && (auth.Username == "" || auth.Password == "") {
+		return cfg, errors.New("dashboard.metrics_basic_auth requires username and password")
+	}
 	if cfg.Dashboard.Enabled && cfg.Dashboard.PublicURL == "" {
 		return cfg, errors.New("dashboard.public_url is required when dashboard.enabled is true")
 	}
@@ -115,6 +502,342 @@ func Load(path string) (Config, error) {
 	return cfg, nil
 }
 
+// normalizeTargets trims and validates targets in place. With
+// rejectDuplicates, two targets on one endpoint are an error.
+func normalizeTargets(targets []Target, rejectDuplicates bool) error {
+	seenTargets := make(map[string]struct{}, len(targets))
+	seenEndpoints := make(map[string]string, len(targets))
+	for i := range targets {
+		targets[i].Name = strings.TrimSpace(targets[i].Name)
+		targets[i].Address = strings.TrimSpace(targets[i].Address)
+		targets[i].Mention = strings.TrimSpace(targets[i].Mention)
+		targets[i].Schedule = strings.TrimSpace(targets[i].Schedule)
+		targets[i].RunbookURL = strings.TrimSpace(targets[i].RunbookURL)
+		targets[i].Type = strings.ToLower(strings.TrimSpace(targets[i].Type))
+		if targets[i].RunbookURL != "" && !isHTTPURL(targets[i].RunbookURL) {
+			return fmt.Errorf("target %s: runbook_url must be an http(s) URL", targets[i].Name)
+		}
+		if targets[i].Name == "" || targets[i].Address == "" || (targets[i].Port <= 0 && targets[i].Type != "ping") {
+			return errors.New("each target requires non-empty name/address and port > 0 (except type ping)")
+		}
+		if targets[i].Probes < 0 || targets[i].Probes > maxProbes {
+			return fmt.Errorf("target %s: probes must be between 0 and %d", targets[i].Name, maxProbes)
+		}
+		if targets[i].MinHealthyProbes < 0 || targets[i].MinHealthyProbes > max(targets[i].Probes, 1) {
+			return fmt.Errorf("target %s: min_healthy_probes must be between 0 and probes", targets[i].Name)
+		}
+		if targets[i].Weight < 0 {
+			return fmt.Errorf("target %s: weight must not be negative", targets[i].Name)
+		}
+		if targets[i].IntervalSeconds < 0 || targets[i].IntervalSeconds > maxIntervalSeconds {
+			return fmt.Errorf("target %s: interval_seconds must be between 0 and %d", targets[i].Name, maxIntervalSeconds)
+		}
+		if targets[i].CertWarnDays < 0 || targets[i].CertWarnDays > maxCertWarnDays {
+			return fmt.Errorf("target %s: cert_warn_days must be between 0 and %d", targets[i].Name, maxCertWarnDays)
+		}
+		if targets[i].SlowThresholdMs < 0 || targets[i].SlowThresholdMs > maxSlowThresholdMs {
+			return fmt.Errorf("target %s: slow_threshold_ms must be between 0 and %d", targets[i].Name, maxSlowThresholdMs)
+		}
+		if targets[i].FailureThreshold < 0 || targets[i].FailureThreshold > maxFailureThreshold {
+			return fmt.Errorf("target %s: failure_threshold must be between 0 and %d", targets[i].Name, maxFailureThreshold)
+		}
+		if err := validateWindowPolicy(targets[i]); err != nil {
+			return fmt.Errorf("target %s: %w", targets[i].Name, err)
+		}
+		if err := validateCheckType(&targets[i]); err != nil {
+			return fmt.Errorf("target %s: %w", targets[i].Name, err)
+		}
+		if err := validateFallbackCheck(targets[i].FallbackCheck); err != nil {
+			return fmt.Errorf("target %s: %w", targets[i].Name, err)
+		}
+		tags, err := normalizeTags(targets[i].Tags)
+		if err != nil {
+			return fmt.Errorf("target %s: %w", targets[i].Name, err)
+		}
+		targets[i].Tags = tags
+		if targets[i].Schedule != "" {
+			if _, err := cron.ParseStandard(targets[i].Schedule); err != nil {
+				return fmt.Errorf("target %s: invalid schedule: %w", targets[i].Name, err)
+			}
+		}
+		key := strings.ToLower(targets[i].Name)
+		if _, exists := seenTargets[key]; exists {
+			return fmt.Errorf("duplicate target name: %s", targets[i].Name)
+		}
+		seenTargets[key] = struct{}{}
+
+		endpoint := NormalizeEndpoint(targets[i].Type, targets[i].Address, targets[i].Port)
+		if other, exists := seenEndpoints[endpoint]; exists && rejectDuplicates {
+			return fmt.Errorf("targets %s and %s share endpoint %s", other, targets[i].Name, endpoint)
+		}
+		seenEndpoints[endpoint] = targets[i].Name
+	}
+	return nil
+}
+
+// NormalizeEndpoint returns a canonical form of what a target checks, used to
+// detect targets that check the same endpoint under different names. tcp
+// checks, the default, are a bare host:port; other check types are prefixed
+// with the type, e.g. udp://host:port, so a tcp and an http check of one port
+// are not duplicates. ping checks have no port.
+func NormalizeEndpoint(checkType, address string, port int) string {
+	host := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(address)), ".")
+	if ip := net.ParseIP(strings.Trim(host, "[]")); ip != nil {
+		host = ip.String()
+	}
+	switch checkType {
+	case "", "tcp":
+		return net.JoinHostPort(host, strconv.Itoa(port))
+	case "ping":
+		return "ping://" + host
+	}
+	return checkType + "://" + net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+func validateWindowPolicy(target Target) error {
+	if target.WindowChecks == 0 && target.WindowFailures == 0 {
+		return nil
+	}
+	if target.WindowChecks < 1 || target.WindowChecks > maxWindowChecks {
+		return fmt.Errorf("window_checks must be between 1 and %d", maxWindowChecks)
+	}
+	if target.WindowFailures < 1 || target.WindowFailures > target.WindowChecks {
+		return errors.New("window_failures must be between 1 and window_checks")
+	}
+	return nil
+}
+
+// validateCheckType normalizes the check type and its HTTP options.
+func validateCheckType(target *Target) error {
+	target.Path = strings.TrimSpace(target.Path)
+	if target.Type != "http" && target.Type != "https" && (target.Path != "" || target.ExpectStatus != 0 || target.HealthyRegex != "" || target.UnhealthyRegex != "") {
+		return errors.New("path, expect_status, healthy_regex and unhealthy_regex need type http or https")
+	}
+	target.UDPNoResponse = strings.ToLower(strings.TrimSpace(target.UDPNoResponse))
+	if target.Type != "udp" && (target.UDPPayload != "" || target.UDPPayloadHex != "" || target.UDPExpect != "" || target.UDPNoResponse != "") {
+		return errors.New("udp_payload, udp_payload_hex, udp_expect and udp_no_response need type udp")
+	}
+	switch target.Type {
+	case "", "tcp":
+		return nil
+	case "udp":
+		return validateUDPCheck(target)
+	case "ping":
+		if target.WatchCert || target.CertWarnDays > 0 {
+			return errors.New("watch_cert and cert_warn_days need a port and are not supported for type ping")
+		}
+		return nil
+	case "http", "https":
+	default:
+		return fmt.Errorf("unsupported type: %s", target.Type)
+	}
+	if target.Path != "" && !strings.HasPrefix(target.Path, "/") {
+		return errors.New("path must start with /")
+	}
+	if target.ExpectStatus != 0 && (target.ExpectStatus < 100 || target.ExpectStatus > 599) {
+		return errors.New("expect_status must be between 100 and 599")
+	}
+	if _, err := regexp.Compile(target.HealthyRegex); err != nil {
+		return fmt.Errorf("invalid healthy_regex: %w", err)
+	}
+	if _, err := regexp.Compile(target.UnhealthyRegex); err != nil {
+		return fmt.Errorf("invalid unhealthy_regex: %w", err)
+	}
+	return nil
+}
+
+func validateUDPCheck(target *Target) error {
+	if target.WatchCert || target.CertWarnDays > 0 {
+		return errors.New("watch_cert and cert_warn_days are not supported for type udp")
+	}
+	if target.UDPPayload != "" && target.UDPPayloadHex != "" {
+		return errors.New("set only one of udp_payload and udp_payload_hex")
+	}
+	if _, err := hex.DecodeString(target.UDPPayloadHex); err != nil {
+		return fmt.Errorf("udp_payload_hex: %w", err)
+	}
+	switch target.UDPNoResponse {
+	case "":
+		target.UDPNoResponse = "down"
+	case "down", "up":
+	default:
+		return fmt.Errorf("unsupported udp_no_response: %s", target.UDPNoResponse)
+	}
+	return nil
+}
+
+// normalizeTags lowercases and dedupes tags, keeping their order.
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !tagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("invalid tag %q: use 1-32 letters, digits, '-', '_' or '.'", tag)
+		}
+		if !slices.Contains(out, tag) {
+			out = append(out, tag)
+		}
+	}
+	return out, nil
+}
+
+func validateFallbackCheck(check *FallbackCheck) error {
+	if check == nil {
+		return nil
+	}
+	check.Type = strings.ToLower(strings.TrimSpace(check.Type))
+	check.Address = strings.TrimSpace(check.Address)
+	check.URL = strings.TrimSpace(check.URL)
+	switch check.Type {
+	case "tcp":
+		if check.Port <= 0 || check.Port > 65535 {
+			return errors.New("fallback_check.port must be between 1 and 65535")
+		}
+	case "http":
+		if !isHTTPURL(check.URL) {
+			return errors.New("fallback_check.url must be an http(s) URL")
+		}
+	default:
+		return fmt.Errorf("unsupported fallback_check.type: %s", check.Type)
+	}
+	if check.Type != "http" && (check.HealthyRegex != "" || check.UnhealthyRegex != "") {
+		return errors.New("fallback_check.healthy_regex and unhealthy_regex need type http")
+	}
+	if _, err := regexp.Compile(check.HealthyRegex); err != nil {
+		return fmt.Errorf("invalid fallback_check.healthy_regex: %w", err)
+	}
+	if _, err := regexp.Compile(check.UnhealthyRegex); err != nil {
+		return fmt.Errorf("invalid fallback_check.unhealthy_regex: %w", err)
+	}
+	return nil
+}
+
+// validateAlertGates requires each alert_gate to name another configured
+// target that is not gated itself, so gates never chain.
+func validateAlertGates(targets []Target) error {
+	gates := make(map[string]string, len(targets))
+	for i := range targets {
+		targets[i].AlertGate = strings.TrimSpace(targets[i].AlertGate)
+		gates[targets[i].Name] = targets[i].AlertGate
+	}
+	for _, target := range targets {
+		if target.AlertGate == "" {
+			continue
+		}
+		gateOfGate, exists := gates[target.AlertGate]
+		switch {
+		case target.AlertGate == target.Name:
+			return fmt.Errorf("target %s: alert_gate must name another target", target.Name)
+		case !exists:
+			return fmt.Errorf("target %s: alert_gate %s is not a configured target", target.Name, target.AlertGate)
+		case gateOfGate != "":
+			return fmt.Errorf("target %s: alert_gate %s has an alert_gate itself", target.Name, target.AlertGate)
+		}
+	}
+	return nil
+}
+
+// validateQuietHours checks the window bounds and timezone, defaulting the
+// timezone to UTC.
+func validateQuietHours(quiet *QuietHours) error {
+	if quiet.Start == "" && quiet.End == "" {
+		return nil
+	}
+	start, err := ParseClock(quiet.Start)
+	if err != nil {
+		return fmt.Errorf("quiet_hours.start: %w", err)
+	}
+	end, err := ParseClock(quiet.End)
+	if err != nil {
+		return fmt.Errorf("quiet_hours.end: %w", err)
+	}
+	if start == end {
+		return errors.New("quiet_hours.start and quiet_hours.end must differ")
+	}
+	quiet.Timezone = strings.TrimSpace(quiet.Timezone)
+	if quiet.Timezone == "" {
+		quiet.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(quiet.Timezone); err != nil {
+		return fmt.Errorf("quiet_hours.timezone: %w", err)
+	}
+	return nil
+}
+
+// validateMaintenance checks that each window is either a fixed range or a
+// schedule with a duration, and only names configured targets.
+func validateMaintenance(windows []MaintenanceWindow, targets []Target) error {
+	names := make(map[string]string, len(targets))
+	for _, target := range targets {
+		names[strings.ToLower(target.Name)] = target.Name
+	}
+	for i := range windows {
+		window := &windows[i]
+		window.Name = strings.TrimSpace(window.Name)
+		if window.Name == "" {
+			window.Name = fmt.Sprintf("window-%d", i+1)
+		}
+		window.Schedule = strings.TrimSpace(window.Schedule)
+		fixed := window.Start != "" || window.End != ""
+		switch {
+		case fixed && window.Schedule != "":
+			return fmt.Errorf("maintenance %s: use start/end or schedule, not both", window.Name)
+		case fixed:
+			start, err := time.Parse(time.RFC3339, strings.TrimSpace(window.Start))
+			if err != nil {
+				return fmt.Errorf("maintenance %s: start must be RFC3339", window.Name)
+			}
+			end, err := time.Parse(time.RFC3339, strings.TrimSpace(window.End))
+			if err != nil {
+				return fmt.Errorf("maintenance %s: end must be RFC3339", window.Name)
+			}
+			if !end.After(start) {
+				return fmt.Errorf("maintenance %s: end must be after start", window.Name)
+			}
+		case window.Schedule != "":
+			if _, err := cron.ParseStandard(window.Schedule); err != nil {
+				return fmt.Errorf("maintenance %s: invalid schedule: %w", window.Name, err)
+			}
+			if window.DurationMinutes < 1 || window.DurationMinutes > 24*60 {
+				return fmt.Errorf("maintenance %s: duration_minutes must be between 1 and 1440", window.Name)
+			}
+		default:
+			return fmt.Errorf("maintenance %s: start/end or schedule is required", window.Name)
+		}
+		for j, name := range window.Targets {
+			configured, ok := names[strings.ToLower(strings.TrimSpace(name))]
+			if !ok {
+				return fmt.Errorf("maintenance %s: %s is not a configured target", window.Name, name)
+			}
+			window.Targets[j] = configured
+		}
+	}
+	return nil
+}
+
+// normalizeBasePath returns "" or a path with a leading and no trailing slash.
+func normalizeBasePath(value string) (string, error) {
+	value = strings.Trim(strings.TrimSpace(value), "/")
+	if value == "" {
+		return "", nil
+	}
+	if strings.ContainsAny(value, "?#\"<> ") || strings.Contains(value, "//") || strings.Contains(value, "..") {
+		return "", fmt.Errorf("invalid dashboard.base_path: %s", value)
+	}
+	return "/" + value, nil
+}
+
+func isHTTPURL(value string) bool {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
 func loadInto(cfg *Config, path string) error {
 	configJSONB64 := strings.TrimSpace(os.Getenv("TRACKWAY_CONFIG_JSON_B64"))
 	if configJSONB64 != "" {
@@ -122,19 +845,20 @@ func loadInto(cfg *Config, path string) error {
 		if err != nil {
 			return fmt.Errorf("decode TRACKWAY_CONFIG_JSON_B64: %w", err)
 		}
-		return unmarshalJSONConfig(rawJSON, "TRACKWAY_CONFIG_JSON_B64", cfg)
+		return unmarshalJSONConfig(rawJSON, "TRACKWAY_CONFIG_JSON_B64", cfg, true)
 	}
 
 	configJSON := strings.TrimSpace(os.Getenv("TRACKWAY_CONFIG_JSON"))
 	if configJSON != "" {
-		return unmarshalJSONConfig([]byte(configJSON), "TRACKWAY_CONFIG_JSON", cfg)
+		return unmarshalJSONConfig([]byte(configJSON), "TRACKWAY_CONFIG_JSON", cfg, true)
 	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return err
 	}
-	return unmarshalJSONConfig(data, path, cfg)
+	// Files stay lenient so existing configs with stale keys keep loading.
+	return unmarshalJSONConfig(data, path, cfg, false)
 }
 
 func decodeBase64Config(value string) ([]byte, error) {
@@ -147,7 +871,9 @@ func decodeBase64Config(value string) ([]byte, error) {
 	return nil, errors.New("invalid base64 payload")
 }
 
-func unmarshalJSONConfig(data []byte, source string, cfg *Config) error {
+// unmarshalJSONConfig decodes a JSON config object. With strict set, keys that
+// match no config field are rejected, naming the offending key.
+func unmarshalJSONConfig(data []byte, source string, cfg *Config, strict bool) error {
 	payload := strings.TrimSpace(string(data))
 	if payload == "" {
 		return fmt.Errorf("%s is empty", source)
@@ -155,7 +881,14 @@ func unmarshalJSONConfig(data []byte, source string, cfg *Config) error {
 	if !strings.HasPrefix(payload, "{") {
 		return fmt.Errorf("%s must be JSON object (YAML is not supported)", source)
 	}
-	if err := json.Unmarshal([]byte(payload), cfg); err != nil {
+	decoder := json.NewDecoder(strings.NewReader(payload))
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(cfg); err != nil {
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			return fmt.Errorf("%s: unknown config field %s (check the key name and nesting)", source, field)
+		}
 		return fmt.Errorf("unmarshal %s: %w", source, err)
 	}
 	return nil
@@ -138,3 +138,443 @@ bot:
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestLoadRejectsUnknownFieldsOnlyInEnvConfig(t *testing.T) {
+	const payload = `{
+		"bot":{"token":"x","chat_id":1},
+		"monitoring":{"interval_secs":5}
+	}`
+	t.Setenv("TRACKWAY_CONFIG_JSON", payload)
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+
+	_, err := Load(filepath.Join(t.TempDir(), "unused.json"))
+	if err == nil || !strings.Contains(err.Error(), `TRACKWAY_CONFIG_JSON: unknown config field "interval_secs"`) {
+		t.Fatalf("expected unknown field error, got %v", err)
+	}
+
+	t.Setenv("TRACKWAY_CONFIG_JSON", "")
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(payload), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Load(path); err != nil {
+		t.Fatalf("expected lenient file load, got %v", err)
+	}
+}
+
+func TestLoadRejectsDuplicateEndpointsWhenConfigured(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{
+		"bot":{"token":"x","chat_id":1},
+		"monitoring":{"reject_duplicate_endpoints":true},
+		"dashboard":{"enabled":false},
+		"targets":[
+			{"name":"a","address":"10.0.0.1","port":22},
+			{"name":"b","address":"10.0.0.1","port":22}
+		]
+	}`)
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+
+	_, err := Load(filepath.Join(t.TempDir(), "unused.json"))
+	if err == nil {
+		t.Fatal("expected duplicate endpoint error")
+	}
+	if !strings.Contains(err.Error(), "share endpoint 10.0.0.1:22") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLoadKeepsChecksOfDifferentTypesOnOneEndpoint(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{
+		"bot":{"token":"x","chat_id":1},
+		"monitoring":{"reject_duplicate_endpoints":true},
+		"dashboard":{"enabled":false},
+		"targets":[
+			{"name":"dns-tcp","address":"10.0.0.1","port":53},
+			{"name":"dns-udp","address":"10.0.0.1","port":53,"type":"udp"},
+			{"name":"web","address":"10.0.0.1","port":80,"type":"http"},
+			{"name":"web-tcp","address":"10.0.0.1","port":80,"type":"tcp"}
+		]
+	}`)
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+
+	if _, err := Load(filepath.Join(t.TempDir(), "unused.json")); err != nil {
+		t.Fatalf("expected different check types on one endpoint to load, got %v", err)
+	}
+	if got := NormalizeEndpoint("udp", "10.0.0.1", 53); got == NormalizeEndpoint("", "10.0.0.1", 53) {
+		t.Fatalf("expected udp and tcp endpoints to differ, got %q", got)
+	}
+	if got := NormalizeEndpoint("tcp", "Example.com.", 443); got != "example.com:443" {
+		t.Fatalf("unexpected tcp endpoint %q", got)
+	}
+}
+
+func TestLoadRejectsInvalidTargetSchedule(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{
+		"bot":{"token":"x","chat_id":1},
+		"dashboard":{"enabled":false},
+		"targets":[
+			{"name":"a","address":"10.0.0.1","port":22,"schedule":"* 9-17 * * 1-5"},
+			{"name":"b","address":"10.0.0.2","port":22,"schedule":"every weekday"}
+		]
+	}`)
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+
+	_, err := Load(filepath.Join(t.TempDir(), "unused.json"))
+	if err == nil {
+		t.Fatal("expected invalid schedule error")
+	}
+	if !strings.Contains(err.Error(), "target b: invalid schedule") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLoadValidatesFallbackCheck(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{
+		"bot":{"token":"x","chat_id":1},
+		"dashboard":{"enabled":false},
+		"targets":[
+			{"name":"a","address":"10.0.0.1","port":22,"fallback_check":{"type":" HTTP ","url":"https://a.example/health"}},
+			{"name":"b","address":"10.0.0.2","port":22,"fallback_check":{"type":"tcp"}}
+		]
+	}`)
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+
+	_, err := Load(filepath.Join(t.TempDir(), "unused.json"))
+	if err == nil {
+		t.Fatal("expected invalid fallback_check error")
+	}
+	if !strings.Contains(err.Error(), "target b: fallback_check.port") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{
+		"bot":{"token":"x","chat_id":1},
+		"dashboard":{"enabled":false},
+		"targets":[
+			{"name":"a","address":"10.0.0.1","port":22,"fallback_check":{"type":"http","url":"https://a.example/health","healthy_regex":"(ok"}}
+		]
+	}`)
+	if _, err := Load(filepath.Join(t.TempDir(), "unused.json")); err == nil || !strings.Contains(err.Error(), "target a: invalid fallback_check.healthy_regex") {
+		t.Fatalf("expected invalid regex error, got %v", err)
+	}
+}
+
+func TestLoadValidatesAlertGates(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+	for gate, want := range map[string]string{
+		"api":     "alert_gate must name another target",
+		"missing": "alert_gate missing is not a configured target",
+		"db":      "alert_gate db has an alert_gate itself",
+	} {
+		t.Setenv("TRACKWAY_CONFIG_JSON", `{
+			"bot":{"token":"x","chat_id":1},
+			"targets":[
+				{"name":"gw","address":"10.0.0.1","port":53},
+				{"name":"db","address":"10.0.0.2","port":5432,"alert_gate":"gw"},
+				{"name":"api","address":"10.0.0.3","port":443,"alert_gate":"`+gate+`"}
+			]
+		}`)
+		if _, err := Load(filepath.Join(t.TempDir(), "unused.json")); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("alert_gate %q: expected %q, got %v", gate, want, err)
+		}
+	}
+}
+
+func TestLoadValidatesCheckType(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+	for options, want := range map[string]string{
+		`"type":"quic"`:                       "unsupported type: quic",
+		`"type":"http","path":"healthz"`:      "path must start with /",
+		`"type":"https","expect_status":9`:    "expect_status must be between 100 and 599",
+		`"path":"/healthz"`:                   "need type http or https",
+		`"unhealthy_regex":"DEGRADED"`:        "need type http or https",
+		`"type":"http","healthy_regex":"(ok"`: "invalid healthy_regex",
+		`"type":"HTTP","path":"/healthz"`:     "",
+	} {
+		t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"targets":[{"name":"api","address":"10.0.0.3","port":443,`+options+`}]}`)
+		cfg, err := Load(filepath.Join(t.TempDir(), "unused.json"))
+		if want == "" {
+			if err != nil || cfg.Targets[0].Type != "http" {
+				t.Fatalf("%s: expected normalized http target, got %+v, %v", options, cfg.Targets, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: expected %q, got %v", options, want, err)
+		}
+	}
+}
+
+func TestLoadValidatesUDPChecks(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+	for options, want := range map[string]string{
+		`"udp_expect":"pong"`: "need type udp",
+		`"type":"udp","udp_payload":"a","udp_payload_hex":"62"`:   "set only one of udp_payload and udp_payload_hex",
+		`"type":"udp","udp_payload_hex":"zz"`:                     "udp_payload_hex",
+		`"type":"udp","udp_no_response":"maybe"`:                  "unsupported udp_no_response: maybe",
+		`"type":"udp","watch_cert":true`:                          "not supported for type udp",
+		`"type":"udp","udp_payload_hex":"0a0b","udp_expect":"ok"`: "",
+	} {
+		t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"targets":[{"name":"dns","address":"10.0.0.3","port":53,`+options+`}]}`)
+		cfg, err := Load(filepath.Join(t.TempDir(), "unused.json"))
+		if want == "" {
+			if err != nil || cfg.Targets[0].UDPNoResponse != "down" {
+				t.Fatalf("%s: expected udp target defaulting to down, got %+v, %v", options, cfg.Targets, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: expected %q, got %v", options, want, err)
+		}
+	}
+}
+
+func TestLoadAllowsPingTargetsWithoutPort(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"targets":[{"name":"nas","address":"10.0.0.9","type":"ping"}]}`)
+	cfg, err := Load(filepath.Join(t.TempDir(), "unused.json"))
+	if err != nil || cfg.Targets[0].Port != 0 {
+		t.Fatalf("expected ping target without port, got %+v, %v", cfg.Targets, err)
+	}
+
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"targets":[{"name":"nas","address":"10.0.0.9"}]}`)
+	if _, err := Load(filepath.Join(t.TempDir(), "unused.json")); err == nil || !strings.Contains(err.Error(), "port > 0") {
+		t.Fatalf("expected tcp target without port to be rejected, got %v", err)
+	}
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"targets":[{"name":"nas","address":"10.0.0.9","type":"ping","watch_cert":true}]}`)
+	if _, err := Load(filepath.Join(t.TempDir(), "unused.json")); err == nil || !strings.Contains(err.Error(), "watch_cert") {
+		t.Fatalf("expected watch_cert on ping target to be rejected, got %v", err)
+	}
+}
+
+func TestLoadValidatesMaintenanceWindows(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+	for window, want := range map[string]string{
+		`{"start":"2026-10-20T22:00:00Z"}`:                                              "end must be RFC3339",
+		`{"start":"2026-10-20T22:00:00Z","end":"2026-10-20T21:00:00Z"}`:                 "end must be after start",
+		`{"schedule":"0 2 * * *"}`:                                                      "duration_minutes must be between 1 and 1440",
+		`{"schedule":"0 2 * * *","duration_minutes":30,"start":"2026-10-20T22:00:00Z"}`: "not both",
+		`{"name":"x"}`: "start/end or schedule is required",
+		`{"schedule":"0 2 * * *","duration_minutes":30,"targets":["missing"]}`: "missing is not a configured target",
+		`{"schedule":"0 2 * * *","duration_minutes":30,"targets":["API"]}`:     "",
+	} {
+		t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"targets":[{"name":"api","address":"10.0.0.3","port":443}],"maintenance":[`+window+`]}`)
+		cfg, err := Load(filepath.Join(t.TempDir(), "unused.json"))
+		if want == "" {
+			if err != nil || cfg.Maintenance[0].Targets[0] != "api" || cfg.Maintenance[0].Name != "window-1" {
+				t.Fatalf("%s: expected normalized window, got %+v, %v", window, cfg.Maintenance, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: expected %q, got %v", window, want, err)
+		}
+	}
+}
+
+func TestNormalizeBasePath(t *testing.T) {
+	t.Parallel()
+
+	for input, want := range map[string]string{"": "", "/": "", "trackway": "/trackway", "/trackway/": "/trackway", " /a/b/ ": "/a/b"} {
+		got, err := normalizeBasePath(input)
+		if err != nil || got != want {
+			t.Fatalf("normalizeBasePath(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := normalizeBasePath("/a/../b"); err == nil {
+		t.Fatal("expected traversal to be rejected")
+	}
+}
+
+func TestLoadValidatesOtel(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"otel":{"enabled":true}}`)
+	if _, err := Load(filepath.Join(t.TempDir(), "unused.json")); err == nil || !strings.Contains(err.Error(), "otel.endpoint") {
+		t.Fatalf("expected otel endpoint error, got %v", err)
+	}
+
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"otel":{"enabled":true,"endpoint":"http://collector:4318"}}`)
+	cfg, err := Load(filepath.Join(t.TempDir(), "unused.json"))
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Otel.ServiceName != "trackway" {
+		t.Fatalf("expected default service name, got %q", cfg.Otel.ServiceName)
+	}
+}
+
+func TestLoadValidatesGraphSize(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1}}`)
+	cfg, err := Load(filepath.Join(t.TempDir(), "unused.json"))
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Graph.Width != 720 || cfg.Graph.Height != 120 {
+		t.Fatalf("expected default graph size, got %+v", cfg.Graph)
+	}
+
+	for body, want := range map[string]string{
+		`{"width":3000}`:             "must be <= 2000",
+		`{"width":1000,"height":20}`: "must not differ",
+	} {
+		t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"graph":`+body+`}`)
+		if _, err := Load(filepath.Join(t.TempDir(), "unused.json")); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("graph %s: expected %q error, got %v", body, want, err)
+		}
+	}
+}
+
+func TestLoadValidatesSourceAddress(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"monitoring":{"source_address":"eth0"}}`)
+	if _, err := Load(filepath.Join(t.TempDir(), "unused.json")); err == nil || !strings.Contains(err.Error(), "monitoring.source_address") {
+		t.Fatalf("expected source address error, got %v", err)
+	}
+
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"monitoring":{"source_address":" 10.0.0.5 "}}`)
+	cfg, err := Load(filepath.Join(t.TempDir(), "unused.json"))
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Monitoring.SourceAddress != "10.0.0.5" {
+		t.Fatalf("expected trimmed source address, got %q", cfg.Monitoring.SourceAddress)
+	}
+}
+
+func TestLoadRequiresBothBasicAuthFields(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"dashboard":{"basic_auth_user":"ci"}}`)
+	if _, err := Load(filepath.Join(t.TempDir(), "unused.json")); err == nil || !strings.Contains(err.Error(), "basic_auth_password") {
+		t.Fatalf("expected basic auth pairing error, got %v", err)
+	}
+}
+
+func TestLoadNormalizesTargetTags(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"targets":[{"name":"api","address":"10.0.0.3","port":443,"tags":[" Prod ","db","PROD"]}]}`)
+	cfg, err := Load(filepath.Join(t.TempDir(), "unused.json"))
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if got := strings.Join(cfg.Targets[0].Tags, ","); got != "prod,db" {
+		t.Fatalf("expected lowercased deduped tags, got %q", got)
+	}
+
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"targets":[{"name":"api","address":"10.0.0.3","port":443,"tags":["a,b"]}]}`)
+	if _, err := Load(filepath.Join(t.TempDir(), "unused.json")); err == nil || !strings.Contains(err.Error(), "invalid tag") {
+		t.Fatalf("expected invalid tag error, got %v", err)
+	}
+}
+
+func TestLoadValidatesAlertCooldown(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"monitoring":{"alert_cooldown_seconds":-1}}`)
+	if _, err := Load(filepath.Join(t.TempDir(), "unused.json")); err == nil || !strings.Contains(err.Error(), "monitoring.alert_cooldown_seconds") {
+		t.Fatalf("expected alert cooldown error, got %v", err)
+	}
+}
+
+func TestLoadValidatesQuietHours(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+	for quiet, want := range map[string]string{
+		`{"start":"22:00"}`:                                   "quiet_hours.end",
+		`{"start":"25:00","end":"07:00"}`:                     "quiet_hours.start",
+		`{"start":"22:00","end":"22:00"}`:                     "must differ",
+		`{"start":"22:00","end":"07:00","timezone":"Mars/X"}`: "quiet_hours.timezone",
+		`{"start":"22:00","end":"07:00"}`:                     "",
+	} {
+		t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"quiet_hours":`+quiet+`}`)
+		cfg, err := Load(filepath.Join(t.TempDir(), "unused.json"))
+		if want == "" {
+			if err != nil || cfg.QuietHours.Timezone != "UTC" {
+				t.Fatalf("%s: expected UTC default, got %+v, %v", quiet, cfg.QuietHours, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: expected %q, got %v", quiet, want, err)
+		}
+	}
+}
+
+func TestLoadValidatesProbeRetries(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"monitoring":{"probe_retries":6}}`)
+	if _, err := Load(filepath.Join(t.TempDir(), "unused.json")); err == nil || !strings.Contains(err.Error(), "monitoring.probe_retries") {
+		t.Fatalf("expected probe retries error, got %v", err)
+	}
+}
+
+func TestLoadValidatesTargetProbes(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+	for options, want := range map[string]string{
+		`"probes":21`:                         "probes must be between 0 and 20",
+		`"probes":-1`:                         "probes must be between 0 and 20",
+		`"probes":3,"min_healthy_probes":4`:   "min_healthy_probes must be between 0 and probes",
+		`"probes":20,"min_healthy_probes":10`: "",
+	} {
+		t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"targets":[{"name":"api","address":"10.0.0.3","port":443,`+options+`}]}`)
+		_, err := Load(filepath.Join(t.TempDir(), "unused.json"))
+		if want == "" {
+			if err != nil {
+				t.Fatalf("%s: expected a valid target, got %v", options, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: expected %q, got %v", options, want, err)
+		}
+	}
+}
+
+func TestLoadTargetsFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "targets.json")
+	if err := os.WriteFile(jsonPath, []byte(`[{"name":" db ","address":"10.0.0.5","port":5432,"tags":["Prod"]}]`), 0o600); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+	targets, err := LoadTargetsFile(jsonPath, false)
+	if err != nil || len(targets) != 1 || targets[0].Name != "db" || targets[0].Tags[0] != "prod" {
+		t.Fatalf("expected normalized json targets, got %+v, %v", targets, err)
+	}
+
+	yamlPath := filepath.Join(dir, "targets.yaml")
+	yaml := "targets:\n  - name: \"web\"\n    address: \"10.0.0.6\"\n    port: 443\n  - name: \"ssh\"\n    address: \"10.0.0.6\"\n    port: 22\n"
+	if err := os.WriteFile(yamlPath, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("write yaml: %v", err)
+	}
+	targets, err = LoadTargetsFile(yamlPath, false)
+	if err != nil || len(targets) != 2 || targets[1].Name != "ssh" || targets[1].Port != 22 {
+		t.Fatalf("expected yaml targets, got %+v, %v", targets, err)
+	}
+
+	for name, body := range map[string]string{
+		"bad.json":  `{"targets":[{"name":"db","address":"10.0.0.5"}]}`,
+		"none.json": `{"items":[]}`,
+		"bad.yaml":  "targets:\n  - name: web\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		if _, err := LoadTargetsFile(path, false); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoadValidatesTargetSource(t *testing.T) {
+	t.Setenv("TRACKWAY_CONFIG_JSON_B64", "")
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"target_source":{"source":"file"}}`)
+	if _, err := Load(filepath.Join(t.TempDir(), "unused.json")); err == nil || !strings.Contains(err.Error(), "target_source.path") {
+		t.Fatalf("expected target source path error, got %v", err)
+	}
+	t.Setenv("TRACKWAY_CONFIG_JSON", `{"bot":{"token":"x","chat_id":1},"target_source":{"source":"FILE","path":"targets.json"}}`)
+	cfg, err := Load(filepath.Join(t.TempDir(), "unused.json"))
+	if err != nil || cfg.TargetSource.Source != "file" || cfg.TargetSource.ReloadIntervalSeconds != 60 {
+		t.Fatalf("expected file source with the default interval, got %+v, %v", cfg.TargetSource, err)
+	}
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func validateTargetSource(source *TargetSource) error {
+	source.Source = strings.ToLower(strings.TrimSpace(source.Source))
+	source.Path = strings.TrimSpace(source.Path)
+	switch source.Source {
+	case "":
+		return nil
+	case "file":
+	default:
+		return fmt.Errorf("unsupported target_source.source: %s", source.Source)
+	}
+	if source.Path == "" {
+		return errors.New("target_source.path is required when target_source.source is file")
+	}
+	if source.ReloadIntervalSeconds < 0 || source.ReloadIntervalSeconds > maxIntervalSeconds {
+		return fmt.Errorf("target_source.reload_interval_seconds must be between 0 and %d", maxIntervalSeconds)
+	}
+	if source.ReloadIntervalSeconds == 0 {
+		source.ReloadIntervalSeconds = defaultTargetsReloadSec
+	}
+	return nil
+}
+
+// LoadTargetsFile reads and validates the targets of a target source file.
+// JSON files hold {"targets": [...]} or a bare array; .yaml and .yml files
+// use the layout written by the targets export, one "key: value" per line
+// with JSON-encoded values.
+func LoadTargetsFile(path string, rejectDuplicates bool) ([]Target, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var targets []Target
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		targets, err = decodeTargetsYAML(data)
+	default:
+		targets, err = decodeTargetsJSON(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := normalizeTargets(targets, rejectDuplicates); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return targets, nil
+}
+
+func decodeTargetsJSON(data []byte) ([]Target, error) {
+	payload := strings.TrimSpace(string(data))
+	if strings.HasPrefix(payload, "[") {
+		var targets []Target
+		if err := json.Unmarshal([]byte(payload), &targets); err != nil {
+			return nil, err
+		}
+		return targets, nil
+	}
+	var wrapped struct {
+		Targets *[]Target `json:"targets"`
+	}
+	if err := json.Unmarshal([]byte(payload), &wrapped); err != nil {
+		return nil, err
+	}
+	if wrapped.Targets == nil {
+		return nil, errors.New(`missing "targets" list`)
+	}
+	return *wrapped.Targets, nil
+}
+
+// decodeTargetsYAML turns the export YAML layout back into JSON objects.
+func decodeTargetsYAML(data []byte) ([]Target, error) {
+	var items []map[string]json.RawMessage
+	seenRoot := false
+	for n, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if !seenRoot {
+			if trimmed == "targets: []" {
+				return []Target{}, nil
+			}
+			if trimmed != "targets:" {
+				return nil, fmt.Errorf("line %d: expected \"targets:\"", n+1)
+			}
+			seenRoot = true
+			continue
+		}
+		entry, isItem := strings.CutPrefix(trimmed, "- ")
+		if isItem {
+			items = append(items, map[string]json.RawMessage{})
+		} else if len(items) == 0 {
+			return nil, fmt.Errorf("line %d: expected a \"- \" list item", n+1)
+		}
+		key, value, found := strings.Cut(entry, ":")
+		value = strings.TrimSpace(value)
+		if !found || strings.TrimSpace(key) == "" || !json.Valid([]byte(value)) {
+			return nil, fmt.Errorf("line %d: expected key: <JSON value>", n+1)
+		}
+		items[len(items)-1][strings.TrimSpace(key)] = json.RawMessage(value)
+	}
+	if !seenRoot {
+		return nil, errors.New(`missing "targets" list`)
+	}
+	encoded, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	targets := []Target{}
+	if err := json.Unmarshal(encoded, &targets); err != nil {
+		return nil, err
+	}
+	return targets, nil
+}
@@ -3,6 +3,7 @@ package dashboard
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"sort"
 	"sync"
 	"time"
 )
@@ -89,6 +90,33 @@ func (m *authManager) RevokeSession(sessionID string) {
 	m.mu.Unlock()
 }
 
+// SessionExpiries returns the expiry of every active session, soonest first.
+func (m *authManager) SessionExpiries(now time.Time) []time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cleanup(now)
+
+	expiries := make([]time.Time, 0, len(m.sessions))
+	for _, startedAt := range m.sessions {
+		expiries = append(expiries, startedAt.Add(m.sessionTTL))
+	}
+	sort.Slice(expiries, func(i, j int) bool { return expiries[i].Before(expiries[j]) })
+	return expiries
+}
+
+// RevokeAllSessions drops every session, the caller's included, and returns
+// how many were active. Unused login tokens are dropped as well.
+func (m *authManager) RevokeAllSessions(now time.Time) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cleanup(now)
+
+	revoked := len(m.sessions)
+	m.sessions = make(map[string]time.Time)
+	m.tokens = make(map[string]time.Time)
+	return revoked
+}
+
 func (m *authManager) cleanup(now time.Time) {
 	for token, expiresAt := range m.tokens {
 		if now.After(expiresAt) {
@@ -0,0 +1,73 @@
+package dashboard
+
+import (
+	"fmt"
+	"net/http"
+
+	"trackway/internal/tracker"
+)
+
+const (
+	badgeGreen = "#4c1"
+	badgeAmber = "#dfb317"
+	badgeRed   = "#e05d44"
+	badgeGrey  = "#9f9f9f"
+	badgeLabel = "status"
+)
+
+// handleBadge renders an embeddable "X/Y up" SVG. It is public when enabled,
+// so it exposes counts only, never target names.
+func (s *Server) handleBadge(w http.ResponseWriter, r *http.Request) {
+	if !s.publicBadge {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	_, _ = fmt.Fprint(w, renderBadge(s.provider.Snapshot()))
+}
+
+// badgeColor is green when every target is UP, red when any is DOWN and amber
+// while some are still UNKNOWN.
+func badgeColor(snapshot tracker.Snapshot) string {
+	switch {
+	case snapshot.Total == 0:
+		return badgeGrey
+	case snapshot.Down > 0:
+		return badgeRed
+	case snapshot.Up < snapshot.Total:
+		return badgeAmber
+	default:
+		return badgeGreen
+	}
+}
+
+func renderBadge(snapshot tracker.Snapshot) string {
+	value := fmt.Sprintf("%d/%d up", snapshot.Up, snapshot.Total)
+	if snapshot.Total == 0 {
+		value = "no targets"
+	}
+	// Verdana 11px averages about 7px per character.
+	labelWidth := 7*len(badgeLabel) + 10
+	valueWidth := 7*len(value) + 10
+	width := labelWidth + valueWidth
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">`+
+		`<title>%[4]s: %[5]s</title>`+
+		`<rect width="%[2]d" height="20" fill="#555"/>`+
+		`<rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%[7]d" y="14">%[4]s</text><text x="%[8]d" y="14">%[5]s</text></g></svg>`,
+		width,
+		labelWidth,
+		valueWidth,
+		badgeLabel,
+		value,
+		badgeColor(snapshot),
+		labelWidth/2,
+		labelWidth+valueWidth/2,
+	)
+}
@@ -0,0 +1,73 @@
+package dashboard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"trackway/internal/config"
+	"trackway/internal/tracker"
+)
+
+type badgeProvider struct {
+	stubProvider
+	snapshot tracker.Snapshot
+}
+
+func (p badgeProvider) Snapshot() tracker.Snapshot {
+	return p.snapshot
+}
+
+func TestBadgeIsPublicOnlyWhenEnabled(t *testing.T) {
+	t.Parallel()
+
+	provider := badgeProvider{snapshot: tracker.Snapshot{Total: 3, Up: 2, Down: 1}}
+	get := func(enabled bool) *httptest.ResponseRecorder {
+		srv, err := New(config.Dashboard{
+			ListenAddress: ":0",
+			PublicURL:     "http://127.0.0.1:8080",
+			PublicBadge:   enabled,
+		}, "test-bot-token", provider)
+		if err != nil {
+			t.Fatalf("new server: %v", err)
+		}
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/badge.svg", nil))
+		return rec
+	}
+
+	if rec := get(false); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 when disabled, got %d", rec.Code)
+	}
+	rec := get(true)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/svg+xml; charset=utf-8" {
+		t.Fatalf("unexpected badge response: %d %v", rec.Code, rec.Header())
+	}
+	if rec.Header().Get("Cache-Control") != "public, max-age=60" {
+		t.Fatalf("unexpected cache control %q", rec.Header().Get("Cache-Control"))
+	}
+	body := rec.Body.String()
+	if !strings.HasPrefix(body, "<svg") || !strings.Contains(body, ">2/3 up<") || !strings.Contains(body, badgeRed) {
+		t.Fatalf("unexpected badge body: %s", body)
+	}
+}
+
+func TestBadgeColor(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		snapshot tracker.Snapshot
+		want     string
+	}{
+		{tracker.Snapshot{}, badgeGrey},
+		{tracker.Snapshot{Total: 2, Up: 2}, badgeGreen},
+		{tracker.Snapshot{Total: 2, Up: 1, Unknown: 1}, badgeAmber},
+		{tracker.Snapshot{Total: 2, Up: 1, Down: 1}, badgeRed},
+	}
+	for _, tc := range cases {
+		if got := badgeColor(tc.snapshot); got != tc.want {
+			t.Fatalf("badgeColor(%+v) = %s, want %s", tc.snapshot, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package dashboard
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	corsAllowMethods  = "GET, POST, DELETE, OPTIONS"
+	corsAllowHeaders  = "Authorization, Content-Type, X-Request-ID"
+	corsExposeHeaders = "X-Request-ID, X-Session-Expires-At, Retry-After"
+	corsMaxAgeSeconds = "600"
+)
+
+// corsOrigins holds the origins allowed to call /api/* from another site.
+type corsOrigins map[string]struct{}
+
+func newCORSOrigins(values []string) (corsOrigins, error) {
+	out := make(corsOrigins, len(values))
+	for _, value := range values {
+		origin, ok := normalizeOrigin(value)
+		if !ok {
+			return nil, fmt.Errorf("dashboard.allowed_origins: invalid origin %q", value)
+		}
+		out[origin] = struct{}{}
+	}
+	return out, nil
+}
+
+// normalizeOrigin reduces an origin to lowercase scheme://host[:port].
+func normalizeOrigin(raw string) (string, bool) {
+	parsed, err := url.Parse(strings.TrimRight(strings.TrimSpace(raw), "/"))
+	if err != nil || parsed.Host == "" || parsed.Path != "" || parsed.RawQuery != "" || parsed.User != nil {
+		return "", false
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return "", false
+	}
+	return scheme + "://" + strings.ToLower(parsed.Host), true
+}
+
+func (o corsOrigins) allowed(origin string) bool {
+	if len(o) == 0 {
+		return false
+	}
+	normalized, ok := normalizeOrigin(origin)
+	if !ok {
+		return false
+	}
+	_, found := o[normalized]
+	return found
+}
+
+// withCORS answers preflights and adds CORS headers to /api/* responses for
+// allowed origins. The origin is echoed rather than "*" so credentialed
+// requests keep their session cookie.
+func (s *Server) withCORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if !strings.HasPrefix(r.URL.Path, "/api/") || origin == "" || !s.corsOrigins.allowed(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		headers := w.Header()
+		headers.Add("Vary", "Origin")
+		headers.Set("Access-Control-Allow-Origin", origin)
+		headers.Set("Access-Control-Allow-Credentials", "true")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			headers.Add("Vary", "Access-Control-Request-Method")
+			headers.Add("Vary", "Access-Control-Request-Headers")
+			headers.Set("Access-Control-Allow-Methods", corsAllowMethods)
+			headers.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+			headers.Set("Access-Control-Max-Age", corsMaxAgeSeconds)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		headers.Set("Access-Control-Expose-Headers", corsExposeHeaders)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// cookieSameSite relaxes the session cookie to SameSite=None when a separate
+// frontend origin is allowed, so its credentialed fetches carry the session.
+// Browsers only accept that on Secure cookies.
+func (s *Server) cookieSameSite() http.SameSite {
+	if len(s.corsOrigins) > 0 && s.secureCookie {
+		return http.SameSiteNoneMode
+	}
+	return http.SameSiteLaxMode
+}
@@ -0,0 +1,88 @@
+package dashboard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"trackway/internal/config"
+)
+
+func TestCORSAllowsConfiguredOrigin(t *testing.T) {
+	t.Parallel()
+
+	provider := &mutableProvider{}
+	srv, err := New(config.Dashboard{
+		ListenAddress:  ":0",
+		PublicURL:      "https://api.example.com",
+		SecureCookie:   true,
+		AllowedOrigins: []string{"https://App.example.com/"},
+	}, "test-bot-token", provider)
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	preflight := httptest.NewRequest(http.MethodOptions, "/api/targets", nil)
+	preflight.Header.Set("Origin", "https://app.example.com")
+	preflight.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(rec, preflight)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected preflight 204, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Fatalf("expected echoed origin, got %q", got)
+	}
+	if rec.Header().Get("Access-Control-Allow-Credentials") != "true" || !strings.Contains(rec.Header().Get("Access-Control-Allow-Methods"), "POST") {
+		t.Fatalf("unexpected preflight headers: %v", rec.Header())
+	}
+
+	sessionID, err := srv.auth.CreateSession(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	post := httptest.NewRequest(http.MethodPost, "/api/targets", strings.NewReader(`{"name":"new-api","address":"100.64.0.10","port":443}`))
+	post.Header.Set("Origin", "https://app.example.com")
+	post.Header.Set("Content-Type", "application/json")
+	post.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+	rec = httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(rec, post)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected allowed origin mutation 201, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Fatalf("expected echoed origin on response, got %q", got)
+	}
+
+	other := httptest.NewRequest(http.MethodPost, "/api/targets", strings.NewReader(`{"name":"x","address":"100.64.0.10","port":443}`))
+	other.Header.Set("Origin", "https://evil.example")
+	other.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+	rec = httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(rec, other)
+	if rec.Code != http.StatusForbidden || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("expected other origin rejected without CORS headers, got %d %v", rec.Code, rec.Header())
+	}
+
+	cookieRec := httptest.NewRecorder()
+	srv.setSessionCookie(cookieRec, "id")
+	if setCookie := cookieRec.Header().Get("Set-Cookie"); !strings.Contains(setCookie, "SameSite=None") || !strings.Contains(setCookie, "Secure") {
+		t.Fatalf("expected SameSite=None secure cookie, got %q", setCookie)
+	}
+}
+
+func TestNewRejectsInvalidAllowedOrigin(t *testing.T) {
+	t.Parallel()
+
+	for _, origin := range []string{"app.example.com", "https://app.example.com/path", "ftp://app.example.com"} {
+		_, err := New(config.Dashboard{
+			ListenAddress:  ":0",
+			PublicURL:      "https://api.example.com",
+			AllowedOrigins: []string{origin},
+		}, "test-bot-token", stubProvider{})
+		if err == nil {
+			t.Fatalf("expected %q to be rejected", origin)
+		}
+	}
+}
@@ -70,11 +70,11 @@ PERFORMANCE OF THIS SOFTWARE.
 
 `:"<br/>",y=h.join(g);this._showOrMove(s,function(){this._updateContentNotChangedOnAxis(t,u)?this._updatePosition(s,c,o[0],o[1],this._tooltipContent,u):this._showTooltipContent(s,y,u,Math.random()+"",o[0],o[1],c,null,v)})},e.prototype._showSeriesItemTooltip=function(t,a,n){var i=this._ecModel,o=nt(a),s=o.seriesIndex,l=i.getSeriesByIndex(s),u=o.dataModel||l,f=o.dataIndex,h=o.dataType,v=u.getData(h),c=this._renderMode,p=t.positionDefault,d=is([v.getItemModel(f),u,l&&(l.coordinateSystem||{}).model],this._tooltipModel,p?{position:p}:null),g=d.get("trigger");if(!(g!=null&&g!=="item")){var y=u.getDataParams(f,h),m=new Nv;y.marker=m.makeTooltipMarker("item",ui(y.color),c);var _=Q0(u.formatTooltip(f,!1,h)),S=d.get("order"),b=d.get("valueFormatter"),x=_.frag,w=x?n_(b?V({valueFormatter:b},x):x,m,c,S,i.get("useUTC"),d.get("textStyle")):_.text,T="item_"+u.name+"_"+f;this._showOrMove(d,function(){this._showTooltipContent(d,w,y,T,t.offsetX,t.offsetY,t.position,t.target,m)}),n({type:"showTip",dataIndexInside:f,dataIndex:v.getRawIndex(f),seriesIndex:s,from:this.uid})}},e.prototype._showComponentItemTooltip=function(t,a,n){var i=this._renderMode==="html",o=nt(a),s=o.tooltipConfig,l=s.option||{},u=l.encodeHTMLContent;if(Y(l)){var f=l;l={content:f,formatter:f},u=!0}u&&i&&l.content&&(l=rt(l),l.content=Ce(l.content));var h=[l],v=this._ecModel.getComponent(o.componentMainType,o.componentIndex);v&&h.push(v),h.push({formatter:l.content});var c=t.positionDefault,p=is(h,this._tooltipModel,c?{position:c}:null),d=p.get("content"),g=Math.random()+"",y=new Nv;this._showOrMove(p,function(){var m=rt(p.get("formatterParams")||{});this._showTooltipContent(p,d,m,g,t.offsetX,t.offsetY,t.position,a,y)}),n({type:"showTip",from:this.uid})},e.prototype._showTooltipContent=function(t,a,n,i,o,s,l,u,f){if(this._ticket="",!(!t.get("showContent")||!t.get("show"))){var h=this._tooltipContent;h.setEnterable(t.get("enterable"));var v=t.get("formatter");l=l||t.get("position");var c=a,p=this._getNearestPoint([o,s],n,t.get("trigger"),t.get("borderColor")),d=p.color;if(v)if(Y(v)){var g=t.ecModel.get("useUTC"),y=z(n)?n[0]:n,m=y&&y.axisType&&y.axisType.indexOf("time")>=0;c=v,m&&(c=_h(y.axisValue,c,g)),c=uA(c,n,!0)}else if(J(v)){var _=$(function(S,b){S===this._ticket&&(h.setContent(b,f,t,d,l),this._updatePosition(t,l,o,s,h,n,u))},this);this._ticket=i,c=v(n,i,_)}else c=v;h.setContent(c,f,t,d,l),h.show(t,d),this._updatePosition(t,l,o,s,h,n,u)}},e.prototype._getNearestPoint=function(t,a,n,i){if(n==="axis"||z(a))return{color:i||(this._renderMode==="html"?"#fff":"none")};if(!z(a))return{color:i||a.color||a.borderColor}},e.prototype._updatePosition=function(t,a,n,i,o,s,l){var u=this._api.getWidth(),f=this._api.getHeight();a=a||t.get("position");var h=o.getSize(),v=t.get("align"),c=t.get("verticalAlign"),p=l&&l.getBoundingRect().clone();if(l&&p.applyTransform(l.transform),J(a)&&(a=a([n,i],s,o.el,p,{viewSize:[u,f],contentSize:h.slice()})),z(a))n=W(a[0],u),i=W(a[1],f);else if(et(a)){var d=a;d.width=h[0],d.height=h[1];var g=ee(d,{width:u,height:f});n=g.x,i=g.y,v=null,c=null}else if(Y(a)&&l){var y=yU(a,p,h,t.get("borderWidth"));n=y[0],i=y[1]}else{var y=dU(n,i,o,u,f,v?null:20,c?null:20);n=y[0],i=y[1]}if(v&&(n-=db(v)?h[0]/2:v==="right"?h[0]:0),c&&(i-=db(c)?h[1]/2:c==="bottom"?h[1]:0),DI(t)){var y=gU(n,i,o,u,f);n=y[0],i=y[1]}o.moveTo(n,i)},e.prototype._updateContentNotChangedOnAxis=function(t,a){var n=this._lastDataByCoordSys,i=this._cbParamsList,o=!!n&&n.length===t.length;return o&&D(n,function(s,l){var u=s.dataByAxis||[],f=t[l]||{},h=f.dataByAxis||[];o=o&&u.length===h.length,o&&D(u,function(v,c){var p=h[c]||{},d=v.seriesDataIndices||[],g=p.seriesDataIndices||[];o=o&&v.value===p.value&&v.axisType===p.axisType&&v.axisId===p.axisId&&d.length===g.length,o&&D(d,function(y,m){var _=g[m];o=o&&y.seriesIndex===_.seriesIndex&&y.dataIndex===_.dataIndex}),i&&D(v.seriesDataIndices,function(y){var m=y.seriesIndex,_=a[m],S=i[m];_&&S&&S.data!==_.data&&(o=!1)})})}),this._lastDataByCoordSys=t,this._cbParamsList=a,!!o},e.prototype._hide=function(t){this._lastDataByCoordSys=null,t({type:"hideTip",from:this.uid})},e.prototype.dispose=function(t,a){mt.node||!a.getDom()||(Js(this,"_updatePosition"),this._tooltipContent.dispose(),Gd("itemTooltip",a))},e.type="tooltip",e})(Ut);function is(r,e,t){var a=e.ecModel,n;t?(n=new Lt(t,a,a),n=new Lt(e.option,n,a)):n=e;for(var i=r.length-1;i>=0;i--){var o=r[i];o&&(o instanceof Lt&&(o=o.get("tooltip",!0)),Y(o)&&(o={formatter:o}),o&&(n=new Lt(o,n,a)))}return n}function pb(r,e){return r.dispatchAction||$(e.dispatchAction,e)}function dU(r,e,t,a,n,i,o){var s=t.getSize(),l=s[0],u=s[1];return i!=null&&(r+l+i+2>a?r-=l+i:r+=i),o!=null&&(e+u+o>n?e-=u+o:e+=o),[r,e]}function gU(r,e,t,a,n){var i=t.getSize(),o=i[0],s=i[1];return r=Math.min(r+o,a)-o,e=Math.min(e+s,n)-s,r=Math.max(r,0),e=Math.max(e,0),[r,e]}function yU(r,e,t,a){var n=t[0],i=t[1],o=Math.ceil(Math.SQRT2*a)+8,s=0,l=0,u=e.width,f=e.height;switch(r){case"inside":s=e.x+u/2-n/2,l=e.y+f/2-i/2;break;case"top":s=e.x+u/2-n/2,l=e.y-i-o;break;case"bottom":s=e.x+u/2-n/2,l=e.y+f+o;break;case"left":s=e.x-n-o,l=e.y+f/2-i/2;break;case"right":s=e.x+u+o,l=e.y+f/2-i/2}return[s,l]}function db(r){return r==="center"||r==="middle"}function mU(r,e,t){var a=_g(r).queryOptionMap,n=a.keys()[0];if(!(!n||n==="series")){var i=gl(e,n,a.get(n),{useDefault:!1,enableAll:!1,enableNone:!1}),o=i.models[0];if(o){var s=t.getViewOfComponentModel(o),l;if(s.group.traverse(function(u){var f=nt(u).tooltipConfig;if(f&&f.name===r.name)return l=u,!0}),l)return{componentMainType:n,componentIndex:o.componentIndex,el:l}}}}function _U(r){gt(Ol),r.registerComponentModel(eU),r.registerComponentView(pU),r.registerAction({type:"showTip",event:"showTip",update:"tooltip:manuallyShowTip"},qt),r.registerAction({type:"hideTip",event:"hideTip",update:"tooltip:manuallyHideTip"},qt)}var SU=["rect","polygon","keep","clear"];function xU(r,e){var t=Et(r?r.brush:[]);if(t.length){var a=[];D(t,function(l){var u=l.hasOwnProperty("toolbox")?l.toolbox:[];u instanceof Array&&(a=a.concat(u))});var n=r&&r.toolbox;z(n)&&(n=n[0]),n||(n={feature:{}},r.toolbox=[n]);var i=n.feature||(n.feature={}),o=i.brush||(i.brush={}),s=o.type||(o.type=[]);s.push.apply(s,a),bU(s),e&&!s.length&&s.push.apply(s,SU)}}function bU(r){var e={};D(r,function(t){e[t]=1}),r.length=0,D(e,function(t,a){r.push(a)})}var gb=D;function yb(r){if(r){for(var e in r)if(r.hasOwnProperty(e))return!0}}function $d(r,e,t){var a={};return gb(e,function(i){var o=a[i]=n();gb(r[i],function(s,l){if(oe.isValidType(l)){var u={type:l,visual:s};t&&t(u,i),o[l]=new oe(u),l==="opacity"&&(u=rt(u),u.type="colorAlpha",o.__hidden.__alphaForOpacity=new oe(u))}})}),a;function n(){var i=function(){};i.prototype.__hidden=i.prototype;var o=new i;return o}}function PI(r,e,t){var a;D(t,function(n){e.hasOwnProperty(n)&&yb(e[n])&&(a=!0)}),a&&D(t,function(n){e.hasOwnProperty(n)&&yb(e[n])?r[n]=rt(e[n]):delete r[n]})}function wU(r,e,t,a,n,i){var o={};D(r,function(h){var v=oe.prepareVisualTypes(e[h]);o[h]=v});var s;function l(h){return Zg(t,s,h)}function u(h,v){QA(t,s,h,v)}t.each(f);function f(h,v){s=h;var c=t.getRawDataItem(s);if(!(c&&c.visualMap===!1))for(var p=a.call(n,h),d=e[p],g=o[p],y=0,m=g.length;y<m;y++){var _=g[y];d[_]&&d[_].applyVisual(h,l,u)}}}function TU(r,e,t,a){var n={};return D(r,function(i){var o=oe.prepareVisualTypes(e[i]);n[i]=o}),{progress:function(o,s){var l;a!=null&&(l=s.getDimensionIndex(a));function u(b){return Zg(s,h,b)}function f(b,x){QA(s,h,b,x)}for(var h,v=s.getStore();(h=o.next())!=null;){var c=s.getRawDataItem(h);if(!(c&&c.visualMap===!1))for(var p=a!=null?v.get(l,h):h,d=t(p),g=e[d],y=n[d],m=0,_=y.length;m<_;m++){var S=y[m];g[S]&&g[S].applyVisual(p,u,f)}}}}}function AU(r){var e=r.brushType,t={point:function(a){return mb[e].point(a,t,r)},rect:function(a){return mb[e].rect(a,t,r)}};return t}var mb={lineX:_b(0),lineY:_b(1),rect:{point:function(r,e,t){return r&&t.boundingRect.contain(r[0],r[1])},rect:function(r,e,t){return r&&t.boundingRect.intersect(r)}},polygon:{point:function(r,e,t){return r&&t.boundingRect.contain(r[0],r[1])&&Bn(t.range,r[0],r[1])},rect:function(r,e,t){var a=t.range;if(!r||a.length<=1)return!1;var n=r.x,i=r.y,o=r.width,s=r.height,l=a[0];if(Bn(a,n,i)||Bn(a,n+o,i)||Bn(a,n,i+s)||Bn(a,n+o,i+s)||vt.create(r).contain(l[0],l[1])||ps(n,i,n+o,i,a)||ps(n,i,n,i+s,a)||ps(n+o,i,n+o,i+s,a)||ps(n,i+s,n+o,i+s,a))return!0}}};function _b(r){var e=["x","y"],t=["width","height"];return{point:function(a,n,i){if(a){var o=i.range,s=a[r];return os(s,o)}},rect:function(a,n,i){if(a){var o=i.range,s=[a[e[r]],a[e[r]]+a[t[r]]];return s[1]<s[0]&&s.reverse(),os(s[0],o)||os(s[1],o)||os(o[0],s)||os(o[1],s)}}}}function os(r,e){return e[0]<=r&&r<=e[1]}var Sb=["inBrush","outOfBrush"],Qc="__ecBrushSelect",Xd="__ecInBrushSelectEvent";function RI(r){r.eachComponent({mainType:"brush"},function(e){var t=e.brushTargetManager=new am(e.option,r);t.setInputRanges(e.areas,r)})}function CU(r,e,t){var a=[],n,i;r.eachComponent({mainType:"brush"},function(o){t&&t.type==="takeGlobalCursor"&&o.setBrushOption(t.key==="brush"?t.brushOption:{brushType:!1})}),RI(r),r.eachComponent({mainType:"brush"},function(o,s){var l={brushId:o.id,brushIndex:s,brushName:o.name,areas:rt(o.areas),selected:[]};a.push(l);var u=o.option,f=u.brushLink,h=[],v=[],c=[],p=!1;s||(n=u.throttleType,i=u.throttleDelay);var d=G(o.areas,function(b){var x=LU[b.brushType],w=j({boundingRect:x?x(b):void 0},b);return w.selectors=AU(w),w}),g=$d(o.option,Sb,function(b){b.mappingMethod="fixed"});z(f)&&D(f,function(b){h[b]=1});function y(b){return f==="all"||!!h[b]}function m(b){return!!b.length}r.eachSeries(function(b,x){var w=c[x]=[];b.subType==="parallel"?_(b,x):S(b,x,w)});function _(b,x){var w=b.coordinateSystem;p=p||w.hasAxisBrushed(),y(x)&&w.eachActiveState(b.getData(),function(T,A){T==="active"&&(v[A]=1)})}function S(b,x,w){if(!(!b.brushSelector||IU(o,x))&&(D(d,function(A){o.brushTargetManager.controlSeries(A,b,r)&&w.push(A),p=p||m(w)}),y(x)&&m(w))){var T=b.getData();T.each(function(A){xb(b,w,T,A)&&(v[A]=1)})}}r.eachSeries(function(b,x){var w={seriesId:b.id,seriesIndex:x,seriesName:b.name,dataIndex:[]};l.selected.push(w);var T=c[x],A=b.getData(),C=y(x)?function(M){return v[M]?(w.dataIndex.push(A.getRawIndex(M)),"inBrush"):"outOfBrush"}:function(M){return xb(b,T,A,M)?(w.dataIndex.push(A.getRawIndex(M)),"inBrush"):"outOfBrush"};(y(x)?p:m(T))&&wU(Sb,g,A,C)})}),DU(e,n,i,a,t)}function DU(r,e,t,a,n){if(n){var i=r.getZr();if(!i[Xd]){i[Qc]||(i[Qc]=MU);var o=Po(i,Qc,t,e);o(r,a)}}}function MU(r,e){if(!r.isDisposed()){var t=r.getZr();t[Xd]=!0,r.dispatchAction({type:"brushSelect",batch:e}),t[Xd]=!1}}function xb(r,e,t,a){for(var n=0,i=e.length;n<i;n++){var o=e[n];if(r.brushSelector(a,t,o.selectors,o))return!0}}function IU(r,e){var t=r.option.seriesIndex;return t!=null&&t!=="all"&&(z(t)?pt(t,e)<0:e!==t)}var LU={rect:function(r){return bb(r.range)},polygon:function(r){for(var e,t=r.range,a=0,n=t.length;a<n;a++){e=e||[[1/0,-1/0],[1/0,-1/0]];var i=t[a];i[0]<e[0][0]&&(e[0][0]=i[0]),i[0]>e[0][1]&&(e[0][1]=i[0]),i[1]<e[1][0]&&(e[1][0]=i[1]),i[1]>e[1][1]&&(e[1][1]=i[1])}return e&&bb(e)}};function bb(r){return new vt(r[0][0],r[1][0],r[0][1]-r[0][0],r[1][1]-r[1][0])}var PU=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.prototype.init=function(t,a){this.ecModel=t,this.api=a,this.model,(this._brushController=new Ly(a.getZr())).on("brush",$(this._onBrush,this)).mount()},e.prototype.render=function(t,a,n,i){this.model=t,this._updateController(t,a,n,i)},e.prototype.updateTransform=function(t,a,n,i){RI(a),this._updateController(t,a,n,i)},e.prototype.updateVisual=function(t,a,n,i){this.updateTransform(t,a,n,i)},e.prototype.updateView=function(t,a,n,i){this._updateController(t,a,n,i)},e.prototype._updateController=function(t,a,n,i){(!i||i.$from!==t.id)&&this._brushController.setPanels(t.brushTargetManager.makePanelOpts(n)).enableBrush(t.brushOption).updateCovers(t.areas.slice())},e.prototype.dispose=function(){this._brushController.dispose()},e.prototype._onBrush=function(t){var a=this.model.id,n=this.model.brushTargetManager.setOutputRanges(t.areas,this.ecModel);(!t.isEnd||t.removeOnClick)&&this.api.dispatchAction({type:"brush",brushId:a,areas:rt(n),$from:a}),t.isEnd&&this.api.dispatchAction({type:"brushEnd",brushId:a,areas:rt(n),$from:a})},e.type="brush",e})(Ut),RU="#ddd",EU=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t.areas=[],t.brushOption={},t}return e.prototype.optionUpdated=function(t,a){var n=this.option;!a&&PI(n,t,["inBrush","outOfBrush"]);var i=n.inBrush=n.inBrush||{};n.outOfBrush=n.outOfBrush||{color:RU},i.hasOwnProperty("liftZ")||(i.liftZ=5)},e.prototype.setAreas=function(t){t&&(this.areas=G(t,function(a){return wb(this.option,a)},this))},e.prototype.setBrushOption=function(t){this.brushOption=wb(this.option,t),this.brushType=this.brushOption.brushType},e.type="brush",e.dependencies=["geo","grid","xAxis","yAxis","parallel","series"],e.defaultOption={seriesIndex:"all",brushType:"rect",brushMode:"single",transformable:!0,brushStyle:{borderWidth:1,color:"rgba(210,219,238,0.3)",borderColor:"#D2DBEE"},throttleType:"fixRate",throttleDelay:0,removeOnClick:!0,z:1e4},e})(_t);function wb(r,e){return ft({brushType:r.brushType,brushMode:r.brushMode,transformable:r.transformable,brushStyle:new Lt(r.brushStyle).getItemStyle(),removeOnClick:r.removeOnClick,z:r.z},e,!0)}var kU=["rect","polygon","lineX","lineY","keep","clear"],OU=(function(r){N(e,r);function e(){return r!==null&&r.apply(this,arguments)||this}return e.prototype.render=function(t,a,n){var i,o,s;a.eachComponent({mainType:"brush"},function(l){i=l.brushType,o=l.brushOption.brushMode||"single",s=s||!!l.areas.length}),this._brushType=i,this._brushMode=o,D(t.get("type",!0),function(l){t.setIconStatus(l,(l==="keep"?o==="multiple":l==="clear"?s:l===i)?"emphasis":"normal")})},e.prototype.updateView=function(t,a,n){this.render(t,a,n)},e.prototype.getIcons=function(){var t=this.model,a=t.get("icon",!0),n={};return D(t.get("type",!0),function(i){a[i]&&(n[i]=a[i])}),n},e.prototype.onclick=function(t,a,n){var i=this._brushType,o=this._brushMode;n==="clear"?(a.dispatchAction({type:"axisAreaSelect",intervals:[]}),a.dispatchAction({type:"brush",command:"clear",areas:[]})):a.dispatchAction({type:"takeGlobalCursor",key:"brush",brushOption:{brushType:n==="keep"?i:i===n?!1:n,brushMode:n==="keep"?o==="multiple"?"single":"multiple":o}})},e.getDefaultOption=function(t){var a={show:!0,type:kU.slice(),icon:{rect:"M7.3,34.7 M0.4,10V-0.2h9.8 M89.6,10V-0.2h-9.8 M0.4,60v10.2h9.8 M89.6,60v10.2h-9.8 M12.3,22.4V10.5h13.1 M33.6,10.5h7.8 M49.1,10.5h7.8 M77.5,22.4V10.5h-13 M12.3,31.1v8.2 M77.7,31.1v8.2 M12.3,47.6v11.9h13.1 M33.6,59.5h7.6 M49.1,59.5 h7.7 M77.5,47.6v11.9h-13",polygon:"M55.2,34.9c1.7,0,3.1,1.4,3.1,3.1s-1.4,3.1-3.1,3.1 s-3.1-1.4-3.1-3.1S53.5,34.9,55.2,34.9z M50.4,51c1.7,0,3.1,1.4,3.1,3.1c0,1.7-1.4,3.1-3.1,3.1c-1.7,0-3.1-1.4-3.1-3.1 C47.3,52.4,48.7,51,50.4,51z M55.6,37.1l1.5-7.8 M60.1,13.5l1.6-8.7l-7.8,4 M59,19l-1,5.3 M24,16.1l6.4,4.9l6.4-3.3 M48.5,11.6 l-5.9,3.1 M19.1,12.8L9.7,5.1l1.1,7.7 M13.4,29.8l1,7.3l6.6,1.6 M11.6,18.4l1,6.1 M32.8,41.9 M26.6,40.4 M27.3,40.2l6.1,1.6 M49.9,52.1l-5.6-7.6l-4.9-1.2",lineX:"M15.2,30 M19.7,15.6V1.9H29 M34.8,1.9H40.4 M55.3,15.6V1.9H45.9 M19.7,44.4V58.1H29 M34.8,58.1H40.4 M55.3,44.4 V58.1H45.9 M12.5,20.3l-9.4,9.6l9.6,9.8 M3.1,29.9h16.5 M62.5,20.3l9.4,9.6L62.3,39.7 M71.9,29.9H55.4",lineY:"M38.8,7.7 M52.7,12h13.2v9 M65.9,26.6V32 M52.7,46.3h13.2v-9 M24.9,12H11.8v9 M11.8,26.6V32 M24.9,46.3H11.8v-9 M48.2,5.1l-9.3-9l-9.4,9.2 M38.9-3.9V12 M48.2,53.3l-9.3,9l-9.4-9.2 M38.9,62.3V46.4",keep:"M4,10.5V1h10.3 M20.7,1h6.1 M33,1h6.1 M55.4,10.5V1H45.2 M4,17.3v6.6 M55.6,17.3v6.6 M4,30.5V40h10.3 M20.7,40 h6.1 M33,40h6.1 M55.4,30.5V40H45.2 M21,18.9h62.9v48.6H21V18.9z",clear:"M22,14.7l30.9,31 M52.9,14.7L22,45.7 M4.7,16.8V4.2h13.1 M26,4.2h7.8 M41.6,4.2h7.8 M70.3,16.8V4.2H57.2 M4.7,25.9v8.6 M70.3,25.9v8.6 M4.7,43.2v12.6h13.1 M26,55.8h7.8 M41.6,55.8h7.8 M70.3,43.2v12.6H57.2"},title:t.getLocaleModel().get(["toolbox","brush","title"])};return a},e})(ir);function NU(r){r.registerComponentView(PU),r.registerComponentModel(EU),r.registerPreprocessor(xU),r.registerVisual(r.PRIORITY.VISUAL.BRUSH,CU),r.registerAction({type:"brush",event:"brush",update:"updateVisual"},function(e,t){t.eachComponent({mainType:"brush",query:e},function(a){a.setAreas(e.areas)})}),r.registerAction({type:"brushSelect",event:"brushSelected",update:"none"},qt),r.registerAction({type:"brushEnd",event:"brushEnd",update:"none"},qt),qi("brush",OU)}var BU=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t.layoutMode={type:"box",ignoreSize:!0},t}return e.type="title",e.defaultOption={z:6,show:!0,text:"",target:"blank",subtext:"",subtarget:"blank",left:0,top:0,backgroundColor:"rgba(0,0,0,0)",borderColor:"#ccc",borderWidth:0,padding:5,itemGap:10,textStyle:{fontSize:18,fontWeight:"bold",color:"#464646"},subtextStyle:{fontSize:12,color:"#6E7079"}},e})(_t),VU=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.prototype.render=function(t,a,n){if(this.group.removeAll(),!!t.get("show")){var i=this.group,o=t.getModel("textStyle"),s=t.getModel("subtextStyle"),l=t.get("textAlign"),u=st(t.get("textBaseline"),t.get("textVerticalAlign")),f=new wt({style:zt(o,{text:t.get("text"),fill:o.getTextColor()},{disableBox:!0}),z2:10}),h=f.getBoundingRect(),v=t.get("subtext"),c=new wt({style:zt(s,{text:v,fill:s.getTextColor(),y:h.height+t.get("itemGap"),verticalAlign:"top"},{disableBox:!0}),z2:10}),p=t.get("link"),d=t.get("sublink"),g=t.get("triggerEvent",!0);f.silent=!p&&!g,c.silent=!d&&!g,p&&f.on("click",function(){bf(p,"_"+t.get("target"))}),d&&c.on("click",function(){bf(d,"_"+t.get("subtarget"))}),nt(f).eventData=nt(c).eventData=g?{componentType:"title",componentIndex:t.componentIndex}:null,i.add(f),v&&i.add(c);var y=i.getBoundingRect(),m=t.getBoxLayoutParams();m.width=y.width,m.height=y.height;var _=ee(m,{width:n.getWidth(),height:n.getHeight()},t.get("padding"));l||(l=t.get("left")||t.get("right"),l==="middle"&&(l="center"),l==="right"?_.x+=_.width:l==="center"&&(_.x+=_.width/2)),u||(u=t.get("top")||t.get("bottom"),u==="center"&&(u="middle"),u==="bottom"?_.y+=_.height:u==="middle"&&(_.y+=_.height/2),u=u||"top"),i.x=_.x,i.y=_.y,i.markRedraw();var S={align:l,verticalAlign:u};f.setStyle(S),c.setStyle(S),y=i.getBoundingRect();var b=_.margin,x=t.getItemStyle(["color","opacity"]);x.fill=t.get("backgroundColor");var w=new xt({shape:{x:y.x-b[3],y:y.y-b[0],width:y.width+b[1]+b[3],height:y.height+b[0]+b[2],r:t.get("borderRadius")},style:x,subPixelOptimize:!0,silent:!0});i.add(w)}},e.type="title",e})(Ut);function zU(r){r.registerComponentModel(BU),r.registerComponentView(VU)}var Tb=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t.layoutMode="box",t}return e.prototype.init=function(t,a,n){this.mergeDefaultAndTheme(t,n),this._initData()},e.prototype.mergeOption=function(t){r.prototype.mergeOption.apply(this,arguments),this._initData()},e.prototype.setCurrentIndex=function(t){t==null&&(t=this.option.currentIndex);var a=this._data.count();this.option.loop?t=(t%a+a)%a:(t>=a&&(t=a-1),t<0&&(t=0)),this.option.currentIndex=t},e.prototype.getCurrentIndex=function(){return this.option.currentIndex},e.prototype.isIndexMax=function(){return this.getCurrentIndex()>=this._data.count()-1},e.prototype.setPlayState=function(t){this.option.autoPlay=!!t},e.prototype.getPlayState=function(){return!!this.option.autoPlay},e.prototype._initData=function(){var t=this.option,a=t.data||[],n=t.axisType,i=this._names=[],o;n==="category"?(o=[],D(a,function(u,f){var h=te(bo(u),""),v;et(u)?(v=rt(u),v.value=f):v=f,o.push(v),i.push(h)})):o=a;var s={category:"ordinal",time:"time",value:"number"}[n]||"number",l=this._data=new De([{name:"value",type:s}],this);l.initData(o,i)},e.prototype.getData=function(){return this._data},e.prototype.getCategories=function(){if(this.get("axisType")==="category")return this._names.slice()},e.type="timeline",e.defaultOption={z:4,show:!0,axisType:"time",realtime:!0,left:"20%",top:null,right:"20%",bottom:0,width:null,height:40,padding:5,controlPosition:"left",autoPlay:!1,rewind:!1,loop:!0,playInterval:2e3,currentIndex:0,itemStyle:{},label:{color:"#000"},data:[]},e})(_t),EI=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.type="timeline.slider",e.defaultOption=nn(Tb.defaultOption,{backgroundColor:"rgba(0,0,0,0)",borderColor:"#ccc",borderWidth:0,orient:"horizontal",inverse:!1,tooltip:{trigger:"item"},symbol:"circle",symbolSize:12,lineStyle:{show:!0,width:2,color:"#DAE1F5"},label:{position:"auto",show:!0,interval:"auto",rotate:0,color:"#A4B1D7"},itemStyle:{color:"#A4B1D7",borderWidth:1},checkpointStyle:{symbol:"circle",symbolSize:15,color:"#316bf3",borderColor:"#fff",borderWidth:2,shadowBlur:2,shadowOffsetX:1,shadowOffsetY:1,shadowColor:"rgba(0, 0, 0, 0.3)",animation:!0,animationDuration:300,animationEasing:"quinticInOut"},controlStyle:{show:!0,showPlayBtn:!0,showPrevBtn:!0,showNextBtn:!0,itemSize:24,itemGap:12,position:"left",playIcon:"path://M31.6,53C17.5,53,6,41.5,6,27.4S17.5,1.8,31.6,1.8C45.7,1.8,57.2,13.3,57.2,27.4S45.7,53,31.6,53z M31.6,3.3 C18.4,3.3,7.5,14.1,7.5,27.4c0,13.3,10.8,24.1,24.1,24.1C44.9,51.5,55.7,40.7,55.7,27.4C55.7,14.1,44.9,3.3,31.6,3.3z M24.9,21.3 c0-2.2,1.6-3.1,3.5-2l10.5,6.1c1.899,1.1,1.899,2.9,0,4l-10.5,6.1c-1.9,1.1-3.5,0.2-3.5-2V21.3z",stopIcon:"path://M30.9,53.2C16.8,53.2,5.3,41.7,5.3,27.6S16.8,2,30.9,2C45,2,56.4,13.5,56.4,27.6S45,53.2,30.9,53.2z M30.9,3.5C17.6,3.5,6.8,14.4,6.8,27.6c0,13.3,10.8,24.1,24.101,24.1C44.2,51.7,55,40.9,55,27.6C54.9,14.4,44.1,3.5,30.9,3.5z M36.9,35.8c0,0.601-0.4,1-0.9,1h-1.3c-0.5,0-0.9-0.399-0.9-1V19.5c0-0.6,0.4-1,0.9-1H36c0.5,0,0.9,0.4,0.9,1V35.8z M27.8,35.8 c0,0.601-0.4,1-0.9,1h-1.3c-0.5,0-0.9-0.399-0.9-1V19.5c0-0.6,0.4-1,0.9-1H27c0.5,0,0.9,0.4,0.9,1L27.8,35.8L27.8,35.8z",nextIcon:"M2,18.5A1.52,1.52,0,0,1,.92,18a1.49,1.49,0,0,1,0-2.12L7.81,9.36,1,3.11A1.5,1.5,0,1,1,3,.89l8,7.34a1.48,1.48,0,0,1,.49,1.09,1.51,1.51,0,0,1-.46,1.1L3,18.08A1.5,1.5,0,0,1,2,18.5Z",prevIcon:"M10,.5A1.52,1.52,0,0,1,11.08,1a1.49,1.49,0,0,1,0,2.12L4.19,9.64,11,15.89a1.5,1.5,0,1,1-2,2.22L1,10.77A1.48,1.48,0,0,1,.5,9.68,1.51,1.51,0,0,1,1,8.58L9,.92A1.5,1.5,0,0,1,10,.5Z",prevBtnSize:18,nextBtnSize:18,color:"#A4B1D7",borderColor:"#A4B1D7",borderWidth:1},emphasis:{label:{show:!0,color:"#6f778d"},itemStyle:{color:"#316BF3"},controlStyle:{color:"#316BF3",borderColor:"#316BF3",borderWidth:2}},progress:{lineStyle:{color:"#316BF3"},itemStyle:{color:"#316BF3"},label:{color:"#6f778d"}},data:[]}),e})(Tb);jt(EI,Ch.prototype);var GU=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.type="timeline",e})(Ut),FU=(function(r){N(e,r);function e(t,a,n,i){var o=r.call(this,t,a,n)||this;return o.type=i||"value",o}return e.prototype.getLabelModel=function(){return this.model.getModel("label")},e.prototype.isHorizontal=function(){return this.model.get("orient")==="horizontal"},e})(Dr),jc=Math.PI,Ab=bt(),HU=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.prototype.init=function(t,a){this.api=a},e.prototype.render=function(t,a,n){if(this.model=t,this.api=n,this.ecModel=a,this.group.removeAll(),t.get("show",!0)){var i=this._layout(t,n),o=this._createGroup("_mainGroup"),s=this._createGroup("_labelGroup"),l=this._axis=this._createAxis(i,t);t.formatTooltip=function(u){var f=l.scale.getLabel({value:u});return le("nameValue",{noName:!0,value:f})},D(["AxisLine","AxisTick","Control","CurrentPointer"],function(u){this["_render"+u](i,o,l,t)},this),this._renderAxisLabel(i,s,l,t),this._position(i,t)}this._doPlayStop(),this._updateTicksStatus()},e.prototype.remove=function(){this._clearTimer(),this.group.removeAll()},e.prototype.dispose=function(){this._clearTimer()},e.prototype._layout=function(t,a){var n=t.get(["label","position"]),i=t.get("orient"),o=UU(t,a),s;n==null||n==="auto"?s=i==="horizontal"?o.y+o.height/2<a.getHeight()/2?"-":"+":o.x+o.width/2<a.getWidth()/2?"+":"-":Y(n)?s={horizontal:{top:"-",bottom:"+"},vertical:{left:"-",right:"+"}}[i][n]:s=n;var l={horizontal:"center",vertical:s>=0||s==="+"?"left":"right"},u={horizontal:s>=0||s==="+"?"top":"bottom",vertical:"middle"},f={horizontal:0,vertical:jc/2},h=i==="vertical"?o.height:o.width,v=t.getModel("controlStyle"),c=v.get("show",!0),p=c?v.get("itemSize"):0,d=c?v.get("itemGap"):0,g=p+d,y=t.get(["label","rotate"])||0;y=y*jc/180;var m,_,S,b=v.get("position",!0),x=c&&v.get("showPlayBtn",!0),w=c&&v.get("showPrevBtn",!0),T=c&&v.get("showNextBtn",!0),A=0,C=h;b==="left"||b==="bottom"?(x&&(m=[0,0],A+=g),w&&(_=[A,0],A+=g),T&&(S=[C-p,0],C-=g)):(x&&(m=[C-p,0],C-=g),w&&(_=[0,0],A+=g),T&&(S=[C-p,0],C-=g));var M=[A,C];return t.get("inverse")&&M.reverse(),{viewRect:o,mainLength:h,orient:i,rotation:f[i],labelRotation:y,labelPosOpt:s,labelAlign:t.get(["label","align"])||l[i],labelBaseline:t.get(["label","verticalAlign"])||t.get(["label","baseline"])||u[i],playPosition:m,prevBtnPosition:_,nextBtnPosition:S,axisExtent:M,controlSize:p,controlGap:d}},e.prototype._position=function(t,a){var n=this._mainGroup,i=this._labelGroup,o=t.viewRect;if(t.orient==="vertical"){var s=Ye(),l=o.x,u=o.y+o.height;Yr(s,s,[-l,-u]),pi(s,s,-jc/2),Yr(s,s,[l,u]),o=o.clone(),o.applyTransform(s)}var f=m(o),h=m(n.getBoundingRect()),v=m(i.getBoundingRect()),c=[n.x,n.y],p=[i.x,i.y];p[0]=c[0]=f[0][0];var d=t.labelPosOpt;if(d==null||Y(d)){var g=d==="+"?0:1;_(c,h,f,1,g),_(p,v,f,1,1-g)}else{var g=d>=0?0:1;_(c,h,f,1,g),p[1]=c[1]+d}n.setPosition(c),i.setPosition(p),n.rotation=i.rotation=t.rotation,y(n),y(i);function y(S){S.originX=f[0][0]-S.x,S.originY=f[1][0]-S.y}function m(S){return[[S.x,S.x+S.width],[S.y,S.y+S.height]]}function _(S,b,x,w,T){S[w]+=x[w][T]-b[w][T]}},e.prototype._createAxis=function(t,a){var n=a.getData(),i=a.get("axisType"),o=WU(a,i);o.getTicks=function(){return n.mapArray(["value"],function(u){return{value:u}})};var s=n.getDataExtent("value");o.setExtent(s[0],s[1]),o.calcNiceTicks();var l=new FU("value",o,t.axisExtent,i);return l.model=a,l},e.prototype._createGroup=function(t){var a=this[t]=new at;return this.group.add(a),a},e.prototype._renderAxisLine=function(t,a,n,i){var o=n.getExtent();if(i.get(["lineStyle","show"])){var s=new ne({shape:{x1:o[0],y1:0,x2:o[1],y2:0},style:V({lineCap:"round"},i.getModel("lineStyle").getLineStyle()),silent:!0,z2:1});a.add(s);var l=this._progressLine=new ne({shape:{x1:o[0],x2:this._currentPointer?this._currentPointer.x:o[0],y1:0,y2:0},style:j({lineCap:"round",lineWidth:s.style.lineWidth},i.getModel(["progress","lineStyle"]).getLineStyle()),silent:!0,z2:1});a.add(l)}},e.prototype._renderAxisTick=function(t,a,n,i){var o=this,s=i.getData(),l=n.scale.getTicks();this._tickSymbols=[],D(l,function(u){var f=n.dataToCoord(u.value),h=s.getItemModel(u.value),v=h.getModel("itemStyle"),c=h.getModel(["emphasis","itemStyle"]),p=h.getModel(["progress","itemStyle"]),d={x:f,y:0,onclick:$(o._changeTimeline,o,u.value)},g=Cb(h,v,a,d);g.ensureState("emphasis").style=c.getItemStyle(),g.ensureState("progress").style=p.getItemStyle(),ti(g);var y=nt(g);h.get("tooltip")?(y.dataIndex=u.value,y.dataModel=i):y.dataIndex=y.dataModel=null,o._tickSymbols.push(g)})},e.prototype._renderAxisLabel=function(t,a,n,i){var o=this,s=n.getLabelModel();if(s.get("show")){var l=i.getData(),u=n.getViewLabels();this._tickLabels=[],D(u,function(f){var h=f.tickValue,v=l.getItemModel(h),c=v.getModel("label"),p=v.getModel(["emphasis","label"]),d=v.getModel(["progress","label"]),g=n.dataToCoord(f.tickValue),y=new wt({x:g,y:0,rotation:t.labelRotation-t.rotation,onclick:$(o._changeTimeline,o,h),silent:!1,style:zt(c,{text:f.formattedLabel,align:t.labelAlign,verticalAlign:t.labelBaseline})});y.ensureState("emphasis").style=zt(p),y.ensureState("progress").style=zt(d),a.add(y),ti(y),Ab(y).dataIndex=h,o._tickLabels.push(y)})}},e.prototype._renderControl=function(t,a,n,i){var o=t.controlSize,s=t.rotation,l=i.getModel("controlStyle").getItemStyle(),u=i.getModel(["emphasis","controlStyle"]).getItemStyle(),f=i.getPlayState(),h=i.get("inverse",!0);v(t.nextBtnPosition,"next",$(this._changeTimeline,this,h?"-":"+")),v(t.prevBtnPosition,"prev",$(this._changeTimeline,this,h?"+":"-")),v(t.playPosition,f?"stop":"play",$(this._handlePlayClick,this,!f),!0);function v(c,p,d,g){if(c){var y=Tr(st(i.get(["controlStyle",p+"BtnSize"]),o),o),m=[0,-y/2,y,y],_=YU(i,p+"Icon",m,{x:c[0],y:c[1],originX:o/2,originY:0,rotation:g?-s:0,rectHover:!0,style:l,onclick:d});_.ensureState("emphasis").style=u,a.add(_),ti(_)}}},e.prototype._renderCurrentPointer=function(t,a,n,i){var o=i.getData(),s=i.getCurrentIndex(),l=o.getItemModel(s).getModel("checkpointStyle"),u=this,f={onCreate:function(h){h.draggable=!0,h.drift=$(u._handlePointerDrag,u),h.ondragend=$(u._handlePointerDragend,u),Db(h,u._progressLine,s,n,i,!0)},onUpdate:function(h){Db(h,u._progressLine,s,n,i)}};this._currentPointer=Cb(l,l,this._mainGroup,{},this._currentPointer,f)},e.prototype._handlePlayClick=function(t){this._clearTimer(),this.api.dispatchAction({type:"timelinePlayChange",playState:t,from:this.uid})},e.prototype._handlePointerDrag=function(t,a,n){this._clearTimer(),this._pointerChangeTimeline([n.offsetX,n.offsetY])},e.prototype._handlePointerDragend=function(t){this._pointerChangeTimeline([t.offsetX,t.offsetY],!0)},e.prototype._pointerChangeTimeline=function(t,a){var n=this._toAxisCoord(t)[0],i=this._axis,o=lr(i.getExtent().slice());n>o[1]&&(n=o[1]),n<o[0]&&(n=o[0]),this._currentPointer.x=n,this._currentPointer.markRedraw();var s=this._progressLine;s&&(s.shape.x2=n,s.dirty());var l=this._findNearestTick(n),u=this.model;(a||l!==u.getCurrentIndex()&&u.get("realtime"))&&this._changeTimeline(l)},e.prototype._doPlayStop=function(){var t=this;this._clearTimer(),this.model.getPlayState()&&(this._timer=setTimeout(function(){var a=t.model;t._changeTimeline(a.getCurrentIndex()+(a.get("rewind",!0)?-1:1))},this.model.get("playInterval")))},e.prototype._toAxisCoord=function(t){var a=this._mainGroup.getLocalTransform();return wr(t,a,!0)},e.prototype._findNearestTick=function(t){var a=this.model.getData(),n=1/0,i,o=this._axis;return a.each(["value"],function(s,l){var u=o.dataToCoord(s),f=Math.abs(u-t);f<n&&(n=f,i=l)}),i},e.prototype._clearTimer=function(){this._timer&&(clearTimeout(this._timer),this._timer=null)},e.prototype._changeTimeline=function(t){var a=this.model.getCurrentIndex();t==="+"?t=a+1:t==="-"&&(t=a-1),this.api.dispatchAction({type:"timelineChange",currentIndex:t,from:this.uid})},e.prototype._updateTicksStatus=function(){var t=this.model.getCurrentIndex(),a=this._tickSymbols,n=this._tickLabels;if(a)for(var i=0;i<a.length;i++)a&&a[i]&&a[i].toggleState("progress",i<t);if(n)for(var i=0;i<n.length;i++)n&&n[i]&&n[i].toggleState("progress",Ab(n[i]).dataIndex<=t)},e.type="timeline.slider",e})(GU);function WU(r,e){if(e=e||r.get("type"),e)switch(e){case"category":return new Rh({ordinalMeta:r.getCategories(),extent:[1/0,-1/0]});case"time":return new ry({locale:r.ecModel.getLocaleModel(),useUTC:r.ecModel.get("useUTC")});default:return new da}}function UU(r,e){return ee(r.getBoxLayoutParams(),{width:e.getWidth(),height:e.getHeight()},r.get("padding"))}function YU(r,e,t,a){var n=a.style,i=bl(r.get(["controlStyle",e]),a||{},new vt(t[0],t[1],t[2],t[3]));return n&&i.setStyle(n),i}function Cb(r,e,t,a,n,i){var o=e.get("color");if(n)n.setColor(o),t.add(n),i&&i.onUpdate(n);else{var s=r.get("symbol");n=Qt(s,-1,-1,2,2,o),n.setStyle("strokeNoScale",!0),t.add(n),i&&i.onCreate(n)}var l=e.getItemStyle(["color"]);n.setStyle(l),a=ft({rectHover:!0,z2:100},a,!0);var u=Ro(r.get("symbolSize"));a.scaleX=u[0]/2,a.scaleY=u[1]/2;var f=yi(r.get("symbolOffset"),u);f&&(a.x=(a.x||0)+f[0],a.y=(a.y||0)+f[1]);var h=r.get("symbolRotate");return a.rotation=(h||0)*Math.PI/180||0,n.attr(a),n.updateTransform(),n}function Db(r,e,t,a,n,i){if(!r.dragging){var o=n.getModel("checkpointStyle"),s=a.dataToCoord(n.getData().get("value",t));if(i||!o.get("animation",!0))r.attr({x:s,y:0}),e&&e.attr({shape:{x2:s}});else{var l={duration:o.get("animationDuration",!0),easing:o.get("animationEasing",!0)};r.stopAnimation(null,!0),r.animateTo({x:s,y:0},l),e&&e.animateTo({shape:{x2:s}},l)}}}function $U(r){r.registerAction({type:"timelineChange",event:"timelineChanged",update:"prepareAndUpdate"},function(e,t,a){var n=t.getComponent("timeline");return n&&e.currentIndex!=null&&(n.setCurrentIndex(e.currentIndex),!n.get("loop",!0)&&n.isIndexMax()&&n.getPlayState()&&(n.setPlayState(!1),a.dispatchAction({type:"timelinePlayChange",playState:!1,from:e.from}))),t.resetOption("timeline",{replaceMerge:n.get("replaceMerge",!0)}),j({currentIndex:n.option.currentIndex},e)}),r.registerAction({type:"timelinePlayChange",event:"timelinePlayChanged",update:"update"},function(e,t){var a=t.getComponent("timeline");a&&e.playState!=null&&a.setPlayState(e.playState)})}function XU(r){var e=r&&r.timeline;z(e)||(e=e?[e]:[]),D(e,function(t){t&&ZU(t)})}function ZU(r){var e=r.type,t={number:"value",time:"time"};if(t[e]&&(r.axisType=t[e],delete r.type),Mb(r),Gn(r,"controlPosition")){var a=r.controlStyle||(r.controlStyle={});Gn(a,"position")||(a.position=r.controlPosition),a.position==="none"&&!Gn(a,"show")&&(a.show=!1,delete a.position),delete r.controlPosition}D(r.data||[],function(n){et(n)&&!z(n)&&(!Gn(n,"value")&&Gn(n,"name")&&(n.value=n.name),Mb(n))})}function Mb(r){var e=r.itemStyle||(r.itemStyle={}),t=e.emphasis||(e.emphasis={}),a=r.label||r.label||{},n=a.normal||(a.normal={}),i={normal:1,emphasis:1};D(a,function(o,s){!i[s]&&!Gn(n,s)&&(n[s]=o)}),t.label&&!Gn(a,"emphasis")&&(a.emphasis=t.label,delete t.label)}function Gn(r,e){return r.hasOwnProperty(e)}function qU(r){r.registerComponentModel(EI),r.registerComponentView(HU),r.registerSubTypeDefaulter("timeline",function(){return"slider"}),$U(r),r.registerPreprocessor(XU)}function om(r,e){if(!r)return!1;for(var t=z(r)?r:[r],a=0;a<t.length;a++)if(t[a]&&t[a][e])return!0;return!1}function Ru(r){ii(r,"label",["show"])}var Eu=bt(),ya=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t.createdBySelf=!1,t}return e.prototype.init=function(t,a,n){this.mergeDefaultAndTheme(t,n),this._mergeOption(t,n,!1,!0)},e.prototype.isAnimationEnabled=function(){if(mt.node)return!1;var t=this.__hostSeries;return this.getShallow("animation")&&t&&t.isAnimationEnabled()},e.prototype.mergeOption=function(t,a){this._mergeOption(t,a,!1,!1)},e.prototype._mergeOption=function(t,a,n,i){var o=this.mainType;n||a.eachSeries(function(s){var l=s.get(this.mainType,!0),u=Eu(s)[o];if(!l||!l.data){Eu(s)[o]=null;return}u?u._mergeOption(l,a,!0):(i&&Ru(l),D(l.data,function(f){f instanceof Array?(Ru(f[0]),Ru(f[1])):Ru(f)}),u=this.createMarkerModelFromSeries(l,this,a),V(u,{mainType:this.mainType,seriesIndex:s.seriesIndex,name:s.name,createdBySelf:!0}),u.__hostSeries=s),Eu(s)[o]=u},this)},e.prototype.formatTooltip=function(t,a,n){var i=this.getData(),o=this.getRawValue(t),s=i.getName(t);return le("section",{header:this.name,blocks:[le("nameValue",{name:s,value:o,noName:!s,noValue:o==null})]})},e.prototype.getData=function(){return this._data},e.prototype.setData=function(t){this._data=t},e.prototype.getDataParams=function(t,a){var n=Ch.prototype.getDataParams.call(this,t,a),i=this.__hostSeries;return i&&(n.seriesId=i.id,n.seriesName=i.name,n.seriesType=i.subType),n},e.getMarkerModelFromSeries=function(t,a){return Eu(t)[a]},e.type="marker",e.dependencies=["series","grid","polar","geo"],e})(_t);jt(ya,Ch.prototype);var KU=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.prototype.createMarkerModelFromSeries=function(t,a,n){return new e(t,a,n)},e.type="markPoint",e.defaultOption={z:5,symbol:"pin",symbolSize:50,tooltip:{trigger:"item"},label:{show:!0,position:"inside"},itemStyle:{borderWidth:2},emphasis:{label:{show:!0}}},e})(ya);function Zd(r){return!(isNaN(parseFloat(r.x))&&isNaN(parseFloat(r.y)))}function JU(r){return!isNaN(parseFloat(r.x))&&!isNaN(parseFloat(r.y))}function ku(r,e,t,a,n,i){var o=[],s=tn(e,a),l=s?e.getCalculationInfo("stackResultDimension"):a,u=sm(e,l,r),f=e.indicesOfNearest(l,u)[0];o[n]=e.get(t,f),o[i]=e.get(l,f);var h=e.get(a,f),v=Vr(e.get(a,f));return v=Math.min(v,20),v>=0&&(o[i]=+o[i].toFixed(v)),[o,h]}var tp={min:lt(ku,"min"),max:lt(ku,"max"),average:lt(ku,"average"),median:lt(ku,"median")};function hl(r,e){if(e){var t=r.getData(),a=r.coordinateSystem,n=a&&a.dimensions;if(!JU(e)&&!z(e.coord)&&z(n)){var i=kI(e,t,a,r);if(e=rt(e),e.type&&tp[e.type]&&i.baseAxis&&i.valueAxis){var o=pt(n,i.baseAxis.dim),s=pt(n,i.valueAxis.dim),l=tp[e.type](t,i.baseDataDim,i.valueDataDim,o,s);e.coord=l[0],e.value=l[1]}else e.coord=[e.xAxis!=null?e.xAxis:e.radiusAxis,e.yAxis!=null?e.yAxis:e.angleAxis]}if(e.coord==null||!z(n))e.coord=[];else for(var u=e.coord,f=0;f<2;f++)tp[u[f]]&&(u[f]=sm(t,t.mapDimension(n[f]),u[f]));return e}}function kI(r,e,t,a){var n={};return r.valueIndex!=null||r.valueDim!=null?(n.valueDataDim=r.valueIndex!=null?e.getDimension(r.valueIndex):r.valueDim,n.valueAxis=t.getAxis(QU(a,n.valueDataDim)),n.baseAxis=t.getOtherAxis(n.valueAxis),n.baseDataDim=e.mapDimension(n.baseAxis.dim)):(n.baseAxis=a.getBaseAxis(),n.valueAxis=t.getOtherAxis(n.baseAxis),n.baseDataDim=e.mapDimension(n.baseAxis.dim),n.valueDataDim=e.mapDimension(n.valueAxis.dim)),n}function QU(r,e){var t=r.getData().getDimensionInfo(e);return t&&t.coordDim}function vl(r,e){return r&&r.containData&&e.coord&&!Zd(e)?r.containData(e.coord):!0}function jU(r,e,t){return r&&r.containZone&&e.coord&&t.coord&&!Zd(e)&&!Zd(t)?r.containZone(e.coord,t.coord):!0}function OI(r,e){return r?function(t,a,n,i){var o=i<2?t.coord&&t.coord[i]:t.value;return Xa(o,e[i])}:function(t,a,n,i){return Xa(t.value,e[i])}}function sm(r,e,t){if(t==="average"){var a=0,n=0;return r.each(e,function(i,o){isNaN(i)||(a+=i,n++)}),a/n}else return t==="median"?r.getMedian(e):r.getDataExtent(e)[t==="max"?1:0]}var ep=bt(),lm=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.prototype.init=function(){this.markerGroupMap=Z()},e.prototype.render=function(t,a,n){var i=this,o=this.markerGroupMap;o.each(function(s){ep(s).keep=!1}),a.eachSeries(function(s){var l=ya.getMarkerModelFromSeries(s,i.type);l&&i.renderSeries(s,l,a,n)}),o.each(function(s){!ep(s).keep&&i.group.remove(s.group)})},e.prototype.markKeep=function(t){ep(t).keep=!0},e.prototype.toggleBlurSeries=function(t,a){var n=this;D(t,function(i){var o=ya.getMarkerModelFromSeries(i,n.type);if(o){var s=o.getData();s.eachItemGraphicEl(function(l){l&&(a?CT(l):Cg(l))})}})},e.type="marker",e})(Ut);function Ib(r,e,t){var a=e.coordinateSystem;r.each(function(n){var i=r.getItemModel(n),o,s=W(i.get("x"),t.getWidth()),l=W(i.get("y"),t.getHeight());if(!isNaN(s)&&!isNaN(l))o=[s,l];else if(e.getMarkerPosition)o=e.getMarkerPosition(r.getValues(r.dimensions,n));else if(a){var u=r.get(a.dimensions[0],n),f=r.get(a.dimensions[1],n);o=a.dataToPoint([u,f])}isNaN(s)||(o[0]=s),isNaN(l)||(o[1]=l),r.setItemLayout(n,o)})}var t8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.prototype.updateTransform=function(t,a,n){a.eachSeries(function(i){var o=ya.getMarkerModelFromSeries(i,"markPoint");o&&(Ib(o.getData(),i,n),this.markerGroupMap.get(i.id).updateLayout())},this)},e.prototype.renderSeries=function(t,a,n,i){var o=t.coordinateSystem,s=t.id,l=t.getData(),u=this.markerGroupMap,f=u.get(s)||u.set(s,new Ml),h=e8(o,t,a);a.setData(h),Ib(a.getData(),t,i),h.each(function(v){var c=h.getItemModel(v),p=c.getShallow("symbol"),d=c.getShallow("symbolSize"),g=c.getShallow("symbolRotate"),y=c.getShallow("symbolOffset"),m=c.getShallow("symbolKeepAspect");if(J(p)||J(d)||J(g)||J(y)){var _=a.getRawValue(v),S=a.getDataParams(v);J(p)&&(p=p(_,S)),J(d)&&(d=d(_,S)),J(g)&&(g=g(_,S)),J(y)&&(y=y(_,S))}var b=c.getModel("itemStyle").getItemStyle(),x=Tl(l,"color");b.fill||(b.fill=x),h.setItemVisual(v,{symbol:p,symbolSize:d,symbolRotate:g,symbolOffset:y,symbolKeepAspect:m,style:b})}),f.updateData(h),this.group.add(f.group),h.eachItemGraphicEl(function(v){v.traverse(function(c){nt(c).dataModel=a})}),this.markKeep(f),f.group.silent=a.get("silent")||t.get("silent")},e.type="markPoint",e})(lm);function e8(r,e,t){var a;r?a=G(r&&r.dimensions,function(s){var l=e.getData().getDimensionInfo(e.getData().mapDimension(s))||{};return V(V({},l),{name:s,ordinalMeta:null})}):a=[{name:"value",type:"float"}];var n=new De(a,t),i=G(t.get("data"),lt(hl,e));r&&(i=Mt(i,lt(vl,r)));var o=OI(!!r,a);return n.initData(i,null,o),n}function r8(r){r.registerComponentModel(KU),r.registerComponentView(t8),r.registerPreprocessor(function(e){om(e.series,"markPoint")&&(e.markPoint=e.markPoint||{})})}var a8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.prototype.createMarkerModelFromSeries=function(t,a,n){return new e(t,a,n)},e.type="markLine",e.defaultOption={z:5,symbol:["circle","arrow"],symbolSize:[8,16],symbolOffset:0,precision:2,tooltip:{trigger:"item"},label:{show:!0,position:"end",distance:5},lineStyle:{type:"dashed"},emphasis:{label:{show:!0},lineStyle:{width:3}},animationEasing:"linear"},e})(ya),Ou=bt(),n8=function(r,e,t,a){var n=r.getData(),i;if(z(a))i=a;else{var o=a.type;if(o==="min"||o==="max"||o==="average"||o==="median"||a.xAxis!=null||a.yAxis!=null){var s=void 0,l=void 0;if(a.yAxis!=null||a.xAxis!=null)s=e.getAxis(a.yAxis!=null?"y":"x"),l=fe(a.yAxis,a.xAxis);else{var u=kI(a,n,e,r);s=u.valueAxis;var f=DC(n,u.valueDataDim);l=sm(n,f,o)}var h=s.dim==="x"?0:1,v=1-h,c=rt(a),p={coord:[]};c.type=null,c.coord=[],c.coord[v]=-1/0,p.coord[v]=1/0;var d=t.get("precision");d>=0&&At(l)&&(l=+l.toFixed(Math.min(d,20))),c.coord[h]=p.coord[h]=l,i=[c,p,{type:o,valueIndex:a.valueIndex,value:l}]}else i=[]}var g=[hl(r,i[0]),hl(r,i[1]),V({},i[2])];return g[2].type=g[2].type||null,ft(g[2],g[0]),ft(g[2],g[1]),g};function Xf(r){return!isNaN(r)&&!isFinite(r)}function Lb(r,e,t,a){var n=1-r,i=a.dimensions[r];return Xf(e[n])&&Xf(t[n])&&e[r]===t[r]&&a.getAxis(i).containData(e[r])}function i8(r,e){if(r.type==="cartesian2d"){var t=e[0].coord,a=e[1].coord;if(t&&a&&(Lb(1,t,a,r)||Lb(0,t,a,r)))return!0}return vl(r,e[0])&&vl(r,e[1])}function rp(r,e,t,a,n){var i=a.coordinateSystem,o=r.getItemModel(e),s,l=W(o.get("x"),n.getWidth()),u=W(o.get("y"),n.getHeight());if(!isNaN(l)&&!isNaN(u))s=[l,u];else{if(a.getMarkerPosition)s=a.getMarkerPosition(r.getValues(r.dimensions,e));else{var f=i.dimensions,h=r.get(f[0],e),v=r.get(f[1],e);s=i.dataToPoint([h,v])}if(_i(i,"cartesian2d")){var c=i.getAxis("x"),p=i.getAxis("y"),f=i.dimensions;Xf(r.get(f[0],e))?s[0]=c.toGlobalCoord(c.getExtent()[t?0:1]):Xf(r.get(f[1],e))&&(s[1]=p.toGlobalCoord(p.getExtent()[t?0:1]))}isNaN(l)||(s[0]=l),isNaN(u)||(s[1]=u)}r.setItemLayout(e,s)}var o8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.prototype.updateTransform=function(t,a,n){a.eachSeries(function(i){var o=ya.getMarkerModelFromSeries(i,"markLine");if(o){var s=o.getData(),l=Ou(o).from,u=Ou(o).to;l.each(function(f){rp(l,f,!0,i,n),rp(u,f,!1,i,n)}),s.each(function(f){s.setItemLayout(f,[l.getItemLayout(f),u.getItemLayout(f)])}),this.markerGroupMap.get(i.id).updateLayout()}},this)},e.prototype.renderSeries=function(t,a,n,i){var o=t.coordinateSystem,s=t.id,l=t.getData(),u=this.markerGroupMap,f=u.get(s)||u.set(s,new Iy);this.group.add(f.group);var h=s8(o,t,a),v=h.from,c=h.to,p=h.line;Ou(a).from=v,Ou(a).to=c,a.setData(p);var d=a.get("symbol"),g=a.get("symbolSize"),y=a.get("symbolRotate"),m=a.get("symbolOffset");z(d)||(d=[d,d]),z(g)||(g=[g,g]),z(y)||(y=[y,y]),z(m)||(m=[m,m]),h.from.each(function(S){_(v,S,!0),_(c,S,!1)}),p.each(function(S){var b=p.getItemModel(S).getModel("lineStyle").getLineStyle();p.setItemLayout(S,[v.getItemLayout(S),c.getItemLayout(S)]),b.stroke==null&&(b.stroke=v.getItemVisual(S,"style").fill),p.setItemVisual(S,{fromSymbolKeepAspect:v.getItemVisual(S,"symbolKeepAspect"),fromSymbolOffset:v.getItemVisual(S,"symbolOffset"),fromSymbolRotate:v.getItemVisual(S,"symbolRotate"),fromSymbolSize:v.getItemVisual(S,"symbolSize"),fromSymbol:v.getItemVisual(S,"symbol"),toSymbolKeepAspect:c.getItemVisual(S,"symbolKeepAspect"),toSymbolOffset:c.getItemVisual(S,"symbolOffset"),toSymbolRotate:c.getItemVisual(S,"symbolRotate"),toSymbolSize:c.getItemVisual(S,"symbolSize"),toSymbol:c.getItemVisual(S,"symbol"),style:b})}),f.updateData(p),h.line.eachItemGraphicEl(function(S){nt(S).dataModel=a,S.traverse(function(b){nt(b).dataModel=a})});function _(S,b,x){var w=S.getItemModel(b);rp(S,b,x,t,i);var T=w.getModel("itemStyle").getItemStyle();T.fill==null&&(T.fill=Tl(l,"color")),S.setItemVisual(b,{symbolKeepAspect:w.get("symbolKeepAspect"),symbolOffset:st(w.get("symbolOffset",!0),m[x?0:1]),symbolRotate:st(w.get("symbolRotate",!0),y[x?0:1]),symbolSize:st(w.get("symbolSize"),g[x?0:1]),symbol:st(w.get("symbol",!0),d[x?0:1]),style:T})}this.markKeep(f),f.group.silent=a.get("silent")||t.get("silent")},e.type="markLine",e})(lm);function s8(r,e,t){var a;r?a=G(r&&r.dimensions,function(u){var f=e.getData().getDimensionInfo(e.getData().mapDimension(u))||{};return V(V({},f),{name:u,ordinalMeta:null})}):a=[{name:"value",type:"float"}];var n=new De(a,t),i=new De(a,t),o=new De([],t),s=G(t.get("data"),lt(n8,e,r,t));r&&(s=Mt(s,lt(i8,r)));var l=OI(!!r,a);return n.initData(G(s,function(u){return u[0]}),null,l),i.initData(G(s,function(u){return u[1]}),null,l),o.initData(G(s,function(u){return u[2]})),o.hasItemOption=!0,{from:n,to:i,line:o}}function l8(r){r.registerComponentModel(a8),r.registerComponentView(o8),r.registerPreprocessor(function(e){om(e.series,"markLine")&&(e.markLine=e.markLine||{})})}var u8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.prototype.createMarkerModelFromSeries=function(t,a,n){return new e(t,a,n)},e.type="markArea",e.defaultOption={z:1,tooltip:{trigger:"item"},animation:!1,label:{show:!0,position:"top"},itemStyle:{borderWidth:0},emphasis:{label:{show:!0,position:"top"}}},e})(ya),Nu=bt(),f8=function(r,e,t,a){var n=a[0],i=a[1];if(!(!n||!i)){var o=hl(r,n),s=hl(r,i),l=o.coord,u=s.coord;l[0]=fe(l[0],-1/0),l[1]=fe(l[1],-1/0),u[0]=fe(u[0],1/0),u[1]=fe(u[1],1/0);var f=lg([{},o,s]);return f.coord=[o.coord,s.coord],f.x0=o.x,f.y0=o.y,f.x1=s.x,f.y1=s.y,f}};function Zf(r){return!isNaN(r)&&!isFinite(r)}function Pb(r,e,t,a){var n=1-r;return Zf(e[n])&&Zf(t[n])}function h8(r,e){var t=e.coord[0],a=e.coord[1],n={coord:t,x:e.x0,y:e.y0},i={coord:a,x:e.x1,y:e.y1};return _i(r,"cartesian2d")?t&&a&&(Pb(1,t,a)||Pb(0,t,a))?!0:jU(r,n,i):vl(r,n)||vl(r,i)}function Rb(r,e,t,a,n){var i=a.coordinateSystem,o=r.getItemModel(e),s,l=W(o.get(t[0]),n.getWidth()),u=W(o.get(t[1]),n.getHeight());if(!isNaN(l)&&!isNaN(u))s=[l,u];else{if(a.getMarkerPosition){var f=r.getValues(["x0","y0"],e),h=r.getValues(["x1","y1"],e),v=i.clampData(f),c=i.clampData(h),p=[];t[0]==="x0"?p[0]=v[0]>c[0]?h[0]:f[0]:p[0]=v[0]>c[0]?f[0]:h[0],t[1]==="y0"?p[1]=v[1]>c[1]?h[1]:f[1]:p[1]=v[1]>c[1]?f[1]:h[1],s=a.getMarkerPosition(p,t,!0)}else{var d=r.get(t[0],e),g=r.get(t[1],e),y=[d,g];i.clampData&&i.clampData(y,y),s=i.dataToPoint(y,!0)}if(_i(i,"cartesian2d")){var m=i.getAxis("x"),_=i.getAxis("y"),d=r.get(t[0],e),g=r.get(t[1],e);Zf(d)?s[0]=m.toGlobalCoord(m.getExtent()[t[0]==="x0"?0:1]):Zf(g)&&(s[1]=_.toGlobalCoord(_.getExtent()[t[1]==="y0"?0:1]))}isNaN(l)||(s[0]=l),isNaN(u)||(s[1]=u)}return s}var Eb=[["x0","y0"],["x1","y0"],["x1","y1"],["x0","y1"]],v8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.prototype.updateTransform=function(t,a,n){a.eachSeries(function(i){var o=ya.getMarkerModelFromSeries(i,"markArea");if(o){var s=o.getData();s.each(function(l){var u=G(Eb,function(h){return Rb(s,l,h,i,n)});s.setItemLayout(l,u);var f=s.getItemGraphicEl(l);f.setShape("points",u)})}},this)},e.prototype.renderSeries=function(t,a,n,i){var o=t.coordinateSystem,s=t.id,l=t.getData(),u=this.markerGroupMap,f=u.get(s)||u.set(s,{group:new at});this.group.add(f.group),this.markKeep(f);var h=c8(o,t,a);a.setData(h),h.each(function(v){var c=G(Eb,function(T){return Rb(h,v,T,t,i)}),p=o.getAxis("x").scale,d=o.getAxis("y").scale,g=p.getExtent(),y=d.getExtent(),m=[p.parse(h.get("x0",v)),p.parse(h.get("x1",v))],_=[d.parse(h.get("y0",v)),d.parse(h.get("y1",v))];lr(m),lr(_);var S=!(g[0]>m[1]||g[1]<m[0]||y[0]>_[1]||y[1]<_[0]),b=!S;h.setItemLayout(v,{points:c,allClipped:b});var x=h.getItemModel(v).getModel("itemStyle").getItemStyle(),w=Tl(l,"color");x.fill||(x.fill=w,Y(x.fill)&&(x.fill=ff(x.fill,.4))),x.stroke||(x.stroke=w),h.setItemVisual(v,"style",x)}),h.diff(Nu(f).data).add(function(v){var c=h.getItemLayout(v);if(!c.allClipped){var p=new Re({shape:{points:c.points}});h.setItemGraphicEl(v,p),f.group.add(p)}}).update(function(v,c){var p=Nu(f).data.getItemGraphicEl(c),d=h.getItemLayout(v);d.allClipped?p&&f.group.remove(p):(p?Ct(p,{shape:{points:d.points}},a,v):p=new Re({shape:{points:d.points}}),h.setItemGraphicEl(v,p),f.group.add(p))}).remove(function(v){var c=Nu(f).data.getItemGraphicEl(v);f.group.remove(c)}).execute(),h.eachItemGraphicEl(function(v,c){var p=h.getItemModel(c),d=h.getItemVisual(c,"style");v.useStyle(h.getItemVisual(c,"style")),de(v,se(p),{labelFetcher:a,labelDataIndex:c,defaultText:h.getName(c)||"",inheritColor:Y(d.fill)?ff(d.fill,1):"#000"}),pe(v,p),Yt(v,null,null,p.get(["emphasis","disabled"])),nt(v).dataModel=a}),Nu(f).data=h,f.group.silent=a.get("silent")||t.get("silent")},e.type="markArea",e})(lm);function c8(r,e,t){var a,n,i=["x0","y0","x1","y1"];if(r){var o=G(r&&r.dimensions,function(u){var f=e.getData(),h=f.getDimensionInfo(f.mapDimension(u))||{};return V(V({},h),{name:u,ordinalMeta:null})});n=G(i,function(u,f){return{name:u,type:o[f%2].type}}),a=new De(n,t)}else n=[{name:"value",type:"float"}],a=new De(n,t);var s=G(t.get("data"),lt(f8,e,r,t));r&&(s=Mt(s,lt(h8,r)));var l=r?function(u,f,h,v){var c=u.coord[Math.floor(v/2)][v%2];return Xa(c,n[v])}:function(u,f,h,v){return Xa(u.value,n[v])};return a.initData(s,null,l),a.hasItemOption=!0,a}function p8(r){r.registerComponentModel(u8),r.registerComponentView(v8),r.registerPreprocessor(function(e){om(e.series,"markArea")&&(e.markArea=e.markArea||{})})}var d8=function(r,e){if(e==="all")return{type:"all",title:r.getLocaleModel().get(["legend","selector","all"])};if(e==="inverse")return{type:"inverse",title:r.getLocaleModel().get(["legend","selector","inverse"])}},qd=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t.layoutMode={type:"box",ignoreSize:!0},t}return e.prototype.init=function(t,a,n){this.mergeDefaultAndTheme(t,n),t.selected=t.selected||{},this._updateSelector(t)},e.prototype.mergeOption=function(t,a){r.prototype.mergeOption.call(this,t,a),this._updateSelector(t)},e.prototype._updateSelector=function(t){var a=t.selector,n=this.ecModel;a===!0&&(a=t.selector=["all","inverse"]),z(a)&&D(a,function(i,o){Y(i)&&(i={type:i}),a[o]=ft(i,d8(n,i.type))})},e.prototype.optionUpdated=function(){this._updateData(this.ecModel);var t=this._data;if(t[0]&&this.get("selectedMode")==="single"){for(var a=!1,n=0;n<t.length;n++){var i=t[n].get("name");if(this.isSelected(i)){this.select(i),a=!0;break}}!a&&this.select(t[0].get("name"))}},e.prototype._updateData=function(t){var a=[],n=[];t.eachRawSeries(function(l){var u=l.name;n.push(u);var f;if(l.legendVisualProvider){var h=l.legendVisualProvider,v=h.getAllNames();t.isSeriesFiltered(l)||(n=n.concat(v)),v.length?a=a.concat(v):f=!0}else f=!0;f&&mg(l)&&a.push(l.name)}),this._availableNames=n;var i=this.get("data")||a,o=Z(),s=G(i,function(l){return(Y(l)||At(l))&&(l={name:l}),o.get(l.name)?null:(o.set(l.name,!0),new Lt(l,this,this.ecModel))},this);this._data=Mt(s,function(l){return!!l})},e.prototype.getData=function(){return this._data},e.prototype.select=function(t){var a=this.option.selected,n=this.get("selectedMode");if(n==="single"){var i=this._data;D(i,function(o){a[o.get("name")]=!1})}a[t]=!0},e.prototype.unSelect=function(t){this.get("selectedMode")!=="single"&&(this.option.selected[t]=!1)},e.prototype.toggleSelected=function(t){var a=this.option.selected;a.hasOwnProperty(t)||(a[t]=!0),this[a[t]?"unSelect":"select"](t)},e.prototype.allSelect=function(){var t=this._data,a=this.option.selected;D(t,function(n){a[n.get("name",!0)]=!0})},e.prototype.inverseSelect=function(){var t=this._data,a=this.option.selected;D(t,function(n){var i=n.get("name",!0);a.hasOwnProperty(i)||(a[i]=!0),a[i]=!a[i]})},e.prototype.isSelected=function(t){var a=this.option.selected;return!(a.hasOwnProperty(t)&&!a[t])&&pt(this._availableNames,t)>=0},e.prototype.getOrient=function(){return this.get("orient")==="vertical"?{index:1,name:"vertical"}:{index:0,name:"horizontal"}},e.type="legend.plain",e.dependencies=["series"],e.defaultOption={z:4,show:!0,orient:"horizontal",left:"center",top:0,align:"auto",backgroundColor:"rgba(0,0,0,0)",borderColor:"#ccc",borderRadius:0,borderWidth:0,padding:5,itemGap:10,itemWidth:25,itemHeight:14,symbolRotate:"inherit",symbolKeepAspect:!0,inactiveColor:"#ccc",inactiveBorderColor:"#ccc",inactiveBorderWidth:"auto",itemStyle:{color:"inherit",opacity:"inherit",borderColor:"inherit",borderWidth:"auto",borderCap:"inherit",borderJoin:"inherit",borderDashOffset:"inherit",borderMiterLimit:"inherit"},lineStyle:{width:"auto",color:"inherit",inactiveColor:"#ccc",inactiveWidth:2,opacity:"inherit",type:"inherit",cap:"inherit",join:"inherit",dashOffset:"inherit",miterLimit:"inherit"},textStyle:{color:"#333"},selectedMode:!0,selector:!1,selectorLabel:{show:!0,borderRadius:10,padding:[3,5,3,5],fontSize:12,fontFamily:"sans-serif",color:"#666",borderWidth:1,borderColor:"#666"},emphasis:{selectorLabel:{show:!0,color:"#eee",backgroundColor:"#666"}},selectorPosition:"auto",selectorItemGap:7,selectorButtonGap:10,tooltip:{show:!1}},e})(_t),Fi=lt,Kd=D,Bu=at,NI=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t.newlineDisabled=!1,t}return e.prototype.init=function(){this.group.add(this._contentGroup=new Bu),this.group.add(this._selectorGroup=new Bu),this._isFirstRender=!0},e.prototype.getContentGroup=function(){return this._contentGroup},e.prototype.getSelectorGroup=function(){return this._selectorGroup},e.prototype.render=function(t,a,n){var i=this._isFirstRender;if(this._isFirstRender=!1,this.resetInner(),!!t.get("show",!0)){var o=t.get("align"),s=t.get("orient");(!o||o==="auto")&&(o=t.get("left")==="right"&&s==="vertical"?"right":"left");var l=t.get("selector",!0),u=t.get("selectorPosition",!0);l&&(!u||u==="auto")&&(u=s==="horizontal"?"end":"start"),this.renderInner(o,t,a,n,l,s,u);var f=t.getBoxLayoutParams(),h={width:n.getWidth(),height:n.getHeight()},v=t.get("padding"),c=ee(f,h,v),p=this.layoutInner(t,o,c,i,l,u),d=ee(j({width:p.width,height:p.height},f),h,v);this.group.x=d.x-p.x,this.group.y=d.y-p.y,this.group.markRedraw(),this.group.add(this._backgroundEl=TI(p,t))}},e.prototype.resetInner=function(){this.getContentGroup().removeAll(),this._backgroundEl&&this.group.remove(this._backgroundEl),this.getSelectorGroup().removeAll()},e.prototype.renderInner=function(t,a,n,i,o,s,l){var u=this.getContentGroup(),f=Z(),h=a.get("selectedMode"),v=[];n.eachRawSeries(function(c){!c.get("legendHoverLink")&&v.push(c.id)}),Kd(a.getData(),function(c,p){var d=c.get("name");if(!this.newlineDisabled&&(d===""||d===`
 `)){var g=new Bu;g.newline=!0,u.add(g);return}var y=n.getSeriesByName(d)[0];if(!f.get(d))if(y){var m=y.getData(),_=m.getVisual("legendLineStyle")||{},S=m.getVisual("legendIcon"),b=m.getVisual("style"),x=this._createItem(y,d,p,c,a,t,_,b,S,h,i);x.on("click",Fi(kb,d,null,i,v)).on("mouseover",Fi(Jd,y.name,null,i,v)).on("mouseout",Fi(Qd,y.name,null,i,v)),n.ssr&&x.eachChild(function(w){var T=nt(w);T.seriesIndex=y.seriesIndex,T.dataIndex=p,T.ssrType="legend"}),f.set(d,!0)}else n.eachRawSeries(function(w){if(!f.get(d)&&w.legendVisualProvider){var T=w.legendVisualProvider;if(!T.containName(d))return;var A=T.indexOfName(d),C=T.getItemVisual(A,"style"),M=T.getItemVisual(A,"legendIcon"),I=$e(C.fill);I&&I[3]===0&&(I[3]=.2,C=V(V({},C),{fill:sa(I,"rgba")}));var L=this._createItem(w,d,p,c,a,t,{},C,M,h,i);L.on("click",Fi(kb,null,d,i,v)).on("mouseover",Fi(Jd,null,d,i,v)).on("mouseout",Fi(Qd,null,d,i,v)),n.ssr&&L.eachChild(function(P){var R=nt(P);R.seriesIndex=w.seriesIndex,R.dataIndex=p,R.ssrType="legend"}),f.set(d,!0)}},this)},this),o&&this._createSelector(o,a,i,s,l)},e.prototype._createSelector=function(t,a,n,i,o){var s=this.getSelectorGroup();Kd(t,function(u){var f=u.type,h=new wt({style:{x:0,y:0,align:"center",verticalAlign:"middle"},onclick:function(){n.dispatchAction({type:f==="all"?"legendAllSelect":"legendInverseSelect",legendId:a.id})}});s.add(h);var v=a.getModel("selectorLabel"),c=a.getModel(["emphasis","selectorLabel"]);de(h,{normal:v,emphasis:c},{defaultText:u.title}),ti(h)})},e.prototype._createItem=function(t,a,n,i,o,s,l,u,f,h,v){var c=t.visualDrawType,p=o.get("itemWidth"),d=o.get("itemHeight"),g=o.isSelected(a),y=i.get("symbolRotate"),m=i.get("symbolKeepAspect"),_=i.get("icon");f=_||f||"roundRect";var S=g8(f,i,l,u,c,g,v),b=new Bu,x=i.getModel("textStyle");if(J(t.getLegendIcon)&&(!_||_==="inherit"))b.add(t.getLegendIcon({itemWidth:p,itemHeight:d,icon:f,iconRotate:y,itemStyle:S.itemStyle,lineStyle:S.lineStyle,symbolKeepAspect:m}));else{var w=_==="inherit"&&t.getData().getVisual("symbol")?y==="inherit"?t.getData().getVisual("symbolRotate"):y:0;b.add(y8({itemWidth:p,itemHeight:d,icon:f,iconRotate:w,itemStyle:S.itemStyle,symbolKeepAspect:m}))}var T=s==="left"?p+5:-5,A=s,C=o.get("formatter"),M=a;Y(C)&&C?M=C.replace("{name}",a??""):J(C)&&(M=C(a));var I=g?x.getTextColor():i.get("inactiveColor");b.add(new wt({style:zt(x,{text:M,x:T,y:d/2,fill:I,align:A,verticalAlign:"middle"},{inheritColor:I})}));var L=new xt({shape:b.getBoundingRect(),style:{fill:"transparent"}}),P=i.getModel("tooltip");return P.get("show")&&di({el:L,componentModel:o,itemName:a,itemTooltipOption:P.option}),b.add(L),b.eachChild(function(R){R.silent=!0}),L.silent=!h,this.getContentGroup().add(b),ti(b),b.__legendDataIndex=n,b},e.prototype.layoutInner=function(t,a,n,i,o,s){var l=this.getContentGroup(),u=this.getSelectorGroup();ri(t.get("orient"),l,t.get("itemGap"),n.width,n.height);var f=l.getBoundingRect(),h=[-f.x,-f.y];if(u.markRedraw(),l.markRedraw(),o){ri("horizontal",u,t.get("selectorItemGap",!0));var v=u.getBoundingRect(),c=[-v.x,-v.y],p=t.get("selectorButtonGap",!0),d=t.getOrient().index,g=d===0?"width":"height",y=d===0?"height":"width",m=d===0?"y":"x";s==="end"?c[d]+=f[g]+p:h[d]+=v[g]+p,c[1-d]+=f[y]/2-v[y]/2,u.x=c[0],u.y=c[1],l.x=h[0],l.y=h[1];var _={x:0,y:0};return _[g]=f[g]+p+v[g],_[y]=Math.max(f[y],v[y]),_[m]=Math.min(0,v[m]+c[1-d]),_}else return l.x=h[0],l.y=h[1],this.group.getBoundingRect()},e.prototype.remove=function(){this.getContentGroup().removeAll(),this._isFirstRender=!0},e.type="legend.plain",e})(Ut);function g8(r,e,t,a,n,i,o){function s(g,y){g.lineWidth==="auto"&&(g.lineWidth=y.lineWidth>0?2:0),Kd(g,function(m,_){g[_]==="inherit"&&(g[_]=y[_])})}var l=e.getModel("itemStyle"),u=l.getItemStyle(),f=r.lastIndexOf("empty",0)===0?"fill":"stroke",h=l.getShallow("decal");u.decal=!h||h==="inherit"?a.decal:ho(h,o),u.fill==="inherit"&&(u.fill=a[n]),u.stroke==="inherit"&&(u.stroke=a[f]),u.opacity==="inherit"&&(u.opacity=(n==="fill"?a:t).opacity),s(u,a);var v=e.getModel("lineStyle"),c=v.getLineStyle();if(s(c,t),u.fill==="auto"&&(u.fill=a.fill),u.stroke==="auto"&&(u.stroke=a.fill),c.stroke==="auto"&&(c.stroke=a.fill),!i){var p=e.get("inactiveBorderWidth"),d=u[f];u.lineWidth=p==="auto"?a.lineWidth>0&&d?2:0:u.lineWidth,u.fill=e.get("inactiveColor"),u.stroke=e.get("inactiveBorderColor"),c.stroke=v.get("inactiveColor"),c.lineWidth=v.get("inactiveWidth")}return{itemStyle:u,lineStyle:c}}function y8(r){var e=r.icon||"roundRect",t=Qt(e,0,0,r.itemWidth,r.itemHeight,r.itemStyle.fill,r.symbolKeepAspect);return t.setStyle(r.itemStyle),t.rotation=(r.iconRotate||0)*Math.PI/180,t.setOrigin([r.itemWidth/2,r.itemHeight/2]),e.indexOf("empty")>-1&&(t.style.stroke=t.style.fill,t.style.fill="#fff",t.style.lineWidth=2),t}function kb(r,e,t,a){Qd(r,e,t,a),t.dispatchAction({type:"legendToggleSelect",name:r??e}),Jd(r,e,t,a)}function BI(r){for(var e=r.getZr().storage.getDisplayList(),t,a=0,n=e.length;a<n&&!(t=e[a].states.emphasis);)a++;return t&&t.hoverLayer}function Jd(r,e,t,a){BI(t)||t.dispatchAction({type:"highlight",seriesName:r,name:e,excludeSeriesId:a})}function Qd(r,e,t,a){BI(t)||t.dispatchAction({type:"downplay",seriesName:r,name:e,excludeSeriesId:a})}function m8(r){var e=r.findComponents({mainType:"legend"});e&&e.length&&r.filterSeries(function(t){for(var a=0;a<e.length;a++)if(!e[a].isSelected(t.name))return!1;return!0})}function ss(r,e,t){var a=r==="allSelect"||r==="inverseSelect",n={},i=[];t.eachComponent({mainType:"legend",query:e},function(s){a?s[r]():s[r](e.name),Ob(s,n),i.push(s.componentIndex)});var o={};return t.eachComponent("legend",function(s){D(n,function(l,u){s[l?"select":"unSelect"](u)}),Ob(s,o)}),a?{selected:o,legendIndex:i}:{name:e.name,selected:o}}function Ob(r,e){var t=e||{};return D(r.getData(),function(a){var n=a.get("name");if(!(n===`
-`||n==="")){var i=r.isSelected(n);X(t,n)?t[n]=t[n]&&i:t[n]=i}}),t}function _8(r){r.registerAction("legendToggleSelect","legendselectchanged",lt(ss,"toggleSelected")),r.registerAction("legendAllSelect","legendselectall",lt(ss,"allSelect")),r.registerAction("legendInverseSelect","legendinverseselect",lt(ss,"inverseSelect")),r.registerAction("legendSelect","legendselected",lt(ss,"select")),r.registerAction("legendUnSelect","legendunselected",lt(ss,"unSelect"))}function VI(r){r.registerComponentModel(qd),r.registerComponentView(NI),r.registerProcessor(r.PRIORITY.PROCESSOR.SERIES_FILTER,m8),r.registerSubTypeDefaulter("legend",function(){return"plain"}),_8(r)}var S8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.prototype.setScrollDataIndex=function(t){this.option.scrollDataIndex=t},e.prototype.init=function(t,a,n){var i=Mo(t);r.prototype.init.call(this,t,a,n),Nb(this,t,i)},e.prototype.mergeOption=function(t,a){r.prototype.mergeOption.call(this,t,a),Nb(this,this.option,t)},e.type="legend.scroll",e.defaultOption=nn(qd.defaultOption,{scrollDataIndex:0,pageButtonItemGap:5,pageButtonGap:null,pageButtonPosition:"end",pageFormatter:"{current}/{total}",pageIcons:{horizontal:["M0,0L12,-10L12,10z","M0,0L-12,-10L-12,10z"],vertical:["M0,0L20,0L10,-20z","M0,0L20,0L10,20z"]},pageIconColor:"#2f4554",pageIconInactiveColor:"#aaa",pageIconSize:15,pageTextStyle:{color:"#333"},animationDurationUpdate:800}),e})(qd);function Nb(r,e,t){var a=r.getOrient(),n=[1,1];n[a.index]=0,ja(e,t,{type:"box",ignoreSize:!!n})}var Bb=at,ap=["width","height"],np=["x","y"],x8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t.newlineDisabled=!0,t._currentIndex=0,t}return e.prototype.init=function(){r.prototype.init.call(this),this.group.add(this._containerGroup=new Bb),this._containerGroup.add(this.getContentGroup()),this.group.add(this._controllerGroup=new Bb)},e.prototype.resetInner=function(){r.prototype.resetInner.call(this),this._controllerGroup.removeAll(),this._containerGroup.removeClipPath(),this._containerGroup.__rectSize=null},e.prototype.renderInner=function(t,a,n,i,o,s,l){var u=this;r.prototype.renderInner.call(this,t,a,n,i,o,s,l);var f=this._controllerGroup,h=a.get("pageIconSize",!0),v=z(h)?h:[h,h];p("pagePrev",0);var c=a.getModel("pageTextStyle");f.add(new wt({name:"pageText",style:{text:"xx/xx",fill:c.getTextColor(),font:c.getFont(),verticalAlign:"middle",align:"center"},silent:!0})),p("pageNext",1);function p(d,g){var y=d+"DataIndex",m=bl(a.get("pageIcons",!0)[a.getOrient().name][g],{onclick:$(u._pageGo,u,y,a,i)},{x:-v[0]/2,y:-v[1]/2,width:v[0],height:v[1]});m.name=d,f.add(m)}},e.prototype.layoutInner=function(t,a,n,i,o,s){var l=this.getSelectorGroup(),u=t.getOrient().index,f=ap[u],h=np[u],v=ap[1-u],c=np[1-u];o&&ri("horizontal",l,t.get("selectorItemGap",!0));var p=t.get("selectorButtonGap",!0),d=l.getBoundingRect(),g=[-d.x,-d.y],y=rt(n);o&&(y[f]=n[f]-d[f]-p);var m=this._layoutContentAndController(t,i,y,u,f,v,c,h);if(o){if(s==="end")g[u]+=m[f]+p;else{var _=d[f]+p;g[u]-=_,m[h]-=_}m[f]+=d[f]+p,g[1-u]+=m[c]+m[v]/2-d[v]/2,m[v]=Math.max(m[v],d[v]),m[c]=Math.min(m[c],d[c]+g[1-u]),l.x=g[0],l.y=g[1],l.markRedraw()}return m},e.prototype._layoutContentAndController=function(t,a,n,i,o,s,l,u){var f=this.getContentGroup(),h=this._containerGroup,v=this._controllerGroup;ri(t.get("orient"),f,t.get("itemGap"),i?n.width:null,i?null:n.height),ri("horizontal",v,t.get("pageButtonItemGap",!0));var c=f.getBoundingRect(),p=v.getBoundingRect(),d=this._showController=c[o]>n[o],g=[-c.x,-c.y];a||(g[i]=f[u]);var y=[0,0],m=[-p.x,-p.y],_=st(t.get("pageButtonGap",!0),t.get("itemGap",!0));if(d){var S=t.get("pageButtonPosition",!0);S==="end"?m[i]+=n[o]-p[o]:y[i]+=p[o]+_}m[1-i]+=c[s]/2-p[s]/2,f.setPosition(g),h.setPosition(y),v.setPosition(m);var b={x:0,y:0};if(b[o]=d?n[o]:c[o],b[s]=Math.max(c[s],p[s]),b[l]=Math.min(0,p[l]+m[1-i]),h.__rectSize=n[o],d){var x={x:0,y:0};x[o]=Math.max(n[o]-p[o]-_,0),x[s]=b[s],h.setClipPath(new xt({shape:x})),h.__rectSize=x[o]}else v.eachChild(function(T){T.attr({invisible:!0,silent:!0})});var w=this._getPageInfo(t);return w.pageIndex!=null&&Ct(f,{x:w.contentPosition[0],y:w.contentPosition[1]},d?t:null),this._updatePageInfoView(t,w),b},e.prototype._pageGo=function(t,a,n){var i=this._getPageInfo(a)[t];i!=null&&n.dispatchAction({type:"legendScroll",scrollDataIndex:i,legendId:a.id})},e.prototype._updatePageInfoView=function(t,a){var n=this._controllerGroup;D(["pagePrev","pageNext"],function(f){var h=f+"DataIndex",v=a[h]!=null,c=n.childOfName(f);c&&(c.setStyle("fill",v?t.get("pageIconColor",!0):t.get("pageIconInactiveColor",!0)),c.cursor=v?"pointer":"default")});var i=n.childOfName("pageText"),o=t.get("pageFormatter"),s=a.pageIndex,l=s!=null?s+1:0,u=a.pageCount;i&&o&&i.setStyle("text",Y(o)?o.replace("{current}",l==null?"":l+"").replace("{total}",u==null?"":u+""):o({current:l,total:u}))},e.prototype._getPageInfo=function(t){var a=t.get("scrollDataIndex",!0),n=this.getContentGroup(),i=this._containerGroup.__rectSize,o=t.getOrient().index,s=ap[o],l=np[o],u=this._findTargetItemIndex(a),f=n.children(),h=f[u],v=f.length,c=v?1:0,p={contentPosition:[n.x,n.y],pageCount:c,pageIndex:c-1,pagePrevDataIndex:null,pageNextDataIndex:null};if(!h)return p;var d=S(h);p.contentPosition[o]=-d.s;for(var g=u+1,y=d,m=d,_=null;g<=v;++g)_=S(f[g]),(!_&&m.e>y.s+i||_&&!b(_,y.s))&&(m.i>y.i?y=m:y=_,y&&(p.pageNextDataIndex==null&&(p.pageNextDataIndex=y.i),++p.pageCount)),m=_;for(var g=u-1,y=d,m=d,_=null;g>=-1;--g)_=S(f[g]),(!_||!b(m,_.s))&&y.i<m.i&&(m=y,p.pagePrevDataIndex==null&&(p.pagePrevDataIndex=y.i),++p.pageCount,++p.pageIndex),y=_;return p;function S(x){if(x){var w=x.getBoundingRect(),T=w[l]+x[l];return{s:T,e:T+w[s],i:x.__legendDataIndex}}}function b(x,w){return x.e>=w&&x.s<=w+i}},e.prototype._findTargetItemIndex=function(t){if(!this._showController)return 0;var a,n=this.getContentGroup(),i;return n.eachChild(function(o,s){var l=o.__legendDataIndex;i==null&&l!=null&&(i=s),l===t&&(a=s)}),a??i},e.type="legend.scroll",e})(NI);function b8(r){r.registerAction("legendScroll","legendscroll",function(e,t){var a=e.scrollDataIndex;a!=null&&t.eachComponent({mainType:"legend",subType:"scroll",query:e},function(n){n.setScrollDataIndex(a)})})}function w8(r){gt(VI),r.registerComponentModel(S8),r.registerComponentView(x8),b8(r)}function T8(r){gt(VI),gt(w8)}var A8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.type="dataZoom.inside",e.defaultOption=nn(fl.defaultOption,{disabled:!1,zoomLock:!1,zoomOnMouseWheel:!0,moveOnMouseMove:!0,moveOnMouseWheel:!1,preventDefaultMouseMove:!0}),e})(fl),um=bt();function C8(r,e,t){um(r).coordSysRecordMap.each(function(a){var n=a.dataZoomInfoMap.get(e.uid);n&&(n.getRange=t)})}function D8(r,e){for(var t=um(r).coordSysRecordMap,a=t.keys(),n=0;n<a.length;n++){var i=a[n],o=t.get(i),s=o.dataZoomInfoMap;if(s){var l=e.uid,u=s.get(l);u&&(s.removeKey(l),s.keys().length||zI(t,o))}}}function zI(r,e){if(e){r.removeKey(e.model.uid);var t=e.controller;t&&t.dispose()}}function M8(r,e){var t={model:e,containsPoint:lt(L8,e),dispatchAction:lt(I8,r),dataZoomInfoMap:null,controller:null},a=t.controller=new El(r.getZr());return D(["pan","zoom","scrollMove"],function(n){a.on(n,function(i){var o=[];t.dataZoomInfoMap.each(function(s){if(i.isAvailableBehavior(s.model.option)){var l=(s.getRange||{})[n],u=l&&l(s.dzReferCoordSysInfo,t.model.mainType,t.controller,i);!s.model.get("disabled",!0)&&u&&o.push({dataZoomId:s.model.id,start:u[0],end:u[1]})}}),o.length&&t.dispatchAction(o)})}),t}function I8(r,e){r.isDisposed()||r.dispatchAction({type:"dataZoom",animation:{easing:"cubicOut",duration:100},batch:e})}function L8(r,e,t,a){return r.coordinateSystem.containPoint([t,a])}function P8(r){var e,t="type_",a={type_true:2,type_move:1,type_false:0,type_undefined:-1},n=!0;return r.each(function(i){var o=i.model,s=o.get("disabled",!0)?!1:o.get("zoomLock",!0)?"move":!0;a[t+s]>a[t+e]&&(e=s),n=n&&o.get("preventDefaultMouseMove",!0)}),{controlType:e,opt:{zoomOnMouseWheel:!0,moveOnMouseMove:!0,moveOnMouseWheel:!0,preventDefaultMouseMove:!!n}}}function R8(r){r.registerProcessor(r.PRIORITY.PROCESSOR.FILTER,function(e,t){var a=um(t),n=a.coordSysRecordMap||(a.coordSysRecordMap=Z());n.each(function(i){i.dataZoomInfoMap=null}),e.eachComponent({mainType:"dataZoom",subType:"inside"},function(i){var o=xI(i);D(o.infoList,function(s){var l=s.model.uid,u=n.get(l)||n.set(l,M8(t,s.model)),f=u.dataZoomInfoMap||(u.dataZoomInfoMap=Z());f.set(i.uid,{dzReferCoordSysInfo:s,model:i,getRange:null})})}),n.each(function(i){var o=i.controller,s,l=i.dataZoomInfoMap;if(l){var u=l.keys()[0];u!=null&&(s=l.get(u))}if(!s){zI(n,i);return}var f=P8(l);o.enable(f.controlType,f.opt),o.setPointerChecker(i.containsPoint),Po(i,"dispatchAction",s.model.get("throttle",!0),"fixRate")})})}var E8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type="dataZoom.inside",t}return e.prototype.render=function(t,a,n){if(r.prototype.render.apply(this,arguments),t.noTarget()){this._clear();return}this.range=t.getPercentRange(),C8(n,t,{pan:$(ip.pan,this),zoom:$(ip.zoom,this),scrollMove:$(ip.scrollMove,this)})},e.prototype.dispose=function(){this._clear(),r.prototype.dispose.apply(this,arguments)},e.prototype._clear=function(){D8(this.api,this.dataZoomModel),this.range=null},e.type="dataZoom.inside",e})(tm),ip={zoom:function(r,e,t,a){var n=this.range,i=n.slice(),o=r.axisModels[0];if(o){var s=op[e](null,[a.originX,a.originY],o,t,r),l=(s.signal>0?s.pixelStart+s.pixelLength-s.pixel:s.pixel-s.pixelStart)/s.pixelLength*(i[1]-i[0])+i[0],u=Math.max(1/a.scale,0);i[0]=(i[0]-l)*u+l,i[1]=(i[1]-l)*u+l;var f=this.dataZoomModel.findRepresentativeAxisProxy().getMinMaxSpan();if(xi(0,i,[0,100],0,f.minSpan,f.maxSpan),this.range=i,n[0]!==i[0]||n[1]!==i[1])return i}},pan:Vb(function(r,e,t,a,n,i){var o=op[a]([i.oldX,i.oldY],[i.newX,i.newY],e,n,t);return o.signal*(r[1]-r[0])*o.pixel/o.pixelLength}),scrollMove:Vb(function(r,e,t,a,n,i){var o=op[a]([0,0],[i.scrollDelta,i.scrollDelta],e,n,t);return o.signal*(r[1]-r[0])*i.scrollDelta})};function Vb(r){return function(e,t,a,n){var i=this.range,o=i.slice(),s=e.axisModels[0];if(s){var l=r(o,s,e,t,a,n);if(xi(l,o,[0,100],"all"),this.range=o,i[0]!==o[0]||i[1]!==o[1])return o}}}var op={grid:function(r,e,t,a,n){var i=t.axis,o={},s=n.model.coordinateSystem.getRect();return r=r||[0,0],i.dim==="x"?(o.pixel=e[0]-r[0],o.pixelLength=s.width,o.pixelStart=s.x,o.signal=i.inverse?1:-1):(o.pixel=e[1]-r[1],o.pixelLength=s.height,o.pixelStart=s.y,o.signal=i.inverse?-1:1),o},polar:function(r,e,t,a,n){var i=t.axis,o={},s=n.model.coordinateSystem,l=s.getRadiusAxis().getExtent(),u=s.getAngleAxis().getExtent();return r=r?s.pointToCoord(r):[0,0],e=s.pointToCoord(e),t.mainType==="radiusAxis"?(o.pixel=e[0]-r[0],o.pixelLength=l[1]-l[0],o.pixelStart=l[0],o.signal=i.inverse?1:-1):(o.pixel=e[1]-r[1],o.pixelLength=u[1]-u[0],o.pixelStart=u[0],o.signal=i.inverse?-1:1),o},singleAxis:function(r,e,t,a,n){var i=t.axis,o=n.model.coordinateSystem.getRect(),s={};return r=r||[0,0],i.orient==="horizontal"?(s.pixel=e[0]-r[0],s.pixelLength=o.width,s.pixelStart=o.x,s.signal=i.inverse?1:-1):(s.pixel=e[1]-r[1],s.pixelLength=o.height,s.pixelStart=o.y,s.signal=i.inverse?-1:1),s}};function GI(r){em(r),r.registerComponentModel(A8),r.registerComponentView(E8),R8(r)}var k8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.type="dataZoom.slider",e.layoutMode="box",e.defaultOption=nn(fl.defaultOption,{show:!0,right:"ph",top:"ph",width:"ph",height:"ph",left:null,bottom:null,borderColor:"#d2dbee",borderRadius:3,backgroundColor:"rgba(47,69,84,0)",dataBackground:{lineStyle:{color:"#d2dbee",width:.5},areaStyle:{color:"#d2dbee",opacity:.2}},selectedDataBackground:{lineStyle:{color:"#8fb0f7",width:.5},areaStyle:{color:"#8fb0f7",opacity:.2}},fillerColor:"rgba(135,175,274,0.2)",handleIcon:"path://M-9.35,34.56V42m0-40V9.5m-2,0h4a2,2,0,0,1,2,2v21a2,2,0,0,1-2,2h-4a2,2,0,0,1-2-2v-21A2,2,0,0,1-11.35,9.5Z",handleSize:"100%",handleStyle:{color:"#fff",borderColor:"#ACB8D1"},moveHandleSize:7,moveHandleIcon:"path://M-320.9-50L-320.9-50c18.1,0,27.1,9,27.1,27.1V85.7c0,18.1-9,27.1-27.1,27.1l0,0c-18.1,0-27.1-9-27.1-27.1V-22.9C-348-41-339-50-320.9-50z M-212.3-50L-212.3-50c18.1,0,27.1,9,27.1,27.1V85.7c0,18.1-9,27.1-27.1,27.1l0,0c-18.1,0-27.1-9-27.1-27.1V-22.9C-239.4-41-230.4-50-212.3-50z M-103.7-50L-103.7-50c18.1,0,27.1,9,27.1,27.1V85.7c0,18.1-9,27.1-27.1,27.1l0,0c-18.1,0-27.1-9-27.1-27.1V-22.9C-130.9-41-121.8-50-103.7-50z",moveHandleStyle:{color:"#D2DBEE",opacity:.7},showDetail:!0,showDataShadow:"auto",realtime:!0,zoomLock:!1,textStyle:{color:"#6E7079"},brushSelect:!0,brushStyle:{color:"rgba(135,175,274,0.15)"},emphasis:{handleLabel:{show:!0},handleStyle:{borderColor:"#8FB0F7"},moveHandleStyle:{color:"#8FB0F7"}}}),e})(fl),ls=xt,zb=7,O8=1,sp=30,N8=7,us="horizontal",Gb="vertical",B8=5,V8=["line","bar","candlestick","scatter"],z8={easing:"cubicOut",duration:100,delay:0},G8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t._displayables={},t}return e.prototype.init=function(t,a){this.api=a,this._onBrush=$(this._onBrush,this),this._onBrushEnd=$(this._onBrushEnd,this)},e.prototype.render=function(t,a,n,i){if(r.prototype.render.apply(this,arguments),Po(this,"_dispatchZoomAction",t.get("throttle"),"fixRate"),this._orient=t.getOrient(),t.get("show")===!1){this.group.removeAll();return}if(t.noTarget()){this._clear(),this.group.removeAll();return}(!i||i.type!=="dataZoom"||i.from!==this.uid)&&this._buildView(),this._updateView()},e.prototype.dispose=function(){this._clear(),r.prototype.dispose.apply(this,arguments)},e.prototype._clear=function(){Js(this,"_dispatchZoomAction");var t=this.api.getZr();t.off("mousemove",this._onBrush),t.off("mouseup",this._onBrushEnd)},e.prototype._buildView=function(){var t=this.group;t.removeAll(),this._brushing=!1,this._displayables.brushRect=null,this._resetLocation(),this._resetInterval();var a=this._displayables.sliderGroup=new at;this._renderBackground(),this._renderHandle(),this._renderDataShadow(),t.add(a),this._positionGroup()},e.prototype._resetLocation=function(){var t=this.dataZoomModel,a=this.api,n=t.get("brushSelect"),i=n?N8:0,o=this._findCoordRect(),s={width:a.getWidth(),height:a.getHeight()},l=this._orient===us?{right:s.width-o.x-o.width,top:s.height-sp-zb-i,width:o.width,height:sp}:{right:zb,top:o.y,width:sp,height:o.height},u=Mo(t.option);D(["right","top","width","height"],function(h){u[h]==="ph"&&(u[h]=l[h])});var f=ee(u,s);this._location={x:f.x,y:f.y},this._size=[f.width,f.height],this._orient===Gb&&this._size.reverse()},e.prototype._positionGroup=function(){var t=this.group,a=this._location,n=this._orient,i=this.dataZoomModel.getFirstTargetAxisModel(),o=i&&i.get("inverse"),s=this._displayables.sliderGroup,l=(this._dataShadowInfo||{}).otherAxisInverse;s.attr(n===us&&!o?{scaleY:l?1:-1,scaleX:1}:n===us&&o?{scaleY:l?1:-1,scaleX:-1}:n===Gb&&!o?{scaleY:l?-1:1,scaleX:1,rotation:Math.PI/2}:{scaleY:l?-1:1,scaleX:-1,rotation:Math.PI/2});var u=t.getBoundingRect([s]);t.x=a.x-u.x,t.y=a.y-u.y,t.markRedraw()},e.prototype._getViewExtent=function(){return[0,this._size[0]]},e.prototype._renderBackground=function(){var t=this.dataZoomModel,a=this._size,n=this._displayables.sliderGroup,i=t.get("brushSelect");n.add(new ls({silent:!0,shape:{x:0,y:0,width:a[0],height:a[1]},style:{fill:t.get("backgroundColor")},z2:-40}));var o=new ls({shape:{x:0,y:0,width:a[0],height:a[1]},style:{fill:"transparent"},z2:0,onclick:$(this._onClickPanel,this)}),s=this.api.getZr();i?(o.on("mousedown",this._onBrushStart,this),o.cursor="crosshair",s.on("mousemove",this._onBrush),s.on("mouseup",this._onBrushEnd)):(s.off("mousemove",this._onBrush),s.off("mouseup",this._onBrushEnd)),n.add(o)},e.prototype._renderDataShadow=function(){var t=this._dataShadowInfo=this._prepareDataShadowInfo();if(this._displayables.dataShadowSegs=[],!t)return;var a=this._size,n=this._shadowSize||[],i=t.series,o=i.getRawData(),s=i.getShadowDim&&i.getShadowDim(),l=s&&o.getDimensionInfo(s)?i.getShadowDim():t.otherDim;if(l==null)return;var u=this._shadowPolygonPts,f=this._shadowPolylinePts;if(o!==this._shadowData||l!==this._shadowDim||a[0]!==n[0]||a[1]!==n[1]){var h=o.getDataExtent(l),v=(h[1]-h[0])*.3;h=[h[0]-v,h[1]+v];var c=[0,a[1]],p=[0,a[0]],d=[[a[0],0],[0,0]],g=[],y=p[1]/(o.count()-1),m=0,_=Math.round(o.count()/a[0]),S;o.each([l],function(A,C){if(_>0&&C%_){m+=y;return}var M=A==null||isNaN(A)||A==="",I=M?0:It(A,h,c,!0);M&&!S&&C?(d.push([d[d.length-1][0],0]),g.push([g[g.length-1][0],0])):!M&&S&&(d.push([m,0]),g.push([m,0])),d.push([m,I]),g.push([m,I]),m+=y,S=M}),u=this._shadowPolygonPts=d,f=this._shadowPolylinePts=g}this._shadowData=o,this._shadowDim=l,this._shadowSize=[a[0],a[1]];var b=this.dataZoomModel;function x(A){var C=b.getModel(A?"selectedDataBackground":"dataBackground"),M=new at,I=new Re({shape:{points:u},segmentIgnoreThreshold:1,style:C.getModel("areaStyle").getAreaStyle(),silent:!0,z2:-20}),L=new Ee({shape:{points:f},segmentIgnoreThreshold:1,style:C.getModel("lineStyle").getLineStyle(),silent:!0,z2:-19});return M.add(I),M.add(L),M}for(var w=0;w<3;w++){var T=x(w===1);this._displayables.sliderGroup.add(T),this._displayables.dataShadowSegs.push(T)}},e.prototype._prepareDataShadowInfo=function(){var t=this.dataZoomModel,a=t.get("showDataShadow");if(a!==!1){var n,i=this.ecModel;return t.eachTargetAxis(function(o,s){var l=t.getAxisProxy(o,s).getTargetSeriesModels();D(l,function(u){if(!n&&!(a!==!0&&pt(V8,u.get("type"))<0)){var f=i.getComponent(Ua(o),s).axis,h=F8(o),v,c=u.coordinateSystem;h!=null&&c.getOtherAxis&&(v=c.getOtherAxis(f).inverse),h=u.getData().mapDimension(h),n={thisAxis:f,series:u,thisDim:o,otherDim:h,otherAxisInverse:v}}},this)},this),n}},e.prototype._renderHandle=function(){var t=this.group,a=this._displayables,n=a.handles=[null,null],i=a.handleLabels=[null,null],o=this._displayables.sliderGroup,s=this._size,l=this.dataZoomModel,u=this.api,f=l.get("borderRadius")||0,h=l.get("brushSelect"),v=a.filler=new ls({silent:h,style:{fill:l.get("fillerColor")},textConfig:{position:"inside"}});o.add(v),o.add(new ls({silent:!0,subPixelOptimize:!0,shape:{x:0,y:0,width:s[0],height:s[1],r:f},style:{stroke:l.get("dataBackgroundColor")||l.get("borderColor"),lineWidth:O8,fill:"rgba(0,0,0,0)"}})),D([0,1],function(_){var S=l.get("handleIcon");!Af[S]&&S.indexOf("path://")<0&&S.indexOf("image://")<0&&(S="path://"+S);var b=Qt(S,-1,0,2,2,null,!0);b.attr({cursor:Fb(this._orient),draggable:!0,drift:$(this._onDragMove,this,_),ondragend:$(this._onDragEnd,this),onmouseover:$(this._showDataInfo,this,!0),onmouseout:$(this._showDataInfo,this,!1),z2:5});var x=b.getBoundingRect(),w=l.get("handleSize");this._handleHeight=W(w,this._size[1]),this._handleWidth=x.width/x.height*this._handleHeight,b.setStyle(l.getModel("handleStyle").getItemStyle()),b.style.strokeNoScale=!0,b.rectHover=!0,b.ensureState("emphasis").style=l.getModel(["emphasis","handleStyle"]).getItemStyle(),ti(b);var T=l.get("handleColor");T!=null&&(b.style.fill=T),o.add(n[_]=b);var A=l.getModel("textStyle"),C=l.get("handleLabel")||{},M=C.show||!1;t.add(i[_]=new wt({silent:!0,invisible:!M,style:zt(A,{x:0,y:0,text:"",verticalAlign:"middle",align:"center",fill:A.getTextColor(),font:A.getFont()}),z2:10}))},this);var c=v;if(h){var p=W(l.get("moveHandleSize"),s[1]),d=a.moveHandle=new xt({style:l.getModel("moveHandleStyle").getItemStyle(),silent:!0,shape:{r:[0,0,2,2],y:s[1]-.5,height:p}}),g=p*.8,y=a.moveHandleIcon=Qt(l.get("moveHandleIcon"),-g/2,-g/2,g,g,"#fff",!0);y.silent=!0,y.y=s[1]+p/2-.5,d.ensureState("emphasis").style=l.getModel(["emphasis","moveHandleStyle"]).getItemStyle();var m=Math.min(s[1]/2,Math.max(p,10));c=a.moveZone=new xt({invisible:!0,shape:{y:s[1]-m,height:p+m}}),c.on("mouseover",function(){u.enterEmphasis(d)}).on("mouseout",function(){u.leaveEmphasis(d)}),o.add(d),o.add(y),o.add(c)}c.attr({draggable:!0,cursor:Fb(this._orient),drift:$(this._onDragMove,this,"all"),ondragstart:$(this._showDataInfo,this,!0),ondragend:$(this._onDragEnd,this),onmouseover:$(this._showDataInfo,this,!0),onmouseout:$(this._showDataInfo,this,!1)})},e.prototype._resetInterval=function(){var t=this._range=this.dataZoomModel.getPercentRange(),a=this._getViewExtent();this._handleEnds=[It(t[0],[0,100],a,!0),It(t[1],[0,100],a,!0)]},e.prototype._updateInterval=function(t,a){var n=this.dataZoomModel,i=this._handleEnds,o=this._getViewExtent(),s=n.findRepresentativeAxisProxy().getMinMaxSpan(),l=[0,100];xi(a,i,o,n.get("zoomLock")?"all":t,s.minSpan!=null?It(s.minSpan,l,o,!0):null,s.maxSpan!=null?It(s.maxSpan,l,o,!0):null);var u=this._range,f=this._range=lr([It(i[0],o,l,!0),It(i[1],o,l,!0)]);return!u||u[0]!==f[0]||u[1]!==f[1]},e.prototype._updateView=function(t){var a=this._displayables,n=this._handleEnds,i=lr(n.slice()),o=this._size;D([0,1],function(c){var p=a.handles[c],d=this._handleHeight;p.attr({scaleX:d/2,scaleY:d/2,x:n[c]+(c?-1:1),y:o[1]/2-d/2})},this),a.filler.setShape({x:i[0],y:0,width:i[1]-i[0],height:o[1]});var s={x:i[0],width:i[1]-i[0]};a.moveHandle&&(a.moveHandle.setShape(s),a.moveZone.setShape(s),a.moveZone.getBoundingRect(),a.moveHandleIcon&&a.moveHandleIcon.attr("x",s.x+s.width/2));for(var l=a.dataShadowSegs,u=[0,i[0],i[1],o[0]],f=0;f<l.length;f++){var h=l[f],v=h.getClipPath();v||(v=new xt,h.setClipPath(v)),v.setShape({x:u[f],y:0,width:u[f+1]-u[f],height:o[1]})}this._updateDataInfo(t)},e.prototype._updateDataInfo=function(t){var a=this.dataZoomModel,n=this._displayables,i=n.handleLabels,o=this._orient,s=["",""];if(a.get("showDetail")){var l=a.findRepresentativeAxisProxy();if(l){var u=l.getAxisModel().axis,f=this._range,h=t?l.calculateDataWindow({start:f[0],end:f[1]}).valueWindow:l.getDataValueWindow();s=[this._formatLabel(h[0],u),this._formatLabel(h[1],u)]}}var v=lr(this._handleEnds.slice());c.call(this,0),c.call(this,1);function c(p){var d=ei(n.handles[p].parent,this.group),g=yh(p===0?"right":"left",d),y=this._handleWidth/2+B8,m=wr([v[p]+(p===0?-y:y),this._size[1]/2],d);i[p].setStyle({x:m[0],y:m[1],verticalAlign:o===us?"middle":g,align:o===us?g:"center",text:s[p]})}},e.prototype._formatLabel=function(t,a){var n=this.dataZoomModel,i=n.get("labelFormatter"),o=n.get("labelPrecision");(o==null||o==="auto")&&(o=a.getPixelPrecision());var s=t==null||isNaN(t)?"":a.type==="category"||a.type==="time"?a.scale.getLabel({value:Math.round(t)}):t.toFixed(Math.min(o,20));return J(i)?i(t,s):Y(i)?i.replace("{value}",s):s},e.prototype._showDataInfo=function(t){var a=this.dataZoomModel.get("handleLabel")||{},n=a.show||!1,i=this.dataZoomModel.getModel(["emphasis","handleLabel"]),o=i.get("show")||!1,s=t||this._dragging?o:n,l=this._displayables,u=l.handleLabels;u[0].attr("invisible",!s),u[1].attr("invisible",!s),l.moveHandle&&this.api[s?"enterEmphasis":"leaveEmphasis"](l.moveHandle,1)},e.prototype._onDragMove=function(t,a,n,i){this._dragging=!0,fa(i.event);var o=this._displayables.sliderGroup.getLocalTransform(),s=wr([a,n],o,!0),l=this._updateInterval(t,s[0]),u=this.dataZoomModel.get("realtime");this._updateView(!u),l&&u&&this._dispatchZoomAction(!0)},e.prototype._onDragEnd=function(){this._dragging=!1,this._showDataInfo(!1);var t=this.dataZoomModel.get("realtime");!t&&this._dispatchZoomAction(!1)},e.prototype._onClickPanel=function(t){var a=this._size,n=this._displayables.sliderGroup.transformCoordToLocal(t.offsetX,t.offsetY);if(!(n[0]<0||n[0]>a[0]||n[1]<0||n[1]>a[1])){var i=this._handleEnds,o=(i[0]+i[1])/2,s=this._updateInterval("all",n[0]-o);this._updateView(),s&&this._dispatchZoomAction(!1)}},e.prototype._onBrushStart=function(t){var a=t.offsetX,n=t.offsetY;this._brushStart=new ht(a,n),this._brushing=!0,this._brushStartTime=+new Date},e.prototype._onBrushEnd=function(t){if(this._brushing){var a=this._displayables.brushRect;if(this._brushing=!1,!!a){a.attr("ignore",!0);var n=a.shape,i=+new Date;if(!(i-this._brushStartTime<200&&Math.abs(n.width)<5)){var o=this._getViewExtent(),s=[0,100];this._range=lr([It(n.x,o,s,!0),It(n.x+n.width,o,s,!0)]),this._handleEnds=[n.x,n.x+n.width],this._updateView(),this._dispatchZoomAction(!1)}}}},e.prototype._onBrush=function(t){this._brushing&&(fa(t.event),this._updateBrushRect(t.offsetX,t.offsetY))},e.prototype._updateBrushRect=function(t,a){var n=this._displayables,i=this.dataZoomModel,o=n.brushRect;o||(o=n.brushRect=new ls({silent:!0,style:i.getModel("brushStyle").getItemStyle()}),n.sliderGroup.add(o)),o.attr("ignore",!1);var s=this._brushStart,l=this._displayables.sliderGroup,u=l.transformCoordToLocal(t,a),f=l.transformCoordToLocal(s.x,s.y),h=this._size;u[0]=Math.max(Math.min(h[0],u[0]),0),o.setShape({x:f[0],y:0,width:u[0]-f[0],height:h[1]})},e.prototype._dispatchZoomAction=function(t){var a=this._range;this.api.dispatchAction({type:"dataZoom",from:this.uid,dataZoomId:this.dataZoomModel.id,animation:t?z8:null,start:a[0],end:a[1]})},e.prototype._findCoordRect=function(){var t,a=xI(this.dataZoomModel).infoList;if(!t&&a.length){var n=a[0].model.coordinateSystem;t=n.getRect&&n.getRect()}if(!t){var i=this.api.getWidth(),o=this.api.getHeight();t={x:i*.2,y:o*.2,width:i*.6,height:o*.6}}return t},e.type="dataZoom.slider",e})(tm);function F8(r){var e={x:"y",y:"x",radius:"angle",angle:"radius"};return e[r]}function Fb(r){return r==="vertical"?"ns-resize":"ew-resize"}function FI(r){r.registerComponentModel(k8),r.registerComponentView(G8),em(r)}function H8(r){gt(GI),gt(FI)}var HI={get:function(r,e,t){var a=rt((W8[r]||{})[e]);return t&&z(a)?a[a.length-1]:a}},W8={color:{active:["#006edd","#e0ffff"],inactive:["rgba(0,0,0,0)"]},colorHue:{active:[0,360],inactive:[0,0]},colorSaturation:{active:[.3,1],inactive:[0,0]},colorLightness:{active:[.9,.5],inactive:[0,0]},colorAlpha:{active:[.3,1],inactive:[0,0]},opacity:{active:[.3,1],inactive:[0,0]},symbol:{active:["circle","roundRect","diamond"],inactive:["none"]},symbolSize:{active:[10,50],inactive:[0,0]}},Hb=oe.mapVisual,U8=oe.eachVisual,Y8=z,Wb=D,$8=lr,X8=It,qf=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t.stateList=["inRange","outOfRange"],t.replacableOptionKeys=["inRange","outOfRange","target","controller","color"],t.layoutMode={type:"box",ignoreSize:!0},t.dataBound=[-1/0,1/0],t.targetVisuals={},t.controllerVisuals={},t}return e.prototype.init=function(t,a,n){this.mergeDefaultAndTheme(t,n)},e.prototype.optionUpdated=function(t,a){var n=this.option;!a&&PI(n,t,this.replacableOptionKeys),this.textStyleModel=this.getModel("textStyle"),this.resetItemSize(),this.completeVisualOption()},e.prototype.resetVisual=function(t){var a=this.stateList;t=$(t,this),this.controllerVisuals=$d(this.option.controller,a,t),this.targetVisuals=$d(this.option.target,a,t)},e.prototype.getItemSymbol=function(){return null},e.prototype.getTargetSeriesIndices=function(){var t=this.option.seriesIndex,a=[];return t==null||t==="all"?this.ecModel.eachSeries(function(n,i){a.push(i)}):a=Et(t),a},e.prototype.eachTargetSeries=function(t,a){D(this.getTargetSeriesIndices(),function(n){var i=this.ecModel.getSeriesByIndex(n);i&&t.call(a,i)},this)},e.prototype.isTargetSeries=function(t){var a=!1;return this.eachTargetSeries(function(n){n===t&&(a=!0)}),a},e.prototype.formatValueText=function(t,a,n){var i=this.option,o=i.precision,s=this.dataBound,l=i.formatter,u;n=n||["<",">"],z(t)&&(t=t.slice(),u=!0);var f=a?t:u?[h(t[0]),h(t[1])]:h(t);if(Y(l))return l.replace("{value}",u?f[0]:f).replace("{value2}",u?f[1]:f);if(J(l))return u?l(t[0],t[1]):l(t);if(u)return t[0]===s[0]?n[0]+" "+f[1]:t[1]===s[1]?n[1]+" "+f[0]:f[0]+" - "+f[1];return f;function h(v){return v===s[0]?"min":v===s[1]?"max":(+v).toFixed(Math.min(o,20))}},e.prototype.resetExtent=function(){var t=this.option,a=$8([t.min,t.max]);this._dataExtent=a},e.prototype.getDataDimensionIndex=function(t){var a=this.option.dimension;if(a!=null)return t.getDimensionIndex(a);for(var n=t.dimensions,i=n.length-1;i>=0;i--){var o=n[i],s=t.getDimensionInfo(o);if(!s.isCalculationCoord)return s.storeDimIndex}},e.prototype.getExtent=function(){return this._dataExtent.slice()},e.prototype.completeVisualOption=function(){var t=this.ecModel,a=this.option,n={inRange:a.inRange,outOfRange:a.outOfRange},i=a.target||(a.target={}),o=a.controller||(a.controller={});ft(i,n),ft(o,n);var s=this.isCategory();l.call(this,i),l.call(this,o),u.call(this,i,"inRange","outOfRange"),f.call(this,o);function l(h){Y8(a.color)&&!h.inRange&&(h.inRange={color:a.color.slice().reverse()}),h.inRange=h.inRange||{color:t.get("gradientColor")}}function u(h,v,c){var p=h[v],d=h[c];p&&!d&&(d=h[c]={},Wb(p,function(g,y){if(oe.isValidType(y)){var m=HI.get(y,"inactive",s);m!=null&&(d[y]=m,y==="color"&&!d.hasOwnProperty("opacity")&&!d.hasOwnProperty("colorAlpha")&&(d.opacity=[0,0]))}}))}function f(h){var v=(h.inRange||{}).symbol||(h.outOfRange||{}).symbol,c=(h.inRange||{}).symbolSize||(h.outOfRange||{}).symbolSize,p=this.get("inactiveColor"),d=this.getItemSymbol(),g=d||"roundRect";Wb(this.stateList,function(y){var m=this.itemSize,_=h[y];_||(_=h[y]={color:s?p:[p]}),_.symbol==null&&(_.symbol=v&&rt(v)||(s?g:[g])),_.symbolSize==null&&(_.symbolSize=c&&rt(c)||(s?m[0]:[m[0],m[0]])),_.symbol=Hb(_.symbol,function(x){return x==="none"?g:x});var S=_.symbolSize;if(S!=null){var b=-1/0;U8(S,function(x){x>b&&(b=x)}),_.symbolSize=Hb(S,function(x){return X8(x,[0,b],[0,m[0]],!0)})}},this)}},e.prototype.resetItemSize=function(){this.itemSize=[parseFloat(this.get("itemWidth")),parseFloat(this.get("itemHeight"))]},e.prototype.isCategory=function(){return!!this.option.categories},e.prototype.setSelected=function(t){},e.prototype.getSelected=function(){return null},e.prototype.getValueState=function(t){return null},e.prototype.getVisualMeta=function(t){return null},e.type="visualMap",e.dependencies=["series"],e.defaultOption={show:!0,z:4,seriesIndex:"all",min:0,max:200,left:0,right:null,top:null,bottom:0,itemWidth:null,itemHeight:null,inverse:!1,orient:"vertical",backgroundColor:"rgba(0,0,0,0)",borderColor:"#ccc",contentColor:"#5793f3",inactiveColor:"#aaa",borderWidth:0,padding:5,textGap:10,precision:0,textStyle:{color:"#333"}},e})(_t),Ub=[20,140],Z8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.prototype.optionUpdated=function(t,a){r.prototype.optionUpdated.apply(this,arguments),this.resetExtent(),this.resetVisual(function(n){n.mappingMethod="linear",n.dataExtent=this.getExtent()}),this._resetRange()},e.prototype.resetItemSize=function(){r.prototype.resetItemSize.apply(this,arguments);var t=this.itemSize;(t[0]==null||isNaN(t[0]))&&(t[0]=Ub[0]),(t[1]==null||isNaN(t[1]))&&(t[1]=Ub[1])},e.prototype._resetRange=function(){var t=this.getExtent(),a=this.option.range;!a||a.auto?(t.auto=1,this.option.range=t):z(a)&&(a[0]>a[1]&&a.reverse(),a[0]=Math.max(a[0],t[0]),a[1]=Math.min(a[1],t[1]))},e.prototype.completeVisualOption=function(){r.prototype.completeVisualOption.apply(this,arguments),D(this.stateList,function(t){var a=this.option.controller[t].symbolSize;a&&a[0]!==a[1]&&(a[0]=a[1]/3)},this)},e.prototype.setSelected=function(t){this.option.range=t.slice(),this._resetRange()},e.prototype.getSelected=function(){var t=this.getExtent(),a=lr((this.get("range")||[]).slice());return a[0]>t[1]&&(a[0]=t[1]),a[1]>t[1]&&(a[1]=t[1]),a[0]<t[0]&&(a[0]=t[0]),a[1]<t[0]&&(a[1]=t[0]),a},e.prototype.getValueState=function(t){var a=this.option.range,n=this.getExtent();return(a[0]<=n[0]||a[0]<=t)&&(a[1]>=n[1]||t<=a[1])?"inRange":"outOfRange"},e.prototype.findTargetDataIndices=function(t){var a=[];return this.eachTargetSeries(function(n){var i=[],o=n.getData();o.each(this.getDataDimensionIndex(o),function(s,l){t[0]<=s&&s<=t[1]&&i.push(l)},this),a.push({seriesId:n.id,dataIndex:i})},this),a},e.prototype.getVisualMeta=function(t){var a=Yb(this,"outOfRange",this.getExtent()),n=Yb(this,"inRange",this.option.range.slice()),i=[];function o(c,p){i.push({value:c,color:t(c,p)})}for(var s=0,l=0,u=n.length,f=a.length;l<f&&(!n.length||a[l]<=n[0]);l++)a[l]<n[s]&&o(a[l],"outOfRange");for(var h=1;s<u;s++,h=0)h&&i.length&&o(n[s],"outOfRange"),o(n[s],"inRange");for(var h=1;l<f;l++)(!n.length||n[n.length-1]<a[l])&&(h&&(i.length&&o(i[i.length-1].value,"outOfRange"),h=0),o(a[l],"outOfRange"));var v=i.length;return{stops:i,outerColors:[v?i[0].color:"transparent",v?i[v-1].color:"transparent"]}},e.type="visualMap.continuous",e.defaultOption=nn(qf.defaultOption,{align:"auto",calculable:!1,hoverLink:!0,realtime:!0,handleIcon:"path://M-11.39,9.77h0a3.5,3.5,0,0,1-3.5,3.5h-22a3.5,3.5,0,0,1-3.5-3.5h0a3.5,3.5,0,0,1,3.5-3.5h22A3.5,3.5,0,0,1-11.39,9.77Z",handleSize:"120%",handleStyle:{borderColor:"#fff",borderWidth:1},indicatorIcon:"circle",indicatorSize:"50%",indicatorStyle:{borderColor:"#fff",borderWidth:2,shadowBlur:2,shadowOffsetX:1,shadowOffsetY:1,shadowColor:"rgba(0,0,0,0.2)"}}),e})(qf);function Yb(r,e,t){if(t[0]===t[1])return t.slice();for(var a=200,n=(t[1]-t[0])/a,i=t[0],o=[],s=0;s<=a&&i<t[1];s++)o.push(i),i+=n;return o.push(t[1]),o}var WI=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t.autoPositionValues={left:1,right:1,top:1,bottom:1},t}return e.prototype.init=function(t,a){this.ecModel=t,this.api=a},e.prototype.render=function(t,a,n,i){if(this.visualMapModel=t,t.get("show")===!1){this.group.removeAll();return}this.doRender(t,a,n,i)},e.prototype.renderBackground=function(t){var a=this.visualMapModel,n=Do(a.get("padding")||0),i=t.getBoundingRect();t.add(new xt({z2:-1,silent:!0,shape:{x:i.x-n[3],y:i.y-n[0],width:i.width+n[3]+n[1],height:i.height+n[0]+n[2]},style:{fill:a.get("backgroundColor"),stroke:a.get("borderColor"),lineWidth:a.get("borderWidth")}}))},e.prototype.getControllerVisual=function(t,a,n){n=n||{};var i=n.forceState,o=this.visualMapModel,s={};if(a==="color"){var l=o.get("contentColor");s.color=l}function u(c){return s[c]}function f(c,p){s[c]=p}var h=o.controllerVisuals[i||o.getValueState(t)],v=oe.prepareVisualTypes(h);return D(v,function(c){var p=h[c];n.convertOpacityToAlpha&&c==="opacity"&&(c="colorAlpha",p=h.__alphaForOpacity),oe.dependsOn(c,a)&&p&&p.applyVisual(t,u,f)}),s[a]},e.prototype.positionGroup=function(t){var a=this.visualMapModel,n=this.api;Th(t,a.getBoxLayoutParams(),{width:n.getWidth(),height:n.getHeight()})},e.prototype.doRender=function(t,a,n,i){},e.type="visualMap",e})(Ut),$b=[["left","right","width"],["top","bottom","height"]];function UI(r,e,t){var a=r.option,n=a.align;if(n!=null&&n!=="auto")return n;for(var i={width:e.getWidth(),height:e.getHeight()},o=a.orient==="horizontal"?1:0,s=$b[o],l=[0,null,10],u={},f=0;f<3;f++)u[$b[1-o][f]]=l[f],u[s[f]]=f===2?t[0]:a[s[f]];var h=[["x","width",3],["y","height",0]][o],v=ee(u,i,a.padding);return s[(v.margin[h[2]]||0)+v[h[0]]+v[h[1]]*.5<i[h[1]]*.5?0:1]}function nf(r,e){return D(r||[],function(t){t.dataIndex!=null&&(t.dataIndexInside=t.dataIndex,t.dataIndex=null),t.highlightKey="visualMap"+(e?e.componentIndex:"")}),r}var kr=It,q8=D,Xb=Math.min,lp=Math.max,K8=12,J8=6,Q8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t._shapes={},t._dataInterval=[],t._handleEnds=[],t._hoverLinkDataIndices=[],t}return e.prototype.init=function(t,a){r.prototype.init.call(this,t,a),this._hoverLinkFromSeriesMouseOver=$(this._hoverLinkFromSeriesMouseOver,this),this._hideIndicator=$(this._hideIndicator,this)},e.prototype.doRender=function(t,a,n,i){(!i||i.type!=="selectDataRange"||i.from!==this.uid)&&this._buildView()},e.prototype._buildView=function(){this.group.removeAll();var t=this.visualMapModel,a=this.group;this._orient=t.get("orient"),this._useHandle=t.get("calculable"),this._resetInterval(),this._renderBar(a);var n=t.get("text");this._renderEndsText(a,n,0),this._renderEndsText(a,n,1),this._updateView(!0),this.renderBackground(a),this._updateView(),this._enableHoverLinkToSeries(),this._enableHoverLinkFromSeries(),this.positionGroup(a)},e.prototype._renderEndsText=function(t,a,n){if(a){var i=a[1-n];i=i!=null?i+"":"";var o=this.visualMapModel,s=o.get("textGap"),l=o.itemSize,u=this._shapes.mainGroup,f=this._applyTransform([l[0]/2,n===0?-s:l[1]+s],u),h=this._applyTransform(n===0?"bottom":"top",u),v=this._orient,c=this.visualMapModel.textStyleModel;this.group.add(new wt({style:zt(c,{x:f[0],y:f[1],verticalAlign:v==="horizontal"?"middle":h,align:v==="horizontal"?h:"center",text:i})}))}},e.prototype._renderBar=function(t){var a=this.visualMapModel,n=this._shapes,i=a.itemSize,o=this._orient,s=this._useHandle,l=UI(a,this.api,i),u=n.mainGroup=this._createBarGroup(l),f=new at;u.add(f),f.add(n.outOfRange=Zb()),f.add(n.inRange=Zb(null,s?Kb(this._orient):null,$(this._dragHandle,this,"all",!1),$(this._dragHandle,this,"all",!0))),f.setClipPath(new xt({shape:{x:0,y:0,width:i[0],height:i[1],r:3}}));var h=a.textStyleModel.getTextRect("国"),v=lp(h.width,h.height);s&&(n.handleThumbs=[],n.handleLabels=[],n.handleLabelPoints=[],this._createHandle(a,u,0,i,v,o),this._createHandle(a,u,1,i,v,o)),this._createIndicator(a,u,i,v,o),t.add(u)},e.prototype._createHandle=function(t,a,n,i,o,s){var l=$(this._dragHandle,this,n,!1),u=$(this._dragHandle,this,n,!0),f=Tr(t.get("handleSize"),i[0]),h=Qt(t.get("handleIcon"),-f/2,-f/2,f,f,null,!0),v=Kb(this._orient);h.attr({cursor:v,draggable:!0,drift:l,ondragend:u,onmousemove:function(y){fa(y.event)}}),h.x=i[0]/2,h.useStyle(t.getModel("handleStyle").getItemStyle()),h.setStyle({strokeNoScale:!0,strokeFirst:!0}),h.style.lineWidth*=2,h.ensureState("emphasis").style=t.getModel(["emphasis","handleStyle"]).getItemStyle(),Un(h,!0),a.add(h);var c=this.visualMapModel.textStyleModel,p=new wt({cursor:v,draggable:!0,drift:l,onmousemove:function(y){fa(y.event)},ondragend:u,style:zt(c,{x:0,y:0,text:""})});p.ensureState("blur").style={opacity:.1},p.stateTransition={duration:200},this.group.add(p);var d=[f,0],g=this._shapes;g.handleThumbs[n]=h,g.handleLabelPoints[n]=d,g.handleLabels[n]=p},e.prototype._createIndicator=function(t,a,n,i,o){var s=Tr(t.get("indicatorSize"),n[0]),l=Qt(t.get("indicatorIcon"),-s/2,-s/2,s,s,null,!0);l.attr({cursor:"move",invisible:!0,silent:!0,x:n[0]/2});var u=t.getModel("indicatorStyle").getItemStyle();if(l instanceof he){var f=l.style;l.useStyle(V({image:f.image,x:f.x,y:f.y,width:f.width,height:f.height},u))}else l.useStyle(u);a.add(l);var h=this.visualMapModel.textStyleModel,v=new wt({silent:!0,invisible:!0,style:zt(h,{x:0,y:0,text:""})});this.group.add(v);var c=[(o==="horizontal"?i/2:J8)+n[0]/2,0],p=this._shapes;p.indicator=l,p.indicatorLabel=v,p.indicatorLabelPoint=c,this._firstShowIndicator=!0},e.prototype._dragHandle=function(t,a,n,i){if(this._useHandle){if(this._dragging=!a,!a){var o=this._applyTransform([n,i],this._shapes.mainGroup,!0);this._updateInterval(t,o[1]),this._hideIndicator(),this._updateView()}a===!this.visualMapModel.get("realtime")&&this.api.dispatchAction({type:"selectDataRange",from:this.uid,visualMapId:this.visualMapModel.id,selected:this._dataInterval.slice()}),a?!this._hovering&&this._clearHoverLinkToSeries():qb(this.visualMapModel)&&this._doHoverLinkToSeries(this._handleEnds[t],!1)}},e.prototype._resetInterval=function(){var t=this.visualMapModel,a=this._dataInterval=t.getSelected(),n=t.getExtent(),i=[0,t.itemSize[1]];this._handleEnds=[kr(a[0],n,i,!0),kr(a[1],n,i,!0)]},e.prototype._updateInterval=function(t,a){a=a||0;var n=this.visualMapModel,i=this._handleEnds,o=[0,n.itemSize[1]];xi(a,i,o,t,0);var s=n.getExtent();this._dataInterval=[kr(i[0],o,s,!0),kr(i[1],o,s,!0)]},e.prototype._updateView=function(t){var a=this.visualMapModel,n=a.getExtent(),i=this._shapes,o=[0,a.itemSize[1]],s=t?o:this._handleEnds,l=this._createBarVisual(this._dataInterval,n,s,"inRange"),u=this._createBarVisual(n,n,o,"outOfRange");i.inRange.setStyle({fill:l.barColor}).setShape("points",l.barPoints),i.outOfRange.setStyle({fill:u.barColor}).setShape("points",u.barPoints),this._updateHandle(s,l)},e.prototype._createBarVisual=function(t,a,n,i){var o={forceState:i,convertOpacityToAlpha:!0},s=this._makeColorGradient(t,o),l=[this.getControllerVisual(t[0],"symbolSize",o),this.getControllerVisual(t[1],"symbolSize",o)],u=this._createBarPoints(n,l);return{barColor:new Sl(0,0,0,1,s),barPoints:u,handlesColor:[s[0].color,s[s.length-1].color]}},e.prototype._makeColorGradient=function(t,a){var n=100,i=[],o=(t[1]-t[0])/n;i.push({color:this.getControllerVisual(t[0],"color",a),offset:0});for(var s=1;s<n;s++){var l=t[0]+o*s;if(l>t[1])break;i.push({color:this.getControllerVisual(l,"color",a),offset:s/n})}return i.push({color:this.getControllerVisual(t[1],"color",a),offset:1}),i},e.prototype._createBarPoints=function(t,a){var n=this.visualMapModel.itemSize;return[[n[0]-a[0],t[0]],[n[0],t[0]],[n[0],t[1]],[n[0]-a[1],t[1]]]},e.prototype._createBarGroup=function(t){var a=this._orient,n=this.visualMapModel.get("inverse");return new at(a==="horizontal"&&!n?{scaleX:t==="bottom"?1:-1,rotation:Math.PI/2}:a==="horizontal"&&n?{scaleX:t==="bottom"?-1:1,rotation:-Math.PI/2}:a==="vertical"&&!n?{scaleX:t==="left"?1:-1,scaleY:-1}:{scaleX:t==="left"?1:-1})},e.prototype._updateHandle=function(t,a){if(this._useHandle){var n=this._shapes,i=this.visualMapModel,o=n.handleThumbs,s=n.handleLabels,l=i.itemSize,u=i.getExtent(),f=this._applyTransform("left",n.mainGroup);q8([0,1],function(h){var v=o[h];v.setStyle("fill",a.handlesColor[h]),v.y=t[h];var c=kr(t[h],[0,l[1]],u,!0),p=this.getControllerVisual(c,"symbolSize");v.scaleX=v.scaleY=p/l[0],v.x=l[0]-p/2;var d=wr(n.handleLabelPoints[h],ei(v,this.group));if(this._orient==="horizontal"){var g=f==="left"||f==="top"?(l[0]-p)/2:(l[0]-p)/-2;d[1]+=g}s[h].setStyle({x:d[0],y:d[1],text:i.formatValueText(this._dataInterval[h]),verticalAlign:"middle",align:this._orient==="vertical"?this._applyTransform("left",n.mainGroup):"center"})},this)}},e.prototype._showIndicator=function(t,a,n,i){var o=this.visualMapModel,s=o.getExtent(),l=o.itemSize,u=[0,l[1]],f=this._shapes,h=f.indicator;if(h){h.attr("invisible",!1);var v={convertOpacityToAlpha:!0},c=this.getControllerVisual(t,"color",v),p=this.getControllerVisual(t,"symbolSize"),d=kr(t,s,u,!0),g=l[0]-p/2,y={x:h.x,y:h.y};h.y=d,h.x=g;var m=wr(f.indicatorLabelPoint,ei(h,this.group)),_=f.indicatorLabel;_.attr("invisible",!1);var S=this._applyTransform("left",f.mainGroup),b=this._orient,x=b==="horizontal";_.setStyle({text:(n||"")+o.formatValueText(a),verticalAlign:x?S:"middle",align:x?"center":S});var w={x:g,y:d,style:{fill:c}},T={style:{x:m[0],y:m[1]}};if(o.ecModel.isAnimationEnabled()&&!this._firstShowIndicator){var A={duration:100,easing:"cubicInOut",additive:!0};h.x=y.x,h.y=y.y,h.animateTo(w,A),_.animateTo(T,A)}else h.attr(w),_.attr(T);this._firstShowIndicator=!1;var C=this._shapes.handleLabels;if(C)for(var M=0;M<C.length;M++)this.api.enterBlur(C[M])}},e.prototype._enableHoverLinkToSeries=function(){var t=this;this._shapes.mainGroup.on("mousemove",function(a){if(t._hovering=!0,!t._dragging){var n=t.visualMapModel.itemSize,i=t._applyTransform([a.offsetX,a.offsetY],t._shapes.mainGroup,!0,!0);i[1]=Xb(lp(0,i[1]),n[1]),t._doHoverLinkToSeries(i[1],0<=i[0]&&i[0]<=n[0])}}).on("mouseout",function(){t._hovering=!1,!t._dragging&&t._clearHoverLinkToSeries()})},e.prototype._enableHoverLinkFromSeries=function(){var t=this.api.getZr();this.visualMapModel.option.hoverLink?(t.on("mouseover",this._hoverLinkFromSeriesMouseOver,this),t.on("mouseout",this._hideIndicator,this)):this._clearHoverLinkFromSeries()},e.prototype._doHoverLinkToSeries=function(t,a){var n=this.visualMapModel,i=n.itemSize;if(n.option.hoverLink){var o=[0,i[1]],s=n.getExtent();t=Xb(lp(o[0],t),o[1]);var l=j8(n,s,o),u=[t-l,t+l],f=kr(t,o,s,!0),h=[kr(u[0],o,s,!0),kr(u[1],o,s,!0)];u[0]<o[0]&&(h[0]=-1/0),u[1]>o[1]&&(h[1]=1/0),a&&(h[0]===-1/0?this._showIndicator(f,h[1],"< ",l):h[1]===1/0?this._showIndicator(f,h[0],"> ",l):this._showIndicator(f,f,"≈ ",l));var v=this._hoverLinkDataIndices,c=[];(a||qb(n))&&(c=this._hoverLinkDataIndices=n.findTargetDataIndices(h));var p=oP(v,c);this._dispatchHighDown("downplay",nf(p[0],n)),this._dispatchHighDown("highlight",nf(p[1],n))}},e.prototype._hoverLinkFromSeriesMouseOver=function(t){var a;if($n(t.target,function(l){var u=nt(l);if(u.dataIndex!=null)return a=u,!0},!0),!!a){var n=this.ecModel.getSeriesByIndex(a.seriesIndex),i=this.visualMapModel;if(i.isTargetSeries(n)){var o=n.getData(a.dataType),s=o.getStore().get(i.getDataDimensionIndex(o),a.dataIndex);isNaN(s)||this._showIndicator(s,s)}}},e.prototype._hideIndicator=function(){var t=this._shapes;t.indicator&&t.indicator.attr("invisible",!0),t.indicatorLabel&&t.indicatorLabel.attr("invisible",!0);var a=this._shapes.handleLabels;if(a)for(var n=0;n<a.length;n++)this.api.leaveBlur(a[n])},e.prototype._clearHoverLinkToSeries=function(){this._hideIndicator();var t=this._hoverLinkDataIndices;this._dispatchHighDown("downplay",nf(t,this.visualMapModel)),t.length=0},e.prototype._clearHoverLinkFromSeries=function(){this._hideIndicator();var t=this.api.getZr();t.off("mouseover",this._hoverLinkFromSeriesMouseOver),t.off("mouseout",this._hideIndicator)},e.prototype._applyTransform=function(t,a,n,i){var o=ei(a,i?null:this.group);return z(t)?wr(t,o,n):yh(t,o,n)},e.prototype._dispatchHighDown=function(t,a){a&&a.length&&this.api.dispatchAction({type:t,batch:a})},e.prototype.dispose=function(){this._clearHoverLinkFromSeries(),this._clearHoverLinkToSeries()},e.type="visualMap.continuous",e})(WI);function Zb(r,e,t,a){return new Re({shape:{points:r},draggable:!!t,cursor:e,drift:t,onmousemove:function(n){fa(n.event)},ondragend:a})}function j8(r,e,t){var a=K8/2,n=r.get("hoverLinkDataSize");return n&&(a=kr(n,e,t,!0)/2),a}function qb(r){var e=r.get("hoverLinkOnHandle");return!!(e??r.get("realtime"))}function Kb(r){return r==="vertical"?"ns-resize":"ew-resize"}var tY={type:"selectDataRange",event:"dataRangeSelected",update:"update"},eY=function(r,e){e.eachComponent({mainType:"visualMap",query:r},function(t){t.setSelected(r.selected)})},rY=[{createOnAllSeries:!0,reset:function(r,e){var t=[];return e.eachComponent("visualMap",function(a){var n=r.pipelineContext;!a.isTargetSeries(r)||n&&n.large||t.push(TU(a.stateList,a.targetVisuals,$(a.getValueState,a),a.getDataDimensionIndex(r.getData())))}),t}},{createOnAllSeries:!0,reset:function(r,e){var t=r.getData(),a=[];e.eachComponent("visualMap",function(n){if(n.isTargetSeries(r)){var i=n.getVisualMeta($(aY,null,r,n))||{stops:[],outerColors:[]},o=n.getDataDimensionIndex(t);o>=0&&(i.dimension=o,a.push(i))}}),r.getData().setVisual("visualMeta",a)}}];function aY(r,e,t,a){for(var n=e.targetVisuals[a],i=oe.prepareVisualTypes(n),o={color:Tl(r.getData(),"color")},s=0,l=i.length;s<l;s++){var u=i[s],f=n[u==="opacity"?"__alphaForOpacity":u];f&&f.applyVisual(t,h,v)}return o.color;function h(c){return o[c]}function v(c,p){o[c]=p}}var Jb=D;function nY(r){var e=r&&r.visualMap;z(e)||(e=e?[e]:[]),Jb(e,function(t){if(t){Hi(t,"splitList")&&!Hi(t,"pieces")&&(t.pieces=t.splitList,delete t.splitList);var a=t.pieces;a&&z(a)&&Jb(a,function(n){et(n)&&(Hi(n,"start")&&!Hi(n,"min")&&(n.min=n.start),Hi(n,"end")&&!Hi(n,"max")&&(n.max=n.end))})}})}function Hi(r,e){return r&&r.hasOwnProperty&&r.hasOwnProperty(e)}var Qb=!1;function YI(r){Qb||(Qb=!0,r.registerSubTypeDefaulter("visualMap",function(e){return!e.categories&&(!(e.pieces?e.pieces.length>0:e.splitNumber>0)||e.calculable)?"continuous":"piecewise"}),r.registerAction(tY,eY),D(rY,function(e){r.registerVisual(r.PRIORITY.VISUAL.COMPONENT,e)}),r.registerPreprocessor(nY))}function $I(r){r.registerComponentModel(Z8),r.registerComponentView(Q8),YI(r)}var iY=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t._pieceList=[],t}return e.prototype.optionUpdated=function(t,a){r.prototype.optionUpdated.apply(this,arguments),this.resetExtent();var n=this._mode=this._determineMode();this._pieceList=[],oY[this._mode].call(this,this._pieceList),this._resetSelected(t,a);var i=this.option.categories;this.resetVisual(function(o,s){n==="categories"?(o.mappingMethod="category",o.categories=rt(i)):(o.dataExtent=this.getExtent(),o.mappingMethod="piecewise",o.pieceList=G(this._pieceList,function(l){return l=rt(l),s!=="inRange"&&(l.visual=null),l}))})},e.prototype.completeVisualOption=function(){var t=this.option,a={},n=oe.listVisualTypes(),i=this.isCategory();D(t.pieces,function(s){D(n,function(l){s.hasOwnProperty(l)&&(a[l]=1)})}),D(a,function(s,l){var u=!1;D(this.stateList,function(f){u=u||o(t,f,l)||o(t.target,f,l)},this),!u&&D(this.stateList,function(f){(t[f]||(t[f]={}))[l]=HI.get(l,f==="inRange"?"active":"inactive",i)})},this);function o(s,l,u){return s&&s[l]&&s[l].hasOwnProperty(u)}r.prototype.completeVisualOption.apply(this,arguments)},e.prototype._resetSelected=function(t,a){var n=this.option,i=this._pieceList,o=(a?n:t).selected||{};if(n.selected=o,D(i,function(l,u){var f=this.getSelectedMapKey(l);o.hasOwnProperty(f)||(o[f]=!0)},this),n.selectedMode==="single"){var s=!1;D(i,function(l,u){var f=this.getSelectedMapKey(l);o[f]&&(s?o[f]=!1:s=!0)},this)}},e.prototype.getItemSymbol=function(){return this.get("itemSymbol")},e.prototype.getSelectedMapKey=function(t){return this._mode==="categories"?t.value+"":t.index+""},e.prototype.getPieceList=function(){return this._pieceList},e.prototype._determineMode=function(){var t=this.option;return t.pieces&&t.pieces.length>0?"pieces":this.option.categories?"categories":"splitNumber"},e.prototype.setSelected=function(t){this.option.selected=rt(t)},e.prototype.getValueState=function(t){var a=oe.findPieceIndex(t,this._pieceList);return a!=null&&this.option.selected[this.getSelectedMapKey(this._pieceList[a])]?"inRange":"outOfRange"},e.prototype.findTargetDataIndices=function(t){var a=[],n=this._pieceList;return this.eachTargetSeries(function(i){var o=[],s=i.getData();s.each(this.getDataDimensionIndex(s),function(l,u){var f=oe.findPieceIndex(l,n);f===t&&o.push(u)},this),a.push({seriesId:i.id,dataIndex:o})},this),a},e.prototype.getRepresentValue=function(t){var a;if(this.isCategory())a=t.value;else if(t.value!=null)a=t.value;else{var n=t.interval||[];a=n[0]===-1/0&&n[1]===1/0?0:(n[0]+n[1])/2}return a},e.prototype.getVisualMeta=function(t){if(this.isCategory())return;var a=[],n=["",""],i=this;function o(f,h){var v=i.getRepresentValue({interval:f});h||(h=i.getValueState(v));var c=t(v,h);f[0]===-1/0?n[0]=c:f[1]===1/0?n[1]=c:a.push({value:f[0],color:c},{value:f[1],color:c})}var s=this._pieceList.slice();if(!s.length)s.push({interval:[-1/0,1/0]});else{var l=s[0].interval[0];l!==-1/0&&s.unshift({interval:[-1/0,l]}),l=s[s.length-1].interval[1],l!==1/0&&s.push({interval:[l,1/0]})}var u=-1/0;return D(s,function(f){var h=f.interval;h&&(h[0]>u&&o([u,h[0]],"outOfRange"),o(h.slice()),u=h[1])},this),{stops:a,outerColors:n}},e.type="visualMap.piecewise",e.defaultOption=nn(qf.defaultOption,{selected:null,minOpen:!1,maxOpen:!1,align:"auto",itemWidth:20,itemHeight:14,itemSymbol:"roundRect",pieces:null,categories:null,splitNumber:5,selectedMode:"multiple",itemGap:10,hoverLink:!0}),e})(qf),oY={splitNumber:function(r){var e=this.option,t=Math.min(e.precision,20),a=this.getExtent(),n=e.splitNumber;n=Math.max(parseInt(n,10),1),e.splitNumber=n;for(var i=(a[1]-a[0])/n;+i.toFixed(t)!==i&&t<5;)t++;e.precision=t,i=+i.toFixed(t),e.minOpen&&r.push({interval:[-1/0,a[0]],close:[0,0]});for(var o=0,s=a[0];o<n;s+=i,o++){var l=o===n-1?a[1]:s+i;r.push({interval:[s,l],close:[1,1]})}e.maxOpen&&r.push({interval:[a[1],1/0],close:[0,0]}),Um(r),D(r,function(u,f){u.index=f,u.text=this.formatValueText(u.interval)},this)},categories:function(r){var e=this.option;D(e.categories,function(t){r.push({text:this.formatValueText(t,!0),value:t})},this),jb(e,r)},pieces:function(r){var e=this.option;D(e.pieces,function(t,a){et(t)||(t={value:t});var n={text:"",index:a};if(t.label!=null&&(n.text=t.label),t.hasOwnProperty("value")){var i=n.value=t.value;n.interval=[i,i],n.close=[1,1]}else{for(var o=n.interval=[],s=n.close=[0,0],l=[1,0,1],u=[-1/0,1/0],f=[],h=0;h<2;h++){for(var v=[["gte","gt","min"],["lte","lt","max"]][h],c=0;c<3&&o[h]==null;c++)o[h]=t[v[c]],s[h]=l[c],f[h]=c===2;o[h]==null&&(o[h]=u[h])}f[0]&&o[1]===1/0&&(s[0]=0),f[1]&&o[0]===-1/0&&(s[1]=0),o[0]===o[1]&&s[0]&&s[1]&&(n.value=o[0])}n.visual=oe.retrieveVisuals(t),r.push(n)},this),jb(e,r),Um(r),D(r,function(t){var a=t.close,n=[["<","≤"][a[1]],[">","≥"][a[0]]];t.text=t.text||this.formatValueText(t.value!=null?t.value:t.interval,!1,n)},this)}};function jb(r,e){var t=r.inverse;(r.orient==="vertical"?!t:t)&&e.reverse()}var sY=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.prototype.doRender=function(){var t=this.group;t.removeAll();var a=this.visualMapModel,n=a.get("textGap"),i=a.textStyleModel,o=i.getFont(),s=i.getTextColor(),l=this._getItemAlign(),u=a.itemSize,f=this._getViewData(),h=f.endsText,v=fe(a.get("showLabel",!0),!h),c=!a.get("selectedMode");h&&this._renderEndsText(t,h[0],u,v,l),D(f.viewPieceList,function(p){var d=p.piece,g=new at;g.onclick=$(this._onItemClick,this,d),this._enableHoverLink(g,p.indexInModelPieceList);var y=a.getRepresentValue(d);if(this._createItemSymbol(g,y,[0,0,u[0],u[1]],c),v){var m=this.visualMapModel.getValueState(y);g.add(new wt({style:{x:l==="right"?-n:u[0]+n,y:u[1]/2,text:d.text,verticalAlign:"middle",align:l,font:o,fill:s,opacity:m==="outOfRange"?.5:1},silent:c}))}t.add(g)},this),h&&this._renderEndsText(t,h[1],u,v,l),ri(a.get("orient"),t,a.get("itemGap")),this.renderBackground(t),this.positionGroup(t)},e.prototype._enableHoverLink=function(t,a){var n=this;t.on("mouseover",function(){return i("highlight")}).on("mouseout",function(){return i("downplay")});var i=function(o){var s=n.visualMapModel;s.option.hoverLink&&n.api.dispatchAction({type:o,batch:nf(s.findTargetDataIndices(a),s)})}},e.prototype._getItemAlign=function(){var t=this.visualMapModel,a=t.option;if(a.orient==="vertical")return UI(t,this.api,t.itemSize);var n=a.align;return(!n||n==="auto")&&(n="left"),n},e.prototype._renderEndsText=function(t,a,n,i,o){if(a){var s=new at,l=this.visualMapModel.textStyleModel;s.add(new wt({style:zt(l,{x:i?o==="right"?n[0]:0:n[0]/2,y:n[1]/2,verticalAlign:"middle",align:i?o:"center",text:a})})),t.add(s)}},e.prototype._getViewData=function(){var t=this.visualMapModel,a=G(t.getPieceList(),function(s,l){return{piece:s,indexInModelPieceList:l}}),n=t.get("text"),i=t.get("orient"),o=t.get("inverse");return(i==="horizontal"?o:!o)?a.reverse():n&&(n=n.slice().reverse()),{viewPieceList:a,endsText:n}},e.prototype._createItemSymbol=function(t,a,n,i){var o=Qt(this.getControllerVisual(a,"symbol"),n[0],n[1],n[2],n[3],this.getControllerVisual(a,"color"));o.silent=i,t.add(o)},e.prototype._onItemClick=function(t){var a=this.visualMapModel,n=a.option,i=n.selectedMode;if(i){var o=rt(n.selected),s=a.getSelectedMapKey(t);i==="single"||i===!0?(o[s]=!0,D(o,function(l,u){o[u]=u===s})):o[s]=!o[s],this.api.dispatchAction({type:"selectDataRange",from:this.uid,visualMapId:this.visualMapModel.id,selected:o})}},e.type="visualMap.piecewise",e})(WI);function XI(r){r.registerComponentModel(iY),r.registerComponentView(sY),YI(r)}function lY(r){gt($I),gt(XI)}var uY={label:{enabled:!0},decal:{show:!1}},tw=bt(),fY={};function hY(r,e){var t=r.getModel("aria");if(!t.get("enabled"))return;var a=rt(uY);ft(a.label,r.getLocaleModel().get("aria"),!1),ft(t.option,a,!1),n(),i();function n(){var u=t.getModel("decal"),f=u.get("show");if(f){var h=Z();r.eachSeries(function(v){if(!v.isColorBySeries()){var c=h.get(v.type);c||(c={},h.set(v.type,c)),tw(v).scope=c}}),r.eachRawSeries(function(v){if(r.isSeriesFiltered(v))return;if(J(v.enableAriaDecal)){v.enableAriaDecal();return}var c=v.getData();if(v.isColorBySeries()){var m=Qp(v.ecModel,v.name,fY,r.getSeriesCount()),_=c.getVisual("decal");c.setVisual("decal",S(_,m))}else{var p=v.getRawData(),d={},g=tw(v).scope;c.each(function(b){var x=c.getRawIndex(b);d[x]=b});var y=p.count();p.each(function(b){var x=d[b],w=p.getName(b)||b+"",T=Qp(v.ecModel,w,g,y),A=c.getItemVisual(x,"decal");c.setItemVisual(x,"decal",S(A,T))})}function S(b,x){var w=b?V(V({},x),b):x;return w.dirty=!0,w}})}}function i(){var u=e.getZr().dom;if(u){var f=r.getLocaleModel().get("aria"),h=t.getModel("label");if(h.option=j(h.option,f),!!h.get("enabled")){if(u.setAttribute("role","img"),h.get("description")){u.setAttribute("aria-label",h.get("description"));return}var v=r.getSeriesCount(),c=h.get(["data","maxCount"])||10,p=h.get(["series","maxCount"])||10,d=Math.min(v,p),g;if(!(v<1)){var y=s();if(y){var m=h.get(["general","withTitle"]);g=o(m,{title:y})}else g=h.get(["general","withoutTitle"]);var _=[],S=v>1?h.get(["series","multiple","prefix"]):h.get(["series","single","prefix"]);g+=o(S,{seriesCount:v}),r.eachSeries(function(T,A){if(A<d){var C=void 0,M=T.get("name"),I=M?"withName":"withoutName";C=v>1?h.get(["series","multiple",I]):h.get(["series","single",I]),C=o(C,{seriesId:T.seriesIndex,seriesName:T.get("name"),seriesType:l(T.subType)});var L=T.getData();if(L.count()>c){var P=h.get(["data","partialData"]);C+=o(P,{displayCnt:c})}else C+=h.get(["data","allData"]);for(var R=h.get(["data","separator","middle"]),E=h.get(["data","separator","end"]),O=h.get(["data","excludeDimensionId"]),k=[],B=0;B<L.count();B++)if(B<c){var F=L.getName(B),H=O?Mt(L.getValues(B),function(K,Q){return pt(O,Q)===-1}):L.getValues(B),U=h.get(["data",F?"withName":"withoutName"]);k.push(o(U,{name:F,value:H.join(R)}))}C+=k.join(R)+E,_.push(C)}});var b=h.getModel(["series","multiple","separator"]),x=b.get("middle"),w=b.get("end");g+=_.join(x)+w,u.setAttribute("aria-label",g)}}}}function o(u,f){if(!Y(u))return u;var h=u;return D(f,function(v,c){h=h.replace(new RegExp("\\{\\s*"+c+"\\s*\\}","g"),v)}),h}function s(){var u=r.get("title");return u&&u.length&&(u=u[0]),u&&u.text}function l(u){var f=r.getLocaleModel().get(["series","typeNames"]);return f[u]||f.chart}}function vY(r){if(!(!r||!r.aria)){var e=r.aria;e.show!=null&&(e.enabled=e.show),e.label=e.label||{},D(["description","general","series","data"],function(t){e[t]!=null&&(e.label[t]=e[t])})}}function cY(r){r.registerPreprocessor(vY),r.registerVisual(r.PRIORITY.VISUAL.ARIA,hY)}var ew={value:"eq","<":"lt","<=":"lte",">":"gt",">=":"gte","=":"eq","!=":"ne","<>":"ne"},pY=(function(){function r(e){var t=this._condVal=Y(e)?new RegExp(e):CL(e)?e:null;if(t==null){var a="";Dt(a)}}return r.prototype.evaluate=function(e){var t=typeof e;return Y(t)?this._condVal.test(e):At(t)?this._condVal.test(e+""):!1},r})(),dY=(function(){function r(){}return r.prototype.evaluate=function(){return this.value},r})(),gY=(function(){function r(){}return r.prototype.evaluate=function(){for(var e=this.children,t=0;t<e.length;t++)if(!e[t].evaluate())return!1;return!0},r})(),yY=(function(){function r(){}return r.prototype.evaluate=function(){for(var e=this.children,t=0;t<e.length;t++)if(e[t].evaluate())return!0;return!1},r})(),mY=(function(){function r(){}return r.prototype.evaluate=function(){return!this.child.evaluate()},r})(),_Y=(function(){function r(){}return r.prototype.evaluate=function(){for(var e=!!this.valueParser,t=this.getValue,a=t(this.valueGetterParam),n=e?this.valueParser(a):null,i=0;i<this.subCondList.length;i++)if(!this.subCondList[i].evaluate(e?n:a))return!1;return!0},r})();function fm(r,e){if(r===!0||r===!1){var t=new dY;return t.value=r,t}var a="";return ZI(r)||Dt(a),r.and?rw("and",r,e):r.or?rw("or",r,e):r.not?SY(r,e):xY(r,e)}function rw(r,e,t){var a=e[r],n="";z(a)||Dt(n),a.length||Dt(n);var i=r==="and"?new gY:new yY;return i.children=G(a,function(o){return fm(o,t)}),i.children.length||Dt(n),i}function SY(r,e){var t=r.not,a="";ZI(t)||Dt(a);var n=new mY;return n.child=fm(t,e),n.child||Dt(a),n}function xY(r,e){for(var t="",a=e.prepareGetValue(r),n=[],i=St(r),o=r.parser,s=o?IA(o):null,l=0;l<i.length;l++){var u=i[l];if(!(u==="parser"||e.valueGetterAttrMap.get(u))){var f=X(ew,u)?ew[u]:u,h=r[u],v=s?s(h):h,c=uk(f,v)||f==="reg"&&new pY(v);c||Dt(t),n.push(c)}}n.length||Dt(t);var p=new _Y;return p.valueGetterParam=a,p.valueParser=s,p.getValue=e.getValue,p.subCondList=n,p}function ZI(r){return et(r)&&!Se(r)}var bY=(function(){function r(e,t){this._cond=fm(e,t)}return r.prototype.evaluate=function(){return this._cond.evaluate()},r})();function wY(r,e){return new bY(r,e)}var TY={type:"echarts:filter",transform:function(r){for(var e=r.upstream,t,a=wY(r.config,{valueGetterAttrMap:Z({dimension:!0}),prepareGetValue:function(s){var l="",u=s.dimension;X(s,"dimension")||Dt(l);var f=e.getDimensionInfo(u);return f||Dt(l),{dimIdx:f.index}},getValue:function(s){return e.retrieveValueFromItem(t,s.dimIdx)}}),n=[],i=0,o=e.count();i<o;i++)t=e.getRawDataItem(i),a.evaluate()&&n.push(t);return{data:n}}},AY={type:"echarts:sort",transform:function(r){var e=r.upstream,t=r.config,a="",n=Et(t);n.length||Dt(a);var i=[];D(n,function(f){var h=f.dimension,v=f.order,c=f.parser,p=f.incomparable;if(h==null&&Dt(a),v!=="asc"&&v!=="desc"&&Dt(a),p&&p!=="min"&&p!=="max"){var d="";Dt(d)}if(v!=="asc"&&v!=="desc"){var g="";Dt(g)}var y=e.getDimensionInfo(h);y||Dt(a);var m=c?IA(c):null;c&&!m&&Dt(a),i.push({dimIdx:y.index,parser:m,comparator:new PA(v,p)})});var o=e.sourceFormat;o!==xe&&o!==cr&&Dt(a);for(var s=[],l=0,u=e.count();l<u;l++)s.push(e.getRawDataItem(l));return s.sort(function(f,h){for(var v=0;v<i.length;v++){var c=i[v],p=e.retrieveValueFromItem(f,c.dimIdx),d=e.retrieveValueFromItem(h,c.dimIdx);c.parser&&(p=c.parser(p),d=c.parser(d));var g=c.comparator.evaluate(p,d);if(g!==0)return g}return 0}),{data:s}}};function CY(r){r.registerTransform(TY),r.registerTransform(AY)}var DY=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type="dataset",t}return e.prototype.init=function(t,a,n){r.prototype.init.call(this,t,a,n),this._sourceManager=new OA(this),r_(this)},e.prototype.mergeOption=function(t,a){r.prototype.mergeOption.call(this,t,a),r_(this)},e.prototype.optionUpdated=function(){this._sourceManager.dirty()},e.prototype.getSourceManager=function(){return this._sourceManager},e.type="dataset",e.defaultOption={seriesLayoutBy:Wr},e})(_t),MY=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type="dataset",t}return e.type="dataset",e})(Ut);function IY(r){r.registerComponentModel(DY),r.registerComponentView(MY)}var Rr=Zr.CMD;function ji(r,e){return Math.abs(r-e)<1e-5}function jd(r){var e=r.data,t=r.len(),a=[],n,i=0,o=0,s=0,l=0;function u(L,P){n&&n.length>2&&a.push(n),n=[L,P]}function f(L,P,R,E){ji(L,R)&&ji(P,E)||n.push(L,P,R,E,R,E)}function h(L,P,R,E,O,k){var B=Math.abs(P-L),F=Math.tan(B/4)*4/3,H=P<L?-1:1,U=Math.cos(L),K=Math.sin(L),Q=Math.cos(P),it=Math.sin(P),Rt=U*O+R,$t=K*k+E,ct=Q*O+R,tt=it*k+E,dt=O*F*H,q=k*F*H;n.push(Rt-dt*K,$t+q*U,ct+dt*it,tt-q*Q,ct,tt)}for(var v,c,p,d,g=0;g<t;){var y=e[g++],m=g===1;switch(m&&(i=e[g],o=e[g+1],s=i,l=o,(y===Rr.L||y===Rr.C||y===Rr.Q)&&(n=[s,l])),y){case Rr.M:i=s=e[g++],o=l=e[g++],u(s,l);break;case Rr.L:v=e[g++],c=e[g++],f(i,o,v,c),i=v,o=c;break;case Rr.C:n.push(e[g++],e[g++],e[g++],e[g++],i=e[g++],o=e[g++]);break;case Rr.Q:v=e[g++],c=e[g++],p=e[g++],d=e[g++],n.push(i+2/3*(v-i),o+2/3*(c-o),p+2/3*(v-p),d+2/3*(c-d),p,d),i=p,o=d;break;case Rr.A:var _=e[g++],S=e[g++],b=e[g++],x=e[g++],w=e[g++],T=e[g++]+w;g+=1;var A=!e[g++];v=Math.cos(w)*b+_,c=Math.sin(w)*x+S,m?(s=v,l=c,u(s,l)):f(i,o,v,c),i=Math.cos(T)*b+_,o=Math.sin(T)*x+S;for(var C=(A?-1:1)*Math.PI/2,M=w;A?M>T:M<T;M+=C){var I=A?Math.max(M+C,T):Math.min(M+C,T);h(M,I,_,S,b,x)}break;case Rr.R:s=i=e[g++],l=o=e[g++],v=s+e[g++],c=l+e[g++],u(v,l),f(v,l,v,c),f(v,c,s,c),f(s,c,s,l),f(s,l,v,l);break;case Rr.Z:n&&f(i,o,s,l),i=s,o=l;break}}return n&&n.length>2&&a.push(n),a}function tg(r,e,t,a,n,i,o,s,l,u){if(ji(r,t)&&ji(e,a)&&ji(n,o)&&ji(i,s)){l.push(o,s);return}var f=2/u,h=f*f,v=o-r,c=s-e,p=Math.sqrt(v*v+c*c);v/=p,c/=p;var d=t-r,g=a-e,y=n-o,m=i-s,_=d*d+g*g,S=y*y+m*m;if(_<h&&S<h){l.push(o,s);return}var b=v*d+c*g,x=-v*y-c*m,w=_-b*b,T=S-x*x;if(w<h&&b>=0&&T<h&&x>=0){l.push(o,s);return}var A=[],C=[];Ja(r,t,n,o,.5,A),Ja(e,a,i,s,.5,C),tg(A[0],C[0],A[1],C[1],A[2],C[2],A[3],C[3],l,u),tg(A[4],C[4],A[5],C[5],A[6],C[6],A[7],C[7],l,u)}function LY(r,e){var t=jd(r),a=[];e=e||1;for(var n=0;n<t.length;n++){var i=t[n],o=[],s=i[0],l=i[1];o.push(s,l);for(var u=2;u<i.length;){var f=i[u++],h=i[u++],v=i[u++],c=i[u++],p=i[u++],d=i[u++];tg(s,l,f,h,v,c,p,d,o,e),s=p,l=d}a.push(o)}return a}function qI(r,e,t){var a=r[e],n=r[1-e],i=Math.abs(a/n),o=Math.ceil(Math.sqrt(i*t)),s=Math.floor(t/o);s===0&&(s=1,o=t);for(var l=[],u=0;u<o;u++)l.push(s);var f=o*s,h=t-f;if(h>0)for(var u=0;u<h;u++)l[u%o]+=1;return l}function aw(r,e,t){for(var a=r.r0,n=r.r,i=r.startAngle,o=r.endAngle,s=Math.abs(o-i),l=s*n,u=n-a,f=l>Math.abs(u),h=qI([l,u],f?0:1,e),v=(f?s:u)/h.length,c=0;c<h.length;c++)for(var p=(f?u:s)/h[c],d=0;d<h[c];d++){var g={};f?(g.startAngle=i+v*c,g.endAngle=i+v*(c+1),g.r0=a+p*d,g.r=a+p*(d+1)):(g.startAngle=i+p*d,g.endAngle=i+p*(d+1),g.r0=a+v*c,g.r=a+v*(c+1)),g.clockwise=r.clockwise,g.cx=r.cx,g.cy=r.cy,t.push(g)}}function PY(r,e,t){for(var a=r.width,n=r.height,i=a>n,o=qI([a,n],i?0:1,e),s=i?"width":"height",l=i?"height":"width",u=i?"x":"y",f=i?"y":"x",h=r[s]/o.length,v=0;v<o.length;v++)for(var c=r[l]/o[v],p=0;p<o[v];p++){var d={};d[u]=v*h,d[f]=p*c,d[s]=h,d[l]=c,d.x+=r.x,d.y+=r.y,t.push(d)}}function nw(r,e,t,a){return r*a-t*e}function RY(r,e,t,a,n,i,o,s){var l=t-r,u=a-e,f=o-n,h=s-i,v=nw(f,h,l,u);if(Math.abs(v)<1e-6)return null;var c=r-n,p=e-i,d=nw(c,p,f,h)/v;return d<0||d>1?null:new ht(d*l+r,d*u+e)}function EY(r,e,t){var a=new ht;ht.sub(a,t,e),a.normalize();var n=new ht;ht.sub(n,r,e);var i=n.dot(a);return i}function Wi(r,e){var t=r[r.length-1];t&&t[0]===e[0]&&t[1]===e[1]||r.push(e)}function kY(r,e,t){for(var a=r.length,n=[],i=0;i<a;i++){var o=r[i],s=r[(i+1)%a],l=RY(o[0],o[1],s[0],s[1],e.x,e.y,t.x,t.y);l&&n.push({projPt:EY(l,e,t),pt:l,idx:i})}if(n.length<2)return[{points:r},{points:r}];n.sort(function(g,y){return g.projPt-y.projPt});var u=n[0],f=n[n.length-1];if(f.idx<u.idx){var h=u;u=f,f=h}for(var v=[u.pt.x,u.pt.y],c=[f.pt.x,f.pt.y],p=[v],d=[c],i=u.idx+1;i<=f.idx;i++)Wi(p,r[i].slice());Wi(p,c),Wi(p,v);for(var i=f.idx+1;i<=u.idx+a;i++)Wi(d,r[i%a].slice());return Wi(d,v),Wi(d,c),[{points:p},{points:d}]}function iw(r){var e=r.points,t=[],a=[];hh(e,t,a);var n=new vt(t[0],t[1],a[0]-t[0],a[1]-t[1]),i=n.width,o=n.height,s=n.x,l=n.y,u=new ht,f=new ht;return i>o?(u.x=f.x=s+i/2,u.y=l,f.y=l+o):(u.y=f.y=l+o/2,u.x=s,f.x=s+i),kY(e,u,f)}function Kf(r,e,t,a){if(t===1)a.push(e);else{var n=Math.floor(t/2),i=r(e);Kf(r,i[0],n,a),Kf(r,i[1],t-n,a)}return a}function OY(r,e){for(var t=[],a=0;a<e;a++)t.push(Mg(r));return t}function NY(r,e){e.setStyle(r.style),e.z=r.z,e.z2=r.z2,e.zlevel=r.zlevel}function BY(r){for(var e=[],t=0;t<r.length;)e.push([r[t++],r[t++]]);return e}function VY(r,e){var t=[],a=r.shape,n;switch(r.type){case"rect":PY(a,e,t),n=xt;break;case"sector":aw(a,e,t),n=Pe;break;case"circle":aw({r0:0,r:a.r,startAngle:0,endAngle:Math.PI*2,cx:a.cx,cy:a.cy},e,t),n=Pe;break;default:var i=r.getComputedTransform(),o=i?Math.sqrt(Math.max(i[0]*i[0]+i[1]*i[1],i[2]*i[2]+i[3]*i[3])):1,s=G(LY(r.getUpdatedPathProxy(),o),function(y){return BY(y)}),l=s.length;if(l===0)Kf(iw,{points:s[0]},e,t);else if(l===e)for(var u=0;u<l;u++)t.push({points:s[u]});else{var f=0,h=G(s,function(y){var m=[],_=[];hh(y,m,_);var S=(_[1]-m[1])*(_[0]-m[0]);return f+=S,{poly:y,area:S}});h.sort(function(y,m){return m.area-y.area});for(var v=e,u=0;u<l;u++){var c=h[u];if(v<=0)break;var p=u===l-1?v:Math.ceil(c.area/f*e);p<0||(Kf(iw,{points:c.poly},p,t),v-=p)}}n=Re;break}if(!n)return OY(r,e);for(var d=[],u=0;u<t.length;u++){var g=new n;g.setShape(t[u]),NY(r,g),d.push(g)}return d}function zY(r,e){var t=r.length,a=e.length;if(t===a)return[r,e];for(var n=[],i=[],o=t<a?r:e,s=Math.min(t,a),l=Math.abs(a-t)/6,u=(s-2)/6,f=Math.ceil(l/u)+1,h=[o[0],o[1]],v=l,c=2;c<s;){var p=o[c-2],d=o[c-1],g=o[c++],y=o[c++],m=o[c++],_=o[c++],S=o[c++],b=o[c++];if(v<=0){h.push(g,y,m,_,S,b);continue}for(var x=Math.min(v,f-1)+1,w=1;w<=x;w++){var T=w/x;Ja(p,g,m,S,T,n),Ja(d,y,_,b,T,i),p=n[3],d=i[3],h.push(n[1],i[1],n[2],i[2],p,d),g=n[5],y=i[5],m=n[6],_=i[6]}v-=x-1}return o===r?[h,e]:[r,h]}function ow(r,e){for(var t=r.length,a=r[t-2],n=r[t-1],i=[],o=0;o<e.length;)i[o++]=a,i[o++]=n;return i}function GY(r,e){for(var t,a,n,i=[],o=[],s=0;s<Math.max(r.length,e.length);s++){var l=r[s],u=e[s],f=void 0,h=void 0;l?u?(t=zY(l,u),f=t[0],h=t[1],a=f,n=h):(h=ow(n||l,l),f=l):(f=ow(a||u,u),h=u),i.push(f),o.push(h)}return[i,o]}function sw(r){for(var e=0,t=0,a=0,n=r.length,i=0,o=n-2;i<n;o=i,i+=2){var s=r[o],l=r[o+1],u=r[i],f=r[i+1],h=s*f-u*l;e+=h,t+=(s+u)*h,a+=(l+f)*h}return e===0?[r[0]||0,r[1]||0]:[t/e/3,a/e/3,e]}function FY(r,e,t,a){for(var n=(r.length-2)/6,i=1/0,o=0,s=r.length,l=s-2,u=0;u<n;u++){for(var f=u*6,h=0,v=0;v<s;v+=2){var c=v===0?f:(f+v-2)%l+2,p=r[c]-t[0],d=r[c+1]-t[1],g=e[v]-a[0],y=e[v+1]-a[1],m=g-p,_=y-d;h+=m*m+_*_}h<i&&(i=h,o=u)}return o}function HY(r){for(var e=[],t=r.length,a=0;a<t;a+=2)e[a]=r[t-a-2],e[a+1]=r[t-a-1];return e}function WY(r,e,t,a){for(var n=[],i,o=0;o<r.length;o++){var s=r[o],l=e[o],u=sw(s),f=sw(l);i==null&&(i=u[2]<0!=f[2]<0);var h=[],v=[],c=0,p=1/0,d=[],g=s.length;i&&(s=HY(s));for(var y=FY(s,l,u,f)*6,m=g-2,_=0;_<m;_+=2){var S=(y+_)%m+2;h[_+2]=s[S]-u[0],h[_+3]=s[S+1]-u[1]}h[0]=s[y]-u[0],h[1]=s[y+1]-u[1];for(var b=a/t,x=-a/2;x<=a/2;x+=b){for(var w=Math.sin(x),T=Math.cos(x),A=0,_=0;_<s.length;_+=2){var C=h[_],M=h[_+1],I=l[_]-f[0],L=l[_+1]-f[1],P=I*T-L*w,R=I*w+L*T;d[_]=P,d[_+1]=R;var E=P-C,O=R-M;A+=E*E+O*O}if(A<p){p=A,c=x;for(var k=0;k<d.length;k++)v[k]=d[k]}}n.push({from:h,to:v,fromCp:u,toCp:f,rotation:-c})}return n}function Jf(r){return r.__isCombineMorphing}var KI="__mOriginal_";function Qf(r,e,t){var a=KI+e,n=r[a]||r[e];r[a]||(r[a]=r[e]);var i=t.replace,o=t.after,s=t.before;r[e]=function(){var l=arguments,u;return s&&s.apply(this,l),i?u=i.apply(this,l):u=n.apply(this,l),o&&o.apply(this,l),u}}function Bs(r,e){var t=KI+e;r[t]&&(r[e]=r[t],r[t]=null)}function lw(r,e){for(var t=0;t<r.length;t++)for(var a=r[t],n=0;n<a.length;){var i=a[n],o=a[n+1];a[n++]=e[0]*i+e[2]*o+e[4],a[n++]=e[1]*i+e[3]*o+e[5]}}function JI(r,e){var t=r.getUpdatedPathProxy(),a=e.getUpdatedPathProxy(),n=GY(jd(t),jd(a)),i=n[0],o=n[1],s=r.getComputedTransform(),l=e.getComputedTransform();function u(){this.transform=null}s&&lw(i,s),l&&lw(o,l),Qf(e,"updateTransform",{replace:u}),e.transform=null;var f=WY(i,o,10,Math.PI),h=[];Qf(e,"buildPath",{replace:function(v){for(var c=e.__morphT,p=1-c,d=[],g=0;g<f.length;g++){var y=f[g],m=y.from,_=y.to,S=y.rotation*c,b=y.fromCp,x=y.toCp,w=Math.sin(S),T=Math.cos(S);Gu(d,b,x,c);for(var A=0;A<m.length;A+=2){var C=m[A],M=m[A+1],I=_[A],L=_[A+1],P=C*p+I*c,R=M*p+L*c;h[A]=P*T-R*w+d[0],h[A+1]=P*w+R*T+d[1]}var E=h[0],O=h[1];v.moveTo(E,O);for(var A=2;A<m.length;){var I=h[A++],L=h[A++],k=h[A++],B=h[A++],F=h[A++],H=h[A++];E===I&&O===L&&k===F&&B===H?v.lineTo(F,H):v.bezierCurveTo(I,L,k,B,F,H),E=F,O=H}}}})}function hm(r,e,t){if(!r||!e)return e;var a=t.done,n=t.during;JI(r,e),e.__morphT=0;function i(){Bs(e,"buildPath"),Bs(e,"updateTransform"),e.__morphT=-1,e.createPathProxy(),e.dirtyShape()}return e.animateTo({__morphT:1},j({during:function(o){e.dirtyShape(),n&&n(o)},done:function(){i(),a&&a()}},t)),e}function UY(r,e,t,a,n,i){var o=16;r=n===t?0:Math.round(32767*(r-t)/(n-t)),e=i===a?0:Math.round(32767*(e-a)/(i-a));for(var s=0,l,u=(1<<o)/2;u>0;u/=2){var f=0,h=0;(r&u)>0&&(f=1),(e&u)>0&&(h=1),s+=u*u*(3*f^h),h===0&&(f===1&&(r=u-1-r,e=u-1-e),l=r,r=e,e=l)}return s}function jf(r){var e=1/0,t=1/0,a=-1/0,n=-1/0,i=G(r,function(s){var l=s.getBoundingRect(),u=s.getComputedTransform(),f=l.x+l.width/2+(u?u[4]:0),h=l.y+l.height/2+(u?u[5]:0);return e=Math.min(f,e),t=Math.min(h,t),a=Math.max(f,a),n=Math.max(h,n),[f,h]}),o=G(i,function(s,l){return{cp:s,z:UY(s[0],s[1],e,t,a,n),path:r[l]}});return o.sort(function(s,l){return s.z-l.z}).map(function(s){return s.path})}function QI(r){return VY(r.path,r.count)}function eg(){return{fromIndividuals:[],toIndividuals:[],count:0}}function YY(r,e,t){var a=[];function n(b){for(var x=0;x<b.length;x++){var w=b[x];Jf(w)?n(w.childrenRef()):w instanceof yt&&a.push(w)}}n(r);var i=a.length;if(!i)return eg();var o=t.dividePath||QI,s=o({path:e,count:i});if(s.length!==i)return console.error("Invalid morphing: unmatched splitted path"),eg();a=jf(a),s=jf(s);for(var l=t.done,u=t.during,f=t.individualDelay,h=new aa,v=0;v<i;v++){var c=a[v],p=s[v];p.parent=e,p.copyTransform(h),f||JI(c,p)}e.__isCombineMorphing=!0,e.childrenRef=function(){return s};function d(b){for(var x=0;x<s.length;x++)s[x].addSelfToZr(b)}Qf(e,"addSelfToZr",{after:function(b){d(b)}}),Qf(e,"removeSelfFromZr",{after:function(b){for(var x=0;x<s.length;x++)s[x].removeSelfFromZr(b)}});function g(){e.__isCombineMorphing=!1,e.__morphT=-1,e.childrenRef=null,Bs(e,"addSelfToZr"),Bs(e,"removeSelfFromZr")}var y=s.length;if(f)for(var m=y,_=function(){m--,m===0&&(g(),l&&l())},v=0;v<y;v++){var S=f?j({delay:(t.delay||0)+f(v,y,a[v],s[v]),done:_},t):t;hm(a[v],s[v],S)}else e.__morphT=0,e.animateTo({__morphT:1},j({during:function(b){for(var x=0;x<y;x++){var w=s[x];w.__morphT=e.__morphT,w.dirtyShape()}u&&u(b)},done:function(){g();for(var b=0;b<r.length;b++)Bs(r[b],"updateTransform");l&&l()}},t));return e.__zr&&d(e.__zr),{fromIndividuals:a,toIndividuals:s,count:y}}function $Y(r,e,t){var a=e.length,n=[],i=t.dividePath||QI;function o(c){for(var p=0;p<c.length;p++){var d=c[p];Jf(d)?o(d.childrenRef()):d instanceof yt&&n.push(d)}}if(Jf(r)){o(r.childrenRef());var s=n.length;if(s<a)for(var l=0,u=s;u<a;u++)n.push(Mg(n[l++%s]));n.length=a}else{n=i({path:r,count:a});for(var f=r.getComputedTransform(),u=0;u<n.length;u++)n[u].setLocalTransform(f);if(n.length!==a)return console.error("Invalid morphing: unmatched splitted path"),eg()}n=jf(n),e=jf(e);for(var h=t.individualDelay,u=0;u<a;u++){var v=h?j({delay:(t.delay||0)+h(u,a,n[u],e[u])},t):t;hm(n[u],e[u],v)}return{fromIndividuals:n,toIndividuals:e,count:e.length}}function uw(r){return z(r[0])}function fw(r,e){for(var t=[],a=r.length,n=0;n<a;n++)t.push({one:r[n],many:[]});for(var n=0;n<e.length;n++){var i=e[n].length,o=void 0;for(o=0;o<i;o++)t[o%a].many.push(e[n][o])}for(var s=0,n=a-1;n>=0;n--)if(!t[n].many.length){var l=t[s].many;if(l.length<=1)if(s)s=0;else return t;var i=l.length,u=Math.ceil(i/2);t[n].many=l.slice(u,i),t[s].many=l.slice(0,u),s++}return t}var XY={clone:function(r){for(var e=[],t=1-Math.pow(1-r.path.style.opacity,1/r.count),a=0;a<r.count;a++){var n=Mg(r.path);n.setStyle("opacity",t),e.push(n)}return e},split:null};function up(r,e,t,a,n,i){if(!r.length||!e.length)return;var o=To("update",a,n);if(!(o&&o.duration>0))return;var s=a.getModel("universalTransition").get("delay"),l=Object.assign({setToFinal:!0},o),u,f;uw(r)&&(u=r,f=e),uw(e)&&(u=e,f=r);function h(y,m,_,S,b){var x=y.many,w=y.one;if(x.length===1&&!b){var T=m?x[0]:w,A=m?w:x[0];if(Jf(T))h({many:[T],one:A},!0,_,S,!0);else{var C=s?j({delay:s(_,S)},l):l;hm(T,A,C),i(T,A,T,A,C)}}else for(var M=j({dividePath:XY[t],individualDelay:s&&function(O,k,B,F){return s(O+_,S)}},l),I=m?YY(x,w,M):$Y(w,x,M),L=I.fromIndividuals,P=I.toIndividuals,R=L.length,E=0;E<R;E++){var C=s?j({delay:s(E,R)},l):l;i(L[E],P[E],m?x[E]:y.one,m?y.one:x[E],C)}}for(var v=u?u===r:r.length>e.length,c=u?fw(f,u):fw(v?e:r,[v?r:e]),p=0,d=0;d<c.length;d++)p+=c[d].many.length;for(var g=0,d=0;d<c.length;d++)h(c[d],v,g,p),g+=c[d].many.length}function Nn(r){if(!r)return[];if(z(r)){for(var e=[],t=0;t<r.length;t++)e.push(Nn(r[t]));return e}var a=[];return r.traverse(function(n){n instanceof yt&&!n.disableMorphing&&!n.invisible&&!n.ignore&&a.push(n)}),a}var jI=1e4,ZY=0,hw=1,vw=2,qY=bt();function KY(r,e){for(var t=r.dimensions,a=0;a<t.length;a++){var n=r.getDimensionInfo(t[a]);if(n&&n.otherDims[e]===0)return t[a]}}function JY(r,e,t){var a=r.getDimensionInfo(t),n=a&&a.ordinalMeta;if(a){var i=r.get(a.name,e);return n&&n.categories[i]||i+""}}function cw(r,e,t,a){var n=a?"itemChildGroupId":"itemGroupId",i=KY(r,n);if(i){var o=JY(r,e,i);return o}var s=r.getRawDataItem(e),l=a?"childGroupId":"groupId";if(s&&s[l])return s[l]+"";if(!a)return t||r.getId(e)}function pw(r){var e=[];return D(r,function(t){var a=t.data,n=t.dataGroupId;if(!(a.count()>jI))for(var i=a.getIndices(),o=0;o<i.length;o++)e.push({data:a,groupId:cw(a,o,n,!1),childGroupId:cw(a,o,n,!0),divide:t.divide,dataIndex:o})}),e}function fp(r,e,t){r.traverse(function(a){a instanceof yt&&Ht(a,{style:{opacity:0}},e,{dataIndex:t,isFrom:!0})})}function hp(r){if(r.parent){var e=r.getComputedTransform();r.setLocalTransform(e),r.parent.remove(r)}}function Ui(r){r.stopAnimation(),r.isGroup&&r.traverse(function(e){e.stopAnimation()})}function QY(r,e,t){var a=To("update",t,e);a&&r.traverse(function(n){if(n instanceof fr){var i=$R(n);i&&n.animateFrom({style:i},a)}})}function jY(r,e){var t=r.length;if(t!==e.length)return!1;for(var a=0;a<t;a++){var n=r[a],i=e[a];if(n.data.getId(n.dataIndex)!==i.data.getId(i.dataIndex))return!1}return!0}function tL(r,e,t){var a=pw(r),n=pw(e);function i(_,S,b,x,w){(b||_)&&S.animateFrom({style:b&&b!==_?V(V({},b.style),_.style):_.style},w)}var o=!1,s=ZY,l=Z(),u=Z();a.forEach(function(_){_.groupId&&l.set(_.groupId,!0),_.childGroupId&&u.set(_.childGroupId,!0)});for(var f=0;f<n.length;f++){var h=n[f].groupId;if(u.get(h)){s=hw;break}var v=n[f].childGroupId;if(v&&l.get(v)){s=vw;break}}function c(_,S){return function(b){var x=b.data,w=b.dataIndex;return S?x.getId(w):_?s===hw?b.childGroupId:b.groupId:s===vw?b.childGroupId:b.groupId}}var p=jY(a,n),d={};if(!p)for(var f=0;f<n.length;f++){var g=n[f],y=g.data.getItemGraphicEl(g.dataIndex);y&&(d[y.id]=!0)}function m(_,S){var b=a[S],x=n[_],w=x.data.hostModel,T=b.data.getItemGraphicEl(b.dataIndex),A=x.data.getItemGraphicEl(x.dataIndex);if(T===A){A&&QY(A,x.dataIndex,w);return}T&&d[T.id]||A&&(Ui(A),T?(Ui(T),hp(T),o=!0,up(Nn(T),Nn(A),x.divide,w,_,i)):fp(A,w,_))}new pa(a,n,c(!0,p),c(!1,p),null,"multiple").update(m).updateManyToOne(function(_,S){var b=n[_],x=b.data,w=x.hostModel,T=x.getItemGraphicEl(b.dataIndex),A=Mt(G(S,function(C){return a[C].data.getItemGraphicEl(a[C].dataIndex)}),function(C){return C&&C!==T&&!d[C.id]});T&&(Ui(T),A.length?(D(A,function(C){Ui(C),hp(C)}),o=!0,up(Nn(A),Nn(T),b.divide,w,_,i)):fp(T,w,b.dataIndex))}).updateOneToMany(function(_,S){var b=a[S],x=b.data.getItemGraphicEl(b.dataIndex);if(!(x&&d[x.id])){var w=Mt(G(_,function(A){return n[A].data.getItemGraphicEl(n[A].dataIndex)}),function(A){return A&&A!==x}),T=n[_[0]].data.hostModel;w.length&&(D(w,function(A){return Ui(A)}),x?(Ui(x),hp(x),o=!0,up(Nn(x),Nn(w),b.divide,T,_[0],i)):D(w,function(A){return fp(A,T,_[0])}))}}).updateManyToMany(function(_,S){new pa(S,_,function(b){return a[b].data.getId(a[b].dataIndex)},function(b){return n[b].data.getId(n[b].dataIndex)}).update(function(b,x){m(_[b],S[x])}).execute()}).execute(),o&&D(e,function(_){var S=_.data,b=S.hostModel,x=b&&t.getViewOfSeriesModel(b),w=To("update",b,0);x&&b.isAnimationEnabled()&&w&&w.duration>0&&x.group.traverse(function(T){T instanceof yt&&!T.animators.length&&T.animateFrom({style:{opacity:0}},w)})})}function dw(r){var e=r.getModel("universalTransition").get("seriesKey");return e||r.id}function gw(r){return z(r)?r.sort().join(","):r}function Oa(r){if(r.hostModel)return r.hostModel.getModel("universalTransition").get("divideShape")}function t7(r,e){var t=Z(),a=Z(),n=Z();return D(r.oldSeries,function(i,o){var s=r.oldDataGroupIds[o],l=r.oldData[o],u=dw(i),f=gw(u);a.set(f,{dataGroupId:s,data:l}),z(u)&&D(u,function(h){n.set(h,{key:f,dataGroupId:s,data:l})})}),D(e.updatedSeries,function(i){if(i.isUniversalTransitionEnabled()&&i.isAnimationEnabled()){var o=i.get("dataGroupId"),s=i.getData(),l=dw(i),u=gw(l),f=a.get(u);if(f)t.set(u,{oldSeries:[{dataGroupId:f.dataGroupId,divide:Oa(f.data),data:f.data}],newSeries:[{dataGroupId:o,divide:Oa(s),data:s}]});else if(z(l)){var h=[];D(l,function(p){var d=a.get(p);d.data&&h.push({dataGroupId:d.dataGroupId,divide:Oa(d.data),data:d.data})}),h.length&&t.set(u,{oldSeries:h,newSeries:[{dataGroupId:o,data:s,divide:Oa(s)}]})}else{var v=n.get(l);if(v){var c=t.get(v.key);c||(c={oldSeries:[{dataGroupId:v.dataGroupId,data:v.data,divide:Oa(v.data)}],newSeries:[]},t.set(v.key,c)),c.newSeries.push({dataGroupId:o,data:s,divide:Oa(s)})}}}}),t}function yw(r,e){for(var t=0;t<r.length;t++){var a=e.seriesIndex!=null&&e.seriesIndex===r[t].seriesIndex||e.seriesId!=null&&e.seriesId===r[t].id;if(a)return t}}function e7(r,e,t,a){var n=[],i=[];D(Et(r.from),function(o){var s=yw(e.oldSeries,o);s>=0&&n.push({dataGroupId:e.oldDataGroupIds[s],data:e.oldData[s],divide:Oa(e.oldData[s]),groupIdDim:o.dimension})}),D(Et(r.to),function(o){var s=yw(t.updatedSeries,o);if(s>=0){var l=t.updatedSeries[s].getData();i.push({dataGroupId:e.oldDataGroupIds[s],data:l,divide:Oa(l),groupIdDim:o.dimension})}}),n.length>0&&i.length>0&&tL(n,i,a)}function r7(r){r.registerUpdateLifecycle("series:beforeupdate",function(e,t,a){D(Et(a.seriesTransition),function(n){D(Et(n.to),function(i){for(var o=a.updatedSeries,s=0;s<o.length;s++)(i.seriesIndex!=null&&i.seriesIndex===o[s].seriesIndex||i.seriesId!=null&&i.seriesId===o[s].id)&&(o[s][Ju]=!0)})})}),r.registerUpdateLifecycle("series:transition",function(e,t,a){var n=qY(t);if(n.oldSeries&&a.updatedSeries&&a.optionChanged){var i=a.seriesTransition;if(i)D(Et(i),function(c){e7(c,n,a,t)});else{var o=t7(n,a);D(o.keys(),function(c){var p=o.get(c);tL(p.oldSeries,p.newSeries,t)})}D(a.updatedSeries,function(c){c[Ju]&&(c[Ju]=!1)})}for(var s=e.getSeries(),l=n.oldSeries=[],u=n.oldDataGroupIds=[],f=n.oldData=[],h=0;h<s.length;h++){var v=s[h].getData();v.count()<jI&&(l.push(s[h]),u.push(s[h].get("dataGroupId")),f.push(v))}})}gt([vV]);gt([sV]);gt([RV,XV,az,Nz,Xz,E5,sG,UG,vF,mF,DF,x3,$3,aH,_H,wH,EH,GH,JH,a4,c4,K4]);gt(dW);gt(FW);gt(QD);gt(t6);gt(BM);gt(n6);gt(c6);gt(tU);gt(_U);gt(Ol);gt(NU);gt(zU);gt(qU);gt(r8);gt(l8);gt(p8);gt(T8);gt(H8);gt(GI);gt(FI);gt(lY);gt($I);gt(XI);gt(cY);gt(CY);gt(IY);gt(r7);gt(DB);const ut={statusTableBody:document.getElementById("status-table-body"),rangeSelect:document.getElementById("range-select"),trackSelect:document.getElementById("track-select"),limitInput:document.getElementById("limit-input"),targetNameInput:document.getElementById("target-name-input"),targetAddressInput:document.getElementById("target-address-input"),targetPortInput:document.getElementById("target-port-input"),targetSaveBtn:document.getElementById("target-save-btn"),targetDeleteBtn:document.getElementById("target-delete-btn"),logsOutput:document.getElementById("logs-output"),logsMeta:document.getElementById("logs-meta"),statusLine:document.getElementById("status-line"),authScreen:document.getElementById("auth-screen"),authStatus:document.getElementById("auth-status"),authRetryBtn:document.getElementById("auth-retry-btn"),authOpenTelegramBtn:document.getElementById("auth-open-telegram-btn"),dashboardMain:document.getElementById("dashboard-main"),refreshBtn:document.getElementById("refresh-btn"),logsBtn:document.getElementById("logs-btn"),logoutBtn:document.getElementById("logout-btn"),themeBtn:document.getElementById("theme-btn"),summaryTotal:document.getElementById("summary-total"),summaryUp:document.getElementById("summary-up"),summaryDown:document.getElementById("summary-down"),summaryUnknown:document.getElementById("summary-unknown"),sessionExpiry:document.getElementById("session-expiry"),snapshotTime:document.getElementById("snapshot-time"),timelineChart:B_(document.getElementById("timeline-chart")),distributionChart:B_(document.getElementById("distribution-chart"))},Tt={selectedTrack:"",tracks:[],refreshTimer:null,logTimer:null,theme:"dark",rows:[],formDirty:!1},eL="trackway_theme_v2";function Fn(r){return String(r).padStart(2,"0")}function vi(r){const e=new Date(r);return Number.isNaN(e.valueOf())?String(r):`${Fn(e.getDate())}.${Fn(e.getMonth()+1)}.${e.getFullYear()} ${Fn(e.getHours())}:${Fn(e.getMinutes())}:${Fn(e.getSeconds())}`}function a7(r){return r==="UP"?"status-pill status-up":r==="DOWN"?"status-pill status-down":"status-pill status-unknown"}function Ze(r){ut.statusLine.textContent=r}function rg(r){ut.authStatus.textContent=r}function th(r){ut.dashboardMain.classList.add("hidden"),ut.authScreen.classList.remove("hidden"),r&&rg(r)}function mw(){ut.authScreen.classList.add("hidden"),ut.dashboardMain.classList.remove("hidden"),requestAnimationFrame(()=>{ut.timelineChart.resize(),ut.distributionChart.resize()})}function to(r){return String(r).replaceAll("&","&amp;").replaceAll("<","&lt;").replaceAll(">","&gt;").replaceAll('"',"&quot;").replaceAll("'","&#39;")}function rL(r){return{timestamp:r.timestamp||r.Timestamp||"",status:r.status||r.Status||"",endpoint:r.endpoint||r.Endpoint||"",reason:r.reason||r.Reason||""}}async function bi(r,e={}){const t=await fetch(r,{credentials:"include",headers:{Accept:"application/json",...e.headers},...e});let a=null;try{a=await t.json()}catch{a=null}if(!t.ok){const n=a?.error||`HTTP ${t.status}`,i=new Error(n);throw i.status=t.status,i}return a}function aL(r){Tt.theme=r==="light"?"light":"dark",document.documentElement.setAttribute("data-theme",Tt.theme),localStorage.setItem(eL,Tt.theme),ut.themeBtn.textContent=Tt.theme==="dark"?"Theme: Dark":"Theme: Light"}function n7(){const r=localStorage.getItem(eL);return r==="dark"||r==="light"?r:"dark"}async function _w(){const r=await bi("/api/auth/session");return ut.sessionExpiry.textContent=vi(r.expires_at),r}async function i7(){const r=window.Telegram?.WebApp;if(!r)return!1;r.ready?.(),r.expand?.();const e=String(r.initData||"").trim();if(!e)throw new Error("Telegram WebApp initData is empty");return await bi("/api/auth/telegram-miniapp",{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify({init_data:e})}),!0}async function o7(){try{return await _w(),mw(),rg("Authorized."),!0}catch{try{if(await i7())return await _w(),mw(),rg("Authorized via Telegram Mini App."),!0}catch(e){return th(`Mini App auth failed: ${e?.message||"unknown error"}`),!1}return th("Open Telegram, send /authme, then open the auth link here."),!1}}function nL(r){const e=[{value:r.up,name:"UP",itemStyle:{color:"#5cd89a"}},{value:r.down,name:"DOWN",itemStyle:{color:"#ff6f8f"}},{value:r.unknown,name:"UNKNOWN",itemStyle:{color:"#ffc36d"}}];ut.distributionChart.setOption({backgroundColor:"transparent",tooltip:{trigger:"item"},legend:{bottom:0,textStyle:{color:getComputedStyle(document.documentElement).getPropertyValue("--muted").trim()}},series:[{type:"pie",radius:["42%","70%"],center:["50%","46%"],label:{color:getComputedStyle(document.documentElement).getPropertyValue("--ink").trim()},data:e}]})}function ag(r){const e=r.map(t=>{const a=rL(t),n=a.status==="DOWN"?0:a.status==="UP"?1:null;return[a.timestamp,n]}).filter(t=>t[1]!==null);ut.timelineChart.setOption({backgroundColor:"transparent",tooltip:{trigger:"axis",axisPointer:{type:"line"},formatter:t=>{if(!t||!t.length)return"";const a=t[0],n=vi(a.axisValue),i=Number(a.data[1])===1?"UP":"DOWN";return`${n}<br/><b>${i}</b>`}},grid:{left:46,right:18,top:18,bottom:56},xAxis:{type:"time",axisLabel:{color:getComputedStyle(document.documentElement).getPropertyValue("--muted").trim(),formatter:t=>{const a=new Date(t);return`${Fn(a.getHours())}:${Fn(a.getMinutes())}`}},axisLine:{lineStyle:{color:getComputedStyle(document.documentElement).getPropertyValue("--line").trim()}},splitLine:{show:!1}},yAxis:{type:"value",min:0,max:1,interval:1,axisLabel:{color:getComputedStyle(document.documentElement).getPropertyValue("--muted").trim(),formatter:t=>t===1?"UP":"DOWN"},axisLine:{show:!1},splitLine:{lineStyle:{color:getComputedStyle(document.documentElement).getPropertyValue("--line").trim()}}},dataZoom:[{type:"inside",xAxisIndex:0},{type:"slider",xAxisIndex:0,height:18,bottom:12,borderColor:getComputedStyle(document.documentElement).getPropertyValue("--line").trim(),backgroundColor:"transparent",fillerColor:"rgba(101,184,255,0.18)"}],series:[{name:"State",type:"line",step:"end",smooth:!1,showSymbol:!1,lineStyle:{width:2,color:"#65b8ff"},areaStyle:{color:"rgba(101,184,255,0.18)"},data:e}]})}function iL(r){ut.statusTableBody.innerHTML="";for(const e of r.targets){const t=document.createElement("tr");e.name===Tt.selectedTrack&&t.classList.add("active"),t.innerHTML=`
-            <td>${to(e.name)}</td>
+`||n==="")){var i=r.isSelected(n);X(t,n)?t[n]=t[n]&&i:t[n]=i}}),t}function _8(r){r.registerAction("legendToggleSelect","legendselectchanged",lt(ss,"toggleSelected")),r.registerAction("legendAllSelect","legendselectall",lt(ss,"allSelect")),r.registerAction("legendInverseSelect","legendinverseselect",lt(ss,"inverseSelect")),r.registerAction("legendSelect","legendselected",lt(ss,"select")),r.registerAction("legendUnSelect","legendunselected",lt(ss,"unSelect"))}function VI(r){r.registerComponentModel(qd),r.registerComponentView(NI),r.registerProcessor(r.PRIORITY.PROCESSOR.SERIES_FILTER,m8),r.registerSubTypeDefaulter("legend",function(){return"plain"}),_8(r)}var S8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.prototype.setScrollDataIndex=function(t){this.option.scrollDataIndex=t},e.prototype.init=function(t,a,n){var i=Mo(t);r.prototype.init.call(this,t,a,n),Nb(this,t,i)},e.prototype.mergeOption=function(t,a){r.prototype.mergeOption.call(this,t,a),Nb(this,this.option,t)},e.type="legend.scroll",e.defaultOption=nn(qd.defaultOption,{scrollDataIndex:0,pageButtonItemGap:5,pageButtonGap:null,pageButtonPosition:"end",pageFormatter:"{current}/{total}",pageIcons:{horizontal:["M0,0L12,-10L12,10z","M0,0L-12,-10L-12,10z"],vertical:["M0,0L20,0L10,-20z","M0,0L20,0L10,20z"]},pageIconColor:"#2f4554",pageIconInactiveColor:"#aaa",pageIconSize:15,pageTextStyle:{color:"#333"},animationDurationUpdate:800}),e})(qd);function Nb(r,e,t){var a=r.getOrient(),n=[1,1];n[a.index]=0,ja(e,t,{type:"box",ignoreSize:!!n})}var Bb=at,ap=["width","height"],np=["x","y"],x8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t.newlineDisabled=!0,t._currentIndex=0,t}return e.prototype.init=function(){r.prototype.init.call(this),this.group.add(this._containerGroup=new Bb),this._containerGroup.add(this.getContentGroup()),this.group.add(this._controllerGroup=new Bb)},e.prototype.resetInner=function(){r.prototype.resetInner.call(this),this._controllerGroup.removeAll(),this._containerGroup.removeClipPath(),this._containerGroup.__rectSize=null},e.prototype.renderInner=function(t,a,n,i,o,s,l){var u=this;r.prototype.renderInner.call(this,t,a,n,i,o,s,l);var f=this._controllerGroup,h=a.get("pageIconSize",!0),v=z(h)?h:[h,h];p("pagePrev",0);var c=a.getModel("pageTextStyle");f.add(new wt({name:"pageText",style:{text:"xx/xx",fill:c.getTextColor(),font:c.getFont(),verticalAlign:"middle",align:"center"},silent:!0})),p("pageNext",1);function p(d,g){var y=d+"DataIndex",m=bl(a.get("pageIcons",!0)[a.getOrient().name][g],{onclick:$(u._pageGo,u,y,a,i)},{x:-v[0]/2,y:-v[1]/2,width:v[0],height:v[1]});m.name=d,f.add(m)}},e.prototype.layoutInner=function(t,a,n,i,o,s){var l=this.getSelectorGroup(),u=t.getOrient().index,f=ap[u],h=np[u],v=ap[1-u],c=np[1-u];o&&ri("horizontal",l,t.get("selectorItemGap",!0));var p=t.get("selectorButtonGap",!0),d=l.getBoundingRect(),g=[-d.x,-d.y],y=rt(n);o&&(y[f]=n[f]-d[f]-p);var m=this._layoutContentAndController(t,i,y,u,f,v,c,h);if(o){if(s==="end")g[u]+=m[f]+p;else{var _=d[f]+p;g[u]-=_,m[h]-=_}m[f]+=d[f]+p,g[1-u]+=m[c]+m[v]/2-d[v]/2,m[v]=Math.max(m[v],d[v]),m[c]=Math.min(m[c],d[c]+g[1-u]),l.x=g[0],l.y=g[1],l.markRedraw()}return m},e.prototype._layoutContentAndController=function(t,a,n,i,o,s,l,u){var f=this.getContentGroup(),h=this._containerGroup,v=this._controllerGroup;ri(t.get("orient"),f,t.get("itemGap"),i?n.width:null,i?null:n.height),ri("horizontal",v,t.get("pageButtonItemGap",!0));var c=f.getBoundingRect(),p=v.getBoundingRect(),d=this._showController=c[o]>n[o],g=[-c.x,-c.y];a||(g[i]=f[u]);var y=[0,0],m=[-p.x,-p.y],_=st(t.get("pageButtonGap",!0),t.get("itemGap",!0));if(d){var S=t.get("pageButtonPosition",!0);S==="end"?m[i]+=n[o]-p[o]:y[i]+=p[o]+_}m[1-i]+=c[s]/2-p[s]/2,f.setPosition(g),h.setPosition(y),v.setPosition(m);var b={x:0,y:0};if(b[o]=d?n[o]:c[o],b[s]=Math.max(c[s],p[s]),b[l]=Math.min(0,p[l]+m[1-i]),h.__rectSize=n[o],d){var x={x:0,y:0};x[o]=Math.max(n[o]-p[o]-_,0),x[s]=b[s],h.setClipPath(new xt({shape:x})),h.__rectSize=x[o]}else v.eachChild(function(T){T.attr({invisible:!0,silent:!0})});var w=this._getPageInfo(t);return w.pageIndex!=null&&Ct(f,{x:w.contentPosition[0],y:w.contentPosition[1]},d?t:null),this._updatePageInfoView(t,w),b},e.prototype._pageGo=function(t,a,n){var i=this._getPageInfo(a)[t];i!=null&&n.dispatchAction({type:"legendScroll",scrollDataIndex:i,legendId:a.id})},e.prototype._updatePageInfoView=function(t,a){var n=this._controllerGroup;D(["pagePrev","pageNext"],function(f){var h=f+"DataIndex",v=a[h]!=null,c=n.childOfName(f);c&&(c.setStyle("fill",v?t.get("pageIconColor",!0):t.get("pageIconInactiveColor",!0)),c.cursor=v?"pointer":"default")});var i=n.childOfName("pageText"),o=t.get("pageFormatter"),s=a.pageIndex,l=s!=null?s+1:0,u=a.pageCount;i&&o&&i.setStyle("text",Y(o)?o.replace("{current}",l==null?"":l+"").replace("{total}",u==null?"":u+""):o({current:l,total:u}))},e.prototype._getPageInfo=function(t){var a=t.get("scrollDataIndex",!0),n=this.getContentGroup(),i=this._containerGroup.__rectSize,o=t.getOrient().index,s=ap[o],l=np[o],u=this._findTargetItemIndex(a),f=n.children(),h=f[u],v=f.length,c=v?1:0,p={contentPosition:[n.x,n.y],pageCount:c,pageIndex:c-1,pagePrevDataIndex:null,pageNextDataIndex:null};if(!h)return p;var d=S(h);p.contentPosition[o]=-d.s;for(var g=u+1,y=d,m=d,_=null;g<=v;++g)_=S(f[g]),(!_&&m.e>y.s+i||_&&!b(_,y.s))&&(m.i>y.i?y=m:y=_,y&&(p.pageNextDataIndex==null&&(p.pageNextDataIndex=y.i),++p.pageCount)),m=_;for(var g=u-1,y=d,m=d,_=null;g>=-1;--g)_=S(f[g]),(!_||!b(m,_.s))&&y.i<m.i&&(m=y,p.pagePrevDataIndex==null&&(p.pagePrevDataIndex=y.i),++p.pageCount,++p.pageIndex),y=_;return p;function S(x){if(x){var w=x.getBoundingRect(),T=w[l]+x[l];return{s:T,e:T+w[s],i:x.__legendDataIndex}}}function b(x,w){return x.e>=w&&x.s<=w+i}},e.prototype._findTargetItemIndex=function(t){if(!this._showController)return 0;var a,n=this.getContentGroup(),i;return n.eachChild(function(o,s){var l=o.__legendDataIndex;i==null&&l!=null&&(i=s),l===t&&(a=s)}),a??i},e.type="legend.scroll",e})(NI);function b8(r){r.registerAction("legendScroll","legendscroll",function(e,t){var a=e.scrollDataIndex;a!=null&&t.eachComponent({mainType:"legend",subType:"scroll",query:e},function(n){n.setScrollDataIndex(a)})})}function w8(r){gt(VI),r.registerComponentModel(S8),r.registerComponentView(x8),b8(r)}function T8(r){gt(VI),gt(w8)}var A8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.type="dataZoom.inside",e.defaultOption=nn(fl.defaultOption,{disabled:!1,zoomLock:!1,zoomOnMouseWheel:!0,moveOnMouseMove:!0,moveOnMouseWheel:!1,preventDefaultMouseMove:!0}),e})(fl),um=bt();function C8(r,e,t){um(r).coordSysRecordMap.each(function(a){var n=a.dataZoomInfoMap.get(e.uid);n&&(n.getRange=t)})}function D8(r,e){for(var t=um(r).coordSysRecordMap,a=t.keys(),n=0;n<a.length;n++){var i=a[n],o=t.get(i),s=o.dataZoomInfoMap;if(s){var l=e.uid,u=s.get(l);u&&(s.removeKey(l),s.keys().length||zI(t,o))}}}function zI(r,e){if(e){r.removeKey(e.model.uid);var t=e.controller;t&&t.dispose()}}function M8(r,e){var t={model:e,containsPoint:lt(L8,e),dispatchAction:lt(I8,r),dataZoomInfoMap:null,controller:null},a=t.controller=new El(r.getZr());return D(["pan","zoom","scrollMove"],function(n){a.on(n,function(i){var o=[];t.dataZoomInfoMap.each(function(s){if(i.isAvailableBehavior(s.model.option)){var l=(s.getRange||{})[n],u=l&&l(s.dzReferCoordSysInfo,t.model.mainType,t.controller,i);!s.model.get("disabled",!0)&&u&&o.push({dataZoomId:s.model.id,start:u[0],end:u[1]})}}),o.length&&t.dispatchAction(o)})}),t}function I8(r,e){r.isDisposed()||r.dispatchAction({type:"dataZoom",animation:{easing:"cubicOut",duration:100},batch:e})}function L8(r,e,t,a){return r.coordinateSystem.containPoint([t,a])}function P8(r){var e,t="type_",a={type_true:2,type_move:1,type_false:0,type_undefined:-1},n=!0;return r.each(function(i){var o=i.model,s=o.get("disabled",!0)?!1:o.get("zoomLock",!0)?"move":!0;a[t+s]>a[t+e]&&(e=s),n=n&&o.get("preventDefaultMouseMove",!0)}),{controlType:e,opt:{zoomOnMouseWheel:!0,moveOnMouseMove:!0,moveOnMouseWheel:!0,preventDefaultMouseMove:!!n}}}function R8(r){r.registerProcessor(r.PRIORITY.PROCESSOR.FILTER,function(e,t){var a=um(t),n=a.coordSysRecordMap||(a.coordSysRecordMap=Z());n.each(function(i){i.dataZoomInfoMap=null}),e.eachComponent({mainType:"dataZoom",subType:"inside"},function(i){var o=xI(i);D(o.infoList,function(s){var l=s.model.uid,u=n.get(l)||n.set(l,M8(t,s.model)),f=u.dataZoomInfoMap||(u.dataZoomInfoMap=Z());f.set(i.uid,{dzReferCoordSysInfo:s,model:i,getRange:null})})}),n.each(function(i){var o=i.controller,s,l=i.dataZoomInfoMap;if(l){var u=l.keys()[0];u!=null&&(s=l.get(u))}if(!s){zI(n,i);return}var f=P8(l);o.enable(f.controlType,f.opt),o.setPointerChecker(i.containsPoint),Po(i,"dispatchAction",s.model.get("throttle",!0),"fixRate")})})}var E8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type="dataZoom.inside",t}return e.prototype.render=function(t,a,n){if(r.prototype.render.apply(this,arguments),t.noTarget()){this._clear();return}this.range=t.getPercentRange(),C8(n,t,{pan:$(ip.pan,this),zoom:$(ip.zoom,this),scrollMove:$(ip.scrollMove,this)})},e.prototype.dispose=function(){this._clear(),r.prototype.dispose.apply(this,arguments)},e.prototype._clear=function(){D8(this.api,this.dataZoomModel),this.range=null},e.type="dataZoom.inside",e})(tm),ip={zoom:function(r,e,t,a){var n=this.range,i=n.slice(),o=r.axisModels[0];if(o){var s=op[e](null,[a.originX,a.originY],o,t,r),l=(s.signal>0?s.pixelStart+s.pixelLength-s.pixel:s.pixel-s.pixelStart)/s.pixelLength*(i[1]-i[0])+i[0],u=Math.max(1/a.scale,0);i[0]=(i[0]-l)*u+l,i[1]=(i[1]-l)*u+l;var f=this.dataZoomModel.findRepresentativeAxisProxy().getMinMaxSpan();if(xi(0,i,[0,100],0,f.minSpan,f.maxSpan),this.range=i,n[0]!==i[0]||n[1]!==i[1])return i}},pan:Vb(function(r,e,t,a,n,i){var o=op[a]([i.oldX,i.oldY],[i.newX,i.newY],e,n,t);return o.signal*(r[1]-r[0])*o.pixel/o.pixelLength}),scrollMove:Vb(function(r,e,t,a,n,i){var o=op[a]([0,0],[i.scrollDelta,i.scrollDelta],e,n,t);return o.signal*(r[1]-r[0])*i.scrollDelta})};function Vb(r){return function(e,t,a,n){var i=this.range,o=i.slice(),s=e.axisModels[0];if(s){var l=r(o,s,e,t,a,n);if(xi(l,o,[0,100],"all"),this.range=o,i[0]!==o[0]||i[1]!==o[1])return o}}}var op={grid:function(r,e,t,a,n){var i=t.axis,o={},s=n.model.coordinateSystem.getRect();return r=r||[0,0],i.dim==="x"?(o.pixel=e[0]-r[0],o.pixelLength=s.width,o.pixelStart=s.x,o.signal=i.inverse?1:-1):(o.pixel=e[1]-r[1],o.pixelLength=s.height,o.pixelStart=s.y,o.signal=i.inverse?-1:1),o},polar:function(r,e,t,a,n){var i=t.axis,o={},s=n.model.coordinateSystem,l=s.getRadiusAxis().getExtent(),u=s.getAngleAxis().getExtent();return r=r?s.pointToCoord(r):[0,0],e=s.pointToCoord(e),t.mainType==="radiusAxis"?(o.pixel=e[0]-r[0],o.pixelLength=l[1]-l[0],o.pixelStart=l[0],o.signal=i.inverse?1:-1):(o.pixel=e[1]-r[1],o.pixelLength=u[1]-u[0],o.pixelStart=u[0],o.signal=i.inverse?-1:1),o},singleAxis:function(r,e,t,a,n){var i=t.axis,o=n.model.coordinateSystem.getRect(),s={};return r=r||[0,0],i.orient==="horizontal"?(s.pixel=e[0]-r[0],s.pixelLength=o.width,s.pixelStart=o.x,s.signal=i.inverse?1:-1):(s.pixel=e[1]-r[1],s.pixelLength=o.height,s.pixelStart=o.y,s.signal=i.inverse?-1:1),s}};function GI(r){em(r),r.registerComponentModel(A8),r.registerComponentView(E8),R8(r)}var k8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.type="dataZoom.slider",e.layoutMode="box",e.defaultOption=nn(fl.defaultOption,{show:!0,right:"ph",top:"ph",width:"ph",height:"ph",left:null,bottom:null,borderColor:"#d2dbee",borderRadius:3,backgroundColor:"rgba(47,69,84,0)",dataBackground:{lineStyle:{color:"#d2dbee",width:.5},areaStyle:{color:"#d2dbee",opacity:.2}},selectedDataBackground:{lineStyle:{color:"#8fb0f7",width:.5},areaStyle:{color:"#8fb0f7",opacity:.2}},fillerColor:"rgba(135,175,274,0.2)",handleIcon:"path://M-9.35,34.56V42m0-40V9.5m-2,0h4a2,2,0,0,1,2,2v21a2,2,0,0,1-2,2h-4a2,2,0,0,1-2-2v-21A2,2,0,0,1-11.35,9.5Z",handleSize:"100%",handleStyle:{color:"#fff",borderColor:"#ACB8D1"},moveHandleSize:7,moveHandleIcon:"path://M-320.9-50L-320.9-50c18.1,0,27.1,9,27.1,27.1V85.7c0,18.1-9,27.1-27.1,27.1l0,0c-18.1,0-27.1-9-27.1-27.1V-22.9C-348-41-339-50-320.9-50z M-212.3-50L-212.3-50c18.1,0,27.1,9,27.1,27.1V85.7c0,18.1-9,27.1-27.1,27.1l0,0c-18.1,0-27.1-9-27.1-27.1V-22.9C-239.4-41-230.4-50-212.3-50z M-103.7-50L-103.7-50c18.1,0,27.1,9,27.1,27.1V85.7c0,18.1-9,27.1-27.1,27.1l0,0c-18.1,0-27.1-9-27.1-27.1V-22.9C-130.9-41-121.8-50-103.7-50z",moveHandleStyle:{color:"#D2DBEE",opacity:.7},showDetail:!0,showDataShadow:"auto",realtime:!0,zoomLock:!1,textStyle:{color:"#6E7079"},brushSelect:!0,brushStyle:{color:"rgba(135,175,274,0.15)"},emphasis:{handleLabel:{show:!0},handleStyle:{borderColor:"#8FB0F7"},moveHandleStyle:{color:"#8FB0F7"}}}),e})(fl),ls=xt,zb=7,O8=1,sp=30,N8=7,us="horizontal",Gb="vertical",B8=5,V8=["line","bar","candlestick","scatter"],z8={easing:"cubicOut",duration:100,delay:0},G8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t._displayables={},t}return e.prototype.init=function(t,a){this.api=a,this._onBrush=$(this._onBrush,this),this._onBrushEnd=$(this._onBrushEnd,this)},e.prototype.render=function(t,a,n,i){if(r.prototype.render.apply(this,arguments),Po(this,"_dispatchZoomAction",t.get("throttle"),"fixRate"),this._orient=t.getOrient(),t.get("show")===!1){this.group.removeAll();return}if(t.noTarget()){this._clear(),this.group.removeAll();return}(!i||i.type!=="dataZoom"||i.from!==this.uid)&&this._buildView(),this._updateView()},e.prototype.dispose=function(){this._clear(),r.prototype.dispose.apply(this,arguments)},e.prototype._clear=function(){Js(this,"_dispatchZoomAction");var t=this.api.getZr();t.off("mousemove",this._onBrush),t.off("mouseup",this._onBrushEnd)},e.prototype._buildView=function(){var t=this.group;t.removeAll(),this._brushing=!1,this._displayables.brushRect=null,this._resetLocation(),this._resetInterval();var a=this._displayables.sliderGroup=new at;this._renderBackground(),this._renderHandle(),this._renderDataShadow(),t.add(a),this._positionGroup()},e.prototype._resetLocation=function(){var t=this.dataZoomModel,a=this.api,n=t.get("brushSelect"),i=n?N8:0,o=this._findCoordRect(),s={width:a.getWidth(),height:a.getHeight()},l=this._orient===us?{right:s.width-o.x-o.width,top:s.height-sp-zb-i,width:o.width,height:sp}:{right:zb,top:o.y,width:sp,height:o.height},u=Mo(t.option);D(["right","top","width","height"],function(h){u[h]==="ph"&&(u[h]=l[h])});var f=ee(u,s);this._location={x:f.x,y:f.y},this._size=[f.width,f.height],this._orient===Gb&&this._size.reverse()},e.prototype._positionGroup=function(){var t=this.group,a=this._location,n=this._orient,i=this.dataZoomModel.getFirstTargetAxisModel(),o=i&&i.get("inverse"),s=this._displayables.sliderGroup,l=(this._dataShadowInfo||{}).otherAxisInverse;s.attr(n===us&&!o?{scaleY:l?1:-1,scaleX:1}:n===us&&o?{scaleY:l?1:-1,scaleX:-1}:n===Gb&&!o?{scaleY:l?-1:1,scaleX:1,rotation:Math.PI/2}:{scaleY:l?-1:1,scaleX:-1,rotation:Math.PI/2});var u=t.getBoundingRect([s]);t.x=a.x-u.x,t.y=a.y-u.y,t.markRedraw()},e.prototype._getViewExtent=function(){return[0,this._size[0]]},e.prototype._renderBackground=function(){var t=this.dataZoomModel,a=this._size,n=this._displayables.sliderGroup,i=t.get("brushSelect");n.add(new ls({silent:!0,shape:{x:0,y:0,width:a[0],height:a[1]},style:{fill:t.get("backgroundColor")},z2:-40}));var o=new ls({shape:{x:0,y:0,width:a[0],height:a[1]},style:{fill:"transparent"},z2:0,onclick:$(this._onClickPanel,this)}),s=this.api.getZr();i?(o.on("mousedown",this._onBrushStart,this),o.cursor="crosshair",s.on("mousemove",this._onBrush),s.on("mouseup",this._onBrushEnd)):(s.off("mousemove",this._onBrush),s.off("mouseup",this._onBrushEnd)),n.add(o)},e.prototype._renderDataShadow=function(){var t=this._dataShadowInfo=this._prepareDataShadowInfo();if(this._displayables.dataShadowSegs=[],!t)return;var a=this._size,n=this._shadowSize||[],i=t.series,o=i.getRawData(),s=i.getShadowDim&&i.getShadowDim(),l=s&&o.getDimensionInfo(s)?i.getShadowDim():t.otherDim;if(l==null)return;var u=this._shadowPolygonPts,f=this._shadowPolylinePts;if(o!==this._shadowData||l!==this._shadowDim||a[0]!==n[0]||a[1]!==n[1]){var h=o.getDataExtent(l),v=(h[1]-h[0])*.3;h=[h[0]-v,h[1]+v];var c=[0,a[1]],p=[0,a[0]],d=[[a[0],0],[0,0]],g=[],y=p[1]/(o.count()-1),m=0,_=Math.round(o.count()/a[0]),S;o.each([l],function(A,C){if(_>0&&C%_){m+=y;return}var M=A==null||isNaN(A)||A==="",I=M?0:It(A,h,c,!0);M&&!S&&C?(d.push([d[d.length-1][0],0]),g.push([g[g.length-1][0],0])):!M&&S&&(d.push([m,0]),g.push([m,0])),d.push([m,I]),g.push([m,I]),m+=y,S=M}),u=this._shadowPolygonPts=d,f=this._shadowPolylinePts=g}this._shadowData=o,this._shadowDim=l,this._shadowSize=[a[0],a[1]];var b=this.dataZoomModel;function x(A){var C=b.getModel(A?"selectedDataBackground":"dataBackground"),M=new at,I=new Re({shape:{points:u},segmentIgnoreThreshold:1,style:C.getModel("areaStyle").getAreaStyle(),silent:!0,z2:-20}),L=new Ee({shape:{points:f},segmentIgnoreThreshold:1,style:C.getModel("lineStyle").getLineStyle(),silent:!0,z2:-19});return M.add(I),M.add(L),M}for(var w=0;w<3;w++){var T=x(w===1);this._displayables.sliderGroup.add(T),this._displayables.dataShadowSegs.push(T)}},e.prototype._prepareDataShadowInfo=function(){var t=this.dataZoomModel,a=t.get("showDataShadow");if(a!==!1){var n,i=this.ecModel;return t.eachTargetAxis(function(o,s){var l=t.getAxisProxy(o,s).getTargetSeriesModels();D(l,function(u){if(!n&&!(a!==!0&&pt(V8,u.get("type"))<0)){var f=i.getComponent(Ua(o),s).axis,h=F8(o),v,c=u.coordinateSystem;h!=null&&c.getOtherAxis&&(v=c.getOtherAxis(f).inverse),h=u.getData().mapDimension(h),n={thisAxis:f,series:u,thisDim:o,otherDim:h,otherAxisInverse:v}}},this)},this),n}},e.prototype._renderHandle=function(){var t=this.group,a=this._displayables,n=a.handles=[null,null],i=a.handleLabels=[null,null],o=this._displayables.sliderGroup,s=this._size,l=this.dataZoomModel,u=this.api,f=l.get("borderRadius")||0,h=l.get("brushSelect"),v=a.filler=new ls({silent:h,style:{fill:l.get("fillerColor")},textConfig:{position:"inside"}});o.add(v),o.add(new ls({silent:!0,subPixelOptimize:!0,shape:{x:0,y:0,width:s[0],height:s[1],r:f},style:{stroke:l.get("dataBackgroundColor")||l.get("borderColor"),lineWidth:O8,fill:"rgba(0,0,0,0)"}})),D([0,1],function(_){var S=l.get("handleIcon");!Af[S]&&S.indexOf("path://")<0&&S.indexOf("image://")<0&&(S="path://"+S);var b=Qt(S,-1,0,2,2,null,!0);b.attr({cursor:Fb(this._orient),draggable:!0,drift:$(this._onDragMove,this,_),ondragend:$(this._onDragEnd,this),onmouseover:$(this._showDataInfo,this,!0),onmouseout:$(this._showDataInfo,this,!1),z2:5});var x=b.getBoundingRect(),w=l.get("handleSize");this._handleHeight=W(w,this._size[1]),this._handleWidth=x.width/x.height*this._handleHeight,b.setStyle(l.getModel("handleStyle").getItemStyle()),b.style.strokeNoScale=!0,b.rectHover=!0,b.ensureState("emphasis").style=l.getModel(["emphasis","handleStyle"]).getItemStyle(),ti(b);var T=l.get("handleColor");T!=null&&(b.style.fill=T),o.add(n[_]=b);var A=l.getModel("textStyle"),C=l.get("handleLabel")||{},M=C.show||!1;t.add(i[_]=new wt({silent:!0,invisible:!M,style:zt(A,{x:0,y:0,text:"",verticalAlign:"middle",align:"center",fill:A.getTextColor(),font:A.getFont()}),z2:10}))},this);var c=v;if(h){var p=W(l.get("moveHandleSize"),s[1]),d=a.moveHandle=new xt({style:l.getModel("moveHandleStyle").getItemStyle(),silent:!0,shape:{r:[0,0,2,2],y:s[1]-.5,height:p}}),g=p*.8,y=a.moveHandleIcon=Qt(l.get("moveHandleIcon"),-g/2,-g/2,g,g,"#fff",!0);y.silent=!0,y.y=s[1]+p/2-.5,d.ensureState("emphasis").style=l.getModel(["emphasis","moveHandleStyle"]).getItemStyle();var m=Math.min(s[1]/2,Math.max(p,10));c=a.moveZone=new xt({invisible:!0,shape:{y:s[1]-m,height:p+m}}),c.on("mouseover",function(){u.enterEmphasis(d)}).on("mouseout",function(){u.leaveEmphasis(d)}),o.add(d),o.add(y),o.add(c)}c.attr({draggable:!0,cursor:Fb(this._orient),drift:$(this._onDragMove,this,"all"),ondragstart:$(this._showDataInfo,this,!0),ondragend:$(this._onDragEnd,this),onmouseover:$(this._showDataInfo,this,!0),onmouseout:$(this._showDataInfo,this,!1)})},e.prototype._resetInterval=function(){var t=this._range=this.dataZoomModel.getPercentRange(),a=this._getViewExtent();this._handleEnds=[It(t[0],[0,100],a,!0),It(t[1],[0,100],a,!0)]},e.prototype._updateInterval=function(t,a){var n=this.dataZoomModel,i=this._handleEnds,o=this._getViewExtent(),s=n.findRepresentativeAxisProxy().getMinMaxSpan(),l=[0,100];xi(a,i,o,n.get("zoomLock")?"all":t,s.minSpan!=null?It(s.minSpan,l,o,!0):null,s.maxSpan!=null?It(s.maxSpan,l,o,!0):null);var u=this._range,f=this._range=lr([It(i[0],o,l,!0),It(i[1],o,l,!0)]);return!u||u[0]!==f[0]||u[1]!==f[1]},e.prototype._updateView=function(t){var a=this._displayables,n=this._handleEnds,i=lr(n.slice()),o=this._size;D([0,1],function(c){var p=a.handles[c],d=this._handleHeight;p.attr({scaleX:d/2,scaleY:d/2,x:n[c]+(c?-1:1),y:o[1]/2-d/2})},this),a.filler.setShape({x:i[0],y:0,width:i[1]-i[0],height:o[1]});var s={x:i[0],width:i[1]-i[0]};a.moveHandle&&(a.moveHandle.setShape(s),a.moveZone.setShape(s),a.moveZone.getBoundingRect(),a.moveHandleIcon&&a.moveHandleIcon.attr("x",s.x+s.width/2));for(var l=a.dataShadowSegs,u=[0,i[0],i[1],o[0]],f=0;f<l.length;f++){var h=l[f],v=h.getClipPath();v||(v=new xt,h.setClipPath(v)),v.setShape({x:u[f],y:0,width:u[f+1]-u[f],height:o[1]})}this._updateDataInfo(t)},e.prototype._updateDataInfo=function(t){var a=this.dataZoomModel,n=this._displayables,i=n.handleLabels,o=this._orient,s=["",""];if(a.get("showDetail")){var l=a.findRepresentativeAxisProxy();if(l){var u=l.getAxisModel().axis,f=this._range,h=t?l.calculateDataWindow({start:f[0],end:f[1]}).valueWindow:l.getDataValueWindow();s=[this._formatLabel(h[0],u),this._formatLabel(h[1],u)]}}var v=lr(this._handleEnds.slice());c.call(this,0),c.call(this,1);function c(p){var d=ei(n.handles[p].parent,this.group),g=yh(p===0?"right":"left",d),y=this._handleWidth/2+B8,m=wr([v[p]+(p===0?-y:y),this._size[1]/2],d);i[p].setStyle({x:m[0],y:m[1],verticalAlign:o===us?"middle":g,align:o===us?g:"center",text:s[p]})}},e.prototype._formatLabel=function(t,a){var n=this.dataZoomModel,i=n.get("labelFormatter"),o=n.get("labelPrecision");(o==null||o==="auto")&&(o=a.getPixelPrecision());var s=t==null||isNaN(t)?"":a.type==="category"||a.type==="time"?a.scale.getLabel({value:Math.round(t)}):t.toFixed(Math.min(o,20));return J(i)?i(t,s):Y(i)?i.replace("{value}",s):s},e.prototype._showDataInfo=function(t){var a=this.dataZoomModel.get("handleLabel")||{},n=a.show||!1,i=this.dataZoomModel.getModel(["emphasis","handleLabel"]),o=i.get("show")||!1,s=t||this._dragging?o:n,l=this._displayables,u=l.handleLabels;u[0].attr("invisible",!s),u[1].attr("invisible",!s),l.moveHandle&&this.api[s?"enterEmphasis":"leaveEmphasis"](l.moveHandle,1)},e.prototype._onDragMove=function(t,a,n,i){this._dragging=!0,fa(i.event);var o=this._displayables.sliderGroup.getLocalTransform(),s=wr([a,n],o,!0),l=this._updateInterval(t,s[0]),u=this.dataZoomModel.get("realtime");this._updateView(!u),l&&u&&this._dispatchZoomAction(!0)},e.prototype._onDragEnd=function(){this._dragging=!1,this._showDataInfo(!1);var t=this.dataZoomModel.get("realtime");!t&&this._dispatchZoomAction(!1)},e.prototype._onClickPanel=function(t){var a=this._size,n=this._displayables.sliderGroup.transformCoordToLocal(t.offsetX,t.offsetY);if(!(n[0]<0||n[0]>a[0]||n[1]<0||n[1]>a[1])){var i=this._handleEnds,o=(i[0]+i[1])/2,s=this._updateInterval("all",n[0]-o);this._updateView(),s&&this._dispatchZoomAction(!1)}},e.prototype._onBrushStart=function(t){var a=t.offsetX,n=t.offsetY;this._brushStart=new ht(a,n),this._brushing=!0,this._brushStartTime=+new Date},e.prototype._onBrushEnd=function(t){if(this._brushing){var a=this._displayables.brushRect;if(this._brushing=!1,!!a){a.attr("ignore",!0);var n=a.shape,i=+new Date;if(!(i-this._brushStartTime<200&&Math.abs(n.width)<5)){var o=this._getViewExtent(),s=[0,100];this._range=lr([It(n.x,o,s,!0),It(n.x+n.width,o,s,!0)]),this._handleEnds=[n.x,n.x+n.width],this._updateView(),this._dispatchZoomAction(!1)}}}},e.prototype._onBrush=function(t){this._brushing&&(fa(t.event),this._updateBrushRect(t.offsetX,t.offsetY))},e.prototype._updateBrushRect=function(t,a){var n=this._displayables,i=this.dataZoomModel,o=n.brushRect;o||(o=n.brushRect=new ls({silent:!0,style:i.getModel("brushStyle").getItemStyle()}),n.sliderGroup.add(o)),o.attr("ignore",!1);var s=this._brushStart,l=this._displayables.sliderGroup,u=l.transformCoordToLocal(t,a),f=l.transformCoordToLocal(s.x,s.y),h=this._size;u[0]=Math.max(Math.min(h[0],u[0]),0),o.setShape({x:f[0],y:0,width:u[0]-f[0],height:h[1]})},e.prototype._dispatchZoomAction=function(t){var a=this._range;this.api.dispatchAction({type:"dataZoom",from:this.uid,dataZoomId:this.dataZoomModel.id,animation:t?z8:null,start:a[0],end:a[1]})},e.prototype._findCoordRect=function(){var t,a=xI(this.dataZoomModel).infoList;if(!t&&a.length){var n=a[0].model.coordinateSystem;t=n.getRect&&n.getRect()}if(!t){var i=this.api.getWidth(),o=this.api.getHeight();t={x:i*.2,y:o*.2,width:i*.6,height:o*.6}}return t},e.type="dataZoom.slider",e})(tm);function F8(r){var e={x:"y",y:"x",radius:"angle",angle:"radius"};return e[r]}function Fb(r){return r==="vertical"?"ns-resize":"ew-resize"}function FI(r){r.registerComponentModel(k8),r.registerComponentView(G8),em(r)}function H8(r){gt(GI),gt(FI)}var HI={get:function(r,e,t){var a=rt((W8[r]||{})[e]);return t&&z(a)?a[a.length-1]:a}},W8={color:{active:["#006edd","#e0ffff"],inactive:["rgba(0,0,0,0)"]},colorHue:{active:[0,360],inactive:[0,0]},colorSaturation:{active:[.3,1],inactive:[0,0]},colorLightness:{active:[.9,.5],inactive:[0,0]},colorAlpha:{active:[.3,1],inactive:[0,0]},opacity:{active:[.3,1],inactive:[0,0]},symbol:{active:["circle","roundRect","diamond"],inactive:["none"]},symbolSize:{active:[10,50],inactive:[0,0]}},Hb=oe.mapVisual,U8=oe.eachVisual,Y8=z,Wb=D,$8=lr,X8=It,qf=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t.stateList=["inRange","outOfRange"],t.replacableOptionKeys=["inRange","outOfRange","target","controller","color"],t.layoutMode={type:"box",ignoreSize:!0},t.dataBound=[-1/0,1/0],t.targetVisuals={},t.controllerVisuals={},t}return e.prototype.init=function(t,a,n){this.mergeDefaultAndTheme(t,n)},e.prototype.optionUpdated=function(t,a){var n=this.option;!a&&PI(n,t,this.replacableOptionKeys),this.textStyleModel=this.getModel("textStyle"),this.resetItemSize(),this.completeVisualOption()},e.prototype.resetVisual=function(t){var a=this.stateList;t=$(t,this),this.controllerVisuals=$d(this.option.controller,a,t),this.targetVisuals=$d(this.option.target,a,t)},e.prototype.getItemSymbol=function(){return null},e.prototype.getTargetSeriesIndices=function(){var t=this.option.seriesIndex,a=[];return t==null||t==="all"?this.ecModel.eachSeries(function(n,i){a.push(i)}):a=Et(t),a},e.prototype.eachTargetSeries=function(t,a){D(this.getTargetSeriesIndices(),function(n){var i=this.ecModel.getSeriesByIndex(n);i&&t.call(a,i)},this)},e.prototype.isTargetSeries=function(t){var a=!1;return this.eachTargetSeries(function(n){n===t&&(a=!0)}),a},e.prototype.formatValueText=function(t,a,n){var i=this.option,o=i.precision,s=this.dataBound,l=i.formatter,u;n=n||["<",">"],z(t)&&(t=t.slice(),u=!0);var f=a?t:u?[h(t[0]),h(t[1])]:h(t);if(Y(l))return l.replace("{value}",u?f[0]:f).replace("{value2}",u?f[1]:f);if(J(l))return u?l(t[0],t[1]):l(t);if(u)return t[0]===s[0]?n[0]+" "+f[1]:t[1]===s[1]?n[1]+" "+f[0]:f[0]+" - "+f[1];return f;function h(v){return v===s[0]?"min":v===s[1]?"max":(+v).toFixed(Math.min(o,20))}},e.prototype.resetExtent=function(){var t=this.option,a=$8([t.min,t.max]);this._dataExtent=a},e.prototype.getDataDimensionIndex=function(t){var a=this.option.dimension;if(a!=null)return t.getDimensionIndex(a);for(var n=t.dimensions,i=n.length-1;i>=0;i--){var o=n[i],s=t.getDimensionInfo(o);if(!s.isCalculationCoord)return s.storeDimIndex}},e.prototype.getExtent=function(){return this._dataExtent.slice()},e.prototype.completeVisualOption=function(){var t=this.ecModel,a=this.option,n={inRange:a.inRange,outOfRange:a.outOfRange},i=a.target||(a.target={}),o=a.controller||(a.controller={});ft(i,n),ft(o,n);var s=this.isCategory();l.call(this,i),l.call(this,o),u.call(this,i,"inRange","outOfRange"),f.call(this,o);function l(h){Y8(a.color)&&!h.inRange&&(h.inRange={color:a.color.slice().reverse()}),h.inRange=h.inRange||{color:t.get("gradientColor")}}function u(h,v,c){var p=h[v],d=h[c];p&&!d&&(d=h[c]={},Wb(p,function(g,y){if(oe.isValidType(y)){var m=HI.get(y,"inactive",s);m!=null&&(d[y]=m,y==="color"&&!d.hasOwnProperty("opacity")&&!d.hasOwnProperty("colorAlpha")&&(d.opacity=[0,0]))}}))}function f(h){var v=(h.inRange||{}).symbol||(h.outOfRange||{}).symbol,c=(h.inRange||{}).symbolSize||(h.outOfRange||{}).symbolSize,p=this.get("inactiveColor"),d=this.getItemSymbol(),g=d||"roundRect";Wb(this.stateList,function(y){var m=this.itemSize,_=h[y];_||(_=h[y]={color:s?p:[p]}),_.symbol==null&&(_.symbol=v&&rt(v)||(s?g:[g])),_.symbolSize==null&&(_.symbolSize=c&&rt(c)||(s?m[0]:[m[0],m[0]])),_.symbol=Hb(_.symbol,function(x){return x==="none"?g:x});var S=_.symbolSize;if(S!=null){var b=-1/0;U8(S,function(x){x>b&&(b=x)}),_.symbolSize=Hb(S,function(x){return X8(x,[0,b],[0,m[0]],!0)})}},this)}},e.prototype.resetItemSize=function(){this.itemSize=[parseFloat(this.get("itemWidth")),parseFloat(this.get("itemHeight"))]},e.prototype.isCategory=function(){return!!this.option.categories},e.prototype.setSelected=function(t){},e.prototype.getSelected=function(){return null},e.prototype.getValueState=function(t){return null},e.prototype.getVisualMeta=function(t){return null},e.type="visualMap",e.dependencies=["series"],e.defaultOption={show:!0,z:4,seriesIndex:"all",min:0,max:200,left:0,right:null,top:null,bottom:0,itemWidth:null,itemHeight:null,inverse:!1,orient:"vertical",backgroundColor:"rgba(0,0,0,0)",borderColor:"#ccc",contentColor:"#5793f3",inactiveColor:"#aaa",borderWidth:0,padding:5,textGap:10,precision:0,textStyle:{color:"#333"}},e})(_t),Ub=[20,140],Z8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.prototype.optionUpdated=function(t,a){r.prototype.optionUpdated.apply(this,arguments),this.resetExtent(),this.resetVisual(function(n){n.mappingMethod="linear",n.dataExtent=this.getExtent()}),this._resetRange()},e.prototype.resetItemSize=function(){r.prototype.resetItemSize.apply(this,arguments);var t=this.itemSize;(t[0]==null||isNaN(t[0]))&&(t[0]=Ub[0]),(t[1]==null||isNaN(t[1]))&&(t[1]=Ub[1])},e.prototype._resetRange=function(){var t=this.getExtent(),a=this.option.range;!a||a.auto?(t.auto=1,this.option.range=t):z(a)&&(a[0]>a[1]&&a.reverse(),a[0]=Math.max(a[0],t[0]),a[1]=Math.min(a[1],t[1]))},e.prototype.completeVisualOption=function(){r.prototype.completeVisualOption.apply(this,arguments),D(this.stateList,function(t){var a=this.option.controller[t].symbolSize;a&&a[0]!==a[1]&&(a[0]=a[1]/3)},this)},e.prototype.setSelected=function(t){this.option.range=t.slice(),this._resetRange()},e.prototype.getSelected=function(){var t=this.getExtent(),a=lr((this.get("range")||[]).slice());return a[0]>t[1]&&(a[0]=t[1]),a[1]>t[1]&&(a[1]=t[1]),a[0]<t[0]&&(a[0]=t[0]),a[1]<t[0]&&(a[1]=t[0]),a},e.prototype.getValueState=function(t){var a=this.option.range,n=this.getExtent();return(a[0]<=n[0]||a[0]<=t)&&(a[1]>=n[1]||t<=a[1])?"inRange":"outOfRange"},e.prototype.findTargetDataIndices=function(t){var a=[];return this.eachTargetSeries(function(n){var i=[],o=n.getData();o.each(this.getDataDimensionIndex(o),function(s,l){t[0]<=s&&s<=t[1]&&i.push(l)},this),a.push({seriesId:n.id,dataIndex:i})},this),a},e.prototype.getVisualMeta=function(t){var a=Yb(this,"outOfRange",this.getExtent()),n=Yb(this,"inRange",this.option.range.slice()),i=[];function o(c,p){i.push({value:c,color:t(c,p)})}for(var s=0,l=0,u=n.length,f=a.length;l<f&&(!n.length||a[l]<=n[0]);l++)a[l]<n[s]&&o(a[l],"outOfRange");for(var h=1;s<u;s++,h=0)h&&i.length&&o(n[s],"outOfRange"),o(n[s],"inRange");for(var h=1;l<f;l++)(!n.length||n[n.length-1]<a[l])&&(h&&(i.length&&o(i[i.length-1].value,"outOfRange"),h=0),o(a[l],"outOfRange"));var v=i.length;return{stops:i,outerColors:[v?i[0].color:"transparent",v?i[v-1].color:"transparent"]}},e.type="visualMap.continuous",e.defaultOption=nn(qf.defaultOption,{align:"auto",calculable:!1,hoverLink:!0,realtime:!0,handleIcon:"path://M-11.39,9.77h0a3.5,3.5,0,0,1-3.5,3.5h-22a3.5,3.5,0,0,1-3.5-3.5h0a3.5,3.5,0,0,1,3.5-3.5h22A3.5,3.5,0,0,1-11.39,9.77Z",handleSize:"120%",handleStyle:{borderColor:"#fff",borderWidth:1},indicatorIcon:"circle",indicatorSize:"50%",indicatorStyle:{borderColor:"#fff",borderWidth:2,shadowBlur:2,shadowOffsetX:1,shadowOffsetY:1,shadowColor:"rgba(0,0,0,0.2)"}}),e})(qf);function Yb(r,e,t){if(t[0]===t[1])return t.slice();for(var a=200,n=(t[1]-t[0])/a,i=t[0],o=[],s=0;s<=a&&i<t[1];s++)o.push(i),i+=n;return o.push(t[1]),o}var WI=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t.autoPositionValues={left:1,right:1,top:1,bottom:1},t}return e.prototype.init=function(t,a){this.ecModel=t,this.api=a},e.prototype.render=function(t,a,n,i){if(this.visualMapModel=t,t.get("show")===!1){this.group.removeAll();return}this.doRender(t,a,n,i)},e.prototype.renderBackground=function(t){var a=this.visualMapModel,n=Do(a.get("padding")||0),i=t.getBoundingRect();t.add(new xt({z2:-1,silent:!0,shape:{x:i.x-n[3],y:i.y-n[0],width:i.width+n[3]+n[1],height:i.height+n[0]+n[2]},style:{fill:a.get("backgroundColor"),stroke:a.get("borderColor"),lineWidth:a.get("borderWidth")}}))},e.prototype.getControllerVisual=function(t,a,n){n=n||{};var i=n.forceState,o=this.visualMapModel,s={};if(a==="color"){var l=o.get("contentColor");s.color=l}function u(c){return s[c]}function f(c,p){s[c]=p}var h=o.controllerVisuals[i||o.getValueState(t)],v=oe.prepareVisualTypes(h);return D(v,function(c){var p=h[c];n.convertOpacityToAlpha&&c==="opacity"&&(c="colorAlpha",p=h.__alphaForOpacity),oe.dependsOn(c,a)&&p&&p.applyVisual(t,u,f)}),s[a]},e.prototype.positionGroup=function(t){var a=this.visualMapModel,n=this.api;Th(t,a.getBoxLayoutParams(),{width:n.getWidth(),height:n.getHeight()})},e.prototype.doRender=function(t,a,n,i){},e.type="visualMap",e})(Ut),$b=[["left","right","width"],["top","bottom","height"]];function UI(r,e,t){var a=r.option,n=a.align;if(n!=null&&n!=="auto")return n;for(var i={width:e.getWidth(),height:e.getHeight()},o=a.orient==="horizontal"?1:0,s=$b[o],l=[0,null,10],u={},f=0;f<3;f++)u[$b[1-o][f]]=l[f],u[s[f]]=f===2?t[0]:a[s[f]];var h=[["x","width",3],["y","height",0]][o],v=ee(u,i,a.padding);return s[(v.margin[h[2]]||0)+v[h[0]]+v[h[1]]*.5<i[h[1]]*.5?0:1]}function nf(r,e){return D(r||[],function(t){t.dataIndex!=null&&(t.dataIndexInside=t.dataIndex,t.dataIndex=null),t.highlightKey="visualMap"+(e?e.componentIndex:"")}),r}var kr=It,q8=D,Xb=Math.min,lp=Math.max,K8=12,J8=6,Q8=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t._shapes={},t._dataInterval=[],t._handleEnds=[],t._hoverLinkDataIndices=[],t}return e.prototype.init=function(t,a){r.prototype.init.call(this,t,a),this._hoverLinkFromSeriesMouseOver=$(this._hoverLinkFromSeriesMouseOver,this),this._hideIndicator=$(this._hideIndicator,this)},e.prototype.doRender=function(t,a,n,i){(!i||i.type!=="selectDataRange"||i.from!==this.uid)&&this._buildView()},e.prototype._buildView=function(){this.group.removeAll();var t=this.visualMapModel,a=this.group;this._orient=t.get("orient"),this._useHandle=t.get("calculable"),this._resetInterval(),this._renderBar(a);var n=t.get("text");this._renderEndsText(a,n,0),this._renderEndsText(a,n,1),this._updateView(!0),this.renderBackground(a),this._updateView(),this._enableHoverLinkToSeries(),this._enableHoverLinkFromSeries(),this.positionGroup(a)},e.prototype._renderEndsText=function(t,a,n){if(a){var i=a[1-n];i=i!=null?i+"":"";var o=this.visualMapModel,s=o.get("textGap"),l=o.itemSize,u=this._shapes.mainGroup,f=this._applyTransform([l[0]/2,n===0?-s:l[1]+s],u),h=this._applyTransform(n===0?"bottom":"top",u),v=this._orient,c=this.visualMapModel.textStyleModel;this.group.add(new wt({style:zt(c,{x:f[0],y:f[1],verticalAlign:v==="horizontal"?"middle":h,align:v==="horizontal"?h:"center",text:i})}))}},e.prototype._renderBar=function(t){var a=this.visualMapModel,n=this._shapes,i=a.itemSize,o=this._orient,s=this._useHandle,l=UI(a,this.api,i),u=n.mainGroup=this._createBarGroup(l),f=new at;u.add(f),f.add(n.outOfRange=Zb()),f.add(n.inRange=Zb(null,s?Kb(this._orient):null,$(this._dragHandle,this,"all",!1),$(this._dragHandle,this,"all",!0))),f.setClipPath(new xt({shape:{x:0,y:0,width:i[0],height:i[1],r:3}}));var h=a.textStyleModel.getTextRect("国"),v=lp(h.width,h.height);s&&(n.handleThumbs=[],n.handleLabels=[],n.handleLabelPoints=[],this._createHandle(a,u,0,i,v,o),this._createHandle(a,u,1,i,v,o)),this._createIndicator(a,u,i,v,o),t.add(u)},e.prototype._createHandle=function(t,a,n,i,o,s){var l=$(this._dragHandle,this,n,!1),u=$(this._dragHandle,this,n,!0),f=Tr(t.get("handleSize"),i[0]),h=Qt(t.get("handleIcon"),-f/2,-f/2,f,f,null,!0),v=Kb(this._orient);h.attr({cursor:v,draggable:!0,drift:l,ondragend:u,onmousemove:function(y){fa(y.event)}}),h.x=i[0]/2,h.useStyle(t.getModel("handleStyle").getItemStyle()),h.setStyle({strokeNoScale:!0,strokeFirst:!0}),h.style.lineWidth*=2,h.ensureState("emphasis").style=t.getModel(["emphasis","handleStyle"]).getItemStyle(),Un(h,!0),a.add(h);var c=this.visualMapModel.textStyleModel,p=new wt({cursor:v,draggable:!0,drift:l,onmousemove:function(y){fa(y.event)},ondragend:u,style:zt(c,{x:0,y:0,text:""})});p.ensureState("blur").style={opacity:.1},p.stateTransition={duration:200},this.group.add(p);var d=[f,0],g=this._shapes;g.handleThumbs[n]=h,g.handleLabelPoints[n]=d,g.handleLabels[n]=p},e.prototype._createIndicator=function(t,a,n,i,o){var s=Tr(t.get("indicatorSize"),n[0]),l=Qt(t.get("indicatorIcon"),-s/2,-s/2,s,s,null,!0);l.attr({cursor:"move",invisible:!0,silent:!0,x:n[0]/2});var u=t.getModel("indicatorStyle").getItemStyle();if(l instanceof he){var f=l.style;l.useStyle(V({image:f.image,x:f.x,y:f.y,width:f.width,height:f.height},u))}else l.useStyle(u);a.add(l);var h=this.visualMapModel.textStyleModel,v=new wt({silent:!0,invisible:!0,style:zt(h,{x:0,y:0,text:""})});this.group.add(v);var c=[(o==="horizontal"?i/2:J8)+n[0]/2,0],p=this._shapes;p.indicator=l,p.indicatorLabel=v,p.indicatorLabelPoint=c,this._firstShowIndicator=!0},e.prototype._dragHandle=function(t,a,n,i){if(this._useHandle){if(this._dragging=!a,!a){var o=this._applyTransform([n,i],this._shapes.mainGroup,!0);this._updateInterval(t,o[1]),this._hideIndicator(),this._updateView()}a===!this.visualMapModel.get("realtime")&&this.api.dispatchAction({type:"selectDataRange",from:this.uid,visualMapId:this.visualMapModel.id,selected:this._dataInterval.slice()}),a?!this._hovering&&this._clearHoverLinkToSeries():qb(this.visualMapModel)&&this._doHoverLinkToSeries(this._handleEnds[t],!1)}},e.prototype._resetInterval=function(){var t=this.visualMapModel,a=this._dataInterval=t.getSelected(),n=t.getExtent(),i=[0,t.itemSize[1]];this._handleEnds=[kr(a[0],n,i,!0),kr(a[1],n,i,!0)]},e.prototype._updateInterval=function(t,a){a=a||0;var n=this.visualMapModel,i=this._handleEnds,o=[0,n.itemSize[1]];xi(a,i,o,t,0);var s=n.getExtent();this._dataInterval=[kr(i[0],o,s,!0),kr(i[1],o,s,!0)]},e.prototype._updateView=function(t){var a=this.visualMapModel,n=a.getExtent(),i=this._shapes,o=[0,a.itemSize[1]],s=t?o:this._handleEnds,l=this._createBarVisual(this._dataInterval,n,s,"inRange"),u=this._createBarVisual(n,n,o,"outOfRange");i.inRange.setStyle({fill:l.barColor}).setShape("points",l.barPoints),i.outOfRange.setStyle({fill:u.barColor}).setShape("points",u.barPoints),this._updateHandle(s,l)},e.prototype._createBarVisual=function(t,a,n,i){var o={forceState:i,convertOpacityToAlpha:!0},s=this._makeColorGradient(t,o),l=[this.getControllerVisual(t[0],"symbolSize",o),this.getControllerVisual(t[1],"symbolSize",o)],u=this._createBarPoints(n,l);return{barColor:new Sl(0,0,0,1,s),barPoints:u,handlesColor:[s[0].color,s[s.length-1].color]}},e.prototype._makeColorGradient=function(t,a){var n=100,i=[],o=(t[1]-t[0])/n;i.push({color:this.getControllerVisual(t[0],"color",a),offset:0});for(var s=1;s<n;s++){var l=t[0]+o*s;if(l>t[1])break;i.push({color:this.getControllerVisual(l,"color",a),offset:s/n})}return i.push({color:this.getControllerVisual(t[1],"color",a),offset:1}),i},e.prototype._createBarPoints=function(t,a){var n=this.visualMapModel.itemSize;return[[n[0]-a[0],t[0]],[n[0],t[0]],[n[0],t[1]],[n[0]-a[1],t[1]]]},e.prototype._createBarGroup=function(t){var a=this._orient,n=this.visualMapModel.get("inverse");return new at(a==="horizontal"&&!n?{scaleX:t==="bottom"?1:-1,rotation:Math.PI/2}:a==="horizontal"&&n?{scaleX:t==="bottom"?-1:1,rotation:-Math.PI/2}:a==="vertical"&&!n?{scaleX:t==="left"?1:-1,scaleY:-1}:{scaleX:t==="left"?1:-1})},e.prototype._updateHandle=function(t,a){if(this._useHandle){var n=this._shapes,i=this.visualMapModel,o=n.handleThumbs,s=n.handleLabels,l=i.itemSize,u=i.getExtent(),f=this._applyTransform("left",n.mainGroup);q8([0,1],function(h){var v=o[h];v.setStyle("fill",a.handlesColor[h]),v.y=t[h];var c=kr(t[h],[0,l[1]],u,!0),p=this.getControllerVisual(c,"symbolSize");v.scaleX=v.scaleY=p/l[0],v.x=l[0]-p/2;var d=wr(n.handleLabelPoints[h],ei(v,this.group));if(this._orient==="horizontal"){var g=f==="left"||f==="top"?(l[0]-p)/2:(l[0]-p)/-2;d[1]+=g}s[h].setStyle({x:d[0],y:d[1],text:i.formatValueText(this._dataInterval[h]),verticalAlign:"middle",align:this._orient==="vertical"?this._applyTransform("left",n.mainGroup):"center"})},this)}},e.prototype._showIndicator=function(t,a,n,i){var o=this.visualMapModel,s=o.getExtent(),l=o.itemSize,u=[0,l[1]],f=this._shapes,h=f.indicator;if(h){h.attr("invisible",!1);var v={convertOpacityToAlpha:!0},c=this.getControllerVisual(t,"color",v),p=this.getControllerVisual(t,"symbolSize"),d=kr(t,s,u,!0),g=l[0]-p/2,y={x:h.x,y:h.y};h.y=d,h.x=g;var m=wr(f.indicatorLabelPoint,ei(h,this.group)),_=f.indicatorLabel;_.attr("invisible",!1);var S=this._applyTransform("left",f.mainGroup),b=this._orient,x=b==="horizontal";_.setStyle({text:(n||"")+o.formatValueText(a),verticalAlign:x?S:"middle",align:x?"center":S});var w={x:g,y:d,style:{fill:c}},T={style:{x:m[0],y:m[1]}};if(o.ecModel.isAnimationEnabled()&&!this._firstShowIndicator){var A={duration:100,easing:"cubicInOut",additive:!0};h.x=y.x,h.y=y.y,h.animateTo(w,A),_.animateTo(T,A)}else h.attr(w),_.attr(T);this._firstShowIndicator=!1;var C=this._shapes.handleLabels;if(C)for(var M=0;M<C.length;M++)this.api.enterBlur(C[M])}},e.prototype._enableHoverLinkToSeries=function(){var t=this;this._shapes.mainGroup.on("mousemove",function(a){if(t._hovering=!0,!t._dragging){var n=t.visualMapModel.itemSize,i=t._applyTransform([a.offsetX,a.offsetY],t._shapes.mainGroup,!0,!0);i[1]=Xb(lp(0,i[1]),n[1]),t._doHoverLinkToSeries(i[1],0<=i[0]&&i[0]<=n[0])}}).on("mouseout",function(){t._hovering=!1,!t._dragging&&t._clearHoverLinkToSeries()})},e.prototype._enableHoverLinkFromSeries=function(){var t=this.api.getZr();this.visualMapModel.option.hoverLink?(t.on("mouseover",this._hoverLinkFromSeriesMouseOver,this),t.on("mouseout",this._hideIndicator,this)):this._clearHoverLinkFromSeries()},e.prototype._doHoverLinkToSeries=function(t,a){var n=this.visualMapModel,i=n.itemSize;if(n.option.hoverLink){var o=[0,i[1]],s=n.getExtent();t=Xb(lp(o[0],t),o[1]);var l=j8(n,s,o),u=[t-l,t+l],f=kr(t,o,s,!0),h=[kr(u[0],o,s,!0),kr(u[1],o,s,!0)];u[0]<o[0]&&(h[0]=-1/0),u[1]>o[1]&&(h[1]=1/0),a&&(h[0]===-1/0?this._showIndicator(f,h[1],"< ",l):h[1]===1/0?this._showIndicator(f,h[0],"> ",l):this._showIndicator(f,f,"≈ ",l));var v=this._hoverLinkDataIndices,c=[];(a||qb(n))&&(c=this._hoverLinkDataIndices=n.findTargetDataIndices(h));var p=oP(v,c);this._dispatchHighDown("downplay",nf(p[0],n)),this._dispatchHighDown("highlight",nf(p[1],n))}},e.prototype._hoverLinkFromSeriesMouseOver=function(t){var a;if($n(t.target,function(l){var u=nt(l);if(u.dataIndex!=null)return a=u,!0},!0),!!a){var n=this.ecModel.getSeriesByIndex(a.seriesIndex),i=this.visualMapModel;if(i.isTargetSeries(n)){var o=n.getData(a.dataType),s=o.getStore().get(i.getDataDimensionIndex(o),a.dataIndex);isNaN(s)||this._showIndicator(s,s)}}},e.prototype._hideIndicator=function(){var t=this._shapes;t.indicator&&t.indicator.attr("invisible",!0),t.indicatorLabel&&t.indicatorLabel.attr("invisible",!0);var a=this._shapes.handleLabels;if(a)for(var n=0;n<a.length;n++)this.api.leaveBlur(a[n])},e.prototype._clearHoverLinkToSeries=function(){this._hideIndicator();var t=this._hoverLinkDataIndices;this._dispatchHighDown("downplay",nf(t,this.visualMapModel)),t.length=0},e.prototype._clearHoverLinkFromSeries=function(){this._hideIndicator();var t=this.api.getZr();t.off("mouseover",this._hoverLinkFromSeriesMouseOver),t.off("mouseout",this._hideIndicator)},e.prototype._applyTransform=function(t,a,n,i){var o=ei(a,i?null:this.group);return z(t)?wr(t,o,n):yh(t,o,n)},e.prototype._dispatchHighDown=function(t,a){a&&a.length&&this.api.dispatchAction({type:t,batch:a})},e.prototype.dispose=function(){this._clearHoverLinkFromSeries(),this._clearHoverLinkToSeries()},e.type="visualMap.continuous",e})(WI);function Zb(r,e,t,a){return new Re({shape:{points:r},draggable:!!t,cursor:e,drift:t,onmousemove:function(n){fa(n.event)},ondragend:a})}function j8(r,e,t){var a=K8/2,n=r.get("hoverLinkDataSize");return n&&(a=kr(n,e,t,!0)/2),a}function qb(r){var e=r.get("hoverLinkOnHandle");return!!(e??r.get("realtime"))}function Kb(r){return r==="vertical"?"ns-resize":"ew-resize"}var tY={type:"selectDataRange",event:"dataRangeSelected",update:"update"},eY=function(r,e){e.eachComponent({mainType:"visualMap",query:r},function(t){t.setSelected(r.selected)})},rY=[{createOnAllSeries:!0,reset:function(r,e){var t=[];return e.eachComponent("visualMap",function(a){var n=r.pipelineContext;!a.isTargetSeries(r)||n&&n.large||t.push(TU(a.stateList,a.targetVisuals,$(a.getValueState,a),a.getDataDimensionIndex(r.getData())))}),t}},{createOnAllSeries:!0,reset:function(r,e){var t=r.getData(),a=[];e.eachComponent("visualMap",function(n){if(n.isTargetSeries(r)){var i=n.getVisualMeta($(aY,null,r,n))||{stops:[],outerColors:[]},o=n.getDataDimensionIndex(t);o>=0&&(i.dimension=o,a.push(i))}}),r.getData().setVisual("visualMeta",a)}}];function aY(r,e,t,a){for(var n=e.targetVisuals[a],i=oe.prepareVisualTypes(n),o={color:Tl(r.getData(),"color")},s=0,l=i.length;s<l;s++){var u=i[s],f=n[u==="opacity"?"__alphaForOpacity":u];f&&f.applyVisual(t,h,v)}return o.color;function h(c){return o[c]}function v(c,p){o[c]=p}}var Jb=D;function nY(r){var e=r&&r.visualMap;z(e)||(e=e?[e]:[]),Jb(e,function(t){if(t){Hi(t,"splitList")&&!Hi(t,"pieces")&&(t.pieces=t.splitList,delete t.splitList);var a=t.pieces;a&&z(a)&&Jb(a,function(n){et(n)&&(Hi(n,"start")&&!Hi(n,"min")&&(n.min=n.start),Hi(n,"end")&&!Hi(n,"max")&&(n.max=n.end))})}})}function Hi(r,e){return r&&r.hasOwnProperty&&r.hasOwnProperty(e)}var Qb=!1;function YI(r){Qb||(Qb=!0,r.registerSubTypeDefaulter("visualMap",function(e){return!e.categories&&(!(e.pieces?e.pieces.length>0:e.splitNumber>0)||e.calculable)?"continuous":"piecewise"}),r.registerAction(tY,eY),D(rY,function(e){r.registerVisual(r.PRIORITY.VISUAL.COMPONENT,e)}),r.registerPreprocessor(nY))}function $I(r){r.registerComponentModel(Z8),r.registerComponentView(Q8),YI(r)}var iY=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t._pieceList=[],t}return e.prototype.optionUpdated=function(t,a){r.prototype.optionUpdated.apply(this,arguments),this.resetExtent();var n=this._mode=this._determineMode();this._pieceList=[],oY[this._mode].call(this,this._pieceList),this._resetSelected(t,a);var i=this.option.categories;this.resetVisual(function(o,s){n==="categories"?(o.mappingMethod="category",o.categories=rt(i)):(o.dataExtent=this.getExtent(),o.mappingMethod="piecewise",o.pieceList=G(this._pieceList,function(l){return l=rt(l),s!=="inRange"&&(l.visual=null),l}))})},e.prototype.completeVisualOption=function(){var t=this.option,a={},n=oe.listVisualTypes(),i=this.isCategory();D(t.pieces,function(s){D(n,function(l){s.hasOwnProperty(l)&&(a[l]=1)})}),D(a,function(s,l){var u=!1;D(this.stateList,function(f){u=u||o(t,f,l)||o(t.target,f,l)},this),!u&&D(this.stateList,function(f){(t[f]||(t[f]={}))[l]=HI.get(l,f==="inRange"?"active":"inactive",i)})},this);function o(s,l,u){return s&&s[l]&&s[l].hasOwnProperty(u)}r.prototype.completeVisualOption.apply(this,arguments)},e.prototype._resetSelected=function(t,a){var n=this.option,i=this._pieceList,o=(a?n:t).selected||{};if(n.selected=o,D(i,function(l,u){var f=this.getSelectedMapKey(l);o.hasOwnProperty(f)||(o[f]=!0)},this),n.selectedMode==="single"){var s=!1;D(i,function(l,u){var f=this.getSelectedMapKey(l);o[f]&&(s?o[f]=!1:s=!0)},this)}},e.prototype.getItemSymbol=function(){return this.get("itemSymbol")},e.prototype.getSelectedMapKey=function(t){return this._mode==="categories"?t.value+"":t.index+""},e.prototype.getPieceList=function(){return this._pieceList},e.prototype._determineMode=function(){var t=this.option;return t.pieces&&t.pieces.length>0?"pieces":this.option.categories?"categories":"splitNumber"},e.prototype.setSelected=function(t){this.option.selected=rt(t)},e.prototype.getValueState=function(t){var a=oe.findPieceIndex(t,this._pieceList);return a!=null&&this.option.selected[this.getSelectedMapKey(this._pieceList[a])]?"inRange":"outOfRange"},e.prototype.findTargetDataIndices=function(t){var a=[],n=this._pieceList;return this.eachTargetSeries(function(i){var o=[],s=i.getData();s.each(this.getDataDimensionIndex(s),function(l,u){var f=oe.findPieceIndex(l,n);f===t&&o.push(u)},this),a.push({seriesId:i.id,dataIndex:o})},this),a},e.prototype.getRepresentValue=function(t){var a;if(this.isCategory())a=t.value;else if(t.value!=null)a=t.value;else{var n=t.interval||[];a=n[0]===-1/0&&n[1]===1/0?0:(n[0]+n[1])/2}return a},e.prototype.getVisualMeta=function(t){if(this.isCategory())return;var a=[],n=["",""],i=this;function o(f,h){var v=i.getRepresentValue({interval:f});h||(h=i.getValueState(v));var c=t(v,h);f[0]===-1/0?n[0]=c:f[1]===1/0?n[1]=c:a.push({value:f[0],color:c},{value:f[1],color:c})}var s=this._pieceList.slice();if(!s.length)s.push({interval:[-1/0,1/0]});else{var l=s[0].interval[0];l!==-1/0&&s.unshift({interval:[-1/0,l]}),l=s[s.length-1].interval[1],l!==1/0&&s.push({interval:[l,1/0]})}var u=-1/0;return D(s,function(f){var h=f.interval;h&&(h[0]>u&&o([u,h[0]],"outOfRange"),o(h.slice()),u=h[1])},this),{stops:a,outerColors:n}},e.type="visualMap.piecewise",e.defaultOption=nn(qf.defaultOption,{selected:null,minOpen:!1,maxOpen:!1,align:"auto",itemWidth:20,itemHeight:14,itemSymbol:"roundRect",pieces:null,categories:null,splitNumber:5,selectedMode:"multiple",itemGap:10,hoverLink:!0}),e})(qf),oY={splitNumber:function(r){var e=this.option,t=Math.min(e.precision,20),a=this.getExtent(),n=e.splitNumber;n=Math.max(parseInt(n,10),1),e.splitNumber=n;for(var i=(a[1]-a[0])/n;+i.toFixed(t)!==i&&t<5;)t++;e.precision=t,i=+i.toFixed(t),e.minOpen&&r.push({interval:[-1/0,a[0]],close:[0,0]});for(var o=0,s=a[0];o<n;s+=i,o++){var l=o===n-1?a[1]:s+i;r.push({interval:[s,l],close:[1,1]})}e.maxOpen&&r.push({interval:[a[1],1/0],close:[0,0]}),Um(r),D(r,function(u,f){u.index=f,u.text=this.formatValueText(u.interval)},this)},categories:function(r){var e=this.option;D(e.categories,function(t){r.push({text:this.formatValueText(t,!0),value:t})},this),jb(e,r)},pieces:function(r){var e=this.option;D(e.pieces,function(t,a){et(t)||(t={value:t});var n={text:"",index:a};if(t.label!=null&&(n.text=t.label),t.hasOwnProperty("value")){var i=n.value=t.value;n.interval=[i,i],n.close=[1,1]}else{for(var o=n.interval=[],s=n.close=[0,0],l=[1,0,1],u=[-1/0,1/0],f=[],h=0;h<2;h++){for(var v=[["gte","gt","min"],["lte","lt","max"]][h],c=0;c<3&&o[h]==null;c++)o[h]=t[v[c]],s[h]=l[c],f[h]=c===2;o[h]==null&&(o[h]=u[h])}f[0]&&o[1]===1/0&&(s[0]=0),f[1]&&o[0]===-1/0&&(s[1]=0),o[0]===o[1]&&s[0]&&s[1]&&(n.value=o[0])}n.visual=oe.retrieveVisuals(t),r.push(n)},this),jb(e,r),Um(r),D(r,function(t){var a=t.close,n=[["<","≤"][a[1]],[">","≥"][a[0]]];t.text=t.text||this.formatValueText(t.value!=null?t.value:t.interval,!1,n)},this)}};function jb(r,e){var t=r.inverse;(r.orient==="vertical"?!t:t)&&e.reverse()}var sY=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type=e.type,t}return e.prototype.doRender=function(){var t=this.group;t.removeAll();var a=this.visualMapModel,n=a.get("textGap"),i=a.textStyleModel,o=i.getFont(),s=i.getTextColor(),l=this._getItemAlign(),u=a.itemSize,f=this._getViewData(),h=f.endsText,v=fe(a.get("showLabel",!0),!h),c=!a.get("selectedMode");h&&this._renderEndsText(t,h[0],u,v,l),D(f.viewPieceList,function(p){var d=p.piece,g=new at;g.onclick=$(this._onItemClick,this,d),this._enableHoverLink(g,p.indexInModelPieceList);var y=a.getRepresentValue(d);if(this._createItemSymbol(g,y,[0,0,u[0],u[1]],c),v){var m=this.visualMapModel.getValueState(y);g.add(new wt({style:{x:l==="right"?-n:u[0]+n,y:u[1]/2,text:d.text,verticalAlign:"middle",align:l,font:o,fill:s,opacity:m==="outOfRange"?.5:1},silent:c}))}t.add(g)},this),h&&this._renderEndsText(t,h[1],u,v,l),ri(a.get("orient"),t,a.get("itemGap")),this.renderBackground(t),this.positionGroup(t)},e.prototype._enableHoverLink=function(t,a){var n=this;t.on("mouseover",function(){return i("highlight")}).on("mouseout",function(){return i("downplay")});var i=function(o){var s=n.visualMapModel;s.option.hoverLink&&n.api.dispatchAction({type:o,batch:nf(s.findTargetDataIndices(a),s)})}},e.prototype._getItemAlign=function(){var t=this.visualMapModel,a=t.option;if(a.orient==="vertical")return UI(t,this.api,t.itemSize);var n=a.align;return(!n||n==="auto")&&(n="left"),n},e.prototype._renderEndsText=function(t,a,n,i,o){if(a){var s=new at,l=this.visualMapModel.textStyleModel;s.add(new wt({style:zt(l,{x:i?o==="right"?n[0]:0:n[0]/2,y:n[1]/2,verticalAlign:"middle",align:i?o:"center",text:a})})),t.add(s)}},e.prototype._getViewData=function(){var t=this.visualMapModel,a=G(t.getPieceList(),function(s,l){return{piece:s,indexInModelPieceList:l}}),n=t.get("text"),i=t.get("orient"),o=t.get("inverse");return(i==="horizontal"?o:!o)?a.reverse():n&&(n=n.slice().reverse()),{viewPieceList:a,endsText:n}},e.prototype._createItemSymbol=function(t,a,n,i){var o=Qt(this.getControllerVisual(a,"symbol"),n[0],n[1],n[2],n[3],this.getControllerVisual(a,"color"));o.silent=i,t.add(o)},e.prototype._onItemClick=function(t){var a=this.visualMapModel,n=a.option,i=n.selectedMode;if(i){var o=rt(n.selected),s=a.getSelectedMapKey(t);i==="single"||i===!0?(o[s]=!0,D(o,function(l,u){o[u]=u===s})):o[s]=!o[s],this.api.dispatchAction({type:"selectDataRange",from:this.uid,visualMapId:this.visualMapModel.id,selected:o})}},e.type="visualMap.piecewise",e})(WI);function XI(r){r.registerComponentModel(iY),r.registerComponentView(sY),YI(r)}function lY(r){gt($I),gt(XI)}var uY={label:{enabled:!0},decal:{show:!1}},tw=bt(),fY={};function hY(r,e){var t=r.getModel("aria");if(!t.get("enabled"))return;var a=rt(uY);ft(a.label,r.getLocaleModel().get("aria"),!1),ft(t.option,a,!1),n(),i();function n(){var u=t.getModel("decal"),f=u.get("show");if(f){var h=Z();r.eachSeries(function(v){if(!v.isColorBySeries()){var c=h.get(v.type);c||(c={},h.set(v.type,c)),tw(v).scope=c}}),r.eachRawSeries(function(v){if(r.isSeriesFiltered(v))return;if(J(v.enableAriaDecal)){v.enableAriaDecal();return}var c=v.getData();if(v.isColorBySeries()){var m=Qp(v.ecModel,v.name,fY,r.getSeriesCount()),_=c.getVisual("decal");c.setVisual("decal",S(_,m))}else{var p=v.getRawData(),d={},g=tw(v).scope;c.each(function(b){var x=c.getRawIndex(b);d[x]=b});var y=p.count();p.each(function(b){var x=d[b],w=p.getName(b)||b+"",T=Qp(v.ecModel,w,g,y),A=c.getItemVisual(x,"decal");c.setItemVisual(x,"decal",S(A,T))})}function S(b,x){var w=b?V(V({},x),b):x;return w.dirty=!0,w}})}}function i(){var u=e.getZr().dom;if(u){var f=r.getLocaleModel().get("aria"),h=t.getModel("label");if(h.option=j(h.option,f),!!h.get("enabled")){if(u.setAttribute("role","img"),h.get("description")){u.setAttribute("aria-label",h.get("description"));return}var v=r.getSeriesCount(),c=h.get(["data","maxCount"])||10,p=h.get(["series","maxCount"])||10,d=Math.min(v,p),g;if(!(v<1)){var y=s();if(y){var m=h.get(["general","withTitle"]);g=o(m,{title:y})}else g=h.get(["general","withoutTitle"]);var _=[],S=v>1?h.get(["series","multiple","prefix"]):h.get(["series","single","prefix"]);g+=o(S,{seriesCount:v}),r.eachSeries(function(T,A){if(A<d){var C=void 0,M=T.get("name"),I=M?"withName":"withoutName";C=v>1?h.get(["series","multiple",I]):h.get(["series","single",I]),C=o(C,{seriesId:T.seriesIndex,seriesName:T.get("name"),seriesType:l(T.subType)});var L=T.getData();if(L.count()>c){var P=h.get(["data","partialData"]);C+=o(P,{displayCnt:c})}else C+=h.get(["data","allData"]);for(var R=h.get(["data","separator","middle"]),E=h.get(["data","separator","end"]),O=h.get(["data","excludeDimensionId"]),k=[],B=0;B<L.count();B++)if(B<c){var F=L.getName(B),H=O?Mt(L.getValues(B),function(K,Q){return pt(O,Q)===-1}):L.getValues(B),U=h.get(["data",F?"withName":"withoutName"]);k.push(o(U,{name:F,value:H.join(R)}))}C+=k.join(R)+E,_.push(C)}});var b=h.getModel(["series","multiple","separator"]),x=b.get("middle"),w=b.get("end");g+=_.join(x)+w,u.setAttribute("aria-label",g)}}}}function o(u,f){if(!Y(u))return u;var h=u;return D(f,function(v,c){h=h.replace(new RegExp("\\{\\s*"+c+"\\s*\\}","g"),v)}),h}function s(){var u=r.get("title");return u&&u.length&&(u=u[0]),u&&u.text}function l(u){var f=r.getLocaleModel().get(["series","typeNames"]);return f[u]||f.chart}}function vY(r){if(!(!r||!r.aria)){var e=r.aria;e.show!=null&&(e.enabled=e.show),e.label=e.label||{},D(["description","general","series","data"],function(t){e[t]!=null&&(e.label[t]=e[t])})}}function cY(r){r.registerPreprocessor(vY),r.registerVisual(r.PRIORITY.VISUAL.ARIA,hY)}var ew={value:"eq","<":"lt","<=":"lte",">":"gt",">=":"gte","=":"eq","!=":"ne","<>":"ne"},pY=(function(){function r(e){var t=this._condVal=Y(e)?new RegExp(e):CL(e)?e:null;if(t==null){var a="";Dt(a)}}return r.prototype.evaluate=function(e){var t=typeof e;return Y(t)?this._condVal.test(e):At(t)?this._condVal.test(e+""):!1},r})(),dY=(function(){function r(){}return r.prototype.evaluate=function(){return this.value},r})(),gY=(function(){function r(){}return r.prototype.evaluate=function(){for(var e=this.children,t=0;t<e.length;t++)if(!e[t].evaluate())return!1;return!0},r})(),yY=(function(){function r(){}return r.prototype.evaluate=function(){for(var e=this.children,t=0;t<e.length;t++)if(e[t].evaluate())return!0;return!1},r})(),mY=(function(){function r(){}return r.prototype.evaluate=function(){return!this.child.evaluate()},r})(),_Y=(function(){function r(){}return r.prototype.evaluate=function(){for(var e=!!this.valueParser,t=this.getValue,a=t(this.valueGetterParam),n=e?this.valueParser(a):null,i=0;i<this.subCondList.length;i++)if(!this.subCondList[i].evaluate(e?n:a))return!1;return!0},r})();function fm(r,e){if(r===!0||r===!1){var t=new dY;return t.value=r,t}var a="";return ZI(r)||Dt(a),r.and?rw("and",r,e):r.or?rw("or",r,e):r.not?SY(r,e):xY(r,e)}function rw(r,e,t){var a=e[r],n="";z(a)||Dt(n),a.length||Dt(n);var i=r==="and"?new gY:new yY;return i.children=G(a,function(o){return fm(o,t)}),i.children.length||Dt(n),i}function SY(r,e){var t=r.not,a="";ZI(t)||Dt(a);var n=new mY;return n.child=fm(t,e),n.child||Dt(a),n}function xY(r,e){for(var t="",a=e.prepareGetValue(r),n=[],i=St(r),o=r.parser,s=o?IA(o):null,l=0;l<i.length;l++){var u=i[l];if(!(u==="parser"||e.valueGetterAttrMap.get(u))){var f=X(ew,u)?ew[u]:u,h=r[u],v=s?s(h):h,c=uk(f,v)||f==="reg"&&new pY(v);c||Dt(t),n.push(c)}}n.length||Dt(t);var p=new _Y;return p.valueGetterParam=a,p.valueParser=s,p.getValue=e.getValue,p.subCondList=n,p}function ZI(r){return et(r)&&!Se(r)}var bY=(function(){function r(e,t){this._cond=fm(e,t)}return r.prototype.evaluate=function(){return this._cond.evaluate()},r})();function wY(r,e){return new bY(r,e)}var TY={type:"echarts:filter",transform:function(r){for(var e=r.upstream,t,a=wY(r.config,{valueGetterAttrMap:Z({dimension:!0}),prepareGetValue:function(s){var l="",u=s.dimension;X(s,"dimension")||Dt(l);var f=e.getDimensionInfo(u);return f||Dt(l),{dimIdx:f.index}},getValue:function(s){return e.retrieveValueFromItem(t,s.dimIdx)}}),n=[],i=0,o=e.count();i<o;i++)t=e.getRawDataItem(i),a.evaluate()&&n.push(t);return{data:n}}},AY={type:"echarts:sort",transform:function(r){var e=r.upstream,t=r.config,a="",n=Et(t);n.length||Dt(a);var i=[];D(n,function(f){var h=f.dimension,v=f.order,c=f.parser,p=f.incomparable;if(h==null&&Dt(a),v!=="asc"&&v!=="desc"&&Dt(a),p&&p!=="min"&&p!=="max"){var d="";Dt(d)}if(v!=="asc"&&v!=="desc"){var g="";Dt(g)}var y=e.getDimensionInfo(h);y||Dt(a);var m=c?IA(c):null;c&&!m&&Dt(a),i.push({dimIdx:y.index,parser:m,comparator:new PA(v,p)})});var o=e.sourceFormat;o!==xe&&o!==cr&&Dt(a);for(var s=[],l=0,u=e.count();l<u;l++)s.push(e.getRawDataItem(l));return s.sort(function(f,h){for(var v=0;v<i.length;v++){var c=i[v],p=e.retrieveValueFromItem(f,c.dimIdx),d=e.retrieveValueFromItem(h,c.dimIdx);c.parser&&(p=c.parser(p),d=c.parser(d));var g=c.comparator.evaluate(p,d);if(g!==0)return g}return 0}),{data:s}}};function CY(r){r.registerTransform(TY),r.registerTransform(AY)}var DY=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type="dataset",t}return e.prototype.init=function(t,a,n){r.prototype.init.call(this,t,a,n),this._sourceManager=new OA(this),r_(this)},e.prototype.mergeOption=function(t,a){r.prototype.mergeOption.call(this,t,a),r_(this)},e.prototype.optionUpdated=function(){this._sourceManager.dirty()},e.prototype.getSourceManager=function(){return this._sourceManager},e.type="dataset",e.defaultOption={seriesLayoutBy:Wr},e})(_t),MY=(function(r){N(e,r);function e(){var t=r!==null&&r.apply(this,arguments)||this;return t.type="dataset",t}return e.type="dataset",e})(Ut);function IY(r){r.registerComponentModel(DY),r.registerComponentView(MY)}var Rr=Zr.CMD;function ji(r,e){return Math.abs(r-e)<1e-5}function jd(r){var e=r.data,t=r.len(),a=[],n,i=0,o=0,s=0,l=0;function u(L,P){n&&n.length>2&&a.push(n),n=[L,P]}function f(L,P,R,E){ji(L,R)&&ji(P,E)||n.push(L,P,R,E,R,E)}function h(L,P,R,E,O,k){var B=Math.abs(P-L),F=Math.tan(B/4)*4/3,H=P<L?-1:1,U=Math.cos(L),K=Math.sin(L),Q=Math.cos(P),it=Math.sin(P),Rt=U*O+R,$t=K*k+E,ct=Q*O+R,tt=it*k+E,dt=O*F*H,q=k*F*H;n.push(Rt-dt*K,$t+q*U,ct+dt*it,tt-q*Q,ct,tt)}for(var v,c,p,d,g=0;g<t;){var y=e[g++],m=g===1;switch(m&&(i=e[g],o=e[g+1],s=i,l=o,(y===Rr.L||y===Rr.C||y===Rr.Q)&&(n=[s,l])),y){case Rr.M:i=s=e[g++],o=l=e[g++],u(s,l);break;case Rr.L:v=e[g++],c=e[g++],f(i,o,v,c),i=v,o=c;break;case Rr.C:n.push(e[g++],e[g++],e[g++],e[g++],i=e[g++],o=e[g++]);break;case Rr.Q:v=e[g++],c=e[g++],p=e[g++],d=e[g++],n.push(i+2/3*(v-i),o+2/3*(c-o),p+2/3*(v-p),d+2/3*(c-d),p,d),i=p,o=d;break;case Rr.A:var _=e[g++],S=e[g++],b=e[g++],x=e[g++],w=e[g++],T=e[g++]+w;g+=1;var A=!e[g++];v=Math.cos(w)*b+_,c=Math.sin(w)*x+S,m?(s=v,l=c,u(s,l)):f(i,o,v,c),i=Math.cos(T)*b+_,o=Math.sin(T)*x+S;for(var C=(A?-1:1)*Math.PI/2,M=w;A?M>T:M<T;M+=C){var I=A?Math.max(M+C,T):Math.min(M+C,T);h(M,I,_,S,b,x)}break;case Rr.R:s=i=e[g++],l=o=e[g++],v=s+e[g++],c=l+e[g++],u(v,l),f(v,l,v,c),f(v,c,s,c),f(s,c,s,l),f(s,l,v,l);break;case Rr.Z:n&&f(i,o,s,l),i=s,o=l;break}}return n&&n.length>2&&a.push(n),a}function tg(r,e,t,a,n,i,o,s,l,u){if(ji(r,t)&&ji(e,a)&&ji(n,o)&&ji(i,s)){l.push(o,s);return}var f=2/u,h=f*f,v=o-r,c=s-e,p=Math.sqrt(v*v+c*c);v/=p,c/=p;var d=t-r,g=a-e,y=n-o,m=i-s,_=d*d+g*g,S=y*y+m*m;if(_<h&&S<h){l.push(o,s);return}var b=v*d+c*g,x=-v*y-c*m,w=_-b*b,T=S-x*x;if(w<h&&b>=0&&T<h&&x>=0){l.push(o,s);return}var A=[],C=[];Ja(r,t,n,o,.5,A),Ja(e,a,i,s,.5,C),tg(A[0],C[0],A[1],C[1],A[2],C[2],A[3],C[3],l,u),tg(A[4],C[4],A[5],C[5],A[6],C[6],A[7],C[7],l,u)}function LY(r,e){var t=jd(r),a=[];e=e||1;for(var n=0;n<t.length;n++){var i=t[n],o=[],s=i[0],l=i[1];o.push(s,l);for(var u=2;u<i.length;){var f=i[u++],h=i[u++],v=i[u++],c=i[u++],p=i[u++],d=i[u++];tg(s,l,f,h,v,c,p,d,o,e),s=p,l=d}a.push(o)}return a}function qI(r,e,t){var a=r[e],n=r[1-e],i=Math.abs(a/n),o=Math.ceil(Math.sqrt(i*t)),s=Math.floor(t/o);s===0&&(s=1,o=t);for(var l=[],u=0;u<o;u++)l.push(s);var f=o*s,h=t-f;if(h>0)for(var u=0;u<h;u++)l[u%o]+=1;return l}function aw(r,e,t){for(var a=r.r0,n=r.r,i=r.startAngle,o=r.endAngle,s=Math.abs(o-i),l=s*n,u=n-a,f=l>Math.abs(u),h=qI([l,u],f?0:1,e),v=(f?s:u)/h.length,c=0;c<h.length;c++)for(var p=(f?u:s)/h[c],d=0;d<h[c];d++){var g={};f?(g.startAngle=i+v*c,g.endAngle=i+v*(c+1),g.r0=a+p*d,g.r=a+p*(d+1)):(g.startAngle=i+p*d,g.endAngle=i+p*(d+1),g.r0=a+v*c,g.r=a+v*(c+1)),g.clockwise=r.clockwise,g.cx=r.cx,g.cy=r.cy,t.push(g)}}function PY(r,e,t){for(var a=r.width,n=r.height,i=a>n,o=qI([a,n],i?0:1,e),s=i?"width":"height",l=i?"height":"width",u=i?"x":"y",f=i?"y":"x",h=r[s]/o.length,v=0;v<o.length;v++)for(var c=r[l]/o[v],p=0;p<o[v];p++){var d={};d[u]=v*h,d[f]=p*c,d[s]=h,d[l]=c,d.x+=r.x,d.y+=r.y,t.push(d)}}function nw(r,e,t,a){return r*a-t*e}function RY(r,e,t,a,n,i,o,s){var l=t-r,u=a-e,f=o-n,h=s-i,v=nw(f,h,l,u);if(Math.abs(v)<1e-6)return null;var c=r-n,p=e-i,d=nw(c,p,f,h)/v;return d<0||d>1?null:new ht(d*l+r,d*u+e)}function EY(r,e,t){var a=new ht;ht.sub(a,t,e),a.normalize();var n=new ht;ht.sub(n,r,e);var i=n.dot(a);return i}function Wi(r,e){var t=r[r.length-1];t&&t[0]===e[0]&&t[1]===e[1]||r.push(e)}function kY(r,e,t){for(var a=r.length,n=[],i=0;i<a;i++){var o=r[i],s=r[(i+1)%a],l=RY(o[0],o[1],s[0],s[1],e.x,e.y,t.x,t.y);l&&n.push({projPt:EY(l,e,t),pt:l,idx:i})}if(n.length<2)return[{points:r},{points:r}];n.sort(function(g,y){return g.projPt-y.projPt});var u=n[0],f=n[n.length-1];if(f.idx<u.idx){var h=u;u=f,f=h}for(var v=[u.pt.x,u.pt.y],c=[f.pt.x,f.pt.y],p=[v],d=[c],i=u.idx+1;i<=f.idx;i++)Wi(p,r[i].slice());Wi(p,c),Wi(p,v);for(var i=f.idx+1;i<=u.idx+a;i++)Wi(d,r[i%a].slice());return Wi(d,v),Wi(d,c),[{points:p},{points:d}]}function iw(r){var e=r.points,t=[],a=[];hh(e,t,a);var n=new vt(t[0],t[1],a[0]-t[0],a[1]-t[1]),i=n.width,o=n.height,s=n.x,l=n.y,u=new ht,f=new ht;return i>o?(u.x=f.x=s+i/2,u.y=l,f.y=l+o):(u.y=f.y=l+o/2,u.x=s,f.x=s+i),kY(e,u,f)}function Kf(r,e,t,a){if(t===1)a.push(e);else{var n=Math.floor(t/2),i=r(e);Kf(r,i[0],n,a),Kf(r,i[1],t-n,a)}return a}function OY(r,e){for(var t=[],a=0;a<e;a++)t.push(Mg(r));return t}function NY(r,e){e.setStyle(r.style),e.z=r.z,e.z2=r.z2,e.zlevel=r.zlevel}function BY(r){for(var e=[],t=0;t<r.length;)e.push([r[t++],r[t++]]);return e}function VY(r,e){var t=[],a=r.shape,n;switch(r.type){case"rect":PY(a,e,t),n=xt;break;case"sector":aw(a,e,t),n=Pe;break;case"circle":aw({r0:0,r:a.r,startAngle:0,endAngle:Math.PI*2,cx:a.cx,cy:a.cy},e,t),n=Pe;break;default:var i=r.getComputedTransform(),o=i?Math.sqrt(Math.max(i[0]*i[0]+i[1]*i[1],i[2]*i[2]+i[3]*i[3])):1,s=G(LY(r.getUpdatedPathProxy(),o),function(y){return BY(y)}),l=s.length;if(l===0)Kf(iw,{points:s[0]},e,t);else if(l===e)for(var u=0;u<l;u++)t.push({points:s[u]});else{var f=0,h=G(s,function(y){var m=[],_=[];hh(y,m,_);var S=(_[1]-m[1])*(_[0]-m[0]);return f+=S,{poly:y,area:S}});h.sort(function(y,m){return m.area-y.area});for(var v=e,u=0;u<l;u++){var c=h[u];if(v<=0)break;var p=u===l-1?v:Math.ceil(c.area/f*e);p<0||(Kf(iw,{points:c.poly},p,t),v-=p)}}n=Re;break}if(!n)return OY(r,e);for(var d=[],u=0;u<t.length;u++){var g=new n;g.setShape(t[u]),NY(r,g),d.push(g)}return d}function zY(r,e){var t=r.length,a=e.length;if(t===a)return[r,e];for(var n=[],i=[],o=t<a?r:e,s=Math.min(t,a),l=Math.abs(a-t)/6,u=(s-2)/6,f=Math.ceil(l/u)+1,h=[o[0],o[1]],v=l,c=2;c<s;){var p=o[c-2],d=o[c-1],g=o[c++],y=o[c++],m=o[c++],_=o[c++],S=o[c++],b=o[c++];if(v<=0){h.push(g,y,m,_,S,b);continue}for(var x=Math.min(v,f-1)+1,w=1;w<=x;w++){var T=w/x;Ja(p,g,m,S,T,n),Ja(d,y,_,b,T,i),p=n[3],d=i[3],h.push(n[1],i[1],n[2],i[2],p,d),g=n[5],y=i[5],m=n[6],_=i[6]}v-=x-1}return o===r?[h,e]:[r,h]}function ow(r,e){for(var t=r.length,a=r[t-2],n=r[t-1],i=[],o=0;o<e.length;)i[o++]=a,i[o++]=n;return i}function GY(r,e){for(var t,a,n,i=[],o=[],s=0;s<Math.max(r.length,e.length);s++){var l=r[s],u=e[s],f=void 0,h=void 0;l?u?(t=zY(l,u),f=t[0],h=t[1],a=f,n=h):(h=ow(n||l,l),f=l):(f=ow(a||u,u),h=u),i.push(f),o.push(h)}return[i,o]}function sw(r){for(var e=0,t=0,a=0,n=r.length,i=0,o=n-2;i<n;o=i,i+=2){var s=r[o],l=r[o+1],u=r[i],f=r[i+1],h=s*f-u*l;e+=h,t+=(s+u)*h,a+=(l+f)*h}return e===0?[r[0]||0,r[1]||0]:[t/e/3,a/e/3,e]}function FY(r,e,t,a){for(var n=(r.length-2)/6,i=1/0,o=0,s=r.length,l=s-2,u=0;u<n;u++){for(var f=u*6,h=0,v=0;v<s;v+=2){var c=v===0?f:(f+v-2)%l+2,p=r[c]-t[0],d=r[c+1]-t[1],g=e[v]-a[0],y=e[v+1]-a[1],m=g-p,_=y-d;h+=m*m+_*_}h<i&&(i=h,o=u)}return o}function HY(r){for(var e=[],t=r.length,a=0;a<t;a+=2)e[a]=r[t-a-2],e[a+1]=r[t-a-1];return e}function WY(r,e,t,a){for(var n=[],i,o=0;o<r.length;o++){var s=r[o],l=e[o],u=sw(s),f=sw(l);i==null&&(i=u[2]<0!=f[2]<0);var h=[],v=[],c=0,p=1/0,d=[],g=s.length;i&&(s=HY(s));for(var y=FY(s,l,u,f)*6,m=g-2,_=0;_<m;_+=2){var S=(y+_)%m+2;h[_+2]=s[S]-u[0],h[_+3]=s[S+1]-u[1]}h[0]=s[y]-u[0],h[1]=s[y+1]-u[1];for(var b=a/t,x=-a/2;x<=a/2;x+=b){for(var w=Math.sin(x),T=Math.cos(x),A=0,_=0;_<s.length;_+=2){var C=h[_],M=h[_+1],I=l[_]-f[0],L=l[_+1]-f[1],P=I*T-L*w,R=I*w+L*T;d[_]=P,d[_+1]=R;var E=P-C,O=R-M;A+=E*E+O*O}if(A<p){p=A,c=x;for(var k=0;k<d.length;k++)v[k]=d[k]}}n.push({from:h,to:v,fromCp:u,toCp:f,rotation:-c})}return n}function Jf(r){return r.__isCombineMorphing}var KI="__mOriginal_";function Qf(r,e,t){var a=KI+e,n=r[a]||r[e];r[a]||(r[a]=r[e]);var i=t.replace,o=t.after,s=t.before;r[e]=function(){var l=arguments,u;return s&&s.apply(this,l),i?u=i.apply(this,l):u=n.apply(this,l),o&&o.apply(this,l),u}}function Bs(r,e){var t=KI+e;r[t]&&(r[e]=r[t],r[t]=null)}function lw(r,e){for(var t=0;t<r.length;t++)for(var a=r[t],n=0;n<a.length;){var i=a[n],o=a[n+1];a[n++]=e[0]*i+e[2]*o+e[4],a[n++]=e[1]*i+e[3]*o+e[5]}}function JI(r,e){var t=r.getUpdatedPathProxy(),a=e.getUpdatedPathProxy(),n=GY(jd(t),jd(a)),i=n[0],o=n[1],s=r.getComputedTransform(),l=e.getComputedTransform();function u(){this.transform=null}s&&lw(i,s),l&&lw(o,l),Qf(e,"updateTransform",{replace:u}),e.transform=null;var f=WY(i,o,10,Math.PI),h=[];Qf(e,"buildPath",{replace:function(v){for(var c=e.__morphT,p=1-c,d=[],g=0;g<f.length;g++){var y=f[g],m=y.from,_=y.to,S=y.rotation*c,b=y.fromCp,x=y.toCp,w=Math.sin(S),T=Math.cos(S);Gu(d,b,x,c);for(var A=0;A<m.length;A+=2){var C=m[A],M=m[A+1],I=_[A],L=_[A+1],P=C*p+I*c,R=M*p+L*c;h[A]=P*T-R*w+d[0],h[A+1]=P*w+R*T+d[1]}var E=h[0],O=h[1];v.moveTo(E,O);for(var A=2;A<m.length;){var I=h[A++],L=h[A++],k=h[A++],B=h[A++],F=h[A++],H=h[A++];E===I&&O===L&&k===F&&B===H?v.lineTo(F,H):v.bezierCurveTo(I,L,k,B,F,H),E=F,O=H}}}})}function hm(r,e,t){if(!r||!e)return e;var a=t.done,n=t.during;JI(r,e),e.__morphT=0;function i(){Bs(e,"buildPath"),Bs(e,"updateTransform"),e.__morphT=-1,e.createPathProxy(),e.dirtyShape()}return e.animateTo({__morphT:1},j({during:function(o){e.dirtyShape(),n&&n(o)},done:function(){i(),a&&a()}},t)),e}function UY(r,e,t,a,n,i){var o=16;r=n===t?0:Math.round(32767*(r-t)/(n-t)),e=i===a?0:Math.round(32767*(e-a)/(i-a));for(var s=0,l,u=(1<<o)/2;u>0;u/=2){var f=0,h=0;(r&u)>0&&(f=1),(e&u)>0&&(h=1),s+=u*u*(3*f^h),h===0&&(f===1&&(r=u-1-r,e=u-1-e),l=r,r=e,e=l)}return s}function jf(r){var e=1/0,t=1/0,a=-1/0,n=-1/0,i=G(r,function(s){var l=s.getBoundingRect(),u=s.getComputedTransform(),f=l.x+l.width/2+(u?u[4]:0),h=l.y+l.height/2+(u?u[5]:0);return e=Math.min(f,e),t=Math.min(h,t),a=Math.max(f,a),n=Math.max(h,n),[f,h]}),o=G(i,function(s,l){return{cp:s,z:UY(s[0],s[1],e,t,a,n),path:r[l]}});return o.sort(function(s,l){return s.z-l.z}).map(function(s){return s.path})}function QI(r){return VY(r.path,r.count)}function eg(){return{fromIndividuals:[],toIndividuals:[],count:0}}function YY(r,e,t){var a=[];function n(b){for(var x=0;x<b.length;x++){var w=b[x];Jf(w)?n(w.childrenRef()):w instanceof yt&&a.push(w)}}n(r);var i=a.length;if(!i)return eg();var o=t.dividePath||QI,s=o({path:e,count:i});if(s.length!==i)return console.error("Invalid morphing: unmatched splitted path"),eg();a=jf(a),s=jf(s);for(var l=t.done,u=t.during,f=t.individualDelay,h=new aa,v=0;v<i;v++){var c=a[v],p=s[v];p.parent=e,p.copyTransform(h),f||JI(c,p)}e.__isCombineMorphing=!0,e.childrenRef=function(){return s};function d(b){for(var x=0;x<s.length;x++)s[x].addSelfToZr(b)}Qf(e,"addSelfToZr",{after:function(b){d(b)}}),Qf(e,"removeSelfFromZr",{after:function(b){for(var x=0;x<s.length;x++)s[x].removeSelfFromZr(b)}});function g(){e.__isCombineMorphing=!1,e.__morphT=-1,e.childrenRef=null,Bs(e,"addSelfToZr"),Bs(e,"removeSelfFromZr")}var y=s.length;if(f)for(var m=y,_=function(){m--,m===0&&(g(),l&&l())},v=0;v<y;v++){var S=f?j({delay:(t.delay||0)+f(v,y,a[v],s[v]),done:_},t):t;hm(a[v],s[v],S)}else e.__morphT=0,e.animateTo({__morphT:1},j({during:function(b){for(var x=0;x<y;x++){var w=s[x];w.__morphT=e.__morphT,w.dirtyShape()}u&&u(b)},done:function(){g();for(var b=0;b<r.length;b++)Bs(r[b],"updateTransform");l&&l()}},t));return e.__zr&&d(e.__zr),{fromIndividuals:a,toIndividuals:s,count:y}}function $Y(r,e,t){var a=e.length,n=[],i=t.dividePath||QI;function o(c){for(var p=0;p<c.length;p++){var d=c[p];Jf(d)?o(d.childrenRef()):d instanceof yt&&n.push(d)}}if(Jf(r)){o(r.childrenRef());var s=n.length;if(s<a)for(var l=0,u=s;u<a;u++)n.push(Mg(n[l++%s]));n.length=a}else{n=i({path:r,count:a});for(var f=r.getComputedTransform(),u=0;u<n.length;u++)n[u].setLocalTransform(f);if(n.length!==a)return console.error("Invalid morphing: unmatched splitted path"),eg()}n=jf(n),e=jf(e);for(var h=t.individualDelay,u=0;u<a;u++){var v=h?j({delay:(t.delay||0)+h(u,a,n[u],e[u])},t):t;hm(n[u],e[u],v)}return{fromIndividuals:n,toIndividuals:e,count:e.length}}function uw(r){return z(r[0])}function fw(r,e){for(var t=[],a=r.length,n=0;n<a;n++)t.push({one:r[n],many:[]});for(var n=0;n<e.length;n++){var i=e[n].length,o=void 0;for(o=0;o<i;o++)t[o%a].many.push(e[n][o])}for(var s=0,n=a-1;n>=0;n--)if(!t[n].many.length){var l=t[s].many;if(l.length<=1)if(s)s=0;else return t;var i=l.length,u=Math.ceil(i/2);t[n].many=l.slice(u,i),t[s].many=l.slice(0,u),s++}return t}var XY={clone:function(r){for(var e=[],t=1-Math.pow(1-r.path.style.opacity,1/r.count),a=0;a<r.count;a++){var n=Mg(r.path);n.setStyle("opacity",t),e.push(n)}return e},split:null};function up(r,e,t,a,n,i){if(!r.length||!e.length)return;var o=To("update",a,n);if(!(o&&o.duration>0))return;var s=a.getModel("universalTransition").get("delay"),l=Object.assign({setToFinal:!0},o),u,f;uw(r)&&(u=r,f=e),uw(e)&&(u=e,f=r);function h(y,m,_,S,b){var x=y.many,w=y.one;if(x.length===1&&!b){var T=m?x[0]:w,A=m?w:x[0];if(Jf(T))h({many:[T],one:A},!0,_,S,!0);else{var C=s?j({delay:s(_,S)},l):l;hm(T,A,C),i(T,A,T,A,C)}}else for(var M=j({dividePath:XY[t],individualDelay:s&&function(O,k,B,F){return s(O+_,S)}},l),I=m?YY(x,w,M):$Y(w,x,M),L=I.fromIndividuals,P=I.toIndividuals,R=L.length,E=0;E<R;E++){var C=s?j({delay:s(E,R)},l):l;i(L[E],P[E],m?x[E]:y.one,m?y.one:x[E],C)}}for(var v=u?u===r:r.length>e.length,c=u?fw(f,u):fw(v?e:r,[v?r:e]),p=0,d=0;d<c.length;d++)p+=c[d].many.length;for(var g=0,d=0;d<c.length;d++)h(c[d],v,g,p),g+=c[d].many.length}function Nn(r){if(!r)return[];if(z(r)){for(var e=[],t=0;t<r.length;t++)e.push(Nn(r[t]));return e}var a=[];return r.traverse(function(n){n instanceof yt&&!n.disableMorphing&&!n.invisible&&!n.ignore&&a.push(n)}),a}var jI=1e4,ZY=0,hw=1,vw=2,qY=bt();function KY(r,e){for(var t=r.dimensions,a=0;a<t.length;a++){var n=r.getDimensionInfo(t[a]);if(n&&n.otherDims[e]===0)return t[a]}}function JY(r,e,t){var a=r.getDimensionInfo(t),n=a&&a.ordinalMeta;if(a){var i=r.get(a.name,e);return n&&n.categories[i]||i+""}}function cw(r,e,t,a){var n=a?"itemChildGroupId":"itemGroupId",i=KY(r,n);if(i){var o=JY(r,e,i);return o}var s=r.getRawDataItem(e),l=a?"childGroupId":"groupId";if(s&&s[l])return s[l]+"";if(!a)return t||r.getId(e)}function pw(r){var e=[];return D(r,function(t){var a=t.data,n=t.dataGroupId;if(!(a.count()>jI))for(var i=a.getIndices(),o=0;o<i.length;o++)e.push({data:a,groupId:cw(a,o,n,!1),childGroupId:cw(a,o,n,!0),divide:t.divide,dataIndex:o})}),e}function fp(r,e,t){r.traverse(function(a){a instanceof yt&&Ht(a,{style:{opacity:0}},e,{dataIndex:t,isFrom:!0})})}function hp(r){if(r.parent){var e=r.getComputedTransform();r.setLocalTransform(e),r.parent.remove(r)}}function Ui(r){r.stopAnimation(),r.isGroup&&r.traverse(function(e){e.stopAnimation()})}function QY(r,e,t){var a=To("update",t,e);a&&r.traverse(function(n){if(n instanceof fr){var i=$R(n);i&&n.animateFrom({style:i},a)}})}function jY(r,e){var t=r.length;if(t!==e.length)return!1;for(var a=0;a<t;a++){var n=r[a],i=e[a];if(n.data.getId(n.dataIndex)!==i.data.getId(i.dataIndex))return!1}return!0}function tL(r,e,t){var a=pw(r),n=pw(e);function i(_,S,b,x,w){(b||_)&&S.animateFrom({style:b&&b!==_?V(V({},b.style),_.style):_.style},w)}var o=!1,s=ZY,l=Z(),u=Z();a.forEach(function(_){_.groupId&&l.set(_.groupId,!0),_.childGroupId&&u.set(_.childGroupId,!0)});for(var f=0;f<n.length;f++){var h=n[f].groupId;if(u.get(h)){s=hw;break}var v=n[f].childGroupId;if(v&&l.get(v)){s=vw;break}}function c(_,S){return function(b){var x=b.data,w=b.dataIndex;return S?x.getId(w):_?s===hw?b.childGroupId:b.groupId:s===vw?b.childGroupId:b.groupId}}var p=jY(a,n),d={};if(!p)for(var f=0;f<n.length;f++){var g=n[f],y=g.data.getItemGraphicEl(g.dataIndex);y&&(d[y.id]=!0)}function m(_,S){var b=a[S],x=n[_],w=x.data.hostModel,T=b.data.getItemGraphicEl(b.dataIndex),A=x.data.getItemGraphicEl(x.dataIndex);if(T===A){A&&QY(A,x.dataIndex,w);return}T&&d[T.id]||A&&(Ui(A),T?(Ui(T),hp(T),o=!0,up(Nn(T),Nn(A),x.divide,w,_,i)):fp(A,w,_))}new pa(a,n,c(!0,p),c(!1,p),null,"multiple").update(m).updateManyToOne(function(_,S){var b=n[_],x=b.data,w=x.hostModel,T=x.getItemGraphicEl(b.dataIndex),A=Mt(G(S,function(C){return a[C].data.getItemGraphicEl(a[C].dataIndex)}),function(C){return C&&C!==T&&!d[C.id]});T&&(Ui(T),A.length?(D(A,function(C){Ui(C),hp(C)}),o=!0,up(Nn(A),Nn(T),b.divide,w,_,i)):fp(T,w,b.dataIndex))}).updateOneToMany(function(_,S){var b=a[S],x=b.data.getItemGraphicEl(b.dataIndex);if(!(x&&d[x.id])){var w=Mt(G(_,function(A){return n[A].data.getItemGraphicEl(n[A].dataIndex)}),function(A){return A&&A!==x}),T=n[_[0]].data.hostModel;w.length&&(D(w,function(A){return Ui(A)}),x?(Ui(x),hp(x),o=!0,up(Nn(x),Nn(w),b.divide,T,_[0],i)):D(w,function(A){return fp(A,T,_[0])}))}}).updateManyToMany(function(_,S){new pa(S,_,function(b){return a[b].data.getId(a[b].dataIndex)},function(b){return n[b].data.getId(n[b].dataIndex)}).update(function(b,x){m(_[b],S[x])}).execute()}).execute(),o&&D(e,function(_){var S=_.data,b=S.hostModel,x=b&&t.getViewOfSeriesModel(b),w=To("update",b,0);x&&b.isAnimationEnabled()&&w&&w.duration>0&&x.group.traverse(function(T){T instanceof yt&&!T.animators.length&&T.animateFrom({style:{opacity:0}},w)})})}function dw(r){var e=r.getModel("universalTransition").get("seriesKey");return e||r.id}function gw(r){return z(r)?r.sort().join(","):r}function Oa(r){if(r.hostModel)return r.hostModel.getModel("universalTransition").get("divideShape")}function t7(r,e){var t=Z(),a=Z(),n=Z();return D(r.oldSeries,function(i,o){var s=r.oldDataGroupIds[o],l=r.oldData[o],u=dw(i),f=gw(u);a.set(f,{dataGroupId:s,data:l}),z(u)&&D(u,function(h){n.set(h,{key:f,dataGroupId:s,data:l})})}),D(e.updatedSeries,function(i){if(i.isUniversalTransitionEnabled()&&i.isAnimationEnabled()){var o=i.get("dataGroupId"),s=i.getData(),l=dw(i),u=gw(l),f=a.get(u);if(f)t.set(u,{oldSeries:[{dataGroupId:f.dataGroupId,divide:Oa(f.data),data:f.data}],newSeries:[{dataGroupId:o,divide:Oa(s),data:s}]});else if(z(l)){var h=[];D(l,function(p){var d=a.get(p);d.data&&h.push({dataGroupId:d.dataGroupId,divide:Oa(d.data),data:d.data})}),h.length&&t.set(u,{oldSeries:h,newSeries:[{dataGroupId:o,data:s,divide:Oa(s)}]})}else{var v=n.get(l);if(v){var c=t.get(v.key);c||(c={oldSeries:[{dataGroupId:v.dataGroupId,data:v.data,divide:Oa(v.data)}],newSeries:[]},t.set(v.key,c)),c.newSeries.push({dataGroupId:o,data:s,divide:Oa(s)})}}}}),t}function yw(r,e){for(var t=0;t<r.length;t++){var a=e.seriesIndex!=null&&e.seriesIndex===r[t].seriesIndex||e.seriesId!=null&&e.seriesId===r[t].id;if(a)return t}}function e7(r,e,t,a){var n=[],i=[];D(Et(r.from),function(o){var s=yw(e.oldSeries,o);s>=0&&n.push({dataGroupId:e.oldDataGroupIds[s],data:e.oldData[s],divide:Oa(e.oldData[s]),groupIdDim:o.dimension})}),D(Et(r.to),function(o){var s=yw(t.updatedSeries,o);if(s>=0){var l=t.updatedSeries[s].getData();i.push({dataGroupId:e.oldDataGroupIds[s],data:l,divide:Oa(l),groupIdDim:o.dimension})}}),n.length>0&&i.length>0&&tL(n,i,a)}function r7(r){r.registerUpdateLifecycle("series:beforeupdate",function(e,t,a){D(Et(a.seriesTransition),function(n){D(Et(n.to),function(i){for(var o=a.updatedSeries,s=0;s<o.length;s++)(i.seriesIndex!=null&&i.seriesIndex===o[s].seriesIndex||i.seriesId!=null&&i.seriesId===o[s].id)&&(o[s][Ju]=!0)})})}),r.registerUpdateLifecycle("series:transition",function(e,t,a){var n=qY(t);if(n.oldSeries&&a.updatedSeries&&a.optionChanged){var i=a.seriesTransition;if(i)D(Et(i),function(c){e7(c,n,a,t)});else{var o=t7(n,a);D(o.keys(),function(c){var p=o.get(c);tL(p.oldSeries,p.newSeries,t)})}D(a.updatedSeries,function(c){c[Ju]&&(c[Ju]=!1)})}for(var s=e.getSeries(),l=n.oldSeries=[],u=n.oldDataGroupIds=[],f=n.oldData=[],h=0;h<s.length;h++){var v=s[h].getData();v.count()<jI&&(l.push(s[h]),u.push(s[h].get("dataGroupId")),f.push(v))}})}gt([vV]);gt([sV]);gt([RV,XV,az,Nz,Xz,E5,sG,UG,vF,mF,DF,x3,$3,aH,_H,wH,EH,GH,JH,a4,c4,K4]);gt(dW);gt(FW);gt(QD);gt(t6);gt(BM);gt(n6);gt(c6);gt(tU);gt(_U);gt(Ol);gt(NU);gt(zU);gt(qU);gt(r8);gt(l8);gt(p8);gt(T8);gt(H8);gt(GI);gt(FI);gt(lY);gt($I);gt(XI);gt(cY);gt(CY);gt(IY);gt(r7);gt(DB);const ut={statusTableBody:document.getElementById("status-table-body"),rangeSelect:document.getElementById("range-select"),trackSelect:document.getElementById("track-select"),limitInput:document.getElementById("limit-input"),targetNameInput:document.getElementById("target-name-input"),targetAddressInput:document.getElementById("target-address-input"),targetPortInput:document.getElementById("target-port-input"),targetSaveBtn:document.getElementById("target-save-btn"),targetDeleteBtn:document.getElementById("target-delete-btn"),logsOutput:document.getElementById("logs-output"),logsMeta:document.getElementById("logs-meta"),statusLine:document.getElementById("status-line"),authScreen:document.getElementById("auth-screen"),authStatus:document.getElementById("auth-status"),authRetryBtn:document.getElementById("auth-retry-btn"),authOpenTelegramBtn:document.getElementById("auth-open-telegram-btn"),dashboardMain:document.getElementById("dashboard-main"),refreshBtn:document.getElementById("refresh-btn"),logsBtn:document.getElementById("logs-btn"),logoutBtn:document.getElementById("logout-btn"),themeBtn:document.getElementById("theme-btn"),summaryTotal:document.getElementById("summary-total"),summaryUp:document.getElementById("summary-up"),summaryDown:document.getElementById("summary-down"),summaryUnknown:document.getElementById("summary-unknown"),sessionExpiry:document.getElementById("session-expiry"),snapshotTime:document.getElementById("snapshot-time"),timelineChart:B_(document.getElementById("timeline-chart")),distributionChart:B_(document.getElementById("distribution-chart"))},trkDeepLink=new URLSearchParams(window.location.search),Tt={selectedTrack:trkDeepLink.get("track")||"",statusFilter:(trkDeepLink.get("status")||"").toUpperCase(),tracks:[],refreshTimer:null,logTimer:null,theme:"dark",rows:[],formDirty:!1},eL="trackway_theme_v2";function Fn(r){return String(r).padStart(2,"0")}function vi(r){const e=new Date(r);return Number.isNaN(e.valueOf())?String(r):`${Fn(e.getDate())}.${Fn(e.getMonth()+1)}.${e.getFullYear()} ${Fn(e.getHours())}:${Fn(e.getMinutes())}:${Fn(e.getSeconds())}`}function a7(r){return r==="UP"?"status-pill status-up":r==="DOWN"?"status-pill status-down":"status-pill status-unknown"}function Ze(r){ut.statusLine.textContent=r}function rg(r){ut.authStatus.textContent=r}function th(r){ut.dashboardMain.classList.add("hidden"),ut.authScreen.classList.remove("hidden"),r&&rg(r)}function mw(){ut.authScreen.classList.add("hidden"),ut.dashboardMain.classList.remove("hidden"),requestAnimationFrame(()=>{ut.timelineChart.resize(),ut.distributionChart.resize()})}function to(r){return String(r).replaceAll("&","&amp;").replaceAll("<","&lt;").replaceAll(">","&gt;").replaceAll('"',"&quot;").replaceAll("'","&#39;")}function rL(r){return{timestamp:r.timestamp||r.Timestamp||"",status:r.status||r.Status||"",endpoint:r.endpoint||r.Endpoint||"",reason:r.reason||r.Reason||""}}async function bi(r,e={}){const t=await fetch(r,{credentials:"include",headers:{Accept:"application/json",...e.headers},...e});let a=null;try{a=await t.json()}catch{a=null}if(!t.ok){const n=a?.error||`HTTP ${t.status}`,i=new Error(n);throw i.status=t.status,i}return a}function aL(r){Tt.theme=r==="light"?"light":"dark",document.documentElement.setAttribute("data-theme",Tt.theme),localStorage.setItem(eL,Tt.theme),ut.themeBtn.textContent=Tt.theme==="dark"?"Theme: Dark":"Theme: Light"}function n7(){const r=localStorage.getItem(eL);return r==="dark"||r==="light"?r:"dark"}async function _w(){const r=await bi("api/auth/session");return ut.sessionExpiry.textContent=vi(r.expires_at),r}async function i7(){const r=window.Telegram?.WebApp;if(!r)return!1;r.ready?.(),r.expand?.();const e=String(r.initData||"").trim();if(!e)throw new Error("Telegram WebApp initData is empty");return await bi("api/auth/telegram-miniapp",{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify({init_data:e})}),!0}async function o7(){try{return await _w(),mw(),rg("Authorized."),!0}catch{try{if(await i7())return await _w(),mw(),rg("Authorized via Telegram Mini App."),!0}catch(e){return th(`Mini App auth failed: ${e?.message||"unknown error"}`),!1}return th("Open Telegram, send /authme, then open the auth link here."),!1}}function nL(r){const e=[{value:r.up,name:"UP",itemStyle:{color:"#5cd89a"}},{value:r.down,name:"DOWN",itemStyle:{color:"#ff6f8f"}},{value:r.unknown,name:"UNKNOWN",itemStyle:{color:"#ffc36d"}}];ut.distributionChart.setOption({backgroundColor:"transparent",tooltip:{trigger:"item"},legend:{bottom:0,textStyle:{color:getComputedStyle(document.documentElement).getPropertyValue("--muted").trim()}},series:[{type:"pie",radius:["42%","70%"],center:["50%","46%"],label:{color:getComputedStyle(document.documentElement).getPropertyValue("--ink").trim()},data:e}]})}function ag(r){const e=r.map(t=>{const a=rL(t),n=a.status==="DOWN"?0:a.status==="UP"?1:null;return[a.timestamp,n]}).filter(t=>t[1]!==null);ut.timelineChart.setOption({backgroundColor:"transparent",tooltip:{trigger:"axis",axisPointer:{type:"line"},formatter:t=>{if(!t||!t.length)return"";const a=t[0],n=vi(a.axisValue),i=Number(a.data[1])===1?"UP":"DOWN";return`${n}<br/><b>${i}</b>`}},grid:{left:46,right:18,top:18,bottom:56},xAxis:{type:"time",axisLabel:{color:getComputedStyle(document.documentElement).getPropertyValue("--muted").trim(),formatter:t=>{const a=new Date(t);return`${Fn(a.getHours())}:${Fn(a.getMinutes())}`}},axisLine:{lineStyle:{color:getComputedStyle(document.documentElement).getPropertyValue("--line").trim()}},splitLine:{show:!1}},yAxis:{type:"value",min:0,max:1,interval:1,axisLabel:{color:getComputedStyle(document.documentElement).getPropertyValue("--muted").trim(),formatter:t=>t===1?"UP":"DOWN"},axisLine:{show:!1},splitLine:{lineStyle:{color:getComputedStyle(document.documentElement).getPropertyValue("--line").trim()}}},dataZoom:[{type:"inside",xAxisIndex:0},{type:"slider",xAxisIndex:0,height:18,bottom:12,borderColor:getComputedStyle(document.documentElement).getPropertyValue("--line").trim(),backgroundColor:"transparent",fillerColor:"rgba(101,184,255,0.18)"}],series:[{name:"State",type:"line",step:"end",smooth:!1,showSymbol:!1,lineStyle:{width:2,color:"#65b8ff"},areaStyle:{color:"rgba(101,184,255,0.18)"},data:e}]})}function iL(r){ut.statusTableBody.innerHTML="";for(const e of r.targets){if(Tt.statusFilter&&e.status!==Tt.statusFilter)continue;const t=document.createElement("tr");e.name===Tt.selectedTrack&&t.classList.add("active"),t.innerHTML=`
+            <td>${to(e.name)}${e.runbook_url?` <a href="${to(e.runbook_url)}" target="_blank" rel="noopener noreferrer">runbook</a>`:""}</td>
             <td><code>${to(e.address)}:${e.port}</code></td>
             <td><span class="${a7(e.status)}">${e.status}</span></td>
             <td><code>${to(vi(e.last_changed))}</code></td>
             <td><code>${to(vi(e.last_checked))}</code></td>
-          `,t.addEventListener("click",()=>{Tt.selectedTrack=e.name,sL(),oL(e,!0),iL(r),ma()}),ut.statusTableBody.appendChild(t)}}function oL(r,e=!1){r&&(Tt.formDirty&&!e||(ut.targetNameInput.value=r.name||"",ut.targetAddressInput.value=r.address||"",ut.targetPortInput.value=String(r.port||443),Tt.formDirty=!1))}function sL(){if(!Tt.tracks.length){ut.trackSelect.innerHTML="";return}(!Tt.selectedTrack||!Tt.tracks.includes(Tt.selectedTrack))&&(Tt.selectedTrack=Tt.tracks[0]),ut.trackSelect.innerHTML=Tt.tracks.map(r=>`<option value="${to(r)}">${to(r)}</option>`).join(""),ut.trackSelect.value=Tt.selectedTrack}async function cl(){const r=await bi("/api/status");if(ut.summaryTotal.textContent=String(r.total),ut.summaryUp.textContent=String(r.up),ut.summaryDown.textContent=String(r.down),ut.summaryUnknown.textContent=String(r.unknown),ut.snapshotTime.textContent=vi(r.generated_at),Tt.tracks=r.targets.map(e=>e.name),sL(),Tt.selectedTrack){const e=r.targets.find(t=>t.name===Tt.selectedTrack);e&&oL(e)}iL(r),nL(r),Ze(`Status updated ${vi(new Date().toISOString())}`)}async function s7(){const r=ut.targetNameInput.value.trim(),e=ut.targetAddressInput.value.trim(),t=Number(ut.targetPortInput.value);if(!r||!e||!Number.isFinite(t)||t<1||t>65535)throw new Error("Target fields are invalid");await bi("/api/targets",{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify({name:r,address:e,port:Math.trunc(t)})}),Tt.selectedTrack=r,Tt.formDirty=!1}async function l7(){const r=ut.targetNameInput.value.trim();if(!r)throw new Error("Target name is required for delete");await bi(`/api/targets?name=${encodeURIComponent(r)}`,{method:"DELETE"}),Tt.selectedTrack===r&&(Tt.selectedTrack=""),Tt.formDirty=!1}function u7(r){return r.length?r.map(e=>{const t=rL(e);return`${vi(t.timestamp)}  ${t.status}  ${t.endpoint}  ${t.reason}`}).join(`
-`):"(no rows)"}async function ma(){if(!Tt.selectedTrack){ut.logsMeta.textContent="No track selected.",ut.logsOutput.textContent="(no track)",ag([]);return}const r=Number(ut.rangeSelect.value)||24,e=Number(ut.limitInput.value)||1e4,t=-new Date().getTimezoneOffset(),a=new URLSearchParams({track:Tt.selectedTrack,hours:String(r),limit:String(e),tz_offset_minutes:String(t)}),n=await bi(`/api/logs?${a.toString()}`),i=Array.isArray(n.rows)?n.rows:[];Tt.rows=i,ut.logsMeta.textContent=`Track: ${n.track} | Range: ${r}h | Rows: ${i.length}`,ut.logsOutput.textContent=u7(i),ag(i)}function lL(){Tt.refreshTimer&&clearInterval(Tt.refreshTimer),Tt.logTimer&&clearInterval(Tt.logTimer),Tt.refreshTimer=null,Tt.logTimer=null}async function f7(){try{await bi("/auth/logout",{method:"POST"})}catch{}lL(),th("Logged out. Send /authme and open auth link."),Ze("Logged out")}async function uL(){if(aL(n7()),th("Checking authorization..."),!await o7()){Ze("Authorization required");return}await cl(),await ma(),lL(),Tt.refreshTimer=setInterval(()=>{cl().catch(e=>Ze(`Status error: ${e.message}`))},5e3),Tt.logTimer=setInterval(()=>{ma().catch(e=>Ze(`Log error: ${e.message}`))},1e4)}ut.refreshBtn.addEventListener("click",()=>{cl().then(()=>ma()).catch(r=>Ze(`Refresh error: ${r.message}`))});ut.logsBtn.addEventListener("click",()=>{Tt.selectedTrack=ut.trackSelect.value,ma().catch(r=>Ze(`Load logs error: ${r.message}`))});ut.rangeSelect.addEventListener("change",()=>{ma().catch(r=>Ze(`Range update error: ${r.message}`))});ut.trackSelect.addEventListener("change",()=>{Tt.selectedTrack=ut.trackSelect.value,ma().catch(r=>Ze(`Track update error: ${r.message}`))});ut.logoutBtn.addEventListener("click",()=>{f7()});ut.themeBtn.addEventListener("click",()=>{aL(Tt.theme==="dark"?"light":"dark"),nL({total:Number(ut.summaryTotal.textContent),up:Number(ut.summaryUp.textContent),down:Number(ut.summaryDown.textContent),unknown:Number(ut.summaryUnknown.textContent)}),ag(Tt.rows)});window.addEventListener("resize",()=>{ut.timelineChart.resize(),ut.distributionChart.resize()});ut.targetSaveBtn.addEventListener("click",()=>{s7().then(()=>cl()).then(()=>ma()).then(()=>Ze("Target saved")).catch(r=>Ze(`Target save error: ${r.message}`))});ut.targetDeleteBtn.addEventListener("click",()=>{l7().then(()=>cl()).then(()=>ma()).then(()=>Ze("Target deleted")).catch(r=>Ze(`Target delete error: ${r.message}`))});for(const r of[ut.targetNameInput,ut.targetAddressInput,ut.targetPortInput])r.addEventListener("input",()=>{Tt.formDirty=!0});ut.authRetryBtn.addEventListener("click",()=>{uL()});ut.authOpenTelegramBtn.addEventListener("click",()=>{window.open("https://t.me","_blank","noopener,noreferrer")});uL();
+          `,t.addEventListener("click",()=>{Tt.selectedTrack=e.name,sL(),oL(e,!0),iL(r),ma()}),ut.statusTableBody.appendChild(t)}}function oL(r,e=!1){r&&(Tt.formDirty&&!e||(ut.targetNameInput.value=r.name||"",ut.targetAddressInput.value=r.address||"",ut.targetPortInput.value=String(r.port||443),Tt.formDirty=!1))}function sL(){if(!Tt.tracks.length){ut.trackSelect.innerHTML="";return}(!Tt.selectedTrack||!Tt.tracks.includes(Tt.selectedTrack))&&(Tt.selectedTrack=Tt.tracks[0]),ut.trackSelect.innerHTML=Tt.tracks.map(r=>`<option value="${to(r)}">${to(r)}</option>`).join(""),ut.trackSelect.value=Tt.selectedTrack}async function cl(){const r=await bi("api/status");if(ut.summaryTotal.textContent=String(r.total),ut.summaryUp.textContent=String(r.up),ut.summaryDown.textContent=String(r.down),ut.summaryUnknown.textContent=String(r.unknown),ut.snapshotTime.textContent=vi(r.generated_at),Tt.tracks=r.targets.map(e=>e.name),sL(),Tt.selectedTrack){const e=r.targets.find(t=>t.name===Tt.selectedTrack);e&&oL(e)}iL(r),nL(r),Ze(`Status updated ${vi(new Date().toISOString())}`)}async function s7(){const r=ut.targetNameInput.value.trim(),e=ut.targetAddressInput.value.trim(),t=Number(ut.targetPortInput.value);if(!r||!e||!Number.isFinite(t)||t<1||t>65535)throw new Error("Target fields are invalid");await bi("api/targets",{method:"POST",headers:{"Content-Type":"application/json"},body:JSON.stringify({name:r,address:e,port:Math.trunc(t)})}),Tt.selectedTrack=r,Tt.formDirty=!1}async function l7(){const r=ut.targetNameInput.value.trim();if(!r)throw new Error("Target name is required for delete");await bi(`api/targets?name=${encodeURIComponent(r)}`,{method:"DELETE"}),Tt.selectedTrack===r&&(Tt.selectedTrack=""),Tt.formDirty=!1}function u7(r){return r.length?r.map(e=>{const t=rL(e);return`${vi(t.timestamp)}  ${t.status}  ${t.endpoint}  ${t.reason}`}).join(`
+`):"(no rows)"}async function ma(){if(!Tt.selectedTrack){ut.logsMeta.textContent="No track selected.",ut.logsOutput.textContent="(no track)",ag([]);return}const r=Number(ut.rangeSelect.value)||24,e=Number(ut.limitInput.value)||1e4,t=-new Date().getTimezoneOffset(),a=new URLSearchParams({track:Tt.selectedTrack,hours:String(r),limit:String(e),tz_offset_minutes:String(t)}),n=await bi(`api/logs?${a.toString()}`),i=Array.isArray(n.rows)?n.rows:[];Tt.rows=i,ut.logsMeta.textContent=`Track: ${n.track} | Range: ${r}h | Rows: ${i.length}`,ut.logsOutput.textContent=u7(i),ag(i)}function lL(){Tt.refreshTimer&&clearInterval(Tt.refreshTimer),Tt.logTimer&&clearInterval(Tt.logTimer),Tt.refreshTimer=null,Tt.logTimer=null}async function f7(){try{await bi("auth/logout",{method:"POST"})}catch{}lL(),th("Logged out. Send /authme and open auth link."),Ze("Logged out")}async function uL(){if(aL(n7()),th("Checking authorization..."),!await o7()){Ze("Authorization required");return}await cl(),await ma(),lL(),Tt.refreshTimer=setInterval(()=>{cl().catch(e=>Ze(`Status error: ${e.message}`))},5e3),Tt.logTimer=setInterval(()=>{ma().catch(e=>Ze(`Log error: ${e.message}`))},1e4)}ut.refreshBtn.addEventListener("click",()=>{cl().then(()=>ma()).catch(r=>Ze(`Refresh error: ${r.message}`))});ut.logsBtn.addEventListener("click",()=>{Tt.selectedTrack=ut.trackSelect.value,ma().catch(r=>Ze(`Load logs error: ${r.message}`))});ut.rangeSelect.addEventListener("change",()=>{ma().catch(r=>Ze(`Range update error: ${r.message}`))});ut.trackSelect.addEventListener("change",()=>{Tt.selectedTrack=ut.trackSelect.value,ma().catch(r=>Ze(`Track update error: ${r.message}`))});ut.logoutBtn.addEventListener("click",()=>{f7()});ut.themeBtn.addEventListener("click",()=>{aL(Tt.theme==="dark"?"light":"dark"),nL({total:Number(ut.summaryTotal.textContent),up:Number(ut.summaryUp.textContent),down:Number(ut.summaryDown.textContent),unknown:Number(ut.summaryUnknown.textContent)}),ag(Tt.rows)});window.addEventListener("resize",()=>{ut.timelineChart.resize(),ut.distributionChart.resize()});ut.targetSaveBtn.addEventListener("click",()=>{s7().then(()=>cl()).then(()=>ma()).then(()=>Ze("Target saved")).catch(r=>Ze(`Target save error: ${r.message}`))});ut.targetDeleteBtn.addEventListener("click",()=>{l7().then(()=>cl()).then(()=>ma()).then(()=>Ze("Target deleted")).catch(r=>Ze(`Target delete error: ${r.message}`))});for(const r of[ut.targetNameInput,ut.targetAddressInput,ut.targetPortInput])r.addEventListener("input",()=>{Tt.formDirty=!0});ut.authRetryBtn.addEventListener("click",()=>{uL()});ut.authOpenTelegramBtn.addEventListener("click",()=>{window.open("https://t.me","_blank","noopener,noreferrer")});uL();
@@ -0,0 +1,160 @@
+package dashboard
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// gzipMinSize is the smallest body worth compressing; shorter ones are sent
+// as they are.
+const gzipMinSize = 1024
+
+// withGzip compresses responses for clients that accept gzip. The choice is
+// made once the status, headers and first gzipMinSize bytes are known, so
+// small bodies, event streams, partial content and bodies that are already
+// encoded or not compressible pass through unchanged.
+func withGzip(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if r.Method == http.MethodHead || r.Header.Get("Range") != "" || !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(gw, r)
+		gw.finish()
+	})
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.TrimSpace(name)
+		if !strings.EqualFold(name, "gzip") && name != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q="); ok {
+			if value, err := strconv.ParseFloat(q, 64); err == nil && value == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// compressibleType reports whether a Content-Type benefits from gzip.
+func compressibleType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.TrimSpace(strings.ToLower(mediaType))
+	switch {
+	case mediaType == "text/event-stream":
+		return false
+	case strings.HasPrefix(mediaType, "text/"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/javascript", "image/svg+xml":
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the start of a body until it can tell whether
+// to compress it; it then either streams through gz or writes the body
+// unchanged.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	passthrough bool
+	gz          *gzip.Writer
+	buf         []byte
+}
+
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = code
+	headers := w.Header()
+	length, err := strconv.Atoi(headers.Get("Content-Length"))
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified || code == http.StatusPartialContent ||
+		headers.Get("Content-Encoding") != "" || !compressibleType(headers.Get("Content-Type")) || (err == nil && length < gzipMinSize) {
+		w.passthrough = true
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(p))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	switch {
+	case w.passthrough:
+		return w.ResponseWriter.Write(p)
+	case w.gz != nil:
+		return w.gz.Write(p)
+	}
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= gzipMinSize {
+		if err := w.startGzip(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *gzipResponseWriter) startGzip() error {
+	headers := w.Header()
+	headers.Set("Content-Encoding", "gzip")
+	headers.Del("Content-Length")
+	w.ResponseWriter.WriteHeader(w.status)
+	w.gz = gzip.NewWriter(w.ResponseWriter)
+	_, err := w.gz.Write(w.buf)
+	w.buf = nil
+	return err
+}
+
+// writeBuffered gives up on compression and writes what was buffered.
+func (w *gzipResponseWriter) writeBuffered() {
+	w.passthrough = true
+	w.ResponseWriter.WriteHeader(w.status)
+	if len(w.buf) > 0 {
+		_, _ = w.ResponseWriter.Write(w.buf)
+	}
+	w.buf = nil
+}
+
+// finish completes the response after the handler returned.
+func (w *gzipResponseWriter) finish() {
+	switch {
+	case w.gz != nil:
+		_ = w.gz.Close()
+	case w.wroteHeader && !w.passthrough:
+		w.writeBuffered()
+	}
+}
+
+// Flush sends buffered data right away, so a handler that flushes early is
+// not held back until gzipMinSize bytes arrive.
+func (w *gzipResponseWriter) Flush() {
+	switch {
+	case w.gz != nil:
+		_ = w.gz.Flush()
+	case w.wroteHeader && !w.passthrough:
+		w.writeBuffered()
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
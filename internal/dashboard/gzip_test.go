@@ -0,0 +1,130 @@
+package dashboard
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"trackway/internal/config"
+)
+
+func gunzip(t *testing.T, body []byte) []byte {
+	t.Helper()
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	out, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("gunzip: %v", err)
+	}
+	return out
+}
+
+func TestGzipCompressesLargeJSONOnly(t *testing.T) {
+	t.Parallel()
+
+	rows := make([]string, 500)
+	for i := range rows {
+		rows[i] = "2026-10-16T00:00:00Z UP 10.0.0.1:443 POLL"
+	}
+	handler := withGzip(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/large":
+			writeJSON(w, http.StatusOK, map[string]any{"rows": rows})
+		case "/small":
+			writeJSON(w, http.StatusOK, map[string]any{"ok": true})
+		case "/stream":
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			_, _ = io.WriteString(w, strings.Repeat("data: x\n\n", 200))
+		}
+	}))
+	get := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/large", "br, gzip;q=0.8")
+	if rec.Header().Get("Content-Encoding") != "gzip" || rec.Header().Get("Content-Length") != "" || rec.Header().Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("expected a gzip response, got headers %v", rec.Header())
+	}
+	var payload struct {
+		Rows []string `json:"rows"`
+	}
+	if err := json.Unmarshal(gunzip(t, rec.Body.Bytes()), &payload); err != nil || len(payload.Rows) != len(rows) {
+		t.Fatalf("expected the JSON to round-trip, got %d rows, %v", len(payload.Rows), err)
+	}
+	if rec.Body.Len() >= len(strings.Join(rows, "")) {
+		t.Fatalf("expected a smaller body, got %d bytes", rec.Body.Len())
+	}
+
+	for path, encoding := range map[string]string{"/large": "", "/small": "gzip", "/stream": "gzip"} {
+		rec := get(path, encoding)
+		if rec.Header().Get("Content-Encoding") != "" || rec.Code != http.StatusOK || rec.Body.Len() == 0 {
+			t.Fatalf("%s with %q: expected an unencoded body, got %d %v", path, encoding, rec.Code, rec.Header())
+		}
+	}
+	if rec := get("/large", "gzip;q=0"); rec.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("expected q=0 to refuse gzip, got %v", rec.Header())
+	}
+}
+
+func TestGzipServesStaticAssets(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+	}, "test-bot-token", stubProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	assets, err := fs.Glob(srv.static, "_astro/*.js")
+	if err != nil || len(assets) == 0 {
+		t.Fatalf("expected embedded scripts, got %v, %v", assets, err)
+	}
+	// The largest script is well above gzipMinSize.
+	var asset string
+	var want []byte
+	for _, name := range assets {
+		data, err := fs.ReadFile(srv.static, name)
+		if err != nil {
+			t.Fatalf("read asset: %v", err)
+		}
+		if len(data) > len(want) {
+			asset, want = name, data
+		}
+	}
+
+	for _, path := range []string{"/", "/" + asset} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", path, rec.Code)
+		}
+		body := rec.Body.Bytes()
+		if rec.Header().Get("Content-Encoding") == "gzip" {
+			body = gunzip(t, body)
+		}
+		if path == "/" && !strings.Contains(strings.ToLower(string(body)), "<!doctype html>") {
+			t.Fatalf("expected the index document, got %q", body)
+		}
+		if path != "/" && (!bytes.Equal(body, want) || rec.Header().Get("Content-Encoding") != "gzip") {
+			t.Fatalf("expected the gzip encoded asset, got %d bytes, headers %v", len(body), rec.Header())
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package dashboard
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ipFilter restricts requests to allowed source ranges. The client address is
+// taken from X-Forwarded-For only when the direct peer is a trusted proxy.
+type ipFilter struct {
+	allow   []netip.Prefix
+	trusted []netip.Prefix
+}
+
+func newIPFilter(allowlist, trustedProxies []string) (ipFilter, error) {
+	allow, err := parsePrefixes(allowlist)
+	if err != nil {
+		return ipFilter{}, fmt.Errorf("dashboard.ip_allowlist: %w", err)
+	}
+	trusted, err := parsePrefixes(trustedProxies)
+	if err != nil {
+		return ipFilter{}, fmt.Errorf("dashboard.trusted_proxies: %w", err)
+	}
+	return ipFilter{allow: allow, trusted: trusted}, nil
+}
+
+// parsePrefixes accepts CIDR ranges and bare addresses.
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	out := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if strings.Contains(value, "/") {
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, err
+		}
+		addr = addr.Unmap()
+		out = append(out, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return out, nil
+}
+
+func (f ipFilter) enabled() bool {
+	return len(f.allow) > 0
+}
+
+func (f ipFilter) allowed(addr netip.Addr) bool {
+	if !f.enabled() {
+		return true
+	}
+	return addr.IsValid() && containsAddr(f.allow, addr)
+}
+
+// clientIP returns the request source. Behind trusted proxies it walks
+// X-Forwarded-For from the right and returns the first untrusted hop, falling
+// back to X-Real-IP when the proxy sets only that. Headers from untrusted
+// peers are ignored.
+func (f ipFilter) clientIP(r *http.Request) netip.Addr {
+	addr := parseHostAddr(r.RemoteAddr)
+	if !addr.IsValid() || !containsAddr(f.trusted, addr) {
+		return addr
+	}
+	forwarded := strings.Join(r.Header.Values("X-Forwarded-For"), ",")
+	if strings.TrimSpace(forwarded) == "" {
+		if real := parseHostAddr(r.Header.Get("X-Real-IP")); real.IsValid() {
+			return real
+		}
+		return addr
+	}
+	hops := strings.Split(forwarded, ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := parseHostAddr(hops[i])
+		if !hop.IsValid() {
+			return addr
+		}
+		addr = hop
+		if !containsAddr(f.trusted, hop) {
+			break
+		}
+	}
+	return addr
+}
+
+// clientIP identifies the client for rate limiting and request logs.
+func (s *Server) clientIP(r *http.Request) string {
+	if addr := s.ipFilter.clientIP(r); addr.IsValid() {
+		return addr.String()
+	}
+	return sanitizeRemoteAddr(r.RemoteAddr)
+}
+
+func parseHostAddr(raw string) netip.Addr {
+	raw = strings.TrimSpace(raw)
+	if host, _, err := net.SplitHostPort(raw); err == nil {
+		raw = host
+	}
+	addr, err := netip.ParseAddr(strings.Trim(raw, "[]"))
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr.Unmap()
+}
+
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
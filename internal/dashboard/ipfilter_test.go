@@ -0,0 +1,70 @@
+package dashboard
+
+import (
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestIPFilterClientIPHonorsTrustedProxies(t *testing.T) {
+	t.Parallel()
+
+	filter, err := newIPFilter(nil, []string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("new filter: %v", err)
+	}
+
+	direct := httptest.NewRequest("GET", "/", nil)
+	direct.RemoteAddr = "203.0.113.7:5000"
+	direct.Header.Set("X-Forwarded-For", "192.0.2.1")
+	if got := filter.clientIP(direct); got != netip.MustParseAddr("203.0.113.7") {
+		t.Fatalf("untrusted peer must not be able to spoof X-Forwarded-For, got %s", got)
+	}
+
+	proxied := httptest.NewRequest("GET", "/", nil)
+	proxied.RemoteAddr = "10.0.0.2:5000"
+	proxied.Header.Set("X-Forwarded-For", "192.0.2.1, 198.51.100.4, 10.0.0.3")
+	if got := filter.clientIP(proxied); got != netip.MustParseAddr("198.51.100.4") {
+		t.Fatalf("expected first untrusted hop from the right, got %s", got)
+	}
+
+	realIP := httptest.NewRequest("GET", "/", nil)
+	realIP.RemoteAddr = "10.0.0.2:5000"
+	realIP.Header.Set("X-Real-IP", "198.51.100.9")
+	if got := filter.clientIP(realIP); got != netip.MustParseAddr("198.51.100.9") {
+		t.Fatalf("expected X-Real-IP from trusted proxy, got %s", got)
+	}
+
+	spoofed := httptest.NewRequest("GET", "/", nil)
+	spoofed.RemoteAddr = "203.0.113.7:5000"
+	spoofed.Header.Set("X-Real-IP", "192.0.2.1")
+	if got := filter.clientIP(spoofed); got != netip.MustParseAddr("203.0.113.7") {
+		t.Fatalf("untrusted peer must not be able to spoof X-Real-IP, got %s", got)
+	}
+}
+
+func TestIPFilterAllowlist(t *testing.T) {
+	t.Parallel()
+
+	if _, err := newIPFilter([]string{"not-a-cidr"}, nil); err == nil {
+		t.Fatal("expected invalid allowlist entry to be rejected")
+	}
+	filter, err := newIPFilter([]string{"192.168.1.0/24", "2001:db8::1"}, nil)
+	if err != nil {
+		t.Fatalf("new filter: %v", err)
+	}
+	cases := map[string]bool{
+		"192.168.1.20": true,
+		"192.168.2.20": false,
+		"2001:db8::1":  true,
+		"2001:db8::2":  false,
+	}
+	for raw, want := range cases {
+		if got := filter.allowed(netip.MustParseAddr(raw)); got != want {
+			t.Fatalf("%s: expected allowed=%v, got %v", raw, want, got)
+		}
+	}
+	if !(ipFilter{}).allowed(netip.Addr{}) {
+		t.Fatal("empty allowlist must allow all sources")
+	}
+}
@@ -0,0 +1,162 @@
+package dashboard
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"trackway/internal/config"
+	"trackway/internal/tracker"
+)
+
+// metricsAuth guards /metrics. The zero value leaves the endpoint open, which
+// is only meant for deployments where the dashboard is internal.
+type metricsAuth struct {
+	bearerToken string
+	username    string
+	password    string
+}
+
+func newMetricsAuth(cfg config.Dashboard) metricsAuth {
+	auth := metricsAuth{bearerToken: cfg.MetricsBearerToken}
+	if cfg.MetricsBasicAuth != nil {
+		auth.username = cfg.MetricsBasicAuth.Username
+		auth.password = cfg.MetricsBasicAuth.Password
+	}
+	return auth
+}
+
+func (a metricsAuth) open() bool {
+	return a.bearerToken == "" && a.username == ""
+}
+
+func (a metricsAuth) allowed(r *http.Request) bool {
+	if a.open() {
+		return true
+	}
+	if a.bearerToken != "" {
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && secretEqual(token, a.bearerToken) {
+			return true
+		}
+	}
+	if a.username != "" {
+		user, pass, ok := r.BasicAuth()
+		// Evaluate both comparisons so timing does not reveal which one failed.
+		userOK := secretEqual(user, a.username)
+		passOK := secretEqual(pass, a.password)
+		if ok && userOK && passOK {
+			return true
+		}
+	}
+	return false
+}
+
+// secretEqual compares in constant time; hashing first hides length.
+func secretEqual(got, want string) bool {
+	gotSum := sha256.Sum256([]byte(got))
+	wantSum := sha256.Sum256([]byte(want))
+	return subtle.ConstantTimeCompare(gotSum[:], wantSum[:]) == 1
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.metricsAuth.allowed(r) {
+		if s.metricsAuth.username != "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="trackway metrics"`)
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	snapshot := s.provider.Snapshot()
+	var sb strings.Builder
+	sb.WriteString("# HELP trackway_target_up Whether the target is UP (1) or DOWN (0).\n")
+	sb.WriteString("# TYPE trackway_target_up gauge\n")
+	for _, target := range snapshot.Targets {
+		value := 0
+		switch target.Status {
+		case "UP":
+			value = 1
+		case "DOWN":
+		default:
+			continue
+		}
+		fmt.Fprintf(&sb, "trackway_target_up{%s} %d\n", targetLabels(target), value)
+	}
+	sb.WriteString("# HELP trackway_target_checks_total Checks run for the target since start.\n")
+	sb.WriteString("# TYPE trackway_target_checks_total counter\n")
+	for _, target := range snapshot.Targets {
+		fmt.Fprintf(&sb, "trackway_target_checks_total{%s} %d\n", targetLabels(target), target.Checks)
+	}
+	sb.WriteString("# HELP trackway_target_check_failures_total Failed checks of the target since start.\n")
+	sb.WriteString("# TYPE trackway_target_check_failures_total counter\n")
+	for _, target := range snapshot.Targets {
+		fmt.Fprintf(&sb, "trackway_target_check_failures_total{%s} %d\n", targetLabels(target), target.CheckFailures)
+	}
+	sb.WriteString("# HELP trackway_target_last_latency_seconds Latency of the last successful check.\n")
+	sb.WriteString("# TYPE trackway_target_last_latency_seconds gauge\n")
+	for _, target := range snapshot.Targets {
+		if target.LastLatency > 0 {
+			fmt.Fprintf(&sb, "trackway_target_last_latency_seconds{%s} %g\n", targetLabels(target), target.LastLatency.Seconds())
+		}
+	}
+	sb.WriteString("# HELP trackway_dashboard_requests_total Dashboard HTTP requests by status code.\n")
+	sb.WriteString("# TYPE trackway_dashboard_requests_total counter\n")
+	for _, count := range s.requests.counts() {
+		fmt.Fprintf(&sb, "trackway_dashboard_requests_total{code=\"%d\"} %d\n", count.code, count.total)
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write([]byte(sb.String()))
+}
+
+func targetLabels(target tracker.TargetSnapshot) string {
+	return fmt.Sprintf(
+		"target=\"%s\",endpoint=\"%s\"",
+		escapeLabelValue(target.Name),
+		escapeLabelValue(fmt.Sprintf("%s:%d", target.Address, target.Port)),
+	)
+}
+
+// requestCounter counts dashboard responses by status code.
+type requestCounter struct {
+	mu     sync.Mutex
+	byCode map[int]int64
+}
+
+type codeCount struct {
+	code  int
+	total int64
+}
+
+func (c *requestCounter) add(code int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.byCode == nil {
+		c.byCode = make(map[int]int64)
+	}
+	c.byCode[code]++
+}
+
+func (c *requestCounter) counts() []codeCount {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]codeCount, 0, len(c.byCode))
+	for code, total := range c.byCode {
+		out = append(out, codeCount{code: code, total: total})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].code < out[j].code })
+	return out
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
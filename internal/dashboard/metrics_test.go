@@ -0,0 +1,95 @@
+package dashboard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"trackway/internal/config"
+	"trackway/internal/tracker"
+)
+
+type metricsProvider struct {
+	stubProvider
+}
+
+func (metricsProvider) Snapshot() tracker.Snapshot {
+	return tracker.Snapshot{Targets: []tracker.TargetSnapshot{
+		{Name: `web "main"`, Address: "10.0.0.1", Port: 443, Status: "UP", Checks: 12, CheckFailures: 2, LastLatency: 12 * time.Millisecond},
+		{Name: "db", Address: "10.0.0.2", Port: 5432, Status: "DOWN"},
+		{Name: "new", Address: "10.0.0.3", Port: 22, Status: "UNKNOWN"},
+	}}
+}
+
+func TestMetricsEndpointRequiresConfiguredBasicAuth(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress:    ":0",
+		PublicURL:        "http://127.0.0.1:8080",
+		MetricsBasicAuth: &config.MetricsBasicAuth{Username: "prom", Password: "s3cret"},
+	}, "test-bot-token", metricsProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	scrape := func(user, pass string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if user != "" {
+			req.SetBasicAuth(user, pass)
+		}
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := scrape("", ""); rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+		t.Fatalf("expected basic auth challenge, got %d", rec.Code)
+	}
+	if rec := scrape("prom", "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for wrong password, got %d", rec.Code)
+	}
+	rec := scrape("prom", "s3cret")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `trackway_target_up{target="web \"main\"",endpoint="10.0.0.1:443"} 1`) ||
+		!strings.Contains(body, `trackway_target_up{target="db",endpoint="10.0.0.2:5432"} 0`) ||
+		strings.Contains(body, `trackway_target_up{target="new"`) {
+		t.Fatalf("unexpected metrics body: %s", body)
+	}
+	for _, want := range []string{
+		`trackway_target_checks_total{target="web \"main\"",endpoint="10.0.0.1:443"} 12`,
+		`trackway_target_check_failures_total{target="web \"main\"",endpoint="10.0.0.1:443"} 2`,
+		`trackway_target_last_latency_seconds{target="web \"main\"",endpoint="10.0.0.1:443"} 0.012`,
+		`trackway_target_checks_total{target="new",endpoint="10.0.0.3:22"} 0`,
+		`trackway_dashboard_requests_total{code="401"} 2`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q in metrics body: %s", want, body)
+		}
+	}
+}
+
+func TestMetricsAuthBearerAndOpen(t *testing.T) {
+	t.Parallel()
+
+	open := newMetricsAuth(config.Dashboard{})
+	if !open.allowed(httptest.NewRequest(http.MethodGet, "/metrics", nil)) {
+		t.Fatal("expected metrics to be open without credentials configured")
+	}
+
+	auth := newMetricsAuth(config.Dashboard{MetricsBearerToken: "tok"})
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer tok")
+	if !auth.allowed(req) {
+		t.Fatal("expected bearer token to be accepted")
+	}
+	req.Header.Set("Authorization", "Bearer other")
+	if auth.allowed(req) {
+		t.Fatal("expected wrong bearer token to be rejected")
+	}
+}
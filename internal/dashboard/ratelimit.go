@@ -56,6 +56,36 @@ func (l *rateLimiter) Allow(now time.Time, key string) bool {
 	return true
 }
 
+// Blocked reports whether key has used up its current window, without
+// counting a request.
+func (l *rateLimiter) Blocked(now time.Time, key string) bool {
+	if l == nil || key == "" {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.clients[key]
+	return ok && now.Sub(entry.start) < l.window && entry.count >= l.limit
+}
+
+// RetryAfter reports how long key has to wait before its window resets.
+func (l *rateLimiter) RetryAfter(now time.Time, key string) time.Duration {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.clients[key]
+	if !ok {
+		return 0
+	}
+	return max(l.window-now.Sub(entry.start), 0)
+}
+
 func (l *rateLimiter) cleanup(now time.Time) {
 	for key, entry := range l.clients {
 		if now.Sub(entry.start) >= l.window {
@@ -1,19 +1,24 @@
 package dashboard
 
 import (
+	"bytes"
 	"context"
 	"embed"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"log/slog"
+	"math"
 	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"trackway/internal/config"
@@ -28,6 +33,10 @@ const (
 	maxJSONBodySize   = 16 * 1024
 	maxFormBodySize   = 4 * 1024
 	requestIDHeader   = "X-Request-ID"
+	// A monitor without a finished check round for staleRounds intervals,
+	// and at least minStaleAfter, fails /healthz.
+	staleRounds   = 5
+	minStaleAfter = time.Minute
 )
 
 //go:embed all:frontend/dist
@@ -36,24 +45,51 @@ var staticFiles embed.FS
 type DataProvider interface {
 	Snapshot() tracker.Snapshot
 	Logs(trackName string, days int, limit int) ([]logstore.Row, bool)
+	ListTargets() ([]logstore.Target, error)
 	UpsertTarget(name, address string, port int) error
 	DeleteTarget(name string) error
+	Backup(ctx context.Context) (io.ReadCloser, int64, error)
+	ExportTargets() []config.Target
+	SLA(trackName string, days int) ([]tracker.SLAReport, bool)
+	Incidents(trackName string, days int) ([]logstore.Incident, bool)
+	Mute(trackName string, until time.Time) (time.Time, error)
+	Unmute(trackName string) error
+	Settings() tracker.MonitoringSettings
+	UpdateSettings(values map[string]int) (tracker.MonitoringSettings, error)
+	Ready() bool
+	LastRoundAt() time.Time
+	ResendLastAlert(ctx context.Context) (int, error)
+	SubscribeRounds() (<-chan struct{}, func())
 }
 
 type Server struct {
 	logger                *slog.Logger
 	provider              DataProvider
 	auth                  *authManager
+	links                 *linkSigner
 	miniApp               *miniAppVerifier
 	miniAppOn             bool
 	allowedTelegramUserID int64
 	listenAddr            string
 	publicURL             string
+	basePath              string
 	secureCookie          bool
 	static                fs.FS
+	staticDir             string
+	maxBodyBytes          int64
+	ipFilter              ipFilter
+	corsOrigins           corsOrigins
+	metricsAuth           metricsAuth
+	basicAuthUser         string
+	basicAuthPassword     string
+	publicBadge           bool
+	requests              requestCounter
+	streams               atomic.Int32
+	startedAt             time.Time
 	httpServer            *http.Server
 	authRateLimiter       *rateLimiter
 	mutationRateLimiter   *rateLimiter
+	backupRateLimiter     *rateLimiter
 }
 
 func New(cfg config.Dashboard, botToken string, provider DataProvider, allowedTelegramUserID ...int64) (*Server, error) {
@@ -61,7 +97,7 @@ func New(cfg config.Dashboard, botToken string, provider DataProvider, allowedTe
 		return nil, errors.New("dashboard data provider is required")
 	}
 
-	staticFS, err := fs.Sub(staticFiles, "frontend/dist")
+	staticFS, err := staticFilesystem(cfg.StaticDir)
 	if err != nil {
 		return nil, err
 	}
@@ -71,6 +107,35 @@ func New(cfg config.Dashboard, botToken string, provider DataProvider, allowedTe
 		tokenTTL = 5 * time.Minute
 	}
 
+	filter, err := newIPFilter(cfg.IPAllowlist, cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	origins, err := newCORSOrigins(cfg.AllowedOrigins)
+	if err != nil {
+		return nil, err
+	}
+
+	maxBodyBytes := cfg.MaxBodyBytes
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = maxJSONBodySize
+	}
+
+	authRateLimit := cfg.AuthRateLimit
+	if authRateLimit <= 0 {
+		authRateLimit = 20
+	}
+	authRateWindow := time.Duration(cfg.AuthRateWindowSeconds) * time.Second
+	if authRateWindow <= 0 {
+		authRateWindow = time.Minute
+	}
+
+	links, err := newLinkSigner(botToken, signedLinkTTL)
+	if err != nil {
+		return nil, err
+	}
+
 	allowedUserID := int64(0)
 	if len(allowedTelegramUserID) > 0 {
 		allowedUserID = allowedTelegramUserID[0]
@@ -79,32 +144,61 @@ func New(cfg config.Dashboard, botToken string, provider DataProvider, allowedTe
 	srv := &Server{
 		logger:                slog.Default(),
 		provider:              provider,
+		startedAt:             time.Now().UTC(),
 		auth:                  newAuthManager(tokenTTL, sessionMaxAge),
+		links:                 links,
 		miniApp:               newMiniAppVerifier(botToken, time.Duration(cfg.MiniAppMaxAgeSec)*time.Second),
 		miniAppOn:             cfg.MiniAppEnabled,
 		allowedTelegramUserID: allowedUserID,
 		listenAddr:            cfg.ListenAddress,
 		publicURL:             strings.TrimRight(cfg.PublicURL, "/"),
+		basePath:              cfg.BasePath,
 		secureCookie:          cfg.SecureCookie,
 		static:                staticFS,
-		authRateLimiter:       newRateLimiter(20, time.Minute),
+		staticDir:             cfg.StaticDir,
+		maxBodyBytes:          maxBodyBytes,
+		ipFilter:              filter,
+		corsOrigins:           origins,
+		metricsAuth:           newMetricsAuth(cfg),
+		basicAuthUser:         cfg.BasicAuthUser,
+		basicAuthPassword:     cfg.BasicAuthPassword,
+		publicBadge:           cfg.PublicBadge,
+		authRateLimiter:       newRateLimiter(authRateLimit, authRateWindow),
 		mutationRateLimiter:   newRateLimiter(60, time.Minute),
+		backupRateLimiter:     newRateLimiter(3, 10*time.Minute),
 	}
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/healthz", srv.handleHealth)
+	mux.HandleFunc("/readyz", srv.handleReady)
+	mux.HandleFunc("/metrics", srv.handleMetrics)
+	mux.HandleFunc("/api/badge.svg", srv.handleBadge)
 	mux.HandleFunc("/auth/verify", srv.handleAuthVerify)
+	mux.HandleFunc("/auth/link", srv.handleSignedLink)
 	mux.HandleFunc("/auth/logout", srv.handleAuthLogout)
 	mux.HandleFunc("/api/auth/session", srv.handleAuthSession)
 	mux.HandleFunc("/api/auth/telegram-miniapp", srv.handleTelegramMiniAppAuth)
 	mux.HandleFunc("/api/status", srv.requireAuth(srv.handleStatus))
+	mux.HandleFunc("/api/stream", srv.requireAuth(srv.handleStream))
 	mux.HandleFunc("/api/logs", srv.requireAuth(srv.handleLogs))
+	mux.HandleFunc("/api/logs.csv", srv.requireAuth(srv.handleLogsCSV))
+	mux.HandleFunc("/api/sla", srv.requireAuth(srv.handleSLA))
+	mux.HandleFunc("/api/incidents", srv.requireAuth(srv.handleIncidents))
+	mux.HandleFunc("/api/uptime", srv.requireAuth(srv.handleSLA))
 	mux.HandleFunc("/api/targets", srv.requireAuth(srv.handleTargets))
+	mux.HandleFunc("/api/targets/export", srv.requireAuth(srv.handleTargetsExport))
+	mux.HandleFunc("/api/targets/mute", srv.requireAuth(srv.handleTargetMute))
+	mux.HandleFunc("/api/targets/unmute", srv.requireAuth(srv.handleTargetMute))
+	mux.HandleFunc("/api/backup", srv.requireAuth(srv.handleBackup))
+	mux.HandleFunc("/api/settings", srv.requireAuth(srv.handleSettings))
+	mux.HandleFunc("/api/alerts/resend", srv.requireAuth(srv.handleAlertsResend))
+	mux.HandleFunc("/api/sessions", srv.requireAuth(srv.handleSessions))
+	mux.HandleFunc("/api/sessions/revoke-all", srv.requireAuth(srv.handleSessionsRevokeAll))
 	mux.Handle("/", srv.staticHandler())
 
 	srv.httpServer = &http.Server{
 		Addr:              srv.listenAddr,
-		Handler:           srv.withMiddlewares(mux),
+		Handler:           srv.withMiddlewares(srv.mountBasePath(withGzip(srv.withCORS(mux)))),
 		ReadHeaderTimeout: 5 * time.Second,
 		ReadTimeout:       15 * time.Second,
 		WriteTimeout:      30 * time.Second,
@@ -113,6 +207,39 @@ func New(cfg config.Dashboard, botToken string, provider DataProvider, allowedTe
 	return srv, nil
 }
 
+// mountBasePath serves handler under the configured base path. /healthz and
+// /readyz stay reachable at the root as well so probes do not need to know
+// the prefix.
+func (s *Server) mountBasePath(handler http.Handler) http.Handler {
+	if s.basePath == "" {
+		return handler
+	}
+	root := http.NewServeMux()
+	root.HandleFunc("/healthz", s.handleHealth)
+	root.HandleFunc("/readyz", s.handleReady)
+	root.Handle(s.basePath+"/", http.StripPrefix(s.basePath, handler))
+	root.HandleFunc(s.basePath, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, s.basePath+"/", http.StatusMovedPermanently)
+	})
+	return root
+}
+
+// staticFilesystem returns the embedded frontend build, or the given directory
+// when set so frontend changes are picked up without rebuilding the binary.
+func staticFilesystem(dir string) (fs.FS, error) {
+	if dir == "" {
+		return fs.Sub(staticFiles, "frontend/dist")
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("dashboard.static_dir: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("dashboard.static_dir is not a directory: %s", dir)
+	}
+	return os.DirFS(dir), nil
+}
+
 type statusWriter struct {
 	http.ResponseWriter
 	status int
@@ -123,6 +250,10 @@ func (w *statusWriter) WriteHeader(code int) {
 	w.ResponseWriter.WriteHeader(code)
 }
 
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 func (s *Server) withMiddlewares(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		startedAt := time.Now().UTC()
@@ -139,8 +270,16 @@ func (s *Server) withMiddlewares(next http.Handler) http.Handler {
 					"request_id": requestID,
 				})
 			}
+			s.requests.add(statusCapture.status)
 		}()
-		next.ServeHTTP(statusCapture, r)
+		clientIP := s.ipFilter.clientIP(r)
+		if !s.isProbePath(r.URL.Path) && !s.ipFilter.allowed(clientIP) {
+			writeJSON(statusCapture, http.StatusForbidden, map[string]any{
+				"error": "source address is not allowed",
+			})
+		} else {
+			next.ServeHTTP(statusCapture, r)
+		}
 
 		s.logger.Info(
 			"http request",
@@ -150,6 +289,7 @@ func (s *Server) withMiddlewares(next http.Handler) http.Handler {
 			"status", statusCapture.status,
 			"duration_ms", time.Since(startedAt).Milliseconds(),
 			"remote_addr", sanitizeRemoteAddr(r.RemoteAddr),
+			"client_ip", s.clientIP(r),
 		)
 	})
 }
@@ -209,7 +349,7 @@ func (s *Server) ListenAndServe(ctx context.Context) error {
 	}()
 	defer close(stop)
 
-	s.logger.Info("dashboard listening", "addr", s.listenAddr)
+	s.logger.Info("dashboard listening", "addr", s.listenAddr, "static_dir", s.staticDir)
 	err := s.httpServer.ListenAndServe()
 	if err == nil {
 		return nil
@@ -220,13 +360,57 @@ func (s *Server) ListenAndServe(ctx context.Context) error {
 	return err
 }
 
+// handleHealth reports 503 once the monitor has not finished a check round
+// for longer than the stale threshold, counted from start until the first
+// round, so a liveness probe restarts a wedged instance.
 func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
-	writeJSON(w, http.StatusOK, map[string]any{
-		"ok":   true,
-		"time": time.Now().UTC().Format(time.RFC3339),
+	now := time.Now().UTC()
+	staleAfter := max(staleRounds*time.Duration(s.provider.Settings().IntervalSeconds)*time.Second, minStaleAfter)
+	lastRound := s.provider.LastRoundAt()
+	since := s.startedAt
+	if !lastRound.IsZero() {
+		since = lastRound
+	}
+	ok := now.Sub(since) <= staleAfter
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusServiceUnavailable
+	}
+	body := map[string]any{
+		"ok":                  ok,
+		"time":                now.Format(time.RFC3339),
+		"checks_run":          !lastRound.IsZero(),
+		"stale_after_seconds": int(staleAfter.Seconds()),
+	}
+	if !lastRound.IsZero() {
+		body["last_round_at"] = lastRound.Format(time.RFC3339)
+	}
+	writeJSON(w, status, body)
+}
+
+// handleReady reports 503 until the first check cycle has completed, so load
+// balancers do not route to a dashboard that still shows every target UNKNOWN.
+func (s *Server) handleReady(w http.ResponseWriter, _ *http.Request) {
+	ready := s.provider.Ready()
+	status := http.StatusOK
+	if !ready {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, map[string]any{
+		"ok":    ready,
+		"ready": ready,
+		"time":  time.Now().UTC().Format(time.RFC3339),
 	})
 }
 
+func (s *Server) isProbePath(path string) bool {
+	switch path {
+	case "/healthz", "/readyz", s.basePath + "/healthz", s.basePath + "/readyz":
+		return true
+	}
+	return false
+}
+
 func (s *Server) NewAuthLink() (string, error) {
 	if s.publicURL == "" {
 		return "", errors.New("dashboard.public_url is empty")
@@ -248,6 +432,29 @@ func (s *Server) NewAuthLink() (string, error) {
 
 func (s *Server) requireAuth(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); ok && s.basicAuthUser != "" {
+			// Only failed attempts count against the limiter, so polling
+			// clients with valid credentials never lock out a login.
+			clientID, now := s.clientIP(r), time.Now().UTC()
+			if s.authRateLimiter.Blocked(now, clientID) {
+				writeTooManyRequests(w, s.authRateLimiter, now, clientID)
+				return
+			}
+			// Evaluate both comparisons so timing does not reveal which one failed.
+			userOK := secretEqual(user, s.basicAuthUser)
+			passOK := secretEqual(pass, s.basicAuthPassword)
+			if !userOK || !passOK {
+				s.authRateLimiter.Allow(now, clientID)
+				w.Header().Set("WWW-Authenticate", `Basic realm="trackway", charset="UTF-8"`)
+				writeJSON(w, http.StatusUnauthorized, map[string]any{
+					"authorized": false,
+					"error":      "invalid credentials",
+				})
+				return
+			}
+			next(w, r)
+			return
+		}
 		now := time.Now().UTC()
 		sessionID, ok := s.sessionIDFromRequest(r)
 		if !ok {
@@ -271,9 +478,11 @@ func (s *Server) requireAuth(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// requireSameOrigin rejects browser requests from other origins unless they
+// are listed in dashboard.allowed_origins.
 func (s *Server) requireSameOrigin(w http.ResponseWriter, r *http.Request) bool {
 	origin := strings.TrimSpace(r.Header.Get("Origin"))
-	if origin == "" {
+	if origin == "" || s.corsOrigins.allowed(origin) {
 		return true
 	}
 
@@ -329,14 +538,23 @@ func forwardedScheme(r *http.Request) string {
 }
 
 func (s *Server) enforceRateLimit(w http.ResponseWriter, r *http.Request, limiter *rateLimiter) bool {
-	clientID := sanitizeRemoteAddr(r.RemoteAddr)
-	if limiter.Allow(time.Now().UTC(), clientID) {
+	clientID := s.clientIP(r)
+	now := time.Now().UTC()
+	if limiter.Allow(now, clientID) {
 		return true
 	}
+	writeTooManyRequests(w, limiter, now, clientID)
+	return false
+}
+
+// writeTooManyRequests answers 429 with the seconds left in the window of
+// clientID as Retry-After.
+func writeTooManyRequests(w http.ResponseWriter, limiter *rateLimiter, now time.Time, clientID string) {
+	retryAfter := int(math.Ceil(limiter.RetryAfter(now, clientID).Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
 	writeJSON(w, http.StatusTooManyRequests, map[string]any{
 		"error": "too many requests",
 	})
-	return false
 }
 
 func (s *Server) handleAuthVerify(w http.ResponseWriter, r *http.Request) {
@@ -385,7 +603,7 @@ func (s *Server) handleAuthVerify(w http.ResponseWriter, r *http.Request) {
 	}
 
 	s.setSessionCookie(w, sessionID)
-	http.Redirect(w, r, "/", http.StatusFound)
+	http.Redirect(w, r, s.basePath+"/", http.StatusFound)
 }
 
 func (s *Server) renderVerifyPage(w http.ResponseWriter, token string) {
@@ -399,9 +617,10 @@ func (s *Server) renderVerifyPage(w http.ResponseWriter, token string) {
 			"h1{font-size:20px;margin:0 0 12px}p{color:#a7beca}button{background:#2093c3;color:white;border:0;padding:10px 14px;border-radius:8px;cursor:pointer}"+
 			"code{background:#10202d;border:1px solid #2e4a5b;padding:2px 6px;border-radius:6px}</style></head><body>"+
 			"<main class=\"card\"><h1>Authorize dashboard session</h1><p>Press the button below in the same browser where you will open dashboard.</p>"+
-			"<form method=\"post\" action=\"/auth/verify\"><input type=\"hidden\" name=\"token\" value=\"%s\"><button type=\"submit\">Authorize this browser</button></form>"+
+			"<form method=\"post\" action=\"%s/auth/verify\"><input type=\"hidden\" name=\"token\" value=\"%s\"><button type=\"submit\">Authorize this browser</button></form>"+
 			"<p>Token is one-time and expires quickly.</p><p>If this page was opened by a link preview bot, just ignore it and open the link manually.</p>"+
 			"</main></body></html>",
+		util.HTMLAttrEscape(s.basePath),
 		util.HTMLEscape(token),
 	)
 }
@@ -428,6 +647,117 @@ func (s *Server) handleAuthLogout(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (s *Server) handleSettings(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.provider.Settings())
+	case http.MethodPost, http.MethodPatch:
+		if !s.requireSameOrigin(w, r) {
+			return
+		}
+		if !s.enforceRateLimit(w, r, s.mutationRateLimiter) {
+			return
+		}
+		var values map[string]int
+		if !s.decodeJSONBody(w, r, &values) {
+			return
+		}
+		if len(values) == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]any{
+				"error": "no settings given",
+			})
+			return
+		}
+		settings, err := s.provider.UpdateSettings(values)
+		if errors.Is(err, tracker.ErrInvalidSetting) {
+			writeJSON(w, http.StatusBadRequest, map[string]any{
+				"error": err.Error(),
+			})
+			return
+		}
+		if err != nil {
+			s.logger.Error("settings update failed", "error", err)
+			writeJSON(w, http.StatusInternalServerError, map[string]any{
+				"error": "settings update failed",
+			})
+			return
+		}
+		writeJSON(w, http.StatusOK, settings)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	expiries := s.auth.SessionExpiries(time.Now().UTC())
+	expiresAt := make([]string, 0, len(expiries))
+	for _, expiry := range expiries {
+		expiresAt = append(expiresAt, expiry.Format(time.RFC3339))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"count":      len(expiries),
+		"expires_at": expiresAt,
+	})
+}
+
+// handleAlertsResend replays the last alert batch, e.g. to confirm delivery
+// after fixing a webhook or chat.
+func (s *Server) handleAlertsResend(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.requireSameOrigin(w, r) {
+		return
+	}
+	if !s.enforceRateLimit(w, r, s.mutationRateLimiter) {
+		return
+	}
+
+	groups, err := s.provider.ResendLastAlert(r.Context())
+	if errors.Is(err, tracker.ErrNoAlertToResend) {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]any{
+			"error":  "some deliveries failed: " + err.Error(),
+			"groups": groups,
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"ok":     true,
+		"groups": groups,
+	})
+}
+
+func (s *Server) handleSessionsRevokeAll(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.requireSameOrigin(w, r) {
+		return
+	}
+	if !s.enforceRateLimit(w, r, s.authRateLimiter) {
+		return
+	}
+
+	revoked := s.auth.RevokeAllSessions(time.Now().UTC())
+	s.logger.Warn("revoked all dashboard sessions", "count", revoked)
+	s.expireCookie(w)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"ok":      true,
+		"revoked": revoked,
+	})
+}
+
 func (s *Server) handleAuthSession(w http.ResponseWriter, r *http.Request) {
 	now := time.Now().UTC()
 	sessionID, ok := s.sessionIDFromRequest(r)
@@ -456,16 +786,25 @@ func (s *Server) handleAuthSession(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func (s *Server) handleStatus(w http.ResponseWriter, _ *http.Request) {
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.statusPayload(strings.TrimSpace(r.URL.Query().Get("tag"))))
+}
+
+// statusPayload is the /api/status body; a non-empty tag narrows it to the
+// targets carrying that tag, counters included.
+func (s *Server) statusPayload(tag string) map[string]any {
 	snapshot := s.provider.Snapshot()
-	writeJSON(w, http.StatusOK, map[string]any{
+	if tag != "" {
+		snapshot = snapshot.WithTag(tag)
+	}
+	return map[string]any{
 		"generated_at": snapshot.GeneratedAt.Format(time.RFC3339),
 		"total":        snapshot.Total,
 		"up":           snapshot.Up,
 		"down":         snapshot.Down,
 		"unknown":      snapshot.Unknown,
 		"targets":      snapshotTargets(snapshot),
-	})
+	}
 }
 
 func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
@@ -477,6 +816,62 @@ func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	days, hours, limit := logsQuery(r)
+	rows, ok := s.readLogs(track, days, hours, limit)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]any{
+			"error": "track not found",
+		})
+		return
+	}
+
+	zone := parseClientZone(r)
+
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		lines = append(lines, formatRowLine(row, zone))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"track":  track,
+		"days":   days,
+		"hours":  hours,
+		"limit":  limit,
+		"rows":   rows,
+		"text":   strings.Join(lines, "\n"),
+		"format": "DD.MM.YYYY HH:mm:ss",
+	})
+}
+
+// handleLogsCSV returns the rows of handleLogs as a CSV download.
+func (s *Server) handleLogsCSV(w http.ResponseWriter, r *http.Request) {
+	track := strings.TrimSpace(r.URL.Query().Get("track"))
+	if track == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{
+			"error": "track is required",
+		})
+		return
+	}
+	days, hours, limit := logsQuery(r)
+	rows, ok := s.readLogs(track, days, hours, limit)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]any{
+			"error": "track not found",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+util.SafeName(track)+`-logs.csv"`)
+	w.WriteHeader(http.StatusOK)
+	if err := logstore.WriteCSV(w, rows); err != nil {
+		s.logger.Warn("failed to write logs csv", "track", track, "error", err)
+	}
+}
+
+// logsQuery reads the days, hours and limit parameters of the logs endpoints.
+// hours widens days when it reaches further back.
+func logsQuery(r *http.Request) (int, int, int) {
 	days := parseQueryInt(r, "days", 7, 1, 365)
 	hours := parseQueryInt(r, "hours", 0, 0, 24*365)
 	limit := parseQueryInt(r, "limit", 5000, 1, 50000)
@@ -486,12 +881,13 @@ func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
 			days = roundedDays
 		}
 	}
+	return days, hours, limit
+}
+
+func (s *Server) readLogs(track string, days, hours, limit int) ([]logstore.Row, bool) {
 	rows, ok := s.provider.Logs(track, days, limit)
 	if !ok {
-		writeJSON(w, http.StatusNotFound, map[string]any{
-			"error": "track not found",
-		})
-		return
+		return nil, false
 	}
 	if hours > 0 {
 		cutoff := time.Now().UTC().Add(-time.Duration(hours) * time.Hour)
@@ -500,31 +896,102 @@ func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
 			rows = rows[len(rows)-limit:]
 		}
 	}
+	return rows, true
+}
 
-	zone := parseClientZone(r)
+func (s *Server) handleIncidents(w http.ResponseWriter, r *http.Request) {
+	track := strings.TrimSpace(r.URL.Query().Get("track"))
+	if track == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{
+			"error": "track is required",
+		})
+		return
+	}
+	days := parseQueryInt(r, "days", 7, 1, 365)
+	incidents, ok := s.provider.Incidents(track, days)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]any{
+			"error": "track not found",
+		})
+		return
+	}
 
-	lines := make([]string, 0, len(rows))
-	for _, row := range rows {
-		lines = append(lines, formatRowLine(row, zone))
+	now := time.Now().UTC()
+	items := make([]map[string]any, 0, len(incidents))
+	for _, incident := range incidents {
+		item := map[string]any{
+			"start":            incident.Start.UTC().Format(time.RFC3339),
+			"end":              nil,
+			"duration_seconds": int64(incident.Duration.Seconds()),
+			"ongoing":          incident.Ongoing,
+		}
+		if incident.Ongoing {
+			item["duration_seconds"] = int64(now.Sub(incident.Start).Seconds())
+		} else {
+			item["end"] = incident.End.UTC().Format(time.RFC3339)
+		}
+		items = append(items, item)
 	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"track":     track,
+		"days":      days,
+		"incidents": items,
+	})
+}
+
+func (s *Server) handleSLA(w http.ResponseWriter, r *http.Request) {
+	track := strings.TrimSpace(r.URL.Query().Get("track"))
+	days := parseQueryInt(r, "days", 7, 1, 365)
 
+	reports, ok := s.provider.SLA(track, days)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]any{
+			"error": "track not found",
+		})
+		return
+	}
+
+	targets := make([]map[string]any, 0, len(reports))
+	for _, report := range reports {
+		item := map[string]any{
+			"name":           report.Target,
+			"checks":         report.Checks,
+			"up_checks":      report.UpChecks,
+			"uptime_percent": report.UptimePercent,
+			"latency":        nil,
+		}
+		if report.Latency != nil {
+			item["latency"] = map[string]any{
+				"samples": report.Latency.Samples,
+				"p50_ms":  report.Latency.P50,
+				"p95_ms":  report.Latency.P95,
+				"p99_ms":  report.Latency.P99,
+			}
+		}
+		targets = append(targets, item)
+	}
 	writeJSON(w, http.StatusOK, map[string]any{
-		"track":  track,
-		"days":   days,
-		"hours":  hours,
-		"limit":  limit,
-		"rows":   rows,
-		"text":   strings.Join(lines, "\n"),
-		"format": "DD.MM.YYYY HH:mm:ss",
+		"days":    days,
+		"targets": targets,
 	})
 }
 
 func (s *Server) handleTargets(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
-		snapshot := s.provider.Snapshot()
+		if !s.requireSameOrigin(w, r) {
+			return
+		}
+		targets, err := s.provider.ListTargets()
+		if err != nil {
+			s.logger.Warn("target list failed", "error", err)
+			writeJSON(w, http.StatusInternalServerError, map[string]any{
+				"error": "failed to list targets",
+			})
+			return
+		}
 		writeJSON(w, http.StatusOK, map[string]any{
-			"targets": snapshotTargets(snapshot),
+			"targets": targets,
 		})
 		return
 	case http.MethodPost:
@@ -534,20 +1001,12 @@ func (s *Server) handleTargets(w http.ResponseWriter, r *http.Request) {
 		if !s.enforceRateLimit(w, r, s.mutationRateLimiter) {
 			return
 		}
-		r.Body = http.MaxBytesReader(w, r.Body, maxJSONBodySize)
-		defer r.Body.Close()
-
 		var payload struct {
 			Name    string `json:"name"`
 			Address string `json:"address"`
 			Port    int    `json:"port"`
 		}
-		decoder := json.NewDecoder(r.Body)
-		decoder.DisallowUnknownFields()
-		if err := decoder.Decode(&payload); err != nil {
-			writeJSON(w, http.StatusBadRequest, map[string]any{
-				"error": "invalid json body",
-			})
+		if !s.decodeJSONBody(w, r, &payload) {
 			return
 		}
 		if err := s.provider.UpsertTarget(payload.Name, payload.Address, payload.Port); err != nil {
@@ -592,7 +1051,9 @@ func (s *Server) handleTargets(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (s *Server) handleTelegramMiniAppAuth(w http.ResponseWriter, r *http.Request) {
+// handleTargetMute serves both mute and unmute. Mute takes either "until"
+// (RFC3339) or "minutes"; with neither the default mute duration applies.
+func (s *Server) handleTargetMute(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -600,30 +1061,210 @@ func (s *Server) handleTelegramMiniAppAuth(w http.ResponseWriter, r *http.Reques
 	if !s.requireSameOrigin(w, r) {
 		return
 	}
-	if !s.enforceRateLimit(w, r, s.authRateLimiter) {
+	if !s.enforceRateLimit(w, r, s.mutationRateLimiter) {
 		return
 	}
-	if !s.miniAppOn || s.miniApp == nil {
+
+	var payload struct {
+		Name    string `json:"name"`
+		Minutes int    `json:"minutes"`
+		Until   string `json:"until"`
+	}
+	if !s.decodeJSONBody(w, r, &payload) {
+		return
+	}
+	name := strings.TrimSpace(payload.Name)
+	if name == "" {
 		writeJSON(w, http.StatusBadRequest, map[string]any{
-			"error": "mini app auth is disabled",
+			"error": "name is required",
 		})
 		return
 	}
-	r.Body = http.MaxBytesReader(w, r.Body, maxJSONBodySize)
-	defer r.Body.Close()
 
-	var payload struct {
-		InitData string `json:"init_data"`
+	var (
+		until time.Time
+		err   error
+	)
+	if strings.HasSuffix(r.URL.Path, "/unmute") {
+		err = s.provider.Unmute(name)
+	} else {
+		switch {
+		case payload.Until != "":
+			until, err = time.Parse(time.RFC3339, payload.Until)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]any{
+					"error": "until must be RFC3339",
+				})
+				return
+			}
+		case payload.Minutes < 0:
+			writeJSON(w, http.StatusBadRequest, map[string]any{
+				"error": "minutes must be positive",
+			})
+			return
+		case payload.Minutes > 0:
+			until = time.Now().UTC().Add(time.Duration(payload.Minutes) * time.Minute)
+		}
+		until, err = s.provider.Mute(name, until)
+	}
+	if errors.Is(err, tracker.ErrTargetNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]any{
+			"error": "track not found",
+		})
+		return
+	}
+	if err != nil {
+		s.logger.Warn("target mute rejected", "track", name, "error", err)
+		writeJSON(w, http.StatusBadRequest, map[string]any{
+			"error": "invalid mute request",
+		})
+		return
+	}
+
+	var mutedUntil any
+	if !until.IsZero() {
+		mutedUntil = until.Format(time.RFC3339)
 	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"ok":          true,
+		"name":        name,
+		"muted_until": mutedUntil,
+	})
+}
+
+func (s *Server) handleTargetsExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format == "" {
+		format = "json"
+	}
+	data, err := tracker.EncodeTargets(s.provider.ExportTargets(), format)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{
+			"error": "format must be json or yaml",
+		})
+		return
+	}
+
+	contentType := "application/json; charset=utf-8"
+	extension := "json"
+	if format == "yaml" || format == "yml" {
+		contentType = "application/yaml; charset=utf-8"
+		extension = "yaml"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="trackway-targets.`+extension+`"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+func (s *Server) handleBackup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.enforceRateLimit(w, r, s.backupRateLimiter) {
+		return
+	}
+
+	snapshot, size, err := s.provider.Backup(r.Context())
+	if errors.Is(err, logstore.ErrNotSupported) {
+		writeJSON(w, http.StatusNotImplemented, map[string]any{
+			"error": "backup is not supported by storage backend",
+		})
+		return
+	}
+	if err != nil {
+		s.logger.Warn("database backup failed", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]any{
+			"error": "failed to create backup",
+		})
+		return
+	}
+	defer snapshot.Close()
+
+	// Large databases can take longer than the default write timeout.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(10 * time.Minute))
+
+	filename := "trackway-" + time.Now().UTC().Format("20060102-150405") + ".db"
+	w.Header().Set("Content-Type", "application/vnd.sqlite3")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, snapshot); err != nil {
+		s.logger.Warn("database backup stream interrupted", "error", err)
+	}
+}
+
+// miniAppInitDataFromHeader reads init data sent as "Authorization: tma <init_data>".
+func miniAppInitDataFromHeader(r *http.Request) (string, bool) {
+	scheme, initData, found := strings.Cut(strings.TrimSpace(r.Header.Get("Authorization")), " ")
+	if !found || !strings.EqualFold(scheme, "tma") {
+		return "", false
+	}
+	return strings.TrimSpace(initData), true
+}
+
+// decodeJSONBody decodes a single JSON object from a size-capped body, rejecting
+// unknown fields and trailing data. It writes the 400 response on failure.
+func (s *Server) decodeJSONBody(w http.ResponseWriter, r *http.Request, dst any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxBodyBytes)
+	defer r.Body.Close()
+
 	decoder := json.NewDecoder(r.Body)
 	decoder.DisallowUnknownFields()
-	if err := decoder.Decode(&payload); err != nil {
+	err := decoder.Decode(dst)
+	if err == nil && decoder.More() {
+		err = errors.New("unexpected data after json object")
+	}
+	if err == nil {
+		return true
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
 		writeJSON(w, http.StatusBadRequest, map[string]any{
-			"error": "invalid json body",
+			"error": "request body too large",
 		})
+		return false
+	}
+	writeJSON(w, http.StatusBadRequest, map[string]any{
+		"error": "invalid json body",
+	})
+	return false
+}
+
+func (s *Server) handleTelegramMiniAppAuth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	user, err := s.miniApp.Verify(payload.InitData, time.Now().UTC())
+	if !s.requireSameOrigin(w, r) {
+		return
+	}
+	if !s.enforceRateLimit(w, r, s.authRateLimiter) {
+		return
+	}
+	if !s.miniAppOn || s.miniApp == nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{
+			"error": "mini app auth is disabled",
+		})
+		return
+	}
+	initData, ok := miniAppInitDataFromHeader(r)
+	if !ok {
+		var payload struct {
+			InitData string `json:"init_data"`
+		}
+		if !s.decodeJSONBody(w, r, &payload) {
+			return
+		}
+		initData = payload.InitData
+	}
+	user, err := s.miniApp.Verify(initData, time.Now().UTC())
 	if err != nil {
 		s.logger.Warn("mini app auth failed", "error", err)
 		writeJSON(w, http.StatusUnauthorized, map[string]any{
@@ -688,10 +1329,10 @@ func (s *Server) setSessionCookie(w http.ResponseWriter, sessionID string) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     sessionCookieName,
 		Value:    sessionID,
-		Path:     "/",
+		Path:     s.basePath + "/",
 		HttpOnly: true,
 		Secure:   s.secureCookie,
-		SameSite: http.SameSiteLaxMode,
+		SameSite: s.cookieSameSite(),
 	})
 }
 
@@ -699,10 +1340,10 @@ func (s *Server) expireCookie(w http.ResponseWriter) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     sessionCookieName,
 		Value:    "",
-		Path:     "/",
+		Path:     s.basePath + "/",
 		HttpOnly: true,
 		Secure:   s.secureCookie,
-		SameSite: http.SameSiteLaxMode,
+		SameSite: s.cookieSameSite(),
 		MaxAge:   -1,
 		Expires:  time.Unix(0, 0),
 	})
@@ -732,7 +1373,7 @@ func (s *Server) staticHandler() http.Handler {
 			}
 			w.Header().Set("Content-Type", "text/html; charset=utf-8")
 			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write(indexBytes)
+			_, _ = w.Write(s.rewriteIndex(indexBytes))
 			return
 		}
 
@@ -742,6 +1383,17 @@ func (s *Server) staticHandler() http.Handler {
 	})
 }
 
+// rewriteIndex points the frontend at the base path: a <base> element for its
+// relative API calls and prefixed asset URLs.
+func (s *Server) rewriteIndex(index []byte) []byte {
+	base := fmt.Sprintf("<head><base href=\"%s/\">", util.HTMLAttrEscape(s.basePath))
+	out := bytes.Replace(index, []byte("<head>"), []byte(base), 1)
+	if s.basePath != "" {
+		out = bytes.ReplaceAll(out, []byte(`="/_astro/`), []byte(`="`+s.basePath+`/_astro/`))
+	}
+	return out
+}
+
 func writeJSON(w http.ResponseWriter, status int, value any) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(status)
@@ -777,20 +1429,53 @@ func formatRowLine(row logstore.Row, loc *time.Location) string {
 	if err == nil {
 		timestamp = ts.In(loc).Format("02.01.2006 15:04:05")
 	}
-	return timestamp + "  " + row.Status + "  " + row.Endpoint + "  " + row.Reason
+	line := timestamp + "  " + row.Status + "  " + row.Endpoint + "  " + util.SanitizeReason(row.Reason)
+	if row.Count > 1 {
+		lastSeen := row.LastSeen
+		if ts, err := time.Parse(time.RFC3339, row.LastSeen); err == nil {
+			lastSeen = ts.In(loc).Format("02.01.2006 15:04:05")
+		}
+		line += "  x" + strconv.Itoa(row.Count) + " until " + lastSeen
+	}
+	return line
 }
 
 func snapshotTargets(snapshot tracker.Snapshot) []map[string]any {
 	targets := make([]map[string]any, 0, len(snapshot.Targets))
 	for _, target := range snapshot.Targets {
-		targets = append(targets, map[string]any{
+		item := map[string]any{
 			"name":         target.Name,
 			"address":      target.Address,
 			"port":         target.Port,
 			"status":       target.Status,
 			"last_changed": util.FormatTime(target.LastChanged),
 			"last_checked": util.FormatTime(target.LastChecked),
-		})
+		}
+		if target.LastLatency > 0 {
+			item["last_latency_ms"] = float64(target.LastLatency.Microseconds()) / 1000
+		}
+		if target.ResolvedIP != "" {
+			item["resolved_ip"] = target.ResolvedIP
+			item["resolved_addrs"] = target.ResolvedAddrs
+		}
+		if !target.CertNotAfter.IsZero() {
+			item["cert_not_after"] = target.CertNotAfter.UTC().Format(time.RFC3339)
+			item["cert_days_left"] = int(time.Until(target.CertNotAfter).Hours() / 24)
+		}
+		if target.RunbookURL != "" {
+			item["runbook_url"] = target.RunbookURL
+		}
+		if !target.MutedUntil.IsZero() {
+			item["muted_until"] = target.MutedUntil.Format(time.RFC3339)
+		}
+		if len(target.Tags) > 0 {
+			item["tags"] = target.Tags
+		}
+		if target.AckedBy != "" {
+			item["acked_by"] = target.AckedBy
+			item["acked_at"] = target.AckedAt.UTC().Format(time.RFC3339)
+		}
+		targets = append(targets, item)
 	}
 	return targets
 }
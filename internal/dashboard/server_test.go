@@ -3,9 +3,15 @@ package dashboard
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -21,10 +27,18 @@ func (stubProvider) Snapshot() tracker.Snapshot {
 	return tracker.Snapshot{}
 }
 
+func (stubProvider) SubscribeRounds() (<-chan struct{}, func()) {
+	return make(chan struct{}), func() {}
+}
+
 func (stubProvider) Logs(string, int, int) ([]logstore.Row, bool) {
 	return nil, false
 }
 
+func (stubProvider) ListTargets() ([]logstore.Target, error) {
+	return nil, nil
+}
+
 func (stubProvider) UpsertTarget(string, string, int) error {
 	return nil
 }
@@ -33,7 +47,81 @@ func (stubProvider) DeleteTarget(string) error {
 	return nil
 }
 
+func (stubProvider) Backup(context.Context) (io.ReadCloser, int64, error) {
+	return nil, 0, logstore.ErrNotSupported
+}
+
+func (stubProvider) SLA(trackName string, _ int) ([]tracker.SLAReport, bool) {
+	if trackName != "" && trackName != "a" {
+		return nil, false
+	}
+	return []tracker.SLAReport{
+		{Target: "a", Days: 7, Checks: 4, UpChecks: 3, UptimePercent: 75, Latency: &tracker.LatencyPercentiles{Samples: 3, P50: 1, P95: 2, P99: 3}},
+		{Target: "b", Days: 7},
+	}, true
+}
+
+func (stubProvider) Incidents(trackName string, _ int) ([]logstore.Incident, bool) {
+	if trackName != "a" {
+		return nil, false
+	}
+	start := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	return []logstore.Incident{
+		{Start: time.Now().UTC().Add(-time.Minute), Ongoing: true},
+		{Start: start, End: start.Add(90 * time.Second), Duration: 90 * time.Second},
+	}, true
+}
+
+func (stubProvider) Mute(trackName string, until time.Time) (time.Time, error) {
+	if trackName != "a" {
+		return time.Time{}, tracker.ErrTargetNotFound
+	}
+	if until.IsZero() {
+		until = time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC)
+	}
+	return until, nil
+}
+
+func (stubProvider) Unmute(trackName string) error {
+	if trackName != "a" {
+		return tracker.ErrTargetNotFound
+	}
+	return nil
+}
+
+func (stubProvider) ResendLastAlert(context.Context) (int, error) {
+	return 0, tracker.ErrNoAlertToResend
+}
+
+func (stubProvider) Ready() bool {
+	return true
+}
+
+func (stubProvider) LastRoundAt() time.Time {
+	return time.Time{}
+}
+
+func (stubProvider) Settings() tracker.MonitoringSettings {
+	return tracker.MonitoringSettings{IntervalSeconds: 5, ConnectTimeoutSeconds: 2}
+}
+
+func (p stubProvider) UpdateSettings(values map[string]int) (tracker.MonitoringSettings, error) {
+	settings := p.Settings()
+	for key, value := range values {
+		if key != "interval_seconds" {
+			return settings, fmt.Errorf("%w: unknown key %q", tracker.ErrInvalidSetting, key)
+		}
+		settings.IntervalSeconds = value
+	}
+	return settings, nil
+}
+
+func (stubProvider) ExportTargets() []config.Target {
+	return []config.Target{{Name: "a", Address: "127.0.0.1", Port: 443, Mention: "@ops"}}
+}
+
 type mutableProvider struct {
+	stubProvider
 	lastUpsert struct {
 		name    string
 		address string
@@ -56,6 +144,13 @@ func (m *mutableProvider) Logs(string, int, int) ([]logstore.Row, bool) {
 	return nil, false
 }
 
+func (m *mutableProvider) ListTargets() ([]logstore.Target, error) {
+	return []logstore.Target{
+		{Name: "a", Address: "127.0.0.1", Port: 443, Enabled: true, UpdatedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)},
+		{Name: "old", Address: "127.0.0.2", Port: 22, UpdatedAt: time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)},
+	}, nil
+}
+
 func (m *mutableProvider) UpsertTarget(name, address string, port int) error {
 	m.lastUpsert.name = name
 	m.lastUpsert.address = address
@@ -94,6 +189,42 @@ func TestStaticHandlerServesIndexWithoutRedirect(t *testing.T) {
 	}
 }
 
+func TestBasePathPrefixesRoutesAndIndex(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080/trackway",
+		BasePath:      "/trackway",
+	}, "test-bot-token", stubProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get("/trackway/")
+	body := rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.Contains(body, `<base href="/trackway/">`) || strings.Contains(body, `="/_astro/`) {
+		t.Fatalf("expected prefixed index, got %d %q", rec.Code, body)
+	}
+	if rec := get("/trackway"); rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "/trackway/" {
+		t.Fatalf("expected redirect to trailing slash, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+	if rec := get("/trackway/api/status"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected prefixed API route, got %d", rec.Code)
+	}
+	if rec := get("/api/status"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected unprefixed API route to be gone, got %d", rec.Code)
+	}
+	if rec := get("/healthz"); rec.Code != http.StatusOK {
+		t.Fatalf("expected root health check, got %d", rec.Code)
+	}
+}
+
 func TestStaticHandlerServesIndexForUnknownPath(t *testing.T) {
 	t.Parallel()
 
@@ -117,6 +248,66 @@ func TestStaticHandlerServesIndexForUnknownPath(t *testing.T) {
 	}
 }
 
+func TestStaticHandlerServesFromStaticDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte("<!doctype html><p>dev build</p>"), 0o644); err != nil {
+		t.Fatalf("write index: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "app.js"), []byte("console.log(1)"), 0o644); err != nil {
+		t.Fatalf("write asset: %v", err)
+	}
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+		StaticDir:     dir,
+	}, "test-bot-token", stubProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	for _, path := range []string{"/", "/some/spa/route"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "dev build") {
+			t.Fatalf("expected dev index for %s, got %d %q", path, rec.Code, rec.Body.String())
+		}
+	}
+
+	// Changes on disk are visible without restarting the server.
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte("<!doctype html><p>edited</p>"), 0o644); err != nil {
+		t.Fatalf("rewrite index: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), "edited") {
+		t.Fatalf("expected edited index, got %q", rec.Body.String())
+	}
+
+	assetReq := httptest.NewRequest(http.MethodGet, "/app.js", nil)
+	assetRec := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(assetRec, assetReq)
+	if assetRec.Body.String() != "console.log(1)" {
+		t.Fatalf("unexpected asset body: %q", assetRec.Body.String())
+	}
+}
+
+func TestNewRejectsMissingStaticDir(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+		StaticDir:     filepath.Join(t.TempDir(), "missing"),
+	}, "test-bot-token", stubProvider{})
+	if err == nil {
+		t.Fatal("expected error for missing static_dir")
+	}
+}
+
 func TestHealthEndpoint(t *testing.T) {
 	t.Parallel()
 
@@ -140,6 +331,90 @@ func TestHealthEndpoint(t *testing.T) {
 	}
 }
 
+type roundProvider struct {
+	stubProvider
+	lastRound time.Time
+}
+
+func (p *roundProvider) LastRoundAt() time.Time {
+	return p.lastRound
+}
+
+func TestHealthEndpointFailsWhenRoundsStall(t *testing.T) {
+	t.Parallel()
+
+	provider := &roundProvider{}
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+	}, "test-bot-token", provider)
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	health := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		return rec
+	}
+
+	if rec := health(); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"checks_run":false`) {
+		t.Fatalf("expected 200 while starting, got %d %s", rec.Code, rec.Body.String())
+	}
+	srv.startedAt = time.Now().Add(-2 * time.Minute)
+	if rec := health(); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without any round after the threshold, got %d %s", rec.Code, rec.Body.String())
+	}
+	provider.lastRound = time.Now().Add(-10 * time.Second)
+	if rec := health(); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"last_round_at"`) || !strings.Contains(rec.Body.String(), `"stale_after_seconds":60`) {
+		t.Fatalf("expected 200 after a recent round, got %d %s", rec.Code, rec.Body.String())
+	}
+	provider.lastRound = time.Now().Add(-61 * time.Second)
+	if rec := health(); rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), `"ok":false`) {
+		t.Fatalf("expected 503 for a stale round, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+type warmingProvider struct {
+	stubProvider
+	ready bool
+}
+
+func (p *warmingProvider) Ready() bool {
+	return p.ready
+}
+
+func TestReadyEndpointWaitsForFirstCycle(t *testing.T) {
+	t.Parallel()
+
+	provider := &warmingProvider{}
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+		BasePath:      "/trackway",
+		IPAllowlist:   []string{"10.0.0.0/8"},
+	}, "test-bot-token", provider)
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "203.0.113.9:4000"
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := get("/readyz"); rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), `"ready":false`) {
+		t.Fatalf("expected 503 before the first cycle, got %d %s", rec.Code, rec.Body.String())
+	}
+	provider.ready = true
+	for _, path := range []string{"/readyz", "/trackway/readyz"} {
+		if rec := get(path); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"ready":true`) {
+			t.Fatalf("%s: expected 200 after the first cycle, got %d %s", path, rec.Code, rec.Body.String())
+		}
+	}
+}
+
 func TestListenAndServeReturnsStartupError(t *testing.T) {
 	t.Parallel()
 
@@ -227,6 +502,56 @@ func TestAuthVerifyRequiresPostToConsumeToken(t *testing.T) {
 	}
 }
 
+func TestSignedLinkSignsInAndOpensPath(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+	}, "test-bot-token", stubProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	if _, err := srv.NewSignedLink("//evil.example/"); err == nil {
+		t.Fatal("expected a link to another host to be refused")
+	}
+	link, err := srv.NewSignedLink("/?track=web%26api")
+	if err != nil {
+		t.Fatalf("new signed link: %v", err)
+	}
+	parsed, err := url.Parse(link)
+	if err != nil || parsed.Path != "/auth/link" {
+		t.Fatalf("unexpected signed link %q: %v", link, err)
+	}
+
+	open := func(rawQuery string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/auth/link?"+rawQuery, nil))
+		return rec
+	}
+	// The link works more than once, for everyone in the chat.
+	for range 2 {
+		rec := open(parsed.RawQuery)
+		if rec.Code != http.StatusFound || rec.Header().Get("Location") != "/?track=web%26api" {
+			t.Fatalf("expected redirect to the target, got %d %q", rec.Code, rec.Header().Get("Location"))
+		}
+		if !strings.Contains(rec.Header().Get("Set-Cookie"), "trackway_dashboard_session=") {
+			t.Fatalf("expected session cookie, got %q", rec.Header().Get("Set-Cookie"))
+		}
+	}
+
+	query := parsed.Query()
+	query.Set("next", "/?track=other")
+	if rec := open(query.Encode()); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected a changed path to be rejected, got %d", rec.Code)
+	}
+	query = parsed.Query()
+	query.Set("exp", "1")
+	if rec := open(query.Encode()); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected an expired link to be rejected, got %d", rec.Code)
+	}
+}
+
 func TestMiniAppAuthEndpoint(t *testing.T) {
 	t.Parallel()
 
@@ -257,6 +582,33 @@ func TestMiniAppAuthEndpoint(t *testing.T) {
 	}
 }
 
+func TestMiniAppAuthEndpointAcceptsAuthorizationHeader(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress:    ":0",
+		PublicURL:        "http://127.0.0.1:8080",
+		MiniAppEnabled:   true,
+		MiniAppMaxAgeSec: 3600,
+	}, "test-bot-token", stubProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	initData := buildSignedInitData("test-bot-token", time.Now().UTC(), 42)
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/telegram-miniapp", nil)
+	req.Header.Set("Authorization", "tma "+initData)
+	rec := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d, body=%s", rec.Code, rec.Body.String())
+	}
+	if setCookie := rec.Header().Get("Set-Cookie"); !strings.Contains(setCookie, "trackway_dashboard_session=") {
+		t.Fatalf("expected session cookie, got: %q", setCookie)
+	}
+}
+
 func TestMiniAppAuthEndpointRejectsUnexpectedUser(t *testing.T) {
 	t.Parallel()
 
@@ -285,6 +637,54 @@ func TestMiniAppAuthEndpointRejectsUnexpectedUser(t *testing.T) {
 	}
 }
 
+func TestAuthEndpointsRateLimitPerClient(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress:         ":0",
+		PublicURL:             "http://127.0.0.1:8080",
+		AuthRateLimit:         3,
+		AuthRateWindowSeconds: 60,
+	}, "test-bot-token", stubProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	verify := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/auth/verify", strings.NewReader("token=bogus"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 3; i++ {
+		if rec := verify("203.0.113.7:4000"); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: expected 401, got %d", i+1, rec.Code)
+		}
+	}
+	rec := verify("203.0.113.7:4001")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after limit, got %d", rec.Code)
+	}
+	if retry, err := strconv.Atoi(rec.Header().Get("Retry-After")); err != nil || retry < 1 || retry > 60 {
+		t.Fatalf("expected Retry-After within window, got %q", rec.Header().Get("Retry-After"))
+	}
+
+	miniReq := httptest.NewRequest(http.MethodPost, "/api/auth/telegram-miniapp", strings.NewReader(`{"init_data":""}`))
+	miniReq.RemoteAddr = "203.0.113.7:4002"
+	miniRec := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(miniRec, miniReq)
+	if miniRec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected mini app auth to share the limit, got %d", miniRec.Code)
+	}
+
+	if rec := verify("198.51.100.4:4000"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected other client to be unaffected, got %d", rec.Code)
+	}
+}
+
 func TestFormatRowLineUsesReadableClientTime(t *testing.T) {
 	t.Parallel()
 
@@ -304,6 +704,84 @@ func TestFormatRowLineUsesReadableClientTime(t *testing.T) {
 	if strings.Contains(got, "T") || strings.Contains(got, "Z") {
 		t.Fatalf("line should not contain RFC3339 markers: %q", got)
 	}
+
+	row.Reason = "DOWN\nforged 09.02.2026 14:47:46  UP\x1b[0m"
+	if got := formatRowLine(row, loc); strings.ContainsAny(got, "\n\x1b") {
+		t.Fatalf("reason control characters leaked into the line: %q", got)
+	}
+}
+
+func TestSnapshotTargetsIncludesLastLatency(t *testing.T) {
+	t.Parallel()
+
+	targets := snapshotTargets(tracker.Snapshot{Targets: []tracker.TargetSnapshot{
+		{Name: "a", Status: "UP", LastLatency: 12500 * time.Microsecond, CertNotAfter: time.Now().Add(10*24*time.Hour + time.Hour)},
+		{Name: "b", Status: "UNKNOWN"},
+	}})
+	if got := targets[0]["last_latency_ms"]; got != 12.5 {
+		t.Fatalf("expected last_latency_ms 12.5, got %v", got)
+	}
+	if got := targets[0]["cert_days_left"]; got != 10 {
+		t.Fatalf("expected cert_days_left 10, got %v", got)
+	}
+	if _, ok := targets[1]["last_latency_ms"]; ok {
+		t.Fatalf("expected no latency before the first successful check, got %v", targets[1])
+	}
+}
+
+type taggedProvider struct {
+	stubProvider
+}
+
+func (taggedProvider) Snapshot() tracker.Snapshot {
+	return tracker.Snapshot{Total: 3, Up: 2, Down: 1, Targets: []tracker.TargetSnapshot{
+		{Name: "api", Status: "UP", Tags: []string{"prod"}},
+		{Name: "db", Status: "DOWN", Tags: []string{"prod", "db"}},
+		{Name: "lab", Status: "UP"},
+	}}
+}
+
+func TestStatusEndpointFiltersByTag(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{ListenAddress: ":0", PublicURL: "http://127.0.0.1:8080"}, "test-bot-token", taggedProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	sessionID, err := srv.auth.CreateSession(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	status := func(query string) map[string]any {
+		req := httptest.NewRequest(http.MethodGet, "/api/status"+query, nil)
+		req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+		var body map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode status: %v", err)
+		}
+		return body
+	}
+
+	body := status("?tag=PROD")
+	targets, _ := body["targets"].([]any)
+	if body["total"] != 2.0 || body["up"] != 1.0 || body["down"] != 1.0 || len(targets) != 2 {
+		t.Fatalf("unexpected prod status: %v", body)
+	}
+	if tags := targets[1].(map[string]any)["tags"]; fmt.Sprint(tags) != "[prod db]" {
+		t.Fatalf("expected tags in the target, got %v", tags)
+	}
+	body = status("?tag=missing")
+	if targets, ok := body["targets"].([]any); !ok || len(targets) != 0 || body["total"] != 0.0 {
+		t.Fatalf("expected an empty target list, got %v", body)
+	}
+	if body := status(""); body["total"] != 3.0 {
+		t.Fatalf("expected all targets without a tag, got %v", body)
+	}
 }
 
 func TestFilterRowsByCutoff(t *testing.T) {
@@ -353,6 +831,39 @@ func TestTargetsAPIRequiresAuthAndSupportsMutations(t *testing.T) {
 	}
 	sessionCookie := &http.Cookie{Name: sessionCookieName, Value: sessionID}
 
+	listReq := httptest.NewRequest(http.MethodGet, "/api/targets", nil)
+	listReq.AddCookie(sessionCookie)
+	listRec := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(listRec, listReq)
+	if listRec.Code != http.StatusOK {
+		t.Fatalf("expected 200 on list, got %d body=%s", listRec.Code, listRec.Body.String())
+	}
+	var listed struct {
+		Targets []struct {
+			Name      string `json:"name"`
+			Address   string `json:"address"`
+			Port      int    `json:"port"`
+			Enabled   bool   `json:"enabled"`
+			UpdatedAt string `json:"updated_at"`
+		} `json:"targets"`
+	}
+	if err := json.Unmarshal(listRec.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(listed.Targets) != 2 || !listed.Targets[0].Enabled || listed.Targets[1].Enabled ||
+		listed.Targets[0].Port != 443 || listed.Targets[0].UpdatedAt != "2026-10-01T12:00:00Z" {
+		t.Fatalf("unexpected target list: %+v", listed.Targets)
+	}
+
+	crossListReq := httptest.NewRequest(http.MethodGet, "/api/targets", nil)
+	crossListReq.Header.Set("Origin", "https://evil.example")
+	crossListReq.AddCookie(sessionCookie)
+	crossListRec := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(crossListRec, crossListReq)
+	if crossListRec.Code != http.StatusForbidden {
+		t.Fatalf("expected cross-origin list to be rejected, got %d", crossListRec.Code)
+	}
+
 	postReq := httptest.NewRequest(http.MethodPost, "/api/targets", strings.NewReader(`{"name":"new-api","address":"100.64.0.10","port":443}`))
 	postReq.Header.Set("Content-Type", "application/json")
 	postReq.Header.Set("Origin", "http://example.com")
@@ -379,6 +890,56 @@ func TestTargetsAPIRequiresAuthAndSupportsMutations(t *testing.T) {
 	}
 }
 
+func TestBasicAuthSatisfiesRequireAuth(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress:     ":0",
+		PublicURL:         "http://127.0.0.1:8080",
+		BasicAuthUser:     "ci",
+		BasicAuthPassword: "s3cret",
+	}, "test-bot-token", &mutableProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	get := func(user, pass string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/targets", nil)
+		req.SetBasicAuth(user, pass)
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Polling with valid credentials never uses up the limit.
+	for range 30 {
+		if code := get("ci", "s3cret").Code; code != http.StatusOK {
+			t.Fatalf("expected 200 with valid credentials, got %d", code)
+		}
+	}
+	rec := get("ci", "wrong")
+	if rec.Code != http.StatusUnauthorized || !strings.HasPrefix(rec.Header().Get("WWW-Authenticate"), "Basic ") {
+		t.Fatalf("expected 401 with a Basic challenge, got %d %q", rec.Code, rec.Header().Get("WWW-Authenticate"))
+	}
+	for range 19 {
+		get("ci", "wrong")
+	}
+	if code := get("ci", "s3cret").Code; code != http.StatusTooManyRequests {
+		t.Fatalf("expected failed basic auth attempts to be rate-limited, got %d", code)
+	}
+
+	plain, err := New(config.Dashboard{ListenAddress: ":0", PublicURL: "http://127.0.0.1:8080"}, "test-bot-token", &mutableProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/targets", nil)
+	req.SetBasicAuth("ci", "s3cret")
+	rec = httptest.NewRecorder()
+	plain.httpServer.Handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected basic auth to be ignored when not configured, got %d", rec.Code)
+	}
+}
+
 func TestTargetsMutationRejectsCrossOrigin(t *testing.T) {
 	t.Parallel()
 
@@ -407,13 +968,250 @@ func TestTargetsMutationRejectsCrossOrigin(t *testing.T) {
 	}
 }
 
-func TestSecurityHeadersAndRequestID(t *testing.T) {
+func TestTargetsUpsertRejectsOversizeAndUnknownFields(t *testing.T) {
 	t.Parallel()
 
+	provider := &mutableProvider{}
 	srv, err := New(config.Dashboard{
 		ListenAddress: ":0",
 		PublicURL:     "http://127.0.0.1:8080",
-	}, "test-bot-token", stubProvider{})
+		MaxBodyBytes:  64,
+	}, "test-bot-token", provider)
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	sessionID, err := srv.auth.CreateSession(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	cases := map[string]string{
+		"oversize":      `{"name":"` + strings.Repeat("a", 128) + `","address":"100.64.0.10","port":443}`,
+		"unknown field": `{"name":"x","address":"100.64.0.10","port":443,"extra":1}`,
+		"trailing data": `{"name":"x","address":"100.64.0.10","port":443}{}`,
+	}
+	for name, body := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/api/targets", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Origin", "http://example.com")
+		req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d body=%s", name, rec.Code, rec.Body.String())
+		}
+	}
+	if provider.lastUpsert.name != "" {
+		t.Fatalf("expected no upsert, got %+v", provider.lastUpsert)
+	}
+}
+
+func TestTargetMuteEndpoints(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+	}, "test-bot-token", stubProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	sessionID, err := srv.auth.CreateSession(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Origin", "http://example.com")
+		req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := post("/api/targets/mute", `{"name":"a","until":"2026-01-02T03:04:05Z"}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"muted_until":"2026-01-02T03:04:05Z"`) {
+		t.Fatalf("unexpected mute response: %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := post("/api/targets/mute", `{"name":"a"}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected default mute to succeed, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := post("/api/targets/mute", `{"name":"a","until":"tomorrow"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for bad until, got %d", rec.Code)
+	}
+	if rec := post("/api/targets/mute", `{"name":"missing"}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown target, got %d", rec.Code)
+	}
+	rec = post("/api/targets/unmute", `{"name":"a"}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"muted_until":null`) {
+		t.Fatalf("unexpected unmute response: %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestSettingsEndpoint(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+	}, "test-bot-token", stubProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	sessionID, err := srv.auth.CreateSession(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	serve := func(method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/settings", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Origin", "http://example.com")
+		req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := serve(http.MethodGet, ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"interval_seconds":5`) {
+		t.Fatalf("unexpected settings response: %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := serve(http.MethodPost, `{"interval_seconds":30}`); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"interval_seconds":30`) {
+		t.Fatalf("unexpected update response: %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := serve(http.MethodPost, `{"bogus":1}`); rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "invalid setting") {
+		t.Fatalf("expected 400 for invalid setting, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := serve(http.MethodPost, `{}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for empty update, got %d", rec.Code)
+	}
+}
+
+func TestAlertsResendRequiresPostAndReportsNothingToSend(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+	}, "test-bot-token", stubProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	sessionID, err := srv.auth.CreateSession(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	serve := func(method string, authed bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/alerts/resend", nil)
+		req.Header.Set("Origin", "http://example.com")
+		if authed {
+			req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+		}
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := serve(http.MethodPost, false); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without session, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodGet, true); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for GET, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodPost, true); rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), "no alert sent yet") {
+		t.Fatalf("expected 404 before any alert, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestSessionsListAndRevokeAll(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+	}, "test-bot-token", stubProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	now := time.Now().UTC()
+	sessionID, err := srv.auth.CreateSession(now)
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if _, err := srv.auth.CreateSession(now); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Origin", "http://example.com")
+		req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(http.MethodGet, "/api/sessions")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"count":2`) {
+		t.Fatalf("unexpected sessions response: %d %s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), sessionID) {
+		t.Fatalf("sessions response must not expose session IDs: %s", rec.Body.String())
+	}
+	if rec := serve(http.MethodGet, "/api/sessions/revoke-all"); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for GET revoke-all, got %d", rec.Code)
+	}
+
+	rec = serve(http.MethodPost, "/api/sessions/revoke-all")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"revoked":2`) {
+		t.Fatalf("unexpected revoke response: %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := serve(http.MethodGet, "/api/sessions"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected caller session to be revoked, got %d", rec.Code)
+	}
+}
+
+func TestIPAllowlistRejectsOtherSourcesExceptHealth(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+		IPAllowlist:   []string{"10.8.0.0/16"},
+	}, "test-bot-token", stubProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	cases := []struct {
+		path   string
+		remote string
+		want   int
+	}{
+		{path: "/api/status", remote: "203.0.113.9:4000", want: http.StatusForbidden},
+		{path: "/", remote: "203.0.113.9:4000", want: http.StatusForbidden},
+		{path: "/healthz", remote: "203.0.113.9:4000", want: http.StatusOK},
+		{path: "/api/status", remote: "10.8.1.2:4000", want: http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		req.RemoteAddr = tc.remote
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Fatalf("%s from %s: expected %d, got %d", tc.path, tc.remote, tc.want, rec.Code)
+		}
+	}
+}
+
+func TestSecurityHeadersAndRequestID(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+	}, "test-bot-token", stubProvider{})
 	if err != nil {
 		t.Fatalf("new server: %v", err)
 	}
@@ -432,3 +1230,280 @@ func TestSecurityHeadersAndRequestID(t *testing.T) {
 		t.Fatal("expected generated request id header")
 	}
 }
+
+type backupProvider struct {
+	stubProvider
+	payload string
+}
+
+func (p backupProvider) Backup(context.Context) (io.ReadCloser, int64, error) {
+	return io.NopCloser(strings.NewReader(p.payload)), int64(len(p.payload)), nil
+}
+
+func TestBackupEndpointStreamsSnapshot(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+	}, "test-bot-token", backupProvider{payload: "SQLite format 3"})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	unauthReq := httptest.NewRequest(http.MethodGet, "/api/backup", nil)
+	unauthRec := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(unauthRec, unauthReq)
+	if unauthRec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for unauth request, got %d", unauthRec.Code)
+	}
+
+	sessionID, err := srv.auth.CreateSession(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/backup", nil)
+	req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+	rec := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Disposition"); !strings.Contains(got, "attachment") {
+		t.Fatalf("expected attachment disposition, got %q", got)
+	}
+	if rec.Body.String() != "SQLite format 3" {
+		t.Fatalf("unexpected backup body: %q", rec.Body.String())
+	}
+}
+
+func TestBackupEndpointNotImplementedForMemoryStore(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+	}, "test-bot-token", stubProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	sessionID, err := srv.auth.CreateSession(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/backup", nil)
+	req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+	rec := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("expected 501, got %d body=%s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestTargetsExportEndpoint(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+	}, "test-bot-token", stubProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	sessionID, err := srv.auth.CreateSession(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	jsonReq := httptest.NewRequest(http.MethodGet, "/api/targets/export", nil)
+	jsonReq.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+	jsonRec := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(jsonRec, jsonReq)
+	if jsonRec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", jsonRec.Code, jsonRec.Body.String())
+	}
+	var exported struct {
+		Targets []config.Target `json:"targets"`
+	}
+	if err := json.Unmarshal(jsonRec.Body.Bytes(), &exported); err != nil {
+		t.Fatalf("decode export: %v", err)
+	}
+	if len(exported.Targets) != 1 || exported.Targets[0].Mention != "@ops" {
+		t.Fatalf("unexpected exported targets: %+v", exported.Targets)
+	}
+
+	yamlReq := httptest.NewRequest(http.MethodGet, "/api/targets/export?format=yaml", nil)
+	yamlReq.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+	yamlRec := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(yamlRec, yamlReq)
+	want := "targets:\n  - name: \"a\"\n    address: \"127.0.0.1\"\n    port: 443\n    mention: \"@ops\"\n"
+	if yamlRec.Body.String() != want {
+		t.Fatalf("unexpected yaml export:\nwant: %q\ngot:  %q", want, yamlRec.Body.String())
+	}
+
+	badReq := httptest.NewRequest(http.MethodGet, "/api/targets/export?format=xml", nil)
+	badReq.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+	badRec := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(badRec, badReq)
+	if badRec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown format, got %d", badRec.Code)
+	}
+}
+
+func TestIncidentsEndpoint(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+	}, "test-bot-token", stubProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	sessionID, err := srv.auth.CreateSession(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	get := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/api/incidents?track=a")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	var payload struct {
+		Incidents []struct {
+			Start           string  `json:"start"`
+			End             *string `json:"end"`
+			DurationSeconds int64   `json:"duration_seconds"`
+			Ongoing         bool    `json:"ongoing"`
+		} `json:"incidents"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(payload.Incidents) != 2 || !payload.Incidents[0].Ongoing || payload.Incidents[0].End != nil || payload.Incidents[0].DurationSeconds < 60 {
+		t.Fatalf("expected the ongoing incident first, got %+v", payload.Incidents)
+	}
+	if got := payload.Incidents[1]; got.End == nil || *got.End != "2026-10-14T12:01:30Z" || got.DurationSeconds != 90 {
+		t.Fatalf("unexpected closed incident: %+v", got)
+	}
+	if rec := get("/api/incidents"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without track, got %d", rec.Code)
+	}
+	if rec := get("/api/incidents?track=missing"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown track, got %d", rec.Code)
+	}
+}
+
+func TestSLAEndpointReportsLatencyPercentiles(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+	}, "test-bot-token", stubProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	sessionID, err := srv.auth.CreateSession(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/uptime?days=30", nil)
+	req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+	rec := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `"p95_ms":2`) || !strings.Contains(body, `"latency":null`) {
+		t.Fatalf("expected percentiles and N/A latency, got %s", body)
+	}
+
+	missingReq := httptest.NewRequest(http.MethodGet, "/api/sla?track=missing", nil)
+	missingReq.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+	missingRec := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(missingRec, missingReq)
+	if missingRec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown track, got %d", missingRec.Code)
+	}
+}
+
+type csvLogsProvider struct {
+	stubProvider
+	days, limit *int
+}
+
+func (p csvLogsProvider) Logs(track string, days int, limit int) ([]logstore.Row, bool) {
+	if track != "db, primary" {
+		return nil, false
+	}
+	*p.days, *p.limit = days, limit
+	return []logstore.Row{
+		{Timestamp: "2026-10-14T12:00:00Z", Status: "UP", Endpoint: "db:5432", Reason: "INIT"},
+		{Timestamp: "2026-10-14T12:01:00Z", Status: "DOWN", Endpoint: "db:5432", Reason: `CHANGE "quoted", with comma`},
+	}, true
+}
+
+func TestLogsCSVEndpoint(t *testing.T) {
+	t.Parallel()
+
+	var days, limit int
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+	}, "test-bot-token", csvLogsProvider{days: &days, limit: &limit})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	sessionID, err := srv.auth.CreateSession(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	get := func(target string, authed bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if authed {
+			req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+		}
+		rec := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := get("/api/logs.csv?track=db%2C+primary", false); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a session, got %d", rec.Code)
+	}
+	if rec := get("/api/logs.csv?track=missing", true); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown track, got %d", rec.Code)
+	}
+	rec := get("/api/logs.csv?track=db%2C+primary&days=1000&limit=0", true)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	if days != 365 || limit != 1 {
+		t.Fatalf("expected clamped days/limit 365/1, got %d/%d", days, limit)
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/csv") {
+		t.Fatalf("unexpected content type %q", got)
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="db__primary-logs.csv"` {
+		t.Fatalf("unexpected content disposition %q", got)
+	}
+	want := "timestamp,status,endpoint,reason\n" +
+		"2026-10-14T12:00:00Z,UP,db:5432,INIT\n" +
+		`2026-10-14T12:01:00Z,DOWN,db:5432,"CHANGE ""quoted"", with comma"` + "\n"
+	if rec.Body.String() != want {
+		t.Fatalf("unexpected csv:\n%s", rec.Body.String())
+	}
+}
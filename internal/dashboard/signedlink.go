@@ -0,0 +1,107 @@
+package dashboard
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// signedLinkTTL is how long a signed link, e.g. from an alert, signs in.
+// Unlike /authme tokens the link can be opened more than once, so everyone
+// in a shared chat can follow it.
+const signedLinkTTL = 24 * time.Hour
+
+// linkSigner signs dashboard paths with a key derived from the bot token, so
+// links stay valid across restarts.
+type linkSigner struct {
+	key []byte
+	ttl time.Duration
+}
+
+func newLinkSigner(botToken string, ttl time.Duration) (*linkSigner, error) {
+	if botToken == "" {
+		key, err := randomToken(32)
+		if err != nil {
+			return nil, err
+		}
+		botToken = key
+	}
+	key := sha256.Sum256([]byte("trackway-dashboard-link:" + botToken))
+	return &linkSigner{key: key[:], ttl: ttl}, nil
+}
+
+func (l *linkSigner) sign(next string, expires int64) string {
+	mac := hmac.New(sha256.New, l.key)
+	mac.Write([]byte(next + "\n" + strconv.FormatInt(expires, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify reports whether sig signs next and expires, and the link has not
+// expired at now.
+func (l *linkSigner) verify(next, expires, sig string, now time.Time) bool {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > unix {
+		return false
+	}
+	return hmac.Equal([]byte(l.sign(next, unix)), []byte(sig))
+}
+
+// isLocalPath accepts only paths on this dashboard, so a signed link cannot
+// redirect elsewhere.
+func isLocalPath(p string) bool {
+	return strings.HasPrefix(p, "/") && !strings.HasPrefix(p, "//") && !strings.HasPrefix(p, "/\\")
+}
+
+// NewSignedLink returns a public link that signs the browser in and opens
+// next, a dashboard path with an optional query such as "/?track=api".
+func (s *Server) NewSignedLink(next string) (string, error) {
+	if s.publicURL == "" {
+		return "", errors.New("dashboard.public_url is empty")
+	}
+	if !isLocalPath(next) {
+		return "", errors.New("signed link target must be a dashboard path")
+	}
+	expires := time.Now().UTC().Add(s.links.ttl).Unix()
+
+	link, err := url.Parse(s.publicURL + "/auth/link")
+	if err != nil {
+		return "", err
+	}
+	q := link.Query()
+	q.Set("next", next)
+	q.Set("exp", strconv.FormatInt(expires, 10))
+	q.Set("sig", s.links.sign(next, expires))
+	link.RawQuery = q.Encode()
+	return link.String(), nil
+}
+
+func (s *Server) handleSignedLink(w http.ResponseWriter, r *http.Request) {
+	if !s.enforceRateLimit(w, r, s.authRateLimiter) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	now := time.Now().UTC()
+	q := r.URL.Query()
+	next := q.Get("next")
+	if !isLocalPath(next) || !s.links.verify(next, q.Get("exp"), q.Get("sig"), now) {
+		http.Error(w, "link is invalid or expired, use /authme", http.StatusUnauthorized)
+		return
+	}
+
+	sessionID, err := s.auth.CreateSession(now)
+	if err != nil {
+		http.Error(w, "failed to create session", http.StatusInternalServerError)
+		return
+	}
+	s.setSessionCookie(w, sessionID)
+	http.Redirect(w, r, s.basePath+next, http.StatusFound)
+}
@@ -0,0 +1,73 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	maxStreamClients    = 16
+	streamKeepAliveTime = 25 * time.Second
+)
+
+// handleStream sends the /api/status payload as a "status" server-sent event
+// on connect and after each check round, until the client disconnects.
+// Comment lines keep idle connections open through proxies. ?tag= filters
+// like it does for /api/status.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.streams.Add(1) > maxStreamClients {
+		s.streams.Add(-1)
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{
+			"error": "too many stream clients",
+		})
+		return
+	}
+	defer s.streams.Add(-1)
+
+	rounds, unsubscribe := s.provider.SubscribeRounds()
+	defer unsubscribe()
+
+	controller := http.NewResponseController(w)
+	// The server write timeout would otherwise end the stream.
+	_ = controller.SetWriteDeadline(time.Time{})
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	tag := strings.TrimSpace(r.URL.Query().Get("tag"))
+	send := func() error {
+		data, err := json.Marshal(s.statusPayload(tag))
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: status\ndata: %s\n\n", data); err != nil {
+			return err
+		}
+		return controller.Flush()
+	}
+	keepAlive := time.NewTicker(streamKeepAliveTime)
+	defer keepAlive.Stop()
+
+	err := send()
+	for err == nil {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-rounds:
+			err = send()
+		case <-keepAlive.C:
+			if _, err = io.WriteString(w, ": keep-alive\n\n"); err == nil {
+				err = controller.Flush()
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package dashboard
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"trackway/internal/config"
+	"trackway/internal/tracker"
+)
+
+type streamProvider struct {
+	stubProvider
+	rounds       chan struct{}
+	up           *atomic.Int32
+	unsubscribed chan struct{}
+}
+
+func (p streamProvider) Snapshot() tracker.Snapshot {
+	return tracker.Snapshot{Total: 1, Up: int(p.up.Load())}
+}
+
+func (p streamProvider) SubscribeRounds() (<-chan struct{}, func()) {
+	return p.rounds, func() { close(p.unsubscribed) }
+}
+
+func TestStreamPushesStatusAfterEachRound(t *testing.T) {
+	t.Parallel()
+
+	provider := streamProvider{rounds: make(chan struct{}, 1), up: new(atomic.Int32), unsubscribed: make(chan struct{})}
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+	}, "test-bot-token", provider)
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	sessionID, err := srv.auth.CreateSession(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	ts := httptest.NewServer(srv.httpServer.Handler)
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/api/stream", nil)
+	req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	nextData := func() string {
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("read stream: %v", err)
+			}
+			if strings.HasPrefix(line, "data: ") {
+				return line
+			}
+		}
+	}
+	if data := nextData(); !strings.Contains(data, `"up":0`) {
+		t.Fatalf("expected the current status on connect, got %q", data)
+	}
+	provider.up.Store(1)
+	provider.rounds <- struct{}{}
+	if data := nextData(); !strings.Contains(data, `"up":1`) {
+		t.Fatalf("expected the status after a round, got %q", data)
+	}
+
+	cancel()
+	select {
+	case <-provider.unsubscribed:
+	case <-time.After(time.Second):
+		t.Fatal("expected the subscription to end on disconnect")
+	}
+}
+
+func TestStreamRejectsClientsOverTheCap(t *testing.T) {
+	t.Parallel()
+
+	srv, err := New(config.Dashboard{
+		ListenAddress: ":0",
+		PublicURL:     "http://127.0.0.1:8080",
+	}, "test-bot-token", stubProvider{})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	sessionID, err := srv.auth.CreateSession(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	srv.streams.Store(maxStreamClients)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/stream", nil)
+	req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+	rec := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 over the cap, got %d", rec.Code)
+	}
+	if got := srv.streams.Load(); got != maxStreamClients {
+		t.Fatalf("expected the rejected client not to be counted, got %d", got)
+	}
+}
@@ -0,0 +1,22 @@
+package logstore
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// WriteCSV writes rows as CSV with a timestamp,status,endpoint,reason header.
+// Fields holding commas, quotes or newlines are quoted.
+func WriteCSV(w io.Writer, rows []Row) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"timestamp", "status", "endpoint", "reason"}); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := out.Write([]string{row.Timestamp, row.Status, row.Endpoint, row.Reason}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
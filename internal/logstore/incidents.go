@@ -0,0 +1,59 @@
+package logstore
+
+import (
+	"sort"
+	"time"
+)
+
+// Incident is one outage: from a DOWN row to the next UP row. End is zero
+// while it is ongoing, and Duration then runs to now.
+type Incident struct {
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+	Ongoing  bool
+}
+
+// Incidents returns the outages of the last days days, newest first. An
+// outage already ongoing when the window opens keeps its real start. Only the
+// status changes are read, so limit caps transitions, not checks; the oldest
+// are dropped first.
+func (s *Store) Incidents(targetName string, days int, limit int) []Incident {
+	if days <= 0 {
+		days = 7
+	}
+	if limit <= 0 {
+		limit = 1000
+	}
+	now := time.Now().UTC()
+	since := now.Add(-time.Duration(days) * 24 * time.Hour)
+	rows := s.backend.statusChangesSince(targetName, since, limit)
+	openStart, _ := s.backend.outageStartBefore(targetName, since)
+	incidents := collapseIncidents(rows, openStart, now)
+	sort.Slice(incidents, func(i, j int) bool { return incidents[i].Start.After(incidents[j].Start) })
+	return incidents
+}
+
+// collapseIncidents pairs each DOWN transition in rows, which must be in
+// ascending time order, with the next UP row. A non-zero openStart is an
+// outage already ongoing before the first row.
+func collapseIncidents(rows []Row, openStart time.Time, now time.Time) []Incident {
+	var incidents []Incident
+	for _, row := range rows {
+		at, err := time.Parse(time.RFC3339, row.Timestamp)
+		if err != nil {
+			continue
+		}
+		switch {
+		case row.Status == "DOWN" && openStart.IsZero():
+			openStart = at
+		case row.Status == "UP" && !openStart.IsZero():
+			incidents = append(incidents, Incident{Start: openStart, End: at, Duration: at.Sub(openStart)})
+			openStart = time.Time{}
+		}
+	}
+	if !openStart.IsZero() {
+		incidents = append(incidents, Incident{Start: openStart, Duration: now.Sub(openStart), Ongoing: true})
+	}
+	return incidents
+}
@@ -0,0 +1,41 @@
+package logstore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIncidentsReadsTransitionsBeyondRowLimit(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewSQLite(SQLiteOptions{Path: filepath.Join(t.TempDir(), "trackway.db")})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	now := time.Now().UTC()
+	start := now.Add(-300 * time.Minute)
+	// Two outages: rows 90-109 and 250-259, far more check rows than the limit.
+	for i := range 300 {
+		down := (i >= 90 && i < 110) || (i >= 250 && i < 260)
+		entry := Entry{Target: "api", Address: "127.0.0.1", Port: 443, Status: !down, Reason: "POLL"}
+		if err := store.backend.append(entry, start.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("append row %d: %v", i, err)
+		}
+	}
+
+	incidents := store.Incidents("api", 1, 100)
+	if len(incidents) != 2 {
+		t.Fatalf("expected 2 incidents, got %+v", incidents)
+	}
+	recent, old := incidents[0], incidents[1]
+	if recent.Ongoing || old.Ongoing {
+		t.Fatalf("recovered outages must not be ongoing: %+v", incidents)
+	}
+	if recent.Duration != 10*time.Minute || old.Duration != 20*time.Minute {
+		t.Fatalf("unexpected durations: recent %s, old %s", recent.Duration, old.Duration)
+	}
+	if want := start.Add(250 * time.Minute); !recent.Start.Equal(want) {
+		t.Fatalf("recent incident start = %s, want %s", recent.Start, want)
+	}
+}
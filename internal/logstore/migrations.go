@@ -0,0 +1,128 @@
+package logstore
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// sqliteExecer is satisfied by both *sql.DB and *sql.Tx.
+type sqliteExecer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+type sqliteMigration struct {
+	version int
+	name    string
+	apply   func(db sqliteExecer) error
+}
+
+// sqliteMigrations are applied in order at startup. Steps must stay
+// idempotent: databases created before versioning start at version 0 and
+// replay every step against tables that may already have the change.
+// Append new steps; never edit or reorder applied ones.
+var sqliteMigrations = []sqliteMigration{
+	{version: 1, name: "base schema", apply: initSQLiteSchema},
+	{version: 2, name: "log latency", apply: func(db sqliteExecer) error {
+		return ensureSQLiteColumn(db, "logs", "latency_ms", "REAL")
+	}},
+	{version: 3, name: "poll row dedupe", apply: func(db sqliteExecer) error {
+		if err := ensureSQLiteColumn(db, "logs", "last_seen", "TEXT"); err != nil {
+			return err
+		}
+		return ensureSQLiteColumn(db, "logs", "repeat_count", "INTEGER NOT NULL DEFAULT 1")
+	}},
+	{version: 4, name: "target thresholds", apply: func(db sqliteExecer) error {
+		if err := ensureSQLiteColumn(db, "targets", "fail_threshold", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+			return err
+		}
+		return ensureSQLiteColumn(db, "targets", "success_threshold", "INTEGER NOT NULL DEFAULT 0")
+	}},
+	{version: 5, name: "settings", apply: func(db sqliteExecer) error {
+		_, err := db.Exec(`CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL,
+			updated_at TEXT NOT NULL
+		)`)
+		return err
+	}},
+	{version: 6, name: "target cert fingerprint", apply: func(db sqliteExecer) error {
+		return ensureSQLiteColumn(db, "targets", "cert_fingerprint", "TEXT NOT NULL DEFAULT ''")
+	}},
+	{version: 7, name: "target mute", apply: func(db sqliteExecer) error {
+		return ensureSQLiteColumn(db, "targets", "muted_until", "TEXT NOT NULL DEFAULT ''")
+	}},
+	{version: 8, name: "log resolved ip", apply: func(db sqliteExecer) error {
+		return ensureSQLiteColumn(db, "logs", "resolved_ip", "TEXT NOT NULL DEFAULT ''")
+	}},
+	{version: 9, name: "target tags", apply: func(db sqliteExecer) error {
+		return ensureSQLiteColumn(db, "targets", "tags", "TEXT NOT NULL DEFAULT ''")
+	}},
+	{version: 10, name: "target ack", apply: func(db sqliteExecer) error {
+		if err := ensureSQLiteColumn(db, "targets", "acked_by", "TEXT NOT NULL DEFAULT ''"); err != nil {
+			return err
+		}
+		return ensureSQLiteColumn(db, "targets", "acked_at", "TEXT NOT NULL DEFAULT ''")
+	}},
+}
+
+// migrateSQLite brings the schema up to the latest version, applying each
+// pending migration in its own transaction together with its version record.
+func migrateSQLite(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TEXT NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("init sqlite schema_version: %w", err)
+	}
+	current, err := sqliteSchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	for _, migration := range sqliteMigrations {
+		if migration.version <= current {
+			continue
+		}
+		if err := applySQLiteMigration(db, migration); err != nil {
+			return fmt.Errorf("sqlite migration %d (%s): %w", migration.version, migration.name, err)
+		}
+	}
+	return nil
+}
+
+func sqliteSchemaVersion(db sqliteExecer) (int, error) {
+	rows, err := db.Query(`SELECT COALESCE(MAX(version), 0) FROM schema_version`)
+	if err != nil {
+		return 0, fmt.Errorf("read sqlite schema version: %w", err)
+	}
+	defer rows.Close()
+	version := 0
+	if rows.Next() {
+		if err := rows.Scan(&version); err != nil {
+			return 0, fmt.Errorf("read sqlite schema version: %w", err)
+		}
+	}
+	return version, rows.Err()
+}
+
+func applySQLiteMigration(db *sql.DB, migration sqliteMigration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := migration.apply(tx); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO schema_version(version, name, applied_at) VALUES(?, ?, ?)`,
+		migration.version,
+		migration.name,
+		time.Now().UTC().Format(time.RFC3339),
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
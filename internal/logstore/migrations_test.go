@@ -0,0 +1,88 @@
+package logstore
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrateSQLiteUpgradesPreVersioningDatabase(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "trackway.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	// The schema as it was before schema_version existed.
+	for _, query := range []string{
+		`CREATE TABLE logs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			ts TEXT NOT NULL,
+			target TEXT NOT NULL,
+			address TEXT NOT NULL,
+			port INTEGER NOT NULL,
+			status TEXT NOT NULL,
+			reason TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_logs_target_ts ON logs(target, ts)`,
+		`CREATE TABLE targets (
+			name TEXT PRIMARY KEY,
+			address TEXT NOT NULL,
+			port INTEGER NOT NULL,
+			enabled INTEGER NOT NULL DEFAULT 1,
+			updated_at TEXT NOT NULL
+		)`,
+		`INSERT INTO logs(ts, target, address, port, status, reason) VALUES('2024-01-01T00:00:00Z', 'api', '10.0.0.1', 443, 'UP', 'INIT')`,
+		`INSERT INTO targets(name, address, port, enabled, updated_at) VALUES('api', '10.0.0.1', 443, 1, '2024-01-01T00:00:00Z')`,
+	} {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatalf("create old schema: %v", err)
+		}
+	}
+
+	// Running the migrations again must be a no-op.
+	for range 2 {
+		if err := migrateSQLite(db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	latest := sqliteMigrations[len(sqliteMigrations)-1].version
+	if version, err := sqliteSchemaVersion(db); err != nil || version != latest {
+		t.Fatalf("expected schema version %d, got %d (%v)", latest, version, err)
+	}
+	var applied int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM schema_version`).Scan(&applied); err != nil || applied != len(sqliteMigrations) {
+		t.Fatalf("expected each migration recorded once, got %d (%v)", applied, err)
+	}
+
+	columns := map[string][]string{
+		"logs":    {"latency_ms", "last_seen", "repeat_count", "resolved_ip"},
+		"targets": {"fail_threshold", "success_threshold", "cert_fingerprint", "muted_until", "tags", "acked_by", "acked_at"},
+	}
+	for table, names := range columns {
+		for _, name := range names {
+			var count int
+			if err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, name).Scan(&count); err != nil || count != 1 {
+				t.Fatalf("expected column %s.%s, got %d (%v)", table, name, count, err)
+			}
+		}
+	}
+	if _, err := db.Exec(`INSERT INTO settings(key, value, updated_at) VALUES('k', 'v', '2024-01-01T00:00:00Z')`); err != nil {
+		t.Fatalf("expected settings table: %v", err)
+	}
+	var repeat int
+	if err := db.QueryRow(`SELECT repeat_count FROM logs WHERE target = 'api'`).Scan(&repeat); err != nil || repeat != 1 {
+		t.Fatalf("expected the old row to survive with defaults, got %d (%v)", repeat, err)
+	}
+
+	// The store opens the migrated file and keeps its targets.
+	store, err := NewSQLite(SQLiteOptions{Path: path})
+	if err != nil {
+		t.Fatalf("open migrated store: %v", err)
+	}
+	targets, err := store.ListTargets()
+	if err != nil || len(targets) != 1 || targets[0].Name != "api" {
+		t.Fatalf("expected the old target to survive, got %+v (%v)", targets, err)
+	}
+}
@@ -1,11 +1,13 @@
 package logstore
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync/atomic"
@@ -23,9 +25,10 @@ const (
 )
 
 type sqliteBackend struct {
-	db            *sql.DB
-	retentionDays int
-	writeCount    atomic.Uint64
+	db             *sql.DB
+	retentionDays  int
+	dedupePollRows bool
+	writeCount     atomic.Uint64
 }
 
 func newSQLiteBackend(options SQLiteOptions) (*sqliteBackend, error) {
@@ -69,14 +72,15 @@ func newSQLiteBackend(options SQLiteOptions) (*sqliteBackend, error) {
 		_ = db.Close()
 		return nil, err
 	}
-	if err := initSQLiteSchema(db); err != nil {
+	if err := migrateSQLite(db); err != nil {
 		_ = db.Close()
 		return nil, err
 	}
 
 	backend := &sqliteBackend{
-		db:            db,
-		retentionDays: retentionDays,
+		db:             db,
+		retentionDays:  retentionDays,
+		dedupePollRows: options.DedupePollRows,
 	}
 	if err := backend.cleanupOldLogs(time.Now().UTC()); err != nil {
 		// cleanup is best effort; keep startup resilient
@@ -102,7 +106,7 @@ func applySQLitePragmas(db *sql.DB, busyTimeoutMS int) error {
 	return nil
 }
 
-func initSQLiteSchema(db *sql.DB) error {
+func initSQLiteSchema(db sqliteExecer) error {
 	schema := []string{
 		`CREATE TABLE IF NOT EXISTS logs (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -114,6 +118,7 @@ func initSQLiteSchema(db *sql.DB) error {
 			reason TEXT NOT NULL
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_logs_target_ts ON logs(target, ts)`,
+		`CREATE INDEX IF NOT EXISTS idx_logs_ts ON logs(ts)`,
 		`CREATE TABLE IF NOT EXISTS targets (
 			name TEXT PRIMARY KEY,
 			address TEXT NOT NULL,
@@ -130,15 +135,49 @@ func initSQLiteSchema(db *sql.DB) error {
 	return nil
 }
 
-func (s *sqliteBackend) append(targetName, address string, port int, status bool, reason string, at time.Time) error {
+// ensureSQLiteColumn adds a column to an existing table when it is missing so
+// databases created by older versions keep loading.
+func ensureSQLiteColumn(db sqliteExecer, table, column, definition string) error {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return fmt.Errorf("inspect sqlite table %s: %w", table, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return fmt.Errorf("inspect sqlite table %s: %w", table, err)
+		}
+		if strings.EqualFold(name, column) {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("inspect sqlite table %s: %w", table, err)
+	}
+	if _, err := db.Exec(`ALTER TABLE ` + table + ` ADD COLUMN ` + column + ` ` + definition); err != nil {
+		return fmt.Errorf("add sqlite column %s.%s: %w", table, column, err)
+	}
+	return nil
+}
+
+func (s *sqliteBackend) append(entry Entry, at time.Time) error {
+	if s.dedupePollRows && strings.HasPrefix(strings.ToUpper(entry.Reason), "POLL") {
+		merged, err := s.mergePollRow(entry, at)
+		if err != nil || merged {
+			return err
+		}
+	}
 	_, err := s.db.Exec(
-		`INSERT INTO logs (ts, target, address, port, status, reason) VALUES (?, ?, ?, ?, ?, ?)`,
+		`INSERT INTO logs (ts, target, address, port, resolved_ip, status, reason, latency_ms) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
 		at.UTC().Format(time.RFC3339Nano),
-		targetName,
-		address,
-		port,
-		statusText(status),
-		strings.ToUpper(reason),
+		entry.Target,
+		entry.Address,
+		entry.Port,
+		entry.IP,
+		statusText(entry.Status),
+		strings.ToUpper(entry.Reason),
+		latencyMS(entry.Latency),
 	)
 	if err != nil {
 		return err
@@ -150,15 +189,53 @@ func (s *sqliteBackend) append(targetName, address string, port int, status bool
 	return nil
 }
 
+// mergePollRow folds the entry into the target's latest row when that row has
+// the same status, reason, endpoint and resolved IP. It reports whether a row
+// was updated.
+func (s *sqliteBackend) mergePollRow(entry Entry, at time.Time) (bool, error) {
+	var (
+		id      int64
+		status  string
+		reason  string
+		address string
+		port    int
+		ip      string
+	)
+	err := s.db.QueryRow(
+		`SELECT id, status, reason, address, port, resolved_ip FROM logs WHERE target = ? ORDER BY id DESC LIMIT 1`,
+		entry.Target,
+	).Scan(&id, &status, &reason, &address, &port, &ip)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if status != statusText(entry.Status) || reason != strings.ToUpper(entry.Reason) || address != entry.Address || port != entry.Port || ip != entry.IP {
+		return false, nil
+	}
+	_, err = s.db.Exec(
+		`UPDATE logs SET last_seen = ?, repeat_count = repeat_count + 1, latency_ms = COALESCE(?, latency_ms) WHERE id = ?`,
+		at.UTC().Format(time.RFC3339Nano),
+		latencyMS(entry.Latency),
+		id,
+	)
+	return err == nil, err
+}
+
 func (s *sqliteBackend) readSince(targetName string, since time.Time, limit int) []Row {
+	// Deduplicated rows can start before the window and still cover part of it.
+	// The newest rows are kept when the window holds more than limit, as the
+	// memory backend does.
 	rows, err := s.db.Query(
-		`SELECT ts, status, address, port, reason
+		`SELECT ts, status, address, port, resolved_ip, reason, latency_ms, last_seen, repeat_count
 		FROM logs
-		WHERE target = ? AND ts >= ?
-		ORDER BY ts ASC
+		WHERE target = ? AND (ts >= ? OR last_seen >= ?)
+		ORDER BY ts DESC
 		LIMIT ?`,
 		targetName,
 		since.UTC().Format(time.RFC3339Nano),
+		since.UTC().Format(time.RFC3339Nano),
 		limit,
 	)
 	if err != nil {
@@ -169,31 +246,236 @@ func (s *sqliteBackend) readSince(targetName string, since time.Time, limit int)
 	result := make([]Row, 0, limit)
 	for rows.Next() {
 		var (
+			ts       string
+			status   string
+			address  string
+			port     int
+			ip       string
+			reason   string
+			latency  sql.NullFloat64
+			lastSeen sql.NullString
+			count    int
+		)
+		if err := rows.Scan(&ts, &status, &address, &port, &ip, &reason, &latency, &lastSeen, &count); err != nil {
+			continue
+		}
+		row := Row{
+			Timestamp: ts,
+			Status:    strings.ToUpper(status),
+			Endpoint:  endpointText(address, port, ip),
+			Reason:    strings.ToUpper(reason),
+		}
+		if latency.Valid {
+			value := latency.Float64
+			row.LatencyMS = &value
+		}
+		if lastSeen.Valid && count > 1 {
+			row.LastSeen = lastSeen.String
+			row.Count = count
+		}
+		result = append(result, row)
+	}
+	slices.Reverse(result)
+	return result
+}
+
+func (s *sqliteBackend) statusChangesSince(targetName string, since time.Time, limit int) []Row {
+	rows, err := s.db.Query(
+		`SELECT ts, status FROM (
+			SELECT ts, status, LAG(status) OVER (ORDER BY ts) AS previous
+			FROM logs
+			WHERE target = ? AND (ts >= ? OR last_seen >= ?)
+		)
+		WHERE previous IS NULL OR previous <> status
+		ORDER BY ts DESC
+		LIMIT ?`,
+		targetName,
+		since.UTC().Format(time.RFC3339Nano),
+		since.UTC().Format(time.RFC3339Nano),
+		limit,
+	)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	result := make([]Row, 0)
+	for rows.Next() {
+		var ts, status string
+		if err := rows.Scan(&ts, &status); err != nil {
+			continue
+		}
+		result = append(result, Row{Timestamp: ts, Status: strings.ToUpper(status)})
+	}
+	slices.Reverse(result)
+	return result
+}
+
+func (s *sqliteBackend) checkStats(targetName string, from, to time.Time) CheckStats {
+	var (
+		stats CheckStats
+		first sql.NullString
+	)
+	err := s.db.QueryRow(
+		`SELECT
+			COALESCE(SUM(MAX(repeat_count, 1)), 0),
+			COALESCE(SUM(CASE WHEN UPPER(status) = 'UP' THEN MAX(repeat_count, 1) ELSE 0 END), 0),
+			COUNT(latency_ms),
+			MIN(ts)
+		FROM logs
+		WHERE target = ? AND (ts >= ? OR last_seen >= ?) AND ts < ?`,
+		targetName,
+		from.UTC().Format(time.RFC3339Nano),
+		from.UTC().Format(time.RFC3339Nano),
+		to.UTC().Format(time.RFC3339Nano),
+	).Scan(&stats.Checks, &stats.UpChecks, &stats.LatencySamples, &first)
+	if err != nil {
+		return CheckStats{}
+	}
+	if first.Valid {
+		if ts, err := time.Parse(time.RFC3339Nano, first.String); err == nil {
+			stats.First = ts
+		}
+	}
+	return stats
+}
+
+func (s *sqliteBackend) bucketRows(targetName string, from time.Time, width time.Duration) []Row {
+	rows, err := s.db.Query(
+		`SELECT MIN(ts), MAX(COALESCE(last_seen, ts)), UPPER(status)
+		FROM logs
+		WHERE target = ? AND (ts >= ? OR last_seen >= ?)
+		GROUP BY CAST((unixepoch(ts, 'subsec') * 1000 - ?) / ? AS INTEGER), UPPER(status)
+		ORDER BY MIN(ts) ASC`,
+		targetName,
+		from.UTC().Format(time.RFC3339Nano),
+		from.UTC().Format(time.RFC3339Nano),
+		from.UnixMilli(),
+		max(width.Milliseconds(), 1),
+	)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	result := make([]Row, 0)
+	for rows.Next() {
+		var row Row
+		if err := rows.Scan(&row.Timestamp, &row.LastSeen, &row.Status); err != nil {
+			continue
+		}
+		if row.LastSeen == row.Timestamp {
+			row.LastSeen = ""
+		}
+		result = append(result, row)
+	}
+	return result
+}
+
+func (s *sqliteBackend) latencyAtRank(targetName string, from, to time.Time, rank int) float64 {
+	var value float64
+	err := s.db.QueryRow(
+		`SELECT latency_ms FROM logs
+		WHERE target = ? AND (ts >= ? OR last_seen >= ?) AND ts < ? AND latency_ms IS NOT NULL
+		ORDER BY latency_ms ASC
+		LIMIT 1 OFFSET ?`,
+		targetName,
+		from.UTC().Format(time.RFC3339Nano),
+		from.UTC().Format(time.RFC3339Nano),
+		to.UTC().Format(time.RFC3339Nano),
+		rank-1,
+	).Scan(&value)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+func (s *sqliteBackend) outageStartBefore(targetName string, at time.Time) (time.Time, bool) {
+	before := at.UTC().Format(time.RFC3339Nano)
+	var status string
+	err := s.db.QueryRow(
+		`SELECT status FROM logs WHERE target = ? AND ts < ? ORDER BY ts DESC LIMIT 1`,
+		targetName,
+		before,
+	).Scan(&status)
+	if err != nil || !strings.EqualFold(status, "DOWN") {
+		return time.Time{}, false
+	}
+	var start sql.NullString
+	err = s.db.QueryRow(
+		`SELECT MIN(ts) FROM logs
+		WHERE target = ? AND ts < ? AND ts > COALESCE(
+			(SELECT MAX(ts) FROM logs WHERE target = ? AND ts < ? AND UPPER(status) = 'UP'), '')`,
+		targetName,
+		before,
+		targetName,
+		before,
+	).Scan(&start)
+	if err != nil || !start.Valid {
+		return time.Time{}, false
+	}
+	ts, err := time.Parse(time.RFC3339Nano, start.String)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return ts, true
+}
+
+func (s *sqliteBackend) recentChanges(limit int) []TargetRow {
+	rows, err := s.db.Query(
+		`SELECT target, ts, status, address, port, resolved_ip, reason, latency_ms
+		FROM logs
+		WHERE reason IN ('INIT', 'CHANGE') OR reason LIKE 'INIT %' OR reason LIKE 'CHANGE %'
+		ORDER BY ts DESC
+		LIMIT ?`,
+		limit,
+	)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	result := make([]TargetRow, 0, limit)
+	for rows.Next() {
+		var (
+			target  string
 			ts      string
 			status  string
 			address string
 			port    int
+			ip      string
 			reason  string
+			latency sql.NullFloat64
 		)
-		if err := rows.Scan(&ts, &status, &address, &port, &reason); err != nil {
+		if err := rows.Scan(&target, &ts, &status, &address, &port, &ip, &reason, &latency); err != nil {
 			continue
 		}
-		result = append(result, Row{
-			Timestamp: ts,
-			Status:    strings.ToUpper(status),
-			Endpoint:  fmt.Sprintf("%s:%d", address, port),
-			Reason:    strings.ToUpper(reason),
-		})
+		row := TargetRow{
+			Target: target,
+			Row: Row{
+				Timestamp: ts,
+				Status:    strings.ToUpper(status),
+				Endpoint:  endpointText(address, port, ip),
+				Reason:    strings.ToUpper(reason),
+			},
+		}
+		if latency.Valid {
+			value := latency.Float64
+			row.LatencyMS = &value
+		}
+		result = append(result, row)
 	}
 	return result
 }
 
-func (s *sqliteBackend) listTargets() ([]Target, error) {
+func (s *sqliteBackend) listTargets(includeDisabled bool) ([]Target, error) {
 	rows, err := s.db.Query(
-		`SELECT name, address, port, enabled, updated_at
+		`SELECT name, address, port, enabled, updated_at, fail_threshold, success_threshold, cert_fingerprint, muted_until, tags, acked_by, acked_at
 		FROM targets
-		WHERE enabled = 1
+		WHERE enabled = 1 OR ?
 		ORDER BY name ASC`,
+		includeDisabled,
 	)
 	if err != nil {
 		return nil, err
@@ -203,13 +485,29 @@ func (s *sqliteBackend) listTargets() ([]Target, error) {
 	result := make([]Target, 0, 64)
 	for rows.Next() {
 		var (
-			target    Target
-			enabled   int
-			updatedAt string
+			target     Target
+			enabled    int
+			updatedAt  string
+			mutedUntil string
+			tags       string
+			ackedAt    string
 		)
-		if err := rows.Scan(&target.Name, &target.Address, &target.Port, &enabled, &updatedAt); err != nil {
+		if err := rows.Scan(&target.Name, &target.Address, &target.Port, &enabled, &updatedAt, &target.FailThreshold, &target.SuccessThreshold, &target.CertFingerprint, &mutedUntil, &tags, &target.AckedBy, &ackedAt); err != nil {
 			return nil, err
 		}
+		if tags != "" {
+			target.Tags = strings.Split(tags, ",")
+		}
+		if mutedUntil != "" {
+			if parsed, err := time.Parse(time.RFC3339Nano, mutedUntil); err == nil {
+				target.MutedUntil = parsed.UTC()
+			}
+		}
+		if ackedAt != "" {
+			if parsed, err := time.Parse(time.RFC3339Nano, ackedAt); err == nil {
+				target.AckedAt = parsed.UTC()
+			}
+		}
 		target.Enabled = enabled == 1
 		parsed, err := time.Parse(time.RFC3339Nano, updatedAt)
 		if err == nil {
@@ -250,11 +548,144 @@ func (s *sqliteBackend) deleteTarget(name string) error {
 	return err
 }
 
+func (s *sqliteBackend) setTargetThresholds(name string, fail, success int) error {
+	result, err := s.db.Exec(
+		`UPDATE targets SET fail_threshold = ?, success_threshold = ?, updated_at = ? WHERE name = ? AND enabled = 1`,
+		fail,
+		success,
+		time.Now().UTC().Format(time.RFC3339Nano),
+		name,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrTargetNotFound
+	}
+	return nil
+}
+
+func (s *sqliteBackend) setTargetCertFingerprint(name, fingerprint string) error {
+	result, err := s.db.Exec(
+		`UPDATE targets SET cert_fingerprint = ? WHERE name = ? AND enabled = 1`,
+		fingerprint,
+		name,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrTargetNotFound
+	}
+	return nil
+}
+
+func (s *sqliteBackend) setTargetMute(name string, until time.Time) error {
+	value := ""
+	if !until.IsZero() {
+		value = until.UTC().Format(time.RFC3339Nano)
+	}
+	result, err := s.db.Exec(
+		`UPDATE targets SET muted_until = ? WHERE name = ? AND enabled = 1`,
+		value,
+		name,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrTargetNotFound
+	}
+	return nil
+}
+
+// setTargetTags stores tags comma-separated; config validation keeps commas
+// out of tags.
+func (s *sqliteBackend) setTargetTags(name string, tags []string) error {
+	result, err := s.db.Exec(
+		`UPDATE targets SET tags = ? WHERE name = ? AND enabled = 1`,
+		strings.Join(tags, ","),
+		name,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrTargetNotFound
+	}
+	return nil
+}
+
+func (s *sqliteBackend) setTargetAck(name, by string, at time.Time) error {
+	value := ""
+	if !at.IsZero() {
+		value = at.UTC().Format(time.RFC3339Nano)
+	}
+	result, err := s.db.Exec(
+		`UPDATE targets SET acked_by = ?, acked_at = ? WHERE name = ? AND enabled = 1`,
+		by,
+		value,
+		name,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrTargetNotFound
+	}
+	return nil
+}
+
+func (s *sqliteBackend) settings() (map[string]string, error) {
+	rows, err := s.db.Query(`SELECT key, value FROM settings`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		out[key] = value
+	}
+	return out, rows.Err()
+}
+
+func (s *sqliteBackend) setSettings(values map[string]string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	for key, value := range values {
+		if _, err := tx.Exec(
+			`INSERT INTO settings(key, value, updated_at) VALUES(?, ?, ?)
+			ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+			key,
+			value,
+			now,
+		); err != nil {
+			return fmt.Errorf("store setting %s: %w", key, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *sqliteBackend) backup(ctx context.Context, dstPath string) error {
+	if _, err := s.db.ExecContext(ctx, `VACUUM INTO ?`, dstPath); err != nil {
+		return fmt.Errorf("sqlite backup: %w", err)
+	}
+	return nil
+}
+
 func (s *sqliteBackend) cleanupOldLogs(now time.Time) error {
 	if s.retentionDays <= 0 {
 		return nil
 	}
 	cutoff := now.UTC().Add(-time.Duration(s.retentionDays) * 24 * time.Hour).Format(time.RFC3339Nano)
-	_, err := s.db.Exec(`DELETE FROM logs WHERE ts < ?`, cutoff)
+	_, err := s.db.Exec(`DELETE FROM logs WHERE COALESCE(last_seen, ts) < ?`, cutoff)
 	return err
 }
@@ -0,0 +1,41 @@
+package logstore
+
+import (
+	"math"
+	"time"
+)
+
+// CheckStats summarizes the checks of a target logged in a window. A
+// deduplicated row counts every check it stands for, and belongs to the
+// window when it started in it or was still seen in it.
+type CheckStats struct {
+	Checks   int
+	UpChecks int
+	// LatencySamples counts the rows that carry a latency.
+	LatencySamples int
+	// First is when the earliest row of the window was logged; zero when the
+	// window has no rows.
+	First time.Time
+}
+
+// CheckStats aggregates the rows of targetName from from up to to in the
+// backend, so the whole window counts however many rows it holds.
+func (s *Store) CheckStats(targetName string, from, to time.Time) CheckStats {
+	return s.backend.checkStats(targetName, from, to)
+}
+
+// LatencyPercentiles returns the nearest-rank percentiles of the latency
+// rows of targetName from from up to to, in the order asked for. It returns
+// nil when no row of the window carries a latency.
+func (s *Store) LatencyPercentiles(targetName string, from, to time.Time, percents ...float64) []float64 {
+	samples := s.backend.checkStats(targetName, from, to).LatencySamples
+	if samples == 0 {
+		return nil
+	}
+	values := make([]float64, 0, len(percents))
+	for _, p := range percents {
+		rank := int(math.Ceil(p / 100 * float64(samples)))
+		values = append(values, s.backend.latencyAtRank(targetName, from, to, min(max(rank, 1), samples)))
+	}
+	return values
+}
@@ -0,0 +1,94 @@
+package logstore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckStatsCountsDeduplicatedRowsAcrossTheWindow(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewSQLite(SQLiteOptions{Path: filepath.Join(t.TempDir(), "trackway.db"), DedupePollRows: true})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	now := time.Now().UTC()
+	start := now.Add(-2 * time.Hour)
+	for i := range 9 {
+		entry := Entry{Target: "api", Address: "10.0.0.1", Port: 443, Status: true, Reason: "POLL", Latency: time.Duration(i+1) * time.Millisecond}
+		if err := store.backend.append(entry, start.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	down := Entry{Target: "api", Address: "10.0.0.1", Port: 443, Status: false, Reason: "CHANGE"}
+	if err := store.backend.append(down, start.Add(time.Minute)); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	stats := store.CheckStats("api", now.Add(-24*time.Hour), now)
+	if stats.Checks != 10 || stats.UpChecks != 9 || !stats.First.Equal(start) {
+		t.Fatalf("expected folded rows to count every check, got %+v", stats)
+	}
+	if empty := store.CheckStats("api", now.Add(-time.Hour), now); empty.Checks != 0 || !empty.First.IsZero() {
+		t.Fatalf("expected an empty window, got %+v", empty)
+	}
+}
+
+func TestLatencyPercentilesUseNearestRank(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewSQLite(SQLiteOptions{Path: filepath.Join(t.TempDir(), "trackway.db")})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	now := time.Now().UTC()
+	for i := 100; i >= 1; i-- {
+		entry := Entry{Target: "api", Address: "10.0.0.1", Port: 443, Status: true, Reason: "POLL", Latency: time.Duration(i) * time.Millisecond}
+		if err := store.backend.append(entry, now.Add(-time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	values := store.LatencyPercentiles("api", now.Add(-24*time.Hour), now, 50, 95, 99)
+	if len(values) != 3 || values[0] != 50 || values[1] != 95 || values[2] != 99 {
+		t.Fatalf("unexpected percentiles %v", values)
+	}
+	if values := store.LatencyPercentiles("missing", now.Add(-24*time.Hour), now, 50); values != nil {
+		t.Fatalf("expected nil without samples, got %v", values)
+	}
+}
+
+func TestBucketRowsCondensesEachSlotPerStatus(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewSQLite(SQLiteOptions{Path: filepath.Join(t.TempDir(), "trackway.db")})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	from := time.Now().UTC().Add(-10 * time.Hour)
+	for i := range 600 {
+		entry := Entry{Target: "api", Address: "10.0.0.1", Port: 443, Status: i < 125 || i >= 135, Reason: "POLL"}
+		if err := store.backend.append(entry, from.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	rows := store.BucketRows("api", from, time.Hour)
+	if len(rows) != 11 {
+		t.Fatalf("expected 10 UP slots and 1 DOWN slot, got %d rows", len(rows))
+	}
+	at := func(minute int) string { return from.Add(time.Duration(minute) * time.Minute).Format(time.RFC3339Nano) }
+	if rows[0].Status != "UP" || rows[0].Timestamp != at(0) || rows[0].LastSeen != at(59) {
+		t.Fatalf("unexpected first slot %+v", rows[0])
+	}
+	var down []Row
+	for _, row := range rows {
+		if row.Status == "DOWN" {
+			down = append(down, row)
+		}
+	}
+	if len(down) != 1 || down[0].Timestamp != at(125) || down[0].LastSeen != at(134) {
+		t.Fatalf("unexpected DOWN slot %+v", down)
+	}
+}
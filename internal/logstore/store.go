@@ -1,19 +1,35 @@
 package logstore
 
 import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"trackway/internal/util"
 )
 
+var ErrNotSupported = errors.New("operation is not supported by storage backend")
+
+var ErrTargetNotFound = errors.New("target not found")
+
 type SQLiteOptions struct {
 	Path          string
 	RetentionDays int
 	BusyTimeoutMS int
 	MaxOpenConns  int
 	MaxIdleConns  int
+	// DedupePollRows folds a POLL row identical to the target's latest row into
+	// that row, bumping its last_seen and count instead of inserting.
+	DedupePollRows bool
 }
 
 type Store struct {
@@ -26,21 +42,100 @@ type Target struct {
 	Port      int       `json:"port"`
 	Enabled   bool      `json:"enabled"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// FailThreshold and SuccessThreshold are the consecutive failed/successful
+	// checks needed to flip the state; 0 flips on the first one.
+	FailThreshold    int `json:"fail_threshold,omitempty"`
+	SuccessThreshold int `json:"success_threshold,omitempty"`
+	// CertFingerprint is the last seen TLS leaf certificate SHA-256 of a
+	// target with cert watching enabled.
+	CertFingerprint string `json:"cert_fingerprint,omitempty"`
+	// MutedUntil is when a /mute of the target expires; zero when unmuted.
+	MutedUntil time.Time `json:"muted_until,omitzero"`
+	// Tags group targets for filtered status views.
+	Tags []string `json:"tags,omitempty"`
+	// AckedBy and AckedAt record who acknowledged the ongoing outage of the
+	// target, and when; both are empty when it is not acknowledged.
+	AckedBy string    `json:"acked_by,omitempty"`
+	AckedAt time.Time `json:"acked_at,omitzero"`
 }
 
+// Row is a stored check result. A deduplicated row stands for Count identical
+// checks between Timestamp and LastSeen; both are empty for single rows.
 type Row struct {
-	Timestamp string `json:"timestamp"`
-	Status    string `json:"status"`
-	Endpoint  string `json:"endpoint"`
-	Reason    string `json:"reason"`
+	Timestamp string   `json:"timestamp"`
+	Status    string   `json:"status"`
+	Endpoint  string   `json:"endpoint"`
+	Reason    string   `json:"reason"`
+	LatencyMS *float64 `json:"latency_ms,omitempty"`
+	LastSeen  string   `json:"last_seen,omitempty"`
+	Count     int      `json:"count,omitempty"`
+}
+
+// Checks returns how many checks the row represents.
+func (r Row) Checks() int {
+	if r.Count > 1 {
+		return r.Count
+	}
+	return 1
+}
+
+// TargetRow is a Row tagged with the target it belongs to, for queries that
+// span all targets.
+type TargetRow struct {
+	Target string `json:"target"`
+	Row
+}
+
+// Entry is a single check result. Latency is zero when it was not measured.
+// IP is the address a host name resolved to for the check, if any.
+type Entry struct {
+	Target  string
+	Address string
+	Port    int
+	IP      string
+	Status  bool
+	Reason  string
+	Latency time.Duration
+}
+
+// endpointText formats a row endpoint as address:port, followed by the
+// resolved IP when the address is a host name.
+func endpointText(address string, port int, ip string) string {
+	endpoint := address + ":" + strconv.Itoa(port)
+	if ip != "" && ip != address {
+		endpoint += " (" + ip + ")"
+	}
+	return endpoint
 }
 
 type backend interface {
-	append(targetName, address string, port int, status bool, reason string, at time.Time) error
+	append(entry Entry, at time.Time) error
 	readSince(targetName string, since time.Time, limit int) []Row
-	listTargets() ([]Target, error)
+	// statusChangesSince returns the rows whose status differs from the row
+	// before them, oldest first; the first row of the window always counts.
+	// Only Timestamp and Status are set. When there are more than limit, the
+	// newest limit are kept.
+	statusChangesSince(targetName string, since time.Time, limit int) []Row
+	checkStats(targetName string, from, to time.Time) CheckStats
+	bucketRows(targetName string, from time.Time, width time.Duration) []Row
+	// latencyAtRank returns the rank-th smallest latency in the window,
+	// counting from 1.
+	latencyAtRank(targetName string, from, to time.Time, rank int) float64
+	// outageStartBefore returns when the outage ongoing just before at
+	// started, if the target was DOWN then.
+	outageStartBefore(targetName string, at time.Time) (time.Time, bool)
+	recentChanges(limit int) []TargetRow
+	listTargets(includeDisabled bool) ([]Target, error)
 	upsertTarget(target Target) error
 	deleteTarget(name string) error
+	setTargetThresholds(name string, fail, success int) error
+	setTargetCertFingerprint(name, fingerprint string) error
+	setTargetMute(name string, until time.Time) error
+	setTargetTags(name string, tags []string) error
+	setTargetAck(name, by string, at time.Time) error
+	settings() (map[string]string, error)
+	setSettings(values map[string]string) error
+	backup(ctx context.Context, dstPath string) error
 }
 
 func New(_ string) (*Store, error) {
@@ -53,6 +148,7 @@ func NewMemory() (*Store, error) {
 		backend: &memoryBackend{
 			rowsByTrack: make(map[string][]Row),
 			targets:     make(map[string]Target),
+			settingsKV:  make(map[string]string),
 		},
 	}, nil
 }
@@ -66,7 +162,18 @@ func NewSQLite(options SQLiteOptions) (*Store, error) {
 }
 
 func (s *Store) Append(targetName, address string, port int, status bool, reason string) error {
-	return s.backend.append(targetName, address, port, status, reason, time.Now().UTC())
+	return s.AppendEntry(Entry{
+		Target:  targetName,
+		Address: address,
+		Port:    port,
+		Status:  status,
+		Reason:  reason,
+	})
+}
+
+func (s *Store) AppendEntry(entry Entry) error {
+	entry.Reason = util.SanitizeReason(entry.Reason)
+	return s.backend.append(entry, time.Now().UTC())
 }
 
 func (s *Store) ReadLastDays(targetName string, days int, limit int) []Row {
@@ -91,8 +198,33 @@ func (s *Store) ReadLastHours(targetName string, hours int, limit int) []Row {
 	return s.backend.readSince(targetName, cutoff, limit)
 }
 
+// BucketRows condenses the rows of targetName since from into slots of width
+// counted from from. Each slot yields at most one row per status, spanning
+// from the first to the last check of that status in the slot, oldest
+// first. A graph bucketed on the same slots draws the same as from every row.
+func (s *Store) BucketRows(targetName string, from time.Time, width time.Duration) []Row {
+	if width <= 0 {
+		width = time.Minute
+	}
+	return s.backend.bucketRows(targetName, from, width)
+}
+
+// RecentChanges returns the latest INIT/CHANGE rows across all targets, newest
+// first.
+func (s *Store) RecentChanges(limit int) []TargetRow {
+	if limit <= 0 {
+		limit = 20
+	}
+	return s.backend.recentChanges(limit)
+}
+
 func (s *Store) ListTargets() ([]Target, error) {
-	return s.backend.listTargets()
+	return s.backend.listTargets(false)
+}
+
+// ListAllTargets returns the stored targets, disabled ones included.
+func (s *Store) ListAllTargets() ([]Target, error) {
+	return s.backend.listTargets(true)
 }
 
 func (s *Store) UpsertTarget(name, address string, port int) error {
@@ -105,27 +237,114 @@ func (s *Store) UpsertTarget(name, address string, port int) error {
 	})
 }
 
+// SetTargetThresholds stores the state-change thresholds of an enabled target.
+// Upserts from the dashboard keep them.
+func (s *Store) SetTargetThresholds(name string, fail, success int) error {
+	return s.backend.setTargetThresholds(strings.TrimSpace(name), fail, success)
+}
+
+// SetTargetCertFingerprint stores the last seen certificate fingerprint of an
+// enabled target.
+func (s *Store) SetTargetCertFingerprint(name, fingerprint string) error {
+	return s.backend.setTargetCertFingerprint(strings.TrimSpace(name), fingerprint)
+}
+
+// SetTargetMute stores when the mute of an enabled target expires; zero
+// clears it.
+func (s *Store) SetTargetMute(name string, until time.Time) error {
+	return s.backend.setTargetMute(strings.TrimSpace(name), until)
+}
+
+// SetTargetTags stores the tags of an enabled target; upserts keep them.
+func (s *Store) SetTargetTags(name string, tags []string) error {
+	return s.backend.setTargetTags(strings.TrimSpace(name), tags)
+}
+
+// SetTargetAck stores who acknowledged the outage of an enabled target and
+// when; an empty by clears it.
+func (s *Store) SetTargetAck(name, by string, at time.Time) error {
+	if by == "" {
+		at = time.Time{}
+	}
+	return s.backend.setTargetAck(strings.TrimSpace(name), by, at)
+}
+
+// Settings returns the stored runtime settings by key.
+func (s *Store) Settings() (map[string]string, error) {
+	return s.backend.settings()
+}
+
+// SetSetting stores one runtime setting, replacing any previous value.
+func (s *Store) SetSetting(key, value string) error {
+	return s.backend.setSettings(map[string]string{key: value})
+}
+
+// SetSettings stores several runtime settings at once: either all of them
+// replace their previous values or none does.
+func (s *Store) SetSettings(values map[string]string) error {
+	return s.backend.setSettings(values)
+}
+
 func (s *Store) DeleteTarget(name string) error {
 	return s.backend.deleteTarget(strings.TrimSpace(name))
 }
 
+// Backup writes a consistent copy of the database into a temporary file and
+// returns a reader over it. The file is removed when the reader is closed.
+func (s *Store) Backup(ctx context.Context) (io.ReadCloser, int64, error) {
+	dir, err := os.MkdirTemp("", "trackway-backup-*")
+	if err != nil {
+		return nil, 0, err
+	}
+	dstPath := filepath.Join(dir, "trackway.db")
+	if err := s.backend.backup(ctx, dstPath); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, 0, err
+	}
+	file, err := os.Open(dstPath)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, 0, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		_ = os.RemoveAll(dir)
+		return nil, 0, err
+	}
+	return &tempFile{File: file, dir: dir}, info.Size(), nil
+}
+
+type tempFile struct {
+	*os.File
+	dir string
+}
+
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	_ = os.RemoveAll(f.dir)
+	return err
+}
+
 type memoryBackend struct {
 	mu          sync.RWMutex
 	rowsByTrack map[string][]Row
 	targets     map[string]Target
+	settingsKV  map[string]string
 }
 
-func (m *memoryBackend) append(targetName, address string, port int, status bool, reason string, at time.Time) error {
+func (m *memoryBackend) append(entry Entry, at time.Time) error {
 	row := Row{
 		Timestamp: at.UTC().Format(time.RFC3339),
-		Status:    statusText(status),
-		Endpoint:  address + ":" + strconv.Itoa(port),
-		Reason:    strings.ToUpper(reason),
+		Status:    statusText(entry.Status),
+		Endpoint:  endpointText(entry.Address, entry.Port, entry.IP),
+		Reason:    strings.ToUpper(entry.Reason),
+		LatencyMS: latencyMS(entry.Latency),
 	}
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.rowsByTrack[targetName] = append(m.rowsByTrack[targetName], row)
+	m.rowsByTrack[entry.Target] = append(m.rowsByTrack[entry.Target], row)
 	return nil
 }
 
@@ -146,7 +365,7 @@ func (m *memoryBackend) readSince(targetName string, since time.Time, limit int)
 		filtered = append(filtered, row)
 	}
 
-	sort.Slice(filtered, func(i, j int) bool {
+	sort.SliceStable(filtered, func(i, j int) bool {
 		return filtered[i].Timestamp < filtered[j].Timestamp
 	})
 
@@ -156,13 +375,138 @@ func (m *memoryBackend) readSince(targetName string, since time.Time, limit int)
 	return filtered
 }
 
-func (m *memoryBackend) listTargets() ([]Target, error) {
+func (m *memoryBackend) statusChangesSince(targetName string, since time.Time, limit int) []Row {
+	rows := m.readSince(targetName, since, math.MaxInt)
+	changes := make([]Row, 0)
+	for i, row := range rows {
+		if i == 0 || row.Status != rows[i-1].Status {
+			changes = append(changes, Row{Timestamp: row.Timestamp, Status: row.Status})
+		}
+	}
+	if len(changes) > limit {
+		return changes[len(changes)-limit:]
+	}
+	return changes
+}
+
+// rowsBetween returns the rows of targetName logged from from up to to.
+func (m *memoryBackend) rowsBetween(targetName string, from, to time.Time) []Row {
+	rows := m.readSince(targetName, from, math.MaxInt)
+	for i, row := range rows {
+		if ts, err := time.Parse(time.RFC3339, row.Timestamp); err == nil && !ts.Before(to) {
+			return rows[:i]
+		}
+	}
+	return rows
+}
+
+func (m *memoryBackend) checkStats(targetName string, from, to time.Time) CheckStats {
+	var stats CheckStats
+	for _, row := range m.rowsBetween(targetName, from, to) {
+		if stats.First.IsZero() {
+			stats.First, _ = time.Parse(time.RFC3339, row.Timestamp)
+		}
+		stats.Checks += row.Checks()
+		if row.Status == "UP" {
+			stats.UpChecks += row.Checks()
+		}
+		if row.LatencyMS != nil {
+			stats.LatencySamples++
+		}
+	}
+	return stats
+}
+
+func (m *memoryBackend) bucketRows(targetName string, from time.Time, width time.Duration) []Row {
+	type slotKey struct {
+		slot   int64
+		status string
+	}
+	index := make(map[slotKey]int)
+	out := make([]Row, 0)
+	for _, row := range m.readSince(targetName, from, math.MaxInt) {
+		ts, err := time.Parse(time.RFC3339, row.Timestamp)
+		if err != nil {
+			continue
+		}
+		key := slotKey{slot: int64(ts.Sub(from) / width), status: row.Status}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(out)
+			out = append(out, Row{Timestamp: row.Timestamp, Status: row.Status})
+			continue
+		}
+		out[i].LastSeen = row.Timestamp
+	}
+	return out
+}
+
+func (m *memoryBackend) latencyAtRank(targetName string, from, to time.Time, rank int) float64 {
+	samples := make([]float64, 0)
+	for _, row := range m.rowsBetween(targetName, from, to) {
+		if row.LatencyMS != nil {
+			samples = append(samples, *row.LatencyMS)
+		}
+	}
+	if rank < 1 || rank > len(samples) {
+		return 0
+	}
+	sort.Float64s(samples)
+	return samples[rank-1]
+}
+
+func (m *memoryBackend) outageStartBefore(targetName string, at time.Time) (time.Time, bool) {
+	m.mu.RLock()
+	rows := append([]Row(nil), m.rowsByTrack[targetName]...)
+	m.mu.RUnlock()
+
+	var start time.Time
+	for _, row := range rows {
+		ts, err := time.Parse(time.RFC3339, row.Timestamp)
+		if err != nil || !ts.Before(at) {
+			continue
+		}
+		switch {
+		case row.Status == "UP":
+			start = time.Time{}
+		case start.IsZero():
+			start = ts
+		}
+	}
+	return start, !start.IsZero()
+}
+
+func (m *memoryBackend) recentChanges(limit int) []TargetRow {
+	m.mu.RLock()
+	out := make([]TargetRow, 0)
+	for target, rows := range m.rowsByTrack {
+		for _, row := range rows {
+			if isChangeReason(row.Reason) {
+				out = append(out, TargetRow{Target: target, Row: row})
+			}
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Timestamp != out[j].Timestamp {
+			return out[i].Timestamp > out[j].Timestamp
+		}
+		return out[i].Target < out[j].Target
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+func (m *memoryBackend) listTargets(includeDisabled bool) ([]Target, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	out := make([]Target, 0, len(m.targets))
 	for _, target := range m.targets {
-		if !target.Enabled {
+		if !target.Enabled && !includeDisabled {
 			continue
 		}
 		out = append(out, target)
@@ -179,18 +523,133 @@ func (m *memoryBackend) upsertTarget(target Target) error {
 	target.Address = strings.TrimSpace(target.Address)
 	target.Enabled = true
 	target.UpdatedAt = target.UpdatedAt.UTC()
+	if previous, ok := m.targets[target.Name]; ok {
+		target.FailThreshold = previous.FailThreshold
+		target.SuccessThreshold = previous.SuccessThreshold
+		target.CertFingerprint = previous.CertFingerprint
+		target.MutedUntil = previous.MutedUntil
+		target.Tags = previous.Tags
+		target.AckedBy, target.AckedAt = previous.AckedBy, previous.AckedAt
+	}
 
 	m.targets[target.Name] = target
 	return nil
 }
 
+// deleteTarget disables the target like the SQLite backend does, so a target
+// added back keeps its thresholds, mute and tags.
 func (m *memoryBackend) deleteTarget(name string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	delete(m.targets, strings.TrimSpace(name))
+	target, ok := m.targets[strings.TrimSpace(name)]
+	if !ok {
+		return nil
+	}
+	target.Enabled = false
+	target.UpdatedAt = time.Now().UTC()
+	m.targets[target.Name] = target
+	return nil
+}
+
+func (m *memoryBackend) setTargetThresholds(name string, fail, success int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	target, ok := m.targets[name]
+	if !ok || !target.Enabled {
+		return ErrTargetNotFound
+	}
+	target.FailThreshold = fail
+	target.SuccessThreshold = success
+	m.targets[name] = target
 	return nil
 }
 
+func (m *memoryBackend) setTargetCertFingerprint(name, fingerprint string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	target, ok := m.targets[name]
+	if !ok || !target.Enabled {
+		return ErrTargetNotFound
+	}
+	target.CertFingerprint = fingerprint
+	m.targets[name] = target
+	return nil
+}
+
+func (m *memoryBackend) setTargetMute(name string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	target, ok := m.targets[name]
+	if !ok || !target.Enabled {
+		return ErrTargetNotFound
+	}
+	target.MutedUntil = until.UTC()
+	m.targets[name] = target
+	return nil
+}
+
+func (m *memoryBackend) setTargetTags(name string, tags []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	target, ok := m.targets[name]
+	if !ok || !target.Enabled {
+		return ErrTargetNotFound
+	}
+	target.Tags = slices.Clone(tags)
+	m.targets[name] = target
+	return nil
+}
+
+func (m *memoryBackend) setTargetAck(name, by string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	target, ok := m.targets[name]
+	if !ok || !target.Enabled {
+		return ErrTargetNotFound
+	}
+	target.AckedBy, target.AckedAt = by, at.UTC()
+	m.targets[name] = target
+	return nil
+}
+
+func (m *memoryBackend) settings() (map[string]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make(map[string]string, len(m.settingsKV))
+	for key, value := range m.settingsKV {
+		out[key] = value
+	}
+	return out, nil
+}
+
+func (m *memoryBackend) setSettings(values map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, value := range values {
+		m.settingsKV[key] = value
+	}
+	return nil
+}
+
+func (m *memoryBackend) backup(context.Context, string) error {
+	return ErrNotSupported
+}
+
+// isChangeReason matches INIT and CHANGE rows, including suffixed reasons such
+// as "CHANGE 3/5" written for multi-probe targets.
+func isChangeReason(reason string) bool {
+	kind, _, _ := strings.Cut(reason, " ")
+	return kind == "INIT" || kind == "CHANGE"
+}
+
+func latencyMS(latency time.Duration) *float64 {
+	if latency <= 0 {
+		return nil
+	}
+	value := float64(latency.Microseconds()) / 1000
+	return &value
+}
+
 func statusText(value bool) string {
 	if value {
 		return "UP"
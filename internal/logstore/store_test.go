@@ -0,0 +1,96 @@
+package logstore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadLastDaysKeepsNewestRowsOverLimit(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewSQLite(SQLiteOptions{Path: filepath.Join(t.TempDir(), "trackway.db")})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	now := time.Now().UTC()
+	for i := range 50 {
+		entry := Entry{Target: "api", Address: "10.0.0.1", Port: 443, Status: i < 40, Reason: "POLL"}
+		if err := store.backend.append(entry, now.Add(time.Duration(i-50)*time.Minute)); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	rows := store.ReadLastDays("api", 1, 10)
+	if len(rows) != 10 || rows[0].Status != "DOWN" || rows[0].Timestamp >= rows[9].Timestamp {
+		t.Fatalf("expected the newest 10 rows oldest first, got %+v", rows)
+	}
+}
+
+func TestSetSettingsStoresAllOrNothing(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewSQLite(SQLiteOptions{Path: filepath.Join(t.TempDir(), "trackway.db")})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := store.SetSettings(map[string]string{"interval_seconds": "30", "max_parallel_checks": "4"}); err != nil {
+		t.Fatalf("set settings: %v", err)
+	}
+
+	db := store.backend.(*sqliteBackend).db
+	if _, err := db.Exec(`CREATE TRIGGER reject_bad BEFORE INSERT ON settings WHEN NEW.key = 'bad'
+		BEGIN SELECT RAISE(ABORT, 'rejected'); END`); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+	if err := store.SetSettings(map[string]string{"interval_seconds": "60", "bad": "1"}); err == nil {
+		t.Fatal("expected the update to fail")
+	}
+
+	settings, err := store.Settings()
+	if err != nil {
+		t.Fatalf("read settings: %v", err)
+	}
+	if len(settings) != 2 || settings["interval_seconds"] != "30" || settings["max_parallel_checks"] != "4" {
+		t.Fatalf("expected the failed update to leave every setting unchanged, got %v", settings)
+	}
+}
+
+func TestDeleteTargetDisablesInEveryBackend(t *testing.T) {
+	t.Parallel()
+
+	sqlite, err := NewSQLite(SQLiteOptions{Path: filepath.Join(t.TempDir(), "trackway.db")})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	memory, err := NewMemory()
+	if err != nil {
+		t.Fatalf("open memory: %v", err)
+	}
+	for name, store := range map[string]*Store{"sqlite": sqlite, "memory": memory} {
+		if err := store.UpsertTarget("api", "10.0.0.1", 443); err != nil {
+			t.Fatalf("%s: upsert: %v", name, err)
+		}
+		if err := store.SetTargetThresholds("api", 3, 2); err != nil {
+			t.Fatalf("%s: set thresholds: %v", name, err)
+		}
+		if err := store.DeleteTarget("api"); err != nil {
+			t.Fatalf("%s: delete: %v", name, err)
+		}
+		if targets, err := store.ListTargets(); err != nil || len(targets) != 0 {
+			t.Fatalf("%s: expected no enabled targets, got %+v (%v)", name, targets, err)
+		}
+		all, err := store.ListAllTargets()
+		if err != nil || len(all) != 1 || all[0].Enabled {
+			t.Fatalf("%s: expected the target to stay disabled, got %+v (%v)", name, all, err)
+		}
+
+		if err := store.UpsertTarget("api", "10.0.0.1", 443); err != nil {
+			t.Fatalf("%s: upsert again: %v", name, err)
+		}
+		targets, err := store.ListTargets()
+		if err != nil || len(targets) != 1 || targets[0].FailThreshold != 3 || targets[0].SuccessThreshold != 2 {
+			t.Fatalf("%s: expected the re-enabled target to keep its thresholds, got %+v (%v)", name, targets, err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package teams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"trackway/internal/tracker"
+)
+
+const (
+	colorDown    = "D83B01"
+	colorUp      = "2EB886"
+	colorNeutral = "808080"
+)
+
+// Sink posts alert groups to a Microsoft Teams incoming webhook as
+// MessageCards.
+type Sink struct {
+	webhookURL string
+	client     *http.Client
+}
+
+func New(webhookURL string) *Sink {
+	return &Sink{webhookURL: webhookURL, client: http.DefaultClient}
+}
+
+func (s *Sink) Name() string {
+	return "teams"
+}
+
+func (s *Sink) SendAlertGroup(ctx context.Context, group tracker.AlertGroup) error {
+	body, err := json.Marshal(buildCard(group))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("teams webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+type messageCard struct {
+	Type       string    `json:"@type"`
+	Context    string    `json:"@context"`
+	ThemeColor string    `json:"themeColor"`
+	Summary    string    `json:"summary"`
+	Title      string    `json:"title"`
+	Sections   []section `json:"sections"`
+}
+
+type section struct {
+	ActivityTitle string `json:"activityTitle,omitempty"`
+	Facts         []fact `json:"facts,omitempty"`
+	Text          string `json:"text,omitempty"`
+}
+
+type fact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func buildCard(group tracker.AlertGroup) messageCard {
+	title := group.Kind
+	if len(group.Targets) > 1 {
+		title = fmt.Sprintf("%s x%d", group.Kind, len(group.Targets))
+	}
+	color := kindColor(group.Kind)
+	if group.Replay {
+		title = "REPLAY: " + title
+		color = colorNeutral
+	}
+	facts := []fact{
+		{Name: "reason", Value: group.Reason},
+		{Name: "time_utc", Value: group.Occurred.UTC().Format(time.RFC3339)},
+	}
+	if group.Detail != "" {
+		facts = append(facts, fact{Name: "detail", Value: group.Detail})
+	}
+	sections := []section{{Facts: facts}}
+	for _, target := range group.Targets {
+		targetFacts := []fact{{Name: "endpoint", Value: fmt.Sprintf("%s:%d", target.Address, target.Port)}}
+		if target.RunbookURL != "" {
+			targetFacts = append(targetFacts, fact{Name: "runbook", Value: fmt.Sprintf("[runbook](%s)", target.RunbookURL)})
+		}
+		sections = append(sections, section{ActivityTitle: target.Name, Facts: targetFacts})
+	}
+	return messageCard{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: color,
+		Summary:    title,
+		Title:      title,
+		Sections:   sections,
+	}
+}
+
+func kindColor(kind string) string {
+	switch kind {
+	case "DOWN", "STILL_DOWN", "AUTO_DISABLED", "STORAGE_DEGRADED":
+		return colorDown
+	case "RECOVERED", "RESOLVED", "STORAGE_RECOVERED":
+		return colorUp
+	default:
+		return colorNeutral
+	}
+}
@@ -0,0 +1,66 @@
+package teams
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"trackway/internal/tracker"
+)
+
+func TestSinkPostsMessageCard(t *testing.T) {
+	t.Parallel()
+
+	var got messageCard
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode card: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	err := New(server.URL).SendAlertGroup(context.Background(), tracker.AlertGroup{
+		Kind:     "DOWN",
+		Reason:   "state-change",
+		Occurred: time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC),
+		Targets: []tracker.AlertTarget{
+			{Name: "a", Address: "10.0.0.1", Port: 80},
+			{Name: "b", Address: "10.0.0.2", Port: 443, RunbookURL: "https://wiki.example/b"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if got.Title != "DOWN x2" || got.ThemeColor != colorDown || len(got.Sections) != 3 {
+		t.Fatalf("unexpected card: %+v", got)
+	}
+	if got.Sections[2].ActivityTitle != "b" || len(got.Sections[2].Facts) != 2 {
+		t.Fatalf("expected target section with runbook, got %+v", got.Sections[2])
+	}
+}
+
+func TestReplayCardIsMarked(t *testing.T) {
+	t.Parallel()
+
+	card := buildCard(tracker.AlertGroup{Kind: "DOWN", Replay: true, Targets: []tracker.AlertTarget{{Name: "a"}}})
+	if card.Title != "REPLAY: DOWN" || card.ThemeColor != colorNeutral {
+		t.Fatalf("expected a neutral replay card, got %+v", card)
+	}
+}
+
+func TestSinkReportsWebhookErrors(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	if err := New(server.URL).SendAlertGroup(context.Background(), tracker.AlertGroup{Kind: "RECOVERED"}); err == nil {
+		t.Fatal("expected error for rejected webhook")
+	}
+}
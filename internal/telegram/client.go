@@ -1,7 +1,12 @@
 package telegram
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
 	"time"
 
 	tgbot "github.com/go-telegram/bot"
@@ -12,12 +17,32 @@ import (
 
 const maxMessageLength = 4000
 const sendTimeout = 10 * time.Second
+const uploadTimeout = time.Minute
+
+const (
+	defaultRestartBackoff    = time.Second
+	defaultRestartMaxBackoff = 5 * time.Minute
+)
+
+// maxMirrors bounds how many default-chat messages remember their copies in
+// the broadcast chats for later edits.
+const maxMirrors = 256
 
 type UpdateHandler func(ctx context.Context, update *models.Update)
 
 type Client struct {
 	bot    *tgbot.Bot
 	chatID int64
+	// broadcast chats get a copy of every default-chat message. mirrors maps
+	// a default-chat message ID to its copies so edits and pins follow.
+	broadcast   []int64
+	mu          sync.Mutex
+	mirrors     map[int]map[int64]int
+	mirrorOrder []int
+
+	start      func(ctx context.Context)
+	backoff    time.Duration
+	maxBackoff time.Duration
 }
 
 func New(token string, chatID int64, handler UpdateHandler) (*Client, error) {
@@ -34,66 +59,298 @@ func New(token string, chatID int64, handler UpdateHandler) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Client{bot: b, chatID: chatID}, nil
+	return &Client{
+		bot:        b,
+		chatID:     chatID,
+		start:      b.Start,
+		backoff:    defaultRestartBackoff,
+		maxBackoff: defaultRestartMaxBackoff,
+	}, nil
+}
+
+// SetRestartBackoff configures the delay before the polling loop is restarted
+// after an unexpected exit. The delay doubles per restart up to maxBackoff.
+func (c *Client) SetRestartBackoff(initial, maxBackoff time.Duration) {
+	if initial > 0 {
+		c.backoff = initial
+	}
+	if maxBackoff >= c.backoff {
+		c.maxBackoff = maxBackoff
+	}
 }
 
+// SetBroadcastChatIDs sets extra chats that get a copy of every message sent
+// to the default chat. Command replies via SendHTML are not copied.
+func (c *Client) SetBroadcastChatIDs(chatIDs []int64) {
+	c.broadcast = nil
+	for _, chatID := range chatIDs {
+		if chatID != 0 && chatID != c.chatID && !slices.Contains(c.broadcast, chatID) {
+			c.broadcast = append(c.broadcast, chatID)
+		}
+	}
+}
+
+// Start runs the update polling loop until ctx is cancelled. If the loop
+// returns or panics while ctx is still live it is restarted with exponential
+// backoff, so a Telegram outage does not take the bot side down for good.
 func (c *Client) Start(ctx context.Context) {
-	c.bot.Start(ctx)
+	delay := c.backoff
+	for {
+		startedAt := time.Now()
+		err := c.runOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		// A loop that stayed up for a while is treated as healthy again.
+		if time.Since(startedAt) > c.maxBackoff {
+			delay = c.backoff
+		}
+		slog.Warn("telegram polling stopped unexpectedly, restarting", "error", err, "retry_in", delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, c.maxBackoff)
+	}
 }
 
+func (c *Client) runOnce(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	c.start(ctx)
+	return nil
+}
+
+// SendDefaultHTML sends text to the default chat and every broadcast chat.
+// A broadcast chat that fails is logged and does not stop the others; only a
+// failure in the default chat is returned.
 func (c *Client) SendDefaultHTML(ctx context.Context, text string) error {
-	return c.SendHTML(ctx, c.chatID, text)
+	err := c.SendHTML(ctx, c.chatID, text)
+	for _, chatID := range c.broadcast {
+		if err := c.SendHTML(ctx, chatID, text); err != nil {
+			slog.Warn("failed to send to broadcast chat", "chat_id", chatID, "error", err)
+		}
+	}
+	return err
 }
 
+// SendDefaultHTMLWithID is SendDefaultHTML that returns the default-chat
+// message ID, or 0 when text needed more than one message.
 func (c *Client) SendDefaultHTMLWithID(ctx context.Context, text string) (int, error) {
+	return c.sendDefaultMirrored(ctx, text, 0, nil)
+}
+
+// SendDefaultHTMLReply sends text to the default chat as a reply to
+// replyTo and returns the new message ID. The message is still sent when
+// replyTo no longer exists. Broadcast chats get it as a reply to their copy
+// of replyTo when there is one.
+func (c *Client) SendDefaultHTMLReply(ctx context.Context, replyTo int, text string) (int, error) {
+	return c.sendDefaultMirrored(ctx, text, replyTo, nil)
+}
+
+// SendDefaultHTMLWithButton is SendDefaultHTMLReply with an inline button
+// that sends data back as a callback query; replyTo may be 0. Only the
+// default-chat message gets the button, and only when text fits in one
+// message. Edits remove it.
+func (c *Client) SendDefaultHTMLWithButton(ctx context.Context, replyTo int, text, button, data string) (int, error) {
+	markup := &models.InlineKeyboardMarkup{
+		InlineKeyboard: [][]models.InlineKeyboardButton{{{Text: button, CallbackData: data}}},
+	}
+	return c.sendDefaultMirrored(ctx, text, replyTo, markup)
+}
+
+// AnswerCallbackQuery answers a callback query with a short notification
+// shown to the user who pressed the button.
+func (c *Client) AnswerCallbackQuery(ctx context.Context, queryID, text string) error {
+	answerCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	_, err := c.bot.AnswerCallbackQuery(answerCtx, &tgbot.AnswerCallbackQueryParams{
+		CallbackQueryID: queryID,
+		Text:            text,
+	})
+	return err
+}
+
+func (c *Client) sendDefaultMirrored(ctx context.Context, text string, replyTo int, markup models.ReplyMarkup) (int, error) {
 	chunks := util.SplitByLineLimit(text, maxMessageLength)
-	if len(chunks) != 1 {
-		if err := c.SendDefaultHTML(ctx, text); err != nil {
-			return 0, err
+	messageID, err := c.sendChunks(ctx, c.chatID, chunks, replyTo, markup)
+	replies := c.mirrorsOf(replyTo)
+	copies := make(map[int64]int, len(c.broadcast))
+	for _, chatID := range c.broadcast {
+		copyID, err := c.sendChunks(ctx, chatID, chunks, replies[chatID], nil)
+		if err != nil {
+			slog.Warn("failed to send to broadcast chat", "chat_id", chatID, "error", err)
+			continue
 		}
-		return 0, nil
+		copies[chatID] = copyID
+	}
+	if err != nil {
+		return 0, err
 	}
-	chunkCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	c.remember(messageID, copies)
+	return messageID, nil
+}
+
+// PinDefaultMessage pins a message in the default chat without notifying,
+// and its copies in the broadcast chats.
+func (c *Client) PinDefaultMessage(ctx context.Context, messageID int) error {
+	for chatID, copyID := range c.mirrorsOf(messageID) {
+		if err := c.pin(ctx, chatID, copyID); err != nil {
+			slog.Warn("failed to pin in broadcast chat", "chat_id", chatID, "error", err)
+		}
+	}
+	return c.pin(ctx, c.chatID, messageID)
+}
+
+// DefaultPinnedMessageID returns the ID of the most recently pinned message
+// in the default chat, or 0 when none is pinned.
+func (c *Client) DefaultPinnedMessageID(ctx context.Context) (int, error) {
+	getCtx, cancel := context.WithTimeout(ctx, sendTimeout)
 	defer cancel()
-	msg, err := c.bot.SendMessage(chunkCtx, &tgbot.SendMessageParams{
-		ChatID:    c.chatID,
-		Text:      chunks[0],
-		ParseMode: models.ParseModeHTML,
-	})
+	chat, err := c.bot.GetChat(getCtx, &tgbot.GetChatParams{ChatID: c.chatID})
 	if err != nil {
 		return 0, err
 	}
-	return msg.ID, nil
+	if chat == nil || chat.PinnedMessage == nil {
+		return 0, nil
+	}
+	return chat.PinnedMessage.ID, nil
 }
 
+// EditDefaultHTML edits a default-chat message and its broadcast copies.
 func (c *Client) EditDefaultHTML(ctx context.Context, messageID int, text string) error {
 	chunks := util.SplitByLineLimit(text, maxMessageLength)
 	if len(chunks) != 1 {
 		return c.SendDefaultHTML(ctx, text)
 	}
-	chunkCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	for chatID, copyID := range c.mirrorsOf(messageID) {
+		if err := c.edit(ctx, chatID, copyID, chunks[0]); err != nil {
+			slog.Warn("failed to edit in broadcast chat", "chat_id", chatID, "error", err)
+		}
+	}
+	return c.edit(ctx, c.chatID, messageID, chunks[0])
+}
+
+// sendChunks sends chunks in order and returns the message ID when there was
+// only one, which alone gets markup.
+func (c *Client) sendChunks(ctx context.Context, chatID int64, chunks []string, replyTo int, markup models.ReplyMarkup) (int, error) {
+	if len(chunks) != 1 {
+		markup = nil
+	}
+	messageID := 0
+	for _, chunk := range chunks {
+		msgID, err := c.sendMessage(ctx, chatID, chunk, replyTo, markup)
+		if err != nil {
+			return 0, err
+		}
+		if len(chunks) == 1 {
+			messageID = msgID
+		}
+	}
+	return messageID, nil
+}
+
+func (c *Client) sendMessage(ctx context.Context, chatID int64, text string, replyTo int, markup models.ReplyMarkup) (int, error) {
+	params := &tgbot.SendMessageParams{
+		ChatID:      chatID,
+		Text:        text,
+		ParseMode:   models.ParseModeHTML,
+		ReplyMarkup: markup,
+	}
+	if replyTo != 0 {
+		params.ReplyParameters = &models.ReplyParameters{
+			MessageID:                replyTo,
+			AllowSendingWithoutReply: true,
+		}
+	}
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	msg, err := c.bot.SendMessage(sendCtx, params)
+	if err != nil {
+		return 0, err
+	}
+	return msg.ID, nil
+}
+
+func (c *Client) pin(ctx context.Context, chatID int64, messageID int) error {
+	pinCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	_, err := c.bot.PinChatMessage(pinCtx, &tgbot.PinChatMessageParams{
+		ChatID:              chatID,
+		MessageID:           messageID,
+		DisableNotification: true,
+	})
+	return err
+}
+
+func (c *Client) edit(ctx context.Context, chatID int64, messageID int, text string) error {
+	editCtx, cancel := context.WithTimeout(ctx, sendTimeout)
 	defer cancel()
-	_, err := c.bot.EditMessageText(chunkCtx, &tgbot.EditMessageTextParams{
-		ChatID:    c.chatID,
+	_, err := c.bot.EditMessageText(editCtx, &tgbot.EditMessageTextParams{
+		ChatID:    chatID,
 		MessageID: messageID,
-		Text:      chunks[0],
+		Text:      text,
 		ParseMode: models.ParseModeHTML,
 	})
 	return err
 }
 
-func (c *Client) SendHTML(ctx context.Context, chatID int64, text string) error {
-	for _, chunk := range util.SplitByLineLimit(text, maxMessageLength) {
-		chunkCtx, cancel := context.WithTimeout(ctx, sendTimeout)
-		_, err := c.bot.SendMessage(chunkCtx, &tgbot.SendMessageParams{
-			ChatID:    chatID,
-			Text:      chunk,
-			ParseMode: models.ParseModeHTML,
-		})
-		cancel()
-		if err != nil {
-			return err
-		}
+func (c *Client) remember(messageID int, copies map[int64]int) {
+	if messageID == 0 || len(copies) == 0 {
+		return
 	}
-	return nil
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mirrors == nil {
+		c.mirrors = make(map[int]map[int64]int)
+	}
+	c.mirrors[messageID] = copies
+	c.mirrorOrder = append(c.mirrorOrder, messageID)
+	if len(c.mirrorOrder) > maxMirrors {
+		delete(c.mirrors, c.mirrorOrder[0])
+		c.mirrorOrder = c.mirrorOrder[1:]
+	}
+}
+
+func (c *Client) mirrorsOf(messageID int) map[int64]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mirrors[messageID]
+}
+
+// SendDocument uploads data as a file named filename to chatID, with an
+// optional HTML caption.
+func (c *Client) SendDocument(ctx context.Context, chatID int64, filename string, data []byte, caption string) error {
+	uploadCtx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+	_, err := c.bot.SendDocument(uploadCtx, &tgbot.SendDocumentParams{
+		ChatID:    chatID,
+		Document:  &models.InputFileUpload{Filename: filename, Data: bytes.NewReader(data)},
+		Caption:   caption,
+		ParseMode: models.ParseModeHTML,
+	})
+	return err
+}
+
+// SendPhoto uploads data as an image named filename to chatID, with an
+// optional HTML caption.
+func (c *Client) SendPhoto(ctx context.Context, chatID int64, filename string, data []byte, caption string) error {
+	uploadCtx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+	_, err := c.bot.SendPhoto(uploadCtx, &tgbot.SendPhotoParams{
+		ChatID:    chatID,
+		Photo:     &models.InputFileUpload{Filename: filename, Data: bytes.NewReader(data)},
+		Caption:   caption,
+		ParseMode: models.ParseModeHTML,
+	})
+	return err
+}
+
+func (c *Client) SendHTML(ctx context.Context, chatID int64, text string) error {
+	_, err := c.sendChunks(ctx, chatID, util.SplitByLineLimit(text, maxMessageLength), 0, nil)
+	return err
 }
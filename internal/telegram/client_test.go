@@ -0,0 +1,181 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+)
+
+func TestStartRestartsUntilContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := 0
+	c := &Client{
+		backoff:    time.Millisecond,
+		maxBackoff: 4 * time.Millisecond,
+		start: func(context.Context) {
+			runs++
+			switch runs {
+			case 1:
+				return
+			case 2:
+				panic("poll failed")
+			default:
+				cancel()
+			}
+		},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		c.Start(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after context cancel")
+	}
+	if runs != 3 {
+		t.Fatalf("expected 3 runs (return, panic, cancel), got %d", runs)
+	}
+}
+
+func TestBroadcastChatsGetCopiesAndEdits(t *testing.T) {
+	t.Parallel()
+
+	type call struct {
+		method    string
+		chatID    int64
+		messageID int
+	}
+	var (
+		mu     sync.Mutex
+		calls  []call
+		nextID = 100
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("parse form: %v", err)
+		}
+		chatID, _ := strconv.ParseInt(r.FormValue("chat_id"), 10, 64)
+		messageID, _ := strconv.Atoi(r.FormValue("message_id"))
+		method := path.Base(r.URL.Path)
+		mu.Lock()
+		calls = append(calls, call{method, chatID, messageID})
+		nextID++
+		id := nextID
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if chatID == 3 {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"ok":true,"result":{"message_id":%d,"date":0,"chat":{"id":%d,"type":"private"}}}`, id, chatID)
+	}))
+	defer server.Close()
+
+	b, err := tgbot.New("1:test", tgbot.WithServerURL(server.URL), tgbot.WithSkipGetMe())
+	if err != nil {
+		t.Fatalf("bot init: %v", err)
+	}
+	c := &Client{bot: b, chatID: 1}
+	c.SetBroadcastChatIDs([]int64{3, 2, 1, 2})
+	ctx := context.Background()
+
+	messageID, err := c.SendDefaultHTMLWithID(ctx, "down")
+	if err != nil || messageID != 101 {
+		t.Fatalf("expected the default chat message ID despite a failing broadcast chat, got %d, %v", messageID, err)
+	}
+	if err := c.EditDefaultHTML(ctx, messageID, "recovered"); err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	if err := c.SendHTML(ctx, 9, "reply"); err != nil {
+		t.Fatalf("reply: %v", err)
+	}
+
+	want := []call{
+		{"sendMessage", 1, 0},
+		{"sendMessage", 3, 0},
+		{"sendMessage", 2, 0},
+		{"editMessageText", 2, 103},
+		{"editMessageText", 1, 101},
+		{"sendMessage", 9, 0},
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(calls) != len(want) {
+		t.Fatalf("expected %d calls, got %+v", len(want), calls)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("call %d: expected %+v, got %+v", i, want[i], calls[i])
+		}
+	}
+}
+
+func TestButtonOnlyOnDefaultChatMessage(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		markups = map[int64]string{}
+		answers []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("parse form: %v", err)
+		}
+		chatID, _ := strconv.ParseInt(r.FormValue("chat_id"), 10, 64)
+		mu.Lock()
+		if path.Base(r.URL.Path) == "answerCallbackQuery" {
+			answers = append(answers, r.FormValue("callback_query_id")+":"+r.FormValue("text"))
+		} else {
+			markups[chatID] = r.FormValue("reply_markup")
+		}
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if path.Base(r.URL.Path) == "answerCallbackQuery" {
+			fmt.Fprint(w, `{"ok":true,"result":true}`)
+			return
+		}
+		fmt.Fprintf(w, `{"ok":true,"result":{"message_id":5,"date":0,"chat":{"id":%d,"type":"private"}}}`, chatID)
+	}))
+	defer server.Close()
+
+	b, err := tgbot.New("1:test", tgbot.WithServerURL(server.URL), tgbot.WithSkipGetMe())
+	if err != nil {
+		t.Fatalf("bot init: %v", err)
+	}
+	c := &Client{bot: b, chatID: 1}
+	c.SetBroadcastChatIDs([]int64{2})
+	ctx := context.Background()
+	if messageID, err := c.SendDefaultHTMLWithButton(ctx, 0, "down", "Ack", "ack"); err != nil || messageID != 5 {
+		t.Fatalf("send: %d, %v", messageID, err)
+	}
+	if err := c.AnswerCallbackQuery(ctx, "q1", "Acknowledged"); err != nil {
+		t.Fatalf("answer: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !strings.Contains(markups[1], `"callback_data":"ack"`) || markups[2] != "" {
+		t.Fatalf("expected the button on the default chat message only, got %v", markups)
+	}
+	if len(answers) != 1 || answers[0] != "q1:Acknowledged" {
+		t.Fatalf("unexpected answers %v", answers)
+	}
+}
@@ -0,0 +1,214 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot/models"
+
+	"trackway/internal/util"
+)
+
+// ackCallbackData is the callback data of the Ack button under DOWN alerts.
+const ackCallbackData = "ack"
+
+// maxAckableAlerts bounds how many DOWN alert messages are remembered for
+// acknowledgement; older ones can no longer be acked.
+const maxAckableAlerts = 256
+
+// ErrAlertNotAckable is returned when an acked message is unknown, or all its
+// targets have recovered.
+var ErrAlertNotAckable = errors.New("alert is no longer open")
+
+// AckSender is implemented by notifiers that can put an inline button under a
+// default-chat message and answer the callback queries it raises. DOWN
+// alerts get an Ack button when the notifier implements it.
+type AckSender interface {
+	SendDefaultHTMLWithButton(ctx context.Context, replyTo int, text, button, data string) (int, error)
+	AnswerCallbackQuery(ctx context.Context, queryID, text string) error
+}
+
+// ackableAlert is a sent DOWN alert with an Ack button.
+type ackableAlert struct {
+	targets []string
+	text    string
+	ackedBy string
+}
+
+// Acknowledge records that by acknowledged the outage of the named targets
+// at at, and returns the ones that are DOWN; others are skipped. The ack is
+// stored with the target until it recovers.
+func (e *MonitorEngine) Acknowledge(trackNames []string, by string, at time.Time) []string {
+	e.mu.Lock()
+	acked := make([]string, 0, len(trackNames))
+	for _, name := range trackNames {
+		target, ok := e.targetByName[name]
+		if !ok || target.LastStatus == nil || *target.LastStatus {
+			continue
+		}
+		target.AckedBy, target.AckedAt = by, at
+		acked = append(acked, name)
+	}
+	e.mu.Unlock()
+
+	for _, name := range acked {
+		if err := e.logs.SetTargetAck(name, by, at); err != nil {
+			e.logger.Warn("failed to store target ack", "track", name, "error", err)
+		}
+	}
+	return acked
+}
+
+// sendDown sends a DOWN alert, with an Ack button when the notifier supports
+// it, and returns its message ID. Callers hold a.mu.
+func (a *AlertManager) sendDown(ctx context.Context, text string, group []alertEvent) (int, error) {
+	sender, ok := a.notifier.(AckSender)
+	if !ok {
+		return a.sendDefaultWithID(ctx, text)
+	}
+	replyTo := 0
+	if a.board != nil {
+		replyTo = a.board.currentID()
+	}
+	messageID, err := sender.SendDefaultHTMLWithButton(ctx, replyTo, text, "Ack", ackCallbackData)
+	if err != nil || messageID == 0 {
+		return messageID, err
+	}
+	targets := make([]string, 0, len(group))
+	for _, ev := range group {
+		if ev.Target != "" {
+			targets = append(targets, ev.Target)
+		}
+	}
+	if a.ackable == nil {
+		a.ackable = make(map[int]*ackableAlert)
+	}
+	a.ackable[messageID] = &ackableAlert{targets: targets, text: text}
+	a.ackOrder = append(a.ackOrder, messageID)
+	if len(a.ackOrder) > maxAckableAlerts {
+		delete(a.ackable, a.ackOrder[0])
+		a.ackOrder = a.ackOrder[1:]
+	}
+	return messageID, nil
+}
+
+// ackTargets returns the targets of the DOWN alert messageID and who already
+// acknowledged it, if anyone.
+func (a *AlertManager) ackTargets(messageID int) ([]string, string, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	alert, ok := a.ackable[messageID]
+	if !ok {
+		return nil, "", false
+	}
+	return alert.targets, alert.ackedBy, true
+}
+
+// markAcked edits the DOWN alert messageID to show who acknowledged it and
+// when, which also removes its Ack button.
+func (a *AlertManager) markAcked(ctx context.Context, messageID int, by string, at time.Time) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	alert, ok := a.ackable[messageID]
+	if !ok {
+		return ErrAlertNotAckable
+	}
+	alert.ackedBy = by
+	text := fmt.Sprintf("%s\n\n<b>ACK</b> by %s at <code>%s</code>", alert.text, util.HTMLEscape(by), util.FormatTime(at))
+	return a.notifier.EditDefaultHTML(ctx, messageID, text)
+}
+
+// forgetAckable drops a DOWN alert whose message was edited into a recovery.
+// Callers hold a.mu.
+func (a *AlertManager) forgetAckable(messageID int) {
+	delete(a.ackable, messageID)
+}
+
+// acknowledger acks the DOWN alert messageID for its targets in engine and
+// returns the answer for the user who pressed the button.
+func acknowledger(alerts *AlertManager, engine *MonitorEngine) func(ctx context.Context, messageID int, by string) (string, error) {
+	return func(ctx context.Context, messageID int, by string) (string, error) {
+		targets, ackedBy, ok := alerts.ackTargets(messageID)
+		if !ok {
+			return "", ErrAlertNotAckable
+		}
+		if ackedBy != "" {
+			return "Already acknowledged by " + ackedBy, nil
+		}
+		now := time.Now().UTC()
+		if len(engine.Acknowledge(targets, by, now)) == 0 {
+			return "", ErrAlertNotAckable
+		}
+		if err := alerts.markAcked(ctx, messageID, by, now); err != nil {
+			alerts.logger.Warn("failed to edit acked alert", "message_id", messageID, "error", err)
+		}
+		return "Acknowledged", nil
+	}
+}
+
+// SetAcknowledger enables the Ack button of DOWN alerts with the given ack
+// of an alert message.
+func (h *CommandHandler) SetAcknowledger(fn func(ctx context.Context, messageID int, by string) (string, error)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ackFn = fn
+}
+
+// handleCallback answers a press of an inline button.
+func (h *CommandHandler) handleCallback(ctx context.Context, query *models.CallbackQuery) {
+	sender, ok := h.notifier.(AckSender)
+	if !ok {
+		return
+	}
+	if err := sender.AnswerCallbackQuery(ctx, query.ID, h.callbackText(ctx, query)); err != nil {
+		h.logger.Warn("failed to answer callback query", "error", err)
+	}
+}
+
+func (h *CommandHandler) callbackText(ctx context.Context, query *models.CallbackQuery) string {
+	chatID, messageID := callbackMessage(query.Message)
+	if !h.isChatAllowed(chatID) {
+		return "This bot command is not available in this chat."
+	}
+	h.mu.RLock()
+	ackFn := h.ackFn
+	h.mu.RUnlock()
+	if query.Data != ackCallbackData || ackFn == nil || messageID == 0 {
+		return "This button is no longer supported."
+	}
+	answer, err := ackFn(ctx, messageID, callbackUser(query.From))
+	if errors.Is(err, ErrAlertNotAckable) {
+		return "This alert is no longer open."
+	}
+	if err != nil {
+		h.logger.Warn("failed to acknowledge alert", "message_id", messageID, "error", err)
+		return "Could not acknowledge the alert."
+	}
+	return answer
+}
+
+// callbackMessage returns the chat and ID of the message a button was
+// pressed on.
+func callbackMessage(message models.MaybeInaccessibleMessage) (int64, int) {
+	switch {
+	case message.Message != nil:
+		return message.Message.Chat.ID, message.Message.ID
+	case message.InaccessibleMessage != nil:
+		return message.InaccessibleMessage.Chat.ID, message.InaccessibleMessage.MessageID
+	}
+	return 0, 0
+}
+
+// callbackUser names the user who pressed a button for the ack record.
+func callbackUser(user models.User) string {
+	if user.Username != "" {
+		return "@" + user.Username
+	}
+	if name := strings.TrimSpace(user.FirstName + " " + user.LastName); name != "" {
+		return name
+	}
+	return fmt.Sprintf("user %d", user.ID)
+}
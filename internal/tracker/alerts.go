@@ -4,44 +4,213 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/url"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"trackway/internal/config"
 	"trackway/internal/util"
 )
 
+// AlertOptions holds optional alert routing and formatting settings.
+type AlertOptions struct {
+	EscalationChatID int64
+	// OrphanRecovery handles RECOVERED alerts without a sent DOWN: "relabel",
+	// "suppress" or "send" (also the zero value).
+	OrphanRecovery string
+	// RecoveryConfirmDelay > 0 holds RECOVERED alerts until a recheck after
+	// that delay succeeds; see AlertManager.SetRecoveryConfirm.
+	RecoveryConfirmDelay time.Duration
+	// FastRecoveryWindow is how long after a DOWN alert a recovery edits it
+	// instead of sending a new message; 0 means defaultFastRecoveryWindow.
+	FastRecoveryWindow time.Duration
+	// DownCooldown > 0 suppresses a DOWN alert for a target within that long
+	// of its last sent one, unless a recovery was delivered in between.
+	DownCooldown time.Duration
+	// QuietHours defers alerts of non-critical targets into a digest.
+	QuietHours config.QuietHours
+}
+
+const defaultFastRecoveryWindow = 30 * time.Second
+
 type AlertManager struct {
-	notifier Notifier
-	logger   *slog.Logger
-	mu       sync.Mutex
+	notifier         Notifier
+	logger           *slog.Logger
+	escalationChatID int64
+	// dashboardLink, when set, returns a signed dashboard link for a path,
+	// which DOWN alerts end with.
+	dashboardLink  func(path string) (string, error)
+	orphanRecovery string
+	fastRecovery   time.Duration
+	downCooldown   time.Duration
+	mu             sync.Mutex
 
 	pendingDown  map[string]pendingDownAlert
 	pendingGroup map[string][]pendingDownGroup
+	// downSent marks targets whose DOWN alert reached Telegram.
+	downSent map[string]bool
+	// lastDown is when the last DOWN alert of a target was sent, kept until
+	// downCooldown has passed since; used with downCooldown.
+	lastDown map[string]time.Time
+	sinks    []AlertSink
+	// sinkOutbox holds groups for the sinks, sent once a.mu is released.
+	sinkOutbox []AlertGroup
+	// board, when set, makes alerts replies to the pinned status message.
+	board *statusBoard
+	// confirm rechecks a target before its held recovery is sent.
+	confirm      func(ctx context.Context, target string) bool
+	confirmDelay time.Duration
+	heldRecovery map[string]alertEvent
+	// investigations holds sent INVESTIGATING notes by target.
+	investigations map[string]investigation
+	// quiet, when set, defers non-critical outages into quietQueue by target
+	// and their other alerts into quietNotices until the digest. quietSlow
+	// marks targets whose SLOW alert was deferred.
+	quiet        *quietHours
+	quietQueue   map[string]*quietOutage
+	quietNotices []alertEvent
+	quietSlow    map[string]bool
+	// paused suspends every alert until pausedUntil, or until Resume when
+	// that is zero.
+	paused      bool
+	pausedUntil time.Time
+	// status gives the target states summarized when a pause ends.
+	status func() Snapshot
+	// lastBatch is the latest delivered batch, grouped, for replays.
+	lastBatch [][]alertEvent
+	// ackable holds sent DOWN alerts with an Ack button by message ID,
+	// oldest first in ackOrder.
+	ackable  map[int]*ackableAlert
+	ackOrder []int
 }
 
-func NewAlertManager(notifier Notifier) *AlertManager {
+func NewAlertManager(notifier Notifier, options AlertOptions) *AlertManager {
+	fastRecovery := options.FastRecoveryWindow
+	if fastRecovery <= 0 {
+		fastRecovery = defaultFastRecoveryWindow
+	}
 	return &AlertManager{
-		notifier:     notifier,
-		logger:       slog.Default(),
-		pendingDown:  make(map[string]pendingDownAlert),
-		pendingGroup: make(map[string][]pendingDownGroup),
+		notifier:         notifier,
+		logger:           slog.Default(),
+		escalationChatID: options.EscalationChatID,
+		orphanRecovery:   options.OrphanRecovery,
+		fastRecovery:     fastRecovery,
+		downCooldown:     options.DownCooldown,
+		quiet:            newQuietHours(options.QuietHours),
+		quietQueue:       make(map[string]*quietOutage),
+		quietSlow:        make(map[string]bool),
+		confirmDelay:     options.RecoveryConfirmDelay,
+		pendingDown:      make(map[string]pendingDownAlert),
+		pendingGroup:     make(map[string][]pendingDownGroup),
+		downSent:         make(map[string]bool),
+		lastDown:         make(map[string]time.Time),
+		heldRecovery:     make(map[string]alertEvent),
+		investigations:   make(map[string]investigation),
 	}
 }
 
+// SetDashboardLink sets the generator of the signed dashboard links DOWN
+// alerts end with.
+func (a *AlertManager) SetDashboardLink(link func(path string) (string, error)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.dashboardLink = link
+}
+
+// SetRecoveryConfirm sets the recheck used to confirm recoveries when a
+// confirm delay is configured.
+func (a *AlertManager) SetRecoveryConfirm(confirm func(ctx context.Context, target string) bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.confirm = confirm
+}
+
 func (a *AlertManager) SendBatch(ctx context.Context, events []alertEvent) {
-	if a.notifier == nil || len(events) == 0 {
+	if a.notifier == nil {
 		return
 	}
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	a.sendBatchAt(ctx, events, time.Now())
+	a.unlockAndSendToSinks(ctx)
+}
+
+// sendBatchAt is SendBatch with quiet hours evaluated at now. Callers hold
+// a.mu.
+func (a *AlertManager) sendBatchAt(ctx context.Context, events []alertEvent, now time.Time) {
+	if a.pauseActive(ctx, now) {
+		if len(events) > 0 {
+			a.logger.Debug("alerts suppressed by pause", "count", len(events), "paused_until", a.pausedUntil)
+		}
+		return
+	}
+	a.flushQuietDigest(ctx, now)
+	if len(events) == 0 {
+		return
+	}
+	a.deliver(ctx, a.holdRecoveries(ctx, a.deferQuiet(events, now)), now)
+}
 
-	events = a.applyFastRecoveryEdits(ctx, events, 30*time.Second)
+// deliver sends events to Telegram and queues them for the sinks, unless
+// alerts are paused at now. Callers hold a.mu and release it with
+// unlockAndSendToSinks.
+func (a *AlertManager) deliver(ctx context.Context, events []alertEvent, now time.Time) {
+	if a.pauseActive(ctx, now) {
+		a.logger.Debug("alerts suppressed by pause", "count", len(events), "paused_until", a.pausedUntil)
+		return
+	}
+	events = a.applyDownCooldown(events)
+	events = a.applyDeEscalation(events)
+	events = a.applyOrphanRecoveries(events)
 	if len(events) == 0 {
 		return
 	}
+	// Sinks get every event; fast recovery edits only apply to Telegram.
+	groups := groupAlertEvents(events)
+	a.recordLastBatch(groups)
+	a.queueForSinks(groups)
+	events = a.applyInvestigations(ctx, events)
+	events = a.applyFastRecoveryEdits(ctx, events, a.fastRecovery)
+	if len(events) == 0 {
+		return
+	}
+
+	for _, group := range groupAlertEvents(events) {
+		kind, reason := group[0].Kind, group[0].Reason
+		key := kind + "|" + reason
+		message := formatAlertGroup(group) + a.dashboardFooter(kind, group)
+
+		a.handleGroupSend(ctx, kind, reason, group, message, key)
+		if a.escalationChatID != 0 && (kind == "STILL_DOWN" || kind == "RESOLVED") {
+			if err := a.notifier.SendHTML(ctx, a.escalationChatID, message); err != nil {
+				a.logger.Warn("failed to send escalation alert", "key", key, "count", len(group), "error", err)
+			}
+		}
+	}
+}
+
+func (a *AlertManager) sendDefaultWithID(ctx context.Context, text string) (int, error) {
+	if a.board != nil {
+		if replyTo := a.board.currentID(); replyTo != 0 {
+			return a.board.pinner.SendDefaultHTMLReply(ctx, replyTo, text)
+		}
+	}
+	return a.notifier.SendDefaultHTMLWithID(ctx, text)
+}
 
+func (a *AlertManager) sendDefault(ctx context.Context, text string) error {
+	if a.board == nil {
+		return a.notifier.SendDefaultHTML(ctx, text)
+	}
+	_, err := a.sendDefaultWithID(ctx, text)
+	return err
+}
+
+// groupAlertEvents groups events by kind and reason, DOWN groups first, with
+// targets sorted by impact, then name, inside each group. Groups of the same
+// kind are ordered by their highest impact.
+func groupAlertEvents(events []alertEvent) [][]alertEvent {
 	groups := make(map[string][]alertEvent)
 	order := make([]string, 0, len(events))
 	for _, event := range events {
@@ -52,32 +221,217 @@ func (a *AlertManager) SendBatch(ctx context.Context, events []alertEvent) {
 		groups[key] = append(groups[key], event)
 	}
 
+	for _, group := range groups {
+		sort.Slice(group, func(i, j int) bool {
+			if group[i].Impact != group[j].Impact {
+				return group[i].Impact > group[j].Impact
+			}
+			return group[i].Target < group[j].Target
+		})
+	}
 	sort.SliceStable(order, func(i, j int) bool {
 		leftKind, _, _ := strings.Cut(order[i], "|")
 		rightKind, _, _ := strings.Cut(order[j], "|")
 		if leftKind != rightKind {
 			return alertOrder(leftKind) < alertOrder(rightKind)
 		}
+		// Sorted above, so the first target carries the group's highest impact.
+		if left, right := groups[order[i]][0].Impact, groups[order[j]][0].Impact; left != right {
+			return left > right
+		}
 		return order[i] < order[j]
 	})
 
+	out := make([][]alertEvent, 0, len(order))
 	for _, key := range order {
-		group := groups[key]
-		sort.Slice(group, func(i, j int) bool { return group[i].Target < group[j].Target })
-		message := formatAlertGroup(group)
-		kind, reason, _ := strings.Cut(key, "|")
+		out = append(out, groups[key])
+	}
+	return out
+}
 
-		a.handleGroupSend(ctx, kind, reason, group, message, key)
+// dashboardFooter links DOWN alerts to the target on the dashboard, or to the
+// DOWN-filtered status view for grouped alerts. The link is signed, so it
+// opens the dashboard without /authme.
+func (a *AlertManager) dashboardFooter(kind string, group []alertEvent) string {
+	if a.dashboardLink == nil || (kind != "DOWN" && kind != "STILL_DOWN") || group[0].Target == "" {
+		return ""
+	}
+	query := url.Values{}
+	if len(group) == 1 {
+		query.Set("track", group[0].Target)
+	} else {
+		query.Set("status", "DOWN")
+	}
+	link, err := a.dashboardLink("/?" + query.Encode())
+	if err != nil {
+		a.logger.Warn("failed to sign dashboard link", "error", err)
+		return ""
+	}
+	return fmt.Sprintf("\n<a href=\"%s\">View on dashboard</a>", util.HTMLAttrEscape(link))
+}
+
+// holdRecoveries keeps RECOVERED events back until a delayed recheck confirms
+// them. At most one recheck loop runs per target; a DOWN for a target whose
+// recovery is still held drops both, since the chat never saw it recover.
+func (a *AlertManager) holdRecoveries(ctx context.Context, events []alertEvent) []alertEvent {
+	if a.confirm == nil || a.confirmDelay <= 0 {
+		return events
+	}
+	kept := make([]alertEvent, 0, len(events))
+	for _, ev := range events {
+		switch {
+		case isSlowRecovery(ev):
+		case ev.Kind == "RECOVERED":
+			if _, waiting := a.heldRecovery[ev.Target]; !waiting {
+				go a.confirmRecovery(ctx, ev.Target)
+			}
+			a.heldRecovery[ev.Target] = ev
+			continue
+		case ev.Kind == "DOWN":
+			if _, held := a.heldRecovery[ev.Target]; held {
+				delete(a.heldRecovery, ev.Target)
+				a.logger.Debug("dropped DOWN after unconfirmed recovery", "track", ev.Target)
+				continue
+			}
+		}
+		kept = append(kept, ev)
+	}
+	return kept
+}
+
+// confirmRecovery rechecks target every confirm delay until it is up, then
+// sends the held recovery. It stops once the recovery is no longer held.
+func (a *AlertManager) confirmRecovery(ctx context.Context, target string) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(a.confirmDelay):
+		}
+		up := a.confirm(ctx, target)
+
+		a.mu.Lock()
+		ev, held := a.heldRecovery[target]
+		if held && up {
+			delete(a.heldRecovery, target)
+			a.deliver(ctx, []alertEvent{ev}, time.Now())
+		}
+		a.unlockAndSendToSinks(ctx)
+		if !held || up {
+			return
+		}
+		a.logger.Debug("recovery not confirmed yet", "track", target)
+	}
+}
+
+// applyDeEscalation marks escalated outages in pendingDown and turns their
+// recovery into a RESOLVED event so the follow-up reads as closing the
+// escalation.
+func (a *AlertManager) applyDeEscalation(events []alertEvent) []alertEvent {
+	out := make([]alertEvent, 0, len(events))
+	for _, ev := range events {
+		switch ev.Kind {
+		case "STILL_DOWN":
+			pending, ok := a.pendingDown[ev.Target]
+			if !ok {
+				pending = pendingDownAlert{DownAt: ev.Occurred, Reason: ev.Reason, Address: ev.Address, Port: ev.Port}
+			}
+			pending.Escalated = true
+			a.pendingDown[ev.Target] = pending
+		case "RECOVERED":
+			if a.pendingDown[ev.Target].Escalated && !isSlowRecovery(ev) {
+				delete(a.pendingDown, ev.Target)
+				ev.Kind = "RESOLVED"
+				ev.Reason = "after-escalation"
+			}
+		}
+		out = append(out, ev)
+	}
+	return out
+}
+
+// applyOrphanRecoveries handles recoveries of targets whose DOWN alert was
+// never sent, e.g. while muted or after a failed send, so readers do not see
+// a RECOVERED without a matching DOWN.
+func (a *AlertManager) applyOrphanRecoveries(events []alertEvent) []alertEvent {
+	kept := make([]alertEvent, 0, len(events))
+	for _, ev := range events {
+		if ev.Kind != "RECOVERED" && ev.Kind != "RESOLVED" {
+			kept = append(kept, ev)
+			continue
+		}
+		sent := a.downSent[ev.Target]
+		delete(a.downSent, ev.Target)
+		if !sent && ev.Target != "" {
+			switch a.orphanRecovery {
+			case "suppress":
+				a.logger.Debug("suppressed recovery without sent DOWN", "track", ev.Target)
+				continue
+			case "relabel":
+				ev.Kind = "NOW_UP"
+				ev.Reason = "no-down-alert"
+			}
+		}
+		kept = append(kept, ev)
+	}
+	return kept
+}
+
+func (a *AlertManager) markDownSent(group []alertEvent) {
+	for _, ev := range group {
+		if ev.Target == "" {
+			continue
+		}
+		a.downSent[ev.Target] = true
+		if ev.Kind == "DOWN" && a.downCooldown > 0 {
+			a.lastDown[ev.Target] = ev.Occurred
+		}
+	}
+}
+
+// applyDownCooldown drops DOWN events of targets whose last DOWN alert was
+// sent less than downCooldown before, so a target flapping around its
+// threshold does not repeat the alert, whether or not a recovery was sent in
+// between. The earlier alert stands for the dropped one, so the recovery that
+// follows is a plain RECOVERED rather than an orphan.
+func (a *AlertManager) applyDownCooldown(events []alertEvent) []alertEvent {
+	if a.downCooldown <= 0 {
+		return events
+	}
+	kept := make([]alertEvent, 0, len(events))
+	for _, ev := range events {
+		if ev.Kind == "DOWN" {
+			last, ok := a.lastDown[ev.Target]
+			if ok && ev.Occurred.Sub(last) < a.downCooldown {
+				a.logger.Debug("DOWN alert suppressed by cooldown", "track", ev.Target, "last_down", last)
+				a.downSent[ev.Target] = true
+				continue
+			}
+			if ok {
+				delete(a.lastDown, ev.Target)
+			}
+		}
+		kept = append(kept, ev)
 	}
+	return kept
 }
 
 func (a *AlertManager) handleGroupSend(ctx context.Context, kind, reason string, group []alertEvent, message, key string) {
-	if kind == "DOWN" && reason == "state-change" && len(group) == 1 {
-		messageID, err := a.notifier.SendDefaultHTMLWithID(ctx, message)
+	// SLOW alerts are edited on recovery like DOWN ones, without an Ack button.
+	editable := (kind == "DOWN" && reason == "state-change") || kind == "SLOW"
+	sendEditable := a.sendDown
+	if kind == "SLOW" {
+		sendEditable = func(ctx context.Context, text string, _ []alertEvent) (int, error) {
+			return a.sendDefaultWithID(ctx, text)
+		}
+	}
+	if editable && len(group) == 1 {
+		messageID, err := sendEditable(ctx, message, group)
 		if err != nil {
 			a.logger.Warn("failed to send grouped alert", "key", key, "count", len(group), "error", err)
 			return
 		}
+		a.markDownSent(group)
 		if messageID > 0 {
 			ev := group[0]
 			a.pendingDown[ev.Target] = pendingDownAlert{
@@ -91,12 +445,13 @@ func (a *AlertManager) handleGroupSend(ctx context.Context, kind, reason string,
 		return
 	}
 
-	if kind == "DOWN" && reason == "state-change" && len(group) > 1 {
-		messageID, err := a.notifier.SendDefaultHTMLWithID(ctx, message)
+	if editable && len(group) > 1 {
+		messageID, err := sendEditable(ctx, message, group)
 		if err != nil {
 			a.logger.Warn("failed to send grouped alert", "key", key, "count", len(group), "error", err)
 			return
 		}
+		a.markDownSent(group)
 		if messageID > 0 {
 			pending := pendingDownGroup{
 				MessageID: messageID,
@@ -112,8 +467,18 @@ func (a *AlertManager) handleGroupSend(ctx context.Context, kind, reason string,
 		return
 	}
 
-	if err := a.notifier.SendDefaultHTML(ctx, message); err != nil {
+	var err error
+	if kind == "DOWN" {
+		_, err = a.sendDown(ctx, message, group)
+	} else {
+		err = a.sendDefault(ctx, message)
+	}
+	if err != nil {
 		a.logger.Warn("failed to send grouped alert", "key", key, "count", len(group), "error", err)
+		return
+	}
+	if kind == "DOWN" || kind == "STILL_DOWN" {
+		a.markDownSent(group)
 	}
 }
 
@@ -122,13 +487,13 @@ func (a *AlertManager) applyFastRecoveryEdits(ctx context.Context, events []aler
 	groupedRecoveries := make(map[string][]alertEvent)
 
 	for _, ev := range events {
-		if ev.Kind != "RECOVERED" || ev.Reason != "state-change" {
+		if ev.Kind != "RECOVERED" || (ev.Reason != "state-change" && ev.Reason != slowReason) {
 			remaining = append(remaining, ev)
 			continue
 		}
 
 		pending, ok := a.pendingDown[ev.Target]
-		if !ok {
+		if !ok || pending.MessageID == 0 {
 			groupedRecoveries[ev.Reason] = append(groupedRecoveries[ev.Reason], ev)
 			continue
 		}
@@ -140,7 +505,8 @@ func (a *AlertManager) applyFastRecoveryEdits(ctx context.Context, events []aler
 		}
 
 		editText := formatRecoveredEdit(ev, pending)
-		if err := a.notifier.EditDefaultHTML(ctx, pending.MessageID, editText); err != nil {
+		a.forgetAckable(pending.MessageID)
+		if err := a.editOrResend(ctx, pending.MessageID, editText); err != nil {
 			a.logger.Warn("failed to edit down alert message", "track", ev.Target, "error", err)
 			groupedRecoveries[ev.Reason] = append(groupedRecoveries[ev.Reason], ev)
 		}
@@ -171,7 +537,8 @@ func (a *AlertManager) applyFastRecoveryEdits(ctx context.Context, events []aler
 			}
 			if match {
 				consumedIdx = idx
-				if err := a.notifier.EditDefaultHTML(ctx, pending.MessageID, formatGroupedRecoveryEdit(pending, recovs)); err != nil {
+				a.forgetAckable(pending.MessageID)
+				if err := a.editOrResend(ctx, pending.MessageID, formatGroupedRecoveryEdit(pending, recovs)); err != nil {
 					a.logger.Warn("failed to edit grouped alert", "reason", reason, "error", err)
 					remaining = append(remaining, recovs...)
 				}
@@ -188,17 +555,35 @@ func (a *AlertManager) applyFastRecoveryEdits(ctx context.Context, events []aler
 	return remaining
 }
 
+// editOrResend edits the original DOWN message. When that message was deleted
+// in the chat the recovery, downtime included, is sent as a new message instead
+// of being lost.
+func (a *AlertManager) editOrResend(ctx context.Context, messageID int, text string) error {
+	err := a.notifier.EditDefaultHTML(ctx, messageID, text)
+	if err == nil || !isMessageNotFound(err) {
+		return err
+	}
+	a.logger.Info("down alert message is gone, sending recovery as new message", "message_id", messageID)
+	return a.sendDefault(ctx, text)
+}
+
+// isMessageNotFound matches Telegram's "message to edit not found" error.
+func isMessageNotFound(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "message to edit not found")
+}
+
 func formatRecoveredEdit(recovered alertEvent, pending pendingDownAlert) string {
 	downtime := recovered.Occurred.Sub(pending.DownAt)
 	if downtime < 0 {
 		downtime = 0
 	}
+	from := recoveredFrom(recovered.Reason)
 	var sb strings.Builder
-	sb.WriteString("<b>DOWN -> RECOVERED</b>\n")
+	fmt.Fprintf(&sb, "<b>%s -> RECOVERED</b>\n", from)
 	fmt.Fprintf(&sb, "reason: <code>%s</code>\n", util.HTMLEscape(recovered.Reason))
-	fmt.Fprintf(&sb, "down_at_utc: <code>%s</code>\n", pending.DownAt.Format(time.RFC3339))
+	fmt.Fprintf(&sb, "%s_at_utc: <code>%s</code>\n", strings.ToLower(from), pending.DownAt.Format(time.RFC3339))
 	fmt.Fprintf(&sb, "recovered_at_utc: <code>%s</code>\n", recovered.Occurred.Format(time.RFC3339))
-	fmt.Fprintf(&sb, "downtime: <code>%s</code>\n", formatDurationShort(downtime))
+	fmt.Fprintf(&sb, "%s: <code>%s</code>\n", outageLabel(from), formatDurationShort(downtime))
 	sb.WriteString("target:\n")
 	fmt.Fprintf(
 		&sb,
@@ -236,8 +621,9 @@ func formatGroupedRecoveryEdit(pending pendingDownGroup, recovs []alertEvent) st
 			latest = ev.Occurred
 		}
 	}
+	from := recoveredFrom(recovs[0].Reason)
 	var sb strings.Builder
-	fmt.Fprintf(&sb, "<b>DOWN -> RECOVERED x%d</b>\n", len(recovs))
+	fmt.Fprintf(&sb, "<b>%s -> RECOVERED x%d</b>\n", from, len(recovs))
 	fmt.Fprintf(&sb, "reason: <code>%s</code>\n", util.HTMLEscape(recovs[0].Reason))
 	fmt.Fprintf(&sb, "time_utc: <code>%s</code>\n", latest.Format(time.RFC3339))
 	sb.WriteString("targets:\n")
@@ -249,11 +635,12 @@ func formatGroupedRecoveryEdit(pending pendingDownGroup, recovs []alertEvent) st
 		}
 		fmt.Fprintf(
 			&sb,
-			"- <code>%s</code> (<code>%s:%d</code>)\nrecovered_at_utc: <code>%s</code>\ndowntime: <code>%s</code>\n",
+			"- <code>%s</code> (<code>%s:%d</code>)\nrecovered_at_utc: <code>%s</code>\n%s: <code>%s</code>\n",
 			util.HTMLEscape(ev.Target),
 			util.HTMLEscape(ev.Address),
 			ev.Port,
 			ev.Occurred.Format(time.RFC3339),
+			outageLabel(from),
 			formatDurationShort(downtime),
 		)
 	}
@@ -273,26 +660,83 @@ func formatAlertGroup(events []alertEvent) string {
 	}
 	fmt.Fprintf(&sb, "reason: <code>%s</code>\n", util.HTMLEscape(first.Reason))
 	fmt.Fprintf(&sb, "time_utc: <code>%s</code>\n", first.Occurred.Format(time.RFC3339))
+	// SLOW details are per target latencies, listed with each target.
+	if first.Detail != "" && first.Kind != "SLOW" {
+		fmt.Fprintf(&sb, "detail: <code>%s</code>\n", util.HTMLEscape(first.Detail))
+	}
+	if len(events) == 1 && first.FailedChecks > 0 {
+		fmt.Fprintf(&sb, "failing: <code>%s</code>\n", failureStreak(first))
+	}
+	if first.Target == "" {
+		return strings.TrimSuffix(sb.String(), "\n")
+	}
 	sb.WriteString("targets:\n")
 	for _, event := range events {
 		fmt.Fprintf(
 			&sb,
-			"- <code>%s</code> (<code>%s:%d</code>)\n",
+			"- <code>%s</code> (<code>%s:%d</code>)",
 			util.HTMLEscape(event.Target),
 			util.HTMLEscape(event.Address),
 			event.Port,
 		)
+		if event.Kind == "SLOW" && event.Detail != "" {
+			fmt.Fprintf(&sb, " <code>%s</code>", util.HTMLEscape(event.Detail))
+		}
+		if len(events) > 1 && event.FailedChecks > 1 {
+			fmt.Fprintf(&sb, " <code>%dx/%s</code>", event.FailedChecks, streakDuration(event))
+		}
+		if event.RunbookURL != "" {
+			fmt.Fprintf(&sb, " <a href=\"%s\">runbook</a>", util.HTMLAttrEscape(event.RunbookURL))
+		}
+		sb.WriteString("\n")
+	}
+	if first.Kind == "DOWN" || first.Kind == "SLOW" {
+		if mentions := distinctMentions(events); len(mentions) > 0 {
+			fmt.Fprintf(&sb, "cc: %s\n", util.HTMLEscape(strings.Join(mentions, " ")))
+		}
 	}
 	return strings.TrimSuffix(sb.String(), "\n")
 }
 
+// failureStreak tells an instant failure apart from a gradual one.
+func failureStreak(event alertEvent) string {
+	if event.FailedChecks == 1 {
+		return "down on the first failed check"
+	}
+	return fmt.Sprintf("down for %d consecutive checks over %s", event.FailedChecks, streakDuration(event))
+}
+
+func streakDuration(event alertEvent) time.Duration {
+	return event.Occurred.Sub(event.FailingSince).Round(time.Second)
+}
+
+func distinctMentions(events []alertEvent) []string {
+	seen := make(map[string]struct{}, len(events))
+	out := make([]string, 0, len(events))
+	for _, event := range events {
+		if event.Mention == "" {
+			continue
+		}
+		if _, ok := seen[event.Mention]; ok {
+			continue
+		}
+		seen[event.Mention] = struct{}{}
+		out = append(out, event.Mention)
+	}
+	return out
+}
+
 func alertOrder(kind string) int {
 	switch kind {
 	case "DOWN":
 		return 0
-	case "RECOVERED":
+	case "STILL_DOWN":
 		return 1
-	default:
+	case "SLOW":
 		return 2
+	case "RECOVERED", "RESOLVED", "NOW_UP":
+		return 3
+	default:
+		return 4
 	}
 }
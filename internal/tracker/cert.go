@@ -0,0 +1,128 @@
+package tracker
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// certExpiryInterval spaces the expiry checks of a target: certificates
+// change rarely, and the check verifies the whole chain.
+const certExpiryInterval = time.Hour
+
+// checkCerts runs the certificate checks due for a target after a successful
+// check, over a single TLS handshake: the fingerprint on every check with
+// WatchCert, the expiry at most once per certExpiryInterval with CertWarnDays.
+func (e *MonitorEngine) checkCerts(ctx context.Context, target *TargetState, timeout time.Duration, now time.Time) []alertEvent {
+	e.mu.RLock()
+	watch := target.WatchCert
+	expiryDue := target.CertWarnDays > 0 && now.Sub(target.certCheckedAt) >= certExpiryInterval
+	name, address, port := target.Name, target.Address, target.Port
+	e.mu.RUnlock()
+	if !watch && !expiryDue {
+		return nil
+	}
+
+	certs, err := e.probe.fetchPeerCertificates(ctx, address, port, timeout)
+	if err != nil {
+		e.logger.Debug("certificate unavailable", "track", name, "error", err)
+		return nil
+	}
+	var events []alertEvent
+	if watch {
+		if event := e.watchCert(target, certs); event != nil {
+			events = append(events, *event)
+		}
+	}
+	if expiryDue {
+		if event := e.checkCertExpiry(target, certs, now); event != nil {
+			events = append(events, *event)
+		}
+	}
+	return events
+}
+
+// watchCert compares the fingerprint of the leaf of certs, served by a target
+// with WatchCert, with the stored one and returns CERT_CHANGED when it
+// differs. The first fingerprint is only stored, so enabling the option or
+// restarting does not alert.
+func (e *MonitorEngine) watchCert(target *TargetState, certs []*x509.Certificate) *alertEvent {
+	e.mu.RLock()
+	name, address, port := target.Name, target.Address, target.Port
+	impact, critical := target.Impact, target.Critical
+	previous := target.CertFingerprint
+	e.mu.RUnlock()
+
+	fingerprint := certFingerprint(certs[0])
+	if fingerprint == previous {
+		return nil
+	}
+	if err := e.logs.SetTargetCertFingerprint(name, fingerprint); err != nil {
+		e.logger.Warn("failed to store certificate fingerprint", "track", name, "error", err)
+		return nil
+	}
+	e.mu.Lock()
+	target.CertFingerprint = fingerprint
+	e.mu.Unlock()
+	if previous == "" {
+		e.logger.Info("stored first certificate fingerprint", "track", name, "fingerprint", fingerprint)
+		return nil
+	}
+
+	e.logger.Warn("certificate fingerprint changed", "track", name, "old", previous, "new", fingerprint)
+	return &alertEvent{
+		Kind:     "CERT_CHANGED",
+		Target:   name,
+		Address:  address,
+		Port:     port,
+		Reason:   fmt.Sprintf("sha256 %s -> %s", shortFingerprint(previous), shortFingerprint(fingerprint)),
+		Detail:   fmt.Sprintf("old %s new %s", previous, fingerprint),
+		Occurred: time.Now().UTC(),
+		Impact:   impact,
+		Critical: critical,
+	}
+}
+
+// certFingerprint returns the hex SHA-256 of a certificate. The chain is not
+// verified: the point is to notice any change, including to a certificate
+// that would not verify.
+func certFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// fetchPeerCertificates returns the chain served by the target, leaf first,
+// without verifying it.
+func (p probeOptions) fetchPeerCertificates(ctx context.Context, address string, port int, timeout time.Duration) ([]*x509.Certificate, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	if net.ParseIP(address) == nil {
+		tlsConfig.ServerName = address
+	}
+	dialer := &tls.Dialer{NetDialer: p.dialer(), Config: tlsConfig}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(address, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, errors.New("no peer certificate")
+	}
+	return certs, nil
+}
+
+func shortFingerprint(fingerprint string) string {
+	if len(fingerprint) <= 16 {
+		return fingerprint
+	}
+	return fingerprint[:16]
+}
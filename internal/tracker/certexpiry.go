@@ -0,0 +1,90 @@
+package tracker
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// checkCertExpiry verifies certs, served by a target with CertWarnDays, and
+// returns CERT_INVALID when the chain does not verify or CERT_EXPIRING when
+// fewer than CertWarnDays days are left. Each condition alerts once, when it
+// starts.
+func (e *MonitorEngine) checkCertExpiry(target *TargetState, certs []*x509.Certificate, now time.Time) *alertEvent {
+	e.mu.RLock()
+	warnDays := target.CertWarnDays
+	name, address, port := target.Name, target.Address, target.Port
+	impact, critical := target.Impact, target.Critical
+	roots := e.certRoots
+	e.mu.RUnlock()
+	if warnDays <= 0 {
+		return nil
+	}
+
+	leaf := certs[0]
+	daysLeft := int(leaf.NotAfter.Sub(now).Hours() / 24)
+	var kind, reason string
+	if problem := verifyCertChain(certs, address, roots, now); problem != "" {
+		kind, reason = "CERT_INVALID", problem
+	} else if leaf.NotAfter.Sub(now) < time.Duration(warnDays)*24*time.Hour {
+		kind, reason = "CERT_EXPIRING", fmt.Sprintf("%dd left", daysLeft)
+	}
+
+	e.mu.Lock()
+	target.certCheckedAt = now
+	target.CertNotAfter = leaf.NotAfter
+	previous := target.certAlert
+	target.certAlert = kind
+	e.mu.Unlock()
+	if kind == "" || kind == previous {
+		return nil
+	}
+
+	e.logger.Warn("certificate problem", "track", name, "kind", kind, "reason", reason, "not_after", leaf.NotAfter)
+	return &alertEvent{
+		Kind:     kind,
+		Target:   name,
+		Address:  address,
+		Port:     port,
+		Reason:   reason,
+		Detail:   fmt.Sprintf("expires %s, subject %s", leaf.NotAfter.UTC().Format(time.RFC3339), leaf.Subject.CommonName),
+		Occurred: now,
+		Impact:   impact,
+		Critical: critical,
+	}
+}
+
+// verifyCertChain returns why the chain does not verify for address, or ""
+// when it does. roots nil means the system pool.
+func verifyCertChain(certs []*x509.Certificate, address string, roots *x509.CertPool, now time.Time) string {
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		DNSName:       address,
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+	})
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &unknownAuthority):
+		if bytes.Equal(certs[0].RawIssuer, certs[0].RawSubject) {
+			return "self-signed"
+		}
+		return "unknown-authority"
+	case errors.As(err, &hostname):
+		return "hostname-mismatch"
+	case errors.As(err, &invalid) && invalid.Reason == x509.Expired:
+		return "expired"
+	default:
+		return "invalid-chain"
+	}
+}
@@ -1,22 +1,82 @@
 package tracker
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-telegram/bot/models"
 
+	"trackway/internal/config"
 	"trackway/internal/logstore"
 	"trackway/internal/util"
 )
 
+const (
+	defaultRecentChanges = 20
+	maxRecentChanges     = 200
+)
+
+const (
+	defaultGraphHours = 24
+	maxGraphHours     = 30 * 24
+)
+
+const (
+	defaultChangesWindow = 15 * time.Minute
+	maxChangesWindow     = 24 * time.Hour
+)
+
+const maxIncidentLines = 30
+
+// /export matches the dashboard logs defaults and clamping.
+const (
+	maxExportDays = 365
+	maxExportRows = 5000
+)
+
+const noTargetsText = "No tracks configured yet.\nAdd one with /add &lt;name&gt; &lt;address&gt; &lt;port&gt;, from the dashboard targets form (/authme for a login link) or under <code>targets</code> in the config."
+
 type QueryProvider interface {
 	Snapshot() Snapshot
+	NextChecks(trackName string) ([]NextCheck, bool)
 	Logs(trackName string, days int, limit int) ([]logstore.Row, bool)
+	GraphRows(trackName string, from time.Time, bucket time.Duration) ([]logstore.Row, bool)
+	Diagnostics() Diagnostics
+	ExportTargets() []config.Target
+	SLA(trackName string, days int) ([]SLAReport, bool)
+	OverallSLA(days int) (OverallSLAReport, bool)
+	Compare(trackName string, days int) (WindowComparison, bool)
+	Incidents(trackName string, days int) ([]logstore.Incident, bool)
+	ActiveMaintenance(now time.Time) []ActiveMaintenance
+	Ping(ctx context.Context, trackName string, count int) (PingResult, bool)
+	RecentChanges(limit int) []logstore.TargetRow
+	Mute(trackName string, until time.Time) (time.Time, error)
+	Unmute(trackName string) error
+	SetThreshold(trackName, kind string, value int) (int, int, error)
+	UpsertTarget(name, address string, port int) error
+	DeleteTarget(name string) error
+	Settings() MonitoringSettings
+	UpdateSettings(values map[string]int) (MonitoringSettings, error)
+}
+
+// DocumentSender is implemented by notifiers that can upload files, which
+// /export needs.
+type DocumentSender interface {
+	SendDocument(ctx context.Context, chatID int64, filename string, data []byte, caption string) error
+}
+
+// PhotoSender is implemented by notifiers that can send images, which /graph
+// uses to send its timeline as a PNG.
+type PhotoSender interface {
+	SendPhoto(ctx context.Context, chatID int64, filename string, data []byte, caption string) error
 }
 
 type CommandHandler struct {
@@ -25,17 +85,28 @@ type CommandHandler struct {
 	logger   *slog.Logger
 
 	allowedChat int64
+	queue       *updateQueue
+	// graphWidth and graphHeight size the /graph image.
+	graphWidth  int
+	graphHeight int
 
 	mu         sync.RWMutex
 	authLinkFn func() (string, error)
+	resendFn   func(ctx context.Context) (int, error)
+	ackFn      func(ctx context.Context, messageID int, by string) (string, error)
+	pauser     AlertPauser
+	logLevel   *logLevelControl
 }
 
-func NewCommandHandler(allowedChat int64, source QueryProvider, notifier Notifier) *CommandHandler {
+// NewCommandHandler buffers up to queueSize updates for RunUpdates; 0 means
+// config.DefaultUpdateQueueSize.
+func NewCommandHandler(allowedChat int64, source QueryProvider, notifier Notifier, queueSize int) *CommandHandler {
 	return &CommandHandler{
 		notifier:    notifier,
 		source:      source,
 		logger:      slog.Default(),
 		allowedChat: allowedChat,
+		queue:       newUpdateQueue(queueSize),
 	}
 }
 
@@ -45,7 +116,26 @@ func (h *CommandHandler) SetAuthLinkGenerator(fn func() (string, error)) {
 	h.authLinkFn = fn
 }
 
+// SetResender enables /resend with the given replay of the last alert.
+func (h *CommandHandler) SetResender(fn func(ctx context.Context) (int, error)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.resendFn = fn
+}
+
+// SetLogLevelVar enables /loglevel to adjust the given level at runtime. Its
+// current value is the level restored after each temporary change.
+func (h *CommandHandler) SetLogLevelVar(level *slog.LevelVar) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.logLevel = newLogLevelControl(level)
+}
+
 func (h *CommandHandler) HandleUpdate(ctx context.Context, update *models.Update) {
+	if update.CallbackQuery != nil {
+		h.handleCallback(ctx, update.CallbackQuery)
+		return
+	}
 	msg := update.Message
 	if msg == nil || msg.Text == "" {
 		return
@@ -54,6 +144,7 @@ func (h *CommandHandler) HandleUpdate(ctx context.Context, update *models.Update
 	if !ok {
 		return
 	}
+	args := parseCommandArgs(msg.Text)
 	if !h.isChatAllowed(msg.Chat.ID) {
 		if h.notifier != nil {
 			_ = h.notifier.SendHTML(ctx, msg.Chat.ID, "This bot command is not available in this chat.")
@@ -68,9 +159,74 @@ func (h *CommandHandler) HandleUpdate(ctx context.Context, update *models.Update
 	case "list":
 		response = h.listText()
 	case "status":
-		response = h.statusText()
+		if arg == "" {
+			response = h.statusText()
+		} else {
+			response = h.targetStatusText(arg)
+		}
+		response = h.pauseBanner(time.Now()) + response
 	case "authme":
 		response = h.authLinkText(msg.Chat.ID)
+	case "diag":
+		response = h.diagText()
+	case "sla":
+		response = h.slaText(args)
+	case "mute", "unmute":
+		response = h.muteText(command, args)
+	case "add", "remove":
+		response = h.manageTargetText(msg.Chat.ID, command, args)
+	case "pause", "resume":
+		response = h.pauseText(ctx, msg.Chat.ID, command, args)
+	case "changes":
+		response = h.changesText(args)
+	case "next":
+		response = h.nextText(arg, time.Now())
+	case "graph":
+		response = h.sendGraph(ctx, msg.Chat.ID, args)
+		if response == "" {
+			return
+		}
+	case "compare":
+		response = h.compareText(args)
+	case "incidents":
+		response = h.incidentsText(args, time.Now())
+	case "maintenance":
+		response = h.maintenanceText(time.Now())
+	case "threshold":
+		response = h.thresholdText(args)
+	case "config":
+		response = h.configText(args)
+	case "loglevel":
+		response = h.logLevelText(args)
+	case "resend":
+		response = h.resendText(ctx)
+	case "ping", "pingtest":
+		response = h.pingText(ctx, args)
+	case "exporttargets":
+		if h.notifier == nil {
+			return
+		}
+		for _, message := range h.exportTargetsMessages(arg) {
+			if err := h.notifier.SendHTML(ctx, msg.Chat.ID, message); err != nil {
+				h.logger.Warn("failed to send targets export", "error", err)
+			}
+		}
+		return
+	case "export":
+		response = h.exportLogs(ctx, msg.Chat.ID, args)
+		if response == "" {
+			return
+		}
+	case "recent":
+		if h.notifier == nil {
+			return
+		}
+		for _, message := range h.recentMessages(args) {
+			if err := h.notifier.SendHTML(ctx, msg.Chat.ID, message); err != nil {
+				h.logger.Warn("failed to send recent changes", "error", err)
+			}
+		}
+		return
 	case "logs":
 		if arg == "" {
 			response = "Usage: /logs &lt;track_name&gt;"
@@ -100,7 +256,7 @@ func (h *CommandHandler) HandleUpdate(ctx context.Context, update *models.Update
 func (h *CommandHandler) listText() string {
 	snapshot := h.source.Snapshot()
 	if len(snapshot.Targets) == 0 {
-		return "No tracks configured."
+		return noTargetsText
 	}
 
 	targets := append([]TargetSnapshot(nil), snapshot.Targets...)
@@ -124,37 +280,139 @@ func (h *CommandHandler) listText() string {
 func (h *CommandHandler) statusText() string {
 	snapshot := h.source.Snapshot()
 	if len(snapshot.Targets) == 0 {
-		return "No tracks configured."
+		return noTargetsText
 	}
+	return renderStatus("<b>Status snapshot (UTC)</b>", snapshot)
+}
 
+func renderStatus(title string, snapshot Snapshot) string {
 	targets := append([]TargetSnapshot(nil), snapshot.Targets...)
 	sort.Slice(targets, func(i, j int) bool { return targets[i].Name < targets[j].Name })
 
 	var sb strings.Builder
 	fmt.Fprintf(
 		&sb,
-		"<b>Status snapshot (UTC)</b>\ntracks: %d | up: %d | down: %d | unknown: %d\n\n",
+		"%s\ntracks: %d | up: %d | down: %d | unknown: %d\n\n",
+		title,
 		snapshot.Total,
 		snapshot.Up,
 		snapshot.Down,
 		snapshot.Unknown,
 	)
 	for i, target := range targets {
-		fmt.Fprintf(
-			&sb,
-			"%d. <b>%s</b>\nendpoint: <code>%s:%d</code>\nstate: <b>%s</b>\nchanged: <code>%s</code>\nchecked: <code>%s</code>\n\n",
-			i+1,
-			util.HTMLEscape(target.Name),
-			util.HTMLEscape(target.Address),
-			target.Port,
-			target.Status,
-			util.FormatTime(target.LastChanged),
-			util.FormatTime(target.LastChecked),
-		)
+		fmt.Fprintf(&sb, "%d. ", i+1)
+		writeTargetStatus(&sb, target, snapshot.GeneratedAt)
+		sb.WriteString("\n")
 	}
 	return sb.String()
 }
 
+// targetStatusText shows the target named trackName, or else the targets
+// tagged with it.
+func (h *CommandHandler) targetStatusText(trackName string) string {
+	snapshot := h.source.Snapshot()
+	for _, target := range snapshot.Targets {
+		if target.Name != trackName {
+			continue
+		}
+		var sb strings.Builder
+		writeTargetStatus(&sb, target, snapshot.GeneratedAt)
+		return sb.String()
+	}
+	if tagged := snapshot.WithTag(trackName); tagged.Total > 0 {
+		return renderStatus(fmt.Sprintf("<b>Status snapshot (UTC)</b> | tag: <b>%s</b>", util.HTMLEscape(strings.ToLower(trackName))), tagged)
+	}
+	return "Track not found. Use /list."
+}
+
+func writeTargetStatus(sb *strings.Builder, target TargetSnapshot, now time.Time) {
+	muted := ""
+	if !target.MutedUntil.IsZero() {
+		muted = " (muted)"
+	}
+	fmt.Fprintf(
+		sb,
+		"<b>%s</b>%s\nendpoint: <code>%s:%d</code>\nstate: <b>%s</b>\nchanged: <code>%s</code>\nchecked: <code>%s</code>\n",
+		util.HTMLEscape(target.Name),
+		muted,
+		util.HTMLEscape(target.Address),
+		target.Port,
+		target.Status,
+		util.FormatTime(target.LastChanged),
+		util.FormatTime(target.LastChecked),
+	)
+	if !target.LastChanged.IsZero() && now.After(target.LastChanged) {
+		fmt.Fprintf(sb, "for: <code>%s</code>\n", formatDurationShort(now.Sub(target.LastChanged)))
+	}
+	if target.LastLatency > 0 {
+		fmt.Fprintf(sb, "last: <code>%s</code>\n", formatLatency(target.LastLatency))
+	}
+	if target.ResolvedIP != "" {
+		fmt.Fprintf(sb, "resolved: <code>%s</code> (first of %d)\n", util.HTMLEscape(target.ResolvedIP), target.ResolvedAddrs)
+	}
+	if !target.CertNotAfter.IsZero() {
+		fmt.Fprintf(sb, "cert expires: <code>%s</code> (%dd)\n", util.FormatTime(target.CertNotAfter), int(target.CertNotAfter.Sub(now).Hours()/24))
+	}
+	if !target.MutedUntil.IsZero() {
+		fmt.Fprintf(sb, "muted until: <code>%s</code>\n", util.FormatTime(target.MutedUntil))
+	}
+	if len(target.Tags) > 0 {
+		fmt.Fprintf(sb, "tags: %s\n", util.HTMLEscape(strings.Join(target.Tags, ", ")))
+	}
+	if target.AckedBy != "" {
+		fmt.Fprintf(sb, "acked: %s at <code>%s</code>\n", util.HTMLEscape(target.AckedBy), util.FormatTime(target.AckedAt))
+	}
+}
+
+func formatLatency(latency time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(latency.Microseconds())/1000)
+}
+
+// exportLogs uploads the log rows of a target as CSV to chatID. It returns
+// the reply to send instead, or "" once the file is sent.
+func (h *CommandHandler) exportLogs(ctx context.Context, chatID int64, args []string) string {
+	const usage = "Usage: /export &lt;track&gt; [days]"
+	if len(args) == 0 || len(args) > 2 {
+		return usage
+	}
+	days := 7
+	if len(args) == 2 {
+		value, err := strconv.Atoi(args[1])
+		if err != nil || value < 1 {
+			return usage
+		}
+		days = min(value, maxExportDays)
+	}
+	sender, ok := h.notifier.(DocumentSender)
+	if !ok {
+		return "File uploads are not available."
+	}
+	rows, ok := h.source.Logs(args[0], days, maxExportRows)
+	if !ok {
+		return "Track not found. Use /list."
+	}
+
+	var buf bytes.Buffer
+	if err := logstore.WriteCSV(&buf, rows); err != nil {
+		return "Failed to build the export."
+	}
+	caption := fmt.Sprintf("<b>%s</b> logs, last %d days, %d rows", util.HTMLEscape(args[0]), days, len(rows))
+	if len(rows) >= maxExportRows {
+		caption = fmt.Sprintf(
+			"<b>%s</b> logs, newest %d rows since %s (truncated; the last %d days hold more)",
+			util.HTMLEscape(args[0]),
+			len(rows),
+			rows[0].Timestamp,
+			days,
+		)
+	}
+	if err := sender.SendDocument(ctx, chatID, util.SafeName(args[0])+"-logs.csv", buf.Bytes(), caption); err != nil {
+		h.logger.Warn("failed to send logs export", "track", args[0], "error", err)
+		return "Failed to send the export."
+	}
+	return ""
+}
+
 func (h *CommandHandler) logsMessages(trackName string) []string {
 	rows, ok := h.source.Logs(trackName, 7, 120)
 	if !ok {
@@ -168,9 +426,9 @@ func (h *CommandHandler) logsMessages(trackName string) []string {
 	for _, row := range rows {
 		switch row.Status {
 		case "UP":
-			upCount++
+			upCount += row.Checks()
 		case "DOWN":
-			downCount++
+			downCount += row.Checks()
 		}
 	}
 
@@ -184,6 +442,677 @@ func (h *CommandHandler) logsMessages(trackName string) []string {
 	return renderLogChunks(header, rows)
 }
 
+func (h *CommandHandler) recentMessages(args []string) []string {
+	limit := defaultRecentChanges
+	if len(args) > 0 {
+		value, err := strconv.Atoi(args[0])
+		if err != nil || value <= 0 {
+			return []string{"Usage: /recent [count]"}
+		}
+		limit = min(value, maxRecentChanges)
+	}
+
+	rows := h.source.RecentChanges(limit)
+	if len(rows) == 0 {
+		return []string{"No state changes recorded yet."}
+	}
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		lines = append(lines, fmt.Sprintf("%s  %-4s  %s  %s", row.Timestamp, row.Status, row.Target, row.Reason))
+	}
+	return renderPreChunks(fmt.Sprintf("<b>Recent changes</b> (latest %d)", len(rows)), lines)
+}
+
+func (h *CommandHandler) graphText(args []string) string {
+	now := time.Now().UTC()
+	track, hours, rows, reply := h.graphRows(args, now, graphColumns)
+	if reply != "" {
+		return reply
+	}
+	window := time.Duration(hours) * time.Hour
+	graph := buildUptimeGraph(rows, now, window, graphColumns)
+	return fmt.Sprintf(
+		"Track: <b>%s</b> | last %dh, %s per column\n<pre>%s\n-%dh%snow</pre>\n%c up  %c down  %c no data",
+		util.HTMLEscape(track),
+		hours,
+		formatDurationShort(window/graphColumns),
+		graph,
+		hours,
+		strings.Repeat(" ", max(graphColumns-len(strconv.Itoa(hours))-5, 1)),
+		graphUp,
+		graphDown,
+		graphUnknown,
+	)
+}
+
+// sendGraph sends the /graph timeline as a PNG to chatID. It returns the
+// reply to send instead, or "" once the image is sent; without photo
+// support, or when the upload fails, that is the text timeline.
+func (h *CommandHandler) sendGraph(ctx context.Context, chatID int64, args []string) string {
+	sender, ok := h.notifier.(PhotoSender)
+	if !ok {
+		return h.graphText(args)
+	}
+	width, height := h.graphWidth, h.graphHeight
+	if width <= 0 || height <= 0 {
+		width, height = config.DefaultGraphWidth, config.DefaultGraphHeight
+	}
+	now := time.Now().UTC()
+	track, hours, rows, reply := h.graphRows(args, now, graphImageColumns(width))
+	if reply != "" {
+		return reply
+	}
+	data, err := renderUptimePNG(rows, now, time.Duration(hours)*time.Hour, width, height)
+	if err != nil {
+		h.logger.Warn("failed to render graph", "track", track, "error", err)
+		return h.graphText(args)
+	}
+	caption := fmt.Sprintf("Track: <b>%s</b> | last %s\ngreen up, red down, gray no data", util.HTMLEscape(track), formatGraphWindow(hours))
+	if err := sender.SendPhoto(ctx, chatID, util.SafeName(track)+"-graph.png", data, caption); err != nil {
+		h.logger.Warn("failed to send graph", "track", track, "error", err)
+		return h.graphText(args)
+	}
+	return ""
+}
+
+// graphRows parses "/graph <track> [hours|<days>d]" and reads the window
+// ending at now, condensed to the columns it is drawn with. reply is set to
+// the usage or not-found text on failure.
+func (h *CommandHandler) graphRows(args []string, now time.Time, columns int) (track string, hours int, rows []logstore.Row, reply string) {
+	const usage = "Usage: /graph &lt;track&gt; [hours|&lt;days&gt;d]"
+	if len(args) == 0 || len(args) > 2 {
+		return "", 0, nil, usage
+	}
+	hours = defaultGraphHours
+	if len(args) == 2 {
+		value, ok := parseGraphHours(args[1])
+		if !ok {
+			return "", 0, nil, usage
+		}
+		hours = min(value, maxGraphHours)
+	}
+	window := time.Duration(hours) * time.Hour
+	rows, ok := h.source.GraphRows(args[0], now.Add(-window), window/time.Duration(columns))
+	if !ok {
+		return "", 0, nil, "Track not found. Use /list."
+	}
+	return args[0], hours, rows, ""
+}
+
+// parseGraphHours reads a /graph window: a plain number of hours, or days
+// with a "d" suffix.
+func parseGraphHours(value string) (int, bool) {
+	days, isDays := strings.CutSuffix(strings.ToLower(value), "d")
+	if !isDays {
+		days = strings.TrimSuffix(strings.ToLower(value), "h")
+	}
+	n, err := strconv.Atoi(days)
+	if err != nil || n <= 0 || n > maxGraphHours {
+		return 0, false
+	}
+	if isDays {
+		return n * 24, true
+	}
+	return n, true
+}
+
+func formatGraphWindow(hours int) string {
+	if hours%24 == 0 {
+		return fmt.Sprintf("%dd", hours/24)
+	}
+	return fmt.Sprintf("%dh", hours)
+}
+
+// maintenanceText lists the maintenance windows in effect.
+func (h *CommandHandler) maintenanceText(now time.Time) string {
+	windows := h.source.ActiveMaintenance(now)
+	if len(windows) == 0 {
+		return "No active maintenance windows."
+	}
+	var sb strings.Builder
+	sb.WriteString("<b>Maintenance</b>")
+	for _, window := range windows {
+		targets := "all targets"
+		if len(window.Targets) > 0 {
+			targets = strings.Join(window.Targets, ", ")
+		}
+		fmt.Fprintf(
+			&sb,
+			"\n<b>%s</b> until <code>%s</code> (%s left): %s",
+			util.HTMLEscape(window.Name),
+			util.FormatTime(window.Until),
+			formatDurationShort(window.Until.Sub(now)),
+			util.HTMLEscape(targets),
+		)
+	}
+	return sb.String()
+}
+
+// incidentsText lists a target's outages, newest first.
+func (h *CommandHandler) incidentsText(args []string, now time.Time) string {
+	const usage = "Usage: /incidents &lt;track&gt; [days]"
+	if len(args) == 0 || len(args) > 2 {
+		return usage
+	}
+	days := 7
+	if len(args) == 2 {
+		value, err := strconv.Atoi(args[1])
+		if err != nil || value <= 0 {
+			return usage
+		}
+		days = value
+	}
+	incidents, ok := h.source.Incidents(args[0], days)
+	if !ok {
+		return "Track not found. Use /list."
+	}
+	header := fmt.Sprintf("Track: <b>%s</b> | incidents, last %dd: %d", util.HTMLEscape(args[0]), clampDays(days), len(incidents))
+	if len(incidents) == 0 {
+		return header + "\nNo outages."
+	}
+
+	lines := make([]string, 0, min(len(incidents), maxIncidentLines)+1)
+	for i, incident := range incidents {
+		if i == maxIncidentLines {
+			lines = append(lines, fmt.Sprintf("... %d older", len(incidents)-i))
+			break
+		}
+		end := "ongoing"
+		duration := now.Sub(incident.Start)
+		if !incident.Ongoing {
+			end = util.FormatTime(incident.End)
+			duration = incident.Duration
+		}
+		lines = append(lines, fmt.Sprintf("%s -> %s %s", util.FormatTime(incident.Start), end, formatDurationShort(duration)))
+	}
+	return header + "\n<pre>" + strings.Join(lines, "\n") + "</pre>"
+}
+
+// compareText reports a target's last days against the equal window before.
+func (h *CommandHandler) compareText(args []string) string {
+	const usage = "Usage: /compare &lt;track&gt; [days]"
+	if len(args) == 0 || len(args) > 2 {
+		return usage
+	}
+	days := 7
+	if len(args) == 2 {
+		value, err := strconv.Atoi(args[1])
+		if err != nil || value <= 0 {
+			return usage
+		}
+		days = value
+	}
+	comparison, ok := h.source.Compare(args[0], days)
+	if !ok {
+		return "Track not found. Use /list."
+	}
+
+	current, previous := comparison.Current, comparison.Previous
+	uptimeDelta := "n/a"
+	if current.Checks > 0 && previous.Checks > 0 {
+		uptimeDelta = fmt.Sprintf("%+.2fpp", current.UptimePercent-previous.UptimePercent)
+	}
+	lines := []string{
+		fmt.Sprintf("%-9s %-10s %-10s %s", "", "current", "previous", "delta"),
+		fmt.Sprintf("%-9s %-10s %-10s %s", "uptime", formatWindowUptime(current), formatWindowUptime(previous), uptimeDelta),
+		fmt.Sprintf("%-9s %-10d %-10d %+d", "outages", current.Outages, previous.Outages, current.Outages-previous.Outages),
+		fmt.Sprintf(
+			"%-9s %-10s %-10s %s",
+			"downtime",
+			formatDurationShort(current.Downtime),
+			formatDurationShort(previous.Downtime),
+			formatDurationDelta(current.Downtime-previous.Downtime),
+		),
+	}
+	return fmt.Sprintf(
+		"Track: <b>%s</b> | last %dd vs previous %dd\n<pre>%s</pre>",
+		util.HTMLEscape(comparison.Target),
+		comparison.Days,
+		comparison.Days,
+		strings.Join(lines, "\n"),
+	)
+}
+
+func formatWindowUptime(stats WindowStats) string {
+	if stats.Checks == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f%%", stats.UptimePercent)
+}
+
+func formatDurationDelta(d time.Duration) string {
+	if d < 0 {
+		return "-" + formatDurationShort(-d)
+	}
+	return "+" + formatDurationShort(d)
+}
+
+// thresholdText shows or sets the consecutive failed/successful checks needed
+// to flip a target's state.
+func (h *CommandHandler) thresholdText(args []string) string {
+	const usage = "Usage: /threshold &lt;track&gt; [fail|success &lt;n&gt;]"
+	if len(args) != 1 && len(args) != 3 {
+		return usage
+	}
+	trackName := args[0]
+	var fail, success int
+	if len(args) == 1 {
+		found := false
+		for _, target := range h.source.Snapshot().Targets {
+			if target.Name == trackName {
+				fail, success, found = target.FailThreshold, target.SuccessThreshold, true
+				break
+			}
+		}
+		if !found {
+			return "Track not found. Use /list."
+		}
+	} else {
+		kind := strings.ToLower(args[1])
+		value, err := strconv.Atoi(args[2])
+		if (kind != "fail" && kind != "success") || err != nil {
+			return usage
+		}
+		fail, success, err = h.source.SetThreshold(trackName, kind, value)
+		if errors.Is(err, ErrTargetNotFound) {
+			return "Track not found. Use /list."
+		}
+		if err != nil {
+			return fmt.Sprintf("Threshold not changed: %s", util.HTMLEscape(err.Error()))
+		}
+	}
+	return fmt.Sprintf(
+		"<b>%s</b> thresholds\nfail: <code>%d</code> consecutive failed checks\nsuccess: <code>%d</code> consecutive successful checks",
+		util.HTMLEscape(trackName),
+		max(fail, 1),
+		max(success, 1),
+	)
+}
+
+// configText shows the runtime monitoring settings or changes one of them.
+func (h *CommandHandler) configText(args []string) string {
+	const usage = "Usage: /config [&lt;key&gt; &lt;value&gt;]"
+	var settings MonitoringSettings
+	switch len(args) {
+	case 0:
+		settings = h.source.Settings()
+	case 2:
+		value, err := strconv.Atoi(args[1])
+		if err != nil {
+			return usage
+		}
+		settings, err = h.source.UpdateSettings(map[string]int{strings.ToLower(args[0]): value})
+		if err != nil {
+			return fmt.Sprintf("Setting not changed: %s", util.HTMLEscape(err.Error()))
+		}
+	default:
+		return usage
+	}
+
+	values := settings.byKey()
+	var sb strings.Builder
+	sb.WriteString("<b>Monitoring settings</b>")
+	for _, key := range SettingKeys() {
+		valid := settingRanges[key]
+		fmt.Fprintf(&sb, "\n%s: <code>%d</code> (%d-%d)", key, values[key], valid.min, valid.max)
+	}
+	return sb.String()
+}
+
+// changesText lists targets whose state changed within the window, newest
+// first, from the in-memory snapshot rather than the logs.
+func (h *CommandHandler) changesText(args []string) string {
+	window := defaultChangesWindow
+	if len(args) > 0 {
+		minutes, err := strconv.Atoi(args[0])
+		if err != nil || minutes <= 0 {
+			return "Usage: /changes [minutes]"
+		}
+		window = min(time.Duration(minutes)*time.Minute, maxChangesWindow)
+	}
+
+	snapshot := h.source.Snapshot()
+	cutoff := snapshot.GeneratedAt.Add(-window)
+	changed := make([]TargetSnapshot, 0, len(snapshot.Targets))
+	for _, target := range snapshot.Targets {
+		if !target.LastChanged.IsZero() && !target.LastChanged.Before(cutoff) {
+			changed = append(changed, target)
+		}
+	}
+	header := fmt.Sprintf("<b>Changes in the last %d min</b>", int(window.Minutes()))
+	if len(changed) == 0 {
+		return header + "\nNo state changes."
+	}
+	sort.Slice(changed, func(i, j int) bool { return changed[i].LastChanged.After(changed[j].LastChanged) })
+
+	var sb strings.Builder
+	sb.WriteString(header)
+	for _, target := range changed {
+		fmt.Fprintf(
+			&sb,
+			"\n<b>%s</b> -&gt; <b>%s</b> %s ago",
+			util.HTMLEscape(target.Name),
+			target.Status,
+			formatDurationShort(snapshot.GeneratedAt.Sub(target.LastChanged)),
+		)
+	}
+	return sb.String()
+}
+
+// nextText reports when each target is next checked, soonest first.
+func (h *CommandHandler) nextText(trackName string, now time.Time) string {
+	checks, ok := h.source.NextChecks(trackName)
+	if !ok {
+		return "Track not found. Use /list."
+	}
+	if len(checks) == 0 {
+		return noTargetsText
+	}
+	sort.SliceStable(checks, func(i, j int) bool {
+		if checks[i].At.IsZero() != checks[j].At.IsZero() {
+			return !checks[i].At.IsZero()
+		}
+		return checks[i].At.Before(checks[j].At)
+	})
+
+	var sb strings.Builder
+	sb.WriteString("<b>Next checks</b>")
+	for _, check := range checks {
+		fmt.Fprintf(&sb, "\n<b>%s</b> ", util.HTMLEscape(check.Target))
+		switch {
+		case check.At.IsZero():
+			sb.WriteString("not scheduled")
+		case !check.At.After(now):
+			sb.WriteString("due now")
+		default:
+			fmt.Fprintf(&sb, "in %s (<code>%s</code>)", formatDurationShort(check.At.Sub(now)), util.FormatTime(check.At))
+		}
+		if check.Scheduled {
+			sb.WriteString(", waiting for schedule")
+		}
+	}
+	return sb.String()
+}
+
+func (h *CommandHandler) exportTargetsMessages(format string) []string {
+	if format == "" {
+		format = "json"
+	}
+	data, err := EncodeTargets(h.source.ExportTargets(), format)
+	if err != nil {
+		return []string{"Usage: /exporttargets [json|yaml]"}
+	}
+	header := fmt.Sprintf("<b>Targets export</b> (%s)", util.HTMLEscape(strings.ToLower(format)))
+	return renderPreChunks(header, strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"))
+}
+
+// manageTargetText adds, updates or removes a stored target, with the same
+// validation as the dashboard.
+func (h *CommandHandler) manageTargetText(chatID int64, command string, args []string) string {
+	if !h.isChatAllowed(chatID) {
+		return "This command is not available in this chat."
+	}
+	exists := false
+	if len(args) > 0 {
+		for _, target := range h.source.Snapshot().Targets {
+			if target.Name == args[0] {
+				exists = true
+				break
+			}
+		}
+	}
+
+	if command == "remove" {
+		if len(args) != 1 {
+			return "Usage: /remove &lt;name&gt;"
+		}
+		if !exists {
+			return "Track not found. Use /list."
+		}
+		if err := h.source.DeleteTarget(args[0]); err != nil {
+			return fmt.Sprintf("Target not removed: %s", util.HTMLEscape(err.Error()))
+		}
+		return fmt.Sprintf("Target <b>%s</b> removed.", util.HTMLEscape(args[0]))
+	}
+
+	const usage = "Usage: /add &lt;name&gt; &lt;address&gt; &lt;port&gt;"
+	if len(args) != 3 {
+		return usage
+	}
+	port, err := strconv.Atoi(args[2])
+	if err != nil {
+		return usage
+	}
+	if err := h.source.UpsertTarget(args[0], args[1], port); err != nil {
+		return fmt.Sprintf("Target not saved: %s", util.HTMLEscape(err.Error()))
+	}
+	action := "added"
+	if exists {
+		action = "updated"
+	}
+	return fmt.Sprintf("Target <b>%s</b> %s: <code>%s:%d</code>", util.HTMLEscape(args[0]), action, util.HTMLEscape(args[1]), port)
+}
+
+func (h *CommandHandler) logLevelText(args []string) string {
+	h.mu.RLock()
+	control := h.logLevel
+	h.mu.RUnlock()
+	if control == nil {
+		return "Runtime log level changes are not available."
+	}
+
+	const usage = "Usage: /loglevel [debug|info|warn|error|reset] [duration]"
+	if len(args) == 0 {
+		level, until := control.current()
+		if until.IsZero() {
+			return fmt.Sprintf("log level: <b>%s</b>", level)
+		}
+		return fmt.Sprintf("log level: <b>%s</b> until <code>%s</code>", level, util.FormatTime(until))
+	}
+	if strings.EqualFold(args[0], "reset") {
+		control.reset()
+		level, _ := control.current()
+		return fmt.Sprintf("log level reset to <b>%s</b>", level)
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(args[0])); err != nil {
+		return usage
+	}
+	duration := defaultLogLevelDuration
+	if len(args) >= 2 {
+		value, err := time.ParseDuration(args[1])
+		if err != nil || value <= 0 {
+			return usage
+		}
+		duration = min(value, maxLogLevelDuration)
+	}
+	now := time.Now().UTC()
+	control.set(level, duration, now)
+	h.logger.Info("log level changed via command", "level", level, "duration", duration)
+	return fmt.Sprintf("log level: <b>%s</b> for <code>%s</code> (reverts at <code>%s</code>)", level, duration, util.FormatTime(now.Add(duration)))
+}
+
+func (h *CommandHandler) pingText(ctx context.Context, args []string) string {
+	if len(args) == 0 {
+		return "Usage: /ping &lt;track&gt; [count]"
+	}
+	count := 0
+	if len(args) >= 2 {
+		value, err := strconv.Atoi(args[1])
+		if err != nil {
+			return "Usage: /ping &lt;track&gt; [count]"
+		}
+		count = value
+	}
+
+	result, ok := h.source.Ping(ctx, args[0], count)
+	if !ok {
+		return "Track not found. Use /list."
+	}
+	var sb strings.Builder
+	fmt.Fprintf(
+		&sb,
+		"<b>Ping %s</b>\nendpoint: <code>%s:%d</code>\nconnected: %d/%d\n",
+		util.HTMLEscape(result.Target),
+		util.HTMLEscape(result.Address),
+		result.Port,
+		result.Received,
+		result.Sent,
+	)
+	if result.Received > 0 {
+		fmt.Fprintf(
+			&sb,
+			"min/avg/max: <code>%s / %s / %s</code>\n",
+			formatLatency(result.Min),
+			formatLatency(result.Avg),
+			formatLatency(result.Max),
+		)
+	}
+	return sb.String()
+}
+
+func (h *CommandHandler) slaText(args []string) string {
+	if len(args) > 0 && strings.EqualFold(args[0], "overall") {
+		return h.overallSLAText(args[1:])
+	}
+	trackName, days := "", 7
+	switch {
+	case len(args) == 1:
+		if value, err := strconv.Atoi(args[0]); err == nil {
+			days = value
+		} else {
+			trackName = args[0]
+		}
+	case len(args) >= 2:
+		trackName = args[0]
+		value, err := strconv.Atoi(args[1])
+		if err != nil {
+			return "Usage: /sla [track] [days]"
+		}
+		days = value
+	}
+
+	reports, ok := h.source.SLA(trackName, days)
+	if !ok {
+		return "Track not found. Use /list."
+	}
+	if len(reports) == 0 {
+		return noTargetsText
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<b>SLA report</b> (last %dd)\n\n", reports[0].Days)
+	for i, report := range reports {
+		fmt.Fprintf(&sb, "%d. <b>%s</b>\n", i+1, util.HTMLEscape(report.Target))
+		if report.Checks == 0 {
+			sb.WriteString("uptime: <code>N/A</code> (no checks)\n")
+		} else {
+			fmt.Fprintf(&sb, "uptime: <code>%.3f%%</code> (%d/%d checks)\n", report.UptimePercent, report.UpChecks, report.Checks)
+		}
+		fmt.Fprintf(&sb, "latency p50/p95/p99: <code>%s</code>\n\n", formatLatencyPercentiles(report.Latency))
+	}
+	return sb.String()
+}
+
+func (h *CommandHandler) overallSLAText(args []string) string {
+	days := 7
+	if len(args) > 0 {
+		value, err := strconv.Atoi(args[0])
+		if err != nil {
+			return "Usage: /sla overall [days]"
+		}
+		days = value
+	}
+
+	report, ok := h.source.OverallSLA(days)
+	if !ok {
+		return "No critical tracks. Set <code>critical: true</code> on targets in config."
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<b>Service SLA</b> (last %dd, %d critical tracks)\n", report.Days, len(report.Targets))
+	if report.Covered <= 0 {
+		sb.WriteString("service uptime: <code>N/A</code> (no checks)\n")
+	} else {
+		fmt.Fprintf(
+			&sb,
+			"service uptime: <code>%.3f%%</code> (down %s of %s)\n",
+			report.UptimePercent,
+			formatDurationShort(report.Downtime),
+			formatDurationShort(report.Covered),
+		)
+		fmt.Fprintf(&sb, "weighted uptime: <code>%.3f%%</code>\n", report.WeightedUptimePercent)
+	}
+	sb.WriteString("\n")
+	for _, target := range report.Targets {
+		uptime := "N/A"
+		if target.Checks > 0 {
+			uptime = fmt.Sprintf("%.3f%%", target.UptimePercent)
+		}
+		fmt.Fprintf(&sb, "- <b>%s</b> weight %g: <code>%s</code>\n", util.HTMLEscape(target.Name), target.Weight, uptime)
+	}
+	return sb.String()
+}
+
+func formatLatencyPercentiles(latency *LatencyPercentiles) string {
+	if latency == nil {
+		return "N/A"
+	}
+	return fmt.Sprintf("%.1fms / %.1fms / %.1fms", latency.P50, latency.P95, latency.P99)
+}
+
+func (h *CommandHandler) diagText() string {
+	diag := h.source.Diagnostics()
+
+	var sb strings.Builder
+	sb.WriteString("<b>Diagnostics</b>\n")
+	fmt.Fprintf(&sb, "tracks: %d\n", diag.Targets)
+	storageState := "ok"
+	if diag.StorageDegraded {
+		storageState = "DEGRADED"
+	}
+	fmt.Fprintf(
+		&sb,
+		"storage: %s (consecutive write failures: %d, total: %d)\n",
+		storageState,
+		diag.StorageConsecutiveFailures,
+		diag.StorageTotalFailures,
+	)
+	fmt.Fprintf(&sb, "dropped bot updates: %d (queue size %d)\n", h.DroppedUpdates(), cap(h.queue.updates))
+	if len(diag.DuplicateEndpoints) == 0 {
+		sb.WriteString("duplicate endpoints: none")
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, "duplicate endpoints: %d\n", len(diag.DuplicateEndpoints))
+	for _, dup := range diag.DuplicateEndpoints {
+		fmt.Fprintf(
+			&sb,
+			"- <code>%s</code>: %s\n",
+			util.HTMLEscape(dup.Endpoint),
+			util.HTMLEscape(strings.Join(dup.Targets, ", ")),
+		)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (h *CommandHandler) resendText(ctx context.Context) string {
+	h.mu.RLock()
+	resend := h.resendFn
+	h.mu.RUnlock()
+	if resend == nil {
+		return "Resending alerts is not available."
+	}
+	groups, err := resend(ctx)
+	if errors.Is(err, ErrNoAlertToResend) {
+		return "No alert has been sent yet."
+	}
+	if err != nil {
+		return fmt.Sprintf("Resent %d alert group(s), some deliveries failed: %s", groups, util.HTMLEscape(err.Error()))
+	}
+	return fmt.Sprintf("Resent %d alert group(s) as a replay.", groups)
+}
+
 func (h *CommandHandler) authLinkText(chatID int64) string {
 	if !h.isChatAllowed(chatID) {
 		return "This command is not available in this chat."
@@ -231,8 +1160,30 @@ func parseCommand(text string) (string, string, bool) {
 	return strings.ToLower(command), arg, true
 }
 
+func parseCommandArgs(text string) []string {
+	parts := strings.Fields(strings.TrimSpace(text))
+	if len(parts) <= 1 {
+		return nil
+	}
+	return parts[1:]
+}
+
 func renderLogChunks(header string, rows []logstore.Row) []string {
-	if len(rows) == 0 {
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		line := fmt.Sprintf("%s  %-4s  %-21s  %s", row.Timestamp, row.Status, row.Endpoint, util.SanitizeReason(row.Reason))
+		if row.Count > 1 {
+			line += fmt.Sprintf("  x%d until %s", row.Count, row.LastSeen)
+		}
+		lines = append(lines, line)
+	}
+	return renderPreChunks(header, lines)
+}
+
+// renderPreChunks splits preformatted lines into Telegram-sized messages, each
+// wrapped in its own <pre> block so no tag is split across messages.
+func renderPreChunks(header string, lines []string) []string {
+	if len(lines) == 0 {
 		return []string{header + "\n<pre>(empty)</pre>"}
 	}
 
@@ -245,8 +1196,8 @@ func renderLogChunks(header string, rows []logstore.Row) []string {
 
 	chunks := make([]string, 0, 2)
 	current := strings.Builder{}
-	for _, row := range rows {
-		line := fmt.Sprintf("%s  %-4s  %-21s  %s\n", row.Timestamp, row.Status, row.Endpoint, row.Reason)
+	for _, line := range lines {
+		line += "\n"
 		if current.Len() > 0 && current.Len()+len(line) > maxBody {
 			chunks = append(chunks, current.String())
 			current.Reset()
@@ -271,5 +1222,5 @@ func renderLogChunks(header string, rows []logstore.Row) []string {
 }
 
 func helpText() string {
-	return "<b>Port Tracker Bot</b>\n/list - tracks\n/status - current states\n/logs &lt;track&gt; - last 7 days\n/authme - dashboard login link"
+	return "<b>Port Tracker Bot</b>\n/list - tracks\n/status [track|tag] - current states\n/logs &lt;track&gt; - last 7 days\n/authme - dashboard login link\n/diag - runtime diagnostics\n/exporttargets [json|yaml] - targets as config\n/sla [track] [days] - uptime and latency percentiles\n/sla overall [days] - service uptime over critical tracks\n/ping &lt;track&gt; [count] - ad-hoc connect latency\n/loglevel [level] [duration] - temporary log level\n/recent [count] - latest state changes\n/resend - replay the last alert\n/changes [minutes] - targets that changed recently\n/next [track] - next check times\n/graph &lt;track&gt; [hours|&lt;days&gt;d] - uptime timeline image\n/compare &lt;track&gt; [days] - this window vs the previous one\n/incidents &lt;track&gt; [days] - outages, newest first\n/export &lt;track&gt; [days] - logs as a CSV file\n/threshold &lt;track&gt; [fail|success &lt;n&gt;] - state change thresholds\n/config [&lt;key&gt; &lt;value&gt;] - runtime monitoring settings\n/maintenance - active maintenance windows\n/add &lt;name&gt; &lt;address&gt; &lt;port&gt; - add or update a target\n/remove &lt;name&gt; - remove a target\n/mute &lt;track&gt; [minutes] - silence alerts (default 1h)\n/unmute &lt;track&gt; - resume alerts\n/pause [minutes] - pause all alerts\n/resume - end a /pause"
 }
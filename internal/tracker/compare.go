@@ -0,0 +1,104 @@
+package tracker
+
+import (
+	"time"
+
+	"trackway/internal/logstore"
+)
+
+const maxCompareDays = 90
+
+// DowntimeEvent is one outage: from the first DOWN row to the next UP row, or
+// to now when it is still ongoing.
+type DowntimeEvent struct {
+	Start time.Time
+	End   time.Time
+}
+
+// WindowStats summarizes one comparison window. Outages counts events that
+// started inside it; Downtime is the part of any event that overlaps it.
+type WindowStats struct {
+	From          time.Time
+	To            time.Time
+	Checks        int
+	UptimePercent float64
+	Outages       int
+	Downtime      time.Duration
+}
+
+// WindowComparison holds the last Days days of a target and the equal window
+// right before them.
+type WindowComparison struct {
+	Target   string
+	Days     int
+	Current  WindowStats
+	Previous WindowStats
+}
+
+// Compare counts each window's checks in the store with its own bounds, and
+// reads outages as status changes, so neither is cut short by a row limit.
+func (e *MonitorEngine) Compare(trackName string, days int) (WindowComparison, bool) {
+	days = min(clampDays(days), maxCompareDays)
+	if !e.hasTarget(trackName) {
+		return WindowComparison{}, false
+	}
+	now := time.Now().UTC()
+	window := time.Duration(days) * 24 * time.Hour
+	current := e.logs.CheckStats(trackName, now.Add(-window), now)
+	previous := e.logs.CheckStats(trackName, now.Add(-2*window), now.Add(-window))
+	incidents := e.logs.Incidents(trackName, 2*days, slaMaxRows)
+	return buildWindowComparison(trackName, current, previous, incidents, now, days), true
+}
+
+func buildWindowComparison(name string, current, previous logstore.CheckStats, incidents []logstore.Incident, now time.Time, days int) WindowComparison {
+	window := time.Duration(days) * 24 * time.Hour
+	events := downtimeEvents(incidents)
+	return WindowComparison{
+		Target:   name,
+		Days:     days,
+		Current:  buildWindowStats(current, events, now.Add(-window), now),
+		Previous: buildWindowStats(previous, events, now.Add(-2*window), now.Add(-window)),
+	}
+}
+
+func downtimeEvents(incidents []logstore.Incident) []DowntimeEvent {
+	events := make([]DowntimeEvent, 0, len(incidents))
+	for _, incident := range incidents {
+		events = append(events, DowntimeEvent{Start: incident.Start, End: incident.Start.Add(incident.Duration)})
+	}
+	return events
+}
+
+// Incidents returns a target's outages of the last days days, newest first.
+func (e *MonitorEngine) Incidents(trackName string, days int) ([]logstore.Incident, bool) {
+	e.mu.RLock()
+	target := e.targetByName[trackName]
+	e.mu.RUnlock()
+	if target == nil {
+		return nil, false
+	}
+	return e.logs.Incidents(target.Name, clampDays(days), slaMaxRows), true
+}
+
+func buildWindowStats(checks logstore.CheckStats, events []DowntimeEvent, from, to time.Time) WindowStats {
+	stats := WindowStats{From: from, To: to, Checks: checks.Checks}
+	if stats.Checks > 0 {
+		stats.UptimePercent = float64(checks.UpChecks) * 100 / float64(stats.Checks)
+	}
+	for _, event := range events {
+		if !event.Start.Before(from) && event.Start.Before(to) {
+			stats.Outages++
+		}
+		start, end := event.Start, event.End
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			stats.Downtime += end.Sub(start)
+		}
+	}
+	return stats
+}
@@ -2,16 +2,23 @@ package tracker
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net"
+	"net/netip"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/robfig/cron/v3"
+
 	"trackway/internal/config"
 	"trackway/internal/logstore"
 )
@@ -22,111 +29,327 @@ type MonitorEngine struct {
 	logs   *logstore.Store
 	logger *slog.Logger
 
-	interval    time.Duration
-	timeout     time.Duration
-	maxParallel int
+	interval         time.Duration
+	timeout          time.Duration
+	maxParallel      int
+	rejectDuplicates bool
+	autoDisableAfter time.Duration
+	escalateAfter    time.Duration
+	cycleLogLevel    slog.Level
+	startupResolve   bool
+	warmupCheck      bool
+	telemetry        *checkTelemetry
+	investigateFirst bool
+	downTimeout      time.Duration
+	failThreshold    int
+	// certRoots verifies certificates for cert_warn_days; nil uses the
+	// system pool.
+	certRoots *x509.CertPool
+	storage   *storageHealth
+	probe     probeOptions
+
+	// targetConfig holds the options of config and target file targets;
+	// fileTargets those of the file alone.
+	targetConfig map[string]config.Target
+	targetSource config.TargetSource
+	fileTargets  map[string]config.Target
 
-	mu           sync.RWMutex
-	targets      []*TargetState
-	targetByName map[string]*TargetState
+	mu              sync.RWMutex
+	targets         []*TargetState
+	targetByName    map[string]*TargetState
+	settingsChanged chan struct{}
+	// gatesDown holds alert gates that are currently DOWN; gateHeld the gated
+	// targets whose DOWN alert was held.
+	gatesDown map[string]bool
+	gateHeld  map[string]bool
+	// maintenance changes only on Reload; maintenanceHeld marks targets whose
+	// DOWN alert was dropped during a window.
+	maintenance     []maintenanceWindow
+	maintenanceHeld map[string]bool
+	// firstCycleDone is closed once the first check cycle has completed.
+	firstCycleDone chan struct{}
+	firstCycleOnce sync.Once
+	// lastRound is when runChecks last finished, in Unix nanoseconds.
+	lastRound atomic.Int64
 }
 
 func NewMonitorEngine(cfg config.Config, logs *logstore.Store) *MonitorEngine {
 	targets := buildTargetsFromConfig(cfg.Targets)
 	byName := make(map[string]*TargetState, len(targets))
 	for _, target := range targets {
+		if target.FailThreshold == 0 {
+			target.FailThreshold = cfg.Monitoring.FailureThreshold
+		}
 		byName[target.Name] = target
 	}
+	targetConfig := make(map[string]config.Target, len(cfg.Targets))
+	for _, item := range cfg.Targets {
+		targetConfig[item.Name] = item
+	}
 
-	return &MonitorEngine{
-		logs:         logs,
-		logger:       slog.Default(),
-		interval:     defaultSeconds(cfg.Monitoring.IntervalSeconds, 5),
-		timeout:      defaultSeconds(cfg.Monitoring.ConnectTimeoutSeconds, 2),
-		maxParallel:  cfg.Monitoring.MaxParallelChecks,
-		targets:      targets,
-		targetByName: byName,
+	engine := &MonitorEngine{
+		logs:             logs,
+		logger:           slog.Default(),
+		interval:         defaultSeconds(cfg.Monitoring.IntervalSeconds, 5),
+		timeout:          defaultSeconds(cfg.Monitoring.ConnectTimeoutSeconds, 2),
+		maxParallel:      cfg.Monitoring.MaxParallelChecks,
+		rejectDuplicates: cfg.Monitoring.RejectDuplicateEndpoints,
+		autoDisableAfter: autoDisableAfter(cfg),
+		escalateAfter:    time.Duration(max(cfg.Monitoring.EscalateAfterSeconds, 0)) * time.Second,
+		cycleLogLevel:    cycleLogLevel(cfg.Monitoring.CycleLogLevel),
+		startupResolve:   cfg.Monitoring.StartupResolve,
+		warmupCheck:      cfg.Monitoring.WarmupCheck,
+		telemetry:        newCheckTelemetry(cfg.Otel.Enabled),
+		investigateFirst: cfg.Monitoring.InvestigateFirstFailure,
+		downTimeout:      time.Duration(max(cfg.Monitoring.DownTimeoutSeconds, 0)) * time.Second,
+		failThreshold:    cfg.Monitoring.FailureThreshold,
+		storage: newStorageHealth(
+			cfg.Monitoring.StorageFailureThreshold,
+			defaultSeconds(cfg.Monitoring.StorageAlertCooldownSec, 900),
+		),
+		targetConfig:    targetConfig,
+		targetSource:    cfg.TargetSource,
+		targets:         targets,
+		targetByName:    byName,
+		settingsChanged: make(chan struct{}, 1),
+		firstCycleDone:  make(chan struct{}),
+		maintenance:     newMaintenanceWindows(cfg.Maintenance),
 	}
+	// Load has validated the address; an empty one stays invalid.
+	engine.probe.source, _ = netip.ParseAddr(cfg.Monitoring.SourceAddress)
+	engine.probe.retries = cfg.Monitoring.ProbeRetries
+	engine.loadSettings()
+	return engine
 }
 
 func (e *MonitorEngine) Run(ctx context.Context, onEvents func([]alertEvent)) {
 	if onEvents == nil {
 		onEvents = func([]alertEvent) {}
 	}
+	if e.targetSource.Source == "file" {
+		e.reloadTargetsFile()
+		go e.watchTargetsFile(ctx)
+	}
+	e.storeConfigTags()
+	e.syncTargets()
+	e.warnDuplicateEndpoints("")
+	if len(e.TargetNames()) == 0 {
+		e.logger.Warn("no targets configured; add one with /add, from the dashboard or config targets")
+	}
+	e.warmUp(ctx)
 	e.runChecks(ctx, onEvents)
-	ticker := time.NewTicker(e.interval)
-	defer ticker.Stop()
+	e.firstCycleOnce.Do(func() { close(e.firstCycleDone) })
+	interval := e.checkInterval()
+	timer := time.NewTimer(e.untilNextDue(time.Now()))
+	defer timer.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
+		case <-e.settingsChanged:
+			if current := e.checkInterval(); current != interval {
+				interval = current
+				e.rescheduleDefaultInterval(time.Now())
+			}
+		case <-timer.C:
 			e.runChecks(ctx, onEvents)
 		}
+		timer.Reset(e.untilNextDue(time.Now()))
+	}
+}
+
+// FirstCycleDone is closed after the first check cycle, so every target has
+// a known state instead of UNKNOWN.
+func (e *MonitorEngine) FirstCycleDone() <-chan struct{} {
+	return e.firstCycleDone
+}
+
+// LastRoundAt returns when the last check round finished, including rounds
+// with no target due; zero before the first one.
+func (e *MonitorEngine) LastRoundAt() time.Time {
+	nanos := e.lastRound.Load()
+	if nanos == 0 {
+		return time.Time{}
 	}
+	return time.Unix(0, nanos).UTC()
 }
 
+func (e *MonitorEngine) Ready() bool {
+	select {
+	case <-e.firstCycleDone:
+		return true
+	default:
+		return false
+	}
+}
+
+// runChecks checks the targets that are due and schedules their next check.
 func (e *MonitorEngine) runChecks(ctx context.Context, onEvents func([]alertEvent)) {
+	defer func() { e.lastRound.Store(time.Now().UnixNano()) }()
 	e.syncTargets()
 
+	targets := e.takeDue(time.Now())
 	e.mu.RLock()
-	targets := append([]*TargetState(nil), e.targets...)
+	timeout, maxParallel := e.timeout, e.maxParallel
 	e.mu.RUnlock()
 
 	if len(targets) == 0 {
 		return
 	}
 
-	workers := defaultWorkers(e.maxParallel, len(targets))
+	ctx, endCycle := e.telemetry.startCycle(ctx, len(targets))
+	defer endCycle()
+	workers := defaultWorkers(maxParallel, len(targets))
 
 	sem := make(chan struct{}, workers)
-	eventsCh := make(chan alertEvent, len(targets))
+	eventsCh := make(chan alertEvent, 4*len(targets))
 	var wg sync.WaitGroup
+	var stats cycleStats
 
+	now := time.Now()
 	for _, target := range targets {
 		if ctx.Err() != nil {
 			break
 		}
+		if !inSchedule(target.Schedule, now) {
+			continue
+		}
 		wg.Add(1)
 		sem <- struct{}{}
 		go func(t *TargetState) {
 			defer wg.Done()
 			defer func() { <-sem }()
-			status := checkTCP(ctx, t.Address, t.Port, e.timeout)
-			if event := e.applyStatus(t, status); event != nil {
+			checkCtx, endCheck := e.telemetry.startCheck(ctx, t)
+			result := e.probe.checkWithFallback(checkCtx, t, e.checkTimeout(t, timeout))
+			endCheck(result)
+			event := e.applyResult(t, result)
+			stats.record(result.Up, event != nil)
+			if event != nil {
 				eventsCh <- *event
 			}
+			if event := e.escalate(t, time.Now().UTC()); event != nil {
+				eventsCh <- *event
+			}
+			if event := e.autoDisable(t, time.Now().UTC()); event != nil {
+				eventsCh <- *event
+			}
+			if result.Up {
+				for _, event := range e.checkCerts(ctx, t, timeout, time.Now().UTC()) {
+					eventsCh <- event
+				}
+			}
 		}(target)
 	}
 
 	wg.Wait()
 	close(eventsCh)
 
-	events := make([]alertEvent, 0, len(eventsCh))
+	events := make([]alertEvent, 0, len(eventsCh)+1)
 	for event := range eventsCh {
 		events = append(events, event)
 	}
+	if event := e.storage.event(time.Now().UTC()); event != nil {
+		events = append(events, *event)
+	}
+	finished := time.Now().UTC()
+	events = e.filterMuted(e.filterMaintenance(e.applyGates(events, finished), finished), finished)
+	e.logger.Log(ctx, e.cycleLogLevel, "check cycle complete",
+		"checked", stats.checked,
+		"up", stats.up,
+		"down", stats.checked-stats.up,
+		"changed", stats.changed,
+		"duration", time.Since(now).Round(time.Millisecond),
+	)
 	onEvents(events)
 }
 
+// checkTimeout returns the down timeout for a target last known DOWN when one
+// is configured, and timeout otherwise.
+func (e *MonitorEngine) checkTimeout(target *TargetState, timeout time.Duration) time.Duration {
+	if e.downTimeout <= 0 {
+		return timeout
+	}
+	e.mu.RLock()
+	down := target.LastStatus != nil && !*target.LastStatus
+	e.mu.RUnlock()
+	if down {
+		return e.downTimeout
+	}
+	return timeout
+}
+
+type cycleStats struct {
+	mu      sync.Mutex
+	checked int
+	up      int
+	changed int
+}
+
+func (s *cycleStats) record(up, changed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checked++
+	if up {
+		s.up++
+	}
+	if changed {
+		s.changed++
+	}
+}
+
+func cycleLogLevel(level string) slog.Level {
+	if level == "debug" {
+		return slog.LevelDebug
+	}
+	return slog.LevelInfo
+}
+
 func (e *MonitorEngine) applyStatus(target *TargetState, status bool) *alertEvent {
+	return e.applyResult(target, checkResult{Up: status})
+}
+
+func (e *MonitorEngine) applyResult(target *TargetState, result checkResult) *alertEvent {
 	now := time.Now().UTC()
 	e.mu.Lock()
+	target.checks++
+	if result.Up {
+		target.failStreak = 0
+		target.failingSince = time.Time{}
+		target.okStreak++
+	} else {
+		if target.failStreak == 0 {
+			target.failingSince = now
+		}
+		target.checkFailures++
+		target.failStreak++
+		target.okStreak = 0
+	}
+	status := target.smoothedStatus(result.Up)
 	reason := "POLL"
 	var event *alertEvent
+	clearAck := false
+	if e.investigateFirst {
+		status, event = e.investigate(target, result.Up, status, now)
+	}
 	target.LastChecked = now
+	target.LastLatency = result.Latency
+	if result.IP != "" {
+		target.ResolvedIP, target.ResolvedAddrs = result.IP, result.Addrs
+	}
 	if target.LastStatus == nil {
 		target.LastStatus = boolPtr(status)
 		target.LastChanged = now
 		reason = "INIT"
 		if !status {
 			event = &alertEvent{
-				Kind:     "DOWN",
-				Target:   target.Name,
-				Address:  target.Address,
-				Port:     target.Port,
-				Reason:   "initial-check",
-				Occurred: now,
+				Kind:       "DOWN",
+				Target:     target.Name,
+				Address:    target.Address,
+				Port:       target.Port,
+				Reason:     "initial-check",
+				Mention:    target.Mention,
+				RunbookURL: target.RunbookURL,
+				Occurred:   now,
 			}
 		}
 	} else if *target.LastStatus != status {
@@ -134,16 +357,21 @@ func (e *MonitorEngine) applyStatus(target *TargetState, status bool) *alertEven
 		*target.LastStatus = status
 		target.LastChanged = now
 		reason = "CHANGE"
+		target.Escalated = false
 		if prev && !status {
 			event = &alertEvent{
-				Kind:     "DOWN",
-				Target:   target.Name,
-				Address:  target.Address,
-				Port:     target.Port,
-				Reason:   "state-change",
-				Occurred: now,
+				Kind:       "DOWN",
+				Target:     target.Name,
+				Address:    target.Address,
+				Port:       target.Port,
+				Reason:     "state-change",
+				Mention:    target.Mention,
+				RunbookURL: target.RunbookURL,
+				Occurred:   now,
 			}
 		} else if !prev && status {
+			clearAck = target.AckedBy != ""
+			target.AckedBy, target.AckedAt = "", time.Time{}
 			event = &alertEvent{
 				Kind:     "RECOVERED",
 				Target:   target.Name,
@@ -154,14 +382,154 @@ func (e *MonitorEngine) applyStatus(target *TargetState, status bool) *alertEven
 			}
 		}
 	}
+	slow := status && target.SlowThreshold > 0 && result.Latency > target.SlowThreshold
+	if !status {
+		target.slow = false
+	} else if event == nil && slow != target.slow {
+		target.slow = slow
+		event = slowEvent(target, result.Latency, now)
+	}
+	if event != nil {
+		event.Impact, event.Critical = target.Impact, target.Critical
+	}
+	if event != nil && event.Kind == "DOWN" {
+		target.investigating = false
+		event.FailedChecks = target.failStreak
+		event.FailingSince = target.failingSince
+	}
+	if result.Method != "" {
+		reason = fmt.Sprintf("%s via %s", reason, result.Method)
+	}
+	if result.Rule != "" {
+		reason = fmt.Sprintf("%s rule %s", reason, result.Rule)
+	}
+	if result.Status != "" {
+		reason = fmt.Sprintf("%s %s", reason, result.Status)
+	}
+	if result.Probes > 1 {
+		reason = fmt.Sprintf("%s %d/%d", reason, result.Healthy, result.Probes)
+		if event != nil && event.Kind == "DOWN" {
+			event.Detail = fmt.Sprintf("%d/%d backends failing", result.Probes-result.Healthy, result.Probes)
+		}
+	}
+	if slow {
+		reason += " SLOW"
+	}
+	if e.inMaintenance(target.Name, now) {
+		reason += " MAINTENANCE"
+	}
 	e.mu.Unlock()
 
-	if err := e.logs.Append(target.Name, target.Address, target.Port, status, reason); err != nil {
+	entry := logstore.Entry{
+		Target:  target.Name,
+		Address: target.Address,
+		Port:    target.Port,
+		IP:      result.IP,
+		Status:  status,
+		Reason:  reason,
+		Latency: result.Latency,
+	}
+	err := e.logs.AppendEntry(entry)
+	e.storage.record(err)
+	if err != nil {
 		e.logger.Warn("failed to append log row", "track", target.Name, "error", err)
 	}
+	if clearAck {
+		if err := e.logs.SetTargetAck(target.Name, "", time.Time{}); err != nil {
+			e.logger.Warn("failed to clear target ack", "track", target.Name, "error", err)
+		}
+	}
 	return event
 }
 
+// investigate holds the first failure of an UP target back from flipping it
+// and reports it as INVESTIGATING instead; a recovery before the next failure
+// turns it into a FALSE_ALARM. Callers hold e.mu.
+func (e *MonitorEngine) investigate(target *TargetState, up, status bool, now time.Time) (bool, *alertEvent) {
+	if target.LastStatus == nil || !*target.LastStatus {
+		return status, nil
+	}
+	event := &alertEvent{
+		Target:   target.Name,
+		Address:  target.Address,
+		Port:     target.Port,
+		Occurred: now,
+	}
+	switch {
+	case !up && target.failStreak == 1:
+		target.investigating = true
+		event.Kind = "INVESTIGATING"
+		event.Reason = "first-failure"
+		return true, event
+	case up && target.investigating:
+		target.investigating = false
+		event.Kind = "FALSE_ALARM"
+		event.Reason = "recovered-before-confirm"
+		return status, event
+	}
+	return status, nil
+}
+
+// escalate emits a single STILL_DOWN event once a target has been DOWN for
+// longer than the escalation threshold. The flag resets on the next change.
+func (e *MonitorEngine) escalate(target *TargetState, now time.Time) *alertEvent {
+	if e.escalateAfter <= 0 {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	down := target.LastStatus != nil && !*target.LastStatus
+	if !down || target.Escalated || now.Sub(target.LastChanged) < e.escalateAfter {
+		return nil
+	}
+	target.Escalated = true
+	return &alertEvent{
+		Kind:       "STILL_DOWN",
+		Target:     target.Name,
+		Address:    target.Address,
+		Port:       target.Port,
+		Reason:     "escalation",
+		Detail:     "down for " + formatDurationShort(now.Sub(target.LastChanged)),
+		Mention:    target.Mention,
+		RunbookURL: target.RunbookURL,
+		Occurred:   now,
+		Impact:     target.Impact,
+		Critical:   target.Critical,
+	}
+}
+
+// autoDisable soft-disables a target that has been DOWN for longer than the
+// configured threshold. The target stays in the store and can be re-enabled by
+// upserting it again.
+func (e *MonitorEngine) autoDisable(target *TargetState, now time.Time) *alertEvent {
+	if e.autoDisableAfter <= 0 {
+		return nil
+	}
+	e.mu.RLock()
+	down := target.LastStatus != nil && !*target.LastStatus
+	downSince := target.LastChanged
+	e.mu.RUnlock()
+	if !down || now.Sub(downSince) < e.autoDisableAfter {
+		return nil
+	}
+
+	if err := e.logs.DeleteTarget(target.Name); err != nil {
+		e.logger.Warn("failed to auto-disable target", "track", target.Name, "error", err)
+		return nil
+	}
+	e.logger.Warn("auto-disabled target after prolonged downtime", "track", target.Name, "down_since", downSince)
+	return &alertEvent{
+		Kind:     "AUTO_DISABLED",
+		Target:   target.Name,
+		Address:  target.Address,
+		Port:     target.Port,
+		Reason:   "down-too-long",
+		Occurred: now,
+		Impact:   target.Impact,
+		Critical: target.Critical,
+	}
+}
+
 func (e *MonitorEngine) Snapshot() Snapshot {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
@@ -185,18 +553,54 @@ func (e *MonitorEngine) Snapshot() Snapshot {
 			result.Down++
 		}
 		result.Targets = append(result.Targets, TargetSnapshot{
-			Name:        target.Name,
-			Address:     target.Address,
-			Port:        target.Port,
-			Status:      state,
-			LastChanged: target.LastChanged,
-			LastChecked: target.LastChecked,
+			Name:          target.Name,
+			Address:       target.Address,
+			Port:          target.Port,
+			Status:        state,
+			LastChanged:   target.LastChanged,
+			LastChecked:   target.LastChecked,
+			LastLatency:   target.LastLatency,
+			ResolvedIP:    target.ResolvedIP,
+			ResolvedAddrs: target.ResolvedAddrs,
+			CertNotAfter:  target.CertNotAfter,
+			Checks:        target.checks,
+			CheckFailures: target.checkFailures,
+			RunbookURL:    target.RunbookURL,
+			MutedUntil:    activeMute(target.MutedUntil, result.GeneratedAt),
+			Tags:          target.Tags,
+			AckedBy:       target.AckedBy,
+			AckedAt:       target.AckedAt,
+
+			FailThreshold:    target.FailThreshold,
+			SuccessThreshold: target.SuccessThreshold,
 		})
 	}
 
 	return result
 }
 
+// ExportTargets returns the enabled targets merged with their per-target
+// config options, suitable for writing back into a config file.
+func (e *MonitorEngine) ExportTargets() []config.Target {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	out := make([]config.Target, 0, len(e.targets))
+	for _, target := range e.targets {
+		item := e.targetConfig[target.Name]
+		item.Name = target.Name
+		item.Address = target.Address
+		item.Port = target.Port
+		item.Tags = target.Tags
+		out = append(out, item)
+	}
+	return out
+}
+
+func (e *MonitorEngine) RecentChanges(limit int) []logstore.TargetRow {
+	return e.logs.RecentChanges(limit)
+}
+
 func (e *MonitorEngine) TargetNames() []string {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
@@ -209,12 +613,7 @@ func (e *MonitorEngine) TargetNames() []string {
 }
 
 func (e *MonitorEngine) Logs(trackName string, days int, limit int) ([]logstore.Row, bool) {
-	if days <= 0 {
-		days = 7
-	}
-	if days > 365 {
-		days = 365
-	}
+	days = clampDays(days)
 	if limit <= 0 {
 		limit = 200
 	}
@@ -232,6 +631,24 @@ func (e *MonitorEngine) Logs(trackName string, days int, limit int) ([]logstore.
 	return e.logs.ReadLastDays(target.Name, days, limit), true
 }
 
+// GraphRows reads the rows of a target since from, condensed to buckets of
+// the given width, so a long window does not read every check.
+func (e *MonitorEngine) GraphRows(trackName string, from time.Time, bucket time.Duration) ([]logstore.Row, bool) {
+	if !e.hasTarget(trackName) {
+		return nil, false
+	}
+	return e.logs.BucketRows(trackName, from, bucket), true
+}
+
+func (e *MonitorEngine) Backup(ctx context.Context) (io.ReadCloser, int64, error) {
+	return e.logs.Backup(ctx)
+}
+
+// ListTargets returns the stored targets, disabled ones included.
+func (e *MonitorEngine) ListTargets() ([]logstore.Target, error) {
+	return e.logs.ListAllTargets()
+}
+
 func (e *MonitorEngine) UpsertTarget(name, address string, port int) error {
 	name = strings.TrimSpace(name)
 	address = strings.TrimSpace(address)
@@ -244,13 +661,63 @@ func (e *MonitorEngine) UpsertTarget(name, address string, port int) error {
 	if port <= 0 || port > 65535 {
 		return fmt.Errorf("target port must be between 1 and 65535, got %d", port)
 	}
+	if e.rejectDuplicates {
+		e.syncTargets()
+		if owner := e.endpointOwner(name, address, port); owner != "" {
+			return fmt.Errorf("endpoint %s is already monitored by target %s", config.NormalizeEndpoint("tcp", address, port), owner)
+		}
+	}
 	if err := e.logs.UpsertTarget(name, address, port); err != nil {
 		return err
 	}
 	e.syncTargets()
+	e.warnDuplicateEndpoints(name)
 	return nil
 }
 
+func (e *MonitorEngine) Diagnostics() Diagnostics {
+	consecutive, total, degraded := e.storage.snapshot()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return Diagnostics{
+		Targets:                    len(e.targets),
+		DuplicateEndpoints:         duplicateEndpoints(e.targets),
+		StorageConsecutiveFailures: consecutive,
+		StorageTotalFailures:       total,
+		StorageDegraded:            degraded,
+	}
+}
+
+// endpointOwner returns the other target checking address:port over tcp, the
+// only type a runtime upsert creates, or "" when there is none.
+func (e *MonitorEngine) endpointOwner(name, address string, port int) string {
+	endpoint := config.NormalizeEndpoint("tcp", address, port)
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, target := range e.targets {
+		if target.Name == name {
+			continue
+		}
+		if config.NormalizeEndpoint(target.CheckType, target.Address, target.Port) == endpoint {
+			return target.Name
+		}
+	}
+	return ""
+}
+
+// warnDuplicateEndpoints logs endpoints checked by more than one target. When
+// onlyTarget is set, only duplicates involving that target are reported.
+func (e *MonitorEngine) warnDuplicateEndpoints(onlyTarget string) {
+	for _, dup := range e.Diagnostics().DuplicateEndpoints {
+		if onlyTarget != "" && !containsString(dup.Targets, onlyTarget) {
+			continue
+		}
+		e.logger.Warn("multiple targets share one endpoint", "endpoint", dup.Endpoint, "targets", strings.Join(dup.Targets, ","))
+	}
+}
+
 func (e *MonitorEngine) DeleteTarget(name string) error {
 	name = strings.TrimSpace(name)
 	if name == "" {
@@ -276,20 +743,48 @@ func (e *MonitorEngine) syncTargets() {
 	nextTargets := make([]*TargetState, 0, len(targetRows))
 	nextByName := make(map[string]*TargetState, len(targetRows))
 	for _, row := range targetRows {
-		if !row.Enabled || row.Name == "" || row.Address == "" || row.Port <= 0 {
+		if !row.Enabled || row.Name == "" || row.Address == "" {
+			continue
+		}
+		if row.Port <= 0 && e.targetConfig[row.Name].Type != "ping" {
 			continue
 		}
 
 		target := &TargetState{
-			Name:    row.Name,
-			Address: row.Address,
-			Port:    row.Port,
+			Name:             row.Name,
+			Address:          row.Address,
+			Port:             row.Port,
+			FailThreshold:    row.FailThreshold,
+			SuccessThreshold: row.SuccessThreshold,
+			CertFingerprint:  row.CertFingerprint,
+			MutedUntil:       row.MutedUntil,
+			Tags:             row.Tags,
+			AckedBy:          row.AckedBy,
+			AckedAt:          row.AckedAt,
+		}
+		applyTargetConfig(target, e.targetConfig[row.Name])
+		if target.FailThreshold == 0 {
+			target.FailThreshold = e.failThreshold
 		}
 		if previous := e.targetByName[row.Name]; previous != nil {
+			target.nextDue = previous.nextDue
+			target.CertNotAfter = previous.CertNotAfter
+			target.certAlert = previous.certAlert
+			target.checks = previous.checks
+			target.checkFailures = previous.checkFailures
 			if previous.Address == row.Address && previous.Port == row.Port {
 				target.LastStatus = previous.LastStatus
 				target.LastChanged = previous.LastChanged
 				target.LastChecked = previous.LastChecked
+				target.LastLatency = previous.LastLatency
+				target.ResolvedIP = previous.ResolvedIP
+				target.ResolvedAddrs = previous.ResolvedAddrs
+				target.Escalated = previous.Escalated
+				target.recent = previous.recent
+				target.failStreak = previous.failStreak
+				target.failingSince = previous.failingSince
+				target.okStreak = previous.okStreak
+				target.slow = previous.slow
 			}
 		}
 
@@ -305,25 +800,241 @@ func (e *MonitorEngine) syncTargets() {
 func buildTargetsFromConfig(items []config.Target) []*TargetState {
 	out := make([]*TargetState, 0, len(items))
 	for _, item := range items {
-		out = append(out, &TargetState{
+		target := &TargetState{
 			Name:    item.Name,
 			Address: item.Address,
 			Port:    item.Port,
-		})
+			Tags:    item.Tags,
+		}
+		applyTargetConfig(target, item)
+		out = append(out, target)
 	}
 	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
 	return out
 }
 
-func checkTCP(ctx context.Context, address string, port int, timeout time.Duration) bool {
-	endpoint := net.JoinHostPort(address, strconv.Itoa(port))
-	dialer := net.Dialer{Timeout: timeout}
-	conn, err := dialer.DialContext(ctx, "tcp", endpoint)
-	if err != nil {
+// applyTargetConfig copies per-target options from config onto a target loaded
+// from the store. Targets added at runtime have no config entry and keep defaults.
+func applyTargetConfig(target *TargetState, item config.Target) {
+	target.Mention = item.Mention
+	target.RunbookURL = item.RunbookURL
+	target.Probes = item.Probes
+	target.MinHealthy = item.MinHealthyProbes
+	target.WindowChecks = item.WindowChecks
+	target.WindowFailures = item.WindowFailures
+	target.Fallback = item.FallbackCheck
+	target.FallbackRules = newFallbackBodyRules(item.FallbackCheck)
+	target.Critical = item.Critical
+	target.Weight = item.Weight
+	target.Impact = item.Impact
+	target.WatchCert = item.WatchCert
+	target.AlertGate = item.AlertGate
+	target.CheckType = item.Type
+	target.HTTPPath = item.Path
+	target.ExpectStatus = item.ExpectStatus
+	target.BodyRules = newBodyRules(item.HealthyRegex, item.UnhealthyRegex)
+	target.UDPPayload = []byte(item.UDPPayload)
+	if item.UDPPayloadHex != "" {
+		// Load has already rejected invalid hex.
+		target.UDPPayload, _ = hex.DecodeString(item.UDPPayloadHex)
+	}
+	target.UDPExpect = []byte(item.UDPExpect)
+	target.UDPNoResponseUp = item.UDPNoResponse == "up"
+	target.Interval = time.Duration(item.IntervalSeconds) * time.Second
+	target.CertWarnDays = item.CertWarnDays
+	target.SlowThreshold = time.Duration(item.SlowThresholdMs) * time.Millisecond
+	// A threshold stored with /threshold wins over the config.
+	if target.FailThreshold == 0 {
+		target.FailThreshold = item.FailureThreshold
+	}
+	target.Schedule = nil
+	if item.Schedule != "" {
+		// Load has already rejected invalid expressions.
+		if schedule, err := cron.ParseStandard(item.Schedule); err == nil {
+			target.Schedule = schedule
+		}
+	}
+}
+
+// inSchedule reports whether the minute containing now matches the schedule.
+// Targets without a schedule are always checked.
+func inSchedule(schedule cron.Schedule, now time.Time) bool {
+	if schedule == nil {
+		return true
+	}
+	minute := now.Truncate(time.Minute)
+	return schedule.Next(minute.Add(-time.Second)).Equal(minute)
+}
+
+// smoothedStatus records a raw result and returns the status to apply. A
+// window policy takes precedence over thresholds; without either every result
+// counts on its own.
+func (t *TargetState) smoothedStatus(up bool) bool {
+	if t.WindowChecks <= 0 {
+		if t.LastStatus == nil || up == *t.LastStatus {
+			return up
+		}
+		if up {
+			return t.okStreak >= t.SuccessThreshold
+		}
+		return t.failStreak < t.FailThreshold
+	}
+	t.recent = append(t.recent, up)
+	if len(t.recent) > t.WindowChecks {
+		t.recent = t.recent[len(t.recent)-t.WindowChecks:]
+	}
+	failures := 0
+	for _, ok := range t.recent {
+		if !ok {
+			failures++
+		}
+	}
+	return failures < t.WindowFailures
+}
+
+// checkTarget runs a single check of the target's type, or for multi-probe
+// targets that many, reporting UP while enough of them succeed.
+func (p probeOptions) checkTarget(ctx context.Context, target *TargetState, timeout time.Duration) checkResult {
+	probe := func() checkResult { return p.checkTCP(ctx, target.Address, target.Port, timeout) }
+	switch target.CheckType {
+	case "http", "https":
+		probe = func() checkResult { return p.checkTargetHTTP(ctx, target, timeout) }
+	case "ping":
+		probe = func() checkResult { return p.checkICMP(ctx, target.Address, timeout) }
+	case "udp":
+		probe = func() checkResult { return p.checkUDP(ctx, target, timeout) }
+	}
+	if target.Probes <= 1 {
+		return probe()
+	}
+	results := make([]checkResult, target.Probes)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = probe()
+		}(i)
+	}
+	wg.Wait()
+	return combineProbes(results, target.MinHealthy)
+}
+
+func combineProbes(results []checkResult, minHealthy int) checkResult {
+	if minHealthy <= 0 {
+		minHealthy = len(results)
+	}
+	out := checkResult{Probes: len(results)}
+	var total time.Duration
+	for _, result := range results {
+		if out.IP == "" {
+			out.IP, out.Addrs = result.IP, result.Addrs
+		}
+		if result.Up {
+			out.Healthy++
+			total += result.Latency
+		}
+	}
+	if out.Healthy > 0 {
+		out.Latency = total / time.Duration(out.Healthy)
+	}
+	out.Up = out.Healthy >= minHealthy
+	return out
+}
+
+// checkTCP connects to the target, retrying a failed dial up to p.retries
+// times with a growing delay in between. All attempts share timeout, so a
+// down target costs one timeout however often it is retried: a retry only
+// starts while more than its delay is left, and gets what remains. A failure
+// after retries names the attempts made, e.g. connect-timeout-after-3.
+func (p probeOptions) checkTCP(ctx context.Context, address string, port int, timeout time.Duration) checkResult {
+	deadline := time.Now().Add(timeout)
+	for attempt := 1; ; attempt++ {
+		result := p.dialTCP(ctx, address, port, time.Until(deadline))
+		if result.Up || result.Status == "dns-failure" {
+			return result
+		}
+		delay := time.Duration(attempt) * probeRetryDelay
+		if attempt > p.retries || time.Until(deadline) <= delay || !sleepContext(ctx, delay) {
+			if attempt > 1 {
+				result.Status = fmt.Sprintf("%s-after-%d", result.Status, attempt)
+			}
+			return result
+		}
+	}
+}
+
+// sleepContext waits for d and reports false if ctx ended first.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
 		return false
+	case <-timer.C:
+		return true
 	}
+}
+
+// dialTCP resolves a host name itself, so a DNS failure is reported as
+// dns-failure rather than as a closed port, and then connects to the
+// resolved address within timeout.
+func (p probeOptions) dialTCP(ctx context.Context, address string, port int, timeout time.Duration) checkResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ip, addrs, err := resolveTarget(ctx, address)
+	if err != nil {
+		return checkResult{Status: "dns-failure"}
+	}
+	result := checkResult{Addrs: addrs}
+	if addrs > 0 {
+		result.IP = ip.String()
+	}
+	startedAt := time.Now()
+	conn, err := p.dialer().DialContext(ctx, "tcp", net.JoinHostPort(ip.String(), strconv.Itoa(port)))
+	if err != nil {
+		result.Status = connectFailure(err)
+		return result
+	}
+	result.Up = true
+	result.Latency = time.Since(startedAt)
 	_ = conn.Close()
-	return true
+	return result
+}
+
+func duplicateEndpoints(targets []*TargetState) []DuplicateEndpoint {
+	byEndpoint := make(map[string][]string, len(targets))
+	for _, target := range targets {
+		endpoint := config.NormalizeEndpoint(target.CheckType, target.Address, target.Port)
+		byEndpoint[endpoint] = append(byEndpoint[endpoint], target.Name)
+	}
+
+	out := make([]DuplicateEndpoint, 0)
+	for endpoint, names := range byEndpoint {
+		if len(names) < 2 {
+			continue
+		}
+		sort.Strings(names)
+		out = append(out, DuplicateEndpoint{Endpoint: endpoint, Targets: names})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Endpoint < out[j].Endpoint })
+	return out
+}
+
+func containsString(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func autoDisableAfter(cfg config.Config) time.Duration {
+	if !cfg.Monitoring.AutoDisable || cfg.Monitoring.AutoDisableAfterHours <= 0 {
+		return 0
+	}
+	return time.Duration(cfg.Monitoring.AutoDisableAfterHours) * time.Hour
 }
 
 func defaultSeconds(value int, fallback int) time.Duration {
@@ -1,6 +1,25 @@
 package tracker
 
-import "testing"
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"trackway/internal/config"
+	"trackway/internal/logstore"
+)
 
 func TestDefaultWorkersAppliesLimits(t *testing.T) {
 	t.Parallel()
@@ -15,3 +34,622 @@ func TestDefaultWorkersAppliesLimits(t *testing.T) {
 		t.Fatalf("expected hard limit %d, got %d", maxParallelChecksHardLimit, got)
 	}
 }
+
+func TestCombineProbesReportsFraction(t *testing.T) {
+	t.Parallel()
+
+	results := []checkResult{
+		{Up: true, Latency: 10 * time.Millisecond},
+		{Up: false},
+		{Up: true, Latency: 20 * time.Millisecond},
+		{Up: false},
+		{Up: true, Latency: 30 * time.Millisecond},
+	}
+	got := combineProbes(results, 0)
+	if got.Up || got.Healthy != 3 || got.Probes != 5 {
+		t.Fatalf("expected 3/5 healthy and DOWN by default, got %+v", got)
+	}
+	if got.Latency != 20*time.Millisecond {
+		t.Fatalf("expected mean healthy latency, got %s", got.Latency)
+	}
+	if !combineProbes(results, 3).Up {
+		t.Fatal("expected UP when healthy probes meet threshold")
+	}
+}
+
+func TestInSchedule(t *testing.T) {
+	t.Parallel()
+
+	schedule, err := cron.ParseStandard("CRON_TZ=UTC * 9-17 * * 1-5")
+	if err != nil {
+		t.Fatalf("parse schedule: %v", err)
+	}
+	if !inSchedule(nil, time.Now()) {
+		t.Fatal("expected targets without schedule to always be checked")
+	}
+	// 2026-10-14 is a Wednesday.
+	if !inSchedule(schedule, time.Date(2026, 10, 14, 9, 30, 15, 0, time.UTC)) {
+		t.Fatal("expected weekday working hours to be in schedule")
+	}
+	if inSchedule(schedule, time.Date(2026, 10, 14, 18, 0, 0, 0, time.UTC)) {
+		t.Fatal("expected evening to be outside schedule")
+	}
+	if inSchedule(schedule, time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)) {
+		t.Fatal("expected weekend to be outside schedule")
+	}
+}
+
+func TestSmoothedStatusUsesWindowMajority(t *testing.T) {
+	t.Parallel()
+
+	target := &TargetState{WindowChecks: 5, WindowFailures: 3}
+	results := []bool{true, false, true, false, true, false, false, true, true, true}
+	want := []bool{true, true, true, true, true, false, false, false, true, true}
+	for i, up := range results {
+		if got := target.smoothedStatus(up); got != want[i] {
+			t.Fatalf("check %d: expected %v, got %v (window %v)", i, want[i], got, target.recent)
+		}
+	}
+
+	plain := &TargetState{}
+	if plain.smoothedStatus(false) || !plain.smoothedStatus(true) {
+		t.Fatal("expected targets without a window to follow raw results")
+	}
+}
+
+func TestFilterMutedDropsOnlyMutedTargets(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	store, err := logstore.NewMemory()
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	if err := store.UpsertTarget("expired", "127.0.0.1", 1); err != nil {
+		t.Fatalf("upsert target: %v", err)
+	}
+	engine := &MonitorEngine{
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		logs:   store,
+		targetByName: map[string]*TargetState{
+			"muted":   {Name: "muted", MutedUntil: now.Add(time.Hour)},
+			"expired": {Name: "expired", MutedUntil: now.Add(-time.Minute)},
+		},
+	}
+	events := engine.filterMuted([]alertEvent{
+		{Target: "muted", Kind: "DOWN"},
+		{Target: "expired", Kind: "DOWN"},
+		{Kind: "STORAGE_DEGRADED"},
+	}, now)
+	if len(events) != 2 || events[0].Target != "expired" || events[1].Kind != "STORAGE_DEGRADED" {
+		t.Fatalf("unexpected events after mute filter: %+v", events)
+	}
+
+	if _, err := engine.Mute("missing", time.Time{}); err != ErrTargetNotFound {
+		t.Fatalf("expected ErrTargetNotFound, got %v", err)
+	}
+	until, err := engine.Mute("expired", now.Add(365*24*time.Hour))
+	if err != nil || until.After(now.Add(maxMuteDuration+time.Minute)) {
+		t.Fatalf("expected capped mute, got %s err=%v", until, err)
+	}
+}
+
+func TestCheckWithFallbackRecordsDecidingMethod(t *testing.T) {
+	t.Parallel()
+
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer healthy.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	closedPort := listener.Addr().(*net.TCPAddr).Port
+	_ = listener.Close()
+
+	target := &TargetState{
+		Name:     "a",
+		Address:  "127.0.0.1",
+		Port:     closedPort,
+		Fallback: &config.FallbackCheck{Type: "http", URL: healthy.URL},
+	}
+	got := (probeOptions{}).checkWithFallback(context.Background(), target, time.Second)
+	if !got.Up || got.Method != "fallback-http" {
+		t.Fatalf("expected UP via fallback-http, got %+v", got)
+	}
+
+	target.Fallback = &config.FallbackCheck{Type: "tcp", Port: closedPort}
+	got = (probeOptions{}).checkWithFallback(context.Background(), target, time.Second)
+	if got.Up || got.Method != "primary+fallback" {
+		t.Fatalf("expected DOWN after both checks, got %+v", got)
+	}
+}
+
+func TestCheckTCPResolvesHostNamesAndNamesFailures(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+	openPort := listener.Addr().(*net.TCPAddr).Port
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	_ = closed.Close()
+
+	ctx := context.Background()
+	if got := (probeOptions{}).checkTCP(ctx, "localhost", openPort, time.Second); !got.Up || got.IP != "127.0.0.1" || got.Addrs < 1 {
+		t.Fatalf("expected localhost to resolve to 127.0.0.1 first, got %+v", got)
+	}
+	if got := (probeOptions{}).checkTCP(ctx, "127.0.0.1", closedPort, time.Second); got.Up || got.Status != "connect-refused" || got.IP != "" {
+		t.Fatalf("expected connect-refused without a resolved IP, got %+v", got)
+	}
+	if got := (probeOptions{}).checkTCP(ctx, "trackway-missing.invalid", openPort, time.Second); got.Up || got.Status != "dns-failure" {
+		t.Fatalf("expected dns-failure, got %+v", got)
+	}
+
+	store, err := logstore.NewMemory()
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	engine := NewMonitorEngine(config.Config{}, store)
+	target := &TargetState{Name: "web", Address: "localhost", Port: openPort}
+	engine.applyResult(target, checkResult{Up: true, IP: "127.0.0.1", Addrs: 2})
+	engine.applyResult(target, checkResult{Status: "dns-failure"})
+	rows := store.ReadLastDays("web", 1, 10)
+	if len(rows) != 2 || rows[0].Endpoint != fmt.Sprintf("localhost:%d (127.0.0.1)", openPort) || rows[1].Reason != "CHANGE DNS-FAILURE" {
+		t.Fatalf("expected the resolved IP and failure reason in the log rows, got %+v", rows)
+	}
+	if target.ResolvedIP != "127.0.0.1" || target.ResolvedAddrs != 2 {
+		t.Fatalf("expected the last resolved IP to be kept, got %q/%d", target.ResolvedIP, target.ResolvedAddrs)
+	}
+}
+
+func TestCheckTCPRetriesFailedDials(t *testing.T) {
+	t.Parallel()
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := closed.Addr().(*net.TCPAddr).Port
+	_ = closed.Close()
+
+	probe := probeOptions{retries: 2}
+	ctx := context.Background()
+	startedAt := time.Now()
+	if got := probe.checkTCP(ctx, "127.0.0.1", port, time.Second); got.Up || got.Status != "connect-refused-after-3" {
+		t.Fatalf("expected three attempts, got %+v", got)
+	}
+	if elapsed := time.Since(startedAt); elapsed < 3*probeRetryDelay {
+		t.Fatalf("expected backoff between attempts, took %s", elapsed)
+	}
+	if got := (probeOptions{}).checkTCP(ctx, "127.0.0.1", port, time.Second); got.Status != "connect-refused" {
+		t.Fatalf("expected a single attempt without retries, got %+v", got)
+	}
+	if got := probe.checkTCP(ctx, "trackway-missing.invalid", port, time.Second); got.Status != "dns-failure" {
+		t.Fatalf("expected dns failures not to be retried, got %+v", got)
+	}
+
+	startedAt = time.Now()
+	budgeted := probeOptions{retries: 5}.checkTCP(ctx, "127.0.0.1", port, 500*time.Millisecond)
+	if elapsed := time.Since(startedAt); elapsed > 600*time.Millisecond || budgeted.Status != "connect-refused-after-2" {
+		t.Fatalf("expected retries to stop within the timeout, got %+v after %s", budgeted, elapsed)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if got := probe.checkTCP(cancelled, "127.0.0.1", port, time.Second); got.Up || strings.Contains(got.Status, "-after-") {
+		t.Fatalf("expected a cancelled check to stop after one attempt, got %+v", got)
+	}
+
+	store, err := logstore.NewMemory()
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	engine := NewMonitorEngine(config.Config{}, store)
+	target := &TargetState{Name: "db", Address: "127.0.0.1", Port: port}
+	engine.applyResult(target, checkResult{Status: "connect-timeout-after-3"})
+	if rows := store.ReadLastDays("db", 1, 10); len(rows) != 1 || rows[0].Reason != "INIT CONNECT-TIMEOUT-AFTER-3" {
+		t.Fatalf("expected the attempts in the log reason, got %+v", rows)
+	}
+}
+
+func TestCheckUDPMatchesReplies(t *testing.T) {
+	t.Parallel()
+
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer server.Close()
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := server.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			// "quiet" gets no reply so the no-response path can be tested.
+			if string(buf[:n]) != "quiet" {
+				_, _ = server.WriteTo(append([]byte("pong:"), buf[:n]...), addr)
+			}
+		}
+	}()
+	port := server.LocalAddr().(*net.UDPAddr).Port
+
+	target := &TargetState{Address: "127.0.0.1", Port: port, UDPPayload: []byte("ping"), UDPExpect: []byte("pong:ping")}
+	if got := (probeOptions{}).checkUDP(context.Background(), target, time.Second); !got.Up || got.Latency <= 0 {
+		t.Fatalf("expected matching reply to be up, got %+v", got)
+	}
+	target.UDPExpect = []byte("other")
+	if got := (probeOptions{}).checkUDP(context.Background(), target, time.Second); got.Up || got.Status != "udp-unexpected-response" {
+		t.Fatalf("expected mismatching reply to be down, got %+v", got)
+	}
+
+	target = &TargetState{Address: "127.0.0.1", Port: port, UDPPayload: []byte("quiet")}
+	if got := (probeOptions{}).checkUDP(context.Background(), target, 100*time.Millisecond); got.Up || got.Status != "udp-no-response" {
+		t.Fatalf("expected silence to be down by default, got %+v", got)
+	}
+	target.UDPNoResponseUp = true
+	if got := (probeOptions{}).checkUDP(context.Background(), target, 100*time.Millisecond); !got.Up || got.Status != "udp-no-response" {
+		t.Fatalf("expected silence to be up when configured, got %+v", got)
+	}
+
+	closed, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	closedPort := closed.LocalAddr().(*net.UDPAddr).Port
+	_ = closed.Close()
+	target = &TargetState{Address: "127.0.0.1", Port: closedPort, UDPPayload: []byte("ping"), UDPNoResponseUp: true}
+	if got := (probeOptions{}).checkUDP(context.Background(), target, time.Second); got.Up || got.Status != "udp-refused" {
+		t.Fatalf("expected closed port to be refused, got %+v", got)
+	}
+}
+
+func TestLastRoundAtIsSetAfterEachRound(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.NewMemory()
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	engine := NewMonitorEngine(config.Config{}, store)
+	if !engine.LastRoundAt().IsZero() {
+		t.Fatalf("expected no round before the first one, got %s", engine.LastRoundAt())
+	}
+	before := time.Now()
+	engine.runChecks(context.Background(), nil)
+	if got := engine.LastRoundAt(); got.Before(before.Add(-time.Second)) || got.After(time.Now()) {
+		t.Fatalf("expected a round without due targets to count, got %s", got)
+	}
+}
+
+func TestProbesConnectFromSourceAddress(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+	remotes := make(chan string, 1)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			remotes <- conn.RemoteAddr().String()
+			_ = conn.Close()
+		}
+	}()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	probe := probeOptions{source: netip.MustParseAddr("127.0.0.2")}
+	ctx := context.Background()
+	if got := probe.checkTCP(ctx, "127.0.0.1", port, time.Second); !got.Up {
+		t.Fatalf("expected tcp check to succeed, got %+v", got)
+	}
+	if remote := <-remotes; !strings.HasPrefix(remote, "127.0.0.2:") {
+		t.Fatalf("expected the tcp probe from 127.0.0.2, got %s", remote)
+	}
+	if got := (probeOptions{}).checkTCP(ctx, "127.0.0.1", port, time.Second); !got.Up {
+		t.Fatalf("expected tcp check to succeed, got %+v", got)
+	}
+	if remote := <-remotes; !strings.HasPrefix(remote, "127.0.0.1:") {
+		t.Fatalf("expected the OS default source without a source address, got %s", remote)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.RemoteAddr, "127.0.0.2:") {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+	if got := probe.checkHTTP(ctx, server.URL, time.Second, nil); !got.Up {
+		t.Fatal("expected the http probe from 127.0.0.2")
+	}
+}
+
+func TestHTTPFallbackBodyRulesOverrideStatus(t *testing.T) {
+	t.Parallel()
+
+	body := "status: DEGRADED"
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	closedPort := listener.Addr().(*net.TCPAddr).Port
+	_ = listener.Close()
+
+	check := &config.FallbackCheck{Type: "http", URL: server.URL, HealthyRegex: `status: (OK|DEGRADED)`, UnhealthyRegex: `DEGRADED`}
+	target := &TargetState{Name: "a", Address: "127.0.0.1", Port: closedPort, Fallback: check, FallbackRules: newFallbackBodyRules(check)}
+	if got := (probeOptions{}).checkWithFallback(context.Background(), target, time.Second); got.Up || got.Rule != "unhealthy_regex" {
+		t.Fatalf("expected unhealthy match to win, got %+v", got)
+	}
+
+	mu.Lock()
+	body = "status: OK"
+	mu.Unlock()
+	if got := (probeOptions{}).checkWithFallback(context.Background(), target, time.Second); !got.Up || got.Rule != "healthy_regex" || got.Method != "fallback-http" {
+		t.Fatalf("expected healthy body to override the 503, got %+v", got)
+	}
+
+	mu.Lock()
+	body = "maintenance"
+	mu.Unlock()
+	if got := (probeOptions{}).checkWithFallback(context.Background(), target, time.Second); got.Up || got.Rule != "no-healthy-match" {
+		t.Fatalf("expected DOWN without a healthy match, got %+v", got)
+	}
+}
+
+func TestHTTPCheckTypeMatchesExpectedStatus(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	addr := server.Listener.Addr().(*net.TCPAddr)
+
+	target := &TargetState{Name: "a", Address: "127.0.0.1", Port: addr.Port, CheckType: "http", HTTPPath: "/healthz"}
+	if got := (probeOptions{}).checkTarget(context.Background(), target, time.Second); !got.Up || got.Status != "http 202" {
+		t.Fatalf("expected 202 to be UP without expect_status, got %+v", got)
+	}
+	target.ExpectStatus = http.StatusOK
+	if got := (probeOptions{}).checkTarget(context.Background(), target, time.Second); got.Up || got.Status != "http 202" {
+		t.Fatalf("expected 202 to be DOWN with expect_status 200, got %+v", got)
+	}
+	target.ExpectStatus = 0
+	target.HTTPPath = "/missing"
+	if got := (probeOptions{}).checkTarget(context.Background(), target, time.Second); got.Up || got.Status != "http 404" {
+		t.Fatalf("expected 404 to be DOWN, got %+v", got)
+	}
+}
+
+func TestHTTPCheckTypeBodyRulesOverrideStatus(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"status":"DEGRADED"}`)
+	}))
+	defer server.Close()
+	addr := server.Listener.Addr().(*net.TCPAddr)
+
+	target := &TargetState{Name: "a", Address: "127.0.0.1", Port: addr.Port, CheckType: "http", BodyRules: newBodyRules("", `DEGRADED`)}
+	if got := (probeOptions{}).checkTarget(context.Background(), target, time.Second); got.Up || got.Rule != "unhealthy_regex" || got.Status != "http 200" {
+		t.Fatalf("expected a 200 DEGRADED page to be DOWN, got %+v", got)
+	}
+	target.BodyRules = newBodyRules(`"status":"(OK|DEGRADED)"`, "")
+	if got := (probeOptions{}).checkTarget(context.Background(), target, time.Second); !got.Up || got.Rule != "healthy_regex" {
+		t.Fatalf("expected the healthy rule to match, got %+v", got)
+	}
+}
+
+func TestPingCheckTypeReachesLoopback(t *testing.T) {
+	t.Parallel()
+
+	target := &TargetState{Name: "nas", Address: "127.0.0.1", CheckType: "ping"}
+	got := (probeOptions{}).checkTarget(context.Background(), target, time.Second)
+	if got.Status == "icmp-unavailable" {
+		t.Skip("no ICMP socket available")
+	}
+	if !got.Up {
+		t.Fatalf("expected loopback to answer ping, got %+v", got)
+	}
+}
+
+func TestApplyGatesHoldsGatedAlertsWhileGateIsDown(t *testing.T) {
+	t.Parallel()
+
+	down, up := false, true
+	gateway := &TargetState{Name: "gw", Address: "10.0.0.1", Port: 53, LastStatus: &up}
+	api := &TargetState{Name: "api", Address: "10.0.1.1", Port: 443, AlertGate: "gw", LastStatus: &up}
+	db := &TargetState{Name: "db", Address: "10.0.1.2", Port: 5432, AlertGate: "gw", LastStatus: &down}
+	engine := &MonitorEngine{
+		logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		targets:      []*TargetState{api, db, gateway},
+		targetByName: map[string]*TargetState{"gw": gateway, "api": api, "db": db},
+	}
+	kinds := func(events []alertEvent) string {
+		out := make([]string, 0, len(events))
+		for _, event := range events {
+			out = append(out, event.Kind+":"+event.Target)
+		}
+		return strings.Join(out, ",")
+	}
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+	// db went DOWN (and alerted) before the gateway; then the gateway fails.
+	gateway.LastStatus, api.LastStatus = &down, &down
+	got := engine.applyGates([]alertEvent{{Kind: "DOWN", Target: "gw"}, {Kind: "DOWN", Target: "api"}}, now)
+	if kinds(got) != "DOWN:gw,GATE_DOWN:gw" || !strings.Contains(got[1].Detail, "2 gated target(s): api, db") {
+		t.Fatalf("unexpected events while gate fails: %+v", got)
+	}
+
+	db.LastStatus = &up
+	got = engine.applyGates([]alertEvent{{Kind: "RECOVERED", Target: "db"}, {Kind: "STILL_DOWN", Target: "api"}}, now)
+	if kinds(got) != "RECOVERED:db" {
+		t.Fatalf("expected only the recovery of an already alerted DOWN, got %s", kinds(got))
+	}
+
+	gateway.LastStatus = &up
+	got = engine.applyGates([]alertEvent{{Kind: "RECOVERED", Target: "gw"}}, now)
+	if kinds(got) != "RECOVERED:gw,DOWN:api" || got[1].Reason != "gate-recovered" {
+		t.Fatalf("expected held DOWN to be released after the gate, got %+v", got)
+	}
+	if got = engine.applyGates([]alertEvent{{Kind: "RECOVERED", Target: "api"}}, now); kinds(got) != "RECOVERED:api" {
+		t.Fatalf("expected normal alerts after release, got %s", kinds(got))
+	}
+}
+
+func TestCheckTimeoutUsesDownTimeoutForDownTargets(t *testing.T) {
+	t.Parallel()
+
+	down, up := false, true
+	engine := &MonitorEngine{downTimeout: 10 * time.Second}
+	for _, tc := range []struct {
+		status *bool
+		want   time.Duration
+	}{
+		{status: nil, want: 2 * time.Second},
+		{status: &up, want: 2 * time.Second},
+		{status: &down, want: 10 * time.Second},
+	} {
+		if got := engine.checkTimeout(&TargetState{LastStatus: tc.status}, 2*time.Second); got != tc.want {
+			t.Fatalf("status %v: expected %s, got %s", tc.status, tc.want, got)
+		}
+	}
+	engine.downTimeout = 0
+	if got := engine.checkTimeout(&TargetState{LastStatus: &down}, 2*time.Second); got != 2*time.Second {
+		t.Fatalf("expected the single timeout when unset, got %s", got)
+	}
+}
+
+func TestBuildUptimeGraphBucketsRows(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) string { return now.Add(-d).Format(time.RFC3339) }
+	rows := []logstore.Row{
+		{Timestamp: at(150 * time.Minute), Status: "UP"},
+		{Timestamp: at(110 * time.Minute), Status: "UP"},
+		{Timestamp: at(100 * time.Minute), Status: "DOWN"},
+		{Timestamp: at(50 * time.Minute), Status: "UP", LastSeen: at(5 * time.Minute), Count: 10},
+	}
+	got := buildUptimeGraph(rows, now, 4*time.Hour, 8)
+	if got != "···█░·██" {
+		t.Fatalf("unexpected graph %q", got)
+	}
+}
+
+func TestBuildOverallSLAReportUnionsCriticalDowntime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) time.Time { return now.Add(-d) }
+	stats := map[string]logstore.CheckStats{
+		"a": {Checks: 60, UpChecks: 50, First: ago(60 * time.Minute)},
+		"b": {Checks: 60, UpChecks: 45, First: ago(60 * time.Minute)},
+	}
+	incidents := map[string][]logstore.Incident{
+		"a": {{Start: ago(40 * time.Minute), End: ago(30 * time.Minute), Duration: 10 * time.Minute}},
+		"b": {{Start: ago(35 * time.Minute), End: ago(20 * time.Minute), Duration: 15 * time.Minute}},
+	}
+
+	report := buildOverallSLAReport(map[string]float64{"a": 1, "b": 3}, stats, incidents, now, 7)
+	if report.Covered != time.Hour || report.Downtime != 20*time.Minute {
+		t.Fatalf("unexpected covered/downtime: %s/%s", report.Covered, report.Downtime)
+	}
+	if math.Abs(report.UptimePercent-66.667) > 0.001 {
+		t.Fatalf("unexpected service uptime %.3f", report.UptimePercent)
+	}
+	if math.Abs(report.WeightedUptimePercent-77.083) > 0.001 {
+		t.Fatalf("unexpected weighted uptime %.3f", report.WeightedUptimePercent)
+	}
+	if len(report.Targets) != 2 || report.Targets[0].Name != "a" || report.Targets[1].Weight != 3 {
+		t.Fatalf("unexpected targets %+v", report.Targets)
+	}
+}
+
+func TestBuildWindowComparisonSplitsOutagesAcrossWindows(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	ago := func(d time.Duration) time.Time { return now.Add(-d) }
+	incidents := []logstore.Incident{
+		{Start: ago(time.Hour), Duration: time.Hour, Ongoing: true},
+		// started in the previous window and recovered in the current one
+		{Start: ago(25 * time.Hour), End: ago(23 * time.Hour), Duration: 2 * time.Hour},
+		{Start: ago(36 * time.Hour), End: ago(35 * time.Hour), Duration: time.Hour},
+		// ended before the previous window opened
+		{Start: ago(3 * day), End: ago(2*day + time.Hour), Duration: 23 * time.Hour},
+	}
+	currentChecks := logstore.CheckStats{Checks: 100, UpChecks: 80}
+	previousChecks := logstore.CheckStats{Checks: 200, UpChecks: 180}
+
+	comparison := buildWindowComparison("api", currentChecks, previousChecks, incidents, now, 1)
+	current, previous := comparison.Current, comparison.Previous
+	if previous.Outages != 2 || previous.Downtime != 2*time.Hour || previous.Checks != 200 {
+		t.Fatalf("unexpected previous window %+v", previous)
+	}
+	if current.Outages != 1 || current.Downtime != 2*time.Hour || current.Checks != 100 {
+		t.Fatalf("unexpected current window %+v", current)
+	}
+	if current.UptimePercent != 80 || previous.UptimePercent != 90 {
+		t.Fatalf("unexpected uptime %.2f vs %.2f", current.UptimePercent, previous.UptimePercent)
+	}
+}
+
+func TestWarmUpResolvesHostnamesWithoutChangingState(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	targets := []*TargetState{
+		{Name: "ip", Address: "127.0.0.1", Port: port},
+		{Name: "bad", Address: "missing.invalid", Port: port},
+	}
+	engine := &MonitorEngine{
+		logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		timeout: time.Second,
+		targets: targets,
+	}
+	failed := engine.resolveTargets(context.Background(), targets, time.Second, 0)
+	if len(failed) != 1 || failed[0] != "bad" {
+		t.Fatalf("expected only the .invalid host to fail, got %v", failed)
+	}
+	if up := engine.warmupRound(context.Background(), targets, time.Second, 0); up != 1 {
+		t.Fatalf("expected one reachable target, got %d", up)
+	}
+	if targets[0].LastStatus != nil || !targets[0].LastChecked.IsZero() {
+		t.Fatalf("warm-up must not record state, got %+v", targets[0])
+	}
+}
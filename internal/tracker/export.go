@@ -0,0 +1,65 @@
+package tracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"trackway/internal/config"
+)
+
+// EncodeTargets renders targets in a shape that can be pasted into the
+// "targets" section of a config file. YAML values are emitted as JSON
+// scalars/flow collections, which YAML parsers accept as-is.
+func EncodeTargets(targets []config.Target, format string) ([]byte, error) {
+	if targets == nil {
+		targets = []config.Target{}
+	}
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", "json":
+		data, err := json.MarshalIndent(map[string]any{"targets": targets}, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case "yaml", "yml":
+		return encodeTargetsYAML(targets)
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+func encodeTargetsYAML(targets []config.Target) ([]byte, error) {
+	var sb strings.Builder
+	if len(targets) == 0 {
+		sb.WriteString("targets: []\n")
+		return []byte(sb.String()), nil
+	}
+	sb.WriteString("targets:\n")
+	for _, target := range targets {
+		value := reflect.ValueOf(target)
+		first := true
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			if strings.Contains(opts, "omitempty") && value.Field(i).IsZero() {
+				continue
+			}
+			encoded, err := json.Marshal(value.Field(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			prefix := "    "
+			if first {
+				prefix = "  - "
+				first = false
+			}
+			fmt.Fprintf(&sb, "%s%s: %s\n", prefix, name, encoded)
+		}
+	}
+	return []byte(sb.String()), nil
+}
@@ -0,0 +1,149 @@
+package tracker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"trackway/internal/config"
+)
+
+const (
+	methodPrimary  = "primary"
+	methodFallback = "fallback"
+
+	// maxHealthBodyBytes bounds how much of a response body rules look at.
+	maxHealthBodyBytes = 64 << 10
+)
+
+// bodyRules decide an http check from its response body. Rule names end up
+// in the log reason.
+type bodyRules struct {
+	healthy   *regexp.Regexp
+	unhealthy *regexp.Regexp
+}
+
+func newBodyRules(healthy, unhealthy string) *bodyRules {
+	if healthy == "" && unhealthy == "" {
+		return nil
+	}
+	// Load has already rejected invalid expressions.
+	rules := &bodyRules{}
+	if healthy != "" {
+		rules.healthy, _ = regexp.Compile(healthy)
+	}
+	if unhealthy != "" {
+		rules.unhealthy, _ = regexp.Compile(unhealthy)
+	}
+	return rules
+}
+
+// newFallbackBodyRules returns the body rules of an http fallback check.
+func newFallbackBodyRules(check *config.FallbackCheck) *bodyRules {
+	if check == nil {
+		return nil
+	}
+	return newBodyRules(check.HealthyRegex, check.UnhealthyRegex)
+}
+
+// match reports the state the body implies and the rule that decided it.
+// Without a healthy rule, a body the unhealthy rule does not match is up.
+func (r *bodyRules) match(body []byte) (bool, string) {
+	if r.unhealthy != nil && r.unhealthy.Match(body) {
+		return false, "unhealthy_regex"
+	}
+	if r.healthy == nil {
+		return true, "no-unhealthy-match"
+	}
+	if r.healthy.Match(body) {
+		return true, "healthy_regex"
+	}
+	return false, "no-healthy-match"
+}
+
+// Recheck runs an out-of-band check of trackName without touching its state.
+// Unknown targets report up so that nothing waits on them.
+func (e *MonitorEngine) Recheck(ctx context.Context, trackName string) bool {
+	e.mu.RLock()
+	target, ok := e.targetByName[trackName]
+	var snapshot TargetState
+	if ok {
+		snapshot = *target
+	}
+	timeout := e.timeout
+	e.mu.RUnlock()
+	if !ok {
+		return true
+	}
+	return e.probe.checkWithFallback(ctx, &snapshot, e.checkTimeout(target, timeout)).Up
+}
+
+// checkWithFallback runs the primary check and, when it fails and the target
+// has a fallback, the fallback check. The result records which method decided
+// the state so it ends up in the log reason.
+func (p probeOptions) checkWithFallback(ctx context.Context, target *TargetState, timeout time.Duration) checkResult {
+	result := p.checkTarget(ctx, target, timeout)
+	if target.Fallback == nil {
+		return result
+	}
+	if result.Up {
+		result.Method = methodPrimary
+		return result
+	}
+	fallback := p.checkFallback(ctx, target, timeout)
+	if !fallback.Up {
+		result.Method = methodPrimary + "+" + methodFallback
+		result.Rule = fallback.Rule
+		return result
+	}
+	fallback.Method = methodFallback + "-" + target.Fallback.Type
+	return fallback
+}
+
+func (p probeOptions) checkFallback(ctx context.Context, target *TargetState, timeout time.Duration) checkResult {
+	check := target.Fallback
+	switch check.Type {
+	case "tcp":
+		address := check.Address
+		if address == "" {
+			address = target.Address
+		}
+		return p.checkTCP(ctx, address, check.Port, timeout)
+	case "http":
+		return p.checkHTTP(ctx, check.URL, timeout, target.FallbackRules)
+	default:
+		return checkResult{}
+	}
+}
+
+// checkHTTP expects a status below 400, or, when rules are set, lets the
+// first maxHealthBodyBytes of the body decide regardless of the status.
+func (p probeOptions) checkHTTP(ctx context.Context, url string, timeout time.Duration, rules *bodyRules) checkResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return checkResult{}
+	}
+	startedAt := time.Now()
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return checkResult{}
+	}
+	defer resp.Body.Close()
+	latency := time.Since(startedAt)
+	if rules == nil {
+		if resp.StatusCode >= http.StatusBadRequest {
+			return checkResult{}
+		}
+		return checkResult{Up: true, Latency: latency}
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxHealthBodyBytes))
+	if err != nil {
+		return checkResult{}
+	}
+	up, rule := rules.match(body)
+	return checkResult{Up: up, Latency: latency, Rule: rule}
+}
@@ -0,0 +1,115 @@
+package tracker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// applyGates holds the alerts of targets whose alert_gate is DOWN. The first
+// cycle a gate is down adds one GATE_DOWN notice listing its gated targets.
+// When the gate recovers, gated targets whose DOWN was held and that are still
+// down get their DOWN alert; those that recovered meanwhile stay quiet.
+func (e *MonitorEngine) applyGates(events []alertEvent, now time.Time) []alertEvent {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	gated := make(map[string][]*TargetState)
+	for _, target := range e.targets {
+		if target.AlertGate != "" {
+			gated[target.AlertGate] = append(gated[target.AlertGate], target)
+		}
+	}
+	if len(gated) == 0 && len(e.gatesDown) == 0 {
+		return events
+	}
+	if e.gatesDown == nil {
+		e.gatesDown = make(map[string]bool)
+		e.gateHeld = make(map[string]bool)
+	}
+
+	gates := make([]string, 0, len(gated))
+	for gate := range gated {
+		gates = append(gates, gate)
+	}
+	sort.Strings(gates)
+	var notices []alertEvent
+	for _, gate := range gates {
+		gateTarget := e.targetByName[gate]
+		down := gateTarget != nil && gateTarget.LastStatus != nil && !*gateTarget.LastStatus
+		switch {
+		case down && !e.gatesDown[gate]:
+			e.gatesDown[gate] = true
+			names := make([]string, 0, len(gated[gate]))
+			for _, target := range gated[gate] {
+				names = append(names, target.Name)
+			}
+			e.logger.Warn("alert gate down, holding gated alerts", "gate", gate, "gated", len(names))
+			notices = append(notices, alertEvent{
+				Kind:     "GATE_DOWN",
+				Target:   gate,
+				Address:  gateTarget.Address,
+				Port:     gateTarget.Port,
+				Reason:   "alert-gate",
+				Detail:   fmt.Sprintf("holding alerts for %d gated target(s): %s", len(names), strings.Join(names, ", ")),
+				Occurred: now,
+				Impact:   gateTarget.Impact,
+				Critical: gateTarget.Critical,
+			})
+		case !down && e.gatesDown[gate]:
+			delete(e.gatesDown, gate)
+			e.logger.Info("alert gate recovered, releasing gated alerts", "gate", gate)
+			for _, target := range gated[gate] {
+				if !e.gateHeld[target.Name] {
+					continue
+				}
+				delete(e.gateHeld, target.Name)
+				if target.LastStatus != nil && !*target.LastStatus {
+					notices = append(notices, alertEvent{
+						Kind:         "DOWN",
+						Target:       target.Name,
+						Address:      target.Address,
+						Port:         target.Port,
+						Reason:       "gate-recovered",
+						Mention:      target.Mention,
+						RunbookURL:   target.RunbookURL,
+						Occurred:     now,
+						FailedChecks: target.failStreak,
+						FailingSince: target.failingSince,
+						Impact:       target.Impact,
+						Critical:     target.Critical,
+					})
+				}
+			}
+		}
+	}
+	// Gates removed from the config no longer hold anything.
+	for gate := range e.gatesDown {
+		if _, ok := gated[gate]; !ok {
+			delete(e.gatesDown, gate)
+		}
+	}
+
+	kept := make([]alertEvent, 0, len(events))
+	for _, event := range events {
+		target := e.targetByName[event.Target]
+		if target == nil || !e.gatesDown[target.AlertGate] || strings.HasPrefix(event.Kind, "CERT_") {
+			kept = append(kept, event)
+			continue
+		}
+		switch event.Kind {
+		case "DOWN":
+			e.gateHeld[target.Name] = true
+		case "RECOVERED":
+			// A DOWN sent before the gate failed still needs its recovery.
+			if !e.gateHeld[target.Name] {
+				kept = append(kept, event)
+				continue
+			}
+			delete(e.gateHeld, target.Name)
+		}
+		e.logger.Debug("alert held by gate", "track", target.Name, "gate", target.AlertGate, "kind", event.Kind)
+	}
+	return append(kept, notices...)
+}
@@ -0,0 +1,110 @@
+package tracker
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"time"
+
+	"trackway/internal/logstore"
+)
+
+const (
+	graphColumns = 36
+	graphUp      = '█'
+	graphDown    = '░'
+	graphUnknown = '·'
+
+	// graphPixelsPerColumn is the bucket width of the /graph image.
+	graphPixelsPerColumn = 4
+)
+
+const (
+	bucketUnknown = iota
+	bucketUp
+	bucketDown
+)
+
+// graphPalette is indexed by bucket state.
+var graphPalette = color.Palette{
+	color.RGBA{R: 0x9e, G: 0x9e, B: 0x9e, A: 0xff},
+	color.RGBA{R: 0x2e, G: 0x9d, B: 0x4a, A: 0xff},
+	color.RGBA{R: 0xd3, G: 0x2f, B: 0x2f, A: 0xff},
+}
+
+// buildUptimeGraph buckets rows into columns of equal width ending at now. A
+// bucket with any DOWN check is drawn as down, one with only UP checks as up,
+// and one without rows as unknown.
+func buildUptimeGraph(rows []logstore.Row, now time.Time, window time.Duration, columns int) string {
+	var sb strings.Builder
+	for _, bucket := range uptimeBuckets(rows, now, window, columns) {
+		switch bucket {
+		case bucketUp:
+			sb.WriteRune(graphUp)
+		case bucketDown:
+			sb.WriteRune(graphDown)
+		default:
+			sb.WriteRune(graphUnknown)
+		}
+	}
+	return sb.String()
+}
+
+// renderUptimePNG draws the buckets of rows as a width x height PNG strip:
+// green up, red down and gray without data.
+func renderUptimePNG(rows []logstore.Row, now time.Time, window time.Duration, width, height int) ([]byte, error) {
+	buckets := uptimeBuckets(rows, now, window, graphImageColumns(width))
+	img := image.NewPaletted(image.Rect(0, 0, width, height), graphPalette)
+	line := make([]uint8, width)
+	for x := range line {
+		line[x] = uint8(buckets[x*len(buckets)/width])
+	}
+	for y := range height {
+		copy(img.Pix[y*img.Stride:], line)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// graphImageColumns is the number of buckets of a width pixels wide image.
+func graphImageColumns(width int) int {
+	return max(width/graphPixelsPerColumn, 1)
+}
+
+func uptimeBuckets(rows []logstore.Row, now time.Time, window time.Duration, columns int) []int {
+	start := now.Add(-window)
+	width := window / time.Duration(columns)
+	buckets := make([]int, columns)
+	for _, row := range rows {
+		from, err := time.Parse(time.RFC3339, row.Timestamp)
+		if err != nil {
+			continue
+		}
+		to := from
+		if row.LastSeen != "" {
+			if lastSeen, err := time.Parse(time.RFC3339, row.LastSeen); err == nil {
+				to = lastSeen
+			}
+		}
+		if to.Before(start) || from.After(now) {
+			continue
+		}
+		first := max(int(from.Sub(start)/width), 0)
+		last := min(int(to.Sub(start)/width), columns-1)
+		for i := first; i <= last; i++ {
+			switch {
+			case row.Status == "DOWN":
+				buckets[i] = bucketDown
+			case row.Status == "UP" && buckets[i] == bucketUnknown:
+				buckets[i] = bucketUp
+			}
+		}
+	}
+	return buckets
+}
@@ -0,0 +1,52 @@
+package tracker
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// checkTargetHTTP GETs the target's path on its port. It is UP when the
+// status equals ExpectStatus, or without one when it is below 400; body rules,
+// when set, decide from the first maxHealthBodyBytes of the body instead.
+func (p probeOptions) checkTargetHTTP(ctx context.Context, target *TargetState, timeout time.Duration) checkResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	path := target.HTTPPath
+	if path == "" {
+		path = "/"
+	}
+	url := target.CheckType + "://" + net.JoinHostPort(target.Address, strconv.Itoa(target.Port)) + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return checkResult{Status: "http-error"}
+	}
+	startedAt := time.Now()
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return checkResult{Status: "http-error"}
+	}
+	latency := time.Since(startedAt)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxHealthBodyBytes))
+	_ = resp.Body.Close()
+
+	result := checkResult{Status: "http " + strconv.Itoa(resp.StatusCode)}
+	switch {
+	case target.BodyRules != nil:
+		if err != nil {
+			return result
+		}
+		result.Up, result.Rule = target.BodyRules.match(body)
+	case target.ExpectStatus != 0:
+		result.Up = resp.StatusCode == target.ExpectStatus
+	default:
+		result.Up = resp.StatusCode < http.StatusBadRequest
+	}
+	if result.Up {
+		result.Latency = latency
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+const (
+	protocolICMP   = 1
+	protocolICMPv6 = 58
+)
+
+var icmpSeq atomic.Uint32
+
+// checkICMP sends one echo request and waits for its reply until timeout.
+// It uses a raw socket when allowed and otherwise the kernel's unprivileged
+// UDP ping (net.ipv4.ping_group_range on Linux).
+func (p probeOptions) checkICMP(ctx context.Context, address string, timeout time.Duration) checkResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, address)
+	if err != nil || len(ips) == 0 {
+		return checkResult{Status: "icmp-unresolved"}
+	}
+	ip := ips[0].IP
+
+	network, rawNetwork, listen := "udp4", "ip4:icmp", "0.0.0.0"
+	var requestType icmp.Type = ipv4.ICMPTypeEcho
+	protocol := protocolICMP
+	if ip.To4() == nil {
+		network, rawNetwork, listen = "udp6", "ip6:ipv6-icmp", "::"
+		requestType = ipv6.ICMPTypeEchoRequest
+		protocol = protocolICMPv6
+	}
+	if source := p.source; source.IsValid() && source.Is4() == (ip.To4() != nil) {
+		listen = source.String()
+	}
+	privileged := true
+	conn, err := icmp.ListenPacket(rawNetwork, listen)
+	if err != nil {
+		privileged = false
+		if conn, err = icmp.ListenPacket(network, listen); err != nil {
+			return checkResult{Status: "icmp-unavailable"}
+		}
+	}
+	defer conn.Close()
+	var dst net.Addr = &net.IPAddr{IP: ip}
+	if !privileged {
+		dst = &net.UDPAddr{IP: ip}
+	}
+
+	id := os.Getpid() & 0xffff
+	seq := int(icmpSeq.Add(1) & 0xffff)
+	request, err := (&icmp.Message{
+		Type: requestType,
+		Body: &icmp.Echo{ID: id, Seq: seq, Data: []byte("trackway")},
+	}).Marshal(nil)
+	if err != nil {
+		return checkResult{Status: "icmp-error"}
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return checkResult{Status: "icmp-error"}
+	}
+	startedAt := time.Now()
+	if _, err := conn.WriteTo(request, dst); err != nil {
+		return checkResult{Status: "icmp-error"}
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		n, peer, err := conn.ReadFrom(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return checkResult{Status: "icmp-timeout"}
+			}
+			return checkResult{Status: "icmp-error"}
+		}
+		if !sameIP(peer, ip) {
+			continue
+		}
+		reply, err := icmp.ParseMessage(protocol, buf[:n])
+		if err != nil || (reply.Type != ipv4.ICMPTypeEchoReply && reply.Type != ipv6.ICMPTypeEchoReply) {
+			continue
+		}
+		echo, ok := reply.Body.(*icmp.Echo)
+		// Unprivileged sockets get their ID rewritten by the kernel.
+		if !ok || echo.Seq != seq || (privileged && echo.ID != id) {
+			continue
+		}
+		return checkResult{Up: true, Latency: time.Since(startedAt)}
+	}
+}
+
+func sameIP(addr net.Addr, ip net.IP) bool {
+	switch peer := addr.(type) {
+	case *net.IPAddr:
+		return peer.IP.Equal(ip)
+	case *net.UDPAddr:
+		return peer.IP.Equal(ip)
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"trackway/internal/util"
+)
+
+// investigation is a sent INVESTIGATING note waiting to become DOWN or a
+// false alarm.
+type investigation struct {
+	MessageID int
+	Event     alertEvent
+}
+
+// applyInvestigations sends INVESTIGATING notes one per target and resolves
+// earlier ones in place: a DOWN edits the note into the DOWN alert, a
+// FALSE_ALARM edits it into a short note. Handled events are removed.
+func (a *AlertManager) applyInvestigations(ctx context.Context, events []alertEvent) []alertEvent {
+	kept := make([]alertEvent, 0, len(events))
+	for _, ev := range events {
+		switch ev.Kind {
+		case "INVESTIGATING":
+			messageID, err := a.sendDefaultWithID(ctx, formatAlertGroup([]alertEvent{ev}))
+			if err != nil {
+				a.logger.Warn("failed to send investigating note", "track", ev.Target, "error", err)
+				continue
+			}
+			a.investigations[ev.Target] = investigation{MessageID: messageID, Event: ev}
+		case "FALSE_ALARM":
+			pending, ok := a.investigations[ev.Target]
+			delete(a.investigations, ev.Target)
+			if !ok || pending.MessageID == 0 {
+				continue
+			}
+			if err := a.notifier.EditDefaultHTML(ctx, pending.MessageID, formatFalseAlarm(pending.Event)); err != nil {
+				a.logger.Warn("failed to edit investigating note", "track", ev.Target, "error", err)
+			}
+		case "DOWN":
+			pending, ok := a.investigations[ev.Target]
+			delete(a.investigations, ev.Target)
+			if !ok || pending.MessageID == 0 {
+				kept = append(kept, ev)
+				continue
+			}
+			group := []alertEvent{ev}
+			message := formatAlertGroup(group) + a.dashboardFooter(ev.Kind, group)
+			if err := a.editOrResend(ctx, pending.MessageID, message); err != nil {
+				a.logger.Warn("failed to upgrade investigating note", "track", ev.Target, "error", err)
+				continue
+			}
+			a.markDownSent(group)
+			a.pendingDown[ev.Target] = pendingDownAlert{
+				MessageID: pending.MessageID,
+				DownAt:    ev.Occurred,
+				Reason:    ev.Reason,
+				Address:   ev.Address,
+				Port:      ev.Port,
+			}
+		default:
+			kept = append(kept, ev)
+		}
+	}
+	return kept
+}
+
+func formatFalseAlarm(ev alertEvent) string {
+	var sb strings.Builder
+	sb.WriteString("<b>INVESTIGATING -> false-alarm</b>\n")
+	fmt.Fprintf(
+		&sb,
+		"<code>%s</code> (<code>%s:%d</code>) recovered before the failure was confirmed",
+		util.HTMLEscape(ev.Target),
+		util.HTMLEscape(ev.Address),
+		ev.Port,
+	)
+	return sb.String()
+}
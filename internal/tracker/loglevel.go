@@ -0,0 +1,76 @@
+package tracker
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLogLevelDuration = 10 * time.Minute
+	maxLogLevelDuration     = 24 * time.Hour
+)
+
+// logLevelControl temporarily overrides a process-wide slog level and reverts
+// to the level it had when attached once the override expires.
+type logLevelControl struct {
+	mu    sync.Mutex
+	level *slog.LevelVar
+	base  slog.Level
+	timer *time.Timer
+	until time.Time
+	// generation counts overrides and resets, so an expiry timer that fired
+	// while a newer set or reset held mu does not revert the newer level.
+	generation uint64
+}
+
+func newLogLevelControl(level *slog.LevelVar) *logLevelControl {
+	return &logLevelControl{level: level, base: level.Level()}
+}
+
+func (c *logLevelControl) set(level slog.Level, duration time.Duration, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopLocked()
+	c.level.Set(level)
+	c.until = now.Add(duration)
+	generation := c.generation
+	c.timer = time.AfterFunc(duration, func() { c.expire(generation) })
+}
+
+func (c *logLevelControl) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopLocked()
+	c.level.Set(c.base)
+	c.until = time.Time{}
+}
+
+// expire reverts the override made in generation, unless a later set or
+// reset replaced it.
+func (c *logLevelControl) expire(generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	c.timer = nil
+	c.level.Set(c.base)
+	c.until = time.Time{}
+}
+
+// stopLocked cancels the pending expiry. A timer that has already fired is
+// made stale by the new generation. c.mu must be held.
+func (c *logLevelControl) stopLocked() {
+	c.generation++
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+}
+
+func (c *logLevelControl) current() (slog.Level, time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.level.Level(), c.until
+}
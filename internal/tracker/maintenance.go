@@ -0,0 +1,165 @@
+package tracker
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"trackway/internal/config"
+)
+
+// maintenanceWindow is a parsed config.MaintenanceWindow. Empty targets means
+// every target.
+type maintenanceWindow struct {
+	name     string
+	start    time.Time
+	end      time.Time
+	schedule cron.Schedule
+	duration time.Duration
+	targets  map[string]bool
+}
+
+// ActiveMaintenance is a maintenance window in effect. Targets is empty when
+// it covers every target.
+type ActiveMaintenance struct {
+	Name    string
+	Until   time.Time
+	Targets []string
+}
+
+func newMaintenanceWindows(items []config.MaintenanceWindow) []maintenanceWindow {
+	windows := make([]maintenanceWindow, 0, len(items))
+	for _, item := range items {
+		window := maintenanceWindow{name: item.Name, duration: time.Duration(item.DurationMinutes) * time.Minute}
+		// Load has already rejected invalid values.
+		if item.Schedule != "" {
+			schedule, err := cron.ParseStandard(item.Schedule)
+			if err != nil {
+				continue
+			}
+			window.schedule = schedule
+		} else {
+			window.start, _ = time.Parse(time.RFC3339, strings.TrimSpace(item.Start))
+			window.end, _ = time.Parse(time.RFC3339, strings.TrimSpace(item.End))
+		}
+		if len(item.Targets) > 0 {
+			window.targets = make(map[string]bool, len(item.Targets))
+			for _, name := range item.Targets {
+				window.targets[name] = true
+			}
+		}
+		windows = append(windows, window)
+	}
+	return windows
+}
+
+// activeUntil returns when the window ends if it is in effect at now. A
+// recurring window is in effect for duration after each schedule tick.
+func (w maintenanceWindow) activeUntil(now time.Time) (time.Time, bool) {
+	if w.schedule == nil {
+		return w.end, !now.Before(w.start) && now.Before(w.end)
+	}
+	tick := w.schedule.Next(now.Add(-w.duration))
+	if tick.After(now) {
+		return time.Time{}, false
+	}
+	return tick.Add(w.duration), true
+}
+
+func (w maintenanceWindow) covers(name string) bool {
+	return w.targets == nil || w.targets[name]
+}
+
+// inMaintenance reports whether an active window covers the target.
+func (e *MonitorEngine) inMaintenance(name string, now time.Time) bool {
+	for _, window := range e.maintenance {
+		if _, active := window.activeUntil(now); active && window.covers(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterMaintenance drops alerts of targets in maintenance. A target whose
+// DOWN was dropped and that is still down once its window is over gets that
+// DOWN alert then; one that recovered meanwhile stays quiet.
+func (e *MonitorEngine) filterMaintenance(events []alertEvent, now time.Time) []alertEvent {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.maintenance) == 0 && len(e.maintenanceHeld) == 0 {
+		return events
+	}
+	if e.maintenanceHeld == nil {
+		e.maintenanceHeld = make(map[string]bool)
+	}
+
+	kept := make([]alertEvent, 0, len(events))
+	for _, event := range events {
+		if event.Target == "" || !e.inMaintenance(event.Target, now) {
+			kept = append(kept, event)
+			continue
+		}
+		switch event.Kind {
+		case "DOWN":
+			e.maintenanceHeld[event.Target] = true
+		case "RECOVERED":
+			delete(e.maintenanceHeld, event.Target)
+		}
+		e.logger.Debug("alert suppressed by maintenance", "track", event.Target, "kind", event.Kind)
+	}
+
+	names := make([]string, 0, len(e.maintenanceHeld))
+	for name := range e.maintenanceHeld {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if e.inMaintenance(name, now) {
+			continue
+		}
+		delete(e.maintenanceHeld, name)
+		target := e.targetByName[name]
+		if target == nil || target.LastStatus == nil || *target.LastStatus {
+			continue
+		}
+		kept = append(kept, alertEvent{
+			Kind:         "DOWN",
+			Target:       target.Name,
+			Address:      target.Address,
+			Port:         target.Port,
+			Reason:       "maintenance-ended",
+			Mention:      target.Mention,
+			RunbookURL:   target.RunbookURL,
+			Occurred:     now,
+			FailedChecks: target.failStreak,
+			FailingSince: target.failingSince,
+			Impact:       target.Impact,
+			Critical:     target.Critical,
+		})
+	}
+	return kept
+}
+
+// ActiveMaintenance returns the windows in effect at now, ending soonest
+// first.
+func (e *MonitorEngine) ActiveMaintenance(now time.Time) []ActiveMaintenance {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	out := make([]ActiveMaintenance, 0)
+	for _, window := range e.maintenance {
+		until, active := window.activeUntil(now)
+		if !active {
+			continue
+		}
+		item := ActiveMaintenance{Name: window.name, Until: until}
+		for name := range window.targets {
+			item.Targets = append(item.Targets, name)
+		}
+		sort.Strings(item.Targets)
+		out = append(out, item)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Until.Before(out[j].Until) })
+	return out
+}
@@ -0,0 +1,145 @@
+package tracker
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"trackway/internal/logstore"
+	"trackway/internal/util"
+)
+
+const (
+	defaultMuteDuration = time.Hour
+	maxMuteDuration     = 30 * 24 * time.Hour
+)
+
+var ErrTargetNotFound = errors.New("target not found")
+
+// Mute suppresses alerts for a target until the given time, or for the default
+// duration when until is zero, and returns the effective expiry. Checks and
+// logging continue as usual. The mute is stored with the target, so it
+// survives restarts until it expires.
+func (e *MonitorEngine) Mute(trackName string, until time.Time) (time.Time, error) {
+	until = muteUntil(time.Now().UTC(), until)
+	if err := e.setMute(trackName, until); err != nil {
+		return time.Time{}, err
+	}
+	return until, nil
+}
+
+func (e *MonitorEngine) Unmute(trackName string) error {
+	return e.setMute(trackName, time.Time{})
+}
+
+func (e *MonitorEngine) setMute(trackName string, until time.Time) error {
+	e.mu.RLock()
+	_, ok := e.targetByName[trackName]
+	e.mu.RUnlock()
+	if !ok {
+		return ErrTargetNotFound
+	}
+	if err := e.logs.SetTargetMute(trackName, until); err != nil {
+		if errors.Is(err, logstore.ErrTargetNotFound) {
+			return ErrTargetNotFound
+		}
+		return err
+	}
+
+	e.mu.Lock()
+	if target, ok := e.targetByName[trackName]; ok {
+		target.MutedUntil = until
+	}
+	e.mu.Unlock()
+	return nil
+}
+
+// filterMuted drops events for targets that are muted at now. Events that are
+// not tied to a target are always kept.
+func (e *MonitorEngine) filterMuted(events []alertEvent, now time.Time) []alertEvent {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	kept := make([]alertEvent, 0, len(events))
+	for _, event := range events {
+		target, ok := e.targetByName[event.Target]
+		if ok && target.MutedUntil.After(now) {
+			e.logger.Debug("alert suppressed by mute", "track", event.Target, "kind", event.Kind, "muted_until", target.MutedUntil)
+			continue
+		}
+		kept = append(kept, event)
+	}
+	return kept
+}
+
+// muteUntil defaults a zero expiry to one hour from now and caps it.
+func muteUntil(now, until time.Time) time.Time {
+	if until.IsZero() {
+		until = now.Add(defaultMuteDuration)
+	}
+	return minTime(until.UTC(), now.Add(maxMuteDuration))
+}
+
+// activeMute returns until when the mute is still in effect at now, or zero.
+func activeMute(until, now time.Time) time.Time {
+	if until.After(now) {
+		return until
+	}
+	return time.Time{}
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// muteText handles /mute <track> [minutes] and /unmute <track>. The length is
+// minutes, or a Go duration such as 2h; muteUntil applies the default and cap.
+func (h *CommandHandler) muteText(command string, args []string) string {
+	if command == "unmute" {
+		if len(args) == 0 {
+			return "Usage: /unmute &lt;track&gt;"
+		}
+		if err := h.source.Unmute(args[0]); err != nil {
+			if errors.Is(err, ErrTargetNotFound) {
+				return "Track not found. Use /list."
+			}
+			h.logger.Warn("failed to unmute target", "track", args[0], "error", err)
+			return fmt.Sprintf("Mute not changed: %s", util.HTMLEscape(err.Error()))
+		}
+		return fmt.Sprintf("Alerts for <b>%s</b> are unmuted.", util.HTMLEscape(args[0]))
+	}
+
+	const usage = "Usage: /mute &lt;track&gt; [minutes], or a duration such as 2h"
+	if len(args) == 0 {
+		return usage
+	}
+	until := time.Time{}
+	if len(args) >= 2 {
+		duration, err := parseMuteDuration(args[1])
+		if err != nil || duration <= 0 {
+			return usage
+		}
+		until = time.Now().UTC().Add(duration)
+	}
+	until, err := h.source.Mute(args[0], until)
+	if errors.Is(err, ErrTargetNotFound) {
+		return "Track not found. Use /list."
+	}
+	if err != nil {
+		h.logger.Warn("failed to mute target", "track", args[0], "error", err)
+		return fmt.Sprintf("Mute not changed: %s", util.HTMLEscape(err.Error()))
+	}
+	return fmt.Sprintf("Alerts for <b>%s</b> are muted until <code>%s</code>.", util.HTMLEscape(args[0]), util.FormatTime(until))
+}
+
+// parseMuteDuration reads a bare number as minutes, anything else as a Go
+// duration.
+func parseMuteDuration(raw string) (time.Duration, error) {
+	if minutes, err := strconv.Atoi(raw); err == nil {
+		return time.Duration(minutes) * time.Minute, nil
+	}
+	return time.ParseDuration(raw)
+}
@@ -0,0 +1,125 @@
+package tracker
+
+import (
+	"sort"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// maxScheduleLookahead bounds the search for a tick inside a cron schedule.
+	maxScheduleLookahead = 1000
+	// dueSlack checks targets due this close together in one pass instead of
+	// waking up for each of them.
+	dueSlack = 100 * time.Millisecond
+)
+
+// NextCheck is when a target is next checked. At is zero before the target's
+// first check or when no upcoming tick falls inside the schedule.
+type NextCheck struct {
+	Target string
+	At     time.Time
+	// Scheduled is set when the cron schedule, not the interval, decides At.
+	Scheduled bool
+}
+
+// targetInterval is the target's own interval or the monitoring one.
+// Callers hold e.mu.
+func (e *MonitorEngine) targetInterval(target *TargetState) time.Duration {
+	if target.Interval > 0 {
+		return target.Interval
+	}
+	return e.interval
+}
+
+// takeDue returns the targets due at now and schedules their next check one
+// interval after the previous due time, or after now when that has passed.
+func (e *MonitorEngine) takeDue(now time.Time) []*TargetState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	due := make([]*TargetState, 0, len(e.targets))
+	for _, target := range e.targets {
+		if target.nextDue.After(now.Add(dueSlack)) {
+			continue
+		}
+		interval := e.targetInterval(target)
+		next := target.nextDue.Add(interval)
+		if !next.After(now) {
+			next = now.Add(interval)
+		}
+		target.nextDue = next
+		due = append(due, target)
+	}
+	return due
+}
+
+// untilNextDue is how long the monitor loop may sleep. It is capped at the
+// monitoring interval so targets added at runtime are picked up.
+func (e *MonitorEngine) untilNextDue(now time.Time) time.Duration {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	wait := e.interval
+	for _, target := range e.targets {
+		wait = min(wait, target.nextDue.Sub(now))
+	}
+	return max(wait, 0)
+}
+
+// rescheduleDefaultInterval moves targets on the monitoring interval to one
+// new interval from now, after the interval setting changed.
+func (e *MonitorEngine) rescheduleDefaultInterval(now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, target := range e.targets {
+		if target.Interval <= 0 && !target.nextDue.IsZero() {
+			target.nextDue = now.Add(e.interval)
+		}
+	}
+}
+
+// NextChecks reports the next check of trackName, or of every target when it
+// is empty. The bool is false when the target does not exist.
+func (e *MonitorEngine) NextChecks(trackName string) ([]NextCheck, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	targets := e.targets
+	if trackName != "" {
+		target := e.targetByName[trackName]
+		if target == nil {
+			return nil, false
+		}
+		targets = []*TargetState{target}
+	}
+
+	out := make([]NextCheck, 0, len(targets))
+	for _, target := range targets {
+		next := NextCheck{Target: target.Name}
+		if !target.nextDue.IsZero() {
+			next.At = nextTickInSchedule(target.Schedule, target.nextDue, e.targetInterval(target))
+			next.Scheduled = target.Schedule != nil && !next.At.Equal(target.nextDue)
+		}
+		out = append(out, next)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Target < out[j].Target })
+	return out, true
+}
+
+// nextTickInSchedule returns the first tick (next + k*interval) that falls in
+// a minute matched by schedule.
+func nextTickInSchedule(schedule cron.Schedule, next time.Time, interval time.Duration) time.Time {
+	tick := next
+	for range maxScheduleLookahead {
+		if inSchedule(schedule, tick) {
+			return tick
+		}
+		start := schedule.Next(tick)
+		if start.IsZero() || interval <= 0 {
+			return time.Time{}
+		}
+		ticks := (start.Sub(next) + interval - 1) / interval
+		tick = next.Add(ticks * interval)
+	}
+	return time.Time{}
+}
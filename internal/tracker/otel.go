@@ -0,0 +1,80 @@
+package tracker
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "trackway/internal/tracker"
+
+// checkTelemetry instruments check cycles and single checks. A nil
+// *checkTelemetry (otel disabled) makes every method a no-op.
+type checkTelemetry struct {
+	tracer   trace.Tracer
+	checks   metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+func newCheckTelemetry(enabled bool) *checkTelemetry {
+	if !enabled {
+		return nil
+	}
+	meter := otel.Meter(instrumentationName)
+	// Instrument creation only fails on invalid names; the no-op fallbacks
+	// returned alongside the error are safe to use.
+	checks, _ := meter.Int64Counter("trackway.checks", metric.WithDescription("Completed target checks"))
+	duration, _ := meter.Float64Histogram(
+		"trackway.check.duration",
+		metric.WithDescription("Target check duration"),
+		metric.WithUnit("ms"),
+	)
+	return &checkTelemetry{
+		tracer:   otel.Tracer(instrumentationName),
+		checks:   checks,
+		duration: duration,
+	}
+}
+
+func (t *checkTelemetry) startCycle(ctx context.Context, targets int) (context.Context, func()) {
+	if t == nil {
+		return ctx, func() {}
+	}
+	ctx, span := t.tracer.Start(ctx, "runChecks", trace.WithAttributes(attribute.Int("trackway.targets", targets)))
+	return ctx, func() { span.End() }
+}
+
+func (t *checkTelemetry) startCheck(ctx context.Context, target *TargetState) (context.Context, func(checkResult)) {
+	if t == nil {
+		return ctx, func(checkResult) {}
+	}
+	attrs := []attribute.KeyValue{
+		attribute.String("trackway.target", target.Name),
+		attribute.String("server.address", target.Address),
+		attribute.Int("server.port", target.Port),
+	}
+	started := time.Now()
+	ctx, span := t.tracer.Start(ctx, "check", trace.WithAttributes(attrs...))
+	return ctx, func(result checkResult) {
+		status := "DOWN"
+		if result.Up {
+			status = "UP"
+		} else {
+			span.SetStatus(codes.Error, "target down")
+		}
+		span.SetAttributes(attribute.String("trackway.status", status))
+		if result.Method != "" {
+			span.SetAttributes(attribute.String("trackway.method", result.Method))
+		}
+		span.End()
+
+		set := metric.WithAttributes(attribute.String("trackway.target", target.Name), attribute.String("trackway.status", status))
+		t.checks.Add(ctx, 1, set)
+		t.duration.Record(ctx, float64(time.Since(started))/float64(time.Millisecond), set)
+	}
+}
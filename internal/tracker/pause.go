@@ -0,0 +1,207 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"trackway/internal/util"
+)
+
+const maxPauseMinutes = 24 * 60
+
+// AlertPauser suspends all alerting for /pause and /resume.
+type AlertPauser interface {
+	Pause(until time.Time)
+	Resume(ctx context.Context) bool
+	PausedUntil(now time.Time) (time.Time, bool)
+}
+
+// SetStatusSource sets where the targets still DOWN are read from when a
+// pause ends.
+func (a *AlertManager) SetStatusSource(status func() Snapshot) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.status = status
+}
+
+// Pause suspends every alert until until, or until Resume when until is
+// zero. Checks and log rows go on.
+func (a *AlertManager) Pause(until time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.paused, a.pausedUntil = true, until
+}
+
+// Resume ends a pause and reports whether one was in effect.
+func (a *AlertManager) Resume(ctx context.Context) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, active := a.pausedAt(time.Now())
+	switch {
+	case active:
+		a.endPause(ctx, "alerts were resumed")
+	case a.paused:
+		a.endPause(ctx, "the alert pause has ended")
+	}
+	return active
+}
+
+// pauseActive reports whether alerts are paused at now, and ends a pause
+// whose time has passed. Callers hold a.mu.
+func (a *AlertManager) pauseActive(ctx context.Context, now time.Time) bool {
+	if !a.paused {
+		return false
+	}
+	if _, active := a.pausedAt(now); active {
+		return true
+	}
+	a.endPause(ctx, "the alert pause has ended")
+	return false
+}
+
+// endPause lifts the pause and sends a RESUMED notice listing the targets
+// still DOWN, since the alerts dropped while paused are never sent. Those
+// targets count as reported; the DOWN state of the others is forgotten so
+// nothing edits or waits on alerts from before the pause. Callers hold a.mu.
+func (a *AlertManager) endPause(ctx context.Context, note string) {
+	a.paused, a.pausedUntil = false, time.Time{}
+	text := "<b>RESUMED</b>\n" + note
+	if a.status != nil {
+		text += "\n" + a.resetPausedState(a.status())
+	}
+	if err := a.sendDefault(ctx, text); err != nil {
+		a.logger.Warn("failed to send pause end notice", "error", err)
+	}
+}
+
+// resetPausedState aligns the alert state with snapshot after a pause and
+// returns the list of targets still DOWN. Callers hold a.mu.
+func (a *AlertManager) resetPausedState(snapshot Snapshot) string {
+	down := make(map[string]bool)
+	var events []alertEvent
+	for _, target := range snapshot.Targets {
+		if target.Status != "DOWN" {
+			continue
+		}
+		down[target.Name] = true
+		events = append(events, alertEvent{Kind: "DOWN", Target: target.Name, Address: target.Address, Port: target.Port, Occurred: snapshot.GeneratedAt})
+	}
+	for name := range a.pendingDown {
+		if !down[name] {
+			delete(a.pendingDown, name)
+		}
+	}
+	for name := range a.lastDown {
+		if !down[name] {
+			delete(a.lastDown, name)
+		}
+	}
+	for name := range a.downSent {
+		if !down[name] {
+			delete(a.downSent, name)
+		}
+	}
+	for reason, groups := range a.pendingGroup {
+		kept := make([]pendingDownGroup, 0, len(groups))
+		for _, group := range groups {
+			for name := range group.Targets {
+				if !down[name] {
+					delete(group.Targets, name)
+				}
+			}
+			if len(group.Targets) > 0 {
+				kept = append(kept, group)
+			}
+		}
+		a.pendingGroup[reason] = kept
+	}
+	a.markDownSent(events)
+
+	if len(events) == 0 {
+		return "no targets are down"
+	}
+	var sb strings.Builder
+	sb.WriteString("still down:")
+	for _, ev := range events {
+		fmt.Fprintf(&sb, "\n- <code>%s</code> (<code>%s:%d</code>)", util.HTMLEscape(ev.Target), util.HTMLEscape(ev.Address), ev.Port)
+	}
+	return sb.String()
+}
+
+// PausedUntil reports whether alerts are paused at now and until when; zero
+// means until Resume.
+func (a *AlertManager) PausedUntil(now time.Time) (time.Time, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.pausedAt(now)
+}
+
+// pausedAt is PausedUntil for callers holding a.mu.
+func (a *AlertManager) pausedAt(now time.Time) (time.Time, bool) {
+	if !a.paused || (!a.pausedUntil.IsZero() && !now.Before(a.pausedUntil)) {
+		return time.Time{}, false
+	}
+	return a.pausedUntil, true
+}
+
+// SetAlertPauser enables /pause and /resume.
+func (h *CommandHandler) SetAlertPauser(pauser AlertPauser) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pauser = pauser
+}
+
+func (h *CommandHandler) alertPauser() AlertPauser {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.pauser
+}
+
+func (h *CommandHandler) pauseText(ctx context.Context, chatID int64, command string, args []string) string {
+	if !h.isChatAllowed(chatID) {
+		return "This command is not available in this chat."
+	}
+	pauser := h.alertPauser()
+	if pauser == nil {
+		return "Pausing alerts is not available."
+	}
+	if command == "resume" {
+		if !pauser.Resume(ctx) {
+			return "Alerts were not paused."
+		}
+		return "Alerts are resumed."
+	}
+
+	until := time.Time{}
+	if len(args) > 0 {
+		minutes, err := strconv.Atoi(args[0])
+		if err != nil || minutes < 1 || minutes > maxPauseMinutes {
+			return fmt.Sprintf("Usage: /pause [minutes], 1-%d", maxPauseMinutes)
+		}
+		until = time.Now().UTC().Add(time.Duration(minutes) * time.Minute)
+	}
+	pauser.Pause(until)
+	if until.IsZero() {
+		return "All alerts are paused until /resume. Checks and logs continue."
+	}
+	return fmt.Sprintf("All alerts are paused until <code>%s</code>, then resume on their own; /resume ends the pause early. Checks and logs continue.", util.FormatTime(until))
+}
+
+// pauseBanner heads /status while alerts are paused.
+func (h *CommandHandler) pauseBanner(now time.Time) string {
+	pauser := h.alertPauser()
+	if pauser == nil {
+		return ""
+	}
+	until, paused := pauser.PausedUntil(now)
+	if !paused {
+		return ""
+	}
+	if until.IsZero() {
+		return "<b>ALERTS PAUSED</b> until /resume\n\n"
+	}
+	return fmt.Sprintf("<b>ALERTS PAUSED</b> until <code>%s</code>\n\n", util.FormatTime(until))
+}
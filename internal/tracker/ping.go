@@ -0,0 +1,80 @@
+package tracker
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	defaultPingCount = 4
+	maxPingCount     = 10
+)
+
+// PingResult summarizes ad-hoc probes of a target. Latencies cover
+// successful probes only and are zero when none succeeded.
+type PingResult struct {
+	Target   string
+	Address  string
+	Port     int
+	Sent     int
+	Received int
+	Min      time.Duration
+	Avg      time.Duration
+	Max      time.Duration
+}
+
+// Ping probes a target count times, by ICMP echo for ping targets, a UDP probe
+// for udp targets and a TCP connect otherwise, without touching its stored
+// state or raising alerts. count is clamped to [1, maxPingCount].
+func (e *MonitorEngine) Ping(ctx context.Context, trackName string, count int) (PingResult, bool) {
+	e.mu.RLock()
+	target, ok := e.targetByName[trackName]
+	var address, checkType string
+	var port int
+	var probe TargetState
+	if ok {
+		address, port, checkType = target.Address, target.Port, target.CheckType
+		probe = TargetState{Address: address, Port: port, UDPPayload: target.UDPPayload, UDPExpect: target.UDPExpect}
+	}
+	timeout := e.timeout
+	e.mu.RUnlock()
+	if !ok {
+		return PingResult{}, false
+	}
+
+	if count <= 0 {
+		count = defaultPingCount
+	}
+	count = min(count, maxPingCount)
+
+	// Retries would hide the per-attempt loss and latency /ping reports.
+	p := probeOptions{source: e.probe.source}
+	result := PingResult{Target: trackName, Address: address, Port: port}
+	var total time.Duration
+	for i := 0; i < count && ctx.Err() == nil; i++ {
+		var check checkResult
+		switch checkType {
+		case "ping":
+			check = p.checkICMP(ctx, address, timeout)
+		case "udp":
+			// probe leaves UDPNoResponseUp unset: only replies count.
+			check = p.checkUDP(ctx, &probe, timeout)
+		default:
+			check = p.checkTCP(ctx, address, port, timeout)
+		}
+		result.Sent++
+		if !check.Up {
+			continue
+		}
+		result.Received++
+		total += check.Latency
+		if result.Min == 0 || check.Latency < result.Min {
+			result.Min = check.Latency
+		}
+		result.Max = max(result.Max, check.Latency)
+	}
+	if result.Received > 0 {
+		result.Avg = total / time.Duration(result.Received)
+	}
+	return result, true
+}
@@ -0,0 +1,180 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"trackway/internal/logstore"
+	"trackway/internal/util"
+)
+
+const pinnedStatusMaxDown = 30
+
+// pinnedStatusCheckInterval bounds how often the board asks Telegram whether
+// its message is still pinned.
+const pinnedStatusCheckInterval = 10 * time.Minute
+
+// pinnedStatusSetting is the stored setting that keeps the pinned message ID
+// across restarts.
+const pinnedStatusSetting = "pinned_status_message_id"
+
+// StatusPinner is implemented by notifiers that can keep a pinned message and
+// thread replies under it.
+type StatusPinner interface {
+	SendDefaultHTMLReply(ctx context.Context, replyTo int, text string) (int, error)
+	PinDefaultMessage(ctx context.Context, messageID int) error
+	DefaultPinnedMessageID(ctx context.Context) (int, error)
+}
+
+// statusBoard keeps one pinned message with the live snapshot in the default
+// chat. It is edited only when its text changes, recreated when deleted and
+// pinned again when unpinned. Its ID is stored, so a restart edits the same
+// message instead of pinning a new one.
+type statusBoard struct {
+	notifier Notifier
+	pinner   StatusPinner
+	logs     *logstore.Store
+	logger   *slog.Logger
+
+	mu           sync.Mutex
+	messageID    int
+	lastText     string
+	lastPinCheck time.Time
+}
+
+// newStatusBoard returns nil when the notifier cannot pin messages. It picks
+// up the message ID stored in logs, if any.
+func newStatusBoard(notifier Notifier, logs *logstore.Store) *statusBoard {
+	pinner, ok := notifier.(StatusPinner)
+	if !ok {
+		return nil
+	}
+	board := &statusBoard{notifier: notifier, pinner: pinner, logs: logs, logger: slog.Default()}
+	if logs == nil {
+		return board
+	}
+	settings, err := logs.Settings()
+	if err != nil {
+		board.logger.Warn("failed to load pinned status message id", "error", err)
+		return board
+	}
+	if id, err := strconv.Atoi(settings[pinnedStatusSetting]); err == nil && id > 0 {
+		board.messageID = id
+	}
+	return board
+}
+
+func (b *statusBoard) update(ctx context.Context, snapshot Snapshot) {
+	text := formatPinnedStatus(snapshot)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.messageID != 0 {
+		if text == b.lastText {
+			b.ensurePinned(ctx)
+			return
+		}
+		// After a restart lastText is empty, so the first edit may repeat
+		// the text the message already has.
+		err := b.notifier.EditDefaultHTML(ctx, b.messageID, text)
+		if err == nil || isMessageNotModified(err) {
+			b.lastText = text
+			b.ensurePinned(ctx)
+			return
+		}
+		if !isMessageNotFound(err) {
+			b.logger.Warn("failed to update pinned status", "message_id", b.messageID, "error", err)
+			return
+		}
+		b.logger.Info("pinned status message is gone, recreating", "message_id", b.messageID)
+	}
+
+	messageID, err := b.notifier.SendDefaultHTMLWithID(ctx, text)
+	if err != nil || messageID == 0 {
+		b.logger.Warn("failed to send pinned status", "error", err)
+		return
+	}
+	b.messageID = messageID
+	b.lastText = text
+	b.lastPinCheck = time.Now()
+	if err := b.pinner.PinDefaultMessage(ctx, messageID); err != nil {
+		b.logger.Warn("failed to pin status message", "message_id", messageID, "error", err)
+	}
+	if b.logs != nil {
+		if err := b.logs.SetSetting(pinnedStatusSetting, strconv.Itoa(messageID)); err != nil {
+			b.logger.Warn("failed to store pinned status message id", "message_id", messageID, "error", err)
+		}
+	}
+}
+
+// ensurePinned pins the message again when it is no longer the chat's pinned
+// message, at most once per pinnedStatusCheckInterval. Telegram reports only
+// the latest pin, so a message pinned after the board also makes it pin the
+// board again, keeping it on top. The caller holds b.mu.
+func (b *statusBoard) ensurePinned(ctx context.Context) {
+	now := time.Now()
+	if now.Sub(b.lastPinCheck) < pinnedStatusCheckInterval {
+		return
+	}
+	b.lastPinCheck = now
+	pinned, err := b.pinner.DefaultPinnedMessageID(ctx)
+	if err != nil {
+		b.logger.Warn("failed to check pinned status message", "message_id", b.messageID, "error", err)
+		return
+	}
+	if pinned == b.messageID {
+		return
+	}
+	b.logger.Info("pinned status message is not pinned, pinning again", "message_id", b.messageID)
+	if err := b.pinner.PinDefaultMessage(ctx, b.messageID); err != nil {
+		b.logger.Warn("failed to pin status message", "message_id", b.messageID, "error", err)
+	}
+}
+
+// isMessageNotModified matches Telegram's error for an edit that keeps the
+// text unchanged.
+func isMessageNotModified(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "message is not modified")
+}
+
+func (b *statusBoard) currentID() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.messageID
+}
+
+func formatPinnedStatus(snapshot Snapshot) string {
+	var sb strings.Builder
+	fmt.Fprintf(
+		&sb,
+		"<b>Trackway status</b>\ntracks: %d | up: %d | down: %d | unknown: %d",
+		snapshot.Total,
+		snapshot.Up,
+		snapshot.Down,
+		snapshot.Unknown,
+	)
+	shown := 0
+	for _, target := range snapshot.Targets {
+		if target.Status != "DOWN" {
+			continue
+		}
+		if shown == pinnedStatusMaxDown {
+			fmt.Fprintf(&sb, "\n... and %d more", snapshot.Down-shown)
+			break
+		}
+		if shown == 0 {
+			sb.WriteString("\n\nDOWN:")
+		}
+		fmt.Fprintf(&sb, "\n- <code>%s</code> since <code>%s</code>", util.HTMLEscape(target.Name), util.FormatTime(target.LastChanged))
+		shown++
+	}
+	if snapshot.Down == 0 && snapshot.Total > 0 {
+		sb.WriteString("\n\nAll tracks are up.")
+	}
+	return sb.String()
+}
@@ -0,0 +1,59 @@
+package tracker
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// probeRetryDelay is the wait before the first retry of a failed dial; each
+// further retry waits one more step.
+const probeRetryDelay = 200 * time.Millisecond
+
+// probeOptions are engine-wide probe settings. The checks are its methods,
+// so every probe type runs with them; the engine holds them in its probe
+// field.
+type probeOptions struct {
+	// source, when valid, is the local address probes connect from.
+	source netip.Addr
+	// retries is how often a failed tcp dial is retried within a check.
+	retries int
+}
+
+// sourceClients caches one HTTP client per source address, so checks keep
+// reusing connections like http.DefaultClient does.
+var sourceClients sync.Map
+
+// dialer returns a tcp dialer bound to the source address, if any.
+func (p probeOptions) dialer() *net.Dialer {
+	dialer := &net.Dialer{}
+	if p.source.IsValid() {
+		dialer.LocalAddr = &net.TCPAddr{IP: p.source.AsSlice()}
+	}
+	return dialer
+}
+
+// udpDialer is dialer for udp.
+func (p probeOptions) udpDialer() *net.Dialer {
+	dialer := &net.Dialer{}
+	if p.source.IsValid() {
+		dialer.LocalAddr = &net.UDPAddr{IP: p.source.AsSlice()}
+	}
+	return dialer
+}
+
+// client returns the HTTP client for the source address.
+func (p probeOptions) client() *http.Client {
+	if !p.source.IsValid() {
+		return http.DefaultClient
+	}
+	if client, ok := sourceClients.Load(p.source); ok {
+		return client.(*http.Client)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = p.dialer().DialContext
+	client, _ := sourceClients.LoadOrStore(p.source, &http.Client{Transport: transport})
+	return client.(*http.Client)
+}
@@ -0,0 +1,211 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"trackway/internal/config"
+	"trackway/internal/util"
+)
+
+// quietHours is a parsed config.QuietHours: a daily window of wall clock
+// minutes in loc that wraps midnight when start > end.
+type quietHours struct {
+	start, end int
+	loc        *time.Location
+	label      string
+}
+
+// quietOutage is a DOWN deferred during quiet hours. recoveredAt is set once
+// the target recovered before the digest went out.
+type quietOutage struct {
+	event       alertEvent
+	recoveredAt time.Time
+	outages     int
+}
+
+// newQuietHours returns nil when quiet hours are disabled.
+func newQuietHours(cfg config.QuietHours) *quietHours {
+	if cfg.Start == "" {
+		return nil
+	}
+	// Load has already rejected invalid values.
+	start, err := config.ParseClock(cfg.Start)
+	if err != nil {
+		return nil
+	}
+	end, err := config.ParseClock(cfg.End)
+	if err != nil {
+		return nil
+	}
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil
+	}
+	return &quietHours{
+		start: start,
+		end:   end,
+		loc:   loc,
+		label: fmt.Sprintf("%s-%s %s", strings.TrimSpace(cfg.Start), strings.TrimSpace(cfg.End), loc),
+	}
+}
+
+// active reports whether now falls in the window on the local wall clock,
+// so it follows daylight saving time changes.
+func (q *quietHours) active(now time.Time) bool {
+	local := now.In(q.loc)
+	minute := local.Hour()*60 + local.Minute()
+	if q.start < q.end {
+		return minute >= q.start && minute < q.end
+	}
+	return minute >= q.start || minute < q.end
+}
+
+// deferQuiet holds back every alert of targets that are not critical while
+// quiet hours are active. DOWN events are queued, and their recoveries and
+// escalations folded in; other alerts such as SLOW, CERT_* or AUTO_DISABLED,
+// and escalations of outages alerted before quiet hours, are listed in the
+// digest as they are. INVESTIGATING notes are dropped, since the DOWN that
+// may follow is queued. Recoveries and false alarms of alerts sent before
+// quiet hours pass, as they close those. Events not tied to a target pass.
+// Callers hold a.mu.
+func (a *AlertManager) deferQuiet(events []alertEvent, now time.Time) []alertEvent {
+	if a.quiet == nil || !a.quiet.active(now) {
+		return events
+	}
+	kept := make([]alertEvent, 0, len(events))
+	for _, ev := range events {
+		if ev.Target == "" || ev.Critical {
+			kept = append(kept, ev)
+			continue
+		}
+		outage, queued := a.quietQueue[ev.Target]
+		switch ev.Kind {
+		case "DOWN":
+			// holdRecoveries drops a DOWN after an unconfirmed recovery.
+			if _, held := a.heldRecovery[ev.Target]; held {
+				break
+			}
+			// The digest reports the outage, so a note sent before quiet
+			// hours is not upgraded into a DOWN alert later on.
+			delete(a.investigations, ev.Target)
+			if !queued {
+				a.quietQueue[ev.Target] = &quietOutage{event: ev, outages: 1}
+			} else if !outage.recoveredAt.IsZero() {
+				outage.event, outage.recoveredAt = ev, time.Time{}
+				outage.outages++
+			}
+			a.logger.Debug("DOWN alert deferred by quiet hours", "track", ev.Target)
+			continue
+		case "RECOVERED", "RESOLVED":
+			if isSlowRecovery(ev) {
+				if a.quietSlow[ev.Target] {
+					delete(a.quietSlow, ev.Target)
+					continue
+				}
+				break
+			}
+			if !queued {
+				break
+			}
+			outage.recoveredAt = ev.Occurred
+			continue
+		case "STILL_DOWN":
+			if queued {
+				continue
+			}
+			a.deferQuietNotice(ev)
+			continue
+		case "FALSE_ALARM":
+			if _, noted := a.investigations[ev.Target]; noted {
+				break
+			}
+			continue
+		case "INVESTIGATING":
+			a.logger.Debug("INVESTIGATING note dropped by quiet hours", "track", ev.Target)
+			continue
+		default:
+			if ev.Kind == "SLOW" {
+				a.quietSlow[ev.Target] = true
+			}
+			a.deferQuietNotice(ev)
+			continue
+		}
+		kept = append(kept, ev)
+	}
+	return kept
+}
+
+func (a *AlertManager) deferQuietNotice(ev alertEvent) {
+	a.quietNotices = append(a.quietNotices, ev)
+	a.logger.Debug("alert deferred by quiet hours", "track", ev.Target, "kind", ev.Kind)
+}
+
+// flushQuietDigest sends the deferred outages and notices as one digest once
+// quiet hours are over. Targets still down count as alerted, so their
+// recovery reads as usual. Callers hold a.mu.
+func (a *AlertManager) flushQuietDigest(ctx context.Context, now time.Time) {
+	if (len(a.quietQueue) == 0 && len(a.quietNotices) == 0) || a.quiet.active(now) {
+		return
+	}
+	if err := a.sendDefault(ctx, formatQuietDigest(a.quietQueue, a.quietNotices, a.quiet.label, now)); err != nil {
+		a.logger.Warn("failed to send quiet hours digest", "count", len(a.quietQueue)+len(a.quietNotices), "error", err)
+		return
+	}
+	for name, outage := range a.quietQueue {
+		if outage.recoveredAt.IsZero() {
+			a.markDownSent([]alertEvent{outage.event})
+		}
+		delete(a.quietQueue, name)
+	}
+	a.quietNotices = nil
+	clear(a.quietSlow)
+}
+
+func formatQuietDigest(queue map[string]*quietOutage, notices []alertEvent, label string, now time.Time) string {
+	outages := make([]*quietOutage, 0, len(queue))
+	for _, outage := range queue {
+		outages = append(outages, outage)
+	}
+	sort.Slice(outages, func(i, j int) bool {
+		if !outages[i].event.Occurred.Equal(outages[j].event.Occurred) {
+			return outages[i].event.Occurred.Before(outages[j].event.Occurred)
+		}
+		return outages[i].event.Target < outages[j].event.Target
+	})
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<b>QUIET HOURS DIGEST x%d</b>\n", len(outages)+len(notices))
+	fmt.Fprintf(&sb, "window: <code>%s</code>\n", util.HTMLEscape(label))
+	if len(outages) > 0 {
+		sb.WriteString("targets:\n")
+	}
+	for _, outage := range outages {
+		ev := outage.event
+		fmt.Fprintf(&sb, "- <code>%s</code> (<code>%s:%d</code>) ", util.HTMLEscape(ev.Target), util.HTMLEscape(ev.Address), ev.Port)
+		if outage.recoveredAt.IsZero() {
+			fmt.Fprintf(&sb, "<b>DOWN</b> for <code>%s</code>", formatDurationShort(now.Sub(ev.Occurred)))
+		} else {
+			fmt.Fprintf(&sb, "recovered after <code>%s</code>", formatDurationShort(outage.recoveredAt.Sub(ev.Occurred)))
+		}
+		if outage.outages > 1 {
+			fmt.Fprintf(&sb, ", %d outages", outage.outages)
+		}
+		sb.WriteString("\n")
+	}
+	if len(notices) > 0 {
+		sb.WriteString("notices:\n")
+	}
+	// Notices are listed in the order they were deferred.
+	for _, ev := range notices {
+		fmt.Fprintf(&sb, "- <b>%s</b> <code>%s</code> (<code>%s:%d</code>) at <code>%s</code>", util.HTMLEscape(ev.Kind), util.HTMLEscape(ev.Target), util.HTMLEscape(ev.Address), ev.Port, util.FormatTime(ev.Occurred))
+		if ev.Reason != "" {
+			fmt.Fprintf(&sb, ": %s", util.HTMLEscape(ev.Reason))
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,85 @@
+package tracker
+
+import (
+	"fmt"
+
+	"trackway/internal/config"
+)
+
+// Reload applies a reloaded config to the running engine: the monitoring
+// defaults (settings stored with /config still win), the failure threshold,
+// per-target options, maintenance windows and the config target list. State
+// of targets that are kept, e.g. last status and streaks, is not reset.
+func (e *MonitorEngine) Reload(cfg config.Config) error {
+	if err := e.reloadTargets(cfg.Targets); err != nil {
+		return err
+	}
+	targetConfig := make(map[string]config.Target, len(cfg.Targets))
+	for _, item := range cfg.Targets {
+		targetConfig[item.Name] = item
+	}
+
+	e.mu.Lock()
+	for name, item := range e.fileTargets {
+		if _, fromConfig := targetConfig[name]; !fromConfig {
+			targetConfig[name] = item
+		}
+	}
+	e.interval = defaultSeconds(cfg.Monitoring.IntervalSeconds, 5)
+	e.timeout = defaultSeconds(cfg.Monitoring.ConnectTimeoutSeconds, 2)
+	e.maxParallel = cfg.Monitoring.MaxParallelChecks
+	e.escalateAfter = defaultSeconds(cfg.Monitoring.EscalateAfterSeconds, 0)
+	e.failThreshold = cfg.Monitoring.FailureThreshold
+	e.targetConfig = targetConfig
+	e.maintenance = newMaintenanceWindows(cfg.Maintenance)
+	e.mu.Unlock()
+	e.storeConfigTags()
+	e.loadSettings()
+	e.syncTargets()
+
+	select {
+	case e.settingsChanged <- struct{}{}:
+	default:
+	}
+	e.logger.Info("config reloaded", "targets", len(cfg.Targets), "settings", e.Settings())
+	return nil
+}
+
+// reloadTargets stores config targets that are new or moved to another
+// endpoint and removes those dropped from the config since the last load.
+// Targets added from the dashboard, with /add or from the target source file
+// are left alone.
+func (e *MonitorEngine) reloadTargets(targets []config.Target) error {
+	keep := make(map[string]bool, len(targets))
+	for _, item := range targets {
+		keep[item.Name] = true
+	}
+
+	e.mu.RLock()
+	var removed []string
+	for name := range e.targetConfig {
+		if !keep[name] && !e.isFileTarget(name) {
+			removed = append(removed, name)
+		}
+	}
+	var changed []config.Target
+	for _, item := range targets {
+		current := e.targetByName[item.Name]
+		if current == nil || current.Address != item.Address || current.Port != item.Port {
+			changed = append(changed, item)
+		}
+	}
+	e.mu.RUnlock()
+
+	for _, name := range removed {
+		if err := e.logs.DeleteTarget(name); err != nil {
+			return fmt.Errorf("remove target %s: %w", name, err)
+		}
+	}
+	for _, item := range changed {
+		if err := e.logs.UpsertTarget(item.Name, item.Address, item.Port); err != nil {
+			return fmt.Errorf("store target %s: %w", item.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNoAlertToResend is returned by ResendLastAlert before any alert was sent.
+var ErrNoAlertToResend = errors.New("no alert sent yet")
+
+// recordLastBatch keeps the groups of the latest delivered batch for
+// ResendLastAlert. Callers hold a.mu.
+func (a *AlertManager) recordLastBatch(groups [][]alertEvent) {
+	a.lastBatch = make([][]alertEvent, 0, len(groups))
+	for _, group := range groups {
+		a.lastBatch = append(a.lastBatch, append([]alertEvent(nil), group...))
+	}
+}
+
+// ResendLastAlert sends the latest alert batch again to Telegram and every
+// sink, marked as a replay. It does not touch pending DOWN, escalation or
+// recovery state, and returns the number of groups sent.
+func (a *AlertManager) ResendLastAlert(ctx context.Context) (int, error) {
+	if a.notifier == nil {
+		return 0, ErrNoAlertToResend
+	}
+	a.mu.Lock()
+	if len(a.lastBatch) == 0 {
+		a.mu.Unlock()
+		return 0, ErrNoAlertToResend
+	}
+
+	now := time.Now().UTC()
+	var sendErr error
+	replays := make([]AlertGroup, 0, len(a.lastBatch))
+	for _, group := range a.lastBatch {
+		message := "<b>REPLAY</b> resent " + now.Format(time.RFC3339) + ", not a new event\n" + formatAlertGroup(group)
+		if err := a.sendDefault(ctx, message); err != nil {
+			a.logger.Warn("failed to resend alert", "kind", group[0].Kind, "error", err)
+			sendErr = err
+		}
+		alertGroup := newAlertGroup(group)
+		alertGroup.Replay = true
+		replays = append(replays, alertGroup)
+	}
+	sinks := a.sinks
+	a.mu.Unlock()
+
+	if err := a.sendToSinks(ctx, sinks, replays); err != nil {
+		sendErr = err
+	}
+	a.logger.Info("resent last alert batch", "groups", len(replays))
+	return len(replays), sendErr
+}
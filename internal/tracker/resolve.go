@@ -0,0 +1,45 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"slices"
+	"syscall"
+)
+
+// resolveTarget returns the address to dial for a target and how many
+// addresses its host name resolved to. Of several, the lowest is used, IPv4
+// before IPv6, so a check does not hop between records. An IP literal is
+// returned as is with a count of 0.
+func resolveTarget(ctx context.Context, address string) (netip.Addr, int, error) {
+	if ip, err := netip.ParseAddr(address); err == nil {
+		return ip, 0, nil
+	}
+	ips, err := net.DefaultResolver.LookupNetIP(ctx, "ip", address)
+	if err != nil {
+		return netip.Addr{}, 0, err
+	}
+	if len(ips) == 0 {
+		return netip.Addr{}, 0, errors.New("no addresses")
+	}
+	for i := range ips {
+		ips[i] = ips[i].Unmap()
+	}
+	slices.SortFunc(ips, netip.Addr.Compare)
+	return ips[0], len(ips), nil
+}
+
+// connectFailure names why a dial failed for the log reason.
+func connectFailure(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "connect-timeout"
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return "connect-refused"
+	default:
+		return "connect-error"
+	}
+}
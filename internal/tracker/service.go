@@ -2,6 +2,9 @@ package tracker
 
 import (
 	"context"
+	"io"
+	"log/slog"
+	"time"
 
 	"github.com/go-telegram/bot/models"
 
@@ -12,7 +15,9 @@ import (
 type Service struct {
 	engine   *MonitorEngine
 	alerts   *AlertManager
+	board    *statusBoard
 	commands *CommandHandler
+	rounds   roundHub
 
 	// compatibility layer for package tests and internal callers
 	targets      []*TargetState
@@ -21,32 +26,119 @@ type Service struct {
 
 func New(cfg config.Config, logs *logstore.Store, notifier Notifier) *Service {
 	engine := NewMonitorEngine(cfg, logs)
-	alerts := NewAlertManager(notifier)
-	commands := NewCommandHandler(cfg.Bot.ChatID, engine, notifier)
+	alerts := NewAlertManager(notifier, alertOptions(cfg))
+	commands := NewCommandHandler(cfg.Bot.ChatID, engine, notifier, cfg.Bot.UpdateQueueSize)
+	commands.queue.alert = cfg.Bot.AlertDroppedUpdates
+	commands.graphWidth, commands.graphHeight = cfg.Graph.Width, cfg.Graph.Height
+	alerts.SetRecoveryConfirm(engine.Recheck)
+	commands.SetResender(alerts.ResendLastAlert)
+	commands.SetAcknowledger(acknowledger(alerts, engine))
+	commands.SetAlertPauser(alerts)
+	alerts.SetStatusSource(engine.Snapshot)
+	var board *statusBoard
+	if cfg.Bot.PinnedStatus && notifier != nil {
+		board = newStatusBoard(notifier, logs)
+		alerts.board = board
+	}
 
 	return &Service{
 		engine:       engine,
 		alerts:       alerts,
+		board:        board,
 		commands:     commands,
 		targets:      engine.targets,
 		targetByName: engine.targetByName,
 	}
 }
 
+func alertOptions(cfg config.Config) AlertOptions {
+	options := AlertOptions{
+		EscalationChatID: cfg.Bot.EscalationChatID,
+		OrphanRecovery:   cfg.Monitoring.OrphanRecovery,
+		QuietHours:       cfg.QuietHours,
+	}
+	if cfg.Monitoring.RecoveryConfirmSeconds > 0 {
+		options.RecoveryConfirmDelay = time.Duration(cfg.Monitoring.RecoveryConfirmSeconds) * time.Second
+	}
+	if cfg.Monitoring.AlertCooldownSeconds > 0 {
+		options.DownCooldown = time.Duration(cfg.Monitoring.AlertCooldownSeconds) * time.Second
+	}
+	if cfg.Monitoring.FastRecoveryWindowSeconds > 0 {
+		options.FastRecoveryWindow = time.Duration(cfg.Monitoring.FastRecoveryWindowSeconds) * time.Second
+	}
+	return options
+}
+
 func (s *Service) SetAuthLinkGenerator(fn func() (string, error)) {
 	s.commands.SetAuthLinkGenerator(fn)
 }
 
+// SetDashboardLinkGenerator makes DOWN alerts end with a signed dashboard
+// link built by fn.
+func (s *Service) SetDashboardLinkGenerator(fn func(path string) (string, error)) {
+	s.alerts.SetDashboardLink(fn)
+}
+
+// AddAlertSink sends alerts to sink in addition to Telegram.
+func (s *Service) AddAlertSink(sink AlertSink) {
+	s.alerts.AddSink(sink)
+}
+
+func (s *Service) SetLogLevelVar(level *slog.LevelVar) {
+	s.commands.SetLogLevelVar(level)
+}
+
 func (s *Service) RunMonitor(ctx context.Context) {
 	s.engine.Run(ctx, func(events []alertEvent) {
+		if s.board != nil {
+			s.board.update(ctx, s.engine.Snapshot())
+		}
+		s.rounds.publish()
 		s.alerts.SendBatch(ctx, events)
 	})
 }
 
+// SubscribeRounds returns a channel that receives a signal after each check
+// round, and a function that cancels the subscription. Signals are dropped
+// while the previous one is unread.
+func (s *Service) SubscribeRounds() (<-chan struct{}, func()) {
+	return s.rounds.subscribe()
+}
+
+// FirstCycleDone is closed once the monitor has completed its first cycle.
+func (s *Service) FirstCycleDone() <-chan struct{} {
+	return s.engine.FirstCycleDone()
+}
+
+// LastRoundAt returns when the last check round finished; zero before the
+// first one.
+func (s *Service) LastRoundAt() time.Time {
+	return s.engine.LastRoundAt()
+}
+
+func (s *Service) Ready() bool {
+	return s.engine.Ready()
+}
+
 func (s *Service) HandleUpdate(ctx context.Context, update *models.Update) {
 	s.commands.HandleUpdate(ctx, update)
 }
 
+// EnqueueUpdate queues update for RunUpdates, dropping it when the queue is
+// full so Telegram polling never blocks on a slow command.
+func (s *Service) EnqueueUpdate(ctx context.Context, update *models.Update) {
+	s.commands.EnqueueUpdate(ctx, update)
+}
+
+func (s *Service) RunUpdates(ctx context.Context) {
+	s.commands.RunUpdates(ctx)
+}
+
+// ResendLastAlert replays the last alert batch to Telegram and all sinks.
+func (s *Service) ResendLastAlert(ctx context.Context) (int, error) {
+	return s.alerts.ResendLastAlert(ctx)
+}
+
 func (s *Service) Snapshot() Snapshot {
 	return s.engine.Snapshot()
 }
@@ -55,10 +147,71 @@ func (s *Service) TargetNames() []string {
 	return s.engine.TargetNames()
 }
 
+func (s *Service) Diagnostics() Diagnostics {
+	return s.engine.Diagnostics()
+}
+
+func (s *Service) ExportTargets() []config.Target {
+	return s.engine.ExportTargets()
+}
+
 func (s *Service) Logs(trackName string, days int, limit int) ([]logstore.Row, bool) {
 	return s.engine.Logs(trackName, days, limit)
 }
 
+func (s *Service) GraphRows(trackName string, from time.Time, bucket time.Duration) ([]logstore.Row, bool) {
+	return s.engine.GraphRows(trackName, from, bucket)
+}
+
+func (s *Service) Backup(ctx context.Context) (io.ReadCloser, int64, error) {
+	return s.engine.Backup(ctx)
+}
+
+func (s *Service) Mute(trackName string, until time.Time) (time.Time, error) {
+	return s.engine.Mute(trackName, until)
+}
+
+func (s *Service) Unmute(trackName string) error {
+	return s.engine.Unmute(trackName)
+}
+
+func (s *Service) RecentChanges(limit int) []logstore.TargetRow {
+	return s.engine.RecentChanges(limit)
+}
+
+func (s *Service) SLA(trackName string, days int) ([]SLAReport, bool) {
+	return s.engine.SLA(trackName, days)
+}
+
+func (s *Service) Compare(trackName string, days int) (WindowComparison, bool) {
+	return s.engine.Compare(trackName, days)
+}
+
+func (s *Service) Settings() MonitoringSettings {
+	return s.engine.Settings()
+}
+
+func (s *Service) UpdateSettings(values map[string]int) (MonitoringSettings, error) {
+	return s.engine.UpdateSettings(values)
+}
+
+func (s *Service) OverallSLA(days int) (OverallSLAReport, bool) {
+	return s.engine.OverallSLA(days)
+}
+
+func (s *Service) Ping(ctx context.Context, trackName string, count int) (PingResult, bool) {
+	return s.engine.Ping(ctx, trackName, count)
+}
+
+// Reload applies a reloaded config to the monitor without a restart.
+func (s *Service) Reload(cfg config.Config) error {
+	return s.engine.Reload(cfg)
+}
+
+func (s *Service) ListTargets() ([]logstore.Target, error) {
+	return s.engine.ListTargets()
+}
+
 func (s *Service) UpsertTarget(name, address string, port int) error {
 	return s.engine.UpsertTarget(name, address, port)
 }
@@ -83,10 +236,90 @@ func (s *Service) statusText() string {
 	return s.commands.statusText()
 }
 
+func (s *Service) targetStatusText(trackName string) string {
+	return s.commands.targetStatusText(trackName)
+}
+
 func (s *Service) logsMessages(trackName string) []string {
 	return s.commands.logsMessages(trackName)
 }
 
+func (s *Service) droppedUpdates() int64 {
+	return s.commands.DroppedUpdates()
+}
+
+func (s *Service) diagText() string {
+	return s.commands.diagText()
+}
+
+func (s *Service) exportTargetsMessages(format string) []string {
+	return s.commands.exportTargetsMessages(format)
+}
+
+func (s *Service) slaText(args []string) string {
+	return s.commands.slaText(args)
+}
+
+func (s *Service) recentMessages(args []string) []string {
+	return s.commands.recentMessages(args)
+}
+
+func (s *Service) Incidents(trackName string, days int) ([]logstore.Incident, bool) {
+	return s.engine.Incidents(trackName, days)
+}
+
+func (s *Service) ActiveMaintenance(now time.Time) []ActiveMaintenance {
+	return s.engine.ActiveMaintenance(now)
+}
+
+func (s *Service) maintenanceText(now time.Time) string {
+	return s.commands.maintenanceText(now)
+}
+
+func (s *Service) incidentsText(args []string, now time.Time) string {
+	return s.commands.incidentsText(args, now)
+}
+
+func (s *Service) compareText(args []string) string {
+	return s.commands.compareText(args)
+}
+
+func (s *Service) thresholdText(args []string) string {
+	return s.commands.thresholdText(args)
+}
+
+func (s *Service) graphText(args []string) string {
+	return s.commands.graphText(args)
+}
+
+func (s *Service) changesText(args []string) string {
+	return s.commands.changesText(args)
+}
+
+func (s *Service) configText(args []string) string {
+	return s.commands.configText(args)
+}
+
+func (s *Service) nextText(trackName string, now time.Time) string {
+	return s.commands.nextText(trackName, now)
+}
+
+func (s *Service) muteText(command string, args []string) string {
+	return s.commands.muteText(command, args)
+}
+
+func (s *Service) manageTargetText(chatID int64, command string, args []string) string {
+	return s.commands.manageTargetText(chatID, command, args)
+}
+
+func (s *Service) pingText(ctx context.Context, args []string) string {
+	return s.commands.pingText(ctx, args)
+}
+
+func (s *Service) resendText(ctx context.Context) string {
+	return s.commands.resendText(ctx)
+}
+
 func (s *Service) authLinkText(chatID int64) string {
 	return s.commands.authLinkText(chatID)
 }
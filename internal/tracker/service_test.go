@@ -1,9 +1,22 @@
 package tracker
 
 import (
+	"bytes"
 	"context"
+	"crypto/x509"
+	"errors"
+	"image/png"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -18,11 +31,16 @@ type fakeNotifier struct {
 	defaults []string
 	replies  []string
 	edits    []string
+	editErr  error
+	sendErr  error
 }
 
 func (f *fakeNotifier) SendDefaultHTML(_ context.Context, text string) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	if f.sendErr != nil {
+		return f.sendErr
+	}
 	f.defaults = append(f.defaults, text)
 	return nil
 }
@@ -30,6 +48,9 @@ func (f *fakeNotifier) SendDefaultHTML(_ context.Context, text string) error {
 func (f *fakeNotifier) SendDefaultHTMLWithID(_ context.Context, text string) (int, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	if f.sendErr != nil {
+		return 0, f.sendErr
+	}
 	f.defaults = append(f.defaults, text)
 	return 100 + len(f.defaults), nil
 }
@@ -37,6 +58,9 @@ func (f *fakeNotifier) SendDefaultHTMLWithID(_ context.Context, text string) (in
 func (f *fakeNotifier) EditDefaultHTML(_ context.Context, _ int, text string) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	if f.editErr != nil {
+		return f.editErr
+	}
 	f.edits = append(f.edits, text)
 	return nil
 }
@@ -132,6 +156,44 @@ func TestSendAlertBatchCombinesSameKind(t *testing.T) {
 	}
 }
 
+func TestFormatAlertGroupListsDistinctMentions(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	down := formatAlertGroup([]alertEvent{
+		{Kind: "DOWN", Target: "a", Address: "10.0.0.1", Port: 80, Reason: "state-change", Mention: "@oncall", Occurred: now},
+		{Kind: "DOWN", Target: "b", Address: "10.0.0.2", Port: 443, Reason: "state-change", Mention: "@oncall", Occurred: now},
+		{Kind: "DOWN", Target: "c", Address: "10.0.0.3", Port: 22, Reason: "state-change", Mention: "@db<team>", Occurred: now},
+		{Kind: "DOWN", Target: "d", Address: "10.0.0.4", Port: 22, Reason: "state-change", Occurred: now},
+	})
+	if !strings.HasSuffix(down, "cc: @oncall @db&lt;team&gt;") {
+		t.Fatalf("expected escaped distinct mentions footer, got %q", down)
+	}
+
+	recovered := formatAlertGroup([]alertEvent{
+		{Kind: "RECOVERED", Target: "a", Address: "10.0.0.1", Port: 80, Reason: "state-change", Mention: "@oncall", Occurred: now},
+	})
+	if strings.Contains(recovered, "cc:") {
+		t.Fatalf("mentions must only be added to DOWN alerts, got %q", recovered)
+	}
+}
+
+func TestFormatAlertGroupLinksRunbooks(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	down := formatAlertGroup([]alertEvent{
+		{Kind: "DOWN", Target: "a", Address: "10.0.0.1", Port: 80, Reason: "state-change", RunbookURL: "https://wiki.example/a?x=1&y=\"2\"", Occurred: now},
+		{Kind: "DOWN", Target: "b", Address: "10.0.0.2", Port: 443, Reason: "state-change", Occurred: now},
+	})
+	if !strings.Contains(down, `(<code>10.0.0.1:80</code>) <a href="https://wiki.example/a?x=1&amp;y=&quot;2&quot;">runbook</a>`) {
+		t.Fatalf("expected escaped runbook link for a, got %q", down)
+	}
+	if strings.Count(down, "runbook") != 1 {
+		t.Fatalf("expected runbook link only for targets that have one, got %q", down)
+	}
+}
+
 func TestFastRecoveryEditsDownMessage(t *testing.T) {
 	t.Parallel()
 
@@ -182,6 +244,449 @@ func TestFastRecoveryEditsDownMessage(t *testing.T) {
 	}
 }
 
+func TestFastRecoveryWindowIsConfigurable(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Monitoring.FastRecoveryWindowSeconds = 10
+	notifier := &fakeNotifier{}
+	svc := New(cfg, store, notifier)
+
+	downTime := time.Now().UTC()
+	svc.sendAlertBatch(context.Background(), []alertEvent{
+		{Kind: "DOWN", Target: "test-track", Address: "127.0.0.1", Port: 1, Reason: "state-change", Occurred: downTime},
+	})
+	svc.sendAlertBatch(context.Background(), []alertEvent{
+		{Kind: "RECOVERED", Target: "test-track", Address: "127.0.0.1", Port: 1, Reason: "state-change", Occurred: downTime.Add(11 * time.Second)},
+	})
+
+	if len(notifier.edits) != 0 {
+		t.Fatalf("expected no edit outside the window, got %q", notifier.edits)
+	}
+	if len(notifier.defaults) != 2 || !strings.Contains(notifier.defaults[1], "RECOVERED") || strings.Contains(notifier.defaults[1], "DOWN -> RECOVERED") {
+		t.Fatalf("expected a fresh RECOVERED message, got %q", notifier.defaults)
+	}
+
+	svc.sendAlertBatch(context.Background(), []alertEvent{
+		{Kind: "DOWN", Target: "test-track", Address: "127.0.0.1", Port: 1, Reason: "state-change", Occurred: downTime.Add(20 * time.Second)},
+	})
+	svc.sendAlertBatch(context.Background(), []alertEvent{
+		{Kind: "RECOVERED", Target: "test-track", Address: "127.0.0.1", Port: 1, Reason: "state-change", Occurred: downTime.Add(29 * time.Second)},
+	})
+	if len(notifier.edits) != 1 || !strings.Contains(notifier.edits[0], "DOWN -> RECOVERED") {
+		t.Fatalf("expected a recovery inside the window to edit the alert, got %q", notifier.edits)
+	}
+}
+
+func TestSlowTargetAlertsAndRecoversWithEdit(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Targets[0].SlowThresholdMs = 500
+	cfg.Targets = append(cfg.Targets, config.Target{Name: "other", Address: "127.0.0.1", Port: 2, SlowThresholdMs: 500})
+	notifier := &fakeNotifier{}
+	svc := New(cfg, store, notifier)
+	ctx := context.Background()
+	slow := checkResult{Up: true, Latency: 800 * time.Millisecond}
+	fast := checkResult{Up: true, Latency: 100 * time.Millisecond}
+
+	var events []alertEvent
+	for _, target := range svc.targets {
+		if ev := svc.engine.applyResult(target, fast); ev != nil {
+			t.Fatalf("expected no alert for a fast first check, got %+v", ev)
+		}
+		ev := svc.engine.applyResult(target, slow)
+		if ev == nil || ev.Kind != "SLOW" || ev.Reason != slowReason {
+			t.Fatalf("expected SLOW event, got %+v", ev)
+		}
+		events = append(events, *ev)
+	}
+	if ev := svc.engine.applyResult(svc.targets[0], slow); ev != nil {
+		t.Fatalf("expected SLOW to alert once, got %+v", ev)
+	}
+	svc.sendAlertBatch(ctx, append(events, alertEvent{Kind: "DOWN", Target: "gone", Address: "127.0.0.1", Port: 3, Reason: "state-change", Occurred: time.Now().UTC()}))
+	if len(notifier.defaults) != 2 || !strings.Contains(notifier.defaults[1], "<b>SLOW x2</b>") || !strings.Contains(notifier.defaults[1], "800.0ms &gt; 500.0ms") {
+		t.Fatalf("expected slow targets grouped apart from DOWN, got %q", notifier.defaults)
+	}
+
+	events = nil
+	for _, target := range svc.targets {
+		ev := svc.engine.applyResult(target, fast)
+		if ev == nil || !isSlowRecovery(*ev) {
+			t.Fatalf("expected slow recovery, got %+v", ev)
+		}
+		events = append(events, *ev)
+	}
+	svc.sendAlertBatch(ctx, events)
+	if len(notifier.edits) != 1 || !strings.Contains(notifier.edits[0], "SLOW -> RECOVERED x2") || !strings.Contains(notifier.edits[0], "slow_for:") {
+		t.Fatalf("expected the SLOW alert to be edited, got %q", notifier.edits)
+	}
+
+	rows := store.ReadLastDays("test-track", 1, 10)
+	if len(rows) < 3 || !strings.Contains(rows[1].Reason, "SLOW") || strings.Contains(rows[len(rows)-1].Reason, "SLOW") {
+		t.Fatalf("expected SLOW in the log reason of slow checks, got %+v", rows)
+	}
+
+	svc.engine.applyResult(svc.targets[0], slow)
+	if ev := svc.engine.applyResult(svc.targets[0], checkResult{Up: false}); ev == nil || ev.Kind != "DOWN" {
+		t.Fatalf("expected DOWN to take over from SLOW, got %+v", ev)
+	}
+	if ev := svc.engine.applyResult(svc.targets[0], fast); ev == nil || ev.Kind != "RECOVERED" || ev.Reason != "state-change" {
+		t.Fatalf("expected a plain recovery after DOWN, got %+v", ev)
+	}
+}
+
+func TestAlertCooldownSuppressesRepeatedDown(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Monitoring.AlertCooldownSeconds = 300
+	notifier := &fakeNotifier{}
+	svc := New(cfg, store, notifier)
+
+	ctx := context.Background()
+	downTime := time.Now().UTC()
+	send := func(kind, reason string, at time.Duration) {
+		svc.sendAlertBatch(ctx, []alertEvent{
+			{Kind: kind, Target: "test-track", Address: "127.0.0.1", Port: 1, Reason: reason, Occurred: downTime.Add(at)},
+		})
+	}
+	// The target flaps: its recovery never reaches the chat, e.g. while
+	// muted, and it goes DOWN again inside the cooldown.
+	send("DOWN", "state-change", 0)
+	send("DOWN", "state-change", time.Minute)
+	send("DOWN", "initial-check", 2*time.Minute)
+	if len(notifier.defaults) != 1 {
+		t.Fatalf("expected a single DOWN message within the cooldown, got %q", notifier.defaults)
+	}
+
+	// A delivered recovery does not end the cooldown either: the DOWN of the
+	// next flap folds into the first alert and its recovery is a plain one.
+	send("RECOVERED", "state-change", 3*time.Minute)
+	send("DOWN", "state-change", 4*time.Minute)
+	send("RECOVERED", "state-change", 4*time.Minute+30*time.Second)
+	if len(notifier.defaults) != 3 || !strings.Contains(notifier.defaults[1], "RECOVERED") || !strings.Contains(notifier.defaults[2], "RECOVERED") || strings.Contains(notifier.defaults[2], "NOW_UP") {
+		t.Fatalf("expected the flap to send no second DOWN, got %q", notifier.defaults)
+	}
+
+	send("DOWN", "state-change", 9*time.Minute)
+	if len(notifier.defaults) != 4 || !strings.Contains(notifier.defaults[3], "DOWN") {
+		t.Fatalf("expected a DOWN after the cooldown to be sent, got %q", notifier.defaults)
+	}
+	send("DOWN", "state-change", 11*time.Minute)
+	if len(notifier.defaults) != 4 {
+		t.Fatalf("expected the new DOWN to start another cooldown, got %q", notifier.defaults)
+	}
+}
+
+func TestEscalatedOutageResolvesWithDeEscalation(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Monitoring.EscalateAfterSeconds = 600
+	notifier := &fakeNotifier{}
+	svc := New(cfg, store, notifier)
+	target := svc.targetByName["test-track"]
+	svc.applyStatus(target, false)
+
+	now := time.Now().UTC()
+	if ev := svc.engine.escalate(target, now.Add(time.Minute)); ev != nil {
+		t.Fatalf("expected no escalation before threshold, got %+v", ev)
+	}
+	ev := svc.engine.escalate(target, now.Add(15*time.Minute))
+	if ev == nil || ev.Kind != "STILL_DOWN" {
+		t.Fatalf("expected STILL_DOWN event, got %+v", ev)
+	}
+	if again := svc.engine.escalate(target, now.Add(30*time.Minute)); again != nil {
+		t.Fatalf("expected a single escalation per outage, got %+v", again)
+	}
+	svc.sendAlertBatch(context.Background(), []alertEvent{*ev})
+
+	recovered := svc.applyStatus(target, true)
+	if recovered == nil {
+		t.Fatal("expected RECOVERED event")
+	}
+	svc.sendAlertBatch(context.Background(), []alertEvent{*recovered})
+
+	last := notifier.defaults[len(notifier.defaults)-1]
+	if !strings.Contains(last, "<b>RESOLVED</b>") || !strings.Contains(last, "after-escalation") {
+		t.Fatalf("expected de-escalation message, got %q", last)
+	}
+	if target.Escalated {
+		t.Fatal("expected escalation flag to reset on recovery")
+	}
+}
+
+func TestQuietHoursDeferNonCriticalAlertsIntoDigest(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.QuietHours = config.QuietHours{Start: "22:00", End: "07:00", Timezone: "America/New_York"}
+	notifier := &fakeNotifier{}
+	svc := New(cfg, store, notifier)
+
+	ctx := context.Background()
+	send := func(at time.Time, events ...alertEvent) {
+		svc.alerts.mu.Lock()
+		defer svc.alerts.mu.Unlock()
+		svc.alerts.sendBatchAt(ctx, events, at)
+	}
+	event := func(kind, target string, critical bool, at time.Time) alertEvent {
+		return alertEvent{Kind: kind, Target: target, Address: "10.0.0.1", Port: 443, Reason: "state-change", Critical: critical, Occurred: at}
+	}
+
+	// 23:00 UTC is 18:00 in New York, outside quiet hours.
+	evening := time.Date(2026, 1, 14, 23, 0, 0, 0, time.UTC)
+	send(evening, event("DOWN", "web", false, evening))
+	if len(notifier.defaults) != 1 {
+		t.Fatalf("expected an immediate alert outside local quiet hours, got %q", notifier.defaults)
+	}
+
+	// 10:00 UTC is 05:00 in New York.
+	night := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	send(night, event("DOWN", "api", false, night), event("DOWN", "db", false, night), event("DOWN", "core", true, night))
+	send(night.Add(20*time.Minute), event("RECOVERED", "db", false, night.Add(20*time.Minute)))
+	if len(notifier.defaults) != 2 || !strings.Contains(notifier.defaults[1], "core") || strings.Contains(notifier.defaults[1], "api") {
+		t.Fatalf("expected only the critical target to alert during quiet hours, got %q", notifier.defaults)
+	}
+
+	send(night.Add(time.Hour))
+	if len(notifier.defaults) != 2 {
+		t.Fatalf("expected no digest during quiet hours, got %q", notifier.defaults)
+	}
+	// 12:30 UTC is 07:30 in New York.
+	morning := time.Date(2026, 1, 15, 12, 30, 0, 0, time.UTC)
+	send(morning)
+	if len(notifier.defaults) != 3 {
+		t.Fatalf("expected a digest after quiet hours, got %q", notifier.defaults)
+	}
+	digest := notifier.defaults[2]
+	for _, want := range []string{"QUIET HOURS DIGEST x2", "22:00-07:00 America/New_York", "<code>api</code> (<code>10.0.0.1:443</code>) <b>DOWN</b> for <code>2h30m0s</code>", "<code>db</code> (<code>10.0.0.1:443</code>) recovered after <code>20m0s</code>"} {
+		if !strings.Contains(digest, want) {
+			t.Fatalf("expected digest to contain %q, got %q", want, digest)
+		}
+	}
+
+	send(morning, event("RECOVERED", "api", false, morning.Add(time.Minute)))
+	send(morning.Add(time.Hour))
+	if len(notifier.defaults) != 4 || !strings.HasPrefix(notifier.defaults[3], "<b>RECOVERED</b>") {
+		t.Fatalf("expected the recovery after the digest to be sent as usual, got %q", notifier.defaults)
+	}
+}
+
+func TestQuietHoursDeferInvestigationsAndNotices(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Monitoring.InvestigateFirstFailure = true
+	cfg.QuietHours = config.QuietHours{Start: "22:00", End: "07:00", Timezone: "UTC"}
+	notifier := &fakeNotifier{}
+	svc := New(cfg, store, notifier)
+	target := svc.targets[0]
+
+	ctx := context.Background()
+	send := func(at time.Time, events ...alertEvent) {
+		svc.alerts.mu.Lock()
+		defer svc.alerts.mu.Unlock()
+		svc.alerts.sendBatchAt(ctx, events, at)
+	}
+	step := func(up bool, at time.Time) {
+		if ev := svc.applyStatus(target, up); ev != nil {
+			send(at, *ev)
+		}
+	}
+
+	// The note is sent before quiet hours start.
+	evening := time.Date(2026, 1, 14, 21, 0, 0, 0, time.UTC)
+	step(true, evening)
+	step(false, evening)
+	if len(notifier.defaults) != 1 || !strings.Contains(notifier.defaults[0], "<b>INVESTIGATING</b>") {
+		t.Fatalf("expected an investigating note before quiet hours, got %q", notifier.defaults)
+	}
+
+	night := time.Date(2026, 1, 15, 2, 0, 0, 0, time.UTC)
+	step(false, night)
+	step(true, night.Add(10*time.Minute))
+	// A new first failure and its false alarm stay quiet too.
+	step(false, night.Add(20*time.Minute))
+	step(true, night.Add(30*time.Minute))
+	send(night.Add(40*time.Minute), alertEvent{Kind: "CERT_CHANGED", Target: target.Name, Address: target.Address, Port: target.Port, Reason: "fingerprint-changed", Occurred: night.Add(40 * time.Minute)})
+	if len(notifier.defaults) != 1 || len(notifier.edits) != 0 {
+		t.Fatalf("expected no alerts during quiet hours, got defaults=%q edits=%q", notifier.defaults, notifier.edits)
+	}
+	if len(svc.alerts.investigations) != 0 {
+		t.Fatalf("expected no investigation left to upgrade, got %+v", svc.alerts.investigations)
+	}
+
+	morning := time.Date(2026, 1, 15, 7, 30, 0, 0, time.UTC)
+	send(morning)
+	if len(notifier.defaults) != 2 {
+		t.Fatalf("expected a digest after quiet hours, got %q", notifier.defaults)
+	}
+	digest := notifier.defaults[1]
+	for _, want := range []string{"QUIET HOURS DIGEST x2", "<code>test-track</code> (<code>127.0.0.1:1</code>) recovered after", "<b>CERT_CHANGED</b> <code>test-track</code>"} {
+		if !strings.Contains(digest, want) {
+			t.Fatalf("expected digest to contain %q, got %q", want, digest)
+		}
+	}
+	if strings.Contains(digest, "INVESTIGATING") || strings.Contains(digest, "FALSE_ALARM") {
+		t.Fatalf("expected investigations to stay out of the digest, got %q", digest)
+	}
+
+	// The next outage gets a note of its own instead of editing the old one.
+	step(false, morning.Add(time.Hour))
+	step(false, morning.Add(time.Hour))
+	if len(notifier.defaults) != 3 || len(notifier.edits) != 1 || !strings.Contains(notifier.edits[0], "<b>DOWN</b>") {
+		t.Fatalf("expected a new note upgraded to DOWN, got defaults=%q edits=%q", notifier.defaults, notifier.edits)
+	}
+}
+
+func TestPauseSuspendsAllAlertsUntilResume(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	notifier := &fakeNotifier{}
+	svc := New(testConfig(), store, notifier)
+	ctx := context.Background()
+	command := func(text string) string {
+		notifier.replies = nil
+		svc.HandleUpdate(ctx, &models.Update{Message: &models.Message{Text: text, Chat: models.Chat{ID: 1}}})
+		if len(notifier.replies) != 1 {
+			t.Fatalf("%s: expected one reply, got %q", text, notifier.replies)
+		}
+		return notifier.replies[0]
+	}
+
+	if reply := command("/pause 0"); !strings.HasPrefix(reply, "Usage: /pause") {
+		t.Fatalf("expected usage, got %q", reply)
+	}
+	if reply := command("/pause 30"); !strings.Contains(reply, "paused until <code>") {
+		t.Fatalf("expected the resume time in the reply, got %q", reply)
+	}
+	if reply := command("/status"); !strings.HasPrefix(reply, "<b>ALERTS PAUSED</b> until <code>") {
+		t.Fatalf("expected a pause banner in /status, got %q", reply)
+	}
+
+	target := svc.targets[0]
+	if ev := svc.applyStatus(target, false); ev != nil {
+		svc.sendAlertBatch(ctx, []alertEvent{*ev})
+	}
+	// Held recoveries are delivered outside SendBatch and must wait too.
+	svc.alerts.mu.Lock()
+	svc.alerts.deliver(ctx, []alertEvent{{Kind: "RECOVERED", Target: "other", Reason: "state-change", Occurred: time.Now()}}, time.Now())
+	svc.alerts.mu.Unlock()
+	if len(notifier.defaults) != 0 {
+		t.Fatalf("expected no alerts while paused, got %q", notifier.defaults)
+	}
+	if rows := store.ReadLastDays(target.Name, 1, 10); len(rows) != 1 {
+		t.Fatalf("expected log rows to continue while paused, got %+v", rows)
+	}
+
+	// The pause ends on its own once its time has passed.
+	later := time.Now().Add(31 * time.Minute)
+	svc.alerts.mu.Lock()
+	svc.alerts.sendBatchAt(ctx, []alertEvent{{Kind: "DOWN", Target: target.Name, Reason: "state-change", Occurred: later}}, later)
+	svc.alerts.mu.Unlock()
+	if len(notifier.defaults) != 2 || !strings.Contains(notifier.defaults[0], "RESUMED") || !strings.Contains(notifier.defaults[1], "DOWN") {
+		t.Fatalf("expected a resume notice and the alert after the pause, got %q", notifier.defaults)
+	}
+	if !strings.Contains(notifier.defaults[0], "still down:\n- <code>test-track</code>") {
+		t.Fatalf("expected the resume notice to list targets still down, got %q", notifier.defaults[0])
+	}
+
+	command("/pause")
+	if reply := command("/status"); !strings.HasPrefix(reply, "<b>ALERTS PAUSED</b> until /resume") {
+		t.Fatalf("expected an open-ended pause banner, got %q", reply)
+	}
+	svc.applyStatus(target, true)
+	if reply := command("/resume"); reply != "Alerts are resumed." {
+		t.Fatalf("unexpected resume reply %q", reply)
+	}
+	if last := notifier.defaults[len(notifier.defaults)-1]; !strings.Contains(last, "alerts were resumed\nno targets are down") {
+		t.Fatalf("expected a resume notice on /resume, got %q", last)
+	}
+	if reply := command("/resume"); reply != "Alerts were not paused." {
+		t.Fatalf("unexpected second resume reply %q", reply)
+	}
+	if reply := command("/status"); strings.Contains(reply, "PAUSED") {
+		t.Fatalf("expected no banner after /resume, got %q", reply)
+	}
+}
+
+func TestFastRecoveryResendsWhenDownMessageWasDeleted(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	notifier := &fakeNotifier{editErr: errors.New("bad request, Bad Request: message to edit not found")}
+	svc := New(testConfig(), store, notifier)
+
+	downTime := time.Now().UTC()
+	svc.sendAlertBatch(context.Background(), []alertEvent{
+		{Kind: "DOWN", Target: "test-track", Address: "127.0.0.1", Port: 1, Reason: "state-change", Occurred: downTime},
+	})
+	svc.sendAlertBatch(context.Background(), []alertEvent{
+		{Kind: "RECOVERED", Target: "test-track", Address: "127.0.0.1", Port: 1, Reason: "state-change", Occurred: downTime.Add(7 * time.Second)},
+	})
+
+	if len(notifier.defaults) != 2 {
+		t.Fatalf("expected DOWN plus one resent recovery, got %q", notifier.defaults)
+	}
+	if !strings.Contains(notifier.defaults[1], "DOWN -> RECOVERED") || !strings.Contains(notifier.defaults[1], "downtime: <code>7s</code>") {
+		t.Fatalf("expected recovery with downtime, got %q", notifier.defaults[1])
+	}
+}
+
+func TestPingTargetWithoutPortSurvivesStoreSync(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Targets = append(cfg.Targets, config.Target{Name: "nas", Address: "127.0.0.1", Type: "ping"})
+	if err := store.UpsertTarget("nas", "127.0.0.1", 0); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	svc := New(cfg, store, &fakeNotifier{})
+
+	svc.engine.syncTargets()
+	target := svc.engine.targetByName["nas"]
+	if target == nil || target.CheckType != "ping" || target.Port != 0 {
+		t.Fatalf("expected ping target to be kept without a port, got %+v", target)
+	}
+}
+
 func TestFastRecoveryGroupEditsDownMessage(t *testing.T) {
 	t.Parallel()
 
@@ -329,18 +834,1933 @@ func TestHandleUpdateRejectsUnauthorizedChat(t *testing.T) {
 	}
 }
 
-func testConfig() config.Config {
-	var cfg config.Config
-	cfg.Bot.Token = "token"
-	cfg.Bot.ChatID = 1
-	cfg.Monitoring.IntervalSeconds = 1
-	cfg.Monitoring.ConnectTimeoutSeconds = 1
-	cfg.Targets = []config.Target{
-		{
-			Name:    "test-track",
-			Address: "127.0.0.1",
-			Port:    1,
-		},
+func TestUpsertTargetDetectsDuplicateEndpoints(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	svc := New(testConfig(), store, &fakeNotifier{})
+	if err := svc.UpsertTarget("web", "Example.com.", 443); err != nil {
+		t.Fatalf("upsert web: %v", err)
+	}
+	if err := svc.UpsertTarget("web-copy", "example.com", 443); err != nil {
+		t.Fatalf("upsert duplicate without blocking: %v", err)
+	}
+
+	diag := svc.Diagnostics()
+	if len(diag.DuplicateEndpoints) != 1 {
+		t.Fatalf("expected one duplicate endpoint, got %+v", diag.DuplicateEndpoints)
+	}
+	if got := diag.DuplicateEndpoints[0]; got.Endpoint != "example.com:443" || len(got.Targets) != 2 {
+		t.Fatalf("unexpected duplicate entry: %+v", got)
+	}
+	if text := svc.diagText(); !strings.Contains(text, "web, web-copy") {
+		t.Fatalf("expected duplicate targets in /diag output, got %q", text)
+	}
+}
+
+func TestUpsertTargetRejectsDuplicateEndpointWhenConfigured(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Monitoring.RejectDuplicateEndpoints = true
+	svc := New(cfg, store, &fakeNotifier{})
+	if err := svc.UpsertTarget("web", "10.0.0.1", 443); err != nil {
+		t.Fatalf("upsert web: %v", err)
+	}
+	if err := svc.UpsertTarget("web-copy", "10.0.0.1", 443); err == nil {
+		t.Fatal("expected duplicate endpoint to be rejected")
+	}
+	if err := svc.UpsertTarget("web", "10.0.0.1", 443); err != nil {
+		t.Fatalf("re-upserting the same target must be allowed: %v", err)
+	}
+}
+
+func TestSLAReportsPercentilesFromStore(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	svc := New(testConfig(), store, &fakeNotifier{})
+	if err := store.Append("test-track", "127.0.0.1", 1, false, "CHANGE"); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	for i := 1; i <= 100; i++ {
+		entry := logstore.Entry{Target: "test-track", Address: "127.0.0.1", Port: 1, Status: true, Reason: "POLL", Latency: time.Duration(i) * time.Millisecond}
+		if err := store.AppendEntry(entry); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	reports, ok := svc.SLA("test-track", 7)
+	if !ok || len(reports) != 1 {
+		t.Fatalf("expected one report, got %+v", reports)
+	}
+	report := reports[0]
+	if report.Checks != 101 || report.UpChecks != 100 {
+		t.Fatalf("unexpected check counts: %+v", report)
+	}
+	if report.Latency == nil {
+		t.Fatal("expected latency percentiles")
+	}
+	if report.Latency.P50 != 50 || report.Latency.P95 != 95 || report.Latency.P99 != 99 {
+		t.Fatalf("unexpected percentiles: %+v", *report.Latency)
+	}
+
+	legacy := buildSLAReport("a", 7, logstore.CheckStats{Checks: 1, UpChecks: 1}, nil)
+	if legacy.Latency != nil {
+		t.Fatalf("expected no latency for rows without data, got %+v", *legacy.Latency)
+	}
+	if text := formatLatencyPercentiles(legacy.Latency); text != "N/A" {
+		t.Fatalf("expected N/A, got %q", text)
+	}
+}
+
+func TestAutoDisableAfterProlongedDowntime(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Monitoring.AutoDisable = true
+	cfg.Monitoring.AutoDisableAfterHours = 1
+	svc := New(cfg, store, &fakeNotifier{})
+	if err := svc.UpsertTarget("broken", "10.255.0.1", 22); err != nil {
+		t.Fatalf("upsert target: %v", err)
+	}
+	target := svc.engine.targetByName["broken"]
+	svc.applyStatus(target, false)
+
+	if ev := svc.engine.autoDisable(target, time.Now().UTC().Add(30*time.Minute)); ev != nil {
+		t.Fatalf("expected no auto-disable before threshold, got %+v", ev)
+	}
+	ev := svc.engine.autoDisable(target, time.Now().UTC().Add(2*time.Hour))
+	if ev == nil || ev.Kind != "AUTO_DISABLED" {
+		t.Fatalf("expected AUTO_DISABLED event, got %+v", ev)
+	}
+	targets, err := store.ListTargets()
+	if err != nil {
+		t.Fatalf("list targets: %v", err)
+	}
+	if len(targets) != 0 {
+		t.Fatalf("expected target to be disabled, got %+v", targets)
+	}
+}
+
+func TestStorageHealthAlertsOnceAndClears(t *testing.T) {
+	t.Parallel()
+
+	health := newStorageHealth(3, 10*time.Minute)
+	now := time.Now().UTC()
+	writeErr := errors.New("database is locked")
+
+	for i := 0; i < 2; i++ {
+		health.record(writeErr)
+	}
+	if ev := health.event(now); ev != nil {
+		t.Fatalf("expected no alert below threshold, got %+v", ev)
+	}
+	health.record(writeErr)
+	ev := health.event(now)
+	if ev == nil || ev.Kind != "STORAGE_DEGRADED" {
+		t.Fatalf("expected STORAGE_DEGRADED, got %+v", ev)
+	}
+	health.record(writeErr)
+	if ev := health.event(now.Add(time.Second)); ev != nil {
+		t.Fatalf("expected a single degraded alert, got %+v", ev)
+	}
+
+	health.record(nil)
+	if ev := health.event(now.Add(2 * time.Second)); ev == nil || ev.Kind != "STORAGE_RECOVERED" {
+		t.Fatalf("expected STORAGE_RECOVERED, got %+v", ev)
+	}
+
+	for i := 0; i < 3; i++ {
+		health.record(writeErr)
+	}
+	if ev := health.event(now.Add(time.Minute)); ev != nil {
+		t.Fatalf("expected degraded alert to respect cooldown, got %+v", ev)
+	}
+	if ev := health.event(now.Add(11 * time.Minute)); ev == nil {
+		t.Fatal("expected degraded alert after cooldown")
+	}
+
+	message := formatAlertGroup([]alertEvent{*ev})
+	if !strings.Contains(message, "STORAGE_DEGRADED") || strings.Contains(message, "targets:") {
+		t.Fatalf("unexpected storage alert message: %q", message)
+	}
+}
+
+func TestTargetStatusTextSingleTarget(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	svc := New(testConfig(), store, &fakeNotifier{})
+	if err := svc.UpsertTarget("web", "10.0.0.1", 443); err != nil {
+		t.Fatalf("upsert web: %v", err)
+	}
+	if err := svc.UpsertTarget("db", "10.0.0.2", 5432); err != nil {
+		t.Fatalf("upsert db: %v", err)
+	}
+	svc.engine.applyResult(svc.engine.targetByName["web"], checkResult{Up: true, Latency: 12 * time.Millisecond})
+
+	text := svc.targetStatusText("web")
+	if !strings.Contains(text, "<b>web</b>") || strings.Contains(text, "db") {
+		t.Fatalf("expected only web block, got %q", text)
+	}
+	if !strings.Contains(text, "state: <b>UP</b>") || !strings.Contains(text, "last: <code>12.0ms</code>") {
+		t.Fatalf("expected state and latency, got %q", text)
+	}
+	if text := svc.targetStatusText("missing"); !strings.Contains(text, "Track not found") {
+		t.Fatalf("expected not found message, got %q", text)
+	}
+}
+
+func testConfig() config.Config {
+	var cfg config.Config
+	cfg.Bot.Token = "token"
+	cfg.Bot.ChatID = 1
+	cfg.Monitoring.IntervalSeconds = 1
+	cfg.Monitoring.ConnectTimeoutSeconds = 1
+	cfg.Targets = []config.Target{
+		{
+			Name:    "test-track",
+			Address: "127.0.0.1",
+			Port:    1,
+		},
+	}
+	return cfg
+}
+
+func TestPingDoesNotChangeTargetState(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Targets[0].Port = listener.Addr().(*net.TCPAddr).Port
+	svc := New(cfg, store, &fakeNotifier{})
+
+	text := svc.pingText(context.Background(), []string{"test-track", "50"})
+	if !strings.Contains(text, "connected: 10/10") || !strings.Contains(text, "min/avg/max:") {
+		t.Fatalf("expected capped successful ping, got %q", text)
+	}
+	if target := svc.targetByName["test-track"]; target.LastStatus != nil || !target.LastChecked.IsZero() {
+		t.Fatalf("ping must not touch target state, got %+v", target)
+	}
+	if rows := store.ReadLastDays("test-track", 1, 10); len(rows) != 0 {
+		t.Fatalf("ping must not write log rows, got %d", len(rows))
+	}
+	if text := svc.pingText(context.Background(), []string{"missing"}); !strings.Contains(text, "Track not found") {
+		t.Fatalf("expected not found, got %q", text)
+	}
+}
+
+func TestStatusTextExplainsEmptyTargetList(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Targets = nil
+	svc := New(cfg, store, &fakeNotifier{})
+
+	text := svc.commands.statusText()
+	if !strings.Contains(text, "No tracks configured yet") || !strings.Contains(text, "/add") || !strings.Contains(text, "dashboard") {
+		t.Fatalf("expected empty-state hint, got %q", text)
+	}
+}
+
+func TestDownAlertsLinkToDashboard(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	notifier := &fakeNotifier{}
+	svc := New(testConfig(), store, notifier)
+	svc.SetDashboardLinkGenerator(func(path string) (string, error) {
+		return "https://dash.example/auth/link?next=" + url.QueryEscape(path) + "&sig=x", nil
+	})
+
+	now := time.Now().UTC()
+	svc.sendAlertBatch(context.Background(), []alertEvent{
+		{Kind: "DOWN", Target: "web&api", Address: "10.0.0.1", Port: 443, Reason: "state-change", Occurred: now},
+	})
+	svc.sendAlertBatch(context.Background(), []alertEvent{
+		{Kind: "DOWN", Target: "a", Address: "10.0.0.2", Port: 22, Reason: "initial-check", Occurred: now},
+		{Kind: "DOWN", Target: "b", Address: "10.0.0.3", Port: 22, Reason: "initial-check", Occurred: now},
+		{Kind: "RECOVERED", Target: "c", Address: "10.0.0.4", Port: 22, Reason: "state-change", Occurred: now},
+	})
+
+	if len(notifier.defaults) != 3 {
+		t.Fatalf("expected three alert messages, got %d", len(notifier.defaults))
+	}
+	if !strings.HasSuffix(notifier.defaults[0], `<a href="https://dash.example/auth/link?next=%2F%3Ftrack%3Dweb%2526api&amp;sig=x">View on dashboard</a>`) {
+		t.Fatalf("expected per-target dashboard link, got %q", notifier.defaults[0])
+	}
+	if !strings.HasSuffix(notifier.defaults[1], `<a href="https://dash.example/auth/link?next=%2F%3Fstatus%3DDOWN&amp;sig=x">View on dashboard</a>`) {
+		t.Fatalf("expected filtered dashboard link, got %q", notifier.defaults[1])
+	}
+	if strings.Contains(notifier.defaults[2], "View on dashboard") {
+		t.Fatalf("RECOVERED alerts must not link to the dashboard, got %q", notifier.defaults[2])
+	}
+}
+
+func TestLogLevelCommandRaisesAndReverts(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	svc := New(testConfig(), store, &fakeNotifier{})
+	level := new(slog.LevelVar)
+	svc.SetLogLevelVar(level)
+
+	if text := svc.commands.logLevelText([]string{"verbose"}); !strings.HasPrefix(text, "Usage:") {
+		t.Fatalf("expected usage for unknown level, got %q", text)
+	}
+	text := svc.commands.logLevelText([]string{"debug", "50ms"})
+	if level.Level() != slog.LevelDebug || !strings.Contains(text, "DEBUG") {
+		t.Fatalf("expected debug level, got %s (%q)", level.Level(), text)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for level.Level() != slog.LevelInfo && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if level.Level() != slog.LevelInfo {
+		t.Fatalf("expected level to revert to INFO, got %s", level.Level())
+	}
+}
+
+func TestLogLevelExpiryIgnoresReplacedOverride(t *testing.T) {
+	t.Parallel()
+
+	level := new(slog.LevelVar)
+	control := newLogLevelControl(level)
+	now := time.Now().UTC()
+	control.set(slog.LevelDebug, time.Hour, now)
+	stale := control.generation
+	control.set(slog.LevelWarn, time.Hour, now)
+
+	// The first timer fired while the second set held the lock.
+	control.expire(stale)
+	if got, until := control.current(); got != slog.LevelWarn || until.IsZero() {
+		t.Fatalf("expected the newer WARN override to stay, got %s until %v", got, until)
+	}
+	control.reset()
+	control.expire(stale + 1)
+	if got, until := control.current(); got != slog.LevelInfo || !until.IsZero() {
+		t.Fatalf("expected INFO after reset, got %s until %v", got, until)
+	}
+}
+
+func TestRecentMessagesListsChangesAcrossTargets(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	svc := New(testConfig(), store, &fakeNotifier{})
+	for _, row := range []struct {
+		target string
+		status bool
+		reason string
+	}{
+		{"web", true, "INIT"},
+		{"web", true, "POLL"},
+		{"db", false, "INIT"},
+		{"web", false, "CHANGE 1/3"},
+	} {
+		if err := store.Append(row.target, "10.0.0.1", 80, row.status, row.reason); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	changes := store.RecentChanges(10)
+	if len(changes) != 3 {
+		t.Fatalf("expected only INIT/CHANGE rows, got %+v", changes)
+	}
+	text := strings.Join(svc.recentMessages([]string{"2"}), "\n")
+	if !strings.Contains(text, "latest 2") || strings.Contains(text, "POLL") {
+		t.Fatalf("unexpected recent output: %q", text)
+	}
+	if text := svc.recentMessages([]string{"x"}); text[0] != "Usage: /recent [count]" {
+		t.Fatalf("expected usage, got %q", text)
+	}
+}
+
+func TestFormatAlertGroupShowsFailureStreak(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	single := formatAlertGroup([]alertEvent{
+		{Kind: "DOWN", Target: "a", Address: "10.0.0.1", Port: 80, Reason: "state-change", Occurred: now, FailedChecks: 3, FailingSince: now.Add(-10 * time.Second)},
+	})
+	if !strings.Contains(single, "failing: <code>down for 3 consecutive checks over 10s</code>") {
+		t.Fatalf("expected failure streak line, got %q", single)
+	}
+
+	grouped := formatAlertGroup([]alertEvent{
+		{Kind: "DOWN", Target: "a", Address: "10.0.0.1", Port: 80, Reason: "state-change", Occurred: now, FailedChecks: 3, FailingSince: now.Add(-10 * time.Second)},
+		{Kind: "DOWN", Target: "b", Address: "10.0.0.2", Port: 443, Reason: "state-change", Occurred: now, FailedChecks: 1, FailingSince: now},
+	})
+	if !strings.Contains(grouped, "(<code>10.0.0.1:80</code>) <code>3x/10s</code>") || strings.Contains(grouped, "1x/") {
+		t.Fatalf("expected compact streak only for non-trivial targets, got %q", grouped)
+	}
+}
+
+func TestChangesTextListsRecentlyChangedTargets(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	svc := New(testConfig(), store, &fakeNotifier{})
+	if text := svc.changesText(nil); !strings.Contains(text, "No state changes") {
+		t.Fatalf("expected empty changes, got %q", text)
+	}
+
+	svc.applyStatus(svc.targets[0], false)
+	text := svc.changesText([]string{"5"})
+	if !strings.Contains(text, "last 5 min") || !strings.Contains(text, "<b>test-track</b> -&gt; <b>DOWN</b>") {
+		t.Fatalf("unexpected changes output: %q", text)
+	}
+	if text := svc.changesText([]string{"soon"}); text != "Usage: /changes [minutes]" {
+		t.Fatalf("expected usage, got %q", text)
+	}
+}
+
+func TestGraphTextRendersTimeline(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	svc := New(testConfig(), store, &fakeNotifier{})
+	if err := store.Append("test-track", "127.0.0.1", 1, false, "INIT"); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	text := svc.graphText([]string{"test-track", "6"})
+	if !strings.Contains(text, "last 6h, 10m0s per column") || !strings.HasSuffix(strings.Split(text, "\n")[1], "░") {
+		t.Fatalf("unexpected graph output: %q", text)
+	}
+	if text := svc.graphText([]string{"missing"}); !strings.Contains(text, "Track not found") {
+		t.Fatalf("expected not found, got %q", text)
+	}
+	if text := svc.graphText(nil); !strings.HasPrefix(text, "Usage: /graph") {
+		t.Fatalf("expected usage, got %q", text)
+	}
+}
+
+type recordingSink struct {
+	groups []AlertGroup
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) SendAlertGroup(_ context.Context, group AlertGroup) error {
+	s.groups = append(s.groups, group)
+	return nil
+}
+
+func TestAlertSinksReceiveEveryGroup(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	notifier := &fakeNotifier{}
+	svc := New(testConfig(), store, notifier)
+	sink := &recordingSink{}
+	svc.AddAlertSink(sink)
+
+	now := time.Now().UTC()
+	svc.sendAlertBatch(context.Background(), []alertEvent{
+		{Kind: "DOWN", Target: "test-track", Address: "127.0.0.1", Port: 1, Reason: "state-change", Occurred: now},
+	})
+	svc.sendAlertBatch(context.Background(), []alertEvent{
+		{Kind: "RECOVERED", Target: "test-track", Address: "127.0.0.1", Port: 1, Reason: "state-change", Occurred: now.Add(time.Second)},
+	})
+
+	if len(notifier.edits) != 1 {
+		t.Fatalf("expected Telegram fast recovery edit, got %d", len(notifier.edits))
+	}
+	if len(sink.groups) != 2 || sink.groups[0].Kind != "DOWN" || sink.groups[1].Kind != "RECOVERED" {
+		t.Fatalf("expected sink to get DOWN and RECOVERED, got %+v", sink.groups)
+	}
+	if sink.groups[0].Targets[0].Name != "test-track" {
+		t.Fatalf("unexpected sink targets: %+v", sink.groups[0].Targets)
+	}
+}
+
+// reentrantSink reads alert state while it sends, which deadlocks if sinks
+// run under the alert lock.
+type reentrantSink struct {
+	alerts *AlertManager
+	sent   int
+}
+
+func (s *reentrantSink) Name() string { return "reentrant" }
+
+func (s *reentrantSink) SendAlertGroup(_ context.Context, _ AlertGroup) error {
+	s.alerts.PausedUntil(time.Now())
+	s.sent++
+	return nil
+}
+
+func TestAlertSinksRunAfterTelegramWithoutLock(t *testing.T) {
+	t.Parallel()
+
+	notifier := &fakeNotifier{}
+	alerts := NewAlertManager(notifier, AlertOptions{})
+	sink := &reentrantSink{alerts: alerts}
+	alerts.AddSink(sink)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		alerts.SendBatch(context.Background(), []alertEvent{
+			{Kind: "DOWN", Target: "a", Address: "10.0.0.1", Port: 80, Reason: "state-change", Occurred: time.Now().UTC()},
+		})
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("sink ran under the alert lock")
+	}
+	if sink.sent != 1 || len(notifier.defaults) != 1 {
+		t.Fatalf("expected one Telegram message and one sink call, got %q and %d", notifier.defaults, sink.sent)
+	}
+}
+
+func TestOrphanRecoveryWithoutSentDown(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	recovered := func() []alertEvent {
+		return []alertEvent{
+			{Kind: "RECOVERED", Target: "a", Address: "10.0.0.1", Port: 80, Reason: "state-change", Occurred: now},
+		}
+	}
+
+	relabel := &fakeNotifier{}
+	NewAlertManager(relabel, AlertOptions{OrphanRecovery: "relabel"}).SendBatch(context.Background(), recovered())
+	if len(relabel.defaults) != 1 || !strings.Contains(relabel.defaults[0], "<b>NOW_UP</b>") || !strings.Contains(relabel.defaults[0], "no-down-alert") {
+		t.Fatalf("expected relabeled recovery, got %q", relabel.defaults)
+	}
+
+	suppress := &fakeNotifier{}
+	alerts := NewAlertManager(suppress, AlertOptions{OrphanRecovery: "suppress"})
+	alerts.SendBatch(context.Background(), recovered())
+	if len(suppress.defaults) != 0 {
+		t.Fatalf("expected orphan recovery to be suppressed, got %q", suppress.defaults)
+	}
+	alerts.SendBatch(context.Background(), []alertEvent{
+		{Kind: "DOWN", Target: "a", Address: "10.0.0.1", Port: 80, Reason: "initial-check", Occurred: now},
+	})
+	alerts.SendBatch(context.Background(), []alertEvent{
+		{Kind: "RECOVERED", Target: "a", Address: "10.0.0.1", Port: 80, Reason: "state-change", Occurred: now.Add(time.Minute)},
+	})
+	if len(suppress.defaults) != 2 || !strings.Contains(suppress.defaults[1], "<b>RECOVERED</b>") {
+		t.Fatalf("expected recovery after a sent DOWN, got %q", suppress.defaults)
+	}
+}
+
+func TestThresholdDelaysStateChangesAndPersists(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	svc := New(testConfig(), store, &fakeNotifier{})
+	if err := svc.UpsertTarget("web", "10.0.0.1", 443); err != nil {
+		t.Fatalf("upsert web: %v", err)
+	}
+
+	if text := svc.thresholdText([]string{"web", "fail", "3"}); !strings.Contains(text, "fail: <code>3</code>") || !strings.Contains(text, "success: <code>1</code>") {
+		t.Fatalf("unexpected threshold reply: %q", text)
+	}
+	if text := svc.thresholdText([]string{"web", "fail", "99"}); !strings.Contains(text, "between 1 and 20") {
+		t.Fatalf("expected range error, got %q", text)
+	}
+	if text := svc.thresholdText([]string{"missing", "fail", "2"}); !strings.Contains(text, "Track not found") {
+		t.Fatalf("expected not found, got %q", text)
+	}
+	rows, err := store.ListTargets()
+	if err != nil || len(rows) != 1 || rows[0].FailThreshold != 3 {
+		t.Fatalf("expected persisted threshold, got %+v err=%v", rows, err)
+	}
+
+	target := svc.engine.targetByName["web"]
+	svc.applyStatus(target, true)
+	for i := 0; i < 2; i++ {
+		if ev := svc.applyStatus(target, false); ev != nil {
+			t.Fatalf("expected no DOWN before threshold, got %+v", ev)
+		}
+	}
+	if ev := svc.applyStatus(target, false); ev == nil || ev.Kind != "DOWN" {
+		t.Fatalf("expected DOWN on third failure, got %+v", ev)
+	}
+
+	// A dashboard upsert keeps the stored thresholds.
+	if err := svc.UpsertTarget("web", "10.0.0.2", 443); err != nil {
+		t.Fatalf("upsert web: %v", err)
+	}
+	if got := svc.engine.targetByName["web"].FailThreshold; got != 3 {
+		t.Fatalf("expected threshold to survive upsert, got %d", got)
+	}
+}
+
+func TestConfigFailureThresholdFallsBackToMonitoringDefault(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Monitoring.FailureThreshold = 2
+	cfg.Targets = []config.Target{{Name: "db", Address: "10.0.0.5", Port: 5432, FailureThreshold: 3}}
+	svc := New(cfg, store, &fakeNotifier{})
+	for _, item := range []struct {
+		name string
+		port int
+	}{{"db", 5432}, {"web", 443}} {
+		if err := svc.UpsertTarget(item.name, "10.0.0.5", item.port); err != nil {
+			t.Fatalf("upsert %s: %v", item.name, err)
+		}
+	}
+
+	for name, want := range map[string]int{"db": 3, "web": 2} {
+		target := svc.engine.targetByName[name]
+		svc.applyStatus(target, true)
+		for i := 1; i < want; i++ {
+			if ev := svc.applyStatus(target, false); ev != nil {
+				t.Fatalf("%s: expected no DOWN after %d failures, got %+v", name, i, ev)
+			}
+		}
+		if ev := svc.applyStatus(target, false); ev == nil || ev.Kind != "DOWN" {
+			t.Fatalf("%s: expected DOWN after %d failures, got %+v", name, want, ev)
+		}
+		// A success resets the streak.
+		svc.applyStatus(target, true)
+		if target.failStreak != 0 {
+			t.Fatalf("%s: expected fail streak reset, got %d", name, target.failStreak)
+		}
+	}
+}
+
+func TestMuteSurvivesRestartAndMarksStatus(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	svc := New(cfg, store, &fakeNotifier{})
+	if err := svc.UpsertTarget("test-track", "127.0.0.1", 1); err != nil {
+		t.Fatalf("upsert target: %v", err)
+	}
+	until, err := svc.Mute("test-track", time.Now().Add(30*time.Minute))
+	if err != nil {
+		t.Fatalf("mute: %v", err)
+	}
+
+	restarted := New(cfg, store, &fakeNotifier{})
+	restarted.engine.syncTargets()
+	if got := restarted.engine.targetByName["test-track"].MutedUntil; !got.Equal(until) {
+		t.Fatalf("expected mute until %s after restart, got %s", until, got)
+	}
+	if text := restarted.statusText(); !strings.Contains(text, "<b>test-track</b> (muted)") {
+		t.Fatalf("expected muted marker, got %q", text)
+	}
+
+	if err := restarted.Unmute("test-track"); err != nil {
+		t.Fatalf("unmute: %v", err)
+	}
+	restarted.engine.syncTargets()
+	if text := restarted.statusText(); strings.Contains(text, "(muted)") {
+		t.Fatalf("expected no muted marker after unmute, got %q", text)
+	}
+}
+
+func TestMuteCommandsTakeMinutesOrDuration(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	notifier := &fakeNotifier{}
+	svc := New(testConfig(), store, notifier)
+	if err := svc.UpsertTarget("test-track", "127.0.0.1", 1); err != nil {
+		t.Fatalf("upsert target: %v", err)
+	}
+	command := func(text string) string {
+		notifier.replies = nil
+		svc.HandleUpdate(context.Background(), &models.Update{Message: &models.Message{Text: text, Chat: models.Chat{ID: 1}}})
+		if len(notifier.replies) != 1 {
+			t.Fatalf("%s: expected one reply, got %q", text, notifier.replies)
+		}
+		return notifier.replies[0]
+	}
+
+	if reply := command("/mute test-track soon"); !strings.HasPrefix(reply, "Usage: /mute") {
+		t.Fatalf("expected usage, got %q", reply)
+	}
+	for arg, want := range map[string]time.Duration{"90": 90 * time.Minute, "2h": 2 * time.Hour, "": time.Hour} {
+		before := time.Now().UTC()
+		if reply := command(strings.TrimSpace("/mute test-track " + arg)); !strings.Contains(reply, "muted until") {
+			t.Fatalf("/mute %s: unexpected reply %q", arg, reply)
+		}
+		got := svc.engine.targetByName["test-track"].MutedUntil.Sub(before)
+		if got < want-time.Second || got > want+time.Second {
+			t.Fatalf("/mute %s: expected a mute of %s, got %s", arg, want, got)
+		}
+	}
+	if reply := command("/unmute test-track"); !strings.Contains(reply, "unmuted") || !svc.engine.targetByName["test-track"].MutedUntil.IsZero() {
+		t.Fatalf("expected the mute to be lifted, got %q", reply)
+	}
+	if reply := command("/mute missing 5"); !strings.HasPrefix(reply, "Track not found") {
+		t.Fatalf("expected not found, got %q", reply)
+	}
+
+	failing := NewCommandHandler(1, failingMuteSource{svc.engine}, notifier, 0)
+	for _, command := range []string{"mute", "unmute"} {
+		if reply := failing.muteText(command, []string{"test-track"}); !strings.Contains(reply, "Mute not changed: disk I/O error") {
+			t.Fatalf("/%s: expected the storage error, got %q", command, reply)
+		}
+	}
+}
+
+// failingMuteSource fails every mute change as a broken store would.
+type failingMuteSource struct {
+	*MonitorEngine
+}
+
+func (failingMuteSource) Mute(string, time.Time) (time.Time, error) {
+	return time.Time{}, errors.New("disk I/O error")
+}
+
+func (failingMuteSource) Unmute(string) error {
+	return errors.New("disk I/O error")
+}
+
+func TestTagsAreStoredAndFilterStatus(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Targets = []config.Target{
+		{Name: "api", Address: "127.0.0.1", Port: 1, Tags: []string{"prod", "web"}},
+		{Name: "db", Address: "127.0.0.1", Port: 2, Tags: []string{"prod"}},
+		{Name: "lab", Address: "127.0.0.1", Port: 3},
+	}
+	for _, item := range cfg.Targets {
+		if err := store.UpsertTarget(item.Name, item.Address, item.Port); err != nil {
+			t.Fatalf("upsert target: %v", err)
+		}
+	}
+	svc := New(cfg, store, &fakeNotifier{})
+	svc.engine.storeConfigTags()
+	svc.engine.syncTargets()
+	svc.applyStatus(svc.engine.targetByName["api"], true)
+	svc.applyStatus(svc.engine.targetByName["db"], false)
+
+	rows, err := store.ListTargets()
+	if err != nil || len(rows) != 3 || !slices.Equal(rows[0].Tags, []string{"prod", "web"}) || rows[2].Tags != nil {
+		t.Fatalf("expected config tags in the store, got %+v (%v)", rows, err)
+	}
+
+	prod := svc.Snapshot().WithTag("PROD")
+	if prod.Total != 2 || prod.Up != 1 || prod.Down != 1 || prod.Unknown != 0 {
+		t.Fatalf("unexpected prod counters: %+v", prod)
+	}
+	if empty := svc.Snapshot().WithTag("missing"); empty.Total != 0 || empty.Targets == nil {
+		t.Fatalf("expected an empty non-nil target list, got %+v", empty)
+	}
+
+	text := svc.targetStatusText("Prod")
+	if !strings.Contains(text, "tag: <b>prod</b>") || !strings.Contains(text, "tracks: 2 | up: 1 | down: 1") || strings.Contains(text, "lab") {
+		t.Fatalf("unexpected tag status: %q", text)
+	}
+	if text := svc.targetStatusText("api"); !strings.Contains(text, "tags: prod, web") {
+		t.Fatalf("expected tags in target status, got %q", text)
+	}
+
+	cfg.Targets[0].Tags = nil
+	if err := svc.Reload(cfg); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if tagged := svc.Snapshot().WithTag("web"); tagged.Total != 0 {
+		t.Fatalf("expected removed config tags to be cleared, got %+v", tagged)
+	}
+}
+
+func TestAddAndRemoveTargetCommands(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	svc := New(testConfig(), store, &fakeNotifier{})
+
+	for _, tc := range []struct {
+		chatID  int64
+		command string
+		args    []string
+		want    string
+	}{
+		{1, "add", []string{"db"}, "Usage: /add"},
+		{1, "add", []string{"db", "10.0.0.5", "http"}, "Usage: /add"},
+		{1, "add", []string{"db", "10.0.0.5", "70000"}, "Target not saved: target port must be between 1 and 65535"},
+		{2, "add", []string{"db", "10.0.0.5", "5432"}, "not available in this chat"},
+		{1, "add", []string{"db", "10.0.0.5", "5432"}, "Target <b>db</b> added: <code>10.0.0.5:5432</code>"},
+		{1, "add", []string{"db", "10.0.0.6", "5432"}, "Target <b>db</b> updated: <code>10.0.0.6:5432</code>"},
+		{1, "remove", []string{"missing"}, "Track not found"},
+		{1, "remove", []string{"db"}, "Target <b>db</b> removed."},
+	} {
+		if got := svc.manageTargetText(tc.chatID, tc.command, tc.args); !strings.Contains(got, tc.want) {
+			t.Fatalf("/%s %v: expected %q, got %q", tc.command, tc.args, tc.want, got)
+		}
+		if tc.want == "Target <b>db</b> updated: <code>10.0.0.6:5432</code>" {
+			if target := svc.engine.targetByName["db"]; target == nil || target.Address != "10.0.0.6" {
+				t.Fatalf("expected the target to be updated in place, got %+v", target)
+			}
+		}
+	}
+	if _, ok := svc.engine.targetByName["db"]; ok {
+		t.Fatal("expected db to be removed")
+	}
+}
+
+func TestReloadAppliesConfigAndKeepsTargetState(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Targets = append(cfg.Targets, config.Target{Name: "old", Address: "127.0.0.1", Port: 2})
+	for _, target := range cfg.Targets {
+		if err := store.UpsertTarget(target.Name, target.Address, target.Port); err != nil {
+			t.Fatalf("upsert %s: %v", target.Name, err)
+		}
+	}
+	svc := New(cfg, store, &fakeNotifier{})
+	if err := svc.UpsertTarget("manual", "127.0.0.1", 4); err != nil {
+		t.Fatalf("upsert manual: %v", err)
+	}
+	if _, err := svc.UpdateSettings(map[string]int{"connect_timeout_seconds": 7}); err != nil {
+		t.Fatalf("update settings: %v", err)
+	}
+	svc.applyStatus(svc.engine.targetByName["test-track"], false)
+
+	next := testConfig()
+	next.Monitoring.IntervalSeconds = 30
+	next.Monitoring.ConnectTimeoutSeconds = 3
+	next.Monitoring.MaxParallelChecks = 4
+	next.Targets[0].Mention = "@oncall"
+	next.Targets = append(next.Targets, config.Target{Name: "new", Address: "127.0.0.1", Port: 3})
+	if err := svc.Reload(next); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+
+	settings := svc.Settings()
+	if settings.IntervalSeconds != 30 || settings.MaxParallelChecks != 4 || settings.ConnectTimeoutSeconds != 7 {
+		t.Fatalf("expected reloaded defaults under stored settings, got %+v", settings)
+	}
+	byName := svc.engine.targetByName
+	if byName["old"] != nil || byName["new"] == nil || byName["manual"] == nil {
+		t.Fatalf("expected old removed, new added and manual kept, got %v", svc.TargetNames())
+	}
+	kept := byName["test-track"]
+	if kept.Mention != "@oncall" || kept.LastStatus == nil || *kept.LastStatus {
+		t.Fatalf("expected reloaded options with the state kept, got %+v", kept)
+	}
+}
+
+func TestTargetsFileReconcilesStore(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.NewSQLite(logstore.SQLiteOptions{Path: filepath.Join(t.TempDir(), "trackway.db")})
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "targets.yaml")
+	write := func(targets []config.Target) {
+		data, err := EncodeTargets(targets, "yaml")
+		if err != nil {
+			t.Fatalf("encode targets: %v", err)
+		}
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatalf("write targets file: %v", err)
+		}
+	}
+	write([]config.Target{
+		{Name: "inv-a", Address: "127.0.0.1", Port: 10, Tags: []string{"inventory"}},
+		{Name: "inv-b", Address: "127.0.0.1", Port: 11},
+	})
+
+	cfg := testConfig()
+	cfg.TargetSource = config.TargetSource{Source: "file", Path: path, ReloadIntervalSeconds: 60}
+	svc := New(cfg, store, &fakeNotifier{})
+	svc.engine.reloadTargetsFile()
+	if got := svc.TargetNames(); len(got) != 2 || svc.engine.targetByName["inv-a"] == nil || !slices.Equal(svc.engine.targetByName["inv-a"].Tags, []string{"inventory"}) {
+		t.Fatalf("expected file targets with their tags, got %v", got)
+	}
+	svc.applyStatus(svc.engine.targetByName["inv-a"], false)
+
+	write([]config.Target{
+		{Name: "inv-a", Address: "127.0.0.1", Port: 10},
+		{Name: "inv-c", Address: "127.0.0.1", Port: 12},
+	})
+	svc.engine.reloadTargetsFile()
+	byName := svc.engine.targetByName
+	if byName["inv-b"] != nil || byName["inv-a"] == nil || byName["inv-c"] == nil {
+		t.Fatalf("expected inv-b dropped and inv-c added, got %v", svc.TargetNames())
+	}
+	if status := byName["inv-a"].LastStatus; status == nil || *status {
+		t.Fatalf("expected inv-a to keep its state, got %+v", byName["inv-a"])
+	}
+	rows, err := store.ListAllTargets()
+	if err != nil {
+		t.Fatalf("list targets: %v", err)
+	}
+	for _, row := range rows {
+		if row.Name == "inv-b" && row.Enabled {
+			t.Fatal("expected inv-b to be disabled, not kept enabled")
+		}
+	}
+	if !slices.ContainsFunc(rows, func(row logstore.Target) bool { return row.Name == "inv-b" }) {
+		t.Fatal("expected inv-b to stay in the store disabled")
+	}
+
+	if err := os.WriteFile(path, []byte("targets:\n  - name: [broken\n"), 0o600); err != nil {
+		t.Fatalf("write broken file: %v", err)
+	}
+	svc.engine.reloadTargetsFile()
+	if svc.engine.targetByName["inv-a"] == nil {
+		t.Fatalf("expected the last good targets after a malformed reload, got %v", svc.TargetNames())
+	}
+
+	if err := svc.Reload(cfg); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if svc.engine.targetByName["inv-a"] == nil {
+		t.Fatalf("expected a config reload to keep file targets, got %v", svc.TargetNames())
+	}
+}
+
+type documentNotifier struct {
+	fakeNotifier
+	chatID   int64
+	filename string
+	data     string
+	caption  string
+}
+
+func (d *documentNotifier) SendDocument(_ context.Context, chatID int64, filename string, data []byte, caption string) error {
+	d.chatID, d.filename, d.data, d.caption = chatID, filename, string(data), caption
+	return nil
+}
+
+func TestExportCommandUploadsLogsAsCSV(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	notifier := &documentNotifier{}
+	svc := New(testConfig(), store, notifier)
+	if err := svc.UpsertTarget("test-track", "127.0.0.1", 1); err != nil {
+		t.Fatalf("upsert target: %v", err)
+	}
+	target := svc.engine.targetByName["test-track"]
+	svc.applyStatus(target, true)
+	svc.applyStatus(target, false)
+
+	send := func(text string) {
+		svc.HandleUpdate(context.Background(), &models.Update{Message: &models.Message{Text: text, Chat: models.Chat{ID: 1}}})
+	}
+	send("/export test-track 3")
+	if notifier.chatID != 1 || notifier.filename != "test-track-logs.csv" || !strings.Contains(notifier.caption, "last 3 days, 2 rows") {
+		t.Fatalf("unexpected upload: chat=%d file=%q caption=%q", notifier.chatID, notifier.filename, notifier.caption)
+	}
+	lines := strings.Split(strings.TrimSpace(notifier.data), "\n")
+	if len(lines) != 3 || lines[0] != "timestamp,status,endpoint,reason" || !strings.HasSuffix(lines[2], ",DOWN,127.0.0.1:1,CHANGE") {
+		t.Fatalf("unexpected csv:\n%s", notifier.data)
+	}
+
+	for range maxExportRows {
+		if err := store.Append("test-track", "127.0.0.1", 1, true, "POLL"); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	send("/export test-track 3")
+	lines = strings.Split(strings.TrimSpace(notifier.data), "\n")
+	if !strings.Contains(notifier.caption, "truncated") || len(lines) != maxExportRows+1 || !strings.HasSuffix(lines[len(lines)-1], ",UP,127.0.0.1:1,POLL") {
+		t.Fatalf("expected the newest rows with a truncation note, got caption %q and %d lines", notifier.caption, len(lines))
+	}
+
+	send("/export missing")
+	send("/export")
+	if len(notifier.replies) != 2 || !strings.Contains(notifier.replies[0], "Track not found") || !strings.Contains(notifier.replies[1], "Usage: /export") {
+		t.Fatalf("unexpected replies: %v", notifier.replies)
+	}
+}
+
+type photoNotifier struct {
+	fakeNotifier
+	filename string
+	data     []byte
+	caption  string
+}
+
+func (p *photoNotifier) SendPhoto(_ context.Context, _ int64, filename string, data []byte, caption string) error {
+	p.filename, p.data, p.caption = filename, data, caption
+	return nil
+}
+
+func TestGraphCommandSendsTimelineImage(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Graph.Width, cfg.Graph.Height = 200, 40
+	notifier := &photoNotifier{}
+	svc := New(cfg, store, notifier)
+	if err := store.Append("test-track", "127.0.0.1", 1, false, "INIT"); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	send := func(text string) {
+		svc.HandleUpdate(context.Background(), &models.Update{Message: &models.Message{Text: text, Chat: models.Chat{ID: 1}}})
+	}
+	send("/graph test-track 2d")
+	if notifier.filename != "test-track-graph.png" || !strings.Contains(notifier.caption, "last 2d") {
+		t.Fatalf("unexpected photo: file=%q caption=%q", notifier.filename, notifier.caption)
+	}
+	img, err := png.Decode(bytes.NewReader(notifier.data))
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != 200 || size.Y != 40 {
+		t.Fatalf("unexpected image size %v", size)
+	}
+	if got := img.At(0, 0); got != graphPalette[bucketUnknown] {
+		t.Fatalf("expected gray without data, got %v", got)
+	}
+	if got := img.At(199, 39); got != graphPalette[bucketDown] {
+		t.Fatalf("expected red for the DOWN row, got %v", got)
+	}
+
+	send("/graph test-track 0d")
+	send("/graph missing")
+	if len(notifier.replies) != 2 || !strings.HasPrefix(notifier.replies[0], "Usage: /graph") || !strings.Contains(notifier.replies[1], "Track not found") {
+		t.Fatalf("unexpected replies: %v", notifier.replies)
+	}
+
+	text := &fakeNotifier{}
+	svc = New(cfg, store, text)
+	svc.HandleUpdate(context.Background(), &models.Update{Message: &models.Message{Text: "/graph test-track 6", Chat: models.Chat{ID: 1}}})
+	if len(text.replies) != 1 || !strings.Contains(text.replies[0], "last 6h") {
+		t.Fatalf("expected the text timeline without photo support, got %v", text.replies)
+	}
+}
+
+type ackNotifier struct {
+	fakeNotifier
+	buttons []string
+	answers []string
+}
+
+func (n *ackNotifier) SendDefaultHTMLWithButton(ctx context.Context, _ int, text, button, data string) (int, error) {
+	n.buttons = append(n.buttons, button+":"+data)
+	return n.SendDefaultHTMLWithID(ctx, text)
+}
+
+func (n *ackNotifier) AnswerCallbackQuery(_ context.Context, _ string, text string) error {
+	n.answers = append(n.answers, text)
+	return nil
+}
+
+func TestAckButtonAcknowledgesDownAlert(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	notifier := &ackNotifier{}
+	svc := New(testConfig(), store, notifier)
+	if err := store.UpsertTarget("test-track", "127.0.0.1", 1); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	target := svc.targets[0]
+	svc.applyStatus(target, true)
+	ctx := context.Background()
+	if ev := svc.applyStatus(target, false); ev != nil {
+		svc.sendAlertBatch(ctx, []alertEvent{*ev})
+	}
+	if len(notifier.buttons) != 1 || notifier.buttons[0] != "Ack:ack" {
+		t.Fatalf("expected an Ack button on the DOWN alert, got %v", notifier.buttons)
+	}
+
+	press := func(chatID int64, messageID int) {
+		svc.HandleUpdate(ctx, &models.Update{CallbackQuery: &models.CallbackQuery{
+			ID:      "q",
+			From:    models.User{ID: 7, Username: "oncall"},
+			Data:    ackCallbackData,
+			Message: models.MaybeInaccessibleMessage{Message: &models.Message{ID: messageID, Chat: models.Chat{ID: chatID}}},
+		}})
+	}
+	press(1, 101)
+	press(1, 101)
+	press(1, 999)
+	press(2, 101)
+	want := []string{"Acknowledged", "Already acknowledged by @oncall", "This alert is no longer open.", "This bot command is not available in this chat."}
+	if !slices.Equal(notifier.answers, want) {
+		t.Fatalf("expected answers %v, got %v", want, notifier.answers)
+	}
+	if len(notifier.edits) != 1 || !strings.Contains(notifier.edits[0], "<b>ACK</b> by @oncall") {
+		t.Fatalf("expected the alert to be edited with the ack, got %v", notifier.edits)
+	}
+	if snapshot := svc.Snapshot(); snapshot.Targets[0].AckedBy != "@oncall" || snapshot.Targets[0].AckedAt.IsZero() {
+		t.Fatalf("expected the ack in the snapshot, got %+v", snapshot.Targets[0])
+	}
+	if rows, _ := store.ListTargets(); len(rows) != 1 || rows[0].AckedBy != "@oncall" {
+		t.Fatalf("expected the ack to be stored, got %+v", rows)
+	}
+
+	svc.applyStatus(target, true)
+	if rows, _ := store.ListTargets(); rows[0].AckedBy != "" || !rows[0].AckedAt.IsZero() {
+		t.Fatalf("expected recovery to clear the stored ack, got %+v", rows[0])
+	}
+	if svc.Snapshot().Targets[0].AckedBy != "" {
+		t.Fatal("expected recovery to clear the ack")
+	}
+}
+
+func TestSubscribeRoundsCoalescesAndUnsubscribes(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	svc := New(testConfig(), store, &fakeNotifier{})
+	rounds, unsubscribe := svc.SubscribeRounds()
+	svc.rounds.publish()
+	svc.rounds.publish()
+	select {
+	case <-rounds:
+	default:
+		t.Fatal("expected a signal after a round")
+	}
+	select {
+	case <-rounds:
+		t.Fatal("expected unread rounds to coalesce into one signal")
+	default:
+	}
+
+	unsubscribe()
+	unsubscribe()
+	svc.rounds.publish()
+	select {
+	case <-rounds:
+		t.Fatal("expected no signal after unsubscribing")
+	default:
+	}
+}
+
+func TestSnapshotCountsChecksAndFailures(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	svc := New(testConfig(), store, &fakeNotifier{})
+	target := svc.targets[0]
+	for _, up := range []bool{true, false, false, true} {
+		svc.applyStatus(target, up)
+	}
+	got := svc.engine.Snapshot().Targets[0]
+	if got.Checks != 4 || got.CheckFailures != 2 {
+		t.Fatalf("expected 4 checks with 2 failures, got %d/%d", got.Checks, got.CheckFailures)
+	}
+}
+
+func TestIncidentsTextListsOutagesNewestFirst(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	svc := New(testConfig(), store, &fakeNotifier{})
+	now := time.Now().UTC()
+	if text := svc.incidentsText([]string{"test-track"}, now); !strings.Contains(text, "incidents, last 7d: 0") || !strings.Contains(text, "No outages.") {
+		t.Fatalf("expected no incidents, got %q", text)
+	}
+	if err := store.Append("test-track", "127.0.0.1", 1, false, "CHANGE"); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	text := svc.incidentsText([]string{"test-track", "3"}, now.Add(2*time.Minute))
+	if !strings.Contains(text, "incidents, last 3d: 1") || !strings.Contains(text, "-> ongoing 2m") {
+		t.Fatalf("expected one ongoing incident, got %q", text)
+	}
+	if text := svc.incidentsText([]string{"missing"}, now); !strings.Contains(text, "Track not found") {
+		t.Fatalf("expected not found, got %q", text)
+	}
+	if text := svc.incidentsText(nil, now); !strings.Contains(text, "Usage") {
+		t.Fatalf("expected usage, got %q", text)
+	}
+}
+
+func TestMaintenanceSuppressesAlertsAndTagsRows(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	now := time.Now().UTC()
+	cfg := testConfig()
+	cfg.Targets = append(cfg.Targets, config.Target{Name: "db", Address: "127.0.0.1", Port: 2})
+	cfg.Maintenance = []config.MaintenanceWindow{{
+		Name:    "deploy",
+		Start:   now.Add(-time.Minute).Format(time.RFC3339),
+		End:     now.Add(time.Hour).Format(time.RFC3339),
+		Targets: []string{"test-track"},
+	}}
+	svc := New(cfg, store, &fakeNotifier{})
+	engine := svc.engine
+	target := engine.targetByName["test-track"]
+
+	svc.applyStatus(target, true)
+	event := svc.applyStatus(target, false)
+	if event == nil || event.Kind != "DOWN" || target.LastStatus == nil || *target.LastStatus {
+		t.Fatalf("expected the state to flip during maintenance, got %+v", event)
+	}
+	other := alertEvent{Kind: "DOWN", Target: "db"}
+	if kept := engine.filterMaintenance([]alertEvent{*event, other}, now); len(kept) != 1 || kept[0].Target != "db" {
+		t.Fatalf("expected only the uncovered target to alert, got %+v", kept)
+	}
+	rows, _ := svc.Logs("test-track", 1, 10)
+	if len(rows) == 0 || !strings.HasSuffix(rows[len(rows)-1].Reason, "MAINTENANCE") {
+		t.Fatalf("expected maintenance rows to be tagged, got %+v", rows)
+	}
+	if text := svc.maintenanceText(now); !strings.Contains(text, "<b>deploy</b>") || !strings.Contains(text, "test-track") {
+		t.Fatalf("expected the active window, got %q", text)
+	}
+
+	// Still down after the window: the held DOWN goes out once.
+	after := now.Add(2 * time.Hour)
+	kept := engine.filterMaintenance(nil, after)
+	if len(kept) != 1 || kept[0].Kind != "DOWN" || kept[0].Reason != "maintenance-ended" {
+		t.Fatalf("expected held DOWN after the window, got %+v", kept)
+	}
+	if kept := engine.filterMaintenance(nil, after); len(kept) != 0 {
+		t.Fatalf("expected the held DOWN only once, got %+v", kept)
+	}
+	if text := svc.maintenanceText(after); text != "No active maintenance windows." {
+		t.Fatalf("expected no active windows, got %q", text)
+	}
+}
+
+func TestRecurringMaintenanceWindowIsActiveForItsDuration(t *testing.T) {
+	t.Parallel()
+
+	windows := newMaintenanceWindows([]config.MaintenanceWindow{{Name: "nightly", Schedule: "0 2 * * *", DurationMinutes: 30}})
+	day := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	for offset, want := range map[time.Duration]bool{
+		time.Hour + 59*time.Minute:   false,
+		2 * time.Hour:                true,
+		2*time.Hour + 29*time.Minute: true,
+		2*time.Hour + 30*time.Minute: false,
+	} {
+		until, active := windows[0].activeUntil(day.Add(offset))
+		if active != want {
+			t.Fatalf("at %s: expected active=%v", offset, want)
+		}
+		if active && !until.Equal(day.Add(2*time.Hour+30*time.Minute)) {
+			t.Fatalf("at %s: unexpected end %s", offset, until)
+		}
+	}
+}
+
+type pinningNotifier struct {
+	fakeNotifier
+	pinned  []int
+	replyTo []int
+	// pinnedID is the message Telegram reports as pinned.
+	pinnedID int
+}
+
+func (p *pinningNotifier) SendDefaultHTMLReply(_ context.Context, replyTo int, text string) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.replyTo = append(p.replyTo, replyTo)
+	p.replies = append(p.replies, text)
+	return 500 + len(p.replies), nil
+}
+
+func (p *pinningNotifier) PinDefaultMessage(_ context.Context, messageID int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pinned = append(p.pinned, messageID)
+	p.pinnedID = messageID
+	return nil
+}
+
+func (p *pinningNotifier) DefaultPinnedMessageID(context.Context) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.pinnedID, nil
+}
+
+func TestPinnedStatusThreadsAlerts(t *testing.T) {
+	t.Parallel()
+
+	notifier := &pinningNotifier{}
+	alerts := NewAlertManager(notifier, AlertOptions{})
+	board := newStatusBoard(notifier, nil)
+	alerts.board = board
+
+	snapshot := Snapshot{Total: 1, Down: 1, Targets: []TargetSnapshot{{Name: "a", Status: "DOWN"}}}
+	board.update(context.Background(), snapshot)
+	board.update(context.Background(), snapshot)
+	if len(notifier.defaults) != 1 || len(notifier.pinned) != 1 || notifier.pinned[0] != 101 {
+		t.Fatalf("expected one pinned status message, got defaults=%q pinned=%v", notifier.defaults, notifier.pinned)
+	}
+	if len(notifier.edits) != 0 {
+		t.Fatalf("expected unchanged status to skip edits, got %q", notifier.edits)
+	}
+
+	alerts.SendBatch(context.Background(), []alertEvent{
+		{Kind: "DOWN", Target: "a", Address: "10.0.0.1", Port: 80, Reason: "initial-check", Occurred: time.Now()},
+	})
+	if len(notifier.replyTo) != 1 || notifier.replyTo[0] != 101 {
+		t.Fatalf("expected alert to reply to pinned status, got %v", notifier.replyTo)
+	}
+
+	board.update(context.Background(), Snapshot{Total: 1, Up: 1, Targets: []TargetSnapshot{{Name: "a", Status: "UP"}}})
+	if len(notifier.edits) != 1 || !strings.Contains(notifier.edits[0], "All tracks are up.") {
+		t.Fatalf("expected status edit, got %q", notifier.edits)
+	}
+
+	notifier.editErr = errors.New("bad request, Bad Request: message to edit not found")
+	board.update(context.Background(), snapshot)
+	if len(notifier.defaults) != 2 || len(notifier.pinned) != 2 || board.currentID() != 102 {
+		t.Fatalf("expected deleted status to be recreated, got defaults=%q pinned=%v", notifier.defaults, notifier.pinned)
+	}
+}
+
+func TestPinnedStatusIsPinnedAgainWhenUnpinned(t *testing.T) {
+	t.Parallel()
+
+	notifier := &pinningNotifier{}
+	board := newStatusBoard(notifier, nil)
+	snapshot := Snapshot{Total: 1, Up: 1, Targets: []TargetSnapshot{{Name: "a", Status: "UP"}}}
+	board.update(context.Background(), snapshot)
+
+	// Someone unpins the message; editing it still succeeds.
+	notifier.pinnedID = 0
+	board.update(context.Background(), snapshot)
+	if len(notifier.pinned) != 1 {
+		t.Fatalf("expected no pin check within the interval, got %v", notifier.pinned)
+	}
+	board.lastPinCheck = time.Now().Add(-pinnedStatusCheckInterval)
+	board.update(context.Background(), snapshot)
+	if len(notifier.pinned) != 2 || notifier.pinned[1] != 101 || len(notifier.defaults) != 1 {
+		t.Fatalf("expected the same message pinned again, got defaults=%q pinned=%v", notifier.defaults, notifier.pinned)
+	}
+
+	board.lastPinCheck = time.Now().Add(-pinnedStatusCheckInterval)
+	board.update(context.Background(), snapshot)
+	if len(notifier.pinned) != 2 {
+		t.Fatalf("expected a pinned message to stay as it is, got %v", notifier.pinned)
+	}
+}
+
+func TestPinnedStatusMessageSurvivesRestart(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	snapshot := Snapshot{Total: 1, Up: 1, Targets: []TargetSnapshot{{Name: "a", Status: "UP"}}}
+	notifier := &pinningNotifier{}
+	newStatusBoard(notifier, store).update(context.Background(), snapshot)
+	if len(notifier.pinned) != 1 || notifier.pinned[0] != 101 {
+		t.Fatalf("expected one pinned status message, got %v", notifier.pinned)
+	}
+
+	// After a restart the stored message is edited, not pinned again, even
+	// when Telegram reports its text as unchanged.
+	restarted := &pinningNotifier{pinnedID: 101}
+	restarted.editErr = errors.New("bad request, Bad Request: message is not modified")
+	board := newStatusBoard(restarted, store)
+	if board.currentID() != 101 {
+		t.Fatalf("expected the stored message id, got %d", board.currentID())
+	}
+	board.update(context.Background(), snapshot)
+	board.update(context.Background(), snapshot)
+	if len(restarted.defaults) != 0 || len(restarted.pinned) != 0 {
+		t.Fatalf("expected no new pinned message, got defaults=%q pinned=%v", restarted.defaults, restarted.pinned)
+	}
+	if settings := New(testConfig(), store, nil).Settings(); settings.IntervalSeconds != 1 {
+		t.Fatalf("expected the pinned message id to leave runtime settings alone, got %+v", settings)
+	}
+}
+
+func TestRecoveryConfirmHoldsUntilRecheckSucceeds(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	down := alertEvent{Kind: "DOWN", Target: "a", Address: "10.0.0.1", Port: 80, Reason: "initial-check", Occurred: now}
+	recovered := alertEvent{Kind: "RECOVERED", Target: "a", Address: "10.0.0.1", Port: 80, Reason: "state-change", Occurred: now.Add(time.Minute)}
+
+	notifier := &fakeNotifier{}
+	alerts := NewAlertManager(notifier, AlertOptions{RecoveryConfirmDelay: 5 * time.Millisecond})
+	var checks atomic.Int32
+	alerts.SetRecoveryConfirm(func(context.Context, string) bool { return checks.Add(1) >= 2 })
+
+	alerts.SendBatch(context.Background(), []alertEvent{down})
+	alerts.SendBatch(context.Background(), []alertEvent{recovered})
+	sent := func() int {
+		notifier.mu.Lock()
+		defer notifier.mu.Unlock()
+		return len(notifier.defaults)
+	}
+	if sent() != 1 {
+		t.Fatalf("expected recovery to be held, got %q", notifier.defaults)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for sent() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if sent() != 2 || !strings.Contains(notifier.defaults[1], "<b>RECOVERED</b>") || checks.Load() != 2 {
+		t.Fatalf("expected recovery after second recheck, got %q (checks=%d)", notifier.defaults, checks.Load())
+	}
+
+	flapping := &fakeNotifier{}
+	alerts = NewAlertManager(flapping, AlertOptions{RecoveryConfirmDelay: 5 * time.Millisecond})
+	alerts.SetRecoveryConfirm(func(context.Context, string) bool { return false })
+	alerts.SendBatch(context.Background(), []alertEvent{down})
+	alerts.SendBatch(context.Background(), []alertEvent{recovered})
+	alerts.SendBatch(context.Background(), []alertEvent{{Kind: "DOWN", Target: "a", Address: "10.0.0.1", Port: 80, Reason: "state-change", Occurred: now.Add(2 * time.Minute)}})
+	time.Sleep(30 * time.Millisecond)
+	flapping.mu.Lock()
+	defer flapping.mu.Unlock()
+	if len(flapping.defaults) != 1 {
+		t.Fatalf("expected flap to be swallowed, got %q", flapping.defaults)
+	}
+}
+
+func TestNextTextReportsIntervalAndScheduleTicks(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Targets = append(cfg.Targets, config.Target{Name: "nightly", Address: "127.0.0.1", Port: 2, Schedule: "0 3 * * *"})
+	svc := New(cfg, store, &fakeNotifier{})
+
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	if text := svc.nextText("", now); !strings.Contains(text, "<b>nightly</b> not scheduled") {
+		t.Fatalf("expected unscheduled targets before the loop starts, got %q", text)
+	}
+
+	for _, target := range svc.engine.targets {
+		target.nextDue = now.Add(5 * time.Second)
+	}
+	text := svc.nextText("", now)
+	if !strings.Contains(text, "<b>test-track</b> in 5s") {
+		t.Fatalf("expected interval tick, got %q", text)
+	}
+	if !strings.Contains(text, "<b>nightly</b> in 15h0m0s (<code>2026-10-15T03:00:00Z</code>), waiting for schedule") {
+		t.Fatalf("expected schedule tick, got %q", text)
+	}
+	if strings.Index(text, "test-track") > strings.Index(text, "nightly") {
+		t.Fatalf("expected soonest first, got %q", text)
+	}
+	if text := svc.nextText("missing", now); !strings.Contains(text, "Track not found") {
+		t.Fatalf("expected not found, got %q", text)
+	}
+}
+
+func TestTakeDueHonorsPerTargetIntervals(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Monitoring.IntervalSeconds = 60
+	cfg.Targets = []config.Target{
+		{Name: "api", Address: "127.0.0.1", Port: 1, IntervalSeconds: 2},
+		{Name: "cheap", Address: "127.0.0.1", Port: 2},
+	}
+	svc := New(cfg, store, &fakeNotifier{})
+	engine := svc.engine
+
+	names := func(targets []*TargetState) string {
+		out := make([]string, 0, len(targets))
+		for _, target := range targets {
+			out = append(out, target.Name)
+		}
+		return strings.Join(out, ",")
+	}
+	start := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	if got := names(engine.takeDue(start)); got != "api,cheap" {
+		t.Fatalf("expected every target due on the first pass, got %q", got)
+	}
+	if wait := engine.untilNextDue(start); wait != 2*time.Second {
+		t.Fatalf("expected to sleep until api is due, got %s", wait)
+	}
+	for i := 1; i <= 29; i++ {
+		if got := names(engine.takeDue(start.Add(time.Duration(i) * 2 * time.Second))); got != "api" {
+			t.Fatalf("pass %d: expected only api due, got %q", i, got)
+		}
+	}
+	if got := names(engine.takeDue(start.Add(time.Minute))); got != "api,cheap" {
+		t.Fatalf("expected both due after a minute, got %q", got)
+	}
+	// A pass that runs late reschedules from now instead of catching up.
+	late := start.Add(5 * time.Minute)
+	engine.takeDue(late)
+	if wait := engine.untilNextDue(late); wait != 2*time.Second {
+		t.Fatalf("expected no backlog after a late pass, got %s", wait)
+	}
+}
+
+func TestInvestigateFirstFailureUpgradesOrClearsNote(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Monitoring.InvestigateFirstFailure = true
+	notifier := &fakeNotifier{}
+	svc := New(cfg, store, notifier)
+	target := svc.targets[0]
+	ctx := context.Background()
+	step := func(up bool) {
+		if ev := svc.applyStatus(target, up); ev != nil {
+			svc.sendAlertBatch(ctx, []alertEvent{*ev})
+		}
+	}
+
+	step(true)
+	step(false)
+	if len(notifier.defaults) != 1 || !strings.Contains(notifier.defaults[0], "<b>INVESTIGATING</b>") || !*target.LastStatus {
+		t.Fatalf("expected investigating note with target still UP, got %q", notifier.defaults)
+	}
+	step(true)
+	if len(notifier.edits) != 1 || !strings.Contains(notifier.edits[0], "false-alarm") {
+		t.Fatalf("expected false alarm edit, got %q", notifier.edits)
+	}
+
+	step(false)
+	step(false)
+	if len(notifier.defaults) != 2 || len(notifier.edits) != 2 || !strings.Contains(notifier.edits[1], "<b>DOWN</b>") {
+		t.Fatalf("expected second note upgraded to DOWN, got defaults=%q edits=%q", notifier.defaults, notifier.edits)
+	}
+	if *target.LastStatus {
+		t.Fatal("expected target DOWN after confirmed failure")
+	}
+	step(true)
+	if len(notifier.edits) != 3 || !strings.Contains(notifier.edits[2], "DOWN -> RECOVERED") {
+		t.Fatalf("expected fast recovery edit of the upgraded note, got %q", notifier.edits)
+	}
+}
+
+func TestGroupAlertEventsOrdersByImpact(t *testing.T) {
+	t.Parallel()
+
+	groups := groupAlertEvents([]alertEvent{
+		{Kind: "DOWN", Target: "b", Reason: "state-change"},
+		{Kind: "DOWN", Target: "a", Reason: "state-change"},
+		{Kind: "DOWN", Target: "db", Reason: "state-change", Impact: 10},
+		{Kind: "DOWN", Target: "z", Reason: "initial-check", Impact: 20},
+		{Kind: "RECOVERED", Target: "core", Reason: "state-change", Impact: 99},
+	})
+	var got []string
+	for _, group := range groups {
+		for _, ev := range group {
+			got = append(got, ev.Target)
+		}
+		got = append(got, "|")
+	}
+	if want := "z | db a b | core |"; strings.Join(got, " ") != want {
+		t.Fatalf("unexpected order %q, want %q", strings.Join(got, " "), want)
+	}
+}
+
+func TestConfigCommandUpdatesAndPersistsSettings(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	svc := New(testConfig(), store, &fakeNotifier{})
+
+	if text := svc.configText(nil); !strings.Contains(text, "interval_seconds: <code>1</code> (1-3600)") {
+		t.Fatalf("expected config defaults, got %q", text)
+	}
+	if text := svc.configText([]string{"interval_seconds", "30"}); !strings.Contains(text, "interval_seconds: <code>30</code>") {
+		t.Fatalf("expected updated interval, got %q", text)
+	}
+	if text := svc.configText([]string{"connect_timeout_seconds", "0"}); !strings.Contains(text, "must be between 1 and 60") {
+		t.Fatalf("expected range error, got %q", text)
+	}
+	if text := svc.configText([]string{"bogus", "1"}); !strings.Contains(text, "unknown key") {
+		t.Fatalf("expected unknown key error, got %q", text)
+	}
+	if _, err := svc.UpdateSettings(map[string]int{"escalate_after_seconds": 600, "interval_seconds": 0}); !errors.Is(err, ErrInvalidSetting) {
+		t.Fatalf("expected batch to be rejected as a whole, got %v", err)
+	}
+
+	restarted := New(testConfig(), store, &fakeNotifier{})
+	if got := restarted.Settings(); got.IntervalSeconds != 30 || got.EscalateAfterSeconds != 0 {
+		t.Fatalf("expected stored settings to override config, got %+v", got)
+	}
+}
+
+func TestFirstCycleDoneSignalsReadiness(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	svc := New(testConfig(), store, &fakeNotifier{})
+	if svc.Ready() {
+		t.Fatal("expected service not to be ready before the first cycle")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svc.RunMonitor(ctx)
+
+	select {
+	case <-svc.FirstCycleDone():
+	case <-time.After(5 * time.Second):
+		t.Fatal("first cycle did not complete")
+	}
+	if !svc.Ready() {
+		t.Fatal("expected service to be ready after the first cycle")
+	}
+	if snapshot := svc.Snapshot(); snapshot.Unknown != 0 {
+		t.Fatalf("expected every target to have a known state, got %+v", snapshot)
+	}
+}
+
+// slowNotifier blocks every command reply until release is closed.
+type slowNotifier struct {
+	fakeNotifier
+	release chan struct{}
+}
+
+func (n *slowNotifier) SendHTML(ctx context.Context, chatID int64, text string) error {
+	<-n.release
+	return n.fakeNotifier.SendHTML(ctx, chatID, text)
+}
+
+func TestUpdateQueueDropsAndAlertsWhenHandlerIsSlow(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Bot.UpdateQueueSize = 2
+	cfg.Bot.AlertDroppedUpdates = true
+	notifier := &slowNotifier{release: make(chan struct{})}
+	svc := New(cfg, store, notifier)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svc.RunUpdates(ctx)
+
+	update := func() *models.Update {
+		return &models.Update{Message: &models.Message{Text: "/list", Chat: models.Chat{ID: 1}}}
+	}
+	// The first update blocks the handler; wait until it has been taken off
+	// the queue so the next two fill it and the rest are dropped.
+	svc.EnqueueUpdate(ctx, update())
+	deadline := time.Now().Add(5 * time.Second)
+	for len(svc.commands.queue.updates) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("handler did not pick up the first update")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for range 5 {
+		svc.EnqueueUpdate(ctx, update())
+	}
+	if got := svc.droppedUpdates(); got != 3 {
+		t.Fatalf("expected 3 dropped updates, got %d", got)
+	}
+
+	deadline = time.Now().Add(5 * time.Second)
+	for {
+		notifier.mu.Lock()
+		alerts := append([]string(nil), notifier.defaults...)
+		notifier.mu.Unlock()
+		if len(alerts) == 1 {
+			if !strings.Contains(alerts[0], "1 update(s) dropped") {
+				t.Fatalf("unexpected dropped updates alert %q", alerts[0])
+			}
+			break
+		}
+		if len(alerts) > 1 || time.Now().After(deadline) {
+			t.Fatalf("expected exactly one rate-limited alert, got %q", alerts)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	close(notifier.release)
+	if text := svc.diagText(); !strings.Contains(text, "dropped bot updates: 3 (queue size 2)") {
+		t.Fatalf("expected dropped updates in diag, got %q", text)
+	}
+}
+
+func TestWatchCertAlertsOnlyWhenFingerprintChanges(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	addr := server.Listener.Addr().(*net.TCPAddr)
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Targets = []config.Target{{Name: "tls", Address: "127.0.0.1", Port: addr.Port, WatchCert: true}}
+	svc := New(cfg, store, &fakeNotifier{})
+	if err := svc.UpsertTarget("tls", "127.0.0.1", addr.Port); err != nil {
+		t.Fatalf("upsert target: %v", err)
+	}
+	target := svc.engine.targetByName["tls"]
+	certs, err := (probeOptions{}).fetchPeerCertificates(context.Background(), "127.0.0.1", addr.Port, time.Second)
+	if err != nil {
+		t.Fatalf("fetch certificates: %v", err)
+	}
+	if event := svc.engine.watchCert(target, certs); event != nil {
+		t.Fatalf("first fingerprint must not alert, got %+v", event)
+	}
+	stored := target.CertFingerprint
+	if len(stored) != 64 {
+		t.Fatalf("expected a stored sha256 fingerprint, got %q", stored)
+	}
+
+	// A restart loads the stored fingerprint, so the same certificate is quiet.
+	restarted := New(cfg, store, &fakeNotifier{})
+	restarted.engine.syncTargets()
+	target = restarted.engine.targetByName["tls"]
+	if target.CertFingerprint != stored {
+		t.Fatalf("expected fingerprint to survive restart, got %q", target.CertFingerprint)
+	}
+	if event := restarted.engine.watchCert(target, certs); event != nil {
+		t.Fatalf("unchanged certificate must not alert, got %+v", event)
+	}
+
+	target.CertFingerprint = strings.Repeat("ab", 32)
+	event := restarted.engine.watchCert(target, certs)
+	if event == nil || event.Kind != "CERT_CHANGED" || !strings.Contains(event.Detail, "old "+strings.Repeat("ab", 32)+" new "+stored) {
+		t.Fatalf("expected CERT_CHANGED with both fingerprints, got %+v", event)
+	}
+	targets, err := store.ListTargets()
+	if err != nil || len(targets) != 1 || targets[0].CertFingerprint != stored {
+		t.Fatalf("expected the new fingerprint to be persisted, got %+v err=%v", targets, err)
+	}
+}
+
+func TestCertExpiryReportsInvalidChainsAndExpiringCertificates(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	addr := server.Listener.Addr().(*net.TCPAddr)
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Targets = []config.Target{{Name: "tls", Address: "127.0.0.1", Port: addr.Port, CertWarnDays: 30}}
+	svc := New(cfg, store, &fakeNotifier{})
+	if err := svc.UpsertTarget("tls", "127.0.0.1", addr.Port); err != nil {
+		t.Fatalf("upsert target: %v", err)
+	}
+	engine := svc.engine
+	target := engine.targetByName["tls"]
+	certs, err := (probeOptions{}).fetchPeerCertificates(context.Background(), "127.0.0.1", addr.Port, time.Second)
+	if err != nil {
+		t.Fatalf("fetch certificates: %v", err)
+	}
+	now := time.Now().UTC()
+
+	// The test server's certificate is self-signed, so it must not pass.
+	event := engine.checkCertExpiry(target, certs, now)
+	if event == nil || event.Kind != "CERT_INVALID" || event.Reason != "self-signed" {
+		t.Fatalf("expected CERT_INVALID self-signed, got %+v", event)
+	}
+	if event := engine.checkCertExpiry(target, certs, now); event != nil {
+		t.Fatalf("expected one alert per condition, got %+v", event)
+	}
+	if target.CertNotAfter.IsZero() {
+		t.Fatal("expected the expiry to be recorded")
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	engine.certRoots = roots
+	if event := engine.checkCertExpiry(target, certs, now); event != nil {
+		t.Fatalf("expected a trusted certificate far from expiry to be quiet, got %+v", event)
+	}
+	target.CertWarnDays = int(time.Until(target.CertNotAfter).Hours()/24) + 10
+	event = engine.checkCertExpiry(target, certs, now)
+	if event == nil || event.Kind != "CERT_EXPIRING" || !strings.HasSuffix(event.Reason, "d left") {
+		t.Fatalf("expected CERT_EXPIRING, got %+v", event)
+	}
+	if snapshot := engine.Snapshot(); !snapshot.Targets[0].CertNotAfter.Equal(target.CertNotAfter) {
+		t.Fatalf("expected snapshot to carry the expiry, got %+v", snapshot.Targets[0])
+	}
+}
+
+func TestCheckCertsThrottlesExpiryChecks(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	addr := server.Listener.Addr().(*net.TCPAddr)
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	cfg := testConfig()
+	cfg.Targets = []config.Target{{Name: "tls", Address: "127.0.0.1", Port: addr.Port, CertWarnDays: 30, WatchCert: true}}
+	svc := New(cfg, store, &fakeNotifier{})
+	if err := svc.UpsertTarget("tls", "127.0.0.1", addr.Port); err != nil {
+		t.Fatalf("upsert target: %v", err)
+	}
+	engine := svc.engine
+	target := engine.targetByName["tls"]
+
+	now := time.Now().UTC()
+	events := engine.checkCerts(context.Background(), target, time.Second, now)
+	if len(events) != 1 || events[0].Kind != "CERT_INVALID" || target.CertFingerprint == "" {
+		t.Fatalf("expected CERT_INVALID and a stored fingerprint from one handshake, got %+v", events)
+	}
+	target.certAlert = ""
+	if events := engine.checkCerts(context.Background(), target, time.Second, now.Add(time.Minute)); len(events) != 0 {
+		t.Fatalf("expected the expiry check to wait for the interval, got %+v", events)
+	}
+	events = engine.checkCerts(context.Background(), target, time.Second, now.Add(certExpiryInterval))
+	if len(events) != 1 || events[0].Kind != "CERT_INVALID" {
+		t.Fatalf("expected the expiry check to run again after the interval, got %+v", events)
+	}
+}
+
+func TestResendReplaysLastBatchWithoutNewState(t *testing.T) {
+	t.Parallel()
+
+	store, err := logstore.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("logstore init error: %v", err)
+	}
+	notifier := &fakeNotifier{}
+	svc := New(testConfig(), store, notifier)
+	sink := &recordingSink{}
+	svc.AddAlertSink(sink)
+
+	if text := svc.resendText(context.Background()); text != "No alert has been sent yet." {
+		t.Fatalf("unexpected reply before any alert: %q", text)
+	}
+	svc.sendAlertBatch(context.Background(), []alertEvent{
+		{Kind: "DOWN", Target: "test-track", Address: "127.0.0.1", Port: 1, Reason: "state-change", Occurred: time.Now().UTC()},
+	})
+	if text := svc.resendText(context.Background()); text != "Resent 1 alert group(s) as a replay." {
+		t.Fatalf("unexpected resend reply: %q", text)
+	}
+
+	if len(notifier.defaults) != 2 || !strings.HasPrefix(notifier.defaults[1], "<b>REPLAY</b>") || !strings.Contains(notifier.defaults[1], "<b>DOWN</b>") {
+		t.Fatalf("expected a marked replay of the DOWN alert, got %q", notifier.defaults)
+	}
+	if len(sink.groups) != 2 || sink.groups[0].Replay || !sink.groups[1].Replay || sink.groups[1].Kind != "DOWN" {
+		t.Fatalf("expected sinks to get the replay flagged, got %+v", sink.groups)
+	}
+	// The replay must not become the message a fast recovery edits.
+	svc.sendAlertBatch(context.Background(), []alertEvent{
+		{Kind: "RECOVERED", Target: "test-track", Address: "127.0.0.1", Port: 1, Reason: "state-change", Occurred: time.Now().UTC()},
+	})
+	if len(notifier.edits) != 1 || strings.Contains(notifier.edits[0], "REPLAY") {
+		t.Fatalf("expected the original DOWN message to be edited, got %q", notifier.edits)
 	}
-	return cfg
 }
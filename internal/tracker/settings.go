@@ -0,0 +1,155 @@
+package tracker
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidSetting wraps unknown setting keys and out-of-range values.
+var ErrInvalidSetting = errors.New("invalid setting")
+
+// MonitoringSettings are the monitoring globals that can be changed at
+// runtime with /config and /api/settings. Config file values are the defaults
+// until a setting is stored.
+type MonitoringSettings struct {
+	IntervalSeconds       int `json:"interval_seconds"`
+	ConnectTimeoutSeconds int `json:"connect_timeout_seconds"`
+	MaxParallelChecks     int `json:"max_parallel_checks"`
+	EscalateAfterSeconds  int `json:"escalate_after_seconds"`
+}
+
+func (s MonitoringSettings) byKey() map[string]int {
+	return map[string]int{
+		"interval_seconds":        s.IntervalSeconds,
+		"connect_timeout_seconds": s.ConnectTimeoutSeconds,
+		"max_parallel_checks":     s.MaxParallelChecks,
+		"escalate_after_seconds":  s.EscalateAfterSeconds,
+	}
+}
+
+type settingRange struct {
+	min int
+	max int
+}
+
+var settingRanges = map[string]settingRange{
+	"interval_seconds":        {min: 1, max: 3600},
+	"connect_timeout_seconds": {min: 1, max: 60},
+	"max_parallel_checks":     {min: 0, max: maxParallelChecksHardLimit},
+	"escalate_after_seconds":  {min: 0, max: 7 * 24 * 3600},
+}
+
+// SettingKeys returns the runtime setting keys in display order.
+func SettingKeys() []string {
+	keys := make([]string, 0, len(settingRanges))
+	for key := range settingRanges {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (e *MonitorEngine) Settings() MonitoringSettings {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.settingsLocked()
+}
+
+func (e *MonitorEngine) settingsLocked() MonitoringSettings {
+	return MonitoringSettings{
+		IntervalSeconds:       int(e.interval / time.Second),
+		ConnectTimeoutSeconds: int(e.timeout / time.Second),
+		MaxParallelChecks:     e.maxParallel,
+		EscalateAfterSeconds:  int(e.escalateAfter / time.Second),
+	}
+}
+
+// UpdateSettings validates every value, stores them together, then applies
+// them to the running engine. A changed interval resets the ticker.
+func (e *MonitorEngine) UpdateSettings(values map[string]int) (MonitoringSettings, error) {
+	for key, value := range values {
+		if err := validateSetting(key, value); err != nil {
+			return e.Settings(), err
+		}
+	}
+	stored := make(map[string]string, len(values))
+	for key, value := range values {
+		stored[key] = strconv.Itoa(value)
+	}
+	if err := e.logs.SetSettings(stored); err != nil {
+		return e.Settings(), fmt.Errorf("store settings: %w", err)
+	}
+
+	e.mu.Lock()
+	for key, value := range values {
+		e.applySettingLocked(key, value)
+	}
+	settings := e.settingsLocked()
+	e.mu.Unlock()
+
+	select {
+	case e.settingsChanged <- struct{}{}:
+	default:
+	}
+	e.logger.Info("monitoring settings updated", "settings", values)
+	return settings, nil
+}
+
+// loadSettings overlays stored settings on the config defaults. Invalid
+// stored values are logged and skipped.
+func (e *MonitorEngine) loadSettings() {
+	stored, err := e.logs.Settings()
+	if err != nil {
+		e.logger.Warn("failed to load stored settings", "error", err)
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for key, raw := range stored {
+		if key == pinnedStatusSetting {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err == nil {
+			err = validateSetting(key, value)
+		}
+		if err != nil {
+			e.logger.Warn("ignoring stored setting", "key", key, "value", raw, "error", err)
+			continue
+		}
+		e.applySettingLocked(key, value)
+	}
+}
+
+func validateSetting(key string, value int) error {
+	valid, ok := settingRanges[key]
+	if !ok {
+		return fmt.Errorf("%w: unknown key %q", ErrInvalidSetting, key)
+	}
+	if value < valid.min || value > valid.max {
+		return fmt.Errorf("%w: %s must be between %d and %d", ErrInvalidSetting, key, valid.min, valid.max)
+	}
+	return nil
+}
+
+func (e *MonitorEngine) applySettingLocked(key string, value int) {
+	switch key {
+	case "interval_seconds":
+		e.interval = time.Duration(value) * time.Second
+	case "connect_timeout_seconds":
+		e.timeout = time.Duration(value) * time.Second
+	case "max_parallel_checks":
+		e.maxParallel = value
+	case "escalate_after_seconds":
+		e.escalateAfter = time.Duration(value) * time.Second
+	}
+}
+
+func (e *MonitorEngine) checkInterval() time.Duration {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.interval
+}
@@ -0,0 +1,98 @@
+package tracker
+
+import (
+	"context"
+	"time"
+)
+
+const sinkTimeout = 10 * time.Second
+
+// AlertSink delivers alerts to a channel other than Telegram (e.g. a chat
+// webhook). Each call carries one group of alerts sharing kind and reason.
+type AlertSink interface {
+	Name() string
+	SendAlertGroup(ctx context.Context, group AlertGroup) error
+}
+
+// AlertGroup is the sink-facing form of a batch of alert events.
+type AlertGroup struct {
+	Kind     string
+	Reason   string
+	Detail   string
+	Occurred time.Time
+	Targets  []AlertTarget
+	// Replay marks a resent group that is not a new event.
+	Replay bool
+}
+
+type AlertTarget struct {
+	Name       string
+	Address    string
+	Port       int
+	RunbookURL string
+}
+
+// AddSink registers an extra alert destination. Call before monitoring starts.
+func (a *AlertManager) AddSink(sink AlertSink) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sinks = append(a.sinks, sink)
+}
+
+// queueForSinks keeps groups for the sinks until the caller releases a.mu.
+// Callers hold a.mu.
+func (a *AlertManager) queueForSinks(groups [][]alertEvent) {
+	if len(a.sinks) == 0 {
+		return
+	}
+	for _, events := range groups {
+		a.sinkOutbox = append(a.sinkOutbox, newAlertGroup(events))
+	}
+}
+
+// unlockAndSendToSinks releases a.mu, then sends the queued groups to every
+// sink, so a slow sink holds up neither Telegram nor the next batch.
+func (a *AlertManager) unlockAndSendToSinks(ctx context.Context) {
+	groups, sinks := a.sinkOutbox, a.sinks
+	a.sinkOutbox = nil
+	a.mu.Unlock()
+	a.sendToSinks(ctx, sinks, groups)
+}
+
+// sendToSinks sends groups to sinks without a.mu and returns the last error.
+func (a *AlertManager) sendToSinks(ctx context.Context, sinks []AlertSink, groups []AlertGroup) error {
+	var sendErr error
+	for _, group := range groups {
+		for _, sink := range sinks {
+			sinkCtx, cancel := context.WithTimeout(ctx, sinkTimeout)
+			if err := sink.SendAlertGroup(sinkCtx, group); err != nil {
+				a.logger.Warn("failed to send alert to sink", "sink", sink.Name(), "kind", group.Kind, "count", len(group.Targets), "replay", group.Replay, "error", err)
+				sendErr = err
+			}
+			cancel()
+		}
+	}
+	return sendErr
+}
+
+func newAlertGroup(events []alertEvent) AlertGroup {
+	first := events[0]
+	group := AlertGroup{
+		Kind:     first.Kind,
+		Reason:   first.Reason,
+		Detail:   first.Detail,
+		Occurred: first.Occurred,
+	}
+	for _, event := range events {
+		if event.Target == "" {
+			continue
+		}
+		group.Targets = append(group.Targets, AlertTarget{
+			Name:       event.Target,
+			Address:    event.Address,
+			Port:       event.Port,
+			RunbookURL: event.RunbookURL,
+		})
+	}
+	return group
+}
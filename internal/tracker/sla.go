@@ -0,0 +1,211 @@
+package tracker
+
+import (
+	"sort"
+	"time"
+
+	"trackway/internal/logstore"
+)
+
+const slaMaxRows = 50000
+
+type SLAReport struct {
+	Target        string
+	Days          int
+	Checks        int
+	UpChecks      int
+	UptimePercent float64
+	Latency       *LatencyPercentiles
+}
+
+// LatencyPercentiles holds connect latency in milliseconds. It is nil on a
+// report when no row in the window carried latency (e.g. older rows).
+type LatencyPercentiles struct {
+	Samples int
+	P50     float64
+	P95     float64
+	P99     float64
+}
+
+func (e *MonitorEngine) SLA(trackName string, days int) ([]SLAReport, bool) {
+	names := []string{trackName}
+	if trackName == "" {
+		names = e.TargetNames()
+	}
+
+	days = clampDays(days)
+	now := time.Now().UTC()
+	from := now.Add(-time.Duration(days) * 24 * time.Hour)
+	reports := make([]SLAReport, 0, len(names))
+	for _, name := range names {
+		if !e.hasTarget(name) {
+			if trackName != "" {
+				return nil, false
+			}
+			continue
+		}
+		stats := e.logs.CheckStats(name, from, now)
+		latency := e.logs.LatencyPercentiles(name, from, now, 50, 95, 99)
+		reports = append(reports, buildSLAReport(name, days, stats, latency))
+	}
+	return reports, true
+}
+
+func (e *MonitorEngine) hasTarget(name string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.targetByName[name] != nil
+}
+
+// OverallSLAReport is the service-level view over critical targets. The
+// service counts as down whenever any critical target is DOWN, so Downtime is
+// the union of their DOWN periods within Covered (from the first logged row
+// to now). WeightedUptimePercent is the weight-averaged check uptime of the
+// same targets.
+type OverallSLAReport struct {
+	Days                  int
+	Targets               []CriticalTargetSLA
+	Covered               time.Duration
+	Downtime              time.Duration
+	UptimePercent         float64
+	WeightedUptimePercent float64
+}
+
+type CriticalTargetSLA struct {
+	Name          string
+	Weight        float64
+	Checks        int
+	UptimePercent float64
+}
+
+// OverallSLA returns false when no target is marked critical.
+func (e *MonitorEngine) OverallSLA(days int) (OverallSLAReport, bool) {
+	weights := make(map[string]float64)
+	e.mu.RLock()
+	for _, target := range e.targets {
+		if !target.Critical {
+			continue
+		}
+		weights[target.Name] = target.Weight
+		if target.Weight <= 0 {
+			weights[target.Name] = 1
+		}
+	}
+	e.mu.RUnlock()
+	if len(weights) == 0 {
+		return OverallSLAReport{}, false
+	}
+
+	days = clampDays(days)
+	now := time.Now().UTC()
+	from := now.Add(-time.Duration(days) * 24 * time.Hour)
+	stats := make(map[string]logstore.CheckStats, len(weights))
+	incidents := make(map[string][]logstore.Incident, len(weights))
+	for name := range weights {
+		stats[name] = e.logs.CheckStats(name, from, now)
+		incidents[name] = e.logs.Incidents(name, days, slaMaxRows)
+	}
+	return buildOverallSLAReport(weights, stats, incidents, now, days), true
+}
+
+// buildOverallSLAReport clips each incident to the window; Covered starts at
+// the earliest row of any critical target.
+func buildOverallSLAReport(weights map[string]float64, stats map[string]logstore.CheckStats, incidents map[string][]logstore.Incident, now time.Time, days int) OverallSLAReport {
+	report := OverallSLAReport{Days: days}
+	start := now.Add(-time.Duration(days) * 24 * time.Hour)
+	first := now
+	var downSpans [][2]time.Time
+	var weighted, totalWeight float64
+
+	names := make([]string, 0, len(weights))
+	for name := range weights {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		targetReport := buildSLAReport(name, days, stats[name], nil)
+		report.Targets = append(report.Targets, CriticalTargetSLA{
+			Name:          name,
+			Weight:        weights[name],
+			Checks:        targetReport.Checks,
+			UptimePercent: targetReport.UptimePercent,
+		})
+		if targetReport.Checks > 0 {
+			weighted += weights[name] * targetReport.UptimePercent
+			totalWeight += weights[name]
+			if at := stats[name].First; at.Before(first) {
+				first = at
+			}
+		}
+
+		for _, incident := range incidents[name] {
+			from, to := incident.Start, incident.Start.Add(incident.Duration)
+			if from.Before(start) {
+				from = start
+			}
+			if to.After(now) {
+				to = now
+			}
+			if to.After(from) {
+				downSpans = append(downSpans, [2]time.Time{from, to})
+			}
+		}
+	}
+
+	if first.Before(start) {
+		first = start
+	}
+	report.Covered = now.Sub(first)
+	report.Downtime = unionDuration(downSpans)
+	if report.Covered > 0 {
+		report.UptimePercent = 100 - float64(report.Downtime)*100/float64(report.Covered)
+	}
+	if totalWeight > 0 {
+		report.WeightedUptimePercent = weighted / totalWeight
+	}
+	return report
+}
+
+func unionDuration(spans [][2]time.Time) time.Duration {
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0].Before(spans[j][0]) })
+	var total time.Duration
+	var end time.Time
+	for _, span := range spans {
+		if span[0].After(end) {
+			end = span[0]
+		}
+		if span[1].After(end) {
+			total += span[1].Sub(end)
+			end = span[1]
+		}
+	}
+	return total
+}
+
+// buildSLAReport takes latency as the p50, p95 and p99 values, or nil when
+// no check in the window measured latency.
+func buildSLAReport(name string, days int, stats logstore.CheckStats, latency []float64) SLAReport {
+	report := SLAReport{Target: name, Days: days, Checks: stats.Checks, UpChecks: stats.UpChecks}
+	if report.Checks > 0 {
+		report.UptimePercent = float64(report.UpChecks) * 100 / float64(report.Checks)
+	}
+	if len(latency) == 3 {
+		report.Latency = &LatencyPercentiles{
+			Samples: stats.LatencySamples,
+			P50:     latency[0],
+			P95:     latency[1],
+			P99:     latency[2],
+		}
+	}
+	return report
+}
+
+func clampDays(days int) int {
+	if days <= 0 {
+		return 7
+	}
+	if days > 365 {
+		return 365
+	}
+	return days
+}
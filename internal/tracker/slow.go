@@ -0,0 +1,53 @@
+package tracker
+
+import (
+	"fmt"
+	"time"
+)
+
+// slowReason marks SLOW alerts and the recoveries that end them, so they
+// group apart from connectivity alerts and leave DOWN state alone.
+const slowReason = "slow-response"
+
+// slowEvent returns SLOW when an UP target has turned slow and RECOVERED when
+// its latency is back under the threshold. Callers hold e.mu and have already
+// updated target.slow.
+func slowEvent(target *TargetState, latency time.Duration, now time.Time) *alertEvent {
+	event := &alertEvent{
+		Kind:     "RECOVERED",
+		Target:   target.Name,
+		Address:  target.Address,
+		Port:     target.Port,
+		Reason:   slowReason,
+		Detail:   fmt.Sprintf("%s <= %s", formatLatency(latency), formatLatency(target.SlowThreshold)),
+		Occurred: now,
+	}
+	if target.slow {
+		event.Kind = "SLOW"
+		event.Detail = fmt.Sprintf("%s > %s", formatLatency(latency), formatLatency(target.SlowThreshold))
+		event.Mention = target.Mention
+		event.RunbookURL = target.RunbookURL
+	}
+	return event
+}
+
+// isSlowRecovery reports whether ev ends a SLOW alert rather than an outage.
+func isSlowRecovery(ev alertEvent) bool {
+	return ev.Kind == "RECOVERED" && ev.Reason == slowReason
+}
+
+// recoveredFrom names the alert a recovery edit closes.
+func recoveredFrom(reason string) string {
+	if reason == slowReason {
+		return "SLOW"
+	}
+	return "DOWN"
+}
+
+// outageLabel names the duration a recovery edit reports.
+func outageLabel(from string) string {
+	if from == "SLOW" {
+		return "slow_for"
+	}
+	return "downtime"
+}
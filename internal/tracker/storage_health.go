@@ -0,0 +1,75 @@
+package tracker
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// storageHealth tracks consecutive log write failures and turns them into a
+// single STORAGE_DEGRADED notice, cleared by STORAGE_RECOVERED once writes
+// succeed again. Degraded notices are rate limited by cooldown.
+type storageHealth struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu          sync.Mutex
+	consecutive int
+	total       int
+	degraded    bool
+	lastAlert   time.Time
+	lastError   string
+}
+
+func newStorageHealth(threshold int, cooldown time.Duration) *storageHealth {
+	if threshold <= 0 {
+		threshold = 5
+	}
+	return &storageHealth{threshold: threshold, cooldown: cooldown}
+}
+
+func (h *storageHealth) record(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err == nil {
+		h.consecutive = 0
+		return
+	}
+	h.consecutive++
+	h.total++
+	h.lastError = err.Error()
+}
+
+func (h *storageHealth) event(now time.Time) *alertEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch {
+	case !h.degraded && h.consecutive >= h.threshold:
+		if !h.lastAlert.IsZero() && now.Sub(h.lastAlert) < h.cooldown {
+			return nil
+		}
+		h.degraded = true
+		h.lastAlert = now
+		return &alertEvent{
+			Kind:     "STORAGE_DEGRADED",
+			Reason:   "write-failures",
+			Detail:   strconv.Itoa(h.consecutive) + " consecutive failures, last error: " + h.lastError,
+			Occurred: now,
+		}
+	case h.degraded && h.consecutive == 0:
+		h.degraded = false
+		return &alertEvent{
+			Kind:     "STORAGE_RECOVERED",
+			Reason:   "write-succeeded",
+			Occurred: now,
+		}
+	}
+	return nil
+}
+
+func (h *storageHealth) snapshot() (consecutive int, total int, degraded bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.consecutive, h.total, h.degraded
+}
@@ -0,0 +1,61 @@
+package tracker
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"trackway/internal/logstore"
+)
+
+// storeConfigTags writes the tags of config targets to the store when they
+// differ, so they are kept with the target like thresholds and mutes.
+func (e *MonitorEngine) storeConfigTags() {
+	rows, err := e.logs.ListTargets()
+	if err != nil {
+		e.logger.Warn("failed to load targets from store", "error", err)
+		return
+	}
+	e.mu.RLock()
+	changed := make(map[string][]string)
+	for _, row := range rows {
+		item, ok := e.targetConfig[row.Name]
+		if ok && !slices.Equal(row.Tags, item.Tags) {
+			changed[row.Name] = item.Tags
+		}
+	}
+	e.mu.RUnlock()
+
+	for name, tags := range changed {
+		if err := e.logs.SetTargetTags(name, tags); err != nil && !errors.Is(err, logstore.ErrTargetNotFound) {
+			e.logger.Warn("failed to store target tags", "track", name, "error", err)
+		}
+	}
+}
+
+// HasTag reports whether the target carries tag, ignoring case.
+func (t TargetSnapshot) HasTag(tag string) bool {
+	return slices.ContainsFunc(t.Tags, func(item string) bool { return strings.EqualFold(item, tag) })
+}
+
+// WithTag returns the snapshot narrowed to targets tagged tag, with the
+// counters recomputed for them.
+func (s Snapshot) WithTag(tag string) Snapshot {
+	out := Snapshot{GeneratedAt: s.GeneratedAt, Targets: make([]TargetSnapshot, 0)}
+	for _, target := range s.Targets {
+		if !target.HasTag(tag) {
+			continue
+		}
+		out.Targets = append(out.Targets, target)
+		out.Total++
+		switch target.Status {
+		case "UP":
+			out.Up++
+		case "DOWN":
+			out.Down++
+		default:
+			out.Unknown++
+		}
+	}
+	return out
+}
@@ -0,0 +1,108 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"trackway/internal/config"
+)
+
+// watchTargetsFile reconciles the store against the target source file on
+// its own interval until ctx is done.
+func (e *MonitorEngine) watchTargetsFile(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(e.targetSource.ReloadIntervalSeconds) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.reloadTargetsFile()
+		}
+	}
+}
+
+// reloadTargetsFile reads the target source file and applies it. A file that
+// does not load is ignored and the last good set stays in force.
+func (e *MonitorEngine) reloadTargetsFile() {
+	path := e.targetSource.Path
+	targets, err := config.LoadTargetsFile(path, e.rejectDuplicates)
+	if err != nil {
+		e.logger.Warn("target file reload rejected, keeping the last good targets", "path", path, "error", err)
+		return
+	}
+	if err := e.applyFileTargets(targets); err != nil {
+		e.logger.Warn("target file reconcile failed", "path", path, "error", err)
+	}
+}
+
+// applyFileTargets stores file targets that are new or moved to another
+// endpoint and disables those dropped from the file since the last load, so
+// their history is kept. Names that are config targets stay with the config.
+func (e *MonitorEngine) applyFileTargets(targets []config.Target) error {
+	next := make(map[string]config.Target, len(targets))
+	var skipped []string
+	var changed []config.Target
+	var removed []string
+
+	e.mu.RLock()
+	for _, item := range targets {
+		if _, fromConfig := e.targetConfig[item.Name]; fromConfig && !e.isFileTarget(item.Name) {
+			skipped = append(skipped, item.Name)
+			continue
+		}
+		next[item.Name] = item
+		current := e.targetByName[item.Name]
+		if current == nil || current.Address != item.Address || current.Port != item.Port {
+			changed = append(changed, item)
+		}
+	}
+	for name := range e.fileTargets {
+		if _, ok := next[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	unchanged := len(changed) == 0 && reflect.DeepEqual(next, e.fileTargets)
+	e.mu.RUnlock()
+
+	for _, name := range skipped {
+		e.logger.Warn("target file entry shadowed by a config target", "track", name)
+	}
+	if unchanged {
+		return nil
+	}
+	for _, name := range removed {
+		if err := e.logs.DeleteTarget(name); err != nil {
+			return fmt.Errorf("disable target %s: %w", name, err)
+		}
+	}
+	for _, item := range changed {
+		if err := e.logs.UpsertTarget(item.Name, item.Address, item.Port); err != nil {
+			return fmt.Errorf("store target %s: %w", item.Name, err)
+		}
+	}
+
+	e.mu.Lock()
+	for _, name := range removed {
+		delete(e.targetConfig, name)
+	}
+	for name, item := range next {
+		e.targetConfig[name] = item
+	}
+	e.fileTargets = next
+	e.mu.Unlock()
+	e.storeConfigTags()
+	e.syncTargets()
+
+	e.logger.Info("target file applied", "targets", len(next), "stored", len(changed), "disabled", len(removed))
+	return nil
+}
+
+// isFileTarget reports whether name came from the target source file. The
+// caller holds e.mu.
+func (e *MonitorEngine) isFileTarget(name string) bool {
+	_, ok := e.fileTargets[name]
+	return ok
+}
@@ -0,0 +1,53 @@
+package tracker
+
+import (
+	"errors"
+	"fmt"
+
+	"trackway/internal/logstore"
+)
+
+const maxThreshold = 20
+
+// SetThreshold updates the "fail" or "success" threshold of a target, persists
+// it with the target and returns the resulting fail and success thresholds.
+// The in-memory state is updated right away so the next cycle uses it.
+func (e *MonitorEngine) SetThreshold(trackName, kind string, value int) (int, int, error) {
+	if value < 1 || value > maxThreshold {
+		return 0, 0, fmt.Errorf("threshold must be between 1 and %d", maxThreshold)
+	}
+
+	e.mu.RLock()
+	target, ok := e.targetByName[trackName]
+	var fail, success int
+	if ok {
+		fail, success = target.FailThreshold, target.SuccessThreshold
+	}
+	e.mu.RUnlock()
+	if !ok {
+		return 0, 0, ErrTargetNotFound
+	}
+
+	switch kind {
+	case "fail":
+		fail = value
+	case "success":
+		success = value
+	default:
+		return 0, 0, fmt.Errorf("unknown threshold %q", kind)
+	}
+	if err := e.logs.SetTargetThresholds(trackName, fail, success); err != nil {
+		if errors.Is(err, logstore.ErrTargetNotFound) {
+			return 0, 0, ErrTargetNotFound
+		}
+		return 0, 0, err
+	}
+
+	e.mu.Lock()
+	if target, ok := e.targetByName[trackName]; ok {
+		target.FailThreshold = fail
+		target.SuccessThreshold = success
+	}
+	e.mu.Unlock()
+	return fail, success, nil
+}
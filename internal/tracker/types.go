@@ -3,6 +3,10 @@ package tracker
 import (
 	"context"
 	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"trackway/internal/config"
 )
 
 type Notifier interface {
@@ -16,26 +20,133 @@ type TargetState struct {
 	Name        string
 	Address     string
 	Port        int
+	Mention     string
+	RunbookURL  string
+	Tags        []string
 	LastStatus  *bool
 	LastChanged time.Time
 	LastChecked time.Time
+	LastLatency time.Duration
+	// ResolvedIP is the address the last tcp check of a host name dialed,
+	// out of ResolvedAddrs records.
+	ResolvedIP    string
+	ResolvedAddrs int
+	Escalated     bool
+	Probes        int
+	MinHealthy    int
+	Schedule      cron.Schedule
+	Fallback      *config.FallbackCheck
+	FallbackRules *bodyRules
+	Critical      bool
+	Weight        float64
+	Impact        int
+	WatchCert     bool
+	AlertGate     string
+	// SlowThreshold > 0 reports an UP target whose check takes longer as
+	// SLOW; slow is set while that alert is active.
+	SlowThreshold time.Duration
+	slow          bool
+	// CheckType is "tcp", "http", "https", "ping" or "udp"; HTTPPath,
+	// ExpectStatus and BodyRules apply to the http types, the UDP fields to
+	// udp.
+	CheckType       string
+	HTTPPath        string
+	ExpectStatus    int
+	BodyRules       *bodyRules
+	UDPPayload      []byte
+	UDPExpect       []byte
+	UDPNoResponseUp bool
+	// CertFingerprint is the last seen leaf certificate SHA-256, persisted
+	// with the target.
+	CertFingerprint string
+	// CertWarnDays enables the expiry check; CertNotAfter is the expiry of
+	// the last certificate seen, certAlert the CERT_* kind last alerted and
+	// certCheckedAt when the expiry was last checked.
+	CertWarnDays  int
+	CertNotAfter  time.Time
+	certAlert     string
+	certCheckedAt time.Time
+
+	MutedUntil time.Time
+	// AckedBy and AckedAt record the acknowledgement of the ongoing outage;
+	// a recovery clears them.
+	AckedBy string
+	AckedAt time.Time
+
+	// Interval overrides the monitoring interval when set; nextDue is when
+	// the target is checked next, zero until its first check.
+	Interval time.Duration
+	nextDue  time.Time
+
+	WindowChecks   int
+	WindowFailures int
+	// recent holds raw results for the window policy, oldest first.
+	recent []bool
+	// failStreak counts consecutive raw failures since failingSince.
+	failStreak   int
+	failingSince time.Time
+	// FailThreshold/SuccessThreshold are consecutive raw results needed to
+	// flip the state; okStreak counts consecutive successes.
+	FailThreshold    int
+	SuccessThreshold int
+	okStreak         int
+
+	// checks and checkFailures count raw results since start.
+	checks        int64
+	checkFailures int64
+	// investigating is set while an INVESTIGATING note awaits confirmation.
+	investigating bool
+}
+
+type checkResult struct {
+	Up      bool
+	Latency time.Duration
+	// Healthy and Probes are set only for multi-probe checks.
+	Healthy int
+	Probes  int
+	// Method names the check that determined the state when a fallback is
+	// configured.
+	Method string
+	// Rule names the body rule that decided an http check, if any.
+	Rule string
+	// Status describes an http check's answer, like "http 503", or why a
+	// check failed, like "dns-failure".
+	Status string
+	// IP is the resolved address a tcp check dialed, out of Addrs records;
+	// empty for IP literals.
+	IP    string
+	Addrs int
 }
 
 type alertEvent struct {
-	Kind     string
-	Target   string
-	Address  string
-	Port     int
-	Reason   string
-	Occurred time.Time
+	Kind       string
+	Target     string
+	Address    string
+	Port       int
+	Reason     string
+	Detail     string
+	Mention    string
+	RunbookURL string
+	Occurred   time.Time
+	// FailedChecks and FailingSince describe the failure streak behind a DOWN.
+	FailedChecks int
+	FailingSince time.Time
+	// Impact is copied from the target where the event is built, to order
+	// grouped alerts; Critical lets them through quiet hours.
+	Impact   int
+	Critical bool
 }
 
 type pendingDownAlert struct {
+	// MessageID is the DOWN message to edit on a fast recovery; 0 when the
+	// entry only records an escalation.
 	MessageID int
 	DownAt    time.Time
 	Reason    string
 	Address   string
 	Port      int
+	// Escalated marks an outage that got a STILL_DOWN alert.
+	Escalated bool
 }
 
 type pendingDownGroup struct {
@@ -61,6 +172,37 @@ type TargetSnapshot struct {
 	Status      string
 	LastChanged time.Time
 	LastChecked time.Time
+	LastLatency time.Duration
+	RunbookURL  string
+	MutedUntil  time.Time
+	Tags        []string
+	AckedBy     string
+	AckedAt     time.Time
+	// ResolvedIP is the address dialed for a host name, the lowest of
+	// ResolvedAddrs records.
+	ResolvedIP    string
+	ResolvedAddrs int
+	// CertNotAfter is set for targets with cert_warn_days once checked.
+	CertNotAfter time.Time
+	// Checks and CheckFailures count raw results since start.
+	Checks        int64
+	CheckFailures int64
+
+	FailThreshold    int
+	SuccessThreshold int
+}
+
+type Diagnostics struct {
+	Targets                    int
+	DuplicateEndpoints         []DuplicateEndpoint
+	StorageConsecutiveFailures int
+	StorageTotalFailures       int
+	StorageDegraded            bool
+}
+
+type DuplicateEndpoint struct {
+	Endpoint string
+	Targets  []string
 }
 
 func boolPtr(value bool) *bool {
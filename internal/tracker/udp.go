@@ -0,0 +1,65 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// maxUDPReply is the largest reply read; a longer one is truncated.
+const maxUDPReply = 64 * 1024
+
+// checkUDP sends the target's payload and waits until timeout for a reply,
+// which must contain UDPExpect when that is set. Without a reply the target
+// is DOWN with udp-no-response, or UP with the same status when
+// UDPNoResponseUp is set, since UDP cannot tell a silent service from a
+// missing one. An ICMP port unreachable reply is always DOWN.
+func (p probeOptions) checkUDP(ctx context.Context, target *TargetState, timeout time.Duration) checkResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ip, addrs, err := resolveTarget(ctx, target.Address)
+	if err != nil {
+		return checkResult{Status: "dns-failure"}
+	}
+	result := checkResult{Addrs: addrs}
+	if addrs > 0 {
+		result.IP = ip.String()
+	}
+	conn, err := p.udpDialer().DialContext(ctx, "udp", net.JoinHostPort(ip.String(), strconv.Itoa(target.Port)))
+	if err != nil {
+		result.Status = "udp-error"
+		return result
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	startedAt := time.Now()
+	if _, err := conn.Write(target.UDPPayload); err != nil {
+		result.Status = "udp-error"
+		return result
+	}
+	reply := make([]byte, maxUDPReply)
+	n, err := conn.Read(reply)
+	var netErr net.Error
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		result.Status = "udp-refused"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		result.Status = "udp-no-response"
+		result.Up = target.UDPNoResponseUp
+	case err != nil:
+		result.Status = "udp-error"
+	case len(target.UDPExpect) > 0 && !bytes.Contains(reply[:n], target.UDPExpect):
+		result.Status = "udp-unexpected-response"
+	default:
+		result.Up = true
+		result.Latency = time.Since(startedAt)
+	}
+	return result
+}
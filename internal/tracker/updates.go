@@ -0,0 +1,96 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-telegram/bot/models"
+
+	"trackway/internal/config"
+)
+
+const (
+	droppedUpdatesAlertCooldown  = 10 * time.Minute
+	droppedUpdatesAlertSendLimit = 5 * time.Second
+)
+
+// updateQueue buffers Telegram updates between the poller and the command
+// handler so a slow command never blocks polling. Updates that do not fit
+// are dropped and counted.
+type updateQueue struct {
+	updates chan *models.Update
+	dropped atomic.Int64
+	alert   bool
+
+	mu        sync.Mutex
+	lastAlert time.Time
+}
+
+func newUpdateQueue(size int) *updateQueue {
+	if size <= 0 {
+		size = config.DefaultUpdateQueueSize
+	}
+	return &updateQueue{updates: make(chan *models.Update, size)}
+}
+
+// shouldAlert reports whether a drop at now should be announced, at most once
+// per cooldown.
+func (q *updateQueue) shouldAlert(now time.Time) bool {
+	if !q.alert {
+		return false
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.lastAlert.IsZero() && now.Sub(q.lastAlert) < droppedUpdatesAlertCooldown {
+		return false
+	}
+	q.lastAlert = now
+	return true
+}
+
+// EnqueueUpdate hands update to RunUpdates without blocking the caller.
+func (h *CommandHandler) EnqueueUpdate(ctx context.Context, update *models.Update) {
+	select {
+	case h.queue.updates <- update:
+	case <-ctx.Done():
+	default:
+		dropped := h.queue.dropped.Add(1)
+		h.logger.Warn("dropping update due to full queue", "dropped_total", dropped, "queue_size", cap(h.queue.updates))
+		if h.notifier != nil && h.queue.shouldAlert(time.Now()) {
+			go h.sendDroppedUpdatesAlert(dropped)
+		}
+	}
+}
+
+// RunUpdates handles queued updates one at a time until ctx is done.
+func (h *CommandHandler) RunUpdates(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case update := <-h.queue.updates:
+			h.HandleUpdate(ctx, update)
+		}
+	}
+}
+
+// DroppedUpdates returns how many updates were dropped since start.
+func (h *CommandHandler) DroppedUpdates() int64 {
+	return h.queue.dropped.Load()
+}
+
+func (h *CommandHandler) sendDroppedUpdatesAlert(dropped int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), droppedUpdatesAlertSendLimit)
+	defer cancel()
+	text := fmt.Sprintf(
+		"<b>WARN</b>\nbot commands are not keeping up: %d update(s) dropped so far (queue size %d)",
+		dropped,
+		cap(h.queue.updates),
+	)
+	if err := h.notifier.SendDefaultHTML(ctx, text); err != nil {
+		h.logger.Warn("failed to send dropped updates alert", "error", err)
+	}
+}
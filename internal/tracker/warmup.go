@@ -0,0 +1,85 @@
+package tracker
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// warmUp runs before the first real cycle: it pre-resolves target hostnames
+// so bad addresses are logged early and resolver caches are warm, and can run
+// one check round whose results are neither recorded nor alerted.
+func (e *MonitorEngine) warmUp(ctx context.Context) {
+	if !e.startupResolve && !e.warmupCheck {
+		return
+	}
+	e.mu.RLock()
+	targets := append([]*TargetState(nil), e.targets...)
+	timeout, maxParallel := e.timeout, e.maxParallel
+	e.mu.RUnlock()
+	if len(targets) == 0 {
+		return
+	}
+
+	if e.startupResolve {
+		failed := e.resolveTargets(ctx, targets, timeout, maxParallel)
+		e.logger.Info("startup DNS resolution complete", "targets", len(targets), "failed", len(failed))
+	}
+	if e.warmupCheck {
+		up := e.warmupRound(ctx, targets, timeout, maxParallel)
+		e.logger.Info("warm-up check round complete", "checked", len(targets), "up", up)
+	}
+}
+
+// resolveTargets looks up every hostname target address once and returns the
+// names of targets whose address did not resolve.
+func (e *MonitorEngine) resolveTargets(ctx context.Context, targets []*TargetState, timeout time.Duration, maxParallel int) []string {
+	var (
+		mu     sync.Mutex
+		failed []string
+		wg     sync.WaitGroup
+	)
+	sem := make(chan struct{}, defaultWorkers(maxParallel, len(targets)))
+	for _, target := range targets {
+		if net.ParseIP(target.Address) != nil {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(name, address string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			lookupCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			if _, err := net.DefaultResolver.LookupHost(lookupCtx, address); err != nil {
+				e.logger.Warn("startup DNS resolution failed", "track", name, "address", address, "error", err)
+				mu.Lock()
+				failed = append(failed, name)
+				mu.Unlock()
+			}
+		}(target.Name, target.Address)
+	}
+	wg.Wait()
+	return failed
+}
+
+func (e *MonitorEngine) warmupRound(ctx context.Context, targets []*TargetState, timeout time.Duration, maxParallel int) int {
+	var stats cycleStats
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, defaultWorkers(maxParallel, len(targets)))
+	for _, target := range targets {
+		e.mu.RLock()
+		snapshot := *target
+		e.mu.RUnlock()
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(t TargetState) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			stats.record(e.probe.checkWithFallback(ctx, &t, timeout).Up, false)
+		}(snapshot)
+	}
+	wg.Wait()
+	return stats.up
+}
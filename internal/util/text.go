@@ -3,8 +3,14 @@ package util
 import (
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
+// MaxReasonRunes caps a check reason; error text and banners can be
+// arbitrarily long.
+const MaxReasonRunes = 300
+
 func HTMLEscape(input string) string {
 	result := strings.ReplaceAll(input, "&", "&amp;")
 	result = strings.ReplaceAll(result, "<", "&lt;")
@@ -12,14 +18,48 @@ func HTMLEscape(input string) string {
 	return result
 }
 
+// HTMLAttrEscape escapes a value for use inside a double-quoted HTML attribute.
+func HTMLAttrEscape(input string) string {
+	return strings.ReplaceAll(HTMLEscape(input), `"`, "&quot;")
+}
+
+// SanitizeReason makes an untrusted reason safe to store and show: invalid
+// UTF-8 is replaced, line breaks and tabs become spaces, other control
+// characters are dropped, and the result is capped at MaxReasonRunes.
+func SanitizeReason(reason string) string {
+	reason = strings.ToValidUTF8(reason, "\uFFFD")
+	var sb strings.Builder
+	sb.Grow(min(len(reason), MaxReasonRunes*utf8.UTFMax))
+	count := 0
+	for _, r := range reason {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			r = ' '
+		case unicode.IsControl(r) || r == '\u2028' || r == '\u2029':
+			continue
+		}
+		if count == MaxReasonRunes {
+			sb.WriteString("…")
+			break
+		}
+		sb.WriteRune(r)
+		count++
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// SplitByLimit cuts text into chunks of at most maxLen bytes. Cuts fall on
+// rune boundaries and, where possible, before an unterminated HTML tag or
+// entity so that no chunk ends in the middle of "<code>" or "&amp;".
 func SplitByLimit(text string, maxLen int) []string {
 	if len(text) <= maxLen {
 		return []string{text}
 	}
 	chunks := make([]string, 0, len(text)/maxLen+1)
 	for len(text) > maxLen {
-		chunks = append(chunks, text[:maxLen])
-		text = text[maxLen:]
+		cut := splitPoint(text, maxLen)
+		chunks = append(chunks, text[:cut])
+		text = text[cut:]
 	}
 	if text != "" {
 		chunks = append(chunks, text)
@@ -27,6 +67,28 @@ func SplitByLimit(text string, maxLen int) []string {
 	return chunks
 }
 
+// splitPoint returns the byte offset, at most maxLen, at which text should be
+// cut. It never returns 0, so a single rune wider than maxLen still advances.
+func splitPoint(text string, maxLen int) int {
+	cut := maxLen
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	if cut == 0 {
+		_, size := utf8.DecodeRuneInString(text)
+		return size
+	}
+	head := text[:cut]
+	if open := strings.LastIndexByte(head, '<'); open > 0 && open > strings.LastIndexByte(head, '>') {
+		return open
+	}
+	if amp := strings.LastIndexByte(head, '&'); amp > 0 && amp > strings.LastIndexByte(head, ';') &&
+		!strings.ContainsAny(head[amp:], " \n") {
+		return amp
+	}
+	return cut
+}
+
 func SplitByLineLimit(text string, maxLen int) []string {
 	if len(text) <= maxLen {
 		return []string{text}
@@ -0,0 +1,103 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitizeReasonStripsControlCharacters(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"CHANGE":                        "CHANGE",
+		"POLL\x00\x1b[31mred\x1b[0m":    "POLL[31mred[0m",
+		"banner\r\nSSH-2.0\tOpenSSH":    "banner  SSH-2.0 OpenSSH",
+		"bad\xffutf8":                   "bad\uFFFDutf8",
+		"line\u2028sep\u0085next":       "linesepnext",
+		"  \n padded reason \t ":        "padded reason",
+		"dial tcp: i/o timeout\x7f\x07": "dial tcp: i/o timeout",
+	}
+	for input, want := range cases {
+		if got := SanitizeReason(input); got != want {
+			t.Errorf("SanitizeReason(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestSanitizeReasonCapsLength(t *testing.T) {
+	t.Parallel()
+
+	if got := SanitizeReason(strings.Repeat("x", MaxReasonRunes)); got != strings.Repeat("x", MaxReasonRunes) {
+		t.Fatalf("reason at the cap should be kept whole, got %d runes", utf8.RuneCountInString(got))
+	}
+	got := SanitizeReason(strings.Repeat("ж", 10*MaxReasonRunes))
+	if !strings.HasSuffix(got, "…") || utf8.RuneCountInString(got) != MaxReasonRunes+1 {
+		t.Fatalf("expected %d runes plus ellipsis, got %d", MaxReasonRunes, utf8.RuneCountInString(got))
+	}
+	if !utf8.ValidString(got) {
+		t.Fatal("truncation split a multi-byte rune")
+	}
+}
+
+func TestSplitByLimitKeepsShortTextWhole(t *testing.T) {
+	t.Parallel()
+
+	if got := SplitByLimit("привет", 64); len(got) != 1 || got[0] != "привет" {
+		t.Fatalf("short text should stay in one chunk, got %q", got)
+	}
+}
+
+func TestSplitByLimitRespectsRuneBoundaries(t *testing.T) {
+	t.Parallel()
+
+	for _, text := range []string{
+		"a" + strings.Repeat("ж", 40),
+		strings.Repeat("🙂", 25) + "x",
+		"ab" + strings.Repeat("🔥ы", 20),
+	} {
+		for _, limit := range []int{5, 7, 9, 10} {
+			chunks := SplitByLimit(text, limit)
+			if strings.Join(chunks, "") != text {
+				t.Fatalf("limit %d: chunks do not rejoin to the input", limit)
+			}
+			for _, chunk := range chunks {
+				if len(chunk) > limit || !utf8.ValidString(chunk) {
+					t.Fatalf("limit %d: bad chunk %q", limit, chunk)
+				}
+			}
+		}
+	}
+}
+
+func TestSplitByLimitAvoidsCuttingTagsAndEntities(t *testing.T) {
+	t.Parallel()
+
+	chunks := SplitByLimit("статус <code>api</code> &amp; ok", 15)
+	for _, chunk := range chunks {
+		if open := strings.LastIndexByte(chunk, '<'); open > strings.LastIndexByte(chunk, '>') {
+			t.Fatalf("chunk %q ends inside a tag", chunk)
+		}
+		if amp := strings.LastIndexByte(chunk, '&'); amp > strings.LastIndexByte(chunk, ';') {
+			t.Fatalf("chunk %q ends inside an entity", chunk)
+		}
+	}
+	if strings.Join(chunks, "") != "статус <code>api</code> &amp; ok" {
+		t.Fatalf("chunks do not rejoin to the input: %q", chunks)
+	}
+}
+
+func TestSplitByLineLimitFallbackIsUTF8Safe(t *testing.T) {
+	t.Parallel()
+
+	text := "header\n" + strings.Repeat("Ж🙂", 30) + "\nfooter"
+	chunks := SplitByLineLimit(text, 16)
+	for _, chunk := range chunks {
+		if len(chunk) > 16 || !utf8.ValidString(chunk) {
+			t.Fatalf("bad chunk %q", chunk)
+		}
+	}
+	if chunks[0] != "header" || chunks[len(chunks)-1] != "footer" {
+		t.Fatalf("unexpected chunk edges: %q", chunks)
+	}
+}